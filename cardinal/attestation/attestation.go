@@ -0,0 +1,88 @@
+// Package attestation signs the payloads of high-value events, such as tournament results or rare drops, with the
+// key of the shard that emitted them. An Attestation is a self-contained artifact: anyone who knows the shard's signer
+// address can verify that the shard emitted the payload in the given tick, no matter how the artifact reached them.
+package attestation
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rotisserie/eris"
+)
+
+var ErrInvalidSignature = errors.New("attestation signature is invalid")
+
+// domain separates attestation digests from the digests of other things that the same key might sign.
+const domain = "cardinal-attestation-v1"
+
+// Attestation is a payload that a shard signed, together with the namespace of the shard and the tick in which the
+// payload was attested.
+type Attestation struct {
+	Namespace string          `json:"namespace"`
+	Tick      uint64          `json:"tick"`
+	Payload   json.RawMessage `json:"payload"`
+	// Signer is the address of the key that signed the attestation.
+	Signer    string `json:"signer"`
+	Signature string `json:"signature"`
+}
+
+// Sign returns an attestation of the JSON encoded payload, signed by the key.
+func Sign(key *ecdsa.PrivateKey, namespace string, tick uint64, payload []byte) (Attestation, error) {
+	// The payload is signed in the form that it has once the attestation is JSON encoded, so that the signature still
+	// matches after the attestation is encoded and decoded.
+	normalized, err := json.Marshal(json.RawMessage(payload))
+	if err != nil {
+		return Attestation{}, eris.Wrap(err, "attested payload must be valid JSON")
+	}
+	a := Attestation{
+		Namespace: namespace,
+		Tick:      tick,
+		Payload:   normalized,
+		Signer:    crypto.PubkeyToAddress(key.PublicKey).Hex(),
+	}
+	sig, err := crypto.Sign(a.Digest(), key)
+	if err != nil {
+		return Attestation{}, eris.Wrap(err, "failed to sign attestation")
+	}
+	a.Signature = hexutil.Encode(sig)
+	return a, nil
+}
+
+// Digest returns the hash that the attestation's signature signs. It covers the namespace, the tick, and the payload.
+func (a Attestation) Digest() []byte {
+	var buf bytes.Buffer
+	buf.WriteString(domain)
+	for _, part := range [][]byte{[]byte(a.Namespace), binary.BigEndian.AppendUint64(nil, a.Tick), a.Payload} {
+		buf.Write(binary.BigEndian.AppendUint64(nil, uint64(len(part))))
+		buf.Write(part)
+	}
+	return crypto.Keccak256(buf.Bytes())
+}
+
+// Verify returns an error unless the attestation was signed by the key with the given address. The address must come
+// from a trusted source, such as the game's operator: the Signer field only says which key claims to have signed.
+func (a Attestation) Verify(signerAddress string) error {
+	if !common.IsHexAddress(signerAddress) {
+		return eris.Errorf("invalid signer address %q", signerAddress)
+	}
+	sig, err := hexutil.Decode(a.Signature)
+	if err != nil {
+		return eris.Wrap(ErrInvalidSignature, err.Error())
+	}
+	pubKey, err := crypto.SigToPub(a.Digest(), sig)
+	if err != nil {
+		return eris.Wrap(ErrInvalidSignature, err.Error())
+	}
+	signer := crypto.PubkeyToAddress(*pubKey).Hex()
+	if !strings.EqualFold(signer, signerAddress) {
+		return eris.Wrapf(ErrInvalidSignature, "signed by %s, not %s", signer, signerAddress)
+	}
+	return nil
+}
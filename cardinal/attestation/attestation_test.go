@@ -0,0 +1,60 @@
+package attestation_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/attestation"
+)
+
+func TestAttestationSurvivesEncoding(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	signer := crypto.PubkeyToAddress(key.PublicKey).Hex()
+
+	a, err := attestation.Sign(key, "my-world", 42, []byte(`{ "winner": "alice" }`))
+	assert.NilError(t, err)
+	assert.Equal(t, a.Signer, signer)
+	assert.NilError(t, a.Verify(signer))
+
+	bz, err := json.Marshal(a)
+	assert.NilError(t, err)
+	var decoded attestation.Attestation
+	assert.NilError(t, json.Unmarshal(bz, &decoded))
+	assert.NilError(t, decoded.Verify(signer))
+}
+
+func TestTamperedAttestationFailsVerification(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	signer := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	a, err := attestation.Sign(key, "my-world", 42, []byte(`{"winner":"alice"}`))
+	assert.NilError(t, err)
+
+	tampered := []func(a *attestation.Attestation){
+		func(a *attestation.Attestation) { a.Namespace = "other-world" },
+		func(a *attestation.Attestation) { a.Tick++ },
+		func(a *attestation.Attestation) { a.Payload = json.RawMessage(`{"winner":"mallory"}`) },
+	}
+	for _, tamper := range tampered {
+		b := a
+		tamper(&b)
+		assert.Check(t, eris.Is(b.Verify(signer), attestation.ErrInvalidSignature))
+	}
+
+	otherKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	other := crypto.PubkeyToAddress(otherKey.PublicKey).Hex()
+	assert.Check(t, eris.Is(a.Verify(other), attestation.ErrInvalidSignature))
+}
+
+func TestSignRejectsInvalidJSON(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	_, err = attestation.Sign(key, "my-world", 1, []byte(`{"winner":`))
+	assert.ErrorContains(t, err, "valid JSON")
+}
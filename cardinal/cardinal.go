@@ -7,7 +7,10 @@ import (
 
 	"github.com/rotisserie/eris"
 
+	"pkg.world.dev/world-engine/cardinal/codec"
 	"pkg.world.dev/world-engine/cardinal/component"
+	"pkg.world.dev/world-engine/cardinal/events"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
 	"pkg.world.dev/world-engine/cardinal/iterators"
 	"pkg.world.dev/world-engine/cardinal/message"
 	"pkg.world.dev/world-engine/cardinal/query"
@@ -25,6 +28,17 @@ var (
 	ErrEntityMustHaveAtLeastOneComponent = iterators.ErrEntityMustHaveAtLeastOneComponent
 	ErrComponentNotOnEntity              = iterators.ErrComponentNotOnEntity
 	ErrComponentAlreadyOnEntity          = iterators.ErrComponentAlreadyOnEntity
+	// ErrLimitExceeded is wrapped by the errors that are returned when a state change would exceed the world's
+	// Limits. Use eris.As with a *LimitError to find out which limit was exceeded.
+	ErrLimitExceeded = gamestate.ErrLimitExceeded
+	// ErrNotParallelSafe is wrapped by the errors that are returned when a handler that runs in parallel, see
+	// message.WithParallelExecution, tries to create or remove entities, or to add or remove components.
+	ErrNotParallelSafe = message.ErrNotParallelSafe
+)
+
+type (
+	Limits     = gamestate.Limits
+	LimitError = gamestate.LimitError
 )
 
 // Imported
@@ -70,7 +84,11 @@ func RegisterSystems(w *World, sys ...system.System) error {
 			worldstage.Init,
 		)
 	}
-	return w.systemManager.RegisterSystems(sys...)
+	if err := w.systemManager.RegisterSystems(sys...); err != nil {
+		return err
+	}
+	w.recordSystemOwners(sys)
+	return nil
 }
 
 func RegisterInitSystems(w *World, sys ...system.System) error {
@@ -81,10 +99,14 @@ func RegisterInitSystems(w *World, sys ...system.System) error {
 			worldstage.Init,
 		)
 	}
-	return w.systemManager.RegisterInitSystems(sys...)
+	if err := w.systemManager.RegisterInitSystems(sys...); err != nil {
+		return err
+	}
+	w.recordSystemOwners(sys)
+	return nil
 }
 
-func RegisterComponent[T types.Component](w *World) error {
+func RegisterComponent[T types.Component](w *World, opts ...component.Option[T]) error {
 	if w.worldStage.Current() != worldstage.Init {
 		return eris.Errorf(
 			"engine state is %s, expected %s to register component",
@@ -93,21 +115,32 @@ func RegisterComponent[T types.Component](w *World) error {
 		)
 	}
 
-	compMetadata, err := component.NewComponentMetadata[T]()
+	compMetadata, err := component.NewComponentMetadata[T](opts...)
 	if err != nil {
 		return err
 	}
 
+	// Report which module got to the name first, so that clashes between modules are easy to track down.
+	if owner, ok := w.componentOwners[compMetadata.Name()]; ok {
+		return eris.Errorf(
+			"component %q is already registered by %s", compMetadata.Name(), describeComponentOwner(owner),
+		)
+	}
+
 	err = w.componentManager.RegisterComponent(compMetadata)
 	if err != nil {
 		return err
 	}
 
+	if w.componentOwners == nil {
+		w.componentOwners = map[string]string{}
+	}
+	w.componentOwners[compMetadata.Name()] = w.registeringModule
 	return nil
 }
 
-func MustRegisterComponent[T types.Component](w *World) {
-	err := RegisterComponent[T](w)
+func MustRegisterComponent[T types.Component](w *World, opts ...component.Option[T]) {
+	err := RegisterComponent[T](w, opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -129,6 +162,26 @@ func EachMessage[In any, Out any](wCtx engine.Context, fn func(message.TxData[In
 	return nil
 }
 
+// EachMessageParallel is like EachMessage, but passes each handler the context it must use instead of the system's
+// context. The handlers of messages that were registered with message.WithParallelExecution run in parallel for
+// different personas.
+func EachMessageParallel[In any, Out any](
+	wCtx engine.Context, fn func(engine.Context, message.TxData[In]) (Out, error),
+) error {
+	var msg message.MessageType[In, Out]
+	msgType := reflect.TypeOf(msg)
+	tempRes, ok := wCtx.GetMessageByType(msgType)
+	if !ok {
+		return eris.Errorf("Could not find %s, Message may not be registered.", msg.Name())
+	}
+	res, ok := tempRes.(*message.MessageType[In, Out])
+	if !ok {
+		return eris.New("wrong type")
+	}
+	res.EachParallel(wCtx, fn)
+	return nil
+}
+
 // RegisterMessage registers a message to the world. Cardinal will automatically set up HTTP routes that map to each
 // registered message. Message URLs are take the form of "group.name". A default group, "game", is used
 // unless the WithCustomMessageGroup option is used. Example: game.throw-rock
@@ -141,6 +194,23 @@ func RegisterMessage[In any, Out any](world *World, name string, opts ...message
 		)
 	}
 
+	// Messages registered by a module are grouped under the module's name unless the module picks a group itself.
+	if world.registeringModule != "" {
+		opts = append([]message.MessageOption[In, Out]{
+			message.WithCustomMessageGroup[In, Out](world.registeringModule),
+		}, opts...)
+	}
+
+	// The world's decoding options come first, so that the message's own options can add to them.
+	if world.messageDecoding.Strict {
+		opts = append([]message.MessageOption[In, Out]{message.WithStrictDecoding[In, Out]()}, opts...)
+	}
+	if world.messageDecoding.Naming != codec.FieldNamingGo {
+		opts = append([]message.MessageOption[In, Out]{
+			message.WithFieldNaming[In, Out](world.messageDecoding.Naming),
+		}, opts...)
+	}
+
 	// Create the message type
 	msgType := message.NewMessageType[In, Out](name, opts...)
 
@@ -175,6 +245,65 @@ func RegisterQuery[Request any, Reply any](
 	return w.queryManager.RegisterQuery(name, q)
 }
 
+// CallerPersonaTag returns the persona tag of the caller of a query, if the caller was authenticated with a token that
+// maps to a persona; see WithJWTAuth.
+func CallerPersonaTag(wCtx engine.Context) (string, bool) {
+	caller, ok := wCtx.(interface{ CallerPersonaTag() string })
+	if !ok {
+		return "", false
+	}
+	return caller.CallerPersonaTag(), true
+}
+
+// Read is a query that is registered under a namespace with RegisterReads. Use NewRead to create one.
+type Read struct {
+	name     string
+	newQuery func(group string) (engine.Query, error)
+}
+
+// NewRead returns a query handler that can be registered with RegisterReads. The options are applied as they are
+// with RegisterQuery, except that the query's group is always the namespace passed to RegisterReads.
+func NewRead[Request any, Reply any](
+	name string,
+	handler func(wCtx engine.Context, req *Request) (*Reply, error),
+	opts ...query.Option[Request, Reply],
+) Read {
+	return Read{
+		name: name,
+		newQuery: func(group string) (engine.Query, error) {
+			opts = append(opts, query.WithCustomQueryGroup[Request, Reply](group))
+			return query.NewQueryType[Request, Reply](name, handler, opts...)
+		},
+	}
+}
+
+// RegisterReads registers a set of queries under the given namespace. Each query is served at
+// /query/<namespace>/<name>, so feature modules can use the same query names without colliding with each other.
+// Example: RegisterReads(w, "guild", NewRead("roster", handleRoster)) is served at /query/guild/roster.
+func RegisterReads(w *World, namespace string, reads ...Read) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"engine state is %s, expected %s to register query",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	if !regexAlphanumeric.MatchString(namespace) {
+		return eris.Errorf("invalid query namespace %q, a namespace must be alphanumeric", namespace)
+	}
+
+	for _, read := range reads {
+		q, err := read.newQuery(namespace)
+		if err != nil {
+			return err
+		}
+		if err := w.queryManager.RegisterQuery(read.name, q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Create creates a single entity in the world, and returns the id of the newly created entity.
 // At least 1 component must be provided.
 func Create(wCtx engine.Context, components ...types.Component) (_ types.EntityID, err error) {
@@ -227,6 +356,13 @@ func CreateMany(wCtx engine.Context, num int, components ...types.Component) (en
 
 			err = wCtx.StoreManager().SetComponentForEntity(c, id, comp)
 			if err != nil {
+				// Don't leave behind entities without their initial component values, for example because one of the
+				// values exceeds the world's component payload size limit.
+				for _, created := range entityIDs {
+					if removeErr := wCtx.StoreManager().RemoveEntity(created); removeErr != nil {
+						return nil, errors.Join(err, removeErr)
+					}
+				}
 				return nil, err
 			}
 		}
@@ -389,3 +525,22 @@ func Remove(wCtx engine.Context, id types.EntityID) (err error) {
 
 	return nil
 }
+
+// EventRecipientsField is the event field that lists the persona tags an event is addressed to. The relay only
+// delivers events that have this field to the listed personas; events without it are delivered to everyone.
+const EventRecipientsField = events.RecipientsField
+
+// EmitEventTo emits an event that is only delivered to the given persona tags. It does nothing if there are no
+// recipients. Note that events are still broadcast to every websocket subscriber of Cardinal itself, so the relay is
+// what keeps them private from other players.
+func EmitEventTo(wCtx engine.Context, personaTags []string, event map[string]any) error {
+	if len(personaTags) == 0 {
+		return nil
+	}
+	addressed := make(map[string]any, len(event)+1)
+	for k, v := range event {
+		addressed[k] = v
+	}
+	addressed[EventRecipientsField] = personaTags
+	return wCtx.EmitEvent(addressed)
+}
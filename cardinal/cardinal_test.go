@@ -16,9 +16,11 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/fasthttp/websocket"
 	"github.com/golang/mock/gomock"
+	"github.com/rotisserie/eris"
 
 	"pkg.world.dev/world-engine/assert"
 	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
 	"pkg.world.dev/world-engine/cardinal/message"
 	"pkg.world.dev/world-engine/cardinal/router/mocks"
 	"pkg.world.dev/world-engine/cardinal/search/filter"
@@ -852,3 +854,55 @@ func TestCallsRegisterGameShardOnStartup(t *testing.T) {
 func wsURL(addr, path string) string {
 	return fmt.Sprintf("ws://%s/%s", addr, path)
 }
+
+func TestWorldLimitsAreEnforced(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithLimits(cardinal.Limits{
+		MaxEntities:             2,
+		MaxComponentsPerEntity:  1,
+		MaxComponentPayloadSize: 16,
+	}))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Foo](world))
+	assert.NilError(t, cardinal.RegisterComponent[Bar](world))
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+
+	var errs []error
+	var entityCount int
+	assert.NilError(t, cardinal.RegisterInitSystems(world, func(wCtx engine.Context) error {
+		_, err := cardinal.Create(wCtx, Foo{}, Bar{})
+		errs = append(errs, err)
+		id, err := cardinal.Create(wCtx, Health{Value: 1})
+		if err != nil {
+			return err
+		}
+		errs = append(errs, cardinal.SetComponent[Health](wCtx, id, &Health{Value: 1_000_000_000}))
+		_, err = cardinal.Create(wCtx, Health{Value: 1_000_000_000})
+		errs = append(errs, err)
+		if _, err = cardinal.Create(wCtx, Foo{}); err != nil {
+			return err
+		}
+		_, err = cardinal.Create(wCtx, Bar{})
+		errs = append(errs, err)
+		errs = append(errs, cardinal.AddComponentTo[Foo](wCtx, id))
+		entityCount, err = cardinal.NewSearch().Entity(filter.All()).Count(wCtx)
+		return err
+	}))
+	tf.DoTick()
+
+	wantLimits := []gamestate.Limit{
+		gamestate.LimitComponentsPerEntity,
+		gamestate.LimitComponentPayloadSize,
+		gamestate.LimitComponentPayloadSize,
+		gamestate.LimitEntities,
+		gamestate.LimitComponentsPerEntity,
+	}
+	assert.Equal(t, len(errs), len(wantLimits))
+	for i, err := range errs {
+		assert.Check(t, eris.Is(err, cardinal.ErrLimitExceeded), "error %d: %v", i, err)
+		var limitErr *cardinal.LimitError
+		assert.Check(t, eris.As(err, &limitErr))
+		assert.Equal(t, limitErr.Limit, wantLimits[i])
+	}
+	// The entity whose initial component was too large was not created.
+	assert.Equal(t, entityCount, 2)
+}
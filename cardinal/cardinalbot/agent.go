@@ -0,0 +1,64 @@
+package cardinalbot
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+// Agent decides what a bot does. Act is called on every step of the bot's schedule; it typically queries the world,
+// and sends the bot's next transactions.
+type Agent interface {
+	Act(ctx context.Context, bot *Bot) error
+}
+
+// AgentFunc is an Agent whose Act is the function itself.
+type AgentFunc func(ctx context.Context, bot *Bot) error
+
+func (f AgentFunc) Act(ctx context.Context, bot *Bot) error {
+	return f(ctx, bot)
+}
+
+// Run lets the agent act for the bot every interval, until ctx is done or the agent returns an error. Run returns nil
+// when it is stopped by ctx.
+func (b *Bot) Run(ctx context.Context, interval time.Duration, agent Agent) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := agent.Act(ctx, b); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return eris.Wrapf(err, "agent of bot %q failed", b.personaTag)
+		}
+	}
+}
+
+// RunAll runs every bot with the same agent and interval, e.g. to put a world under load. When an agent returns an
+// error, the other bots are stopped, and the errors of all agents that failed are returned.
+func RunAll(ctx context.Context, interval time.Duration, agent Agent, bots ...*Bot) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, len(bots))
+	var wg sync.WaitGroup
+	for i, bot := range bots {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if errs[i] = bot.Run(ctx, interval, agent); errs[i] != nil {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
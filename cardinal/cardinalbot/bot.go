@@ -0,0 +1,210 @@
+// Package cardinalbot scripts agents that play a Cardinal world the way players do: they observe state with queries,
+// and submit signed transactions through the world's HTTP API. Bots are meant for soak tests, and for games that need
+// server driven actors, such as NPCs, that go through the same transaction pipeline as players.
+package cardinalbot
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rotisserie/eris"
+
+	personaMsg "pkg.world.dev/world-engine/cardinal/persona/msg"
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+	"pkg.world.dev/world-engine/sign"
+)
+
+const (
+	personaGroup = "persona"
+
+	// DefaultReceiptPollInterval is how often WaitForReceipt asks the world for new receipts.
+	DefaultReceiptPollInterval = 100 * time.Millisecond
+)
+
+// Bot is a persona that is controlled by code instead of a player. A Bot is safe for concurrent use.
+type Bot struct {
+	baseURL      string
+	personaTag   string
+	client       *http.Client
+	key          *ecdsa.PrivateKey
+	nonce        atomic.Uint64
+	pollInterval time.Duration
+
+	namespaceMu sync.Mutex
+	namespace   string
+}
+
+type Option func(*Bot)
+
+// WithHTTPClient sets the client that sends the bot's requests. The default is http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(b *Bot) {
+		b.client = client
+	}
+}
+
+// WithPrivateKey sets the key that signs the bot's transactions. By default, every bot gets a new key. Use this
+// option, together with WithNonce, to control a persona that already exists.
+func WithPrivateKey(key *ecdsa.PrivateKey) Option {
+	return func(b *Bot) {
+		b.key = key
+	}
+}
+
+// WithNonce sets the nonce of the bot's first transaction. Later transactions use increasing nonces.
+func WithNonce(nonce uint64) Option {
+	return func(b *Bot) {
+		b.nonce.Store(nonce)
+	}
+}
+
+// WithReceiptPollInterval sets how often WaitForReceipt asks the world for new receipts.
+func WithReceiptPollInterval(interval time.Duration) Option {
+	return func(b *Bot) {
+		b.pollInterval = interval
+	}
+}
+
+// New returns a bot that plays as the persona in the world that serves its HTTP API at baseURL, e.g.
+// "http://localhost:4040".
+func New(baseURL, personaTag string, opts ...Option) (*Bot, error) {
+	b := &Bot{
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		personaTag:   personaTag,
+		client:       http.DefaultClient,
+		pollInterval: DefaultReceiptPollInterval,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.key == nil {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, eris.Wrap(err, "failed to generate the bot's private key")
+		}
+		b.key = key
+	}
+	return b, nil
+}
+
+// PersonaTag returns the tag of the persona that the bot plays as.
+func (b *Bot) PersonaTag() string {
+	return b.personaTag
+}
+
+// SignerAddress returns the address that signs the bot's transactions.
+func (b *Bot) SignerAddress() string {
+	return crypto.PubkeyToAddress(b.key.PublicKey).Hex()
+}
+
+// CreatePersona submits the transaction that creates the bot's persona, with the bot's signer address. The persona
+// can be used once the transaction is executed; see WaitForReceipt.
+func (b *Bot) CreatePersona(ctx context.Context) (handler.PostTransactionResponse, error) {
+	return b.Send(ctx, personaGroup, personaMsg.CreatePersonaMessageName, personaMsg.CreatePersona{
+		PersonaTag:    b.personaTag,
+		SignerAddress: b.SignerAddress(),
+	})
+}
+
+// Send signs the message as the bot's persona, and submits it to the message's transaction endpoint,
+// /tx/<group>/<name>.
+func (b *Bot) Send(ctx context.Context, group, name string, msg any) (handler.PostTransactionResponse, error) {
+	var res handler.PostTransactionResponse
+	namespace, err := b.Namespace(ctx)
+	if err != nil {
+		return res, err
+	}
+	tx, err := sign.NewTransaction(b.key, b.personaTag, namespace, b.nonce.Add(1)-1, msg)
+	if err != nil {
+		return res, eris.Wrap(err, "failed to sign transaction")
+	}
+	err = b.post(ctx, "tx/"+group+"/"+name, tx, &res)
+	return res, err
+}
+
+// Query sends the request to the query endpoint, /query/<group>/<name>, and decodes the reply into reply.
+func (b *Bot) Query(ctx context.Context, group, name string, req, reply any) error {
+	return b.post(ctx, "query/"+group+"/"+name, req, reply)
+}
+
+// WaitForReceipt waits until the world has executed the transaction, and returns its receipt. Receipts are only kept
+// for a few ticks, so WaitForReceipt must be called soon after the transaction is submitted.
+func (b *Bot) WaitForReceipt(ctx context.Context, tx handler.PostTransactionResponse) (handler.ReceiptEntry, error) {
+	startTick := tx.Tick
+	for {
+		req := handler.ListTxReceiptsRequest{StartTick: startTick}
+		var res handler.ListTxReceiptsResponse
+		if err := b.post(ctx, "query/receipts/list", req, &res); err != nil {
+			return handler.ReceiptEntry{}, err
+		}
+		for _, r := range res.Receipts {
+			if r.TxHash == tx.TxHash {
+				return r, nil
+			}
+		}
+		startTick = max(startTick, res.EndTick)
+
+		select {
+		case <-ctx.Done():
+			return handler.ReceiptEntry{}, eris.Wrapf(ctx.Err(), "no receipt for transaction %s", tx.TxHash)
+		case <-time.After(b.pollInterval):
+		}
+	}
+}
+
+// Namespace returns the namespace of the world, which the bot's transactions are signed for.
+func (b *Bot) Namespace(ctx context.Context) (string, error) {
+	b.namespaceMu.Lock()
+	defer b.namespaceMu.Unlock()
+	if b.namespace == "" {
+		var res handler.GetWorldResponse
+		if err := b.do(ctx, http.MethodGet, "world", nil, &res); err != nil {
+			return "", err
+		}
+		b.namespace = res.Namespace
+	}
+	return b.namespace, nil
+}
+
+func (b *Bot) post(ctx context.Context, path string, body, reply any) error {
+	return b.do(ctx, http.MethodPost, path, body, reply)
+}
+
+func (b *Bot) do(ctx context.Context, method, path string, body, reply any) error {
+	var reqBody io.Reader
+	if body != nil {
+		bz, err := json.Marshal(body)
+		if err != nil {
+			return eris.Wrapf(err, "failed to encode request to %s", path)
+		}
+		reqBody = bytes.NewReader(bz)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, b.baseURL+"/"+path, reqBody)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := b.client.Do(req)
+	if err != nil {
+		return eris.Wrapf(err, "request to %s failed", path)
+	}
+	defer res.Body.Close()
+	bz, err := io.ReadAll(res.Body)
+	if err != nil {
+		return eris.Wrapf(err, "failed to read response from %s", path)
+	}
+	if res.StatusCode != http.StatusOK {
+		return eris.Errorf("request to %s failed with status %d: %s", path, res.StatusCode, bz)
+	}
+	return eris.Wrapf(json.Unmarshal(bz, reply), "failed to decode response from %s", path)
+}
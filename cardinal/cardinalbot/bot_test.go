@@ -0,0 +1,113 @@
+package cardinalbot_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/cardinalbot"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/persona/query"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type MoveMsg struct {
+	Steps int
+}
+
+type MoveResult struct {
+	Total int
+}
+
+// setupGame starts a world where personas move, and returns the total number of steps each persona moved.
+func setupGame(t *testing.T) (*testutils.TestFixture, map[string]int) {
+	tf := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, cardinal.RegisterMessage[MoveMsg, MoveResult](tf.World, "move"))
+	steps := map[string]int{}
+	assert.NilError(t, cardinal.RegisterSystems(tf.World, func(wCtx engine.Context) error {
+		return cardinal.EachMessage[MoveMsg, MoveResult](wCtx, func(tx message.TxData[MoveMsg]) (MoveResult, error) {
+			steps[tx.Tx.PersonaTag] += tx.Msg.Steps
+			return MoveResult{Total: steps[tx.Tx.PersonaTag]}, nil
+		})
+	}))
+	tf.StartWorld()
+	return tf, steps
+}
+
+func newBot(tf *testutils.TestFixture, personaTag string) *cardinalbot.Bot {
+	bot, err := cardinalbot.New("http://"+tf.BaseURL, personaTag, cardinalbot.WithReceiptPollInterval(time.Millisecond))
+	assert.NilError(tf, err)
+	_, err = bot.CreatePersona(context.Background())
+	assert.NilError(tf, err)
+	tf.DoTick()
+	return bot
+}
+
+func TestBotSendsSignedTransactions(t *testing.T) {
+	tf, steps := setupGame(t)
+	ctx := context.Background()
+	bot := newBot(tf, "npc")
+
+	var signer query.PersonaSignerQueryResponse
+	req := query.PersonaSignerQueryRequest{PersonaTag: "npc"}
+	assert.NilError(t, bot.Query(ctx, "persona", "signer", req, &signer))
+	assert.Equal(t, signer.Status, query.PersonaStatusAssigned)
+	assert.Equal(t, signer.SignerAddress, bot.SignerAddress())
+
+	tx, err := bot.Send(ctx, "game", "move", MoveMsg{Steps: 2})
+	assert.NilError(t, err)
+	_, err = bot.Send(ctx, "game", "move", MoveMsg{Steps: 3})
+	assert.NilError(t, err)
+	tf.DoTick()
+	assert.Equal(t, steps["npc"], 5)
+
+	receipt, err := bot.WaitForReceipt(ctx, tx)
+	assert.NilError(t, err)
+	assert.Equal(t, len(receipt.Errors), 0)
+	assert.Equal(t, receipt.Result.(map[string]any)["Total"], float64(2))
+
+	// The server rejects transactions for a persona that the bot doesn't sign for.
+	impostor, err := cardinalbot.New("http://"+tf.BaseURL, "npc")
+	assert.NilError(t, err)
+	_, err = impostor.Send(ctx, "game", "move", MoveMsg{Steps: 1})
+	assert.ErrorContains(t, err, "failed to validate transaction")
+}
+
+func TestRunAllActsOnASchedule(t *testing.T) {
+	tf, steps := setupGame(t)
+	bots := []*cardinalbot.Bot{newBot(tf, "npc-1"), newBot(tf, "npc-2")}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sent := map[string]*atomic.Int64{"npc-1": {}, "npc-2": {}}
+	acts := make(chan struct{})
+	agent := cardinalbot.AgentFunc(func(ctx context.Context, bot *cardinalbot.Bot) error {
+		// Sends aren't canceled with ctx, so that every transaction that was sent is counted.
+		if _, err := bot.Send(context.Background(), "game", "move", MoveMsg{Steps: 1}); err != nil {
+			return err
+		}
+		sent[bot.PersonaTag()].Add(1)
+		select {
+		case acts <- struct{}{}:
+		case <-ctx.Done():
+		}
+		return nil
+	})
+	done := make(chan error)
+	go func() {
+		done <- cardinalbot.RunAll(ctx, time.Millisecond, agent, bots...)
+	}()
+
+	for sent["npc-1"].Load() < 3 || sent["npc-2"].Load() < 3 {
+		<-acts
+	}
+	cancel()
+	assert.NilError(t, <-done)
+
+	tf.DoTick()
+	assert.Equal(t, int64(steps["npc-1"]), sent["npc-1"].Load())
+	assert.Equal(t, int64(steps["npc-2"]), sent["npc-2"].Load())
+}
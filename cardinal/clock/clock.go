@@ -0,0 +1,102 @@
+// Package clock abstracts the wall-clock time that drives a world's tick timer and tick timestamps, so that tests can
+// run on simulated time. It is unrelated to the in-game time of package worldclock.
+//
+//	c := clock.NewManual(time.Unix(0, 0))
+//	world, err := cardinal.NewWorld(cardinal.WithClock(c))
+//	...
+//	c.Advance(3 * time.Second) // Starts three ticks at once, without sleeping.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a source of time and of tick timers.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// Tick returns a channel that delivers the current time once every interval.
+	Tick(interval time.Duration) <-chan time.Time
+}
+
+// Real returns the clock of the system's wall-clock time.
+func Real() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Tick(interval time.Duration) <-chan time.Time {
+	return time.Tick(interval) //nolint:staticcheck // The ticker lives as long as the world.
+}
+
+// Manual is a simulated clock, whose time only passes when Advance is called. It is safe for concurrent use.
+type Manual struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*manualTicker
+}
+
+var _ Clock = &Manual{}
+
+type manualTicker struct {
+	interval time.Duration
+	next     time.Time
+	ch       chan time.Time
+}
+
+// NewManual creates a simulated clock whose time starts at the given time.
+func NewManual(start time.Time) *Manual {
+	return &Manual{now: start}
+}
+
+func (m *Manual) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+// Tick returns a channel that delivers the simulated time each time it passes a multiple of the interval. The channel
+// is unbuffered, so that every delivery is received before Advance continues.
+func (m *Manual) Tick(interval time.Duration) <-chan time.Time {
+	if interval <= 0 {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t := &manualTicker{interval: interval, next: m.now.Add(interval), ch: make(chan time.Time)}
+	m.tickers = append(m.tickers, t)
+	return t.ch
+}
+
+// Advance moves the simulated time forward by d. The tickers fire in order of their times, ties in the order they were
+// created, and Advance blocks until each delivery is received. Advance must not be called concurrently with itself.
+func (m *Manual) Advance(d time.Duration) {
+	m.mu.Lock()
+	end := m.now.Add(d)
+	m.mu.Unlock()
+	for {
+		m.mu.Lock()
+		var due *manualTicker
+		for _, t := range m.tickers {
+			if !t.next.After(end) && (due == nil || t.next.Before(due.next)) {
+				due = t
+			}
+		}
+		if due == nil {
+			m.now = end
+			m.mu.Unlock()
+			return
+		}
+		now := due.next
+		m.now = now
+		due.next = now.Add(due.interval)
+		m.mu.Unlock()
+		due.ch <- now
+	}
+}
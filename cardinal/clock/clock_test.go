@@ -0,0 +1,53 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/clock"
+)
+
+var start = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func TestManualClockOnlyMovesWhenAdvanced(t *testing.T) {
+	c := clock.NewManual(start)
+	assert.Check(t, c.Now().Equal(start))
+	c.Advance(90 * time.Minute)
+	assert.Check(t, c.Now().Equal(start.Add(90*time.Minute)))
+}
+
+func TestManualClockTicksInOrder(t *testing.T) {
+	c := clock.NewManual(start)
+	seconds := c.Tick(time.Second)
+	halves := c.Tick(1500 * time.Millisecond)
+
+	type delivery struct {
+		Ticker string
+		At     time.Duration
+	}
+	var got []delivery
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for len(got) < 5 {
+			select {
+			case now := <-seconds:
+				got = append(got, delivery{"seconds", now.Sub(start)})
+			case now := <-halves:
+				got = append(got, delivery{"halves", now.Sub(start)})
+			}
+		}
+	}()
+	c.Advance(3 * time.Second)
+	<-done
+
+	assert.DeepEqual(t, got, []delivery{
+		{"seconds", time.Second},
+		{"halves", 1500 * time.Millisecond},
+		{"seconds", 2 * time.Second},
+		{"seconds", 3 * time.Second},
+		{"halves", 3 * time.Second},
+	})
+	assert.Check(t, c.Now().Equal(start.Add(3*time.Second)))
+}
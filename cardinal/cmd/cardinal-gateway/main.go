@@ -0,0 +1,69 @@
+// Command cardinal-gateway runs a read gateway for a Cardinal shard. See package gateway.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/gateway"
+)
+
+func main() {
+	shardURL := flag.String("shard", envOr("GATEWAY_SHARD_URL", "http://localhost:4040"),
+		"base URL of the shard's HTTP API")
+	port := flag.String("port", envOr("GATEWAY_PORT", "4041"), "port that the gateway serves on")
+	flag.Parse()
+
+	if err := run(*shardURL, *port); err != nil {
+		log.Fatal().Err(err).Msg("Gateway failed")
+	}
+}
+
+func run(shardURL, port string) error {
+	g, err := gateway.New(shardURL)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{ //nolint:exhaustruct // defaults are fine
+		Addr:              ":" + port,
+		Handler:           g.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Error().Err(err).Msg("Failed to shut down the gateway")
+		}
+	}()
+	go func() {
+		if err := g.Run(ctx); err != nil {
+			log.Error().Err(err).Msg("Gateway stopped following the shard's events")
+		}
+	}()
+
+	log.Info().Msgf("Serving the API of %s on port %s", shardURL, port)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
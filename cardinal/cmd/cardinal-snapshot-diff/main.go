@@ -0,0 +1,98 @@
+// Command cardinal-snapshot-diff prints how the state of a world changed between two snapshots. See package snapshot.
+//
+// A snapshot is either a file that holds the reply of a shard's /debug/state endpoint, or the base URL of a running
+// shard, whose current state is fetched:
+//
+//	cardinal-snapshot-diff -components health,position before.json http://localhost:4040
+//
+// The command exits with status 1 if the snapshots differ.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/snapshot"
+)
+
+const fetchTimeout = 30 * time.Second
+
+func main() {
+	components := flag.String("components", "", "comma separated names of the components to compare (default all)")
+	asJSON := flag.Bool("json", false, "print the diff as JSON")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] <before> <after>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 { //nolint:gomnd // before and after
+		flag.Usage()
+		os.Exit(2) //nolint:gomnd // usage error
+	}
+
+	same, err := run(flag.Arg(0), flag.Arg(1), *components, *asJSON)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2) //nolint:gomnd // failure
+	}
+	if !same {
+		os.Exit(1)
+	}
+}
+
+func run(beforeSrc, afterSrc, components string, asJSON bool) (bool, error) {
+	before, err := load(beforeSrc)
+	if err != nil {
+		return false, err
+	}
+	after, err := load(afterSrc)
+	if err != nil {
+		return false, err
+	}
+	var names []string
+	if components != "" {
+		names = strings.Split(components, ",")
+	}
+	diff, err := snapshot.Compare(before, after, names...)
+	if err != nil {
+		return false, err
+	}
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return diff.IsEmpty(), eris.Wrap(enc.Encode(diff), "")
+	}
+	return diff.IsEmpty(), diff.WriteText(os.Stdout)
+}
+
+// load reads the snapshot from a file, or fetches the current state of the shard at the URL.
+func load(src string) (snapshot.Snapshot, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		f, err := os.Open(src)
+		if err != nil {
+			return nil, eris.Wrap(err, "")
+		}
+		defer f.Close()
+		return snapshot.Decode(f)
+	}
+	client := &http.Client{Timeout: fetchTimeout}
+	res, err := client.Post(strings.TrimSuffix(src, "/")+"/debug/state", "application/json", bytes.NewBufferString("{}"))
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to fetch the state of %s", src)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(res.Body)
+		return nil, eris.Errorf("failed to fetch the state of %s: %s: %s", src, res.Status, body)
+	}
+	return snapshot.Decode(res.Body)
+}
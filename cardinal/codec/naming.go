@@ -0,0 +1,75 @@
+package codec
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// FieldNaming is a policy for the JSON keys of struct fields that don't have a name in their json tag.
+type FieldNaming int
+
+const (
+	// FieldNamingGo uses the Go field name, which is matched case-insensitively when decoding. This is the behavior
+	// of encoding/json, and the default.
+	FieldNamingGo FieldNaming = iota
+	// FieldNamingCamelCase uses camelCase keys, such as "playerId" for a field named PlayerID.
+	FieldNamingCamelCase
+	// FieldNamingSnakeCase uses snake_case keys, such as "player_id" for a field named PlayerID.
+	FieldNamingSnakeCase
+)
+
+func (n FieldNaming) String() string {
+	switch n {
+	case FieldNamingGo:
+		return "go"
+	case FieldNamingCamelCase:
+		return "camelCase"
+	case FieldNamingSnakeCase:
+		return "snake_case"
+	default:
+		return "unknown"
+	}
+}
+
+// Key returns the JSON key of the struct field under the naming policy. A name in the field's json tag always wins.
+func (n FieldNaming) Key(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+	words := splitWords(field.Name)
+	switch n {
+	case FieldNamingCamelCase:
+		for i, word := range words {
+			if i == 0 {
+				words[i] = strings.ToLower(word)
+			} else {
+				words[i] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
+			}
+		}
+		return strings.Join(words, "")
+	case FieldNamingSnakeCase:
+		return strings.ToLower(strings.Join(words, "_"))
+	default:
+		return field.Name
+	}
+}
+
+// splitWords splits a Go identifier into words. A run of upper case letters is an initialism, except for its last
+// letter when a lower case letter follows it: "HTTPServerID" is "HTTP", "Server", "ID". Digits belong to the word
+// before them.
+func splitWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		lowerToUpper := (unicode.IsLower(prev) || unicode.IsDigit(prev)) && unicode.IsUpper(cur)
+		initialismEnd := unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if lowerToUpper || initialismEnd {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
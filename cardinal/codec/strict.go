@@ -0,0 +1,239 @@
+package codec
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rotisserie/eris"
+)
+
+// RequiredTag is the struct tag that marks a field as required in strict decoding: `cardinal:"required"`.
+const RequiredTag = "cardinal"
+
+// Options changes how JSON is decoded into, and encoded from, a type. The zero Options behave like Decode and Encode.
+type Options struct {
+	// Strict rejects keys that don't match a field, and fields that are tagged as required (see RequiredTag) but
+	// missing, instead of leaving them zero valued.
+	Strict bool
+	// Naming is the naming policy for the keys of fields without a name in their json tag. It applies to nested
+	// structs as well.
+	Naming FieldNaming
+}
+
+// Codec decodes and encodes values of type T with Options.
+type Codec[T any] struct {
+	opts Options
+
+	mu    sync.Mutex
+	plans map[reflect.Type][]fieldPlan
+}
+
+// fieldPlan describes how a struct field is found in JSON.
+type fieldPlan struct {
+	// stdKey is the key that encoding/json uses for the field, and wireKey is the key under the naming policy.
+	stdKey   string
+	wireKey  string
+	required bool
+	typ      reflect.Type
+}
+
+var (
+	marshalerType       = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	unmarshalerType     = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func NewCodec[T any](opts Options) *Codec[T] {
+	return &Codec[T]{
+		opts:  opts,
+		plans: map[reflect.Type][]fieldPlan{},
+	}
+}
+
+// Decode decodes the JSON into a T. With strict Options, unknown and missing required fields are errors that name the
+// path of the field, such as "items[2].itemId".
+func (c *Codec[T]) Decode(bz []byte) (T, error) {
+	if c.opts == (Options{}) {
+		return Decode[T](bz)
+	}
+	normalized, err := c.rewrite(reflect.TypeOf(new(T)).Elem(), bz, false, "")
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return Decode[T](normalized)
+}
+
+// Encode encodes the value to JSON, with the keys of the naming policy.
+func (c *Codec[T]) Encode(v any) ([]byte, error) {
+	bz, err := Encode(v)
+	if err != nil || c.opts.Naming == FieldNamingGo {
+		return bz, err
+	}
+	return c.rewrite(reflect.TypeOf(v), bz, true, "")
+}
+
+// rewrite renames the keys of the JSON objects in raw that are the encodings of structs, from the keys of the naming
+// policy to the keys of encoding/json when decoding, and back when encoding. JSON that doesn't match the type is
+// returned unchanged, so that the error is reported by the decoder.
+func (c *Codec[T]) rewrite(t reflect.Type, raw []byte, encode bool, path string) ([]byte, error) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || hasCustomJSON(t) || bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return raw, nil
+	}
+	switch t.Kind() { //nolint:exhaustive // other kinds don't contain structs
+	case reflect.Struct:
+		return c.rewriteStruct(t, raw, encode, path)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return raw, nil
+		}
+		var elems []json.RawMessage
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			return raw, nil //nolint:nilerr // the decoder reports the error
+		}
+		for i := range elems {
+			var err error
+			if elems[i], err = c.rewrite(t.Elem(), elems[i], encode, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return nil, err
+			}
+		}
+		return marshal(elems)
+	case reflect.Map:
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &values); err != nil {
+			return raw, nil //nolint:nilerr // the decoder reports the error
+		}
+		for key, v := range values {
+			var err error
+			if values[key], err = c.rewrite(t.Elem(), v, encode, joinPath(path, key)); err != nil {
+				return nil, err
+			}
+		}
+		return marshal(values)
+	default:
+		return raw, nil
+	}
+}
+
+func (c *Codec[T]) rewriteStruct(t reflect.Type, raw []byte, encode bool, path string) ([]byte, error) {
+	var in map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &in); err != nil {
+		return raw, nil //nolint:nilerr // the decoder reports the error
+	}
+	out := make(map[string]json.RawMessage, len(in))
+	for _, f := range c.plan(t) {
+		from, to := f.wireKey, f.stdKey
+		if encode {
+			from, to = f.stdKey, f.wireKey
+		}
+		key := from
+		v, ok := in[key]
+		if !ok && !encode && c.opts.Naming == FieldNamingGo {
+			// encoding/json matches keys case-insensitively.
+			for k := range in {
+				if strings.EqualFold(k, from) {
+					key, v, ok = k, in[k], true
+					break
+				}
+			}
+		}
+		if !ok {
+			if f.required && c.opts.Strict && !encode {
+				return nil, eris.Errorf("missing required field %q", joinPath(path, f.wireKey))
+			}
+			continue
+		}
+		delete(in, key)
+		var err error
+		if out[to], err = c.rewrite(f.typ, v, encode, joinPath(path, f.wireKey)); err != nil {
+			return nil, err
+		}
+	}
+	if c.opts.Strict && !encode && len(in) > 0 {
+		unknown := make([]string, 0, len(in))
+		for key := range in {
+			unknown = append(unknown, key)
+		}
+		slices.Sort(unknown)
+		return nil, eris.Errorf("unknown field %q", joinPath(path, unknown[0]))
+	}
+	return marshal(out)
+}
+
+// plan returns the fields of the struct type in JSON, including the fields of embedded structs.
+func (c *Codec[T]) plan(t reflect.Type) []fieldPlan {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if fields, ok := c.plans[t]; ok {
+		return fields
+	}
+	fields := c.planFields(t)
+	c.plans[t] = fields
+	return fields
+}
+
+func (c *Codec[T]) planFields(t reflect.Type) []fieldPlan {
+	var fields []fieldPlan
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			// encoding/json promotes the fields of embedded structs.
+			fields = append(fields, c.planFields(fieldType)...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		stdKey := field.Name
+		if name != "" {
+			stdKey = name
+		}
+		fields = append(fields, fieldPlan{
+			stdKey:   stdKey,
+			wireKey:  c.opts.Naming.Key(field),
+			required: field.Tag.Get(RequiredTag) == "required",
+			typ:      field.Type,
+		})
+	}
+	return fields
+}
+
+func hasCustomJSON(t reflect.Type) bool {
+	pt := reflect.PointerTo(t)
+	return t.Implements(marshalerType) || pt.Implements(unmarshalerType) ||
+		t.Implements(textMarshalerType) || pt.Implements(textUnmarshalerType)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func marshal(v any) ([]byte, error) {
+	bz, err := json.Marshal(v)
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	return bz, nil
+}
@@ -0,0 +1,87 @@
+package codec_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/codec"
+)
+
+type Item struct {
+	ItemID   uint64 `cardinal:"required"`
+	Quantity int
+}
+
+type TradeMsg struct {
+	TargetPersona string `cardinal:"required"`
+	Items         []Item
+	Note          string `json:"memo"`
+}
+
+func TestStrictDecodingRejectsUnknownAndMissingFields(t *testing.T) {
+	c := codec.NewCodec[TradeMsg](codec.Options{Strict: true})
+
+	msg, err := c.Decode([]byte(`{"targetpersona":"bob","Items":[{"ItemID":1,"Quantity":2}],"memo":"hi"}`))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, msg, TradeMsg{TargetPersona: "bob", Items: []Item{{ItemID: 1, Quantity: 2}}, Note: "hi"})
+
+	_, err = c.Decode([]byte(`{"TargetPersona":"bob","Amount":5}`))
+	assert.ErrorContains(t, err, `unknown field "Amount"`)
+	_, err = c.Decode([]byte(`{"TargetPersona":"bob","Items":[{"ItemID":1},{"Quantity":2}]}`))
+	assert.ErrorContains(t, err, `missing required field "Items[1].ItemID"`)
+	_, err = c.Decode([]byte(`{"Items":[]}`))
+	assert.ErrorContains(t, err, `missing required field "TargetPersona"`)
+
+	// Without strict decoding, the same payloads are zero valued.
+	lenient := codec.NewCodec[TradeMsg](codec.Options{})
+	msg, err = lenient.Decode([]byte(`{"Items":[{"Quantity":2}],"Amount":5}`))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, msg, TradeMsg{Items: []Item{{Quantity: 2}}})
+}
+
+func TestFieldNaming(t *testing.T) {
+	msg := TradeMsg{TargetPersona: "bob", Items: []Item{{ItemID: 1, Quantity: 2}}, Note: "hi"}
+	testCases := []struct {
+		naming codec.FieldNaming
+		json   string
+	}{
+		{
+			naming: codec.FieldNamingCamelCase,
+			json:   `{"items":[{"itemId":1,"quantity":2}],"memo":"hi","targetPersona":"bob"}`,
+		},
+		{
+			naming: codec.FieldNamingSnakeCase,
+			json:   `{"items":[{"item_id":1,"quantity":2}],"memo":"hi","target_persona":"bob"}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.naming.String(), func(t *testing.T) {
+			c := codec.NewCodec[TradeMsg](codec.Options{Strict: true, Naming: tc.naming})
+			bz, err := c.Encode(msg)
+			assert.NilError(t, err)
+			assert.Equal(t, string(bz), tc.json)
+
+			decoded, err := c.Decode(bz)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, decoded, msg)
+
+			// Go field names are unknown fields under another naming policy.
+			_, err = c.Decode([]byte(`{"items":[],"memo":"hi","TargetPersona":"bob"}`))
+			assert.ErrorContains(t, err, "missing required field")
+			_, err = c.Decode([]byte(`{"Items":[],"memo":"hi","targetPersona":"bob","target_persona":"bob"}`))
+			assert.ErrorContains(t, err, `unknown field "Items"`)
+		})
+	}
+}
+
+func TestSplitsInitialisms(t *testing.T) {
+	type Names struct {
+		HTTPServerID int
+		X            int
+		Level2Boss   int
+	}
+	c := codec.NewCodec[Names](codec.Options{Naming: codec.FieldNamingSnakeCase})
+	bz, err := c.Encode(Names{HTTPServerID: 1, X: 2, Level2Boss: 3})
+	assert.NilError(t, err)
+	assert.Equal(t, string(bz), `{"http_server_id":1,"level2_boss":3,"x":2}`)
+}
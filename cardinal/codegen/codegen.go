@@ -0,0 +1,120 @@
+// Package codegen generates client type definitions from the components, messages, and queries registered in a
+// World. It emits TypeScript interfaces and JSON schemas so client teams can share payload types with the game
+// instead of writing them by hand.
+//
+// Generation is intended to run as a build step. A game typically adds a small program that builds its World the
+// same way its main package does and then calls Generate:
+//
+//	world, _ := cardinal.NewWorld()
+//	registerEverything(world)
+//	if err := codegen.Generate(world, "./client/generated"); err != nil {
+//		log.Fatal(err)
+//	}
+package codegen
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+)
+
+const (
+	typeScriptFileName = "types.ts"
+	schemaDirName      = "schemas"
+)
+
+// Definition describes a single registered Go type that client definitions are generated for.
+type Definition struct {
+	// Name is the name the type is generated under.
+	Name string
+	Type reflect.Type
+	Doc  string
+	// FieldDocs are descriptions of the type's fields, keyed by Go field name.
+	FieldDocs map[string]string
+}
+
+// Endpoint describes a registered message or query and the types of its input and output.
+type Endpoint struct {
+	// FullName is the <group>.<name> of the message or query.
+	FullName string
+	In       reflect.Type
+	Out      reflect.Type
+}
+
+// Spec is the set of registered types to generate client definitions for.
+type Spec struct {
+	Components []Definition
+	Messages   []Endpoint
+	Queries    []Endpoint
+}
+
+// FromWorld collects the components, messages, and queries registered in the given World.
+func FromWorld(w *cardinal.World) Spec {
+	var spec Spec
+	for _, comp := range w.GetRegisteredComponents() {
+		c, _ := comp.Decode(comp.GetSchema())
+		spec.Components = append(spec.Components, Definition{
+			Name:      comp.Name(),
+			Type:      reflect.TypeOf(c),
+			Doc:       comp.Doc(),
+			FieldDocs: comp.FieldDocs(),
+		})
+	}
+	for _, msg := range w.GetRegisteredMessages() {
+		spec.Messages = append(spec.Messages, Endpoint{
+			FullName: msg.FullName(),
+			In:       msg.InType(),
+			Out:      msg.OutType(),
+		})
+	}
+	for _, qry := range w.GetRegisteredQueries() {
+		spec.Queries = append(spec.Queries, Endpoint{
+			FullName: qry.Group() + "." + qry.Name(),
+			In:       qry.RequestType(),
+			Out:      qry.ReplyType(),
+		})
+	}
+	return spec
+}
+
+// Generate writes TypeScript interfaces and JSON schemas for the types registered in the given World to outDir.
+// TypeScript definitions are written to types.ts and JSON schemas to one file per type in the schemas directory.
+func Generate(w *cardinal.World, outDir string) error {
+	spec := FromWorld(w)
+
+	ts, err := TypeScript(spec)
+	if err != nil {
+		return err
+	}
+	schemas, err := JSONSchemas(spec)
+	if err != nil {
+		return err
+	}
+
+	schemaDir := filepath.Join(outDir, schemaDirName)
+	if err := os.MkdirAll(schemaDir, 0o755); err != nil {
+		return eris.Wrap(err, "failed to create output directory")
+	}
+	if err := os.WriteFile(filepath.Join(outDir, typeScriptFileName), []byte(ts), 0o600); err != nil {
+		return eris.Wrap(err, "failed to write typescript definitions")
+	}
+	for name, schema := range schemas {
+		if err := os.WriteFile(filepath.Join(schemaDir, name+".json"), schema, 0o600); err != nil {
+			return eris.Wrapf(err, "failed to write json schema for %s", name)
+		}
+	}
+	return nil
+}
+
+func marshalIndent(v any) ([]byte, error) {
+	bz, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	return bz, nil
+}
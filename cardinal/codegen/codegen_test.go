@@ -0,0 +1,118 @@
+package codegen_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/codegen"
+	"pkg.world.dev/world-engine/cardinal/component"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+)
+
+type Position struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type Health struct {
+	Current int              `json:"current"`
+	Max     int              `json:"max,omitempty"`
+	Tags    []string         `json:"tags"`
+	Bonuses map[string]int   `json:"bonuses"`
+	Origin  *Position        `json:"origin"`
+	Extra   json.RawMessage  `json:"extra"`
+	Ignored string           `json:"-"`
+	Nested  struct{ A bool } `json:"nested"`
+}
+
+func (Health) Name() string { return "health" }
+
+type MoveMsg struct {
+	To Position `json:"to"`
+}
+
+type MoveResult struct {
+	Success bool `json:"success"`
+}
+
+func TestTypeScript(t *testing.T) {
+	spec := codegen.Spec{
+		Components: []codegen.Definition{{
+			Name:      "health",
+			Type:      reflect.TypeOf(Health{}),
+			Doc:       "Hit points of an entity.",
+			FieldDocs: map[string]string{"Current": "Remaining hit points."},
+		}},
+		Messages: []codegen.Endpoint{{
+			FullName: "game.move",
+			In:       reflect.TypeOf(MoveMsg{}),
+			Out:      reflect.TypeOf(MoveResult{}),
+		}},
+	}
+
+	ts, err := codegen.TypeScript(spec)
+	assert.NilError(t, err)
+	assert.Equal(t, ts, `// Code generated by cardinal codegen. DO NOT EDIT.
+
+/** Hit points of an entity. */
+export interface Health {
+  /** Remaining hit points. */
+  current: number;
+  max?: number;
+  tags: string[];
+  bonuses: Record<string, number>;
+  origin?: Position;
+  extra: unknown;
+  nested: { A: boolean; };
+}
+
+export interface MoveMsg {
+  to: Position;
+}
+
+export interface MoveResult {
+  success: boolean;
+}
+
+export interface Position {
+  x: number;
+  y: number;
+}
+
+export interface Components {
+  "health": Health;
+}
+
+export interface Messages {
+  "game.move": { in: MoveMsg; out: MoveResult };
+}
+
+export interface Queries {
+}
+`)
+}
+
+func TestGenerateFromWorld(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, cardinal.RegisterComponent[Health](tf.World, component.WithDoc[Health]("Hit points.")))
+	assert.NilError(t, cardinal.RegisterMessage[MoveMsg, MoveResult](tf.World, "move"))
+
+	dir := t.TempDir()
+	assert.NilError(t, codegen.Generate(tf.World, dir))
+
+	ts, err := os.ReadFile(filepath.Join(dir, "types.ts"))
+	assert.NilError(t, err)
+	assert.Contains(t, string(ts), "/** Hit points. */\nexport interface Health {")
+	assert.Contains(t, string(ts), `"game.move": { in: MoveMsg; out: MoveResult };`)
+
+	schema, err := os.ReadFile(filepath.Join(dir, "schemas", "message.game.move.in.json"))
+	assert.NilError(t, err)
+	assert.Check(t, json.Valid(schema))
+	_, err = os.Stat(filepath.Join(dir, "schemas", "component.health.json"))
+	assert.NilError(t, err)
+}
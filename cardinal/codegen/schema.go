@@ -0,0 +1,47 @@
+package codegen
+
+import (
+	"reflect"
+
+	"github.com/invopop/jsonschema"
+)
+
+// JSONSchemas returns a JSON schema for every type in the spec. Schemas are keyed by component.<name>,
+// message.<group>.<name>.in/out, and query.<group>.<name>.request/reply.
+func JSONSchemas(spec Spec) (map[string][]byte, error) {
+	schemas := make(map[string][]byte)
+	add := func(key string, t reflect.Type) error {
+		if t == nil {
+			return nil
+		}
+		schema := jsonschema.ReflectFromType(t)
+		bz, err := marshalIndent(schema)
+		if err != nil {
+			return err
+		}
+		schemas[key] = bz
+		return nil
+	}
+	for _, def := range spec.Components {
+		if err := add("component."+def.Name, def.Type); err != nil {
+			return nil, err
+		}
+	}
+	for _, msg := range spec.Messages {
+		if err := add("message."+msg.FullName+".in", msg.In); err != nil {
+			return nil, err
+		}
+		if err := add("message."+msg.FullName+".out", msg.Out); err != nil {
+			return nil, err
+		}
+	}
+	for _, qry := range spec.Queries {
+		if err := add("query."+qry.FullName+".request", qry.In); err != nil {
+			return nil, err
+		}
+		if err := add("query."+qry.FullName+".reply", qry.Out); err != nil {
+			return nil, err
+		}
+	}
+	return schemas, nil
+}
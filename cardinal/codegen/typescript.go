@@ -0,0 +1,246 @@
+package codegen
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	bigIntType        = reflect.TypeOf(big.Int{})
+	rawMessageType    = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+	identifierRegexp    = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	nonIdentifierRegexp = regexp.MustCompile(`[^A-Za-z0-9_]`)
+)
+
+// TypeScript returns TypeScript interfaces for every type in the spec, along with the Components, Messages, and
+// Queries interfaces that map registered names to their types.
+func TypeScript(spec Spec) (string, error) {
+	g := &tsGenerator{
+		names: map[reflect.Type]string{},
+		used:  map[string]bool{},
+		docs:  map[reflect.Type]Definition{},
+	}
+	for _, def := range spec.Components {
+		g.docs[def.Type] = def
+	}
+
+	components := make([]string, 0, len(spec.Components))
+	for _, def := range spec.Components {
+		components = append(components, fmt.Sprintf("  %s: %s;\n", strconv.Quote(def.Name), g.ref(def.Type)))
+	}
+	messages := g.endpoints(spec.Messages, "in", "out")
+	queries := g.endpoints(spec.Queries, "request", "reply")
+
+	var b strings.Builder
+	b.WriteString("// Code generated by cardinal codegen. DO NOT EDIT.\n")
+	for i := 0; i < len(g.queue); i++ {
+		b.WriteString("\n")
+		g.writeInterface(&b, g.queue[i])
+	}
+	writeIndex(&b, "Components", components)
+	writeIndex(&b, "Messages", messages)
+	writeIndex(&b, "Queries", queries)
+	return b.String(), nil
+}
+
+type tsGenerator struct {
+	names map[reflect.Type]string
+	used  map[string]bool
+	docs  map[reflect.Type]Definition
+	// queue holds the named struct types that interfaces must be written for, in the order they were discovered.
+	queue []reflect.Type
+}
+
+func (g *tsGenerator) endpoints(endpoints []Endpoint, in, out string) []string {
+	lines := make([]string, 0, len(endpoints))
+	for _, e := range endpoints {
+		lines = append(lines, fmt.Sprintf("  %s: { %s: %s; %s: %s };\n",
+			strconv.Quote(e.FullName), in, g.ref(e.In), out, g.ref(e.Out)))
+	}
+	return lines
+}
+
+func writeIndex(b *strings.Builder, name string, lines []string) {
+	b.WriteString("\nexport interface " + name + " {\n")
+	for _, line := range lines {
+		b.WriteString(line)
+	}
+	b.WriteString("}\n")
+}
+
+// ref returns the TypeScript type expression for the given Go type, following the rules of encoding/json.
+func (g *tsGenerator) ref(t reflect.Type) string {
+	if t == nil {
+		return "unknown"
+	}
+	switch t {
+	case timeType:
+		return "string"
+	case bigIntType:
+		return "number"
+	case rawMessageType:
+		return "unknown"
+	}
+	if t.Kind() == reflect.Pointer {
+		return g.ref(t.Elem())
+	}
+	if implements(t, jsonMarshalerType) {
+		return "unknown"
+	}
+	if implements(t, textMarshalerType) {
+		return "string"
+	}
+
+	switch t.Kind() { //nolint:exhaustive // everything else is unknown
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json encodes byte slices as base64 strings.
+			return "string"
+		}
+		return g.ref(t.Elem()) + "[]"
+	case reflect.Array:
+		return g.ref(t.Elem()) + "[]"
+	case reflect.Map:
+		return "Record<string, " + g.ref(t.Elem()) + ">"
+	case reflect.Struct:
+		if t.Name() == "" {
+			var b strings.Builder
+			b.WriteString("{ ")
+			for _, f := range g.fields(t, nil) {
+				b.WriteString(f.name + optionalMarker(f.optional) + ": " + f.typ + "; ")
+			}
+			b.WriteString("}")
+			return b.String()
+		}
+		return g.name(t)
+	default:
+		return "unknown"
+	}
+}
+
+// name returns the interface name for a named struct type, queueing the interface to be written the first time the
+// type is seen. If two types share a Go name, the package name is used to tell them apart.
+func (g *tsGenerator) name(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	base := nonIdentifierRegexp.ReplaceAllString(t.Name(), "_")
+	name := base
+	if g.used[name] {
+		name = exportedName(path.Base(t.PkgPath())) + base
+	}
+	for i := 2; g.used[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	g.names[t] = name
+	g.used[name] = true
+	g.queue = append(g.queue, t)
+	return name
+}
+
+func (g *tsGenerator) writeInterface(b *strings.Builder, t reflect.Type) {
+	def := g.docs[t]
+	writeDoc(b, "", def.Doc)
+	b.WriteString("export interface " + g.names[t] + " {\n")
+	for _, f := range g.fields(t, def.FieldDocs) {
+		writeDoc(b, "  ", f.doc)
+		b.WriteString("  " + f.name + optionalMarker(f.optional) + ": " + f.typ + ";\n")
+	}
+	b.WriteString("}\n")
+}
+
+type tsField struct {
+	name     string
+	typ      string
+	doc      string
+	optional bool
+}
+
+// fields returns the fields of a struct as they appear in its JSON encoding. Fields of embedded structs without a
+// JSON name are promoted into the parent, as encoding/json does.
+func (g *tsGenerator) fields(t reflect.Type, docs map[string]string) []tsField {
+	var fields []tsField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tagName, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tagName == "-" {
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && tagName == "" && fieldType.Kind() == reflect.Struct {
+			fields = append(fields, g.fields(fieldType, docs)...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tagName != "" {
+			name = tagName
+		}
+		if !identifierRegexp.MatchString(name) {
+			name = strconv.Quote(name)
+		}
+		typ := g.ref(field.Type)
+		if strings.Contains(opts, "string") {
+			typ = "string"
+		}
+		fields = append(fields, tsField{
+			name:     name,
+			typ:      typ,
+			doc:      docs[field.Name],
+			optional: strings.Contains(opts, "omitempty") || field.Type.Kind() == reflect.Pointer,
+		})
+	}
+	return fields
+}
+
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
+
+func optionalMarker(optional bool) string {
+	if optional {
+		return "?"
+	}
+	return ""
+}
+
+func writeDoc(b *strings.Builder, indent, doc string) {
+	if doc == "" {
+		return
+	}
+	b.WriteString(indent + "/** " + strings.ReplaceAll(doc, "*/", "*\\/") + " */\n")
+}
+
+func exportedName(s string) string {
+	s = nonIdentifierRegexp.ReplaceAllString(s, "_")
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
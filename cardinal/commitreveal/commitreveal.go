@@ -0,0 +1,299 @@
+// Package commitreveal is a module for hidden-information moves, such as the moves of a simultaneous-move game. A
+// persona first sends a commitment to its move, a hash that reveals nothing about it, and later reveals the move
+// itself. The move only reaches the game once the module has checked that it matches a commitment that was made in an
+// earlier tick, so neither the other players nor the server operator can see a move before every player is bound to
+// theirs.
+//
+// A commitment is the hex encoded SHA-256 hash of a 32 byte random salt followed by the JSON payload of the move:
+//
+//	salt := make([]byte, commitreveal.SaltSize)
+//	_, _ = rand.Read(salt)
+//	payload, _ := json.Marshal(Move{Hand: "rock"})
+//	commit := commitreveal.CommitMsg{Kind: "hand", Commitment: commitreveal.Commitment(salt, payload)}
+//	// ...in a later tick:
+//	reveal := commitreveal.RevealMsg{Kind: "hand", Salt: hex.EncodeToString(salt), Payload: string(payload)}
+//
+// Game systems read the verified moves of a kind with EachReveal:
+//
+//	err := commitreveal.EachReveal[Move](wCtx, "hand", func(r commitreveal.Reveal[Move]) error {
+//		return play(wCtx, r.PersonaTag, r.Payload)
+//	})
+//
+// A persona has at most one open commitment of each kind; committing again replaces it. Reveals that don't match an
+// open commitment are rejected by tx middleware before any system runs, and each commitment can only be revealed once.
+package commitreveal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+)
+
+const (
+	ModuleName    = "commitreveal"
+	ModuleVersion = "v1.0.0"
+
+	CommitMessageName = "commit"
+	RevealMessageName = "reveal"
+
+	// SaltSize is the size of the salt of a commitment in bytes.
+	SaltSize = 32
+
+	// DefaultRevealWindow is the default number of ticks a commitment can be revealed in after the tick it was made.
+	DefaultRevealWindow = 600
+)
+
+var (
+	ErrNoCommitment      = errors.New("no open commitment from an earlier tick")
+	ErrRevealMismatch    = errors.New("reveal does not match the commitment")
+	ErrInvalidCommitment = errors.New("commitment must be a hex encoded SHA-256 hash")
+	ErrInvalidSalt       = errors.New("salt must be 32 hex encoded bytes")
+)
+
+var _ cardinal.Module = &Module{}
+
+// CommitMsg commits the sender to a move of the given kind without revealing it. Kinds are chosen by the game, and
+// let a persona have commitments for different decisions open at the same time.
+type CommitMsg struct {
+	Kind       string `json:"kind"`
+	Commitment string `json:"commitment"`
+}
+
+type CommitResult struct {
+	// ExpiresAt is the last tick in which the commitment can be revealed.
+	ExpiresAt uint64 `json:"expiresAt"`
+}
+
+// RevealMsg reveals the move that the sender committed to.
+type RevealMsg struct {
+	Kind string `json:"kind"`
+	// Salt is the hex encoded salt of the commitment.
+	Salt string `json:"salt"`
+	// Payload is the JSON encoded move. It is sent as a string so that the signed body of the transaction can be
+	// normalized without changing the bytes that were committed to.
+	Payload string `json:"payload"`
+}
+
+type RevealResult struct {
+	Success bool `json:"success"`
+}
+
+// OpenCommitment is a commitment that has not been revealed yet.
+type OpenCommitment struct {
+	PersonaTag string `json:"personaTag"`
+	Kind       string `json:"kind"`
+	Commitment string `json:"commitment"`
+	// Tick is the tick the commitment was made in.
+	Tick uint64 `json:"tick"`
+}
+
+func (OpenCommitment) Name() string { return "commitreveal-commitment" }
+
+// Commitment returns the commitment to the payload with the salt.
+func Commitment(salt, payload []byte) string {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write(payload)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type Option func(*Module)
+
+// WithRevealWindow sets the number of ticks a commitment can be revealed in after the tick it was made. Commitments
+// that are not revealed in time are discarded.
+func WithRevealWindow(ticks uint64) Option {
+	return func(m *Module) {
+		m.window = ticks
+	}
+}
+
+type Module struct {
+	cardinal.ModuleBase
+	window uint64
+}
+
+func NewModule(opts ...Option) *Module {
+	m := &Module{window: DefaultRevealWindow}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func (*Module) Name() string    { return ModuleName }
+func (*Module) Version() string { return ModuleVersion }
+
+func (*Module) RegisterComponents(w *cardinal.World) error {
+	return cardinal.RegisterComponent[OpenCommitment](w)
+}
+
+func (*Module) RegisterTxs(w *cardinal.World) error {
+	if err := cardinal.RegisterMessage[CommitMsg, CommitResult](w, CommitMessageName); err != nil {
+		return err
+	}
+	return cardinal.RegisterMessage[RevealMsg, RevealResult](w, RevealMessageName)
+}
+
+func (m *Module) RegisterSystems(w *cardinal.World) error {
+	return cardinal.RegisterSystems(w, m.commitSystem)
+}
+
+// Init registers the tx middleware that checks reveals against their commitments.
+func (m *Module) Init(w *cardinal.World) error {
+	return cardinal.RegisterTxMiddleware(w, m.verifyReveal)
+}
+
+// commitSystem records this tick's commitments, then discards the commitments that can no longer be revealed.
+func (m *Module) commitSystem(wCtx engine.Context) error {
+	err := cardinal.EachMessage[CommitMsg, CommitResult](wCtx,
+		func(tx message.TxData[CommitMsg]) (CommitResult, error) {
+			return m.commit(wCtx, tx)
+		})
+	if err != nil {
+		return err
+	}
+	tick := wCtx.CurrentTick()
+	var expired []types.EntityID
+	err = cardinal.NewSearch().Entity(filter.Exact(filter.Component[OpenCommitment]())).Each(wCtx,
+		func(id types.EntityID) bool {
+			c, err := cardinal.GetComponent[OpenCommitment](wCtx, id)
+			if err == nil && tick > c.Tick+m.window {
+				expired = append(expired, id)
+			}
+			return true
+		})
+	if err != nil {
+		return err
+	}
+	for _, id := range expired {
+		if err := cardinal.Remove(wCtx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Module) commit(wCtx engine.Context, tx message.TxData[CommitMsg]) (CommitResult, error) {
+	if b, err := hex.DecodeString(tx.Msg.Commitment); err != nil || len(b) != sha256.Size {
+		return CommitResult{}, ErrInvalidCommitment
+	}
+	c := OpenCommitment{
+		PersonaTag: tx.Tx.PersonaTag,
+		Kind:       tx.Msg.Kind,
+		Commitment: strings.ToLower(tx.Msg.Commitment),
+		Tick:       wCtx.CurrentTick(),
+	}
+	id, found, err := findCommitment(wCtx, c.PersonaTag, c.Kind)
+	if err != nil {
+		return CommitResult{}, err
+	}
+	if found {
+		err = cardinal.SetComponent[OpenCommitment](wCtx, id, &c)
+	} else {
+		_, err = cardinal.Create(wCtx, c)
+	}
+	if err != nil {
+		return CommitResult{}, err
+	}
+	return CommitResult{ExpiresAt: c.Tick + m.window}, nil
+}
+
+// verifyReveal rejects reveals that don't match an open commitment of the sender. Since middleware runs before the
+// systems that record commitments, a commitment can't be revealed in the tick it was made in. The commitment is closed
+// by its reveal, even if a system later fails to process it.
+func (m *Module) verifyReveal(wCtx engine.Context, _ types.Message, tx txpool.TxData) error {
+	reveal, ok := tx.Msg.(RevealMsg)
+	if !ok {
+		return nil
+	}
+	if tx.Tx == nil {
+		return ErrNoCommitment
+	}
+	salt, err := hex.DecodeString(reveal.Salt)
+	if err != nil || len(salt) != SaltSize {
+		return ErrInvalidSalt
+	}
+	id, found, err := findCommitment(wCtx, tx.Tx.PersonaTag, reveal.Kind)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return eris.Wrapf(ErrNoCommitment, "kind %q", reveal.Kind)
+	}
+	c, err := cardinal.GetComponent[OpenCommitment](wCtx, id)
+	if err != nil {
+		return err
+	}
+	if c.Tick >= wCtx.CurrentTick() || wCtx.CurrentTick() > c.Tick+m.window {
+		return eris.Wrapf(ErrNoCommitment, "kind %q", reveal.Kind)
+	}
+	if Commitment(salt, []byte(reveal.Payload)) != c.Commitment {
+		return ErrRevealMismatch
+	}
+	return cardinal.Remove(wCtx, id)
+}
+
+func findCommitment(wCtx engine.Context, personaTag, kind string) (types.EntityID, bool, error) {
+	ids, err := cardinal.NewSearch().
+		Entity(filter.Exact(filter.Component[OpenCommitment]())).
+		Where(cardinal.FilterFunction[OpenCommitment](func(c OpenCommitment) bool {
+			return c.PersonaTag == personaTag && c.Kind == kind
+		})).
+		Collect(wCtx)
+	if err != nil || len(ids) == 0 {
+		return 0, false, err
+	}
+	return ids[0], true, nil
+}
+
+// Reveal is a verified move.
+type Reveal[T any] struct {
+	Hash       types.TxHash
+	PersonaTag string
+	Payload    T
+}
+
+// EachReveal calls fn for each of this tick's reveals of the kind, in the order they were sent. Only reveals that
+// matched their commitment are passed to fn. The receipt of a reveal records the error that fn returns for it, or
+// that its payload could not be decoded as a T.
+func EachReveal[T any](wCtx engine.Context, kind string, fn func(Reveal[T]) error) error {
+	var revealType message.MessageType[RevealMsg, RevealResult]
+	found, ok := wCtx.GetMessageByType(reflect.TypeOf(revealType))
+	if !ok {
+		return eris.Errorf("message %s is not registered, the %s module may not be used", RevealMessageName, ModuleName)
+	}
+	reveals, ok := found.(*message.MessageType[RevealMsg, RevealResult])
+	if !ok {
+		return eris.New("wrong type")
+	}
+	for _, tx := range reveals.In(wCtx) {
+		if tx.Msg.Kind != kind {
+			continue
+		}
+		r := Reveal[T]{Hash: tx.Hash, PersonaTag: tx.Tx.PersonaTag}
+		err := json.Unmarshal([]byte(tx.Msg.Payload), &r.Payload)
+		if err != nil {
+			err = eris.Wrap(err, "failed to decode reveal payload")
+		} else {
+			err = fn(r)
+		}
+		if err != nil {
+			reveals.AddError(wCtx, tx.Hash, err)
+			continue
+		}
+		reveals.SetResult(wCtx, tx.Hash, RevealResult{Success: true})
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package commitreveal_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/commitreveal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type Hand struct {
+	Shape string `json:"shape"`
+}
+
+type revealFixture struct {
+	*testutils.TestFixture
+	played map[string]string
+}
+
+func newRevealFixture(t *testing.T, opts ...commitreveal.Option) *revealFixture {
+	tf := &revealFixture{TestFixture: testutils.NewTestFixture(t, nil), played: map[string]string{}}
+	assert.NilError(t, tf.World.UseModule(commitreveal.NewModule(opts...)))
+	assert.NilError(t, cardinal.RegisterSystems(tf.World, func(wCtx engine.Context) error {
+		return commitreveal.EachReveal[Hand](wCtx, "hand", func(r commitreveal.Reveal[Hand]) error {
+			tf.played[r.PersonaTag] = r.Payload.Shape
+			return nil
+		})
+	}))
+	tf.DoTick()
+	return tf
+}
+
+func (tf *revealFixture) send(personaTag, name string, msg any) types.TxHash {
+	msgType, ok := tf.World.GetMessageByFullName(commitreveal.ModuleName + "." + name)
+	assert.Check(tf, ok, "message %q is not registered", name)
+	return tf.AddTransaction(msgType.ID(), msg, testutils.UniqueSignatureWithName(personaTag))
+}
+
+func (tf *revealFixture) errs(hash types.TxHash) []error {
+	_, errs, ok := cardinal.NewReadOnlyWorldContext(tf.World).GetTransactionReceipt(hash)
+	assert.Check(tf, ok)
+	return errs
+}
+
+func (tf *revealFixture) commit(personaTag, shape string, salt []byte) commitreveal.RevealMsg {
+	payload := `{"shape":"` + shape + `"}`
+	tf.send(personaTag, commitreveal.CommitMessageName, commitreveal.CommitMsg{
+		Kind:       "hand",
+		Commitment: commitreveal.Commitment(salt, []byte(payload)),
+	})
+	return commitreveal.RevealMsg{Kind: "hand", Salt: hex.EncodeToString(salt), Payload: payload}
+}
+
+func TestRevealMustMatchCommitmentFromEarlierTick(t *testing.T) {
+	tf := newRevealFixture(t)
+	salt := bytes.Repeat([]byte{7}, commitreveal.SaltSize)
+
+	reveal := tf.commit("alice", "rock", salt)
+	early := tf.send("alice", commitreveal.RevealMessageName, reveal)
+	tf.DoTick()
+	errs := tf.errs(early)
+	assert.Equal(t, len(errs), 1)
+	assert.Check(t, errors.Is(errs[0], commitreveal.ErrNoCommitment))
+	assert.Equal(t, len(tf.played), 0)
+
+	forged := reveal
+	forged.Payload = `{"shape":"paper"}`
+	mismatch := tf.send("alice", commitreveal.RevealMessageName, forged)
+	otherPersona := tf.send("bob", commitreveal.RevealMessageName, reveal)
+	tf.DoTick()
+	assert.Check(t, errors.Is(tf.errs(mismatch)[0], commitreveal.ErrRevealMismatch))
+	assert.Check(t, errors.Is(tf.errs(otherPersona)[0], commitreveal.ErrNoCommitment))
+	assert.Equal(t, len(tf.played), 0)
+
+	revealed := tf.send("alice", commitreveal.RevealMessageName, reveal)
+	tf.DoTick()
+	assert.Equal(t, len(tf.errs(revealed)), 0)
+	assert.Equal(t, tf.played["alice"], "rock")
+
+	// A commitment can only be revealed once.
+	again := tf.send("alice", commitreveal.RevealMessageName, reveal)
+	tf.DoTick()
+	assert.Check(t, errors.Is(tf.errs(again)[0], commitreveal.ErrNoCommitment))
+}
+
+func TestUnrevealedCommitmentsExpire(t *testing.T) {
+	tf := newRevealFixture(t, commitreveal.WithRevealWindow(2))
+	reveal := tf.commit("alice", "scissors", bytes.Repeat([]byte{1}, commitreveal.SaltSize))
+	for range 3 {
+		tf.DoTick()
+	}
+	late := tf.send("alice", commitreveal.RevealMessageName, reveal)
+	tf.DoTick()
+	assert.Check(t, errors.Is(tf.errs(late)[0], commitreveal.ErrNoCommitment))
+	assert.Equal(t, len(tf.played), 0)
+}
+
+func TestRevealRejectsShortSalt(t *testing.T) {
+	tf := newRevealFixture(t)
+	reveal := tf.commit("alice", "rock", []byte("salt"))
+	tf.DoTick()
+	hash := tf.send("alice", commitreveal.RevealMessageName, reveal)
+	tf.DoTick()
+	assert.Check(t, errors.Is(tf.errs(hash)[0], commitreveal.ErrInvalidSalt))
+}
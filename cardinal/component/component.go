@@ -28,6 +28,9 @@ type componentMetadata[T types.Component] struct {
 	name       string
 	schema     []byte
 	defaultVal types.Component
+	ephemeral  bool
+	doc        string
+	fieldDocs  map[string]string
 }
 
 // NewComponentMetadata creates a new component type.
@@ -90,6 +93,18 @@ func (c *componentMetadata[T]) ID() types.ComponentID {
 	return c.id
 }
 
+func (c *componentMetadata[T]) IsEphemeral() bool {
+	return c.ephemeral
+}
+
+func (c *componentMetadata[T]) Doc() string {
+	return c.doc
+}
+
+func (c *componentMetadata[T]) FieldDocs() map[string]string {
+	return c.fieldDocs
+}
+
 func (c *componentMetadata[T]) New() ([]byte, error) {
 	if c.defaultVal != nil {
 		return codec.Encode(c.defaultVal)
@@ -131,3 +146,31 @@ func WithDefault[T types.Component](defaultVal T) Option[T] {
 		c.validateDefaultVal()
 	}
 }
+
+// WithEphemeral marks the component as ephemeral. Values of ephemeral components are kept only in the in-process
+// cache; they are never written to storage and are excluded from state hashes. After a restart, ephemeral components
+// report their default value. This is useful for derived data that is expensive to store but cheap to recompute.
+func WithEphemeral[T types.Component]() Option[T] {
+	return func(c *componentMetadata[T]) {
+		c.ephemeral = true
+	}
+}
+
+// WithDoc attaches a human-readable description to the component. The description is reported by the component
+// reflection endpoint and is used in generated client type definitions.
+func WithDoc[T types.Component](doc string) Option[T] {
+	return func(c *componentMetadata[T]) {
+		c.doc = doc
+	}
+}
+
+// WithFieldDoc attaches a human-readable description to a single field of the component. The field is identified by
+// its Go field name.
+func WithFieldDoc[T types.Component](field, doc string) Option[T] {
+	return func(c *componentMetadata[T]) {
+		if c.fieldDocs == nil {
+			c.fieldDocs = make(map[string]string)
+		}
+		c.fieldDocs[field] = doc
+	}
+}
@@ -0,0 +1,119 @@
+package cardinal
+
+import (
+	"slices"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// derivedComponent is a component whose value is a function of other components on the same entity.
+type derivedComponent struct {
+	name    string
+	inputs  []string
+	compute func(wCtx engine.Context, id types.EntityID) (types.Component, error)
+}
+
+// DeriveComponent registers T as a derived component. The value of T is computed with the given function from the
+// input components on the same entity, and it is recomputed automatically at the end of every tick in which any of
+// the inputs was set on that entity. The inputs must already be registered. Derived components are registered like
+// normal components, so they can be searched for and read with GetComponent. Only entities that have the derived
+// component attached are recomputed.
+//
+// Example:
+//
+//	err := cardinal.DeriveComponent[Power](world, computePower, Strength{}, Gear{})
+func DeriveComponent[T types.Component](
+	w *World,
+	compute func(wCtx engine.Context, id types.EntityID) (T, error),
+	from ...types.Component,
+) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"engine state is %s, expected %s to register derived component",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	if compute == nil {
+		return eris.New("cannot derive component without compute function")
+	}
+	if len(from) == 0 {
+		return eris.New("derived component must have at least one input component")
+	}
+
+	var t T
+	inputs := make([]string, 0, len(from))
+	for _, comp := range from {
+		if comp.Name() == t.Name() {
+			return eris.Errorf("derived component %q cannot be derived from itself", t.Name())
+		}
+		if _, err := w.GetComponentByName(comp.Name()); err != nil {
+			return eris.Wrapf(err, "input component of derived component %q must be registered first", t.Name())
+		}
+		inputs = append(inputs, comp.Name())
+	}
+
+	if err := RegisterComponent[T](w); err != nil {
+		return err
+	}
+
+	w.derivedComponents = append(w.derivedComponents, derivedComponent{
+		name:   t.Name(),
+		inputs: inputs,
+		compute: func(wCtx engine.Context, id types.EntityID) (types.Component, error) {
+			return compute(wCtx, id)
+		},
+	})
+	return nil
+}
+
+// recomputeDerivedComponents recomputes the derived components of every entity whose inputs were changed during the
+// current tick. Derived components are recomputed in registration order, so a derived component may use another
+// derived component that was registered before it as an input.
+func (w *World) recomputeDerivedComponents(wCtx engine.Context) error {
+	for _, derived := range w.derivedComponents {
+		derivedComp, err := w.GetComponentByName(derived.name)
+		if err != nil {
+			return err
+		}
+
+		var changed []types.EntityID
+		for _, input := range derived.inputs {
+			inputComp, err := w.GetComponentByName(input)
+			if err != nil {
+				return err
+			}
+			ids, err := w.entityStore.GetChangedEntities(inputComp)
+			if err != nil {
+				return err
+			}
+			changed = append(changed, ids...)
+		}
+		slices.Sort(changed)
+		changed = slices.Compact(changed)
+
+		for _, id := range changed {
+			comps, err := w.entityStore.GetComponentTypesForEntity(id)
+			if err != nil {
+				return err
+			}
+			if !slices.ContainsFunc(comps, func(c types.ComponentMetadata) bool {
+				return c.ID() == derivedComp.ID()
+			}) {
+				continue
+			}
+			value, err := derived.compute(wCtx, id)
+			if err != nil {
+				return eris.Wrapf(err, "failed to compute derived component %q for entity %d", derived.name, id)
+			}
+			if err = w.entityStore.SetComponentForEntity(derivedComp, id, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type Strength struct {
+	Value int
+}
+
+func (Strength) Name() string { return "strength" }
+
+type Gear struct {
+	Bonus int
+}
+
+func (Gear) Name() string { return "gear" }
+
+type Power struct {
+	Value int
+}
+
+func (Power) Name() string { return "power" }
+
+func computePower(wCtx engine.Context, id types.EntityID) (Power, error) {
+	strength, err := cardinal.GetComponent[Strength](wCtx, id)
+	if err != nil {
+		return Power{}, err
+	}
+	gear, err := cardinal.GetComponent[Gear](wCtx, id)
+	if err != nil {
+		return Power{}, err
+	}
+	return Power{Value: strength.Value + gear.Bonus}, nil
+}
+
+func TestDerivedComponentIsRecomputedWhenInputsChange(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Strength](world))
+	assert.NilError(t, cardinal.RegisterComponent[Gear](world))
+	assert.NilError(t, cardinal.DeriveComponent[Power](world, computePower, Strength{}, Gear{}))
+
+	var id types.EntityID
+	assert.NilError(t, cardinal.RegisterInitSystems(world, func(wCtx engine.Context) error {
+		var err error
+		id, err = cardinal.Create(wCtx, Strength{Value: 10}, Gear{Bonus: 5}, Power{})
+		return err
+	}))
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		return cardinal.NewSearch().Entity(filter.Contains(filter.Component[Strength]())).
+			Each(wCtx, func(id types.EntityID) bool {
+				assert.NilError(t, cardinal.UpdateComponent[Strength](wCtx, id, func(s *Strength) *Strength {
+					s.Value++
+					return s
+				}))
+				return true
+			})
+	}))
+
+	tf.StartWorld()
+	tf.DoTick()
+
+	wCtx := cardinal.NewReadOnlyWorldContext(world)
+	power, err := cardinal.GetComponent[Power](wCtx, id)
+	assert.NilError(t, err)
+	assert.Equal(t, 16, power.Value)
+
+	tf.DoTick()
+	power, err = cardinal.GetComponent[Power](wCtx, id)
+	assert.NilError(t, err)
+	assert.Equal(t, 17, power.Value)
+}
+
+func TestDerivedComponentInputsMustBeRegistered(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, cardinal.RegisterComponent[Strength](tf.World))
+	err := cardinal.DeriveComponent[Power](tf.World, computePower, Strength{}, Gear{})
+	assert.ErrorContains(t, err, "must be registered first")
+}
@@ -0,0 +1,112 @@
+package content_test
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/content"
+	"pkg.world.dev/world-engine/cardinal/server/utils"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/sign"
+)
+
+type Health struct {
+	Max int `json:"max"`
+}
+
+func (Health) Name() string { return "health" }
+
+type Loot struct {
+	Item string `json:"item"`
+}
+
+func (Loot) Name() string { return "loot" }
+
+func export(tf *testutils.TestFixture, req content.ExportRequest) content.Document {
+	res := tf.Post("query/content/export", req)
+	assert.Equal(tf, res.StatusCode, http.StatusOK)
+	var doc content.Document
+	assert.NilError(tf, json.NewDecoder(res.Body).Decode(&doc))
+	return doc
+}
+
+func TestExportAndImportEntities(t *testing.T) {
+	adminKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	tf := testutils.NewTestFixture(t, nil,
+		cardinal.WithAdminSigners(crypto.PubkeyToAddress(adminKey.PublicKey).Hex()))
+	assert.NilError(t, cardinal.RegisterComponent[Health](tf.World))
+	assert.NilError(t, cardinal.RegisterComponent[Loot](tf.World))
+	assert.NilError(t, tf.World.UseModule(content.NewModule()))
+	tf.DoTick()
+
+	nonce := uint64(0)
+	importDoc := func(key *ecdsa.PrivateKey, doc content.Document) int {
+		tx, err := sign.NewSystemTransaction(key, tf.World.Namespace(), nonce, doc)
+		assert.NilError(t, err)
+		nonce++
+		res := tf.Post(utils.GetTxURL(content.ModuleName, content.ImportMessageName), tx)
+		tf.DoTick()
+		return res.StatusCode
+	}
+
+	goblin := content.Entity{ContentID: "goblin", Components: map[string]json.RawMessage{
+		"health": json.RawMessage(`{"max": 10}`),
+	}}
+	dragon := content.Entity{ContentID: "dragon", Components: map[string]json.RawMessage{
+		"health": json.RawMessage(`{"max": 500}`),
+		"loot":   json.RawMessage(`{"item": "hoard"}`),
+	}}
+	assert.Equal(t, importDoc(adminKey, content.Document{Entities: []content.Entity{goblin, dragon}}), http.StatusOK)
+
+	goblinID, err := tf.World.GetByContentID("goblin")
+	assert.NilError(t, err)
+
+	doc := export(tf, content.ExportRequest{})
+	assert.Equal(t, len(doc.Entities), 2)
+	assert.Equal(t, doc.Entities[0].ContentID, "dragon")
+	assert.Equal(t, doc.Entities[1].ContentID, "goblin")
+	assert.Equal(t, string(doc.Entities[1].Components["health"]), `{"max":10}`)
+
+	// Edit the goblin, and add a new entity. The dragon is left out, so it doesn't change.
+	goblin.Components = map[string]json.RawMessage{
+		"health": json.RawMessage(`{"max": 15}`),
+		"loot":   json.RawMessage(`{"item": "dagger"}`),
+	}
+	orc := content.Entity{ContentID: "orc", Components: map[string]json.RawMessage{
+		"health": json.RawMessage(`{"max": 40}`),
+	}}
+	assert.Equal(t, importDoc(adminKey, content.Document{Entities: []content.Entity{goblin, orc}}), http.StatusOK)
+
+	// The goblin is updated in place, rather than recreated.
+	id, err := tf.World.GetByContentID("goblin")
+	assert.NilError(t, err)
+	assert.Equal(t, id, goblinID)
+
+	doc = export(tf, content.ExportRequest{ContentIDs: []string{"goblin", "dragon"}})
+	assert.Equal(t, len(doc.Entities), 2)
+	assert.Equal(t, string(doc.Entities[0].Components["loot"]), `{"item":"hoard"}`)
+	assert.Equal(t, string(doc.Entities[1].Components["health"]), `{"max":15}`)
+	assert.Equal(t, string(doc.Entities[1].Components["loot"]), `{"item":"dagger"}`)
+
+	doc = export(tf, content.ExportRequest{CQL: "CONTAINS(loot)"})
+	assert.Equal(t, len(doc.Entities), 2)
+
+	// A document with a mistake in it changes nothing.
+	bad := content.Entity{ContentID: "troll", Components: map[string]json.RawMessage{
+		"stamina": json.RawMessage(`{}`),
+	}}
+	assert.Equal(t, importDoc(adminKey, content.Document{Entities: []content.Entity{orc, bad}}), http.StatusOK)
+	assert.Equal(t, len(export(tf, content.ExportRequest{}).Entities), 3)
+
+	// Only admin signers can import content.
+	otherKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	assert.Equal(t, importDoc(otherKey, content.Document{Entities: []content.Entity{orc}}), http.StatusBadRequest)
+}
@@ -0,0 +1,114 @@
+package content
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/server/handler/cql"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// Document is a set of entities in a form that designers can edit. It is both the reply of the export query and the
+// import-entities message.
+type Document struct {
+	Entities []Entity `json:"entities"`
+}
+
+// Entity is an entity of a Document. Components maps the names of the entity's components to their values.
+type Entity struct {
+	ContentID  string                     `json:"contentId,omitempty"`
+	Components map[string]json.RawMessage `json:"components"`
+}
+
+type ExportRequest struct {
+	// CQL selects the entities to export, e.g. "CONTAINS(health)". By default, every entity with a content ID is
+	// exported.
+	CQL string `json:"cql"`
+	// ContentIDs, if given, limits the export to the entities with these content IDs.
+	ContentIDs []string `json:"contentIds"`
+}
+
+// export returns the entities that match the request, sorted by content ID, so that exports of the same content can
+// be diffed. Entities without a content ID are sorted last, by entity ID.
+func export(wCtx engine.Context, req *ExportRequest) (*Document, error) {
+	var f filter.ComponentFilter = filter.Contains(filter.Component[cardinal.ContentID]())
+	if req.CQL != "" {
+		var err error
+		f, err = cql.Parse(req.CQL, func(name string) (types.Component, error) {
+			return wCtx.GetComponentByName(name)
+		})
+		if err != nil {
+			return nil, eris.Wrap(err, "invalid CQL")
+		}
+	}
+
+	type exported struct {
+		id     types.EntityID
+		entity Entity
+	}
+	var entities []exported
+	var eachErr error
+	err := cardinal.NewSearch().Entity(f).Each(wCtx, func(id types.EntityID) bool {
+		var entity Entity
+		entity, eachErr = exportEntity(wCtx, id)
+		if eachErr != nil {
+			return false
+		}
+		if len(req.ContentIDs) == 0 || slices.Contains(req.ContentIDs, entity.ContentID) {
+			entities = append(entities, exported{id: id, entity: entity})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if eachErr != nil {
+		return nil, eachErr
+	}
+
+	sort.Slice(entities, func(i, j int) bool {
+		a, b := entities[i], entities[j]
+		if (a.entity.ContentID == "") != (b.entity.ContentID == "") {
+			return a.entity.ContentID != ""
+		}
+		if a.entity.ContentID != b.entity.ContentID {
+			return a.entity.ContentID < b.entity.ContentID
+		}
+		return a.id < b.id
+	})
+	doc := &Document{Entities: make([]Entity, 0, len(entities))}
+	for _, e := range entities {
+		doc.Entities = append(doc.Entities, e.entity)
+	}
+	return doc, nil
+}
+
+func exportEntity(wCtx engine.Context, id types.EntityID) (Entity, error) {
+	entity := Entity{Components: map[string]json.RawMessage{}}
+	comps, err := wCtx.StoreReader().GetComponentTypesForEntity(id)
+	if err != nil {
+		return entity, err
+	}
+	for _, c := range comps {
+		if c.Name() == (cardinal.ContentID{}).Name() {
+			contentID, err := cardinal.GetComponent[cardinal.ContentID](wCtx, id)
+			if err != nil {
+				return entity, err
+			}
+			entity.ContentID = contentID.ID
+			continue
+		}
+		value, err := wCtx.StoreReader().GetComponentForEntityInRawJSON(c, id)
+		if err != nil {
+			return entity, err
+		}
+		entity.Components[c.Name()] = value
+	}
+	return entity, nil
+}
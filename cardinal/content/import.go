@@ -0,0 +1,151 @@
+package content
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+var ErrNotAdminTransaction = errors.New("content can only be imported by a system transaction of an admin signer")
+
+// ImportResult lists the content IDs of the entities that an import created and updated, in the order of the
+// document.
+type ImportResult struct {
+	Created []string `json:"created"`
+	Updated []string `json:"updated"`
+}
+
+// importedEntity is an entity of a document whose component values have been decoded.
+type importedEntity struct {
+	contentID string
+	comps     []types.ComponentMetadata
+	values    []types.Component
+}
+
+// importSystem applies the documents of the import-entities transactions of the tick. A document is checked in full
+// before any of it is applied, so a document with a mistake in it changes nothing.
+func importSystem(wCtx engine.Context) error {
+	var index map[string]types.EntityID
+	return cardinal.EachMessage[Document, ImportResult](wCtx, func(tx message.TxData[Document]) (ImportResult, error) {
+		// The server only accepts system transactions from admin signers.
+		if !tx.Tx.IsSystemTransaction() {
+			return ImportResult{}, eris.Wrap(ErrNotAdminTransaction, "")
+		}
+		entities, err := decodeDocument(wCtx, tx.Msg)
+		if err != nil {
+			return ImportResult{}, err
+		}
+		if index == nil {
+			if index, err = contentIndex(wCtx); err != nil {
+				return ImportResult{}, err
+			}
+		}
+
+		result := ImportResult{Created: []string{}, Updated: []string{}}
+		for _, entity := range entities {
+			if id, ok := index[entity.contentID]; ok {
+				if err := updateEntity(wCtx, id, entity); err != nil {
+					return result, eris.Wrapf(err, "failed to update entity %q", entity.contentID)
+				}
+				result.Updated = append(result.Updated, entity.contentID)
+				continue
+			}
+			values := append([]types.Component{cardinal.ContentID{ID: entity.contentID}}, entity.values...)
+			id, err := cardinal.Create(wCtx, values...)
+			if err != nil {
+				return result, eris.Wrapf(err, "failed to create entity %q", entity.contentID)
+			}
+			index[entity.contentID] = id
+			result.Created = append(result.Created, entity.contentID)
+		}
+		return result, nil
+	})
+}
+
+func decodeDocument(wCtx engine.Context, doc Document) ([]importedEntity, error) {
+	seen := map[string]bool{}
+	entities := make([]importedEntity, 0, len(doc.Entities))
+	for i, e := range doc.Entities {
+		if e.ContentID == "" {
+			return nil, eris.Errorf("entity %d has no content ID", i)
+		}
+		if seen[e.ContentID] {
+			return nil, eris.Errorf("content ID %q is used by more than one entity", e.ContentID)
+		}
+		seen[e.ContentID] = true
+
+		// Components are applied in the order of their names, so that every replica applies them in the same order.
+		names := make([]string, 0, len(e.Components))
+		for name := range e.Components {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		entity := importedEntity{contentID: e.ContentID}
+		for _, name := range names {
+			if name == (cardinal.ContentID{}).Name() {
+				return nil, eris.Errorf("entity %q: the content ID must be set with contentId", e.ContentID)
+			}
+			c, err := wCtx.GetComponentByName(name)
+			if err != nil {
+				return nil, eris.Wrapf(err, "entity %q", e.ContentID)
+			}
+			value, err := c.Decode(e.Components[name])
+			if err != nil {
+				return nil, eris.Wrapf(err, "entity %q: invalid value for component %s", e.ContentID, name)
+			}
+			entity.comps = append(entity.comps, c)
+			entity.values = append(entity.values, value)
+		}
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
+
+func updateEntity(wCtx engine.Context, id types.EntityID, entity importedEntity) error {
+	current, err := wCtx.StoreReader().GetComponentTypesForEntity(id)
+	if err != nil {
+		return err
+	}
+	has := map[string]bool{}
+	for _, c := range current {
+		has[c.Name()] = true
+	}
+	for i, c := range entity.comps {
+		if !has[c.Name()] {
+			if err := wCtx.StoreManager().AddComponentToEntity(c, id); err != nil {
+				return err
+			}
+		}
+		if err := wCtx.StoreManager().SetComponentForEntity(c, id, entity.values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// contentIndex maps the content IDs of the world's entities to their entity IDs.
+func contentIndex(wCtx engine.Context) (map[string]types.EntityID, error) {
+	index := map[string]types.EntityID{}
+	var eachErr error
+	err := cardinal.NewSearch().Entity(filter.Contains(filter.Component[cardinal.ContentID]())).Each(wCtx,
+		func(id types.EntityID) bool {
+			var contentID *cardinal.ContentID
+			contentID, eachErr = cardinal.GetComponent[cardinal.ContentID](wCtx, id)
+			if eachErr != nil {
+				return false
+			}
+			index[contentID.ID] = id
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+	return index, eachErr
+}
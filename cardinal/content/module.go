@@ -0,0 +1,64 @@
+// Package content is a module for content tools. It exports entities to JSON that designers can edit offline, and
+// imports the edited JSON back into the world:
+//
+//	err = world.UseModule(content.NewModule())
+//
+// Entities are matched between exports and imports by a stable content ID, which is kept in the cardinal.ContentID
+// component; use World.GetByContentID to look entities up by it. Imports upsert: an import updates the entities
+// whose content IDs already exist, and creates the others. Each component in the document replaces the entity's value
+// for that component; components that are left out of the document are not changed. Entities that were exported
+// without a content ID, such as entities created by the game's systems, must be given one before they are imported,
+// which creates new entities.
+//
+// Documents are exported by the /query/content/export query. They are imported by the import-entities message, which
+// must be sent as a system transaction signed by one of the world's admin signers (see cardinal.WithAdminSigners):
+//
+//	tx, err := sign.NewSystemTransaction(adminKey, namespace, nonce, document)
+//	// POST tx to /tx/content/import-entities
+//
+// Imports are executed in a tick like any other transaction, so they are replayed during recovery, and every replica
+// applies them in the same way.
+package content
+
+import (
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+)
+
+const (
+	ModuleName    = "content"
+	ModuleVersion = "v1.0.0"
+
+	ImportMessageName = "import-entities"
+)
+
+var _ cardinal.Module = &Module{}
+
+type Module struct {
+	cardinal.ModuleBase
+}
+
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (*Module) Name() string    { return ModuleName }
+func (*Module) Version() string { return ModuleVersion }
+
+func (*Module) RegisterComponents(w *cardinal.World) error {
+	return cardinal.RegisterComponent[cardinal.ContentID](w)
+}
+
+func (*Module) RegisterTxs(w *cardinal.World) error {
+	return cardinal.RegisterMessage[Document, ImportResult](w, ImportMessageName,
+		message.WithAdminSignature[Document, ImportResult]())
+}
+
+// RegisterReads registers the /query/content/export query.
+func (*Module) RegisterReads(w *cardinal.World) error {
+	return cardinal.RegisterReads(w, ModuleName, cardinal.NewRead[ExportRequest, Document]("export", export))
+}
+
+func (*Module) RegisterSystems(w *cardinal.World) error {
+	return cardinal.RegisterSystems(w, importSystem)
+}
@@ -0,0 +1,222 @@
+// Package cooldown is a module that limits how often personas can send transactions. Each message type can have a
+// cooldown, the number of ticks a persona has to wait between two transactions of that type, and an energy cost that
+// is paid from an energy pool which regenerates over time.
+//
+// Limits are enforced by tx middleware before any system runs, so systems no longer need their own timestamp checks.
+// Rejected transactions get a RetryAfterError in their receipt that tells the client when to try again:
+//
+//	err = world.UseModule(cooldown.NewModule(
+//		cooldown.WithEnergy(10, 5), // 10 energy, 1 energy regenerated every 5 ticks
+//		cooldown.WithCooldown("game.attack", 3),
+//		cooldown.WithEnergyCost("game.attack", 2),
+//	))
+//
+// The module checks that the limited messages exist, so it must be used after they are registered.
+package cooldown
+
+import (
+	"fmt"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+)
+
+const (
+	ModuleName    = "cooldown"
+	ModuleVersion = "v1.0.0"
+)
+
+var _ cardinal.Module = &Module{}
+
+// RetryAfterError is the error recorded in the receipt of a transaction that was rejected by the module.
+type RetryAfterError struct {
+	Message string
+	Reason  string
+	// Tick is the first tick in which the transaction would be accepted, assuming the persona sends nothing else.
+	// Zero means the transaction will never be accepted.
+	Tick uint64
+}
+
+func (e *RetryAfterError) Error() string {
+	if e.Tick == 0 {
+		return fmt.Sprintf("message %q rejected: %s", e.Message, e.Reason)
+	}
+	return fmt.Sprintf("message %q rejected: %s, retry after tick %d", e.Message, e.Reason, e.Tick)
+}
+
+// Limits is the cooldown and energy state of a persona.
+type Limits struct {
+	PersonaTag string `json:"personaTag"`
+	Energy     int    `json:"energy"`
+	// EnergyUpdatedAt is the tick at which Energy was last regenerated.
+	EnergyUpdatedAt uint64 `json:"energyUpdatedAt"`
+	// ReadyAt maps the full names of messages that are on cooldown to the first tick they can be sent again.
+	ReadyAt map[string]uint64 `json:"readyAt"`
+}
+
+func (Limits) Name() string { return "cooldown-limits" }
+
+type rule struct {
+	cooldown uint64
+	cost     int
+}
+
+type Option func(*Module)
+
+// WithCooldown makes personas wait the given number of ticks between two transactions of the message. msgName is
+// the full name of the message, e.g. "game.attack".
+func WithCooldown(msgName string, ticks uint64) Option {
+	return func(m *Module) {
+		r := m.rules[msgName]
+		r.cooldown = ticks
+		m.rules[msgName] = r
+	}
+}
+
+// WithEnergyCost makes transactions of the message cost energy. msgName is the full name of the message.
+func WithEnergyCost(msgName string, cost int) Option {
+	return func(m *Module) {
+		r := m.rules[msgName]
+		r.cost = cost
+		m.rules[msgName] = r
+	}
+}
+
+// WithEnergy sets the size of every persona's energy pool, and the number of ticks it takes to regenerate one point
+// of energy. Personas start with a full pool. A regenTicks of zero disables regeneration.
+func WithEnergy(maxEnergy int, regenTicks uint64) Option {
+	return func(m *Module) {
+		m.maxEnergy = maxEnergy
+		m.regenTicks = regenTicks
+	}
+}
+
+type Module struct {
+	cardinal.ModuleBase
+	rules      map[string]rule
+	maxEnergy  int
+	regenTicks uint64
+}
+
+func NewModule(opts ...Option) *Module {
+	m := &Module{rules: map[string]rule{}}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func (*Module) Name() string    { return ModuleName }
+func (*Module) Version() string { return ModuleVersion }
+
+func (*Module) RegisterComponents(w *cardinal.World) error {
+	return cardinal.RegisterComponent[Limits](w)
+}
+
+// Init validates the rules and registers the tx middleware that enforces them.
+func (m *Module) Init(w *cardinal.World) error {
+	for msgName, r := range m.rules {
+		if _, ok := w.GetMessageByFullName(msgName); !ok {
+			return eris.Errorf("cannot limit message %q: it is not registered", msgName)
+		}
+		if r.cost < 0 {
+			return eris.Errorf("energy cost of message %q must not be negative", msgName)
+		}
+		if r.cost > m.maxEnergy {
+			return eris.Errorf(
+				"energy cost %d of message %q is more than the maximum energy %d", r.cost, msgName, m.maxEnergy,
+			)
+		}
+	}
+	return cardinal.RegisterTxMiddleware(w, m.enforce)
+}
+
+// enforce rejects transactions that are on cooldown or that the sender does not have the energy for. Accepted
+// transactions start their cooldown and are charged right away, even if a system later fails to process them.
+func (m *Module) enforce(wCtx engine.Context, msg types.Message, tx txpool.TxData) error {
+	r, ok := m.rules[msg.FullName()]
+	if !ok || tx.Tx == nil || tx.Tx.PersonaTag == "" {
+		return nil
+	}
+	tick := wCtx.CurrentTick()
+	id, limits, found, err := m.limitsOf(wCtx, tx.Tx.PersonaTag)
+	if err != nil {
+		return err
+	}
+	m.regenerate(limits, tick)
+
+	if readyAt := limits.ReadyAt[msg.FullName()]; tick < readyAt {
+		return &RetryAfterError{Message: msg.FullName(), Reason: "on cooldown", Tick: readyAt}
+	}
+	if limits.Energy < r.cost {
+		retry := &RetryAfterError{Message: msg.FullName(), Reason: "not enough energy"}
+		if m.regenTicks > 0 {
+			retry.Tick = limits.EnergyUpdatedAt + uint64(r.cost-limits.Energy)*m.regenTicks
+		}
+		return retry
+	}
+
+	limits.Energy -= r.cost
+	if r.cooldown > 0 {
+		limits.ReadyAt[msg.FullName()] = tick + r.cooldown
+	}
+	// Forget cooldowns that are over, so the component doesn't grow with every message type a persona has sent.
+	for name, readyAt := range limits.ReadyAt {
+		if readyAt <= tick {
+			delete(limits.ReadyAt, name)
+		}
+	}
+	if !found {
+		_, err = cardinal.Create(wCtx, *limits)
+		return err
+	}
+	return cardinal.SetComponent[Limits](wCtx, id, limits)
+}
+
+// limitsOf returns the persona's limits. Personas that have not sent a limited transaction yet get a new Limits
+// with a full energy pool, which is not found in the world.
+func (m *Module) limitsOf(wCtx engine.Context, personaTag string) (types.EntityID, *Limits, bool, error) {
+	ids, err := cardinal.NewSearch().
+		Entity(filter.Contains(filter.Component[Limits]())).
+		Where(cardinal.FilterFunction[Limits](func(l Limits) bool { return l.PersonaTag == personaTag })).
+		Collect(wCtx)
+	if err != nil {
+		return 0, nil, false, err
+	}
+	if len(ids) == 0 {
+		return 0, &Limits{
+			PersonaTag:      personaTag,
+			Energy:          m.maxEnergy,
+			EnergyUpdatedAt: wCtx.CurrentTick(),
+			ReadyAt:         map[string]uint64{},
+		}, false, nil
+	}
+	limits, err := cardinal.GetComponent[Limits](wCtx, ids[0])
+	if err != nil {
+		return 0, nil, false, err
+	}
+	if limits.ReadyAt == nil {
+		limits.ReadyAt = map[string]uint64{}
+	}
+	return ids[0], limits, true, nil
+}
+
+// regenerate adds the energy regenerated since the last update. Partial progress towards the next point of energy is
+// kept.
+func (m *Module) regenerate(limits *Limits, tick uint64) {
+	if m.regenTicks == 0 || tick <= limits.EnergyUpdatedAt {
+		return
+	}
+	gained := (tick - limits.EnergyUpdatedAt) / m.regenTicks
+	limits.Energy += int(gained)
+	limits.EnergyUpdatedAt += gained * m.regenTicks
+	if limits.Energy >= m.maxEnergy {
+		limits.Energy = m.maxEnergy
+		limits.EnergyUpdatedAt = tick
+	}
+}
@@ -0,0 +1,96 @@
+package cooldown_test
+
+import (
+	"testing"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/cooldown"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type AttackMsg struct{}
+
+type AttackResult struct{}
+
+func TestCooldownsAndEnergy(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterMessage[AttackMsg, AttackResult](world, "attack"))
+	attacks := map[string]int{}
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		return cardinal.EachMessage[AttackMsg, AttackResult](wCtx,
+			func(tx message.TxData[AttackMsg]) (AttackResult, error) {
+				attacks[tx.Tx.PersonaTag]++
+				return AttackResult{}, nil
+			})
+	}))
+	assert.NilError(t, world.UseModule(cooldown.NewModule(
+		cooldown.WithEnergy(4, 5),
+		cooldown.WithCooldown("game.attack", 3),
+		cooldown.WithEnergyCost("game.attack", 2),
+	)))
+
+	attackMsg, ok := world.GetMessageByFullName("game.attack")
+	assert.Check(t, ok)
+	attack := func(personaTag string) types.TxHash {
+		return tf.AddTransaction(attackMsg.ID(), AttackMsg{}, testutils.UniqueSignatureWithName(personaTag))
+	}
+	errorAt := func(hash types.TxHash) error {
+		_, errs, ok := cardinal.NewReadOnlyWorldContext(world).GetTransactionReceipt(hash)
+		assert.Check(t, ok)
+		if len(errs) == 0 {
+			return nil
+		}
+		return errs[0]
+	}
+	tickUntil := func(tick uint64) {
+		for world.CurrentTick() < tick {
+			tf.DoTick()
+		}
+	}
+
+	tickUntil(1)
+	first := attack("alice")
+	second := attack("alice")
+	bob := attack("bob")
+	tf.DoTick()
+	assert.NilError(t, errorAt(first))
+	assert.NilError(t, errorAt(bob))
+	err := errorAt(second)
+	assert.ErrorContains(t, err, `message "game.attack" rejected: on cooldown, retry after tick 4`)
+	var retry *cooldown.RetryAfterError
+	assert.Check(t, eris.As(err, &retry))
+	assert.Equal(t, retry.Tick, uint64(4))
+	assert.Equal(t, attacks["alice"], 1, "rejected transactions must not reach systems")
+
+	// At tick 4 the cooldown is over, and alice has exactly enough energy left.
+	tickUntil(4)
+	hash := attack("alice")
+	tf.DoTick()
+	assert.NilError(t, errorAt(hash))
+
+	// At tick 7 alice has regenerated only 1 energy, and gets the second point at tick 11.
+	tickUntil(7)
+	hash = attack("alice")
+	tf.DoTick()
+	assert.ErrorContains(t, errorAt(hash), "not enough energy, retry after tick 11")
+
+	tickUntil(11)
+	hash = attack("alice")
+	tf.DoTick()
+	assert.NilError(t, errorAt(hash))
+	assert.Equal(t, attacks["alice"], 3)
+	assert.Equal(t, attacks["bob"], 1)
+}
+
+func TestCooldownRequiresRegisteredMessages(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	err := tf.World.UseModule(cooldown.NewModule(cooldown.WithCooldown("game.missing", 1)))
+	assert.ErrorContains(t, err, `cannot limit message "game.missing": it is not registered`)
+}
@@ -0,0 +1,52 @@
+package crafting
+
+// Inventory holds the items of a persona. Crafting consumes its inputs from, and adds its outputs to, the crafter's
+// inventory.
+type Inventory struct {
+	Owner string         `json:"owner"`
+	Items map[string]int `json:"items"`
+}
+
+func (Inventory) Name() string { return "crafting-inventory" }
+
+// has reports whether the inventory holds all of the stacks.
+func (inv Inventory) has(stacks []Stack) bool {
+	needed := map[string]int{}
+	for _, stack := range stacks {
+		needed[stack.Item] += stack.Count
+	}
+	for item, count := range needed {
+		if inv.Items[item] < count {
+			return false
+		}
+	}
+	return true
+}
+
+func (inv *Inventory) add(stacks []Stack) {
+	if inv.Items == nil {
+		inv.Items = map[string]int{}
+	}
+	for _, stack := range stacks {
+		inv.Items[stack.Item] += stack.Count
+	}
+}
+
+func (inv *Inventory) remove(stacks []Stack) {
+	for _, stack := range stacks {
+		inv.Items[stack.Item] -= stack.Count
+		if inv.Items[stack.Item] == 0 {
+			delete(inv.Items, stack.Item)
+		}
+	}
+}
+
+// Job is a craft in progress. Its inputs have already been consumed; its outputs are added to the owner's inventory
+// at the end of the CompletesAt tick.
+type Job struct {
+	Owner       string `json:"owner"`
+	Recipe      string `json:"recipe"`
+	CompletesAt uint64 `json:"completesAt"`
+}
+
+func (Job) Name() string { return "crafting-job" }
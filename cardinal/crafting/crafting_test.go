@@ -0,0 +1,120 @@
+package crafting_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/crafting"
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func TestParseRecipes(t *testing.T) {
+	recipes, err := crafting.LoadRecipes("testdata/recipes.json")
+	assert.NilError(t, err)
+	assert.Equal(t, len(recipes), 2)
+
+	_, err = crafting.ParseRecipes([]byte(`[{"name": "nothing"}]`))
+	assert.ErrorContains(t, err, "has no outputs")
+	_, err = crafting.ParseRecipes([]byte(`[{"name": "x", "outputs": [{"item": "y", "count": 0}]}]`))
+	assert.ErrorContains(t, err, "invalid stack")
+	_, err = crafting.ParseRecipes([]byte(`[
+		{"name": "x", "outputs": [{"item": "y", "count": 1}]},
+		{"name": "x", "outputs": [{"item": "z", "count": 1}]}
+	]`))
+	assert.ErrorContains(t, err, "defined more than once")
+}
+
+func setupCrafting(t *testing.T) *testutils.TestFixture {
+	tf := testutils.NewTestFixture(t, nil)
+	recipes, err := crafting.LoadRecipes("testdata/recipes.json")
+	assert.NilError(t, err)
+	module, err := crafting.NewModule(recipes...)
+	assert.NilError(t, err)
+	assert.NilError(t, tf.World.UseModule(module))
+	assert.NilError(t, cardinal.RegisterInitSystems(tf.World, func(wCtx engine.Context) error {
+		return crafting.AddItems(wCtx, "alice",
+			crafting.Stack{Item: "iron", Count: 3},
+			crafting.Stack{Item: "wood", Count: 2},
+		)
+	}))
+	tf.DoTick()
+	return tf
+}
+
+func craft(tf *testutils.TestFixture, personaTag, recipe string) types.TxHash {
+	craftMsg, ok := tf.World.GetMessageByFullName(crafting.ModuleName + "." + crafting.CraftMessageName)
+	assert.Check(tf, ok)
+	sig := testutils.UniqueSignatureWithName(personaTag)
+	return tf.AddTransaction(craftMsg.ID(), crafting.CraftMsg{Recipe: recipe}, sig)
+}
+
+func inventory(tf *testutils.TestFixture, personaTag string) crafting.InventoryReply {
+	res := tf.Post("query/crafting/inventory", crafting.InventoryRequest{PersonaTag: personaTag})
+	assert.Equal(tf, res.StatusCode, http.StatusOK)
+	var reply crafting.InventoryReply
+	assert.NilError(tf, json.NewDecoder(res.Body).Decode(&reply))
+	return reply
+}
+
+func TestCraftConsumesInputsAndCompletesLater(t *testing.T) {
+	tf := setupCrafting(t)
+
+	hash := craft(tf, "alice", "iron-sword")
+	tf.DoTick()
+	receipt, errs, ok := cardinal.NewReadOnlyWorldContext(tf.World).GetTransactionReceipt(hash)
+	assert.Check(t, ok)
+	assert.Equal(t, len(errs), 0)
+	result, ok := receipt.(crafting.CraftResult)
+	assert.Check(t, ok)
+
+	// The inputs are consumed right away, and the craft is in progress.
+	inv := inventory(tf, "alice")
+	assert.DeepEqual(t, inv.Items, map[string]int{"wood": 1})
+	assert.Equal(t, len(inv.Jobs), 1)
+	assert.Equal(t, inv.Jobs[0].CompletesAt, result.CompletesAt)
+
+	for tf.World.CurrentTick() <= result.CompletesAt {
+		tf.DoTick()
+	}
+	inv = inventory(tf, "alice")
+	assert.DeepEqual(t, inv.Items, map[string]int{"wood": 1, "iron-sword": 1})
+	assert.Equal(t, len(inv.Jobs), 0)
+
+	res := tf.Post("query/events/list", handler.ListEventsRequest{Type: crafting.EventCompleted, Recipient: "alice"})
+	var events handler.ListEventsResponse
+	assert.NilError(t, json.NewDecoder(res.Body).Decode(&events))
+	assert.Equal(t, len(events.Events), 1)
+}
+
+func TestCraftValidatesIngredients(t *testing.T) {
+	tf := setupCrafting(t)
+
+	// alice has enough wood for two planks.
+	plankHash := craft(tf, "alice", "plank")
+	secondPlankHash := craft(tf, "alice", "plank")
+	thirdPlankHash := craft(tf, "alice", "plank")
+	unknownHash := craft(tf, "alice", "dragon")
+	bobHash := craft(tf, "bob", "plank")
+	tf.DoTick()
+
+	wCtx := cardinal.NewReadOnlyWorldContext(tf.World)
+	for _, hash := range []types.TxHash{plankHash, secondPlankHash} {
+		_, errs, _ := wCtx.GetTransactionReceipt(hash)
+		assert.Equal(t, len(errs), 0)
+	}
+	_, errs, _ := wCtx.GetTransactionReceipt(thirdPlankHash)
+	assert.ErrorContains(t, errs[0], `does not have the ingredients for "plank"`)
+	_, errs, _ = wCtx.GetTransactionReceipt(unknownHash)
+	assert.ErrorContains(t, errs[0], `recipe "dragon" does not exist`)
+	_, errs, _ = wCtx.GetTransactionReceipt(bobHash)
+	assert.ErrorContains(t, errs[0], `persona "bob" does not have the ingredients`)
+
+	// Planks take no time to craft, so they are in the inventory already.
+	assert.DeepEqual(t, inventory(tf, "alice").Items, map[string]int{"iron": 3, "plank": 8})
+}
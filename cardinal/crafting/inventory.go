@@ -0,0 +1,81 @@
+package crafting
+
+import (
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// findInventory returns the persona's inventory entity, or false if the persona does not have an inventory yet.
+func findInventory(wCtx engine.Context, personaTag string) (types.EntityID, *Inventory, bool, error) {
+	ids, err := cardinal.NewSearch().
+		Entity(filter.Contains(filter.Component[Inventory]())).
+		Where(cardinal.FilterFunction[Inventory](func(inv Inventory) bool { return inv.Owner == personaTag })).
+		Collect(wCtx)
+	if err != nil || len(ids) == 0 {
+		return 0, nil, false, err
+	}
+	inv, err := cardinal.GetComponent[Inventory](wCtx, ids[0])
+	if err != nil {
+		return 0, nil, false, err
+	}
+	return ids[0], inv, true, nil
+}
+
+// Items returns the items in the persona's inventory.
+func Items(wCtx engine.Context, personaTag string) (map[string]int, error) {
+	_, inv, ok, err := findInventory(wCtx, personaTag)
+	if err != nil || !ok {
+		return map[string]int{}, err
+	}
+	if inv.Items == nil {
+		return map[string]int{}, nil
+	}
+	return inv.Items, nil
+}
+
+// AddItems adds items to the persona's inventory, creating the inventory if the persona does not have one. Games
+// use it to hand out crafting ingredients, e.g. from loot drops.
+func AddItems(wCtx engine.Context, personaTag string, items ...Stack) error {
+	id, inv, ok, err := findInventory(wCtx, personaTag)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		inv := Inventory{Owner: personaTag}
+		inv.add(items)
+		_, err := cardinal.Create(wCtx, inv)
+		return err
+	}
+	inv.add(items)
+	return cardinal.SetComponent[Inventory](wCtx, id, inv)
+}
+
+// RemoveItems removes items from the persona's inventory. Nothing is removed if the inventory does not hold all of
+// them.
+func RemoveItems(wCtx engine.Context, personaTag string, items ...Stack) error {
+	if len(items) == 0 {
+		return nil
+	}
+	id, inv, ok, err := findInventory(wCtx, personaTag)
+	if err != nil {
+		return err
+	}
+	if !ok || !inv.has(items) {
+		return eris.Errorf("persona %q does not have the items", personaTag)
+	}
+	inv.remove(items)
+	return cardinal.SetComponent[Inventory](wCtx, id, inv)
+}
+
+// HasItems reports whether the persona's inventory holds all of the items.
+func HasItems(wCtx engine.Context, personaTag string, items ...Stack) (bool, error) {
+	_, inv, ok, err := findInventory(wCtx, personaTag)
+	if err != nil || !ok {
+		return len(items) == 0, err
+	}
+	return inv.has(items), nil
+}
@@ -0,0 +1,182 @@
+// Package crafting is a module for crafting items from recipes. A CraftMsg consumes the recipe's inputs from the
+// sender's inventory right away, and the outputs are added to the inventory once the recipe's craft time has passed.
+// Crafters are notified of completed crafts with an EventCompleted event that only they receive.
+//
+// Load the recipes from a data file and add the module to the world:
+//
+//	recipes, err := crafting.LoadRecipes("recipes.json")
+//	...
+//	craftingModule, err := crafting.NewModule(recipes...)
+//	...
+//	err = world.UseModule(craftingModule)
+package crafting
+
+import (
+	"sort"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+const (
+	ModuleName    = "crafting"
+	ModuleVersion = "v1.0.0"
+
+	CraftMessageName = "craft"
+
+	// EventCompleted is emitted to the crafter when a craft completes.
+	EventCompleted = "crafting-completed"
+)
+
+var _ cardinal.Module = &Module{}
+
+// CraftMsg crafts a recipe with the items in the sender's inventory.
+type CraftMsg struct {
+	Recipe string `json:"recipe"`
+}
+
+type CraftResult struct {
+	Job types.EntityID `json:"job"`
+	// CompletesAt is the tick at the end of which the outputs are added to the sender's inventory.
+	CompletesAt uint64 `json:"completesAt"`
+}
+
+type Module struct {
+	cardinal.ModuleBase
+	recipes map[string]Recipe
+}
+
+func NewModule(recipes ...Recipe) (*Module, error) {
+	m := &Module{recipes: map[string]Recipe{}}
+	for _, recipe := range recipes {
+		if err := recipe.Validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := m.recipes[recipe.Name]; ok {
+			return nil, eris.Errorf("recipe %q is defined more than once", recipe.Name)
+		}
+		m.recipes[recipe.Name] = recipe
+	}
+	return m, nil
+}
+
+func (*Module) Name() string    { return ModuleName }
+func (*Module) Version() string { return ModuleVersion }
+
+func (*Module) RegisterComponents(w *cardinal.World) error {
+	if err := cardinal.RegisterComponent[Inventory](w); err != nil {
+		return err
+	}
+	return cardinal.RegisterComponent[Job](w)
+}
+
+func (*Module) RegisterTxs(w *cardinal.World) error {
+	return cardinal.RegisterMessage[CraftMsg, CraftResult](w, CraftMessageName)
+}
+
+func (m *Module) RegisterSystems(w *cardinal.World) error {
+	return cardinal.RegisterSystems(w, m.craftingSystem)
+}
+
+// Recipes returns the module's recipes, sorted by name.
+func (m *Module) Recipes() []Recipe {
+	recipes := make([]Recipe, 0, len(m.recipes))
+	for _, recipe := range m.recipes {
+		recipes = append(recipes, recipe)
+	}
+	sort.Slice(recipes, func(i, j int) bool { return recipes[i].Name < recipes[j].Name })
+	return recipes
+}
+
+// craftingSystem starts this tick's crafts, then completes every craft that is due.
+func (m *Module) craftingSystem(wCtx engine.Context) error {
+	err := cardinal.EachMessage[CraftMsg, CraftResult](wCtx, func(tx message.TxData[CraftMsg]) (CraftResult, error) {
+		return m.craft(wCtx, tx)
+	})
+	if err != nil {
+		return err
+	}
+	return m.completeJobs(wCtx)
+}
+
+func (m *Module) craft(wCtx engine.Context, tx message.TxData[CraftMsg]) (CraftResult, error) {
+	if tx.Tx == nil || tx.Tx.PersonaTag == "" {
+		return CraftResult{}, eris.New("craft messages must be signed by a persona")
+	}
+	crafter := tx.Tx.PersonaTag
+	recipe, ok := m.recipes[tx.Msg.Recipe]
+	if !ok {
+		return CraftResult{}, eris.Errorf("recipe %q does not exist", tx.Msg.Recipe)
+	}
+
+	invID, inv, ok, err := findInventory(wCtx, crafter)
+	if err != nil {
+		return CraftResult{}, err
+	}
+	if !ok || !inv.has(recipe.Inputs) {
+		return CraftResult{}, eris.Errorf("persona %q does not have the ingredients for %q", crafter, recipe.Name)
+	}
+	if len(recipe.Inputs) > 0 {
+		inv.remove(recipe.Inputs)
+		if err := cardinal.SetComponent[Inventory](wCtx, invID, inv); err != nil {
+			return CraftResult{}, err
+		}
+	}
+
+	job := Job{Owner: crafter, Recipe: recipe.Name, CompletesAt: wCtx.CurrentTick() + recipe.Ticks}
+	jobID, err := cardinal.Create(wCtx, job)
+	if err != nil {
+		return CraftResult{}, err
+	}
+	return CraftResult{Job: jobID, CompletesAt: job.CompletesAt}, nil
+}
+
+// completeJobs adds the outputs of every craft that completes this tick to its owner's inventory.
+func (m *Module) completeJobs(wCtx engine.Context) error {
+	tick := wCtx.CurrentTick()
+	ids, err := cardinal.NewSearch().
+		Entity(filter.Contains(filter.Component[Job]())).
+		Where(cardinal.FilterFunction[Job](func(job Job) bool { return job.CompletesAt <= tick })).
+		Collect(wCtx)
+	if err != nil {
+		return err
+	}
+	// Complete jobs in the order they were started, so that inventories change in the same order on every replica.
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		job, err := cardinal.GetComponent[Job](wCtx, id)
+		if err != nil {
+			return err
+		}
+		recipe, ok := m.recipes[job.Recipe]
+		if !ok {
+			// The recipe was removed from the data file since the craft started. Its inputs were consumed, so the
+			// job is logged and dropped rather than retried forever.
+			wCtx.Logger().Warn().Msgf("dropping crafting job %d for unknown recipe %q", id, job.Recipe)
+		} else if err := AddItems(wCtx, job.Owner, recipe.Outputs...); err != nil {
+			return err
+		}
+		if err := cardinal.Remove(wCtx, id); err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		err = cardinal.EmitEventTo(wCtx, []string{job.Owner}, map[string]any{
+			"type":    EventCompleted,
+			"job":     id,
+			"recipe":  recipe.Name,
+			"outputs": recipe.Outputs,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
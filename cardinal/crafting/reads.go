@@ -0,0 +1,69 @@
+package crafting
+
+import (
+	"sort"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type RecipesRequest struct{}
+
+type RecipesReply struct {
+	Recipes []Recipe `json:"recipes"`
+}
+
+type InventoryRequest struct {
+	PersonaTag string `json:"personaTag"`
+}
+
+type JobInfo struct {
+	Job         types.EntityID `json:"job"`
+	Recipe      string         `json:"recipe"`
+	CompletesAt uint64         `json:"completesAt"`
+}
+
+type InventoryReply struct {
+	Items map[string]int `json:"items"`
+	// Jobs lists the persona's crafts that are in progress, in the order they were started.
+	Jobs []JobInfo `json:"jobs"`
+}
+
+// RegisterReads registers the /query/crafting/recipes and /query/crafting/inventory queries.
+func (m *Module) RegisterReads(w *cardinal.World) error {
+	return cardinal.RegisterReads(w, ModuleName,
+		cardinal.NewRead[RecipesRequest, RecipesReply]("recipes", func(engine.Context, *RecipesRequest) (
+			*RecipesReply, error,
+		) {
+			return &RecipesReply{Recipes: m.Recipes()}, nil
+		}),
+		cardinal.NewRead[InventoryRequest, InventoryReply]("inventory", queryInventory),
+	)
+}
+
+func queryInventory(wCtx engine.Context, req *InventoryRequest) (*InventoryReply, error) {
+	items, err := Items(wCtx, req.PersonaTag)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := cardinal.NewSearch().
+		Entity(filter.Contains(filter.Component[Job]())).
+		Where(cardinal.FilterFunction[Job](func(job Job) bool { return job.Owner == req.PersonaTag })).
+		Collect(wCtx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	reply := &InventoryReply{Items: items, Jobs: make([]JobInfo, 0, len(ids))}
+	for _, id := range ids {
+		job, err := cardinal.GetComponent[Job](wCtx, id)
+		if err != nil {
+			return nil, err
+		}
+		reply.Jobs = append(reply.Jobs, JobInfo{Job: id, Recipe: job.Recipe, CompletesAt: job.CompletesAt})
+	}
+	return reply, nil
+}
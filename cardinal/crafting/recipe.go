@@ -0,0 +1,85 @@
+package crafting
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/rotisserie/eris"
+)
+
+// Recipe turns a set of input items into a set of output items after a number of ticks.
+//
+// Recipes are usually defined in a JSON data file and loaded with LoadRecipes:
+//
+//	[
+//	  {
+//	    "name": "iron-sword",
+//	    "inputs": [{"item": "iron", "count": 3}, {"item": "wood", "count": 1}],
+//	    "outputs": [{"item": "iron-sword", "count": 1}],
+//	    "ticks": 10
+//	  }
+//	]
+type Recipe struct {
+	Name    string  `json:"name"`
+	Inputs  []Stack `json:"inputs"`
+	Outputs []Stack `json:"outputs"`
+	// Ticks is the number of ticks it takes to craft the recipe. Recipes with zero ticks complete in the same tick
+	// they are crafted.
+	Ticks uint64 `json:"ticks"`
+}
+
+// Stack is a number of items of the same kind.
+type Stack struct {
+	Item  string `json:"item"`
+	Count int    `json:"count"`
+}
+
+// Validate checks that the recipe can be crafted.
+func (r Recipe) Validate() error {
+	if r.Name == "" {
+		return eris.New("recipe name must not be empty")
+	}
+	if len(r.Outputs) == 0 {
+		return eris.Errorf("recipe %q has no outputs", r.Name)
+	}
+	for _, stacks := range [][]Stack{r.Inputs, r.Outputs} {
+		for _, stack := range stacks {
+			if stack.Item == "" || stack.Count <= 0 {
+				return eris.Errorf("recipe %q has an invalid stack: %d of %q", r.Name, stack.Count, stack.Item)
+			}
+		}
+	}
+	return nil
+}
+
+// ParseRecipes decodes and validates a JSON array of recipes.
+func ParseRecipes(data []byte) ([]Recipe, error) {
+	var recipes []Recipe
+	if err := json.Unmarshal(data, &recipes); err != nil {
+		return nil, eris.Wrap(err, "failed to decode recipes")
+	}
+	seen := map[string]bool{}
+	for _, recipe := range recipes {
+		if err := recipe.Validate(); err != nil {
+			return nil, err
+		}
+		if seen[recipe.Name] {
+			return nil, eris.Errorf("recipe %q is defined more than once", recipe.Name)
+		}
+		seen[recipe.Name] = true
+	}
+	return recipes, nil
+}
+
+// LoadRecipes reads the recipes from a JSON data file. See Recipe for the format.
+func LoadRecipes(path string) ([]Recipe, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to read recipes from %q", path)
+	}
+	recipes, err := ParseRecipes(data)
+	if err != nil {
+		return nil, eris.Wrapf(err, "invalid recipes in %q", path)
+	}
+	return recipes, nil
+}
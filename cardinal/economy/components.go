@@ -0,0 +1,20 @@
+package economy
+
+// Wallet holds the balances of a persona, by currency.
+type Wallet struct {
+	Owner    string            `json:"owner"`
+	Balances map[string]uint64 `json:"balances"`
+}
+
+func (Wallet) Name() string { return "economy-wallet" }
+
+// Supply is the total amount of a currency in all wallets. It is changed in the same tick as the wallets by minting
+// and burning, and never by transfers.
+type Supply struct {
+	Currency string `json:"currency"`
+	Total    uint64 `json:"total"`
+	// Max is the most that can be minted in total, or zero if there is no limit.
+	Max uint64 `json:"max"`
+}
+
+func (Supply) Name() string { return "economy-supply" }
@@ -0,0 +1,256 @@
+package economy
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+var (
+	ErrUnknownCurrency    = errors.New("unknown currency")
+	ErrInvalidAmount      = errors.New("amount must be positive")
+	ErrInsufficientFunds  = errors.New("insufficient funds")
+	ErrMaxSupplyExceeded  = errors.New("max supply exceeded")
+	ErrInvariantViolation = errors.New("economy invariant violated")
+)
+
+// findWallet returns the persona's wallet, or false if the persona does not have a wallet yet.
+func findWallet(wCtx engine.Context, personaTag string) (types.EntityID, *Wallet, bool, error) {
+	ids, err := cardinal.NewSearch().
+		Entity(filter.Contains(filter.Component[Wallet]())).
+		Where(cardinal.FilterFunction[Wallet](func(w Wallet) bool { return w.Owner == personaTag })).
+		Collect(wCtx)
+	if err != nil || len(ids) == 0 {
+		return 0, nil, false, err
+	}
+	wallet, err := cardinal.GetComponent[Wallet](wCtx, ids[0])
+	if err != nil {
+		return 0, nil, false, err
+	}
+	if wallet.Balances == nil {
+		wallet.Balances = map[string]uint64{}
+	}
+	return ids[0], wallet, true, nil
+}
+
+func findSupply(wCtx engine.Context, currency string) (types.EntityID, *Supply, error) {
+	ids, err := cardinal.NewSearch().
+		Entity(filter.Contains(filter.Component[Supply]())).
+		Where(cardinal.FilterFunction[Supply](func(s Supply) bool { return s.Currency == currency })).
+		Collect(wCtx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(ids) == 0 {
+		return 0, nil, eris.Wrapf(ErrUnknownCurrency, "currency %q", currency)
+	}
+	supply, err := cardinal.GetComponent[Supply](wCtx, ids[0])
+	if err != nil {
+		return 0, nil, err
+	}
+	return ids[0], supply, nil
+}
+
+// GetBalance returns the persona's balance of the currency.
+func GetBalance(wCtx engine.Context, personaTag, currency string) (uint64, error) {
+	if _, _, err := findSupply(wCtx, currency); err != nil {
+		return 0, err
+	}
+	_, wallet, ok, err := findWallet(wCtx, personaTag)
+	if err != nil || !ok {
+		return 0, err
+	}
+	return wallet.Balances[currency], nil
+}
+
+// GetSupply returns the total amount of the currency in all wallets.
+func GetSupply(wCtx engine.Context, currency string) (uint64, error) {
+	_, supply, err := findSupply(wCtx, currency)
+	if err != nil {
+		return 0, err
+	}
+	return supply.Total, nil
+}
+
+// credit adds the amount to the persona's wallet, creating the wallet if the persona does not have one.
+func credit(wCtx engine.Context, personaTag, currency string, amount uint64) error {
+	id, wallet, ok, err := findWallet(wCtx, personaTag)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		_, err := cardinal.Create(wCtx, Wallet{Owner: personaTag, Balances: map[string]uint64{currency: amount}})
+		return err
+	}
+	// Balances can't overflow, because no balance is larger than the total supply.
+	wallet.Balances[currency] += amount
+	return cardinal.SetComponent[Wallet](wCtx, id, wallet)
+}
+
+// debit removes the amount from the persona's wallet. Nothing is removed if the wallet holds less than the amount.
+func debit(wCtx engine.Context, personaTag, currency string, amount uint64) error {
+	id, wallet, ok, err := findWallet(wCtx, personaTag)
+	if err != nil {
+		return err
+	}
+	if !ok || wallet.Balances[currency] < amount {
+		return eris.Wrapf(ErrInsufficientFunds, "persona %q has less than %d %s", personaTag, amount, currency)
+	}
+	wallet.Balances[currency] -= amount
+	if wallet.Balances[currency] == 0 {
+		delete(wallet.Balances, currency)
+	}
+	return cardinal.SetComponent[Wallet](wCtx, id, wallet)
+}
+
+// Mint creates the amount of the currency in the persona's wallet, and adds it to the currency's supply.
+func Mint(wCtx engine.Context, personaTag, currency string, amount uint64) error {
+	if amount == 0 {
+		return eris.Wrap(ErrInvalidAmount, "")
+	}
+	supplyID, supply, err := findSupply(wCtx, currency)
+	if err != nil {
+		return err
+	}
+	if amount > math.MaxUint64-supply.Total || (supply.Max > 0 && supply.Total+amount > supply.Max) {
+		return eris.Wrapf(ErrMaxSupplyExceeded, "can't mint %d %s", amount, currency)
+	}
+	if err := credit(wCtx, personaTag, currency, amount); err != nil {
+		return err
+	}
+	supply.Total += amount
+	if err := cardinal.SetComponent[Supply](wCtx, supplyID, supply); err != nil {
+		return err
+	}
+	return cardinal.EmitEventTo(wCtx, []string{personaTag}, map[string]any{
+		"type": EventMinted, "to": personaTag, "currency": currency, "amount": amount,
+	})
+}
+
+// Burn destroys the amount of the currency in the persona's wallet, and removes it from the currency's supply.
+func Burn(wCtx engine.Context, personaTag, currency string, amount uint64) error {
+	if amount == 0 {
+		return eris.Wrap(ErrInvalidAmount, "")
+	}
+	supplyID, supply, err := findSupply(wCtx, currency)
+	if err != nil {
+		return err
+	}
+	if err := debit(wCtx, personaTag, currency, amount); err != nil {
+		return err
+	}
+	supply.Total -= amount
+	if err := cardinal.SetComponent[Supply](wCtx, supplyID, supply); err != nil {
+		return err
+	}
+	return cardinal.EmitEventTo(wCtx, []string{personaTag}, map[string]any{
+		"type": EventBurned, "from": personaTag, "currency": currency, "amount": amount,
+	})
+}
+
+// Transfer moves the amount of the currency from one persona's wallet to another's. The supply doesn't change.
+func Transfer(wCtx engine.Context, from, to, currency string, amount uint64) error {
+	if amount == 0 {
+		return eris.Wrap(ErrInvalidAmount, "")
+	}
+	if from == to {
+		return eris.New("can't transfer to the same persona")
+	}
+	if _, _, err := findSupply(wCtx, currency); err != nil {
+		return err
+	}
+	if err := debit(wCtx, from, currency, amount); err != nil {
+		return err
+	}
+	if err := credit(wCtx, to, currency, amount); err != nil {
+		return err
+	}
+	return cardinal.EmitEventTo(wCtx, []string{from, to}, map[string]any{
+		"type": EventTransferred, "from": from, "to": to, "currency": currency, "amount": amount,
+	})
+}
+
+// CheckInvariants checks that the wallets only hold known currencies, and that the balances of every currency add up
+// to its supply, and not more than its max supply. The module runs it after every tick is committed; games can also
+// run it in their tests.
+func CheckInvariants(wCtx engine.Context) error {
+	supplies := map[string]Supply{}
+	var readErr error
+	err := cardinal.NewSearch().Entity(filter.Contains(filter.Component[Supply]())).Each(wCtx,
+		func(id types.EntityID) bool {
+			supply, err := cardinal.GetComponent[Supply](wCtx, id)
+			if err != nil {
+				readErr = err
+				return false
+			}
+			supplies[supply.Currency] = *supply
+			return true
+		})
+	if err != nil {
+		return err
+	}
+	if readErr != nil {
+		return readErr
+	}
+
+	sums := map[string]uint64{}
+	var violations []string
+	err = cardinal.NewSearch().Entity(filter.Contains(filter.Component[Wallet]())).Each(wCtx,
+		func(id types.EntityID) bool {
+			wallet, err := cardinal.GetComponent[Wallet](wCtx, id)
+			if err != nil {
+				readErr = err
+				return false
+			}
+			for currency, balance := range wallet.Balances {
+				if _, ok := supplies[currency]; !ok {
+					violations = append(violations,
+						fmt.Sprintf("the wallet of %q holds unknown currency %s", wallet.Owner, currency))
+					continue
+				}
+				if balance > math.MaxUint64-sums[currency] {
+					violations = append(violations, fmt.Sprintf("the balances of %s overflow", currency))
+					continue
+				}
+				sums[currency] += balance
+			}
+			return true
+		})
+	if err != nil {
+		return err
+	}
+	if readErr != nil {
+		return readErr
+	}
+
+	currencies := make([]string, 0, len(supplies))
+	for currency := range supplies {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	for _, currency := range currencies {
+		supply := supplies[currency]
+		if sums[currency] != supply.Total {
+			violations = append(violations, fmt.Sprintf(
+				"the balances of %s add up to %d, but its supply is %d", currency, sums[currency], supply.Total))
+		}
+		if supply.Max > 0 && supply.Total > supply.Max {
+			violations = append(violations, fmt.Sprintf(
+				"the supply of %s is %d, more than its max supply %d", currency, supply.Total, supply.Max))
+		}
+	}
+	if len(violations) > 0 {
+		sort.Strings(violations)
+		return eris.Wrap(ErrInvariantViolation, strings.Join(violations, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package economy_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/economy"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type economyFixture struct {
+	*testutils.TestFixture
+}
+
+func newEconomyFixture(t *testing.T) economyFixture {
+	tf := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, tf.World.UseModule(economy.NewModule(
+		[]economy.Currency{{Name: "gold"}, {Name: "gems", MaxSupply: 100}},
+		economy.WithMinters("treasury"),
+	)))
+	return economyFixture{tf}
+}
+
+func (tf economyFixture) send(personaTag, name string, msg any) types.TxHash {
+	msgType, ok := tf.World.GetMessageByFullName(economy.ModuleName + "." + name)
+	assert.Check(tf, ok, "message %q is not registered", name)
+	return tf.AddTransaction(msgType.ID(), msg, testutils.UniqueSignatureWithName(personaTag))
+}
+
+func (tf economyFixture) result(hash types.TxHash) (economy.Result, []error) {
+	receipt, errs, ok := cardinal.NewReadOnlyWorldContext(tf.World).GetTransactionReceipt(hash)
+	assert.Check(tf, ok)
+	result, _ := receipt.(economy.Result)
+	return result, errs
+}
+
+func (tf economyFixture) supplies() []economy.Supply {
+	res := tf.Post("query/economy/supplies", economy.SuppliesRequest{})
+	assert.Equal(tf, res.StatusCode, http.StatusOK)
+	var reply economy.SuppliesReply
+	assert.NilError(tf, json.NewDecoder(res.Body).Decode(&reply))
+	return reply.Supplies
+}
+
+func TestMintTransferAndBurn(t *testing.T) {
+	tf := newEconomyFixture(t)
+	tf.DoTick()
+
+	mint := tf.send("treasury", economy.MintMessageName, economy.MintMsg{To: "alice", Currency: "gold", Amount: 100})
+	forged := tf.send("alice", economy.MintMessageName, economy.MintMsg{To: "alice", Currency: "gold", Amount: 100})
+	tf.DoTick()
+	result, errs := tf.result(mint)
+	assert.Equal(t, len(errs), 0)
+	assert.Equal(t, result.Balance, uint64(100))
+	_, errs = tf.result(forged)
+	assert.Equal(t, len(errs), 1)
+	assert.ErrorContains(t, errs[0], "may not mint")
+
+	transfer := tf.send("alice", economy.TransferMessageName, economy.TransferMsg{To: "bob", Currency: "gold", Amount: 30})
+	overdraft := tf.send("bob", economy.TransferMessageName, economy.TransferMsg{To: "alice", Currency: "gold",
+		Amount: 31})
+	tf.DoTick()
+	result, errs = tf.result(transfer)
+	assert.Equal(t, len(errs), 0)
+	assert.Equal(t, result.Balance, uint64(70))
+	_, errs = tf.result(overdraft)
+	assert.Equal(t, len(errs), 1)
+	assert.ErrorIs(t, errs[0], economy.ErrInsufficientFunds)
+
+	burn := tf.send("bob", economy.BurnMessageName, economy.BurnMsg{Currency: "gold", Amount: 10})
+	tooMany := tf.send("treasury", economy.MintMessageName, economy.MintMsg{To: "bob", Currency: "gems", Amount: 101})
+	tf.DoTick()
+	result, errs = tf.result(burn)
+	assert.Equal(t, len(errs), 0)
+	assert.Equal(t, result.Balance, uint64(20))
+	_, errs = tf.result(tooMany)
+	assert.Equal(t, len(errs), 1)
+	assert.ErrorIs(t, errs[0], economy.ErrMaxSupplyExceeded)
+
+	assert.DeepEqual(t, tf.supplies(), []economy.Supply{
+		{Currency: "gems", Total: 0, Max: 100},
+		{Currency: "gold", Total: 90},
+	})
+	assert.NilError(t, economy.CheckInvariants(cardinal.NewReadOnlyWorldContext(tf.World)))
+}
+
+func TestCheckInvariantsFindsMissingCurrency(t *testing.T) {
+	tf := newEconomyFixture(t)
+	assert.NilError(t, cardinal.RegisterSystems(tf.World, func(wCtx engine.Context) error {
+		switch wCtx.CurrentTick() {
+		case 0:
+			return economy.Mint(wCtx, "alice", "gold", 50)
+		case 1:
+			// A bug that changes a wallet without going through the module.
+			ids, err := cardinal.NewSearch().Entity(filter.Contains(filter.Component[economy.Wallet]())).Collect(wCtx)
+			if err != nil {
+				return err
+			}
+			return cardinal.UpdateComponent[economy.Wallet](wCtx, ids[0], func(w *economy.Wallet) *economy.Wallet {
+				w.Balances["gold"] += 5
+				return w
+			})
+		}
+		return nil
+	}))
+
+	tf.DoTick()
+	assert.NilError(t, economy.CheckInvariants(cardinal.NewReadOnlyWorldContext(tf.World)))
+	tf.DoTick()
+	err := economy.CheckInvariants(cardinal.NewReadOnlyWorldContext(tf.World))
+	assert.ErrorIs(t, err, economy.ErrInvariantViolation)
+	assert.ErrorContains(t, err, "the balances of gold add up to 55, but its supply is 50")
+}
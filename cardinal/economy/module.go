@@ -0,0 +1,229 @@
+// Package economy is a module for fungible currencies, such as gold or gems, that personas hold in wallets:
+//
+//	err = world.UseModule(economy.NewModule(
+//		[]economy.Currency{{Name: "gold"}, {Name: "gems", MaxSupply: 1_000_000}},
+//		economy.WithMinters("treasury"),
+//	))
+//
+// Currency is only created by minting and only destroyed by burning, which change the currency's supply in the same
+// tick as the wallet; transfers move currency between wallets without changing the supply. After every tick is
+// committed, a post-commit system checks that the balances of every currency add up to its supply, and logs an error
+// if they don't, so that a bug that creates or destroys currency is noticed in the tick it happens in.
+//
+// Personas transfer and burn their currency with transactions, and the minters that the module is configured with
+// mint currency with transactions. Games move currency from their systems with Mint, Burn and Transfer, e.g. to hand
+// out quest rewards or to charge for crafting.
+package economy
+
+import (
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/statsd"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+const (
+	ModuleName    = "economy"
+	ModuleVersion = "v1.0.0"
+
+	TransferMessageName = "transfer"
+	MintMessageName     = "mint"
+	BurnMessageName     = "burn"
+)
+
+// Event types, which are emitted to the personas whose wallets changed.
+const (
+	EventTransferred = "economy-transferred"
+	EventMinted      = "economy-minted"
+	EventBurned      = "economy-burned"
+)
+
+var _ cardinal.Module = &Module{}
+
+// Currency is a currency that the module manages.
+type Currency struct {
+	Name string `json:"name"`
+	// MaxSupply is the most of the currency that can be in the wallets at once, or zero if there is no limit.
+	MaxSupply uint64 `json:"maxSupply"`
+}
+
+// TransferMsg moves currency from the sender's wallet to another persona's.
+type TransferMsg struct {
+	To       string `json:"to"`
+	Currency string `json:"currency"`
+	Amount   uint64 `json:"amount"`
+}
+
+// MintMsg creates currency in a persona's wallet. Only minters can send it.
+type MintMsg struct {
+	To       string `json:"to"`
+	Currency string `json:"currency"`
+	Amount   uint64 `json:"amount"`
+}
+
+// BurnMsg destroys currency in the sender's wallet.
+type BurnMsg struct {
+	Currency string `json:"currency"`
+	Amount   uint64 `json:"amount"`
+}
+
+type Result struct {
+	// Balance is the sender's balance of the currency after the message, or the recipient's for mints.
+	Balance uint64 `json:"balance"`
+}
+
+type Option func(*Module)
+
+// WithMinters sets the personas that may mint currency with transactions.
+func WithMinters(personaTags ...string) Option {
+	return func(m *Module) {
+		m.minters = append(m.minters, personaTags...)
+	}
+}
+
+type Module struct {
+	cardinal.ModuleBase
+	currencies []Currency
+	minters    []string
+}
+
+// NewModule returns a module that manages the currencies.
+func NewModule(currencies []Currency, opts ...Option) *Module {
+	m := &Module{currencies: currencies}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func (*Module) Name() string    { return ModuleName }
+func (*Module) Version() string { return ModuleVersion }
+
+func (*Module) RegisterComponents(w *cardinal.World) error {
+	if err := cardinal.RegisterComponent[Wallet](w); err != nil {
+		return err
+	}
+	return cardinal.RegisterComponent[Supply](w)
+}
+
+func (*Module) RegisterTxs(w *cardinal.World) error {
+	if err := cardinal.RegisterMessage[TransferMsg, Result](w, TransferMessageName); err != nil {
+		return err
+	}
+	if err := cardinal.RegisterMessage[MintMsg, Result](w, MintMessageName); err != nil {
+		return err
+	}
+	return cardinal.RegisterMessage[BurnMsg, Result](w, BurnMessageName)
+}
+
+func (*Module) RegisterReads(w *cardinal.World) error {
+	return registerReads(w)
+}
+
+func (m *Module) RegisterSystems(w *cardinal.World) error {
+	if err := cardinal.RegisterSystems(w, m.economySystem); err != nil {
+		return err
+	}
+	return cardinal.RegisterPostCommitSystems(w, checkInvariantsSystem)
+}
+
+// Init registers an init system that creates the supplies of the currencies.
+func (m *Module) Init(w *cardinal.World) error {
+	seen := map[string]bool{}
+	for _, currency := range m.currencies {
+		if currency.Name == "" {
+			return eris.New("currencies must have a name")
+		}
+		if seen[currency.Name] {
+			return eris.Errorf("currency %q is declared twice", currency.Name)
+		}
+		seen[currency.Name] = true
+	}
+	return cardinal.RegisterInitSystems(w, m.createSupplies)
+}
+
+func (m *Module) createSupplies(wCtx engine.Context) error {
+	for _, currency := range m.currencies {
+		if _, err := cardinal.Create(wCtx, Supply{Currency: currency.Name, Max: currency.MaxSupply}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// economySystem processes this tick's economy messages.
+func (m *Module) economySystem(wCtx engine.Context) error {
+	if err := cardinal.EachMessage[TransferMsg, Result](wCtx, func(tx message.TxData[TransferMsg]) (Result, error) {
+		from, err := sender(tx)
+		if err != nil {
+			return Result{}, err
+		}
+		if err := Transfer(wCtx, from, tx.Msg.To, tx.Msg.Currency, tx.Msg.Amount); err != nil {
+			return Result{}, err
+		}
+		balance, err := GetBalance(wCtx, from, tx.Msg.Currency)
+		return Result{Balance: balance}, err
+	}); err != nil {
+		return err
+	}
+	if err := cardinal.EachMessage[MintMsg, Result](wCtx, func(tx message.TxData[MintMsg]) (Result, error) {
+		minter, err := sender(tx)
+		if err != nil {
+			return Result{}, err
+		}
+		if !m.isMinter(minter) {
+			return Result{}, eris.Errorf("persona %q may not mint currency", minter)
+		}
+		if err := Mint(wCtx, tx.Msg.To, tx.Msg.Currency, tx.Msg.Amount); err != nil {
+			return Result{}, err
+		}
+		balance, err := GetBalance(wCtx, tx.Msg.To, tx.Msg.Currency)
+		return Result{Balance: balance}, err
+	}); err != nil {
+		return err
+	}
+	return cardinal.EachMessage[BurnMsg, Result](wCtx, func(tx message.TxData[BurnMsg]) (Result, error) {
+		personaTag, err := sender(tx)
+		if err != nil {
+			return Result{}, err
+		}
+		if err := Burn(wCtx, personaTag, tx.Msg.Currency, tx.Msg.Amount); err != nil {
+			return Result{}, err
+		}
+		balance, err := GetBalance(wCtx, personaTag, tx.Msg.Currency)
+		return Result{Balance: balance}, err
+	})
+}
+
+func (m *Module) isMinter(personaTag string) bool {
+	for _, minter := range m.minters {
+		if minter == personaTag {
+			return true
+		}
+	}
+	return false
+}
+
+func sender[In any](tx message.TxData[In]) (string, error) {
+	if tx.Tx == nil || tx.Tx.PersonaTag == "" {
+		return "", eris.New("economy messages must be signed by a persona")
+	}
+	return tx.Tx.PersonaTag, nil
+}
+
+// checkInvariantsSystem logs an error, and counts the economy.invariant_violations metric, when the committed state
+// violates the economy's invariants.
+func checkInvariantsSystem(wCtx engine.Context) error {
+	err := CheckInvariants(wCtx)
+	if err == nil {
+		return nil
+	}
+	log.Error().Err(err).Uint64("tick", wCtx.CurrentTick()).Msg("economy invariant violated")
+	if err := statsd.Client().Count("economy.invariant_violations", 1, nil, 1); err != nil {
+		log.Warn().Msgf("failed to emit count stat:%v", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package economy
+
+import (
+	"sort"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type BalancesRequest struct {
+	PersonaTag string `json:"personaTag"`
+}
+
+type BalancesReply struct {
+	// Balances are the persona's balances, by currency. Currencies the persona doesn't hold are left out.
+	Balances map[string]uint64 `json:"balances"`
+}
+
+type SuppliesRequest struct{}
+
+type SuppliesReply struct {
+	// Supplies are the supplies of every currency, ordered by currency.
+	Supplies []Supply `json:"supplies"`
+}
+
+// registerReads registers the /query/economy/balances and /query/economy/supplies queries.
+func registerReads(w *cardinal.World) error {
+	return cardinal.RegisterReads(w, ModuleName,
+		cardinal.NewRead[BalancesRequest, BalancesReply]("balances", queryBalances),
+		cardinal.NewRead[SuppliesRequest, SuppliesReply]("supplies", querySupplies),
+	)
+}
+
+func queryBalances(wCtx engine.Context, req *BalancesRequest) (*BalancesReply, error) {
+	_, wallet, ok, err := findWallet(wCtx, req.PersonaTag)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &BalancesReply{Balances: map[string]uint64{}}, nil
+	}
+	return &BalancesReply{Balances: wallet.Balances}, nil
+}
+
+func querySupplies(wCtx engine.Context, _ *SuppliesRequest) (*SuppliesReply, error) {
+	reply := &SuppliesReply{Supplies: []Supply{}}
+	var readErr error
+	err := cardinal.NewSearch().Entity(filter.Contains(filter.Component[Supply]())).Each(wCtx,
+		func(id types.EntityID) bool {
+			supply, err := cardinal.GetComponent[Supply](wCtx, id)
+			if err != nil {
+				readErr = err
+				return false
+			}
+			reply.Supplies = append(reply.Supplies, *supply)
+			return true
+		})
+	if err == nil {
+		err = readErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(reply.Supplies, func(i, j int) bool { return reply.Supplies[i].Currency < reply.Supplies[j].Currency })
+	return reply, nil
+}
@@ -0,0 +1,136 @@
+package entitlements
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+)
+
+var (
+	ErrInvalidFlag = errors.New("invalid entitlement flag")
+	ErrNotEntitled = errors.New("persona is not entitled")
+)
+
+// Entitlements holds the flags that were granted to a persona.
+type Entitlements struct {
+	PersonaTag string `json:"personaTag"`
+	// Flags are in ascending order.
+	Flags []string `json:"flags"`
+}
+
+func (Entitlements) Name() string { return "entitlements" }
+
+func find(wCtx engine.Context, personaTag string) (types.EntityID, *Entitlements, bool, error) {
+	ids, err := cardinal.NewSearch().
+		Entity(filter.Contains(filter.Component[Entitlements]())).
+		Where(cardinal.FilterFunction[Entitlements](func(e Entitlements) bool { return e.PersonaTag == personaTag })).
+		Collect(wCtx)
+	if err != nil || len(ids) == 0 {
+		return 0, nil, false, err
+	}
+	e, err := cardinal.GetComponent[Entitlements](wCtx, ids[0])
+	if err != nil {
+		return 0, nil, false, err
+	}
+	return ids[0], e, true, nil
+}
+
+// Get returns the persona's flags, in ascending order.
+func Get(wCtx engine.Context, personaTag string) ([]string, error) {
+	_, e, ok, err := find(wCtx, personaTag)
+	if err != nil || !ok {
+		return []string{}, err
+	}
+	return e.Flags, nil
+}
+
+// Has reports whether the flag was granted to the persona.
+func Has(wCtx engine.Context, personaTag, flag string) (bool, error) {
+	flags, err := Get(wCtx, personaTag)
+	if err != nil {
+		return false, err
+	}
+	_, found := slices.BinarySearch(flags, flag)
+	return found, nil
+}
+
+// Grant grants the flag to the persona. Granting a flag that the persona already has does nothing.
+func Grant(wCtx engine.Context, personaTag, flag string) error {
+	if personaTag == "" || flag == "" {
+		return eris.Wrap(ErrInvalidFlag, "the persona tag and the flag must not be empty")
+	}
+	id, e, ok, err := find(wCtx, personaTag)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if _, err := cardinal.Create(wCtx, Entitlements{PersonaTag: personaTag, Flags: []string{flag}}); err != nil {
+			return err
+		}
+	} else {
+		i, found := slices.BinarySearch(e.Flags, flag)
+		if found {
+			return nil
+		}
+		e.Flags = slices.Insert(e.Flags, i, flag)
+		if err := cardinal.SetComponent[Entitlements](wCtx, id, e); err != nil {
+			return err
+		}
+	}
+	return cardinal.EmitEventTo(wCtx, []string{personaTag}, map[string]any{
+		"type": EventGranted, "personaTag": personaTag, "flag": flag,
+	})
+}
+
+// Revoke revokes the flag from the persona. Revoking a flag that the persona doesn't have does nothing.
+func Revoke(wCtx engine.Context, personaTag, flag string) error {
+	id, e, ok, err := find(wCtx, personaTag)
+	if err != nil || !ok {
+		return err
+	}
+	i, found := slices.BinarySearch(e.Flags, flag)
+	if !found {
+		return nil
+	}
+	e.Flags = slices.Delete(e.Flags, i, i+1)
+	if len(e.Flags) == 0 {
+		err = cardinal.Remove(wCtx, id)
+	} else {
+		err = cardinal.SetComponent[Entitlements](wCtx, id, e)
+	}
+	if err != nil {
+		return err
+	}
+	return cardinal.EmitEventTo(wCtx, []string{personaTag}, map[string]any{
+		"type": EventRevoked, "personaTag": personaTag, "flag": flag,
+	})
+}
+
+// Require returns tx middleware that rejects the transactions of the given messages, by full name such as
+// "game.buy-skin", unless their persona has the flag. The transactions of other messages are not checked.
+func Require(flag string, messages ...string) cardinal.TxMiddleware {
+	return func(wCtx engine.Context, msg types.Message, tx txpool.TxData) error {
+		if !slices.Contains(messages, msg.FullName()) {
+			return nil
+		}
+		personaTag := ""
+		if tx.Tx != nil {
+			personaTag = tx.Tx.PersonaTag
+		}
+		ok, err := Has(wCtx, personaTag, flag)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return eris.Wrapf(ErrNotEntitled, "%s requires the %q flag", msg.FullName(), flag)
+		}
+		return nil
+	}
+}
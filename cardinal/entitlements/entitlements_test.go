@@ -0,0 +1,99 @@
+package entitlements_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/entitlements"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/server/utils"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/sign"
+)
+
+type BuySkinMsg struct {
+	Skin string `json:"skin"`
+}
+
+type BuySkinResult struct{}
+
+func flags(tf *testutils.TestFixture, personaTag string) []string {
+	res := tf.Post("query/entitlements/persona", entitlements.PersonaRequest{PersonaTag: personaTag})
+	assert.Equal(tf, res.StatusCode, http.StatusOK)
+	var reply entitlements.PersonaReply
+	assert.NilError(tf, json.NewDecoder(res.Body).Decode(&reply))
+	return reply.Flags
+}
+
+func TestGrantRevokeAndRequireFlags(t *testing.T) {
+	adminKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	tf := testutils.NewTestFixture(t, nil,
+		cardinal.WithAdminSigners(crypto.PubkeyToAddress(adminKey.PublicKey).Hex()))
+	assert.NilError(t, tf.World.UseModule(entitlements.NewModule()))
+	assert.NilError(t, cardinal.RegisterMessage[BuySkinMsg, BuySkinResult](tf.World, "buy-skin"))
+	assert.NilError(t, cardinal.RegisterTxMiddleware(tf.World, entitlements.Require("premium", "game.buy-skin")))
+	assert.NilError(t, cardinal.RegisterSystems(tf.World, func(wCtx engine.Context) error {
+		return cardinal.EachMessage[BuySkinMsg, BuySkinResult](wCtx,
+			func(message.TxData[BuySkinMsg]) (BuySkinResult, error) {
+				return BuySkinResult{}, nil
+			})
+	}))
+	tf.DoTick()
+
+	nonce := uint64(0)
+	send := func(name string, msg any) {
+		tx, err := sign.NewSystemTransaction(adminKey, tf.World.Namespace(), nonce, msg)
+		assert.NilError(t, err)
+		nonce++
+		res := tf.Post(utils.GetTxURL(entitlements.ModuleName, name), tx)
+		assert.Equal(t, res.StatusCode, http.StatusOK)
+		tf.DoTick()
+	}
+	buySkin := func(personaTag string) []error {
+		msgType, ok := tf.World.GetMessageByFullName("game.buy-skin")
+		assert.True(t, ok)
+		hash := tf.AddTransaction(msgType.ID(), BuySkinMsg{Skin: "gold"}, testutils.UniqueSignatureWithName(personaTag))
+		tf.DoTick()
+		_, errs, ok := cardinal.NewReadOnlyWorldContext(tf.World).GetTransactionReceipt(hash)
+		assert.True(t, ok)
+		return errs
+	}
+
+	errs := buySkin("alice")
+	assert.Equal(t, len(errs), 1)
+	assert.ErrorIs(t, errs[0], entitlements.ErrNotEntitled)
+
+	send(entitlements.GrantMessageName, entitlements.GrantMsg{PersonaTag: "alice", Flag: "premium"})
+	send(entitlements.GrantMessageName, entitlements.GrantMsg{PersonaTag: "alice", Flag: "beta"})
+	assert.DeepEqual(t, flags(tf, "alice"), []string{"beta", "premium"})
+	assert.Equal(t, len(buySkin("alice")), 0)
+
+	send(entitlements.RevokeMessageName, entitlements.RevokeMsg{PersonaTag: "alice", Flag: "premium"})
+	assert.DeepEqual(t, flags(tf, "alice"), []string{"beta"})
+	assert.DeepEqual(t, flags(tf, "bob"), []string{})
+	assert.Equal(t, len(buySkin("alice")), 1)
+}
+
+func TestOnlyAdminsGrantFlags(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, tf.World.UseModule(entitlements.NewModule()))
+	tf.DoTick()
+
+	msgType, ok := tf.World.GetMessageByFullName(entitlements.ModuleName + "." + entitlements.GrantMessageName)
+	assert.True(t, ok)
+	hash := tf.AddTransaction(msgType.ID(), entitlements.GrantMsg{PersonaTag: "alice", Flag: "premium"},
+		testutils.UniqueSignatureWithName("alice"))
+	tf.DoTick()
+	_, errs, ok := cardinal.NewReadOnlyWorldContext(tf.World).GetTransactionReceipt(hash)
+	assert.True(t, ok)
+	assert.Equal(t, len(errs), 1)
+	assert.ErrorIs(t, errs[0], entitlements.ErrNotAdminTransaction)
+	assert.DeepEqual(t, flags(tf, "alice"), []string{})
+}
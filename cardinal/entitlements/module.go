@@ -0,0 +1,124 @@
+// Package entitlements is a module for named flags that are granted to personas, such as beta access or premium:
+//
+//	err = world.UseModule(entitlements.NewModule())
+//
+// Flags are granted and revoked by the grant and revoke messages, which must be sent as system transactions signed
+// by one of the world's admin signers (see cardinal.WithAdminSigners):
+//
+//	tx, err := sign.NewSystemTransaction(adminKey, namespace, nonce, entitlements.GrantMsg{
+//		PersonaTag: "alice", Flag: "beta",
+//	})
+//	// POST tx to /tx/entitlements/grant
+//
+// Systems gate behavior on flags with Has, and tx middleware rejects the transactions of personas that lack a flag:
+//
+//	err = cardinal.RegisterTxMiddleware(world, entitlements.Require("premium", "game.buy-skin"))
+//
+// Clients read a persona's flags with the /query/entitlements/persona query.
+package entitlements
+
+import (
+	"errors"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+const (
+	ModuleName    = "entitlements"
+	ModuleVersion = "v1.0.0"
+
+	GrantMessageName  = "grant"
+	RevokeMessageName = "revoke"
+)
+
+// Event types, which are emitted to the personas whose flags changed.
+const (
+	EventGranted = "entitlement-granted"
+	EventRevoked = "entitlement-revoked"
+)
+
+var ErrNotAdminTransaction = errors.New(
+	"entitlements can only be granted or revoked by a system transaction of an admin signer",
+)
+
+var _ cardinal.Module = &Module{}
+
+// GrantMsg grants a flag to a persona. Only admin signers can send it.
+type GrantMsg struct {
+	PersonaTag string `json:"personaTag"`
+	Flag       string `json:"flag"`
+}
+
+// RevokeMsg revokes a flag from a persona. Only admin signers can send it.
+type RevokeMsg struct {
+	PersonaTag string `json:"personaTag"`
+	Flag       string `json:"flag"`
+}
+
+type Result struct {
+	// Flags are the persona's flags after the message, in ascending order.
+	Flags []string `json:"flags"`
+}
+
+type Module struct {
+	cardinal.ModuleBase
+}
+
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (*Module) Name() string    { return ModuleName }
+func (*Module) Version() string { return ModuleVersion }
+
+func (*Module) RegisterComponents(w *cardinal.World) error {
+	return cardinal.RegisterComponent[Entitlements](w)
+}
+
+func (*Module) RegisterTxs(w *cardinal.World) error {
+	if err := cardinal.RegisterMessage[GrantMsg, Result](w, GrantMessageName,
+		message.WithAdminSignature[GrantMsg, Result]()); err != nil {
+		return err
+	}
+	return cardinal.RegisterMessage[RevokeMsg, Result](w, RevokeMessageName,
+		message.WithAdminSignature[RevokeMsg, Result]())
+}
+
+func (*Module) RegisterReads(w *cardinal.World) error {
+	return registerReads(w)
+}
+
+func (*Module) RegisterSystems(w *cardinal.World) error {
+	return cardinal.RegisterSystems(w, entitlementsSystem)
+}
+
+// entitlementsSystem processes this tick's grant and revoke messages.
+func entitlementsSystem(wCtx engine.Context) error {
+	if err := cardinal.EachMessage[GrantMsg, Result](wCtx, func(tx message.TxData[GrantMsg]) (Result, error) {
+		// The server only accepts system transactions from admin signers.
+		if !tx.Tx.IsSystemTransaction() {
+			return Result{}, eris.Wrap(ErrNotAdminTransaction, "")
+		}
+		if err := Grant(wCtx, tx.Msg.PersonaTag, tx.Msg.Flag); err != nil {
+			return Result{}, err
+		}
+		flags, err := Get(wCtx, tx.Msg.PersonaTag)
+		return Result{Flags: flags}, err
+	}); err != nil {
+		return err
+	}
+	return cardinal.EachMessage[RevokeMsg, Result](wCtx, func(tx message.TxData[RevokeMsg]) (Result, error) {
+		if !tx.Tx.IsSystemTransaction() {
+			return Result{}, eris.Wrap(ErrNotAdminTransaction, "")
+		}
+		if err := Revoke(wCtx, tx.Msg.PersonaTag, tx.Msg.Flag); err != nil {
+			return Result{}, err
+		}
+		flags, err := Get(wCtx, tx.Msg.PersonaTag)
+		return Result{Flags: flags}, err
+	})
+}
@@ -0,0 +1,30 @@
+package entitlements
+
+import (
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type PersonaRequest struct {
+	PersonaTag string `json:"personaTag"`
+}
+
+type PersonaReply struct {
+	// Flags are the persona's flags, in ascending order.
+	Flags []string `json:"flags"`
+}
+
+// registerReads registers the /query/entitlements/persona query.
+func registerReads(w *cardinal.World) error {
+	return cardinal.RegisterReads(w, ModuleName,
+		cardinal.NewRead[PersonaRequest, PersonaReply]("persona", queryPersona),
+	)
+}
+
+func queryPersona(wCtx engine.Context, req *PersonaRequest) (*PersonaReply, error) {
+	flags, err := Get(wCtx, req.PersonaTag)
+	if err != nil {
+		return nil, err
+	}
+	return &PersonaReply{Flags: flags}, nil
+}
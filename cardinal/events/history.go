@@ -0,0 +1,129 @@
+package events
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+)
+
+// RecipientsField is the event field that lists the persona tags an event is addressed to.
+const RecipientsField = "recipients"
+
+// Entry is a single event that was emitted by a system, along with the tick it was emitted in.
+type Entry struct {
+	Tick  uint64          `json:"tick"`
+	Event json.RawMessage `json:"event"`
+}
+
+// Filter narrows down the events returned by History.Query. Zero values disable the corresponding filter.
+type Filter struct {
+	// StartTick is the first tick (inclusive) to return events for.
+	StartTick uint64
+	// EndTick is the last tick (exclusive) to return events for. 0 means there is no upper bound.
+	EndTick uint64
+	// Fields requires events to be JSON objects whose given fields are equal to the given string values. A field that
+	// is an array, like RecipientsField, matches if it contains the value.
+	Fields map[string]string
+	// Limit is the maximum number of events to return. Only the most recent matching events are returned.
+	Limit int
+}
+
+// History is a fixed-size buffer of the most recently emitted events. It is safe for concurrent use: Add publishes a
+// new snapshot of the events instead of changing the current one, so queries never block, or are blocked by, Add.
+type History struct {
+	size int
+	// mu serializes writers; readers only load the snapshot.
+	mu       sync.Mutex
+	snapshot atomic.Pointer[[]Entry]
+}
+
+// NewHistory creates a History that keeps at most size events.
+func NewHistory(size int) *History {
+	h := &History{size: size}
+	h.snapshot.Store(&[]Entry{})
+	return h
+}
+
+// Add records the events emitted during the given tick. Events that are not valid JSON (e.g. those emitted with
+// EmitStringEvent) are stored as JSON strings.
+func (h *History) Add(tick uint64, events [][]byte) {
+	if h.size == 0 || len(events) == 0 {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	old := *h.snapshot.Load()
+	kept := old[min(len(old), max(0, len(old)+len(events)-h.size)):]
+	entries := make([]Entry, 0, min(h.size, len(kept)+len(events)))
+	entries = append(entries, kept...)
+	for _, event := range events[max(0, len(events)-h.size):] {
+		raw := json.RawMessage(event)
+		if !json.Valid(event) {
+			// Marshalling a string never fails.
+			raw, _ = json.Marshal(string(event))
+		}
+		entries = append(entries, Entry{Tick: tick, Event: raw})
+	}
+	h.snapshot.Store(&entries)
+}
+
+// Query returns the most recent events that match the given filter, ordered from oldest to newest.
+func (h *History) Query(f Filter) []Entry {
+	entries := *h.snapshot.Load()
+
+	var matched []Entry
+	// Walk backwards from the newest event so we can stop as soon as the limit is reached.
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if !f.matches(entry) {
+			continue
+		}
+		matched = append(matched, entry)
+		if f.Limit > 0 && len(matched) == f.Limit {
+			break
+		}
+	}
+
+	// Reverse the result so the events are in the order they were emitted.
+	for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+		matched[i], matched[j] = matched[j], matched[i]
+	}
+	return matched
+}
+
+func (f Filter) matches(entry Entry) bool {
+	if entry.Tick < f.StartTick {
+		return false
+	}
+	if f.EndTick != 0 && entry.Tick >= f.EndTick {
+		return false
+	}
+	if len(f.Fields) == 0 {
+		return true
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(entry.Event, &fields); err != nil {
+		return false
+	}
+	for name, want := range f.Fields {
+		if !fieldMatches(fields[name], want) {
+			return false
+		}
+	}
+	return true
+}
+
+func fieldMatches(field any, want string) bool {
+	switch got := field.(type) {
+	case string:
+		return got == want
+	case []any:
+		for _, elem := range got {
+			if s, ok := elem.(string); ok && s == want {
+				return true
+			}
+		}
+	}
+	return false
+}
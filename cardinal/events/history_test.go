@@ -0,0 +1,65 @@
+package events_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/events"
+)
+
+func TestHistoryKeepsOnlyTheMostRecentEvents(t *testing.T) {
+	h := events.NewHistory(3)
+	h.Add(0, [][]byte{[]byte(`{"n":1}`), []byte(`{"n":2}`)})
+	h.Add(1, [][]byte{[]byte(`{"n":3}`), []byte(`{"n":4}`)})
+
+	got := h.Query(events.Filter{})
+	assert.Equal(t, 3, len(got))
+	assert.Equal(t, `{"n":2}`, string(got[0].Event))
+	assert.Equal(t, uint64(0), got[0].Tick)
+	assert.Equal(t, `{"n":4}`, string(got[2].Event))
+	assert.Equal(t, uint64(1), got[2].Tick)
+}
+
+func TestHistoryQueryFilters(t *testing.T) {
+	h := events.NewHistory(10)
+	h.Add(0, [][]byte{[]byte(`{"type":"attack","personaTag":"alpha"}`), []byte("plain string")})
+	h.Add(1, [][]byte{[]byte(`{"type":"attack","personaTag":"beta"}`)})
+	h.Add(2, [][]byte{[]byte(`{"type":"heal","personaTag":"alpha"}`)})
+
+	got := h.Query(events.Filter{Fields: map[string]string{"type": "attack"}})
+	assert.Equal(t, 2, len(got))
+
+	got = h.Query(events.Filter{Fields: map[string]string{"personaTag": "alpha"}, Limit: 1})
+	assert.Equal(t, 1, len(got))
+	assert.Equal(t, uint64(2), got[0].Tick)
+
+	got = h.Query(events.Filter{StartTick: 1, EndTick: 2})
+	assert.Equal(t, 1, len(got))
+	assert.Equal(t, `{"type":"attack","personaTag":"beta"}`, string(got[0].Event))
+
+	got = h.Query(events.Filter{EndTick: 1})
+	assert.Equal(t, 2, len(got))
+	assert.Equal(t, `"plain string"`, string(got[1].Event))
+
+	h.Add(3, [][]byte{[]byte(`{"type":"whisper","recipients":["alpha","gamma"]}`)})
+	got = h.Query(events.Filter{Fields: map[string]string{events.RecipientsField: "gamma"}})
+	assert.Equal(t, 1, len(got))
+	assert.Equal(t, uint64(3), got[0].Tick)
+	got = h.Query(events.Filter{Fields: map[string]string{events.RecipientsField: "beta"}})
+	assert.Equal(t, 0, len(got))
+}
+
+func TestHistoryQueriesAreSnapshots(t *testing.T) {
+	h := events.NewHistory(2)
+	h.Add(0, [][]byte{[]byte(`{"n":1}`)})
+	got := h.Query(events.Filter{})
+
+	// A batch larger than the history keeps only its most recent events, and doesn't change earlier queries.
+	h.Add(1, [][]byte{[]byte(`{"n":2}`), []byte(`{"n":3}`), []byte(`{"n":4}`)})
+	assert.Equal(t, 1, len(got))
+	assert.Equal(t, `{"n":1}`, string(got[0].Event))
+	got = h.Query(events.Filter{})
+	assert.Equal(t, 2, len(got))
+	assert.Equal(t, `{"n":3}`, string(got[0].Event))
+	assert.Equal(t, `{"n":4}`, string(got[1].Event))
+}
@@ -0,0 +1,106 @@
+package gamestate
+
+import (
+	"cmp"
+	"slices"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// EntityChange is how the components of an entity changed since the pending state was last committed or discarded.
+type EntityChange struct {
+	ID types.EntityID
+	// Created reports whether the entity was created, in which case Added has all of its components.
+	Created bool
+	// Removed reports whether the entity was removed, in which case RemovedComponents has all of its components.
+	Removed bool
+	// Added are the components that were added to the entity.
+	Added []types.ComponentMetadata
+	// RemovedComponents are the components that were removed from the entity.
+	RemovedComponents []types.ComponentMetadata
+	// Changed are the components, other than the added ones, whose value was set.
+	Changed []types.ComponentMetadata
+}
+
+// GetPendingChanges returns the changes, in ascending order of entity ID, to the entities whose components changed
+// since the pending state was last committed or discarded. Entities that were created and removed in that time, and
+// entities that ended up with the components they started with and no changed values, are left out.
+func (m *EntityCommandBuffer) GetPendingChanges() ([]EntityChange, error) {
+	changes := map[types.EntityID]*EntityChange{}
+
+	ids, err := m.entityIDToOriginArchID.Keys()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		originArchID, err := m.entityIDToOriginArchID.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		var before, after []types.ComponentMetadata
+		if originArchID != doesNotExistArchetypeID {
+			if before, err = m.GetComponentTypesForArchID(originArchID); err != nil {
+				return nil, err
+			}
+		}
+		archID, err := m.entityIDToArchID.Get(id)
+		removed := err != nil
+		if removed && originArchID == doesNotExistArchetypeID {
+			continue
+		}
+		if !removed {
+			if after, err = m.GetComponentTypesForArchID(archID); err != nil {
+				return nil, err
+			}
+		}
+		change := &EntityChange{
+			ID:                id,
+			Created:           originArchID == doesNotExistArchetypeID,
+			Removed:           removed,
+			Added:             componentsMissingFrom(after, before),
+			RemovedComponents: componentsMissingFrom(before, after),
+		}
+		if change.Created || change.Removed || len(change.Added) > 0 || len(change.RemovedComponents) > 0 {
+			changes[id] = change
+		}
+	}
+
+	keys, err := m.compValuesChanged.Keys()
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		change, ok := changes[key.entityID]
+		if ok && slices.ContainsFunc(change.Added, func(c types.ComponentMetadata) bool { return c.ID() == key.typeID }) {
+			continue
+		}
+		comp, err := m.typeToComponent.Get(key.typeID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			change = &EntityChange{ID: key.entityID}
+			changes[key.entityID] = change
+		}
+		change.Changed = append(change.Changed, comp)
+	}
+
+	result := make([]EntityChange, 0, len(changes))
+	for _, change := range changes {
+		slices.SortFunc(change.Changed, func(a, b types.ComponentMetadata) int { return cmp.Compare(a.ID(), b.ID()) })
+		result = append(result, *change)
+	}
+	slices.SortFunc(result, func(a, b EntityChange) int { return cmp.Compare(a.ID, b.ID) })
+	return result, nil
+}
+
+// componentsMissingFrom returns the components of a that aren't in b.
+func componentsMissingFrom(a, b []types.ComponentMetadata) []types.ComponentMetadata {
+	var missing []types.ComponentMetadata
+	for _, c := range a {
+		if !slices.ContainsFunc(b, func(other types.ComponentMetadata) bool { return other.ID() == c.ID() }) {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,132 @@
+package gamestate
+
+import (
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/statsd"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// compressedFlag is the first byte of a stored component value that is zstd compressed. Uncompressed values are
+// stored as plain JSON, which never starts with this byte, so values that were stored before compression was turned
+// on, or that are below the threshold, are read as they are.
+const compressedFlag byte = 0x01
+
+// zstdDecoder decompresses component values. Values are decompressed whether or not compression is turned on, since
+// storage may hold values that were compressed by an earlier run.
+var zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+	return zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+})
+
+// compressor compresses component values that are at least threshold bytes long. A nil compressor compresses
+// nothing.
+type compressor struct {
+	threshold int
+	encoder   *zstd.Encoder
+}
+
+func newCompressor(threshold int) (*compressor, error) {
+	if threshold <= 0 {
+		return nil, nil //nolint:nilnil // a nil compressor turns compression off
+	}
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to create zstd encoder")
+	}
+	return &compressor{threshold: threshold, encoder: encoder}, nil
+}
+
+// compress returns the value to store for the JSON encoded component value. Values are only compressed if that
+// makes them smaller.
+func (c *compressor) compress(bz []byte) []byte {
+	if c == nil || len(bz) < c.threshold {
+		return bz
+	}
+	compressed := c.encoder.EncodeAll(bz, []byte{compressedFlag})
+	if len(compressed) >= len(bz) {
+		return bz
+	}
+	return compressed
+}
+
+// decompressComponent returns the JSON encoded component value of a stored value.
+func decompressComponent(bz []byte) ([]byte, error) {
+	if len(bz) == 0 || bz[0] != compressedFlag {
+		return bz, nil
+	}
+	decoder, err := zstdDecoder()
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to create zstd decoder")
+	}
+	bz, err = decoder.DecodeAll(bz[1:], nil)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to decompress component value")
+	}
+	return bz, nil
+}
+
+// compressionStats adds up the sizes of the component values that are stored in a commit, by component.
+type compressionStats map[types.ComponentID]*compressionStat
+
+type compressionStat struct {
+	name          string
+	encodedBytes  int
+	storedBytes   int
+	compressedCnt int
+}
+
+func (s compressionStats) add(cType types.ComponentMetadata, encoded, stored []byte) {
+	stat, ok := s[cType.ID()]
+	if !ok {
+		stat = &compressionStat{name: cType.Name()}
+		s[cType.ID()] = stat
+	}
+	stat.encodedBytes += len(encoded)
+	stat.storedBytes += len(stored)
+	if len(stored) < len(encoded) {
+		stat.compressedCnt++
+	}
+}
+
+// emit reports the sizes of the component values, and their compression ratio, which is the stored size divided by
+// the encoded size.
+func (s compressionStats) emit() {
+	client := statsd.Client()
+	for _, stat := range s {
+		if stat.compressedCnt == 0 {
+			continue
+		}
+		tags := []string{"component:" + stat.name}
+		ratio := float64(stat.storedBytes) / float64(stat.encodedBytes)
+		err := client.Count("component_compression.encoded_bytes", int64(stat.encodedBytes), tags, 1)
+		if err == nil {
+			err = client.Count("component_compression.stored_bytes", int64(stat.storedBytes), tags, 1)
+		}
+		if err == nil {
+			err = client.Count("component_compression.compressed_values", int64(stat.compressedCnt), tags, 1)
+		}
+		if err == nil {
+			err = client.Gauge("component_compression.ratio", ratio, tags, 1)
+		}
+		if err != nil {
+			log.Warn().Err(err).Msg("failed to emit component compression stats")
+			return
+		}
+	}
+}
+
+// SetCompressionThreshold makes later commits store the component values whose JSON encoding is at least threshold
+// bytes long compressed with zstd. Compressed values are decompressed transparently when they are read. A threshold
+// of zero turns compression off, but values that were already compressed can still be read.
+func (m *EntityCommandBuffer) SetCompressionThreshold(threshold int) error {
+	c, err := newCompressor(threshold)
+	if err != nil {
+		return err
+	}
+	m.compression = c
+	return nil
+}
@@ -0,0 +1,80 @@
+package gamestate_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/component"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+type MapTiles struct {
+	Tiles string
+}
+
+func (MapTiles) Name() string {
+	return "map_tiles"
+}
+
+func TestLargeComponentsAreStoredCompressed(t *testing.T) {
+	tilesComp, err := component.NewComponentMetadata[MapTiles]()
+	assert.NilError(t, err)
+	assert.NilError(t, tilesComp.SetID(3))
+
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	storage := gamestate.NewRedisPrimitiveStorage(client)
+	manager, err := gamestate.NewEntityCommandBuffer(&storage)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.RegisterComponents([]types.ComponentMetadata{fooComp, tilesComp}))
+	assert.NilError(t, manager.SetCompressionThreshold(256))
+	ctx := context.Background()
+
+	small := Foo{Value: 1}
+	large := MapTiles{Tiles: strings.Repeat("grass,water,", 1000)}
+	id, err := manager.CreateEntity(fooComp, tilesComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, small))
+	assert.NilError(t, manager.SetComponentForEntity(tilesComp, id, large))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// Only the value above the threshold is compressed.
+	keys, err := client.Keys(ctx, "*").Result()
+	assert.NilError(t, err)
+	var compressed, uncompressed int
+	for _, key := range keys {
+		bz, err := client.Get(ctx, key).Bytes()
+		if err != nil {
+			continue
+		}
+		switch {
+		case strings.Contains(string(bz), "grass,water"):
+			t.Fatalf("large component value was stored uncompressed under %q", key)
+		case len(bz) > 0 && bz[0] == 0x01:
+			compressed++
+			assert.Check(t, len(bz) < len(large.Tiles)/10)
+		case string(bz) == `{"Value":1}`:
+			uncompressed++
+		}
+	}
+	assert.Equal(t, compressed, 1)
+	assert.Equal(t, uncompressed, 1)
+
+	// Values are decompressed when they are read, by a fresh buffer as well as by a read-only view.
+	reloaded, err := gamestate.NewEntityCommandBuffer(&storage)
+	assert.NilError(t, err)
+	assert.NilError(t, reloaded.RegisterComponents([]types.ComponentMetadata{fooComp, tilesComp}))
+	for _, reader := range []gamestate.Reader{reloaded, reloaded.ToReadOnly()} {
+		got, err := reader.GetComponentForEntity(tilesComp, id)
+		assert.NilError(t, err)
+		assert.Equal(t, got, large)
+		got, err = reader.GetComponentForEntity(fooComp, id)
+		assert.NilError(t, err)
+		assert.Equal(t, got, small)
+	}
+}
@@ -79,5 +79,12 @@ these keys and rebuild the other mapping in memory.
 
 In memory, compValues are written to redis during a FinalizeTick cycle. Components that were not actually changed (e.g.
 only read operations were performed) are still written to the DB.
+
+Component values are loaded lazily: GetComponentForEntity reads a single ECB:COMPONENT-VALUE key, and only the first
+time the value is needed, so an entity with many components is never read in full. The encoded values that were read
+or committed are kept in memory across ticks, and are replaced when a commit writes them, so a value is only read from
+redis once. Prefetch loads the values of a set of components that aren't kept yet with batched MGETs; the world calls
+it with the declared access of systems before they run. Systems that don't declare their access still read one value
+per round trip.
 */
 package gamestate
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"slices"
+	"sync"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/rotisserie/eris"
@@ -29,7 +31,12 @@ type EntityCommandBuffer struct {
 
 	compValues         VolatileStorage[compKey, any]
 	compValuesToDelete VolatileStorage[compKey, bool]
-	typeToComponent    VolatileStorage[types.ComponentID, types.ComponentMetadata]
+	compValuesChanged  VolatileStorage[compKey, bool]
+	ephemeralValues    *ephemeralStore
+	// committedValues holds the encoded values that components had as of the last commit, for the values that were
+	// read or written since the buffer was created, so that they aren't read from storage again; see Prefetch.
+	committedValues *committedCache
+	typeToComponent VolatileStorage[types.ComponentID, types.ComponentMetadata]
 
 	activeEntities VolatileStorage[types.ArchetypeID, activeEntities]
 
@@ -44,6 +51,39 @@ type EntityCommandBuffer struct {
 
 	archIDToComps  VolatileStorage[types.ArchetypeID, []types.ComponentMetadata]
 	pendingArchIDs []types.ArchetypeID
+
+	limits Limits
+
+	// compression compresses large component values before they are stored; see SetCompressionThreshold.
+	compression *compressor
+
+	// archMatches caches the archetypes that match each filter signature; see FindArchetypes.
+	archMatches map[string]*archetypeMatches
+
+	// undo holds what is needed to undo the most recent commits, if rewinding is enabled; see SetRewindLimit.
+	undo *undoHistory
+
+	// fingerprint is saved with every finalized tick; see SetFingerprint.
+	fingerprint            []byte
+	saveGenesisFingerprint bool
+
+	// tickEvents are saved with the next finalized tick, if the tick log is kept; see SetTickLogRetention.
+	tickLogRetention int
+	tickEvents       [][]byte
+	tickEventsTick   uint64
+
+	// indexEntries are the entries of lookup indexes that are saved with the next finalized tick; see
+	// SetIndexEntries.
+	indexEntries map[string]map[string][]byte
+
+	// fieldIndexes are the indexes of component fields that searches can look up; see AddFieldIndex.
+	fieldIndexes []*fieldIndex
+
+	// queuedHashes are the hashes of the transactions in the transaction queue, in order, so that StartNextTick
+	// doesn't read the queue back from storage; see QueueTransaction. Transactions are queued by other goroutines
+	// than the one that ticks, so queuedHashes is guarded by queueMu.
+	queuedHashes []types.TxHash
+	queueMu      sync.Mutex
 }
 
 // NewEntityCommandBuffer creates a new command buffer manager that is able to queue up a series of states changes and
@@ -53,6 +93,9 @@ func NewEntityCommandBuffer(storage PrimitiveStorage[string]) (*EntityCommandBuf
 		dbStorage:          storage,
 		compValues:         NewMapStorage[compKey, any](),
 		compValuesToDelete: NewMapStorage[compKey, bool](),
+		compValuesChanged:  NewMapStorage[compKey, bool](),
+		ephemeralValues:    newEphemeralStore(),
+		committedValues:    newCommittedCache(),
 
 		activeEntities: NewMapStorage[types.ArchetypeID, activeEntities](),
 		archIDToComps:  NewMapStorage[types.ArchetypeID, []types.ComponentMetadata](),
@@ -81,10 +124,21 @@ func (m *EntityCommandBuffer) RegisterComponents(comps []types.ComponentMetadata
 
 // DiscardPending discards any pending state changes.
 func (m *EntityCommandBuffer) DiscardPending() error {
+	// The field indexes hold the pending state, so they are built again from the committed state.
+	m.dropFieldIndexes()
+	return m.clearPending()
+}
+
+// clearPending clears the pending state changes, which were either committed or are discarded.
+func (m *EntityCommandBuffer) clearPending() error {
 	err := m.compValues.Clear()
 	if err != nil {
 		return err
 	}
+	err = m.compValuesChanged.Clear()
+	if err != nil {
+		return err
+	}
 
 	// Any entity archetypes movements need to be undone
 	err = m.activeEntities.Clear()
@@ -115,6 +169,10 @@ func (m *EntityCommandBuffer) DiscardPending() error {
 			return err
 		}
 	}
+	if len(m.pendingArchIDs) > 0 {
+		// The discarded archetype IDs will be reused for other sets of components.
+		m.archMatches = nil
+	}
 	m.pendingArchIDs = m.pendingArchIDs[:0]
 	return nil
 }
@@ -159,10 +217,15 @@ func (m *EntityCommandBuffer) RemoveEntity(idToRemove types.EntityID) error {
 		if err != nil {
 			return err
 		}
+		err = m.compValuesChanged.Delete(key)
+		if err != nil {
+			return err
+		}
 		err = m.compValuesToDelete.Set(key, true)
 		if err != nil {
 			return err
 		}
+		m.unindexComponent(comp, idToRemove)
 	}
 
 	return nil
@@ -179,6 +242,19 @@ func (m *EntityCommandBuffer) CreateEntity(comps ...types.ComponentMetadata) (ty
 
 // CreateManyEntities creates many entities with the given set of components.
 func (m *EntityCommandBuffer) CreateManyEntities(num int, comps ...types.ComponentMetadata) ([]types.EntityID, error) {
+	if err := checkLimit(LimitComponentsPerEntity, m.limits.MaxComponentsPerEntity, len(comps)); err != nil {
+		return nil, err
+	}
+	if m.limits.MaxEntities > 0 {
+		count, err := m.entityCount()
+		if err != nil {
+			return nil, err
+		}
+		if err := checkLimit(LimitEntities, m.limits.MaxEntities, count+num); err != nil {
+			return nil, err
+		}
+	}
+
 	archID, err := m.getOrMakeArchIDForComponents(comps)
 	if err != nil {
 		return nil, err
@@ -211,6 +287,11 @@ func (m *EntityCommandBuffer) CreateManyEntities(num int, comps ...types.Compone
 	if err != nil {
 		return nil, err
 	}
+	for _, comp := range comps {
+		if err := m.indexDefaultValue(comp, ids...); err != nil {
+			return nil, err
+		}
+	}
 	return ids, nil
 }
 
@@ -226,9 +307,35 @@ func (m *EntityCommandBuffer) SetComponentForEntity(
 	if !filter.MatchComponentMetadata(comps, cType) {
 		return eris.Wrap(iterators.ErrComponentNotOnEntity, "")
 	}
+	if err := m.checkComponentPayloadSize(cType, value); err != nil {
+		return err
+	}
 
 	key := compKey{cType.ID(), id}
-	return m.compValues.Set(key, value)
+	if err = m.compValuesChanged.Set(key, true); err != nil {
+		return err
+	}
+	if err = m.compValues.Set(key, value); err != nil {
+		return err
+	}
+	return m.indexComponentValue(cType, value, id)
+}
+
+// GetChangedEntities returns the IDs of entities whose value for the given component was set or added during the
+// pending (uncommitted) state changes. The IDs are sorted in ascending order.
+func (m *EntityCommandBuffer) GetChangedEntities(cType types.ComponentMetadata) ([]types.EntityID, error) {
+	keys, err := m.compValuesChanged.Keys()
+	if err != nil {
+		return nil, err
+	}
+	var ids []types.EntityID
+	for _, key := range keys {
+		if key.typeID == cType.ID() {
+			ids = append(ids, key.entityID)
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
 }
 
 // GetComponentForEntity returns the saved component data for the given entity.
@@ -248,6 +355,27 @@ func (m *EntityCommandBuffer) GetComponentForEntity(cType types.ComponentMetadat
 		return nil, eris.Wrap(iterators.ErrComponentNotOnEntity, "")
 	}
 
+	// Ephemeral components are never persisted, so their last committed value can only be found in memory.
+	if cType.IsEphemeral() {
+		bz, ok := m.ephemeralValues.get(key)
+		if !ok {
+			if bz, err = cType.New(); err != nil {
+				return nil, err
+			}
+		}
+		if value, err = cType.Decode(bz); err != nil {
+			return nil, err
+		}
+		return value, m.compValues.Set(key, value)
+	}
+
+	if bz, ok := m.committedValues.get(key); ok {
+		if value, err = cType.Decode(bz); err != nil {
+			return nil, err
+		}
+		return value, m.compValues.Set(key, value)
+	}
+
 	// Fetch the value from storage
 	redisKey := storageComponentKey(cType.ID(), id)
 
@@ -264,10 +392,14 @@ func (m *EntityCommandBuffer) GetComponentForEntity(cType types.ComponentMetadat
 			return nil, err
 		}
 	}
+	if bz, err = decompressComponent(bz); err != nil {
+		return nil, err
+	}
 	value, err = cType.Decode(bz)
 	if err != nil {
 		return nil, err
 	}
+	m.committedValues.set(key, bz)
 	return value, m.compValues.Set(key, value)
 }
 
@@ -292,6 +424,9 @@ func (m *EntityCommandBuffer) AddComponentToEntity(cType types.ComponentMetadata
 	if filter.MatchComponentMetadata(fromComps, cType) {
 		return eris.Wrap(iterators.ErrComponentAlreadyOnEntity, "")
 	}
+	if err := checkLimit(LimitComponentsPerEntity, m.limits.MaxComponentsPerEntity, len(fromComps)+1); err != nil {
+		return err
+	}
 	toComps := append(fromComps, cType) //nolint:gocritic // easier this way.
 	if err = sortComponentSet(toComps); err != nil {
 		return err
@@ -305,7 +440,13 @@ func (m *EntityCommandBuffer) AddComponentToEntity(cType types.ComponentMetadata
 	if err != nil {
 		return err
 	}
-	return m.moveEntityByArchetype(fromArchID, toArchID, id)
+	if err = m.compValuesChanged.Set(compKey{cType.ID(), id}, true); err != nil {
+		return err
+	}
+	if err = m.moveEntityByArchetype(fromArchID, toArchID, id); err != nil {
+		return err
+	}
+	return m.indexDefaultValue(cType, id)
 }
 
 // RemoveComponentFromEntity removes the given component from the given entity. An error is returned if the entity
@@ -335,6 +476,10 @@ func (m *EntityCommandBuffer) RemoveComponentFromEntity(cType types.ComponentMet
 	if err != nil {
 		return err
 	}
+	err = m.compValuesChanged.Delete(key)
+	if err != nil {
+		return err
+	}
 	err = m.compValuesToDelete.Set(key, true)
 	if err != nil {
 		return err
@@ -347,6 +492,7 @@ func (m *EntityCommandBuffer) RemoveComponentFromEntity(cType types.ComponentMet
 	if err != nil {
 		return err
 	}
+	m.unindexComponent(cType, id)
 	return m.moveEntityByArchetype(fromArchID, toArchID, id)
 }
 
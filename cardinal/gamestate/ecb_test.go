@@ -649,3 +649,25 @@ func TestFinalizeTickPerformanceIsConsistent(t *testing.T) {
 	assert.Assert(t, averageAlloc < maxAlloc,
 		"FinalizeTick allocated an average of %v but must be less than %v", averageAlloc, maxAlloc)
 }
+
+func TestFindArchetypesIsCachedUntilArchetypesAreDiscarded(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	ctx := context.Background()
+	hasFoo := filter.Contains(filter.Component[Foo]())
+
+	_, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	assert.Equal(t, len(manager.FindArchetypes(hasFoo)), 1)
+
+	// A new archetype with Foo is found by the cached search.
+	_, err = manager.CreateEntity(fooComp, barComp)
+	assert.NilError(t, err)
+	assert.Equal(t, len(manager.FindArchetypes(hasFoo)), 2)
+
+	// The discarded archetype's ID is reused for an archetype without Foo.
+	assert.NilError(t, manager.DiscardPending())
+	_, err = manager.CreateEntity(barComp)
+	assert.NilError(t, err)
+	assert.Equal(t, len(manager.FindArchetypes(hasFoo)), 1)
+}
@@ -0,0 +1,38 @@
+package gamestate
+
+import (
+	"sync"
+)
+
+// ephemeralStore holds the committed values of ephemeral components. These values only live in process memory; they
+// are never written to the DB and are lost when the process exits. The store is shared between the
+// EntityCommandBuffer and its read-only views, so access is guarded by a mutex.
+type ephemeralStore struct {
+	mu     sync.RWMutex
+	values map[compKey][]byte
+}
+
+func newEphemeralStore() *ephemeralStore {
+	return &ephemeralStore{
+		values: map[compKey][]byte{},
+	}
+}
+
+func (e *ephemeralStore) get(key compKey) ([]byte, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	bz, ok := e.values[key]
+	return bz, ok
+}
+
+func (e *ephemeralStore) set(key compKey, bz []byte) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.values[key] = bz
+}
+
+func (e *ephemeralStore) delete(key compKey) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.values, key)
+}
@@ -0,0 +1,65 @@
+package gamestate_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/component"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+type Path struct {
+	Steps []int
+}
+
+func (Path) Name() string {
+	return "path"
+}
+
+func TestEphemeralComponentsAreNeverPersisted(t *testing.T) {
+	ctx := context.Background()
+	pathComp, err := component.NewComponentMetadata[Path](component.WithEphemeral[Path]())
+	assert.NilError(t, err)
+	assert.NilError(t, pathComp.SetID(3))
+
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	storage := gamestate.NewRedisPrimitiveStorage(client)
+	manager, err := gamestate.NewEntityCommandBuffer(&storage)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.RegisterComponents([]types.ComponentMetadata{fooComp, pathComp}))
+
+	id, err := manager.CreateEntity(fooComp, pathComp)
+	assert.NilError(t, err)
+	wantPath := Path{Steps: []int{1, 2, 3}}
+	assert.NilError(t, manager.SetComponentForEntity(pathComp, id, wantPath))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// The value survives the tick in memory...
+	gotPath, err := manager.GetComponentForEntity(pathComp, id)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, wantPath, gotPath)
+
+	// ...and is visible to read-only views...
+	bz, err := manager.ToReadOnly().GetComponentForEntityInRawJSON(pathComp, id)
+	assert.NilError(t, err)
+	assert.Equal(t, `{"Steps":[1,2,3]}`, string(bz))
+
+	// ...but was never written to the DB.
+	keys, err := client.Keys(ctx, "ECB:COMPONENT-VALUE:TYPE-ID-3:*").Result()
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(keys))
+
+	// A fresh buffer on top of the same DB only sees the default value.
+	freshManager, err := gamestate.NewEntityCommandBuffer(&storage)
+	assert.NilError(t, err)
+	assert.NilError(t, freshManager.RegisterComponents([]types.ComponentMetadata{fooComp, pathComp}))
+	gotPath, err = freshManager.GetComponentForEntity(pathComp, id)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, Path{}, gotPath)
+}
@@ -0,0 +1,165 @@
+package gamestate
+
+import (
+	"slices"
+
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// fieldIndex maps the values of a field of a component to the entities that have them, so that searches for a value
+// don't have to check every entity with the component.
+//
+// The index holds the pending state: it is updated by every state change of the command buffer, so searches see the
+// changes that were made earlier in the same tick. It is built from the state the first time it is looked up, and
+// dropped when the pending state changes are discarded, since they may already be in it.
+type fieldIndex struct {
+	cType types.ComponentMetadata
+	field string
+
+	built bool
+	// entities are the entities that have each key.
+	entities map[string]map[types.EntityID]struct{}
+	// keys are the keys of the entities.
+	keys map[types.EntityID]string
+}
+
+// AddFieldIndex adds an index of the field with the given name of the component, which LookupFieldIndex searches.
+func (m *EntityCommandBuffer) AddFieldIndex(cType types.ComponentMetadata, field string) {
+	m.fieldIndexes = append(m.fieldIndexes, &fieldIndex{cType: cType, field: field})
+}
+
+// LookupFieldIndex returns the IDs, in ascending order, of the entities whose field of the component is indexed
+// under the key; see filter.FieldKey. False is returned if the field has no index.
+func (m *EntityCommandBuffer) LookupFieldIndex(component, field, key string) ([]types.EntityID, bool, error) {
+	for _, index := range m.fieldIndexes {
+		if index.cType.Name() != component || index.field != field {
+			continue
+		}
+		if !index.built {
+			if err := m.buildFieldIndex(index); err != nil {
+				return nil, true, err
+			}
+		}
+		ids := make([]types.EntityID, 0, len(index.entities[key]))
+		for id := range index.entities[key] {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+		return ids, true, nil
+	}
+	return nil, false, nil
+}
+
+func (m *EntityCommandBuffer) buildFieldIndex(index *fieldIndex) error {
+	index.entities = map[string]map[types.EntityID]struct{}{}
+	index.keys = map[types.EntityID]string{}
+	for archID := range types.ArchetypeID(m.ArchetypeCount()) {
+		comps, err := m.GetComponentTypesForArchID(archID)
+		if err != nil {
+			return err
+		}
+		if !filter.MatchComponentMetadata(comps, index.cType) {
+			continue
+		}
+		ids, err := m.GetEntitiesForArchID(archID)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			value, err := m.GetComponentForEntity(index.cType, id)
+			if err != nil {
+				return err
+			}
+			key, err := filter.FieldKey(value, index.field)
+			if err != nil {
+				return err
+			}
+			index.set(id, key)
+		}
+	}
+	index.built = true
+	return nil
+}
+
+// dropFieldIndexes drops the built indexes, which are built again from the state when they are next looked up.
+func (m *EntityCommandBuffer) dropFieldIndexes() {
+	for _, index := range m.fieldIndexes {
+		*index = fieldIndex{cType: index.cType, field: index.field}
+	}
+}
+
+// indexComponentValue updates the built indexes of the component with its new value on the entities.
+func (m *EntityCommandBuffer) indexComponentValue(
+	cType types.ComponentMetadata, value any, ids ...types.EntityID,
+) error {
+	for _, index := range m.fieldIndexes {
+		if !index.built || index.cType.ID() != cType.ID() {
+			continue
+		}
+		key, err := filter.FieldKey(value, index.field)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			index.set(id, key)
+		}
+	}
+	return nil
+}
+
+// indexDefaultValue updates the built indexes of the component when it is added to the entities with its default
+// value.
+func (m *EntityCommandBuffer) indexDefaultValue(cType types.ComponentMetadata, ids ...types.EntityID) error {
+	if !m.hasBuiltFieldIndex(cType) {
+		return nil
+	}
+	bz, err := cType.New()
+	if err != nil {
+		return err
+	}
+	value, err := cType.Decode(bz)
+	if err != nil {
+		return err
+	}
+	return m.indexComponentValue(cType, value, ids...)
+}
+
+// unindexComponent removes the entity from the built indexes of the component.
+func (m *EntityCommandBuffer) unindexComponent(cType types.ComponentMetadata, id types.EntityID) {
+	for _, index := range m.fieldIndexes {
+		if index.built && index.cType.ID() == cType.ID() {
+			index.remove(id)
+		}
+	}
+}
+
+func (m *EntityCommandBuffer) hasBuiltFieldIndex(cType types.ComponentMetadata) bool {
+	for _, index := range m.fieldIndexes {
+		if index.built && index.cType.ID() == cType.ID() {
+			return true
+		}
+	}
+	return false
+}
+
+func (index *fieldIndex) set(id types.EntityID, key string) {
+	index.remove(id)
+	if index.entities[key] == nil {
+		index.entities[key] = map[types.EntityID]struct{}{}
+	}
+	index.entities[key][id] = struct{}{}
+	index.keys[id] = key
+}
+
+func (index *fieldIndex) remove(id types.EntityID) {
+	key, ok := index.keys[id]
+	if !ok {
+		return
+	}
+	delete(index.entities[key], id)
+	if len(index.entities[key]) == 0 {
+		delete(index.entities, key)
+	}
+	delete(index.keys, id)
+}
@@ -0,0 +1,51 @@
+package gamestate
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+)
+
+// SetFingerprint sets the fingerprint that is saved with every tick that is finalized from now on, so that the stored
+// state records the code that produced it. If genesis is set, the next finalized tick also saves the fingerprint as
+// the fingerprint of the world's genesis.
+func (m *EntityCommandBuffer) SetFingerprint(fingerprint []byte, genesis bool) {
+	m.fingerprint = fingerprint
+	m.saveGenesisFingerprint = genesis
+}
+
+// GetFingerprints returns the fingerprints that were saved with the world's genesis and with the most recently
+// finalized tick. Either is nil if it was never saved.
+func (m *EntityCommandBuffer) GetFingerprints() (genesis, latest []byte, err error) {
+	ctx := context.Background()
+	if genesis, err = m.getFingerprint(ctx, storageGenesisFingerprintKey()); err != nil {
+		return nil, nil, err
+	}
+	if latest, err = m.getFingerprint(ctx, storageFingerprintKey()); err != nil {
+		return nil, nil, err
+	}
+	return genesis, latest, nil
+}
+
+func (m *EntityCommandBuffer) getFingerprint(ctx context.Context, key string) ([]byte, error) {
+	bz, err := m.dbStorage.GetBytes(ctx, key)
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return bz, eris.Wrap(err, "")
+}
+
+func (m *EntityCommandBuffer) addFingerprintToPipe(ctx context.Context, pipe PrimitiveStorage[string]) error {
+	if m.fingerprint == nil {
+		return nil
+	}
+	if err := pipe.Set(ctx, storageFingerprintKey(), m.fingerprint); err != nil {
+		return eris.Wrap(err, "")
+	}
+	if !m.saveGenesisFingerprint {
+		return nil
+	}
+	return eris.Wrap(pipe.Set(ctx, storageGenesisFingerprintKey(), m.fingerprint), "")
+}
@@ -0,0 +1,67 @@
+package gamestate
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+)
+
+// ErrForkedState is returned when a forked state is asked to write to the underlying storage.
+var ErrForkedState = errors.New("forked state can't be committed")
+
+var _ Manager = &forkedBuffer{}
+
+// forkedBuffer is a command buffer whose pending state changes can never be committed to storage.
+type forkedBuffer struct {
+	*EntityCommandBuffer
+}
+
+// Fork returns a copy-on-write view of the committed state. The fork reads committed state from the same storage as
+// m, and keeps its state changes in memory until it is dropped; they are never committed, and are not visible to m.
+// State changes that are pending in m are not visible to the fork.
+func (m *EntityCommandBuffer) Fork() (Manager, error) {
+	if m.typeToComponent == nil {
+		return nil, eris.New("components must be registered before the state can be forked")
+	}
+	fork, err := NewEntityCommandBuffer(m.dbStorage)
+	if err != nil {
+		return nil, err
+	}
+	// Registered components and committed ephemeral values are only changed on commit, which the fork never does,
+	// so both are shared instead of copied.
+	fork.typeToComponent = m.typeToComponent
+	fork.ephemeralValues = m.ephemeralValues
+	fork.limits = m.limits
+	for _, index := range m.fieldIndexes {
+		fork.AddFieldIndex(index.cType, index.field)
+	}
+	if err := fork.loadArchIDs(); err != nil {
+		return nil, err
+	}
+	return &forkedBuffer{fork}, nil
+}
+
+func (f *forkedBuffer) StartNextTick([]types.Message, *txpool.TxPool) error {
+	return eris.Wrap(ErrForkedState, "")
+}
+
+func (f *forkedBuffer) FinalizeTick(context.Context) error {
+	return eris.Wrap(ErrForkedState, "")
+}
+
+func (f *forkedBuffer) Recover([]types.Message) (*txpool.TxPool, error) {
+	return nil, eris.Wrap(ErrForkedState, "")
+}
+
+// QueueTransaction does nothing: transactions that are added to a fork only live in its transaction pool.
+func (f *forkedBuffer) QueueTransaction(types.Message, txpool.TxData) error {
+	return nil
+}
+
+func (f *forkedBuffer) RecoverQueuedTransactions([]types.Message) (*txpool.TxPool, error) {
+	return nil, eris.Wrap(ErrForkedState, "")
+}
@@ -0,0 +1,71 @@
+package gamestate
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/binary"
+	"slices"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// StateHash computes a deterministic hash over every entity and component value visible to the given Reader.
+// Entities are visited in ascending ID order, and each entity's components are visited in ascending component ID
+// order, so two stores that contain the same entities with the same component values always produce the same hash,
+// regardless of the order in which archetypes were created. Ephemeral components are not part of the hash.
+func StateHash(r Reader) ([]byte, error) {
+	type entityArch struct {
+		id     types.EntityID
+		archID types.ArchetypeID
+	}
+
+	var entities []entityArch
+	for i := 0; i < r.ArchetypeCount(); i++ {
+		archID := types.ArchetypeID(i)
+		ids, err := r.GetEntitiesForArchID(archID)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			entities = append(entities, entityArch{id: id, archID: archID})
+		}
+	}
+	slices.SortFunc(entities, func(a, b entityArch) int {
+		return cmp.Compare(a.id, b.id)
+	})
+
+	h := sha256.New()
+	buf := make([]byte, 8) //nolint:gomnd // size of a uint64
+	writeUint64 := func(v uint64) {
+		binary.BigEndian.PutUint64(buf, v)
+		_, _ = h.Write(buf)
+	}
+	for _, e := range entities {
+		comps, err := r.GetComponentTypesForArchID(e.archID)
+		if err != nil {
+			return nil, err
+		}
+		comps = slices.Clone(comps)
+		slices.SortFunc(comps, func(a, b types.ComponentMetadata) int {
+			return cmp.Compare(a.ID(), b.ID())
+		})
+
+		comps = slices.DeleteFunc(comps, func(c types.ComponentMetadata) bool {
+			return c.IsEphemeral()
+		})
+
+		writeUint64(uint64(e.id))
+		writeUint64(uint64(len(comps)))
+		for _, c := range comps {
+			bz, err := r.GetComponentForEntityInRawJSON(c, e.id)
+			if err != nil {
+				return nil, err
+			}
+			writeUint64(uint64(len(c.Name())))
+			_, _ = h.Write([]byte(c.Name()))
+			writeUint64(uint64(len(bz)))
+			_, _ = h.Write(bz)
+		}
+	}
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,53 @@
+package gamestate_test
+
+import (
+	"context"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+)
+
+func TestStateHashIgnoresArchetypeCreationOrder(t *testing.T) {
+	ctx := context.Background()
+
+	first := newCmdBufferForTest(t)
+	_, err := first.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	_, err = first.CreateEntity(barComp)
+	assert.NilError(t, err)
+	assert.NilError(t, first.FinalizeTick(ctx))
+
+	// The second buffer ends up with the same state, but creates an extra archetype along the way.
+	second := newCmdBufferForTest(t)
+	id, err := second.CreateEntity(fooComp, barComp)
+	assert.NilError(t, err)
+	assert.NilError(t, second.RemoveComponentFromEntity(barComp, id))
+	_, err = second.CreateEntity(barComp)
+	assert.NilError(t, err)
+	assert.NilError(t, second.FinalizeTick(ctx))
+
+	firstHash, err := gamestate.StateHash(first)
+	assert.NilError(t, err)
+	secondHash, err := gamestate.StateHash(second)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, firstHash, secondHash)
+}
+
+func TestStateHashChangesWhenComponentValueChanges(t *testing.T) {
+	ctx := context.Background()
+	manager := newCmdBufferForTest(t)
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	before, err := gamestate.StateHash(manager)
+	assert.NilError(t, err)
+
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{Value: 100}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	after, err := gamestate.StateHash(manager)
+	assert.NilError(t, err)
+	assert.NotEqual(t, before, after)
+}
@@ -0,0 +1,70 @@
+package gamestate
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+)
+
+// SetIndexEntries stages entries of the named lookup index, which are saved with the next finalized tick. An entry
+// with a nil value is deleted. Indexes hold data that is derived from the state, such as the entity of each persona
+// tag, so that it can be looked up without scanning entities, even before the first tick after a restart.
+func (m *EntityCommandBuffer) SetIndexEntries(index string, entries map[string][]byte) {
+	if len(entries) == 0 {
+		return
+	}
+	if m.indexEntries == nil {
+		m.indexEntries = map[string]map[string][]byte{}
+	}
+	staged, ok := m.indexEntries[index]
+	if !ok {
+		staged = make(map[string][]byte, len(entries))
+		m.indexEntries[index] = staged
+	}
+	for key, value := range entries {
+		staged[key] = value
+	}
+}
+
+// GetIndexEntry returns the value of the entry of the named lookup index as of the last finalized tick, or nil if
+// the index has no such entry.
+func (m *EntityCommandBuffer) GetIndexEntry(index, key string) ([]byte, error) {
+	bz, err := m.dbStorage.GetBytes(context.Background(), storageIndexKey(index, key))
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return bz, eris.Wrap(err, "")
+}
+
+// addIndexEntriesToPipe saves the staged index entries. They are saved in order, so that the undo log of the commit
+// is the same on every node.
+func (m *EntityCommandBuffer) addIndexEntriesToPipe(ctx context.Context, pipe PrimitiveStorage[string]) error {
+	indexes := make([]string, 0, len(m.indexEntries))
+	for index := range m.indexEntries {
+		indexes = append(indexes, index)
+	}
+	sort.Strings(indexes)
+	for _, index := range indexes {
+		entries := m.indexEntries[index]
+		keys := make([]string, 0, len(entries))
+		for key := range entries {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			var err error
+			if value := entries[key]; value == nil {
+				err = pipe.Delete(ctx, storageIndexKey(index, key))
+			} else {
+				err = pipe.Set(ctx, storageIndexKey(index, key), value)
+			}
+			if err != nil {
+				return eris.Wrap(err, "")
+			}
+		}
+	}
+	return nil
+}
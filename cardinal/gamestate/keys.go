@@ -47,6 +47,37 @@ func storageEndTickKey() string {
 	return "ECB:END-TICK"
 }
 
+// storageFingerprintKey is the key of the fingerprint of the code that finalized the most recent tick.
+func storageFingerprintKey() string {
+	return "ECB:FINGERPRINT"
+}
+
+// storageGenesisFingerprintKey is the key of the fingerprint of the code that finalized the world's first tick.
+func storageGenesisFingerprintKey() string {
+	return "ECB:GENESIS-FINGERPRINT"
+}
+
+// storageTickLogKey is the key of the events that were emitted in the tick; see SetTickLogRetention.
+func storageTickLogKey(tick uint64) string {
+	return fmt.Sprintf("ECB:TICK-LOG:%d", tick)
+}
+
+// storageTickLogStartKey is the key of the first tick whose events are kept in the tick log.
+func storageTickLogStartKey() string {
+	return "ECB:TICK-LOG-START"
+}
+
+// storageIndexKey is the key of an entry of a lookup index; see SetIndexEntries.
+func storageIndexKey(index, key string) string {
+	return fmt.Sprintf("ECB:INDEX:%s:%s", index, key)
+}
+
 func storagePendingTransactionKey() string {
 	return "ECB:PENDING-TRANSACTIONS"
 }
+
+// storageQueuedTransactionsKey is the key of the list of transactions that have been accepted, but have not yet been
+// executed in a tick. The list is ordered by the time the transactions were accepted.
+func storageQueuedTransactionsKey() string {
+	return "ECB:QUEUED-TRANSACTIONS"
+}
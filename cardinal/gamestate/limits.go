@@ -0,0 +1,90 @@
+package gamestate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// ErrLimitExceeded is wrapped by every LimitError, so eris.Is(err, ErrLimitExceeded) reports whether a state change
+// was rejected because of a world limit.
+var ErrLimitExceeded = errors.New("world limit exceeded")
+
+// Limit names a world limit.
+type Limit string
+
+const (
+	LimitEntities             Limit = "entities"
+	LimitComponentsPerEntity  Limit = "components per entity"
+	LimitComponentPayloadSize Limit = "component payload size"
+)
+
+// Limits caps the size of the game state, so that runaway game logic can't exhaust the storage that it shares with
+// other worlds. A limit of zero means unlimited.
+type Limits struct {
+	// MaxEntities is the maximum number of entities that exist at the same time.
+	MaxEntities int
+	// MaxComponentsPerEntity is the maximum number of components on a single entity.
+	MaxComponentsPerEntity int
+	// MaxComponentPayloadSize is the maximum size, in bytes, of a JSON encoded component value.
+	MaxComponentPayloadSize int
+}
+
+// LimitError is returned when a state change would exceed one of the world's Limits. The state is not changed.
+type LimitError struct {
+	Limit Limit
+	Max   int
+	// Actual is what the value would have been if the state change was made.
+	Actual int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: %s would be %d, the maximum is %d", ErrLimitExceeded, e.Limit, e.Actual, e.Max)
+}
+
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+func checkLimit(limit Limit, maxValue, actual int) error {
+	if maxValue > 0 && actual > maxValue {
+		return eris.Wrap(&LimitError{Limit: limit, Max: maxValue, Actual: actual}, "")
+	}
+	return nil
+}
+
+// SetLimits sets the limits that are enforced on later state changes. State that already exceeds the limits is kept.
+func (m *EntityCommandBuffer) SetLimits(limits Limits) {
+	m.limits = limits
+}
+
+func (m *EntityCommandBuffer) checkComponentPayloadSize(cType types.ComponentMetadata, value any) error {
+	if m.limits.MaxComponentPayloadSize <= 0 {
+		return nil
+	}
+	bz, err := codec.Encode(value)
+	if err != nil {
+		return err
+	}
+	return eris.Wrapf(
+		checkLimit(LimitComponentPayloadSize, m.limits.MaxComponentPayloadSize, len(bz)),
+		"component %q", cType.Name(),
+	)
+}
+
+// entityCount returns the number of entities, including the ones that were created or removed since the last commit.
+func (m *EntityCommandBuffer) entityCount() (int, error) {
+	count := 0
+	for archID := 0; archID < m.ArchetypeCount(); archID++ {
+		active, err := m.getActiveEntities(types.ArchetypeID(archID))
+		if err != nil {
+			return 0, err
+		}
+		count += len(active.ids)
+	}
+	return count, nil
+}
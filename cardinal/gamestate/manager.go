@@ -27,6 +27,11 @@ type Reader interface {
 
 	// Misc
 	SearchFrom(filter filter.ComponentFilter, start int) *iterators.ArchetypeIterator
+	// FindArchetypes returns the IDs of the archetypes that match the filter.
+	FindArchetypes(filter filter.ComponentFilter) []types.ArchetypeID
+	// LookupFieldIndex returns the IDs, in ascending order, of the entities whose field of the component is indexed
+	// under the key. False is returned if the field has no index.
+	LookupFieldIndex(component, field, key string) ([]types.EntityID, bool, error)
 	ArchetypeCount() int
 }
 
@@ -46,6 +51,12 @@ type Writer interface {
 	// Misc
 	Close() error
 	RegisterComponents([]types.ComponentMetadata) error
+	// SetLimits sets the limits that later state changes are checked against.
+	SetLimits(limits Limits)
+	// SetCompressionThreshold sets the size, in bytes, above which component values are stored compressed.
+	SetCompressionThreshold(threshold int) error
+	// AddFieldIndex adds an index of the field with the given name of the component.
+	AddFieldIndex(cType types.ComponentMetadata, field string)
 }
 
 type TickStorage interface {
@@ -53,6 +64,28 @@ type TickStorage interface {
 	StartNextTick(txs []types.Message, pool *txpool.TxPool) error
 	FinalizeTick(ctx context.Context) error
 	Recover(txs []types.Message) (*txpool.TxPool, error)
+	QueueTransaction(msg types.Message, txData txpool.TxData) error
+	RecoverQueuedTransactions(txs []types.Message) (*txpool.TxPool, error)
+	// SetRewindLimit sets the number of the most recent commits that Rewind can undo.
+	SetRewindLimit(commits int)
+	// Rewind undoes the most recent commits, including the tick numbers that they ended.
+	Rewind(commits int) error
+	// SetFingerprint sets the fingerprint of the code that is saved with every finalized tick.
+	SetFingerprint(fingerprint []byte, genesis bool)
+	// GetFingerprints returns the fingerprints that were saved with the world's genesis and its most recent tick.
+	GetFingerprints() (genesis, latest []byte, err error)
+	// SetTickLogRetention sets the number of the most recent ticks whose events are kept in the tick log.
+	SetTickLogRetention(ticks int)
+	// SetTickEvents sets the events that are saved in the tick log with the next finalized tick.
+	SetTickEvents(tick uint64, events [][]byte)
+	// GetTickLogRange returns the first tick whose events are kept, and the tick after the last finalized one.
+	GetTickLogRange() (first, end uint64, err error)
+	// GetTickEvents returns the events that were saved in the tick log with the tick.
+	GetTickEvents(tick uint64) ([][]byte, error)
+	// SetIndexEntries sets entries of a lookup index that are saved with the next finalized tick.
+	SetIndexEntries(index string, entries map[string][]byte)
+	// GetIndexEntry returns an entry of a lookup index as of the last finalized tick.
+	GetIndexEntry(index, key string) ([]byte, error)
 }
 
 // Manager represents all the methods required to track Component, Entity, and Archetype information
@@ -62,4 +95,16 @@ type Manager interface {
 	Reader
 	Writer
 	ToReadOnly() Reader
+	// GetChangedEntities returns the IDs, in ascending order, of entities whose value for the given component was set
+	// or added since the last time the pending state was committed or discarded.
+	GetChangedEntities(cType types.ComponentMetadata) ([]types.EntityID, error)
+	// GetPendingChanges returns how the components of entities changed since the pending state was last committed or
+	// discarded.
+	GetPendingChanges() ([]EntityChange, error)
+	// DiscardPending discards the state changes that were made since the pending state was last committed.
+	DiscardPending() error
+	// Prefetch loads the values of the components, for every entity that has them, ahead of the reads that need them.
+	Prefetch(comps []types.ComponentMetadata) error
+	// Fork returns a copy-on-write view of the committed state whose changes are never committed.
+	Fork() (Manager, error)
 }
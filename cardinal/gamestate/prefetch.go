@@ -0,0 +1,136 @@
+package gamestate
+
+import (
+	"context"
+
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// prefetchBatchSize is the number of component values that Prefetch reads from storage per round trip.
+const prefetchBatchSize = 1024
+
+// Prefetch loads the committed values of the given components, for every entity that has them, so that later reads
+// of the values don't go to storage one at a time. Committed values are kept across ticks, and are replaced when a
+// commit writes them, so only the values that no earlier tick read or wrote are read from storage. Prefetching never
+// changes what a read returns.
+func (m *EntityCommandBuffer) Prefetch(comps []types.ComponentMetadata) error {
+	var keys []compKey
+	for archID := 0; archID < m.archIDToComps.Len(); archID++ {
+		archComps, err := m.archIDToComps.Get(types.ArchetypeID(archID))
+		if err != nil {
+			return err
+		}
+		var wanted []types.ComponentMetadata
+		for _, cType := range comps {
+			if !cType.IsEphemeral() && filter.MatchComponentMetadata(archComps, cType) {
+				wanted = append(wanted, cType)
+			}
+		}
+		if len(wanted) == 0 {
+			continue
+		}
+		ids, err := m.GetEntitiesForArchID(types.ArchetypeID(archID))
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			for _, cType := range wanted {
+				key := compKey{cType.ID(), id}
+				if _, ok := m.committedValues.get(key); ok {
+					continue
+				}
+				if _, err := m.compValues.Get(key); err != nil {
+					keys = append(keys, key)
+				}
+			}
+		}
+	}
+
+	for start := 0; start < len(keys); start += prefetchBatchSize {
+		if err := m.prefetchValues(keys[start:min(start+prefetchBatchSize, len(keys))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prefetchValues reads the committed values of the keys in one round trip, and caches them for
+// GetComponentForEntity, which decodes them when they are read.
+func (m *EntityCommandBuffer) prefetchValues(keys []compKey) error {
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		redisKeys[i] = storageComponentKey(key.typeID, key.entityID)
+	}
+	values, err := m.dbStorage.GetManyBytes(context.Background(), redisKeys)
+	if err != nil {
+		return err
+	}
+	for i, key := range keys {
+		bz := values[i]
+		if bz == nil {
+			// This value has never been set. Make a default value.
+			cType, err := m.typeToComponent.Get(key.typeID)
+			if err != nil {
+				return err
+			}
+			if bz, err = cType.New(); err != nil {
+				return err
+			}
+		}
+		if bz, err = decompressComponent(bz); err != nil {
+			return err
+		}
+		m.committedValues.set(key, bz)
+	}
+	return nil
+}
+
+// committedCache holds the encoded values that components had as of the last commit. The changes that a commit makes
+// are staged while its pipe is built, and only take effect once the pipe is executed, so that a failed commit leaves
+// the cache as it was. Only the goroutine that ticks uses the cache.
+type committedCache struct {
+	values map[compKey][]byte
+	// staged holds the values that the commit being built writes. Deleted values are nil.
+	staged map[compKey][]byte
+}
+
+func newCommittedCache() *committedCache {
+	return &committedCache{
+		values: map[compKey][]byte{},
+		staged: map[compKey][]byte{},
+	}
+}
+
+func (c *committedCache) get(key compKey) ([]byte, bool) {
+	bz, ok := c.values[key]
+	return bz, ok
+}
+
+func (c *committedCache) set(key compKey, bz []byte) {
+	c.values[key] = bz
+}
+
+func (c *committedCache) stage(key compKey, bz []byte) {
+	c.staged[key] = bz
+}
+
+func (c *committedCache) commitStaged() {
+	for key, bz := range c.staged {
+		if bz == nil {
+			delete(c.values, key)
+		} else {
+			c.values[key] = bz
+		}
+	}
+	clear(c.staged)
+}
+
+func (c *committedCache) discardStaged() {
+	clear(c.staged)
+}
+
+func (c *committedCache) clear() {
+	clear(c.values)
+	clear(c.staged)
+}
@@ -0,0 +1,85 @@
+package gamestate_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+func TestPrefetchedComponentsAreReadWithoutGoingToStorage(t *testing.T) {
+	manager, client := newCmdBufferAndRedisClientForTest(t, nil)
+	ctx := context.Background()
+
+	ids, err := manager.CreateManyEntities(3, fooComp, barComp)
+	assert.NilError(t, err)
+	for i, id := range ids {
+		assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{i}))
+		assert.NilError(t, manager.SetComponentForEntity(barComp, id, Bar{i}))
+	}
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// A fresh command buffer has nothing loaded yet.
+	manager, _ = newCmdBufferAndRedisClientForTest(t, client)
+	assert.NilError(t, manager.Prefetch([]types.ComponentMetadata{fooComp}))
+
+	// Change the stored values behind the command buffer's back. Only values that weren't prefetched are read again.
+	for _, id := range ids {
+		for _, cType := range []types.ComponentMetadata{fooComp, barComp} {
+			key := fmt.Sprintf("ECB:COMPONENT-VALUE:TYPE-ID-%d:ENTITY-ID-%d", cType.ID(), id)
+			assert.NilError(t, client.Set(ctx, key, `{"Value":99}`, 0).Err())
+		}
+	}
+	for i, id := range ids {
+		foo, err := manager.GetComponentForEntity(fooComp, id)
+		assert.NilError(t, err)
+		assert.Equal(t, Foo{i}, foo)
+		bar, err := manager.GetComponentForEntity(barComp, id)
+		assert.NilError(t, err)
+		assert.Equal(t, Bar{99}, bar)
+	}
+}
+
+func TestPrefetchKeepsPendingValues(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	ctx := context.Background()
+
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{1}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{2}))
+	assert.NilError(t, manager.Prefetch([]types.ComponentMetadata{fooComp}))
+	foo, err := manager.GetComponentForEntity(fooComp, id)
+	assert.NilError(t, err)
+	assert.Equal(t, Foo{2}, foo)
+}
+
+func TestPrefetchKeepsCommittedValuesAcrossTicks(t *testing.T) {
+	manager, client := newCmdBufferAndRedisClientForTest(t, nil)
+	ctx := context.Background()
+
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{1}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+
+	// The value that was committed is kept, so the next tick doesn't read it from storage again.
+	key := fmt.Sprintf("ECB:COMPONENT-VALUE:TYPE-ID-%d:ENTITY-ID-%d", fooComp.ID(), id)
+	assert.NilError(t, client.Set(ctx, key, `{"Value":99}`, 0).Err())
+	assert.NilError(t, manager.Prefetch([]types.ComponentMetadata{fooComp}))
+	foo, err := manager.GetComponentForEntity(fooComp, id)
+	assert.NilError(t, err)
+	assert.Equal(t, Foo{1}, foo)
+
+	// A commit replaces the kept value.
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{2}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	assert.NilError(t, manager.Prefetch([]types.ComponentMetadata{fooComp}))
+	foo, err = manager.GetComponentForEntity(fooComp, id)
+	assert.NilError(t, err)
+	assert.Equal(t, Foo{2}, foo)
+}
@@ -0,0 +1,144 @@
+package gamestate
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rotisserie/eris"
+)
+
+var _ PrimitiveStorage[string] = &PrefixedStorage{}
+
+// PrefixedStorage scopes a PrimitiveStorage to the keys that start with a prefix. Keys passed to and returned from
+// PrefixedStorage do not include the prefix, so users of a PrefixedStorage can neither see nor modify keys outside
+// of it.
+type PrefixedStorage struct {
+	storage PrimitiveStorage[string]
+	prefix  string
+}
+
+func NewPrefixedStorage(storage PrimitiveStorage[string], prefix string) *PrefixedStorage {
+	return &PrefixedStorage{
+		storage: storage,
+		prefix:  prefix,
+	}
+}
+
+func (p *PrefixedStorage) key(key string) string {
+	return p.prefix + key
+}
+
+func (p *PrefixedStorage) GetFloat64(ctx context.Context, key string) (float64, error) {
+	return p.storage.GetFloat64(ctx, p.key(key))
+}
+
+func (p *PrefixedStorage) GetFloat32(ctx context.Context, key string) (float32, error) {
+	return p.storage.GetFloat32(ctx, p.key(key))
+}
+
+func (p *PrefixedStorage) GetUInt64(ctx context.Context, key string) (uint64, error) {
+	return p.storage.GetUInt64(ctx, p.key(key))
+}
+
+func (p *PrefixedStorage) GetInt64(ctx context.Context, key string) (int64, error) {
+	return p.storage.GetInt64(ctx, p.key(key))
+}
+
+func (p *PrefixedStorage) GetInt(ctx context.Context, key string) (int, error) {
+	return p.storage.GetInt(ctx, p.key(key))
+}
+
+func (p *PrefixedStorage) GetBool(ctx context.Context, key string) (bool, error) {
+	return p.storage.GetBool(ctx, p.key(key))
+}
+
+func (p *PrefixedStorage) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	return p.storage.GetBytes(ctx, p.key(key))
+}
+
+func (p *PrefixedStorage) GetManyBytes(ctx context.Context, keys []string) ([][]byte, error) {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = p.key(key)
+	}
+	return p.storage.GetManyBytes(ctx, prefixed)
+}
+
+func (p *PrefixedStorage) Get(ctx context.Context, key string) (any, error) {
+	return p.storage.Get(ctx, p.key(key))
+}
+
+func (p *PrefixedStorage) Set(ctx context.Context, key string, value any) error {
+	return p.storage.Set(ctx, p.key(key), value)
+}
+
+func (p *PrefixedStorage) Incr(ctx context.Context, key string) error {
+	return p.storage.Incr(ctx, p.key(key))
+}
+
+func (p *PrefixedStorage) Decr(ctx context.Context, key string) error {
+	return p.storage.Decr(ctx, p.key(key))
+}
+
+func (p *PrefixedStorage) Delete(ctx context.Context, key string) error {
+	return p.storage.Delete(ctx, p.key(key))
+}
+
+func (p *PrefixedStorage) ListAppend(ctx context.Context, key string, value []byte) error {
+	return p.storage.ListAppend(ctx, p.key(key), value)
+}
+
+func (p *PrefixedStorage) ListRange(ctx context.Context, key string) ([][]byte, error) {
+	return p.storage.ListRange(ctx, p.key(key))
+}
+
+func (p *PrefixedStorage) ListTrimFront(ctx context.Context, key string, count int) error {
+	return p.storage.ListTrimFront(ctx, p.key(key), count)
+}
+
+// StartTransaction starts a transaction on the underlying storage. The transaction is scoped to the same prefix.
+func (p *PrefixedStorage) StartTransaction(ctx context.Context) (Transaction[string], error) {
+	tx, err := p.storage.StartTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrefixedStorage(tx, p.prefix), nil
+}
+
+func (p *PrefixedStorage) EndTransaction(ctx context.Context) error {
+	return p.storage.EndTransaction(ctx)
+}
+
+// Close is a no-op. The underlying storage is shared, so it is closed by its owner.
+func (p *PrefixedStorage) Close(_ context.Context) error {
+	return nil
+}
+
+// Clear deletes every key with this storage's prefix. Keys outside of the prefix are not affected.
+func (p *PrefixedStorage) Clear(ctx context.Context) error {
+	keys, err := p.Keys(ctx)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := p.Delete(ctx, key); err != nil {
+			return eris.Wrapf(err, "failed to delete key %q", key)
+		}
+	}
+	return nil
+}
+
+// Keys returns the keys with this storage's prefix, with the prefix removed.
+func (p *PrefixedStorage) Keys(ctx context.Context) ([]string, error) {
+	allKeys, err := p.storage.Keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, key := range allKeys {
+		if rest, ok := strings.CutPrefix(key, p.prefix); ok {
+			keys = append(keys, rest)
+		}
+	}
+	return keys, nil
+}
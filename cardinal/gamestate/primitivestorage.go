@@ -14,11 +14,20 @@ type PrimitiveStorage[K comparable] interface {
 	GetInt(ctx context.Context, key K) (int, error)
 	GetBool(ctx context.Context, key K) (bool, error)
 	GetBytes(ctx context.Context, key K) ([]byte, error)
+	// GetManyBytes returns the values stored at the keys, in order, in one round trip. The value of a key that isn't
+	// set is nil.
+	GetManyBytes(ctx context.Context, keys []K) ([][]byte, error)
 	Get(ctx context.Context, key K) (any, error)
 	Set(ctx context.Context, key K, value any) error
 	Incr(ctx context.Context, key K) error
 	Decr(ctx context.Context, key K) error
 	Delete(ctx context.Context, key K) error
+	// ListAppend appends the value to the end of the list stored at key.
+	ListAppend(ctx context.Context, key K, value []byte) error
+	// ListRange returns all the values of the list stored at key, in order.
+	ListRange(ctx context.Context, key K) ([][]byte, error)
+	// ListTrimFront removes the first count values of the list stored at key.
+	ListTrimFront(ctx context.Context, key K, count int) error
 	StartTransaction(ctx context.Context) (Transaction[K], error)
 	EndTransaction(ctx context.Context) error
 	Close(ctx context.Context) error
@@ -23,6 +23,7 @@ type readOnlyManager struct {
 	storage         PrimitiveStorage[string]
 	typeToComponent VolatileStorage[types.ComponentID, types.ComponentMetadata]
 	archIDToComps   VolatileStorage[types.ArchetypeID, []types.ComponentMetadata]
+	ephemeralValues *ephemeralStore
 }
 
 func (m *EntityCommandBuffer) ToReadOnly() Reader {
@@ -30,6 +31,7 @@ func (m *EntityCommandBuffer) ToReadOnly() Reader {
 		storage:         m.dbStorage,
 		typeToComponent: m.typeToComponent,
 		archIDToComps:   m.archIDToComps,
+		ephemeralValues: m.ephemeralValues,
 	}
 }
 
@@ -60,10 +62,19 @@ func (r *readOnlyManager) GetComponentForEntity(
 func (r *readOnlyManager) GetComponentForEntityInRawJSON(
 	cType types.ComponentMetadata, id types.EntityID,
 ) (json.RawMessage, error) {
+	if cType.IsEphemeral() {
+		if bz, ok := r.ephemeralValues.get(compKey{cType.ID(), id}); ok {
+			return bz, nil
+		}
+		return cType.New()
+	}
 	ctx := context.Background()
 	key := storageComponentKey(cType.ID(), id)
 	res, err := r.storage.GetBytes(ctx, key)
-	return res, eris.Wrap(err, "")
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	return decompressComponent(res)
 }
 
 func (r *readOnlyManager) getComponentsForArchID(archID types.ArchetypeID) ([]types.ComponentMetadata, error) {
@@ -173,3 +184,9 @@ func (r *readOnlyManager) ArchetypeCount() int {
 	}
 	return r.archIDToComps.Len()
 }
+
+// LookupFieldIndex always returns false: the field indexes hold the pending state, which read only managers don't
+// see, so searches check every entity instead.
+func (r *readOnlyManager) LookupFieldIndex(string, string, string) ([]types.EntityID, bool, error) {
+	return nil, false, nil
+}
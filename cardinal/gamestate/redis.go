@@ -72,6 +72,20 @@ func (r *RedisStorage) GetBytes(ctx context.Context, key string) ([]byte, error)
 	return bz, nil
 }
 
+func (r *RedisStorage) GetManyBytes(ctx context.Context, keys []string) ([][]byte, error) {
+	res, err := r.currentClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	values := make([][]byte, len(res))
+	for i, v := range res {
+		if str, ok := v.(string); ok {
+			values[i] = []byte(str)
+		}
+	}
+	return values, nil
+}
+
 func (r *RedisStorage) Set(ctx context.Context, key string, value any) error {
 	return eris.Wrap(r.currentClient.Set(ctx, key, value, 0).Err(), "")
 }
@@ -96,6 +110,26 @@ func (r *RedisStorage) Delete(ctx context.Context, key string) error {
 	return eris.Wrap(r.currentClient.Del(ctx, key).Err(), "")
 }
 
+func (r *RedisStorage) ListAppend(ctx context.Context, key string, value []byte) error {
+	return eris.Wrap(r.currentClient.RPush(ctx, key, value).Err(), "")
+}
+
+func (r *RedisStorage) ListRange(ctx context.Context, key string) ([][]byte, error) {
+	res, err := r.currentClient.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	values := make([][]byte, 0, len(res))
+	for _, v := range res {
+		values = append(values, []byte(v))
+	}
+	return values, nil
+}
+
+func (r *RedisStorage) ListTrimFront(ctx context.Context, key string, count int) error {
+	return eris.Wrap(r.currentClient.LTrim(ctx, key, int64(count), -1).Err(), "")
+}
+
 func (r *RedisStorage) Close(ctx context.Context) error {
 	return eris.Wrap(r.currentClient.Shutdown(ctx).Err(), "")
 }
@@ -136,6 +170,9 @@ func (m *EntityCommandBuffer) makePipeOfRedisCommands(ctx context.Context) (Prim
 	if err != nil {
 		return nil, err
 	}
+	if m.undo != nil {
+		pipe = &undoRecorder{Transaction: pipe, db: m.dbStorage, log: newUndoLog()}
+	}
 
 	if m.typeToComponent == nil {
 		// component.ComponentID -> ComponentMetadata mappings are required to serialized data for the DB
@@ -211,8 +248,10 @@ func (m *EntityCommandBuffer) addNextEntityIDToPipe(ctx context.Context, pipe Pr
 	return eris.Wrap(pipe.Set(ctx, key, nextID), "")
 }
 
-// addComponentChangesToPipe adds updated component values for entities to the redis pipe.
+// addComponentChangesToPipe adds updated component values for entities to the redis pipe. The changes are staged in
+// the committed value cache, which takes them once the pipe is executed.
 func (m *EntityCommandBuffer) addComponentChangesToPipe(ctx context.Context, pipe PrimitiveStorage[string]) error {
+	m.committedValues.discardStaged()
 	keysToDelete, err := m.compValuesToDelete.Keys()
 	if err != nil {
 		return err
@@ -225,10 +264,20 @@ func (m *EntityCommandBuffer) addComponentChangesToPipe(ctx context.Context, pip
 		if !isMarkedForDeletion {
 			continue
 		}
+		cType, err := m.typeToComponent.Get(key.typeID)
+		if err != nil {
+			return err
+		}
+		if cType.IsEphemeral() {
+			undoLogOf(pipe).recordEphemeral(m.ephemeralValues, key)
+			m.ephemeralValues.delete(key)
+			continue
+		}
 		redisKey := storageComponentKey(key.typeID, key.entityID)
 		if err := pipe.Delete(ctx, redisKey); err != nil {
 			return eris.Wrap(err, "")
 		}
+		m.committedValues.stage(key, nil)
 	}
 	if err = m.compValuesToDelete.Clear(); err != nil {
 		return eris.Wrap(err, "failed to clear to-be-deleted component values store")
@@ -237,6 +286,7 @@ func (m *EntityCommandBuffer) addComponentChangesToPipe(ctx context.Context, pip
 	if err != nil {
 		return err
 	}
+	stats := compressionStats{}
 	for _, key := range keys {
 		cType, err := m.typeToComponent.Get(key.typeID)
 		if err != nil {
@@ -250,12 +300,23 @@ func (m *EntityCommandBuffer) addComponentChangesToPipe(ctx context.Context, pip
 		if err != nil {
 			return err
 		}
+		if cType.IsEphemeral() {
+			undoLogOf(pipe).recordEphemeral(m.ephemeralValues, key)
+			m.ephemeralValues.set(key, bz)
+			continue
+		}
 
+		stored := m.compression.compress(bz)
+		if m.compression != nil {
+			stats.add(cType, bz, stored)
+		}
 		redisKey := storageComponentKey(key.typeID, key.entityID)
-		if err = pipe.Set(ctx, redisKey, bz); err != nil {
+		if err = pipe.Set(ctx, redisKey, stored); err != nil {
 			return eris.Wrap(err, "")
 		}
+		m.committedValues.stage(key, bz)
 	}
+	stats.emit()
 	return nil
 }
 
@@ -273,6 +334,7 @@ func (m *EntityCommandBuffer) loadArchIDs() error {
 		return eris.New("assigned archetype ArchetypeID is about to be overwritten by something from dbStorage")
 	}
 	m.archIDToComps = archIDToComps
+	m.archMatches = nil
 	return nil
 }
 
@@ -0,0 +1,218 @@
+package gamestate
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// priorValue is the value that a key had before a commit. exists is false if the key didn't exist.
+type priorValue struct {
+	bz     []byte
+	exists bool
+}
+
+// undoLog holds the values that a commit overwrote, so that the commit can be undone.
+type undoLog struct {
+	values    map[string]priorValue
+	ephemeral map[compKey]priorValue
+}
+
+// undoHistory holds the undo logs of the most recent commits, oldest first.
+type undoHistory struct {
+	limit int
+	logs  []*undoLog
+}
+
+func (h *undoHistory) add(log *undoLog) {
+	h.logs = append(h.logs, log)
+	if len(h.logs) > h.limit {
+		h.logs = h.logs[len(h.logs)-h.limit:]
+	}
+}
+
+func newUndoLog() *undoLog {
+	return &undoLog{
+		values:    map[string]priorValue{},
+		ephemeral: map[compKey]priorValue{},
+	}
+}
+
+// undoRecorder is a transaction that records the committed value of each key before the transaction first writes it.
+// Only the writes that FinalizeTick makes are recorded.
+type undoRecorder struct {
+	Transaction[string]
+	db  PrimitiveStorage[string]
+	log *undoLog
+}
+
+// undoLogOf returns the undo log that the pipe records, or nil if the pipe doesn't record one.
+func undoLogOf(pipe PrimitiveStorage[string]) *undoLog {
+	if r, ok := pipe.(*undoRecorder); ok {
+		return r.log
+	}
+	return nil
+}
+
+func (r *undoRecorder) record(ctx context.Context, key string) error {
+	if _, ok := r.log.values[key]; ok {
+		return nil
+	}
+	bz, err := r.db.GetBytes(ctx, key)
+	err = eris.Wrap(err, "")
+	if eris.Is(eris.Cause(err), redis.Nil) {
+		r.log.values[key] = priorValue{}
+		return nil
+	} else if err != nil {
+		return err
+	}
+	r.log.values[key] = priorValue{bz: bz, exists: true}
+	return nil
+}
+
+func (r *undoRecorder) Set(ctx context.Context, key string, value any) error {
+	if err := r.record(ctx, key); err != nil {
+		return err
+	}
+	return r.Transaction.Set(ctx, key, value)
+}
+
+func (r *undoRecorder) Incr(ctx context.Context, key string) error {
+	if err := r.record(ctx, key); err != nil {
+		return err
+	}
+	return r.Transaction.Incr(ctx, key)
+}
+
+func (r *undoRecorder) Decr(ctx context.Context, key string) error {
+	if err := r.record(ctx, key); err != nil {
+		return err
+	}
+	return r.Transaction.Decr(ctx, key)
+}
+
+func (r *undoRecorder) Delete(ctx context.Context, key string) error {
+	if err := r.record(ctx, key); err != nil {
+		return err
+	}
+	return r.Transaction.Delete(ctx, key)
+}
+
+// recordEphemeral records the committed value of an ephemeral component before the commit changes it. Ephemeral
+// values aren't stored in the DB, so the undo recorder doesn't see them.
+func (l *undoLog) recordEphemeral(store *ephemeralStore, key compKey) {
+	if l == nil {
+		return
+	}
+	if _, ok := l.ephemeral[key]; ok {
+		return
+	}
+	bz, ok := store.get(key)
+	l.ephemeral[key] = priorValue{bz: bz, exists: ok}
+}
+
+// SetRewindLimit makes the buffer keep what it needs to undo its most recent commits with Rewind, up to the given
+// number of commits. This makes every commit read the values that it overwrites first. A limit of zero disables
+// rewinding, and drops the commits that were kept.
+func (m *EntityCommandBuffer) SetRewindLimit(commits int) {
+	if commits <= 0 {
+		m.undo = nil
+		return
+	}
+	if m.undo == nil {
+		m.undo = &undoHistory{}
+	}
+	m.undo.limit = commits
+	if len(m.undo.logs) > commits {
+		m.undo.logs = m.undo.logs[len(m.undo.logs)-commits:]
+	}
+}
+
+// RewindableCommits returns the number of the most recent commits that Rewind can undo.
+func (m *EntityCommandBuffer) RewindableCommits() int {
+	if m.undo == nil {
+		return 0
+	}
+	return len(m.undo.logs)
+}
+
+// Rewind discards the pending state changes, and undoes the most recent commits, which restores the state, and the
+// tick numbers, to what they were before the commits. The transactions that were saved for the undone ticks are not
+// restored.
+func (m *EntityCommandBuffer) Rewind(commits int) error {
+	if commits > m.RewindableCommits() {
+		return eris.Errorf("can't rewind %d commits, only the last %d are kept", commits, m.RewindableCommits())
+	}
+	if commits <= 0 {
+		return nil
+	}
+	if err := m.DiscardPending(); err != nil {
+		return err
+	}
+	// The cached committed values may have been changed by the undone commits.
+	m.committedValues.clear()
+
+	undone := m.undo.logs[len(m.undo.logs)-commits:]
+	values := map[string]priorValue{}
+	ephemeral := map[compKey]priorValue{}
+	// The oldest commit holds the values from before all the undone commits.
+	for i := len(undone) - 1; i >= 0; i-- {
+		for key, v := range undone[i].values {
+			values[key] = v
+		}
+		for key, v := range undone[i].ephemeral {
+			ephemeral[key] = v
+		}
+	}
+
+	ctx := context.Background()
+	pipe, err := m.dbStorage.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+	for key, v := range values {
+		if v.exists {
+			err = pipe.Set(ctx, key, v.bz)
+		} else {
+			err = pipe.Delete(ctx, key)
+		}
+		if err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+	// A completed tick starts and ends at the same number.
+	if end := values[storageEndTickKey()]; end.exists {
+		err = pipe.Set(ctx, storageStartTickKey(), end.bz)
+	} else {
+		err = pipe.Delete(ctx, storageStartTickKey())
+	}
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+	if err = pipe.EndTransaction(ctx); err != nil {
+		return eris.Wrap(err, "")
+	}
+	m.undo.logs = m.undo.logs[:len(m.undo.logs)-commits]
+
+	for key, v := range ephemeral {
+		if v.exists {
+			m.ephemeralValues.set(key, v.bz)
+		} else {
+			m.ephemeralValues.delete(key)
+		}
+	}
+
+	// Everything that was cached from the undone state is reloaded from storage.
+	if err = m.entityIDToArchID.Clear(); err != nil {
+		return err
+	}
+	if err = m.compValuesToDelete.Clear(); err != nil {
+		return err
+	}
+	m.archIDToComps = NewMapStorage[types.ArchetypeID, []types.ComponentMetadata]()
+	m.archMatches = nil
+	return m.loadArchIDs()
+}
@@ -0,0 +1,45 @@
+package gamestate
+
+import (
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// archetypeMatches is the cached result of an archetype search.
+type archetypeMatches struct {
+	archIDs []types.ArchetypeID
+	// seen is the number of archetypes that have been checked against the filter.
+	seen int
+}
+
+// FindArchetypes returns the IDs of the archetypes that match the filter. The callers must not modify the returned
+// slice.
+//
+// Archetypes are never changed once they are created, so the matches of a filter are cached by the filter's
+// signature, and only archetypes created since the last search are checked. The cache is only invalidated when
+// pending archetypes are discarded.
+func (m *EntityCommandBuffer) FindArchetypes(f filter.ComponentFilter) []types.ArchetypeID {
+	sig, ok := filter.Signature(f)
+	if !ok {
+		return m.SearchFrom(f, 0).Values
+	}
+	if m.archMatches == nil {
+		m.archMatches = map[string]*archetypeMatches{}
+	}
+	matches, ok := m.archMatches[sig]
+	if !ok {
+		matches = &archetypeMatches{}
+		m.archMatches[sig] = matches
+	}
+	count := m.ArchetypeCount()
+	if matches.seen < count {
+		matches.archIDs = append(matches.archIDs, m.SearchFrom(f, matches.seen).Values...)
+		matches.seen = count
+	}
+	return matches.archIDs[:len(matches.archIDs):len(matches.archIDs)]
+}
+
+// FindArchetypes returns the IDs of the archetypes that match the filter.
+func (r *readOnlyManager) FindArchetypes(f filter.ComponentFilter) []types.ArchetypeID {
+	return r.SearchFrom(f, 0).Values
+}
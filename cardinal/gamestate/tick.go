@@ -2,6 +2,7 @@ package gamestate
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -20,10 +21,12 @@ import (
 var _ TickStorage = &EntityCommandBuffer{}
 
 type pendingTransaction struct {
-	TypeID types.MessageID
-	TxHash types.TxHash
-	Data   []byte
-	Tx     *sign.Transaction
+	TypeID          types.MessageID
+	TxHash          types.TxHash
+	Data            []byte
+	Tx              *sign.Transaction
+	EVMSourceTxHash string `json:",omitempty"`
+	Impersonator    string `json:",omitempty"`
 }
 
 // GetTickNumbers returns the last tick that was started and the last tick that was ended. If start == end, it means
@@ -60,11 +63,25 @@ func (m *EntityCommandBuffer) StartNextTick(txs []types.Message, pool *txpool.Tx
 	if err := addPendingTransactionToPipe(ctx, pipe, txs, pool); err != nil {
 		return err
 	}
+	// The transactions that are about to be executed no longer need to be kept in the queue. This happens in the
+	// same atomic transaction that saves them as pending transactions, so they are never lost in between.
+	queuedCount := m.countQueuedTransactionsInPool(pool)
+	if queuedCount > 0 {
+		if err := pipe.ListTrimFront(ctx, storageQueuedTransactionsKey(), queuedCount); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
 
 	if err := pipe.Incr(ctx, storageStartTickKey()); err != nil {
 		return eris.Wrap(err, "")
 	}
-	return eris.Wrap(pipe.EndTransaction(ctx), "")
+	if err := pipe.EndTransaction(ctx); err != nil {
+		return eris.Wrap(err, "")
+	}
+	m.queueMu.Lock()
+	m.queuedHashes = slices.Delete(m.queuedHashes, 0, queuedCount)
+	m.queueMu.Unlock()
+	return nil
 }
 
 // FinalizeTick combines all pending state changes into a single multi/exec redis transactions and commits them
@@ -83,6 +100,15 @@ func (m *EntityCommandBuffer) FinalizeTick(ctx context.Context) error {
 	if err = pipe.Incr(ctx, storageEndTickKey()); err != nil {
 		return eris.Wrap(err, "")
 	}
+	if err = m.addFingerprintToPipe(ctx, pipe); err != nil {
+		return err
+	}
+	if err = m.addTickEventsToPipe(ctx, pipe); err != nil {
+		return err
+	}
+	if err = m.addIndexEntriesToPipe(ctx, pipe); err != nil {
+		return err
+	}
 	statsd.EmitTickStat(makePipeStartTime, "pipe_make")
 	flushStartTime := time.Now()
 	err = pipe.EndTransaction(ctx)
@@ -90,9 +116,16 @@ func (m *EntityCommandBuffer) FinalizeTick(ctx context.Context) error {
 	if err != nil {
 		return eris.Wrap(err, "")
 	}
+	if log := undoLogOf(pipe); log != nil {
+		m.undo.add(log)
+	}
+	m.committedValues.commitStaged()
+	m.saveGenesisFingerprint = false
+	m.tickEvents = nil
+	m.indexEntries = nil
 
 	m.pendingArchIDs = nil
-	return m.DiscardPending()
+	return m.clearPending()
 }
 
 // Recover fetches the pending transactions for an incomplete tick. This should only be called if GetTickNumbers
@@ -121,11 +154,126 @@ func (m *EntityCommandBuffer) Recover(txs []types.Message) (*txpool.TxPool, erro
 		if err != nil {
 			return nil, err
 		}
-		txPool.AddTransaction(tx.ID(), txData, p.Tx)
+		txPool.Add(txpool.TxData{
+			MsgID:           tx.ID(),
+			Msg:             txData,
+			Tx:              p.Tx,
+			EVMSourceTxHash: p.EVMSourceTxHash,
+			Impersonator:    p.Impersonator,
+		})
 	}
 	return txPool, nil
 }
 
+// QueueTransaction persists a transaction that has been accepted, but not yet executed in a tick. If the process
+// dies before the transaction is executed, it can be recovered with RecoverQueuedTransactions. Queued transactions
+// are removed from the queue when they are saved as part of StartNextTick.
+func (m *EntityCommandBuffer) QueueTransaction(msg types.Message, txData txpool.TxData) error {
+	buf, err := msg.Encode(txData.Msg)
+	if err != nil {
+		return err
+	}
+	bz, err := codec.Encode(pendingTransaction{
+		TypeID:          msg.ID(),
+		TxHash:          txData.TxHash,
+		Data:            buf,
+		Tx:              txData.Tx,
+		EVMSourceTxHash: txData.EVMSourceTxHash,
+		Impersonator:    txData.Impersonator,
+	})
+	if err != nil {
+		return err
+	}
+	m.queueMu.Lock()
+	defer m.queueMu.Unlock()
+	if err := m.dbStorage.ListAppend(context.Background(), storageQueuedTransactionsKey(), bz); err != nil {
+		return err
+	}
+	m.queuedHashes = append(m.queuedHashes, txData.TxHash)
+	return nil
+}
+
+// RecoverQueuedTransactions returns the transactions that were accepted, but never executed in a tick, in the order
+// they were accepted.
+func (m *EntityCommandBuffer) RecoverQueuedTransactions(txs []types.Message) (*txpool.TxPool, error) {
+	queued, err := m.getQueuedTransactions(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	m.queueMu.Lock()
+	m.queuedHashes = m.queuedHashes[:0]
+	for _, q := range queued {
+		m.queuedHashes = append(m.queuedHashes, q.TxHash)
+	}
+	m.queueMu.Unlock()
+	idToTx := map[types.MessageID]types.Message{}
+	for _, tx := range txs {
+		idToTx[tx.ID()] = tx
+	}
+
+	txPool := txpool.New()
+	for _, q := range queued {
+		tx, ok := idToTx[q.TypeID]
+		if !ok {
+			return nil, eris.Errorf("queued transaction %s has unknown message id %d", q.TxHash, q.TypeID)
+		}
+		txData, err := tx.Decode(q.Data)
+		if err != nil {
+			return nil, err
+		}
+		txPool.Add(txpool.TxData{
+			MsgID:           tx.ID(),
+			Msg:             txData,
+			Tx:              q.Tx,
+			EVMSourceTxHash: q.EVMSourceTxHash,
+			Impersonator:    q.Impersonator,
+		})
+	}
+	return txPool, nil
+}
+
+func (m *EntityCommandBuffer) getQueuedTransactions(ctx context.Context) ([]pendingTransaction, error) {
+	values, err := m.dbStorage.ListRange(ctx, storageQueuedTransactionsKey())
+	if err != nil {
+		return nil, err
+	}
+	queued := make([]pendingTransaction, 0, len(values))
+	for _, bz := range values {
+		p, err := codec.Decode[pendingTransaction](bz)
+		if err != nil {
+			return nil, err
+		}
+		queued = append(queued, p)
+	}
+	return queued, nil
+}
+
+// countQueuedTransactionsInPool returns the length of the longest prefix of the transaction queue that consists of
+// transactions in the given pool. Transactions are queued in the order they are added to the pool, so this prefix
+// is exactly the set of queued transactions that is about to be executed. Only the prefix is visited, so the cost
+// doesn't grow with the transactions that stay queued.
+func (m *EntityCommandBuffer) countQueuedTransactionsInPool(pool *txpool.TxPool) int {
+	if pool.GetAmountOfTxs() == 0 {
+		return 0
+	}
+	inPool := map[types.TxHash]bool{}
+	for _, txs := range pool.Transactions() {
+		for _, tx := range txs {
+			inPool[tx.TxHash] = true
+		}
+	}
+	m.queueMu.Lock()
+	defer m.queueMu.Unlock()
+	count := 0
+	for _, hash := range m.queuedHashes {
+		if !inPool[hash] {
+			break
+		}
+		count++
+	}
+	return count
+}
+
 func addPendingTransactionToPipe(
 	ctx context.Context, pipe PrimitiveStorage[string], txs []types.Message,
 	pool *txpool.TxPool,
@@ -139,10 +287,12 @@ func addPendingTransactionToPipe(
 				return err
 			}
 			currItem := pendingTransaction{
-				TypeID: tx.ID(),
-				TxHash: txData.TxHash,
-				Tx:     txData.Tx,
-				Data:   buf,
+				TypeID:          tx.ID(),
+				TxHash:          txData.TxHash,
+				Tx:              txData.Tx,
+				Data:            buf,
+				EVMSourceTxHash: txData.EVMSourceTxHash,
+				Impersonator:    txData.Impersonator,
 			}
 			pending = append(pending, currItem)
 		}
@@ -50,3 +50,85 @@ func TestErrorWhenRecoveringNoTransactions(t *testing.T) {
 	// Recover should fail when no transactions have previously been saved to the DB.
 	assert.Check(t, err != nil)
 }
+
+func TestQueuedTransactionsAreRecoveredUntilTheyAreTicked(t *testing.T) {
+	type MsgIn struct {
+		Value int
+	}
+	type MsgOut struct{}
+
+	msgAlpha := message.NewMessageType[MsgIn, MsgOut]("alpha")
+	assert.NilError(t, msgAlpha.SetID(16))
+	msgs := []types.Message{msgAlpha}
+
+	manager, client := newCmdBufferAndRedisClientForTest(t, nil)
+	pool := txpool.New()
+	for i := 0; i < 3; i++ {
+		sig := testutils.UniqueSignature()
+		txHash := pool.AddTransaction(msgAlpha.ID(), MsgIn{i}, sig)
+		assert.NilError(t, manager.QueueTransaction(msgAlpha, txpool.TxData{
+			MsgID:  msgAlpha.ID(),
+			Msg:    MsgIn{i},
+			TxHash: txHash,
+			Tx:     sig,
+		}))
+	}
+
+	// The process dies before the transactions are ticked. They should all be recovered in order.
+	manager, _ = newCmdBufferAndRedisClientForTest(t, client)
+	gotPool, err := manager.RecoverQueuedTransactions(msgs)
+	assert.NilError(t, err)
+	gotTxs := gotPool.ForID(msgAlpha.ID())
+	assert.Equal(t, 3, len(gotTxs))
+	for i, tx := range gotTxs {
+		assert.Equal(t, MsgIn{i}, tx.Msg)
+	}
+
+	// Once the transactions are part of a tick, they are removed from the queue.
+	assert.NilError(t, manager.StartNextTick(msgs, gotPool))
+	assert.NilError(t, manager.FinalizeTick(context.Background()))
+	gotPool, err = manager.RecoverQueuedTransactions(msgs)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, gotPool.GetAmountOfTxs())
+}
+
+func TestRewindUndoesCommits(t *testing.T) {
+	manager := newCmdBufferForTest(t)
+	manager.SetRewindLimit(2)
+	ctx := context.Background()
+
+	// Tick 0 creates an entity, which tick 1 changes, and tick 2 moves to another archetype. Tick 1 also creates a
+	// second entity.
+	id, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{1}))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	assert.NilError(t, manager.SetComponentForEntity(fooComp, id, Foo{2}))
+	secondID, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	assert.NilError(t, manager.AddComponentToEntity(barComp, id))
+	assert.NilError(t, manager.FinalizeTick(ctx))
+	assert.Equal(t, manager.RewindableCommits(), 2)
+
+	assert.ErrorContains(t, manager.Rewind(3), "only the last 2 are kept")
+	assert.NilError(t, manager.Rewind(2))
+	assert.Equal(t, manager.RewindableCommits(), 0)
+
+	got, err := manager.GetComponentForEntity(fooComp, id)
+	assert.NilError(t, err)
+	assert.Equal(t, got, Foo{1})
+	comps, err := manager.GetComponentTypesForEntity(id)
+	assert.NilError(t, err)
+	assert.Equal(t, len(comps), 1)
+	_, end, err := manager.GetTickNumbers()
+	assert.NilError(t, err)
+	assert.Equal(t, end, uint64(1))
+
+	// Entities that were created in the undone ticks no longer exist, and their IDs are assigned again.
+	_, err = manager.GetComponentForEntity(fooComp, secondID)
+	assert.Check(t, err != nil)
+	nextID, err := manager.CreateEntity(fooComp)
+	assert.NilError(t, err)
+	assert.Equal(t, nextID, secondID)
+}
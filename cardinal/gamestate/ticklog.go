@@ -0,0 +1,91 @@
+package gamestate
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/codec"
+)
+
+// ErrTickLogDisabled is returned when the tick log is read, but it was never kept.
+var ErrTickLogDisabled = errors.New("tick log is disabled")
+
+// SetTickLogRetention makes every finalized tick save the events that were set with SetTickEvents, and keeps the
+// events of the given number of most recent ticks. Zero, the default, keeps no events.
+func (m *EntityCommandBuffer) SetTickLogRetention(ticks int) {
+	m.tickLogRetention = ticks
+}
+
+// SetTickEvents sets the events that are saved with the next finalized tick, which must be the given tick.
+func (m *EntityCommandBuffer) SetTickEvents(tick uint64, events [][]byte) {
+	m.tickEventsTick = tick
+	m.tickEvents = events
+}
+
+// GetTickLogRange returns the first tick whose events are kept, and the tick after the last one that was finalized.
+// ErrTickLogDisabled is returned if no events were ever kept.
+func (m *EntityCommandBuffer) GetTickLogRange() (first, end uint64, err error) {
+	ctx := context.Background()
+	first, err = m.dbStorage.GetUInt64(ctx, storageTickLogStartKey())
+	if errors.Is(err, redis.Nil) {
+		return 0, 0, eris.Wrap(ErrTickLogDisabled, "")
+	} else if err != nil {
+		return 0, 0, eris.Wrap(err, "")
+	}
+	if _, end, err = m.GetTickNumbers(); err != nil {
+		return 0, 0, err
+	}
+	return first, end, nil
+}
+
+// GetTickEvents returns the events that were saved with the tick. Ticks without events, and ticks that aren't kept
+// anymore, have none.
+func (m *EntityCommandBuffer) GetTickEvents(tick uint64) ([][]byte, error) {
+	bz, err := m.dbStorage.GetBytes(context.Background(), storageTickLogKey(tick))
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	} else if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	return codec.Decode[[][]byte](bz)
+}
+
+// addTickEventsToPipe saves the events of the tick that is being finalized, and removes the events of the ticks that
+// fell out of the retention.
+func (m *EntityCommandBuffer) addTickEventsToPipe(ctx context.Context, pipe PrimitiveStorage[string]) error {
+	if m.tickLogRetention <= 0 {
+		return nil
+	}
+	tick := m.tickEventsTick
+	if len(m.tickEvents) > 0 {
+		bz, err := codec.Encode(m.tickEvents)
+		if err != nil {
+			return err
+		}
+		if err := pipe.Set(ctx, storageTickLogKey(tick), bz); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+
+	// The start is read from the DB rather than kept in memory, so that it stays right when commits are rewound.
+	start, err := m.dbStorage.GetUInt64(ctx, storageTickLogStartKey())
+	started := err == nil
+	if errors.Is(err, redis.Nil) {
+		start = tick
+	} else if err != nil {
+		return eris.Wrap(err, "")
+	}
+	newStart := start
+	for ; tick+1-newStart > uint64(m.tickLogRetention); newStart++ {
+		if err := pipe.Delete(ctx, storageTickLogKey(newStart)); err != nil {
+			return eris.Wrap(err, "")
+		}
+	}
+	if started && newStart == start {
+		return nil
+	}
+	return eris.Wrap(pipe.Set(ctx, storageTickLogStartKey(), newStart), "")
+}
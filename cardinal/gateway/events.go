@@ -0,0 +1,98 @@
+package gateway
+
+import (
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+// subscriber is a websocket client of the gateway's event stream.
+type subscriber struct {
+	frames chan []byte
+}
+
+// serveEvents upgrades the request to a websocket, and sends the shard's tick results to it until the client leaves
+// or falls behind. Only the JSON encoding of tick results is supported.
+func (g *Gateway) serveEvents(w http.ResponseWriter, r *http.Request) {
+	if encoding := r.URL.Query().Get("encoding"); encoding != "" && encoding != "json" {
+		http.Error(w, "unsupported event encoding: "+encoding, http.StatusBadRequest)
+		return
+	}
+	conn, err := g.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already replied with an error.
+		return
+	}
+	defer conn.Close()
+
+	sub := &subscriber{frames: make(chan []byte, g.subscriberBuffer)}
+	g.addSubscriber(sub)
+	defer g.removeSubscriber(sub)
+
+	// Clients don't send anything, but reading is how a closed connection is noticed.
+	left := make(chan struct{})
+	go func() {
+		defer close(left)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-left:
+			return
+		case frame, ok := <-sub.frames:
+			if !ok {
+				msg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "too far behind the event stream")
+				if err := conn.WriteMessage(websocket.CloseMessage, msg); err != nil {
+					log.Debug().Err(err).Msg("Failed to close event subscription")
+				}
+				return
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, frame); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (g *Gateway) addSubscriber(sub *subscriber) {
+	g.subscribersMu.Lock()
+	defer g.subscribersMu.Unlock()
+	g.subscribers[sub] = struct{}{}
+}
+
+func (g *Gateway) removeSubscriber(sub *subscriber) {
+	g.subscribersMu.Lock()
+	defer g.subscribersMu.Unlock()
+	if _, ok := g.subscribers[sub]; ok {
+		delete(g.subscribers, sub)
+		close(sub.frames)
+	}
+}
+
+// broadcast queues the tick results for every subscriber. Subscribers whose queue is full are dropped.
+func (g *Gateway) broadcast(frame []byte) {
+	g.subscribersMu.Lock()
+	defer g.subscribersMu.Unlock()
+	for sub := range g.subscribers {
+		select {
+		case sub.frames <- frame:
+		default:
+			log.Warn().Msg("Disconnecting an event subscriber that fell behind")
+			delete(g.subscribers, sub)
+			close(sub.frames)
+		}
+	}
+}
+
+// Subscribers returns the number of websocket clients that are subscribed to the gateway's event stream.
+func (g *Gateway) Subscribers() int {
+	g.subscribersMu.Lock()
+	defer g.subscribersMu.Unlock()
+	return len(g.subscribers)
+}
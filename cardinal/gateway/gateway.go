@@ -0,0 +1,318 @@
+// Package gateway is a read gateway for a Cardinal shard, meant to run in edge regions close to players. A gateway
+// subscribes to the shard's event stream once, and fans the tick results out to its own websocket clients. Queries
+// are answered from a cache that is kept for the duration of a tick, so repeated reads in the same tick don't travel
+// to the shard. Transactions, and every other request, are forwarded to the shard, which stays the only authority
+// on the game's state.
+//
+// A gateway learns that a tick ended from the shard's event stream, so its cached query results can trail the shard
+// by the latency between the two. While the gateway is not subscribed to the event stream, nothing is cached.
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	DefaultReconnectDelay   = time.Second
+	DefaultSubscriberBuffer = 64
+	DefaultMaxCachedQueries = 10_000
+
+	// CacheHeader is set on query responses to "hit" if the response came from the gateway's cache, and to "miss"
+	// otherwise.
+	CacheHeader = "X-Gateway-Cache"
+
+	// maxQueryBodySize is the largest query body that the gateway accepts.
+	maxQueryBodySize = 1 << 20
+)
+
+type Gateway struct {
+	shardURL  *url.URL
+	eventsURL string
+	client    *http.Client
+	proxy     *httputil.ReverseProxy
+	upgrader  websocket.Upgrader
+
+	reconnectDelay time.Duration
+	// subscriberBuffer is the number of tick results that can be waiting to be sent to a client before the client is
+	// disconnected for falling behind.
+	subscriberBuffer int
+
+	cache *queryCache
+
+	subscribersMu sync.Mutex
+	subscribers   map[*subscriber]struct{}
+}
+
+type Option func(*Gateway)
+
+// WithHTTPClient sets the client that queries are sent to the shard with.
+func WithHTTPClient(client *http.Client) Option {
+	return func(g *Gateway) {
+		g.client = client
+	}
+}
+
+// WithReconnectDelay sets how long the gateway waits before it subscribes to the shard's event stream again after
+// the subscription was lost.
+func WithReconnectDelay(delay time.Duration) Option {
+	return func(g *Gateway) {
+		g.reconnectDelay = delay
+	}
+}
+
+// WithSubscriberBuffer sets the number of tick results that can be waiting to be sent to a websocket client. Clients
+// that fall further behind are disconnected, so that one slow client can't hold back the others.
+func WithSubscriberBuffer(size int) Option {
+	return func(g *Gateway) {
+		g.subscriberBuffer = size
+	}
+}
+
+// WithMaxCachedQueries limits the number of query results that are cached for a tick. Zero disables the cache.
+func WithMaxCachedQueries(limit int) Option {
+	return func(g *Gateway) {
+		g.cache.maxEntries = limit
+	}
+}
+
+// New returns a gateway for the shard at the given base URL, such as "http://cardinal:4040".
+func New(shardURL string, opts ...Option) (*Gateway, error) {
+	u, err := url.Parse(shardURL)
+	if err != nil {
+		return nil, eris.Wrapf(err, "invalid shard URL %q", shardURL)
+	}
+	eventsURL := *u.JoinPath("events")
+	switch u.Scheme {
+	case "http":
+		eventsURL.Scheme = "ws"
+	case "https":
+		eventsURL.Scheme = "wss"
+	default:
+		return nil, eris.Errorf("shard URL %q must be http or https", shardURL)
+	}
+
+	g := &Gateway{
+		shardURL:  u,
+		eventsURL: eventsURL.String(),
+		client:    &http.Client{Timeout: 10 * time.Second},
+		proxy:     httputil.NewSingleHostReverseProxy(u),
+		upgrader: websocket.Upgrader{ //nolint:exhaustruct // defaults are fine
+			// Cardinal accepts requests from any origin, and so does its gateway.
+			CheckOrigin: func(*http.Request) bool { return true },
+		},
+		reconnectDelay:   DefaultReconnectDelay,
+		subscriberBuffer: DefaultSubscriberBuffer,
+		cache:            newQueryCache(DefaultMaxCachedQueries),
+		subscribers:      map[*subscriber]struct{}{},
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g, nil
+}
+
+// Handler returns the handler that serves the gateway's API, which is the same as the shard's API.
+func (g *Gateway) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/events":
+			g.serveEvents(w, r)
+		case r.Method == http.MethodPost && (strings.HasPrefix(r.URL.Path, "/query/") || r.URL.Path == "/cql"):
+			g.serveQuery(w, r)
+		default:
+			g.proxy.ServeHTTP(w, r)
+		}
+	})
+}
+
+// Run subscribes to the shard's event stream until the context is done. The subscription is made again whenever it
+// is lost.
+func (g *Gateway) Run(ctx context.Context) error {
+	for {
+		err := g.subscribe(ctx)
+		g.cache.stop()
+		if ctx.Err() != nil {
+			return nil
+		}
+		log.Warn().Err(err).Msgf("Lost the event stream of %s, subscribing again in %s", g.shardURL, g.reconnectDelay)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(g.reconnectDelay):
+		}
+	}
+}
+
+func (g *Gateway) subscribe(ctx context.Context) error {
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, g.eventsURL, nil)
+	if err != nil {
+		return eris.Wrap(err, "failed to subscribe to events")
+	}
+	defer resp.Body.Close()
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	log.Info().Msgf("Subscribed to the event stream of %s", g.shardURL)
+
+	for {
+		msgType, frame, err := conn.ReadMessage()
+		if err != nil {
+			return eris.Wrap(err, "failed to read events")
+		}
+		if msgType != websocket.TextMessage {
+			continue
+		}
+		var results struct {
+			Tick uint64
+		}
+		if err := json.Unmarshal(frame, &results); err != nil {
+			log.Warn().Err(err).Msg("Skipping tick results that can't be decoded")
+			continue
+		}
+		g.cache.advance(results.Tick)
+		g.broadcast(frame)
+	}
+}
+
+// serveQuery answers a query from the cache if the same query was already answered in the current tick, and from the
+// shard otherwise.
+func (g *Gateway) serveQuery(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxQueryBodySize))
+	if err != nil {
+		http.Error(w, "failed to read query: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	key := r.URL.RequestURI() + "\n" + string(body)
+	if cached, ok := g.cache.get(key); ok {
+		cached.write(w, "hit")
+		return
+	}
+
+	generation, cacheable := g.cache.generation()
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodPost,
+		g.shardURL.JoinPath(r.URL.Path).String(), bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	req.URL.RawQuery = r.URL.RawQuery
+	req.Header.Set("Content-Type", r.Header.Get("Content-Type"))
+	resp, err := g.client.Do(req)
+	if err != nil {
+		http.Error(w, "failed to query shard: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, "failed to read shard response: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	res := cachedResponse{status: resp.StatusCode, contentType: resp.Header.Get("Content-Type"), body: respBody}
+	// Errors are not cached, because they may be caused by something other than the state of the tick.
+	if cacheable && res.status == http.StatusOK {
+		g.cache.put(generation, key, res)
+	}
+	res.write(w, "miss")
+}
+
+type cachedResponse struct {
+	status      int
+	contentType string
+	body        []byte
+}
+
+func (c cachedResponse) write(w http.ResponseWriter, cache string) {
+	if c.contentType != "" {
+		w.Header().Set("Content-Type", c.contentType)
+	}
+	w.Header().Set(CacheHeader, cache)
+	w.WriteHeader(c.status)
+	if _, err := w.Write(c.body); err != nil {
+		log.Debug().Err(err).Msg("Failed to write query response")
+	}
+}
+
+// queryCache holds the query results of the most recent tick.
+type queryCache struct {
+	maxEntries int
+
+	mu sync.Mutex
+	// live is true while the gateway is subscribed to the event stream, so it learns when the cache is stale.
+	live bool
+	tick uint64
+	// gen changes whenever the cache is cleared, so that results that were requested before it was cleared aren't
+	// added after it.
+	gen     uint64
+	entries map[string]cachedResponse
+}
+
+func newQueryCache(maxEntries int) *queryCache {
+	return &queryCache{
+		maxEntries: maxEntries,
+		entries:    map[string]cachedResponse{},
+	}
+}
+
+// advance clears the cache unless the tick results are for the tick that is already cached. The tick can go down
+// as well as up, because a shard that rolls back replays earlier ticks.
+func (c *queryCache) advance(tick uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.live && c.tick == tick {
+		return
+	}
+	c.live = true
+	c.tick = tick
+	c.clearLocked()
+}
+
+// stop clears the cache, and stops caching until the next tick results.
+func (c *queryCache) stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.live = false
+	c.clearLocked()
+}
+
+func (c *queryCache) clearLocked() {
+	c.gen++
+	clear(c.entries)
+}
+
+// generation returns the current generation of the cache, and whether results can be cached in it.
+func (c *queryCache) generation() (uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gen, c.live && c.maxEntries > 0
+}
+
+func (c *queryCache) get(key string) (cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	res, ok := c.entries[key]
+	return res, ok
+}
+
+func (c *queryCache) put(gen uint64, key string, res cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen || len(c.entries) >= c.maxEntries {
+		return
+	}
+	c.entries[key] = res
+}
@@ -0,0 +1,155 @@
+package gateway_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/gateway"
+)
+
+// fakeShard answers queries with the number of queries it has answered, and lets the test send tick results to the
+// gateway's event subscription.
+type fakeShard struct {
+	*httptest.Server
+	queries atomic.Int32
+	txs     atomic.Int32
+	events  chan *websocket.Conn
+}
+
+func newFakeShard(t *testing.T) *fakeShard {
+	s := &fakeShard{events: make(chan *websocket.Conn, 1)}
+	upgrader := websocket.Upgrader{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/events":
+			conn, err := upgrader.Upgrade(w, r, nil)
+			assert.NilError(t, err)
+			s.events <- conn
+		case strings.HasPrefix(r.URL.Path, "/query/"):
+			n := s.queries.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"queries":%d}`, n)
+		case strings.HasPrefix(r.URL.Path, "/tx/"):
+			s.txs.Add(1)
+			fmt.Fprint(w, `{"txHash":"abc","tick":1}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func startGateway(t *testing.T, shard *fakeShard) (*gateway.Gateway, *httptest.Server, *websocket.Conn) {
+	g, err := gateway.New(shard.URL)
+	assert.NilError(t, err)
+	srv := httptest.NewServer(g.Handler())
+	t.Cleanup(srv.Close)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		assert.NilError(t, g.Run(ctx))
+	}()
+	upstream := <-shard.events
+	t.Cleanup(func() { upstream.Close() })
+	return g, srv, upstream
+}
+
+func query(t *testing.T, baseURL string) (string, string) {
+	resp, err := http.Post(baseURL+"/query/game/score", "application/json", strings.NewReader(`{"id":1}`))
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	body, err := io.ReadAll(resp.Body)
+	assert.NilError(t, err)
+	return string(body), resp.Header.Get(gateway.CacheHeader)
+}
+
+// subscribe connects a client to the gateway's event stream, and waits until the gateway has registered it.
+func subscribe(t *testing.T, g *gateway.Gateway, baseURL string) *websocket.Conn {
+	subscribers := g.Subscribers()
+	conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(baseURL, "http")+"/events", nil)
+	assert.NilError(t, err)
+	resp.Body.Close()
+	t.Cleanup(func() { conn.Close() })
+	for g.Subscribers() == subscribers {
+		time.Sleep(time.Millisecond)
+	}
+	return conn
+}
+
+// sendTick sends tick results from the shard, and waits until the gateway has fanned them out to the client.
+func sendTick(t *testing.T, upstream, client *websocket.Conn, tick int) {
+	frame := fmt.Sprintf(`{"Tick":%d,"Receipts":[],"Events":[]}`, tick)
+	assert.NilError(t, upstream.WriteMessage(websocket.TextMessage, []byte(frame)))
+	_, got, err := client.ReadMessage()
+	assert.NilError(t, err)
+	assert.Equal(t, string(got), frame)
+}
+
+func TestQueriesAreCachedForATick(t *testing.T) {
+	shard := newFakeShard(t)
+	g, srv, upstream := startGateway(t, shard)
+	client := subscribe(t, g, srv.URL)
+
+	// Nothing is cached until the gateway knows which tick the shard is on.
+	body, cache := query(t, srv.URL)
+	assert.Equal(t, body, `{"queries":1}`)
+	assert.Equal(t, cache, "miss")
+
+	sendTick(t, upstream, client, 1)
+	body, cache = query(t, srv.URL)
+	assert.Equal(t, body, `{"queries":2}`)
+	assert.Equal(t, cache, "miss")
+	body, cache = query(t, srv.URL)
+	assert.Equal(t, body, `{"queries":2}`)
+	assert.Equal(t, cache, "hit")
+
+	sendTick(t, upstream, client, 2)
+	body, cache = query(t, srv.URL)
+	assert.Equal(t, body, `{"queries":3}`)
+	assert.Equal(t, cache, "miss")
+	assert.Equal(t, shard.queries.Load(), int32(3))
+}
+
+func TestTransactionsAreForwardedToTheShard(t *testing.T) {
+	shard := newFakeShard(t)
+	_, srv, _ := startGateway(t, shard)
+
+	for i := 0; i < 2; i++ {
+		resp, err := http.Post(srv.URL+"/tx/game/move", "application/json", strings.NewReader(`{}`))
+		assert.NilError(t, err)
+		assert.Equal(t, resp.StatusCode, http.StatusOK)
+		resp.Body.Close()
+	}
+	assert.Equal(t, shard.txs.Load(), int32(2))
+}
+
+func TestEventsAreFannedOut(t *testing.T) {
+	shard := newFakeShard(t)
+	g, srv, upstream := startGateway(t, shard)
+	clients := []*websocket.Conn{subscribe(t, g, srv.URL), subscribe(t, g, srv.URL), subscribe(t, g, srv.URL)}
+
+	frame := `{"Tick":7,"Receipts":[],"Events":["e30="]}`
+	assert.NilError(t, upstream.WriteMessage(websocket.TextMessage, []byte(frame)))
+	for _, client := range clients {
+		_, got, err := client.ReadMessage()
+		assert.NilError(t, err)
+		assert.Equal(t, string(got), frame)
+	}
+
+	resp, err := http.Get(srv.URL + "/events?encoding=binary")
+	assert.NilError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusBadRequest)
+}
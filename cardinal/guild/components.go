@@ -0,0 +1,59 @@
+package guild
+
+import (
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// Group is the entity that represents a guild or party.
+type Group struct {
+	DisplayName string `json:"displayName"`
+}
+
+func (Group) Name() string { return "guild-group" }
+
+// Member is attached to an entity for every persona that belongs to a group. Every member entity also has a Role.
+type Member struct {
+	Group      types.EntityID `json:"group"`
+	PersonaTag string         `json:"personaTag"`
+}
+
+func (Member) Name() string { return "guild-member" }
+
+type Rank string
+
+const (
+	// RankLeader can do everything an officer can, and disband the group. Every group has exactly one leader.
+	RankLeader Rank = "leader"
+	// RankOfficer can invite personas to the group, and kick members.
+	RankOfficer Rank = "officer"
+	RankMember  Rank = "member"
+)
+
+// Role is a member's rank within their group.
+type Role struct {
+	Rank Rank `json:"rank"`
+}
+
+func (Role) Name() string { return "guild-role" }
+
+// canManage reports whether a member with this role can invite personas and kick members of the given rank.
+func (r Role) canManage(other Rank) bool {
+	switch r.Rank {
+	case RankLeader:
+		return other != RankLeader
+	case RankOfficer:
+		return other == RankMember
+	case RankMember:
+		return false
+	}
+	return false
+}
+
+// Invite is a pending invitation for a persona to join a group.
+type Invite struct {
+	Group      types.EntityID `json:"group"`
+	PersonaTag string         `json:"personaTag"`
+	InvitedBy  string         `json:"invitedBy"`
+}
+
+func (Invite) Name() string { return "guild-invite" }
@@ -0,0 +1,152 @@
+package guild_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/guild"
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+type guildFixture struct {
+	*testutils.TestFixture
+}
+
+func newGuildFixture(t *testing.T, opts ...guild.Option) guildFixture {
+	tf := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, tf.World.UseModule(guild.NewModule(opts...)))
+	tf.StartWorld()
+	return guildFixture{tf}
+}
+
+// send adds a guild message signed by the given persona, and returns its hash.
+func (gf guildFixture) send(personaTag, name string, msg any) types.TxHash {
+	msgType, ok := gf.World.GetMessageByFullName(guild.ModuleName + "." + name)
+	assert.Check(gf, ok, "message %q is not registered", name)
+	return gf.AddTransaction(msgType.ID(), msg, testutils.UniqueSignatureWithName(personaTag))
+}
+
+// result returns the result of a transaction that was processed in a previous tick.
+func (gf guildFixture) result(hash types.TxHash) (guild.Result, []error) {
+	receipt, errs, ok := cardinal.NewReadOnlyWorldContext(gf.World).GetTransactionReceipt(hash)
+	assert.Check(gf, ok)
+	result, _ := receipt.(guild.Result)
+	return result, errs
+}
+
+func (gf guildFixture) group(group types.EntityID) guild.GroupReply {
+	res := gf.Post("query/guild/group", guild.GroupRequest{Group: group})
+	assert.Equal(gf, res.StatusCode, http.StatusOK)
+	var reply guild.GroupReply
+	assert.NilError(gf, json.NewDecoder(res.Body).Decode(&reply))
+	return reply
+}
+
+func (gf guildFixture) membership(personaTag string) guild.MembershipReply {
+	res := gf.Post("query/guild/membership", guild.MembershipRequest{PersonaTag: personaTag})
+	assert.Equal(gf, res.StatusCode, http.StatusOK)
+	var reply guild.MembershipReply
+	assert.NilError(gf, json.NewDecoder(res.Body).Decode(&reply))
+	return reply
+}
+
+func (gf guildFixture) eventsFor(personaTag, eventType string) int {
+	res := gf.Post("query/events/list", handler.ListEventsRequest{Type: eventType, Recipient: personaTag})
+	assert.Equal(gf, res.StatusCode, http.StatusOK)
+	var reply handler.ListEventsResponse
+	assert.NilError(gf, json.NewDecoder(res.Body).Decode(&reply))
+	return len(reply.Events)
+}
+
+func TestGroupLifecycle(t *testing.T) {
+	gf := newGuildFixture(t)
+
+	hash := gf.send("alice", guild.CreateGroupMessageName, guild.CreateGroupMsg{Name: "Knights"})
+	gf.DoTick()
+	created, errs := gf.result(hash)
+	assert.Equal(t, len(errs), 0)
+	group := created.Group
+
+	gf.send("alice", guild.InviteMessageName, guild.InviteMsg{PersonaTag: "bob"})
+	gf.DoTick()
+	assert.Equal(t, gf.eventsFor("bob", guild.EventInvited), 1)
+	bob := gf.membership("bob")
+	assert.Check(t, !bob.InGroup)
+	assert.Equal(t, len(bob.Invites), 1)
+	assert.Equal(t, bob.Invites[0].Name, "Knights")
+	assert.Equal(t, bob.Invites[0].InvitedBy, "alice")
+
+	gf.send("bob", guild.AcceptInviteMessageName, guild.AcceptInviteMsg{Group: group})
+	gf.DoTick()
+	reply := gf.group(group)
+	assert.Equal(t, reply.Name, "Knights")
+	assert.DeepEqual(t, reply.Members, []guild.MemberInfo{
+		{PersonaTag: "alice", Rank: guild.RankLeader},
+		{PersonaTag: "bob", Rank: guild.RankMember},
+	})
+	assert.Equal(t, len(reply.Invites), 0)
+	assert.Equal(t, gf.eventsFor("alice", guild.EventMemberJoined), 1)
+	assert.Equal(t, gf.eventsFor("carol", guild.EventMemberJoined), 0)
+
+	// Members cannot invite or kick.
+	hash = gf.send("bob", guild.InviteMessageName, guild.InviteMsg{PersonaTag: "carol"})
+	kickHash := gf.send("bob", guild.KickMessageName, guild.KickMsg{PersonaTag: "alice"})
+	gf.DoTick()
+	_, errs = gf.result(hash)
+	assert.Equal(t, len(errs), 1)
+	assert.ErrorContains(t, errs[0], `persona "bob" cannot invite`)
+	_, errs = gf.result(kickHash)
+	assert.Equal(t, len(errs), 1)
+	assert.ErrorContains(t, errs[0], "cannot kick")
+
+	gf.send("alice", guild.KickMessageName, guild.KickMsg{PersonaTag: "bob"})
+	gf.DoTick()
+	assert.Equal(t, gf.eventsFor("bob", guild.EventMemberKicked), 1)
+	assert.Check(t, !gf.membership("bob").InGroup)
+
+	gf.send("alice", guild.DisbandGroupMessageName, guild.DisbandGroupMsg{})
+	gf.DoTick()
+	assert.Equal(t, gf.eventsFor("alice", guild.EventDisbanded), 1)
+	assert.Check(t, !gf.membership("alice").InGroup)
+	res := gf.Post("query/guild/group", guild.GroupRequest{Group: group})
+	assert.Check(t, res.StatusCode != http.StatusOK)
+}
+
+func TestGroupRules(t *testing.T) {
+	gf := newGuildFixture(t, guild.WithMaxMembers(2))
+
+	createHash := gf.send("alice", guild.CreateGroupMessageName, guild.CreateGroupMsg{Name: "Party"})
+	gf.DoTick()
+	created, _ := gf.result(createHash)
+	group := created.Group
+
+	hash := gf.send("alice", guild.CreateGroupMessageName, guild.CreateGroupMsg{Name: "Another"})
+	leaveHash := gf.send("alice", guild.LeaveGroupMessageName, guild.LeaveGroupMsg{})
+	acceptHash := gf.send("bob", guild.AcceptInviteMessageName, guild.AcceptInviteMsg{Group: group})
+	gf.send("alice", guild.InviteMessageName, guild.InviteMsg{PersonaTag: "bob"})
+	gf.send("alice", guild.InviteMessageName, guild.InviteMsg{PersonaTag: "carol"})
+	gf.DoTick()
+
+	_, errs := gf.result(hash)
+	assert.ErrorContains(t, errs[0], "is already in group")
+	_, errs = gf.result(leaveHash)
+	assert.ErrorContains(t, errs[0], "the leader cannot leave the group")
+	// Invites are processed before accepts, so bob's accept in the same tick as the invite succeeds.
+	_, errs = gf.result(acceptHash)
+	assert.Equal(t, len(errs), 0)
+
+	hash = gf.send("carol", guild.AcceptInviteMessageName, guild.AcceptInviteMsg{Group: group})
+	gf.DoTick()
+	_, errs = gf.result(hash)
+	assert.ErrorContains(t, errs[0], "is full")
+
+	gf.send("bob", guild.LeaveGroupMessageName, guild.LeaveGroupMsg{})
+	gf.DoTick()
+	assert.Equal(t, len(gf.group(group).Members), 1)
+	assert.Equal(t, gf.eventsFor("alice", guild.EventMemberLeft), 1)
+}
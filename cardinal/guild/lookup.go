@@ -0,0 +1,102 @@
+package guild
+
+import (
+	"sort"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type membership struct {
+	id     types.EntityID
+	member Member
+	role   Role
+}
+
+type invitation struct {
+	id     types.EntityID
+	invite Invite
+}
+
+// findMemberships returns the member entities that match the filter, sorted by entity ID so that iteration order is
+// deterministic.
+func findMemberships(wCtx engine.Context, match func(Member) bool) ([]membership, error) {
+	ids, err := cardinal.NewSearch().
+		Entity(filter.Contains(filter.Component[Member](), filter.Component[Role]())).
+		Where(cardinal.FilterFunction[Member](match)).
+		Collect(wCtx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	memberships := make([]membership, 0, len(ids))
+	for _, id := range ids {
+		member, err := cardinal.GetComponent[Member](wCtx, id)
+		if err != nil {
+			return nil, err
+		}
+		role, err := cardinal.GetComponent[Role](wCtx, id)
+		if err != nil {
+			return nil, err
+		}
+		memberships = append(memberships, membership{id: id, member: *member, role: *role})
+	}
+	return memberships, nil
+}
+
+// membershipOf returns the persona's membership, if the persona is in a group.
+func membershipOf(wCtx engine.Context, personaTag string) (*membership, error) {
+	found, err := findMemberships(wCtx, func(m Member) bool { return m.PersonaTag == personaTag })
+	if err != nil || len(found) == 0 {
+		return nil, err
+	}
+	return &found[0], nil
+}
+
+func membersOf(wCtx engine.Context, group types.EntityID) ([]membership, error) {
+	return findMemberships(wCtx, func(m Member) bool { return m.Group == group })
+}
+
+func findInvites(wCtx engine.Context, match func(Invite) bool) ([]invitation, error) {
+	ids, err := cardinal.NewSearch().
+		Entity(filter.Contains(filter.Component[Invite]())).
+		Where(cardinal.FilterFunction[Invite](match)).
+		Collect(wCtx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	invites := make([]invitation, 0, len(ids))
+	for _, id := range ids {
+		invite, err := cardinal.GetComponent[Invite](wCtx, id)
+		if err != nil {
+			return nil, err
+		}
+		invites = append(invites, invitation{id: id, invite: *invite})
+	}
+	return invites, nil
+}
+
+// PersonaTags returns the persona tags of the group's members, ordered by when they joined.
+func PersonaTags(wCtx engine.Context, group types.EntityID) ([]string, error) {
+	members, err := membersOf(wCtx, group)
+	if err != nil {
+		return nil, err
+	}
+	personaTags := make([]string, 0, len(members))
+	for _, m := range members {
+		personaTags = append(personaTags, m.member.PersonaTag)
+	}
+	return personaTags, nil
+}
+
+// EmitToGroup emits an event that is only delivered to the members of the group. See cardinal.EmitEventTo.
+func EmitToGroup(wCtx engine.Context, group types.EntityID, event map[string]any) error {
+	personaTags, err := PersonaTags(wCtx, group)
+	if err != nil {
+		return err
+	}
+	return cardinal.EmitEventTo(wCtx, personaTags, event)
+}
@@ -0,0 +1,70 @@
+// Package guild is a module for groups of players, such as guilds or parties. Players create a group, invite other
+// personas, and manage its members through transactions, and the group's members are available through queries.
+//
+// Each member has a Role. The leader can disband the group, and leaders and officers can invite personas and kick
+// members of a lower rank. Use EmitToGroup to send events that only the members of a group receive.
+package guild
+
+import (
+	"pkg.world.dev/world-engine/cardinal"
+)
+
+const (
+	ModuleName    = "guild"
+	ModuleVersion = "v1.0.0"
+
+	// DefaultMaxMembers is the default maximum number of members in a group, including the leader.
+	DefaultMaxMembers = 50
+)
+
+var _ cardinal.Module = &Module{}
+
+type Option func(*Module)
+
+// WithMaxMembers sets the maximum number of members in a group, including the leader. Zero means unlimited.
+func WithMaxMembers(maxMembers int) Option {
+	return func(m *Module) {
+		m.maxMembers = maxMembers
+	}
+}
+
+type Module struct {
+	cardinal.ModuleBase
+	maxMembers int
+}
+
+func NewModule(opts ...Option) *Module {
+	m := &Module{maxMembers: DefaultMaxMembers}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func (*Module) Name() string    { return ModuleName }
+func (*Module) Version() string { return ModuleVersion }
+
+func (*Module) RegisterComponents(w *cardinal.World) error {
+	if err := cardinal.RegisterComponent[Group](w); err != nil {
+		return err
+	}
+	if err := cardinal.RegisterComponent[Member](w); err != nil {
+		return err
+	}
+	if err := cardinal.RegisterComponent[Role](w); err != nil {
+		return err
+	}
+	return cardinal.RegisterComponent[Invite](w)
+}
+
+func (*Module) RegisterTxs(w *cardinal.World) error {
+	return registerMessages(w)
+}
+
+func (*Module) RegisterReads(w *cardinal.World) error {
+	return registerReads(w)
+}
+
+func (m *Module) RegisterSystems(w *cardinal.World) error {
+	return cardinal.RegisterSystems(w, m.guildSystem)
+}
@@ -0,0 +1,114 @@
+package guild
+
+import (
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type GroupRequest struct {
+	Group types.EntityID `json:"group"`
+}
+
+type MemberInfo struct {
+	PersonaTag string `json:"personaTag"`
+	Rank       Rank   `json:"rank"`
+}
+
+type GroupReply struct {
+	Group   types.EntityID `json:"group"`
+	Name    string         `json:"name"`
+	Members []MemberInfo   `json:"members"`
+	// Invites lists the persona tags with a pending invite to the group.
+	Invites []string `json:"invites"`
+}
+
+type MembershipRequest struct {
+	PersonaTag string `json:"personaTag"`
+}
+
+type InviteInfo struct {
+	Group     types.EntityID `json:"group"`
+	Name      string         `json:"name"`
+	InvitedBy string         `json:"invitedBy"`
+}
+
+type MembershipReply struct {
+	InGroup bool           `json:"inGroup"`
+	Group   types.EntityID `json:"group,omitempty"`
+	Name    string         `json:"name,omitempty"`
+	Rank    Rank           `json:"rank,omitempty"`
+	Invites []InviteInfo   `json:"invites"`
+}
+
+// registerReads registers the /query/guild/group and /query/guild/membership queries.
+func registerReads(w *cardinal.World) error {
+	return cardinal.RegisterReads(w, ModuleName,
+		cardinal.NewRead[GroupRequest, GroupReply]("group", queryGroup),
+		cardinal.NewRead[MembershipRequest, MembershipReply]("membership", queryMembership),
+	)
+}
+
+func queryGroup(wCtx engine.Context, req *GroupRequest) (*GroupReply, error) {
+	group, err := cardinal.GetComponent[Group](wCtx, req.Group)
+	if err != nil {
+		return nil, err
+	}
+	members, err := membersOf(wCtx, req.Group)
+	if err != nil {
+		return nil, err
+	}
+	invites, err := findInvites(wCtx, func(i Invite) bool { return i.Group == req.Group })
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &GroupReply{
+		Group:   req.Group,
+		Name:    group.DisplayName,
+		Members: make([]MemberInfo, 0, len(members)),
+		Invites: make([]string, 0, len(invites)),
+	}
+	for _, m := range members {
+		reply.Members = append(reply.Members, MemberInfo{PersonaTag: m.member.PersonaTag, Rank: m.role.Rank})
+	}
+	for _, i := range invites {
+		reply.Invites = append(reply.Invites, i.invite.PersonaTag)
+	}
+	return reply, nil
+}
+
+func queryMembership(wCtx engine.Context, req *MembershipRequest) (*MembershipReply, error) {
+	reply := &MembershipReply{Invites: []InviteInfo{}}
+	m, err := membershipOf(wCtx, req.PersonaTag)
+	if err != nil {
+		return nil, err
+	}
+	if m != nil {
+		group, err := cardinal.GetComponent[Group](wCtx, m.member.Group)
+		if err != nil {
+			return nil, err
+		}
+		reply.InGroup = true
+		reply.Group = m.member.Group
+		reply.Name = group.DisplayName
+		reply.Rank = m.role.Rank
+	}
+
+	invites, err := findInvites(wCtx, func(i Invite) bool { return i.PersonaTag == req.PersonaTag })
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range invites {
+		group, err := cardinal.GetComponent[Group](wCtx, i.invite.Group)
+		if err != nil {
+			return nil, err
+		}
+		reply.Invites = append(reply.Invites, InviteInfo{
+			Group:     i.invite.Group,
+			Name:      group.DisplayName,
+			InvitedBy: i.invite.InvitedBy,
+		})
+	}
+	return reply, nil
+}
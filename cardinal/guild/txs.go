@@ -0,0 +1,349 @@
+package guild
+
+import (
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// Message names. Messages are served at /tx/guild/<name>.
+const (
+	CreateGroupMessageName  = "create-group"
+	DisbandGroupMessageName = "disband-group"
+	InviteMessageName       = "invite"
+	AcceptInviteMessageName = "accept-invite"
+	KickMessageName         = "kick"
+	LeaveGroupMessageName   = "leave-group"
+)
+
+// Event types emitted to the members of a group.
+const (
+	EventMemberJoined = "guild-member-joined"
+	EventMemberLeft   = "guild-member-left"
+	EventMemberKicked = "guild-member-kicked"
+	EventDisbanded    = "guild-disbanded"
+	// EventInvited is only emitted to the invited persona.
+	EventInvited = "guild-invited"
+)
+
+// CreateGroupMsg creates a group with the sender as its leader. A persona can only be in one group at a time.
+type CreateGroupMsg struct {
+	Name string `json:"name"`
+}
+
+// DisbandGroupMsg removes the sender's group and all of its members and invites. Only the leader can disband a group.
+type DisbandGroupMsg struct{}
+
+// InviteMsg invites a persona to the sender's group. Only leaders and officers can invite.
+type InviteMsg struct {
+	PersonaTag string `json:"personaTag"`
+}
+
+// AcceptInviteMsg accepts the sender's invite to a group. The sender's other invites are discarded.
+type AcceptInviteMsg struct {
+	Group types.EntityID `json:"group"`
+}
+
+// KickMsg removes a persona from the sender's group. Leaders can kick officers and members; officers can only kick
+// members.
+type KickMsg struct {
+	PersonaTag string `json:"personaTag"`
+}
+
+// LeaveGroupMsg removes the sender from their group. The leader cannot leave, and must disband the group instead.
+type LeaveGroupMsg struct{}
+
+// Result is the result of every guild message. Group is the group the message applied to.
+type Result struct {
+	Group types.EntityID `json:"group"`
+}
+
+func registerMessages(w *cardinal.World) error {
+	if err := cardinal.RegisterMessage[CreateGroupMsg, Result](w, CreateGroupMessageName); err != nil {
+		return err
+	}
+	if err := cardinal.RegisterMessage[DisbandGroupMsg, Result](w, DisbandGroupMessageName); err != nil {
+		return err
+	}
+	if err := cardinal.RegisterMessage[InviteMsg, Result](w, InviteMessageName); err != nil {
+		return err
+	}
+	if err := cardinal.RegisterMessage[AcceptInviteMsg, Result](w, AcceptInviteMessageName); err != nil {
+		return err
+	}
+	if err := cardinal.RegisterMessage[KickMsg, Result](w, KickMessageName); err != nil {
+		return err
+	}
+	return cardinal.RegisterMessage[LeaveGroupMsg, Result](w, LeaveGroupMessageName)
+}
+
+// guildSystem processes this tick's guild messages. Groups are created first, and disbanded last, so a group that
+// is created and joined in the same tick works as expected.
+func (m *Module) guildSystem(wCtx engine.Context) error {
+	if err := cardinal.EachMessage[CreateGroupMsg, Result](wCtx,
+		func(tx message.TxData[CreateGroupMsg]) (Result, error) {
+			return createGroup(wCtx, tx)
+		}); err != nil {
+		return err
+	}
+	if err := cardinal.EachMessage[InviteMsg, Result](wCtx,
+		func(tx message.TxData[InviteMsg]) (Result, error) {
+			return invite(wCtx, tx)
+		}); err != nil {
+		return err
+	}
+	if err := cardinal.EachMessage[AcceptInviteMsg, Result](wCtx,
+		func(tx message.TxData[AcceptInviteMsg]) (Result, error) {
+			return m.acceptInvite(wCtx, tx)
+		}); err != nil {
+		return err
+	}
+	if err := cardinal.EachMessage[KickMsg, Result](wCtx,
+		func(tx message.TxData[KickMsg]) (Result, error) {
+			return kick(wCtx, tx)
+		}); err != nil {
+		return err
+	}
+	if err := cardinal.EachMessage[LeaveGroupMsg, Result](wCtx,
+		func(tx message.TxData[LeaveGroupMsg]) (Result, error) {
+			return leaveGroup(wCtx, tx)
+		}); err != nil {
+		return err
+	}
+	return cardinal.EachMessage[DisbandGroupMsg, Result](wCtx,
+		func(tx message.TxData[DisbandGroupMsg]) (Result, error) {
+			return disbandGroup(wCtx, tx)
+		})
+}
+
+func sender[In any](tx message.TxData[In]) (string, error) {
+	if tx.Tx == nil || tx.Tx.PersonaTag == "" {
+		return "", eris.New("guild messages must be signed by a persona")
+	}
+	return tx.Tx.PersonaTag, nil
+}
+
+// senderMembership returns the membership of the sender, or an error if the sender is not in a group.
+func senderMembership[In any](wCtx engine.Context, tx message.TxData[In]) (*membership, error) {
+	personaTag, err := sender(tx)
+	if err != nil {
+		return nil, err
+	}
+	m, err := membershipOf(wCtx, personaTag)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, eris.Errorf("persona %q is not in a group", personaTag)
+	}
+	return m, nil
+}
+
+func createGroup(wCtx engine.Context, tx message.TxData[CreateGroupMsg]) (Result, error) {
+	personaTag, err := sender(tx)
+	if err != nil {
+		return Result{}, err
+	}
+	if tx.Msg.Name == "" {
+		return Result{}, eris.New("group name must not be empty")
+	}
+	if existing, err := membershipOf(wCtx, personaTag); err != nil {
+		return Result{}, err
+	} else if existing != nil {
+		return Result{}, eris.Errorf("persona %q is already in group %d", personaTag, existing.member.Group)
+	}
+
+	group, err := cardinal.Create(wCtx, Group{DisplayName: tx.Msg.Name})
+	if err != nil {
+		return Result{}, err
+	}
+	_, err = cardinal.Create(wCtx, Member{Group: group, PersonaTag: personaTag}, Role{Rank: RankLeader})
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Group: group}, nil
+}
+
+func invite(wCtx engine.Context, tx message.TxData[InviteMsg]) (Result, error) {
+	inviter, err := senderMembership(wCtx, tx)
+	if err != nil {
+		return Result{}, err
+	}
+	group := inviter.member.Group
+	if !inviter.role.canManage(RankMember) {
+		return Result{}, eris.Errorf("persona %q cannot invite to group %d", inviter.member.PersonaTag, group)
+	}
+	if tx.Msg.PersonaTag == "" {
+		return Result{}, eris.New("persona tag must not be empty")
+	}
+	if existing, err := membershipOf(wCtx, tx.Msg.PersonaTag); err != nil {
+		return Result{}, err
+	} else if existing != nil {
+		return Result{}, eris.Errorf("persona %q is already in a group", tx.Msg.PersonaTag)
+	}
+	invites, err := findInvites(wCtx, func(i Invite) bool {
+		return i.Group == group && i.PersonaTag == tx.Msg.PersonaTag
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	if len(invites) > 0 {
+		return Result{}, eris.Errorf("persona %q is already invited to group %d", tx.Msg.PersonaTag, group)
+	}
+
+	_, err = cardinal.Create(wCtx, Invite{
+		Group:      group,
+		PersonaTag: tx.Msg.PersonaTag,
+		InvitedBy:  inviter.member.PersonaTag,
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	err = cardinal.EmitEventTo(wCtx, []string{tx.Msg.PersonaTag}, map[string]any{
+		"type":      EventInvited,
+		"group":     group,
+		"invitedBy": inviter.member.PersonaTag,
+	})
+	return Result{Group: group}, err
+}
+
+func (m *Module) acceptInvite(wCtx engine.Context, tx message.TxData[AcceptInviteMsg]) (Result, error) {
+	personaTag, err := sender(tx)
+	if err != nil {
+		return Result{}, err
+	}
+	group := tx.Msg.Group
+	invites, err := findInvites(wCtx, func(i Invite) bool { return i.PersonaTag == personaTag })
+	if err != nil {
+		return Result{}, err
+	}
+	invited := false
+	for _, i := range invites {
+		if i.invite.Group == group {
+			invited = true
+		}
+	}
+	if !invited {
+		return Result{}, eris.Errorf("persona %q is not invited to group %d", personaTag, group)
+	}
+	members, err := membersOf(wCtx, group)
+	if err != nil {
+		return Result{}, err
+	}
+	if m.maxMembers > 0 && len(members) >= m.maxMembers {
+		return Result{}, eris.Errorf("group %d is full", group)
+	}
+
+	// Joining a group discards the persona's other invites, since a persona can only be in one group.
+	for _, i := range invites {
+		if err := cardinal.Remove(wCtx, i.id); err != nil {
+			return Result{}, err
+		}
+	}
+	_, err = cardinal.Create(wCtx, Member{Group: group, PersonaTag: personaTag}, Role{Rank: RankMember})
+	if err != nil {
+		return Result{}, err
+	}
+	err = EmitToGroup(wCtx, group, map[string]any{
+		"type":       EventMemberJoined,
+		"group":      group,
+		"personaTag": personaTag,
+	})
+	return Result{Group: group}, err
+}
+
+func kick(wCtx engine.Context, tx message.TxData[KickMsg]) (Result, error) {
+	kicker, err := senderMembership(wCtx, tx)
+	if err != nil {
+		return Result{}, err
+	}
+	group := kicker.member.Group
+	target, err := membershipOf(wCtx, tx.Msg.PersonaTag)
+	if err != nil {
+		return Result{}, err
+	}
+	if target == nil || target.member.Group != group {
+		return Result{}, eris.Errorf("persona %q is not in group %d", tx.Msg.PersonaTag, group)
+	}
+	if !kicker.role.canManage(target.role.Rank) {
+		return Result{}, eris.Errorf(
+			"persona %q (%s) cannot kick persona %q (%s)",
+			kicker.member.PersonaTag, kicker.role.Rank, tx.Msg.PersonaTag, target.role.Rank,
+		)
+	}
+
+	// The kicked persona is told before it is removed, so it is still one of the group's recipients.
+	err = EmitToGroup(wCtx, group, map[string]any{
+		"type":       EventMemberKicked,
+		"group":      group,
+		"personaTag": tx.Msg.PersonaTag,
+		"kickedBy":   kicker.member.PersonaTag,
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Group: group}, cardinal.Remove(wCtx, target.id)
+}
+
+func leaveGroup(wCtx engine.Context, tx message.TxData[LeaveGroupMsg]) (Result, error) {
+	leaver, err := senderMembership(wCtx, tx)
+	if err != nil {
+		return Result{}, err
+	}
+	group := leaver.member.Group
+	if leaver.role.Rank == RankLeader {
+		return Result{}, eris.New("the leader cannot leave the group, disband it instead")
+	}
+	if err := cardinal.Remove(wCtx, leaver.id); err != nil {
+		return Result{}, err
+	}
+	err = EmitToGroup(wCtx, group, map[string]any{
+		"type":       EventMemberLeft,
+		"group":      group,
+		"personaTag": leaver.member.PersonaTag,
+	})
+	return Result{Group: group}, err
+}
+
+func disbandGroup(wCtx engine.Context, tx message.TxData[DisbandGroupMsg]) (Result, error) {
+	leader, err := senderMembership(wCtx, tx)
+	if err != nil {
+		return Result{}, err
+	}
+	group := leader.member.Group
+	if leader.role.Rank != RankLeader {
+		return Result{}, eris.New("only the leader can disband the group")
+	}
+
+	err = EmitToGroup(wCtx, group, map[string]any{
+		"type":  EventDisbanded,
+		"group": group,
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	members, err := membersOf(wCtx, group)
+	if err != nil {
+		return Result{}, err
+	}
+	invites, err := findInvites(wCtx, func(i Invite) bool { return i.Group == group })
+	if err != nil {
+		return Result{}, err
+	}
+	ids := []types.EntityID{group}
+	for _, m := range members {
+		ids = append(ids, m.id)
+	}
+	for _, i := range invites {
+		ids = append(ids, i.id)
+	}
+	for _, id := range ids {
+		if err := cardinal.Remove(wCtx, id); err != nil {
+			return Result{}, err
+		}
+	}
+	return Result{Group: group}, nil
+}
@@ -0,0 +1,58 @@
+package cardinal
+
+import (
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+	"pkg.world.dev/world-engine/sign"
+)
+
+// Hooks are callbacks that let applications embedding a World integrate billing, analytics, or other external side
+// effects at well-defined points in the tick loop. Nil hooks are ignored.
+//
+// Hooks run synchronously, so slow work should be handed off to another goroutine. Hooks are not called for ticks
+// that are replayed while the world is recovering.
+type Hooks struct {
+	// OnTickStart is called before any systems are run for a tick.
+	OnTickStart func(tick uint64, timestamp uint64)
+	// OnTickEnd is called after a tick has been committed and its results have been broadcast.
+	OnTickEnd func(results TickResults)
+	// OnTxRejected is called when a transaction submitted to the server is rejected before it reaches the transaction
+	// pool, e.g. because it could not be decoded or its signature or nonce is invalid.
+	OnTxRejected func(msgName string, tx *sign.Transaction, err error)
+}
+
+// AddHooks adds hooks to the world like WithHooks does, for modules that need them. It must be called before the game
+// starts, e.g. from a module's Init method.
+func (w *World) AddHooks(hooks Hooks) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf("engine state is %s, expected %s to add hooks", w.worldStage.Current(), worldstage.Init)
+	}
+	w.hooks = append(w.hooks, hooks)
+	return nil
+}
+
+func (w *World) callTickStartHooks(tick uint64, timestamp uint64) {
+	for _, h := range w.hooks {
+		if h.OnTickStart != nil {
+			h.OnTickStart(tick, timestamp)
+		}
+	}
+}
+
+func (w *World) callTickEndHooks(results TickResults) {
+	for _, h := range w.hooks {
+		if h.OnTickEnd != nil {
+			h.OnTickEnd(results)
+		}
+	}
+}
+
+// NotifyTxRejected calls the OnTxRejected hooks for a transaction that was rejected by the server.
+func (w *World) NotifyTxRejected(msgName string, tx *sign.Transaction, err error) {
+	for _, h := range w.hooks {
+		if h.OnTxRejected != nil {
+			h.OnTxRejected(msgName, tx, err)
+		}
+	}
+}
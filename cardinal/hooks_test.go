@@ -0,0 +1,54 @@
+package cardinal_test
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/sign"
+)
+
+func TestTickHooksAreCalledEachTick(t *testing.T) {
+	var startTicks, endTicks []uint64
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithHooks(cardinal.Hooks{
+		OnTickStart: func(tick uint64, _ uint64) {
+			startTicks = append(startTicks, tick)
+		},
+		OnTickEnd: func(results cardinal.TickResults) {
+			endTicks = append(endTicks, results.Tick)
+		},
+	}))
+	tf.StartWorld()
+
+	first := tf.World.CurrentTick()
+	tf.DoTick()
+	tf.DoTick()
+
+	assert.DeepEqual(t, startTicks, []uint64{first, first + 1})
+	assert.DeepEqual(t, endTicks, []uint64{first, first + 1})
+}
+
+func TestTxRejectedHookIsCalledForInvalidTransaction(t *testing.T) {
+	var mu sync.Mutex
+	var rejected []string
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithHooks(cardinal.Hooks{
+		OnTxRejected: func(msgName string, _ *sign.Transaction, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Check(t, err != nil)
+			rejected = append(rejected, msgName)
+		},
+	}))
+	tf.StartWorld()
+
+	// A transaction without a persona tag is rejected by the server.
+	res := tf.Post("tx/persona/create-persona", sign.Transaction{})
+	assert.Equal(t, res.StatusCode, http.StatusBadRequest)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.DeepEqual(t, rejected, []string{"persona.create-persona"})
+}
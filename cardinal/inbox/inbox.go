@@ -0,0 +1,54 @@
+package inbox
+
+import (
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// Inbox holds the messages a persona has received, oldest first.
+type Inbox struct {
+	Owner    string `json:"owner"`
+	Messages []Mail `json:"messages"`
+	// LastID is the ID of the most recent message. Message IDs keep increasing when old messages are dropped.
+	LastID uint64 `json:"lastId"`
+}
+
+func (Inbox) Name() string { return "inbox" }
+
+// Mail is a message in an inbox.
+type Mail struct {
+	ID   uint64 `json:"id"`
+	From string `json:"from"`
+	Body string `json:"body"`
+	// SentAt is the tick the message was sent in.
+	SentAt uint64 `json:"sentAt"`
+}
+
+// add appends the mail to the inbox, dropping the oldest messages so the inbox holds at most capacity messages.
+func (in *Inbox) add(mail Mail, capacity int) Mail {
+	in.LastID++
+	mail.ID = in.LastID
+	in.Messages = append(in.Messages, mail)
+	if extra := len(in.Messages) - capacity; extra > 0 {
+		in.Messages = append([]Mail(nil), in.Messages[extra:]...)
+	}
+	return mail
+}
+
+// findInbox returns the persona's inbox, if the persona has received any messages.
+func findInbox(wCtx engine.Context, personaTag string) (types.EntityID, *Inbox, bool, error) {
+	ids, err := cardinal.NewSearch().
+		Entity(filter.Contains(filter.Component[Inbox]())).
+		Where(cardinal.FilterFunction[Inbox](func(in Inbox) bool { return in.Owner == personaTag })).
+		Collect(wCtx)
+	if err != nil || len(ids) == 0 {
+		return 0, nil, false, err
+	}
+	inbox, err := cardinal.GetComponent[Inbox](wCtx, ids[0])
+	if err != nil {
+		return 0, nil, false, err
+	}
+	return ids[0], inbox, true, nil
+}
@@ -0,0 +1,84 @@
+package inbox_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/inbox"
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+func setupInbox(t *testing.T, opts ...inbox.Option) *testutils.TestFixture {
+	tf := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, tf.World.UseModule(inbox.NewModule(opts...)))
+	tf.CreatePersona("alice", "alice-address")
+	tf.CreatePersona("bob", "bob-address")
+	return tf
+}
+
+func send(tf *testutils.TestFixture, from, to, body string) types.TxHash {
+	sendMsg, ok := tf.World.GetMessageByFullName(inbox.ModuleName + "." + inbox.SendMessageName)
+	assert.Check(tf, ok)
+	msg := inbox.SendMessageTx{Recipient: to, Body: body}
+	return tf.AddTransaction(sendMsg.ID(), msg, testutils.UniqueSignatureWithName(from))
+}
+
+func messages(tf *testutils.TestFixture, req inbox.MessagesRequest) inbox.MessagesReply {
+	res := tf.Post("query/inbox/messages", req)
+	assert.Equal(tf, res.StatusCode, http.StatusOK)
+	var reply inbox.MessagesReply
+	assert.NilError(tf, json.NewDecoder(res.Body).Decode(&reply))
+	return reply
+}
+
+func TestSendMessage(t *testing.T) {
+	tf := setupInbox(t, inbox.WithCapacity(3))
+
+	for _, body := range []string{"one", "two", "three", "four"} {
+		send(tf, "alice", "bob", body)
+	}
+	tf.DoTick()
+
+	// The inbox only keeps the three most recent messages.
+	reply := messages(tf, inbox.MessagesRequest{PersonaTag: "bob", Limit: 2})
+	assert.Equal(t, len(reply.Messages), 2)
+	assert.Equal(t, reply.Messages[0].Body, "four")
+	assert.Equal(t, reply.Messages[0].From, "alice")
+	assert.Equal(t, reply.Messages[1].Body, "three")
+	assert.Equal(t, reply.Next, reply.Messages[1].ID)
+
+	reply = messages(tf, inbox.MessagesRequest{PersonaTag: "bob", Before: reply.Next, Limit: 2})
+	assert.Equal(t, len(reply.Messages), 1)
+	assert.Equal(t, reply.Messages[0].Body, "two")
+	assert.Equal(t, reply.Next, uint64(0))
+
+	assert.Equal(t, len(messages(tf, inbox.MessagesRequest{PersonaTag: "alice"}).Messages), 0)
+
+	res := tf.Post("query/events/list", handler.ListEventsRequest{Type: inbox.EventReceived, Recipient: "bob"})
+	var events handler.ListEventsResponse
+	assert.NilError(t, json.NewDecoder(res.Body).Decode(&events))
+	assert.Equal(t, len(events.Events), 4)
+}
+
+func TestSendMessageValidation(t *testing.T) {
+	tf := setupInbox(t, inbox.WithMaxLength(10))
+
+	unknownHash := send(tf, "alice", "carol", "hi")
+	emptyHash := send(tf, "alice", "bob", "   ")
+	longHash := send(tf, "alice", "bob", strings.Repeat("a", 11))
+	tf.DoTick()
+
+	wCtx := cardinal.NewReadOnlyWorldContext(tf.World)
+	_, errs, _ := wCtx.GetTransactionReceipt(unknownHash)
+	assert.ErrorContains(t, errs[0], `recipient "carol" does not exist`)
+	_, errs, _ = wCtx.GetTransactionReceipt(emptyHash)
+	assert.ErrorContains(t, errs[0], "must not be empty")
+	_, errs, _ = wCtx.GetTransactionReceipt(longHash)
+	assert.ErrorContains(t, errs[0], "the maximum is 10")
+}
@@ -0,0 +1,150 @@
+// Package inbox is a module for direct messages between personas. A SendMessageTx adds a message to the recipient's
+// Inbox, and the recipient receives an EventReceived event, which the relay forwards to them as a notification.
+//
+// Inboxes are bounded: once an inbox is full, the oldest message is dropped to make room for a new one.
+package inbox
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+const (
+	ModuleName    = "inbox"
+	ModuleVersion = "v1.0.0"
+
+	SendMessageName = "send"
+
+	// EventReceived is emitted to the recipient of a message.
+	EventReceived = "inbox-message-received"
+
+	// DefaultCapacity is the default number of messages an inbox keeps.
+	DefaultCapacity = 100
+	// DefaultMaxLength is the default maximum length of a message body, in characters.
+	DefaultMaxLength = 500
+)
+
+var _ cardinal.Module = &Module{}
+
+// SendMessageTx sends a message from the sender's persona to the recipient's inbox.
+type SendMessageTx struct {
+	Recipient string `json:"recipient"`
+	Body      string `json:"body"`
+}
+
+type SendMessageResult struct {
+	// ID identifies the message in the recipient's inbox.
+	ID uint64 `json:"id"`
+}
+
+type Option func(*Module)
+
+// WithCapacity sets the number of messages an inbox keeps.
+func WithCapacity(capacity int) Option {
+	return func(m *Module) {
+		m.capacity = capacity
+	}
+}
+
+// WithMaxLength sets the maximum length of a message body, in characters.
+func WithMaxLength(maxLength int) Option {
+	return func(m *Module) {
+		m.maxLength = maxLength
+	}
+}
+
+type Module struct {
+	cardinal.ModuleBase
+	capacity  int
+	maxLength int
+}
+
+func NewModule(opts ...Option) *Module {
+	m := &Module{capacity: DefaultCapacity, maxLength: DefaultMaxLength}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func (*Module) Name() string    { return ModuleName }
+func (*Module) Version() string { return ModuleVersion }
+
+func (*Module) RegisterComponents(w *cardinal.World) error {
+	return cardinal.RegisterComponent[Inbox](w)
+}
+
+func (*Module) RegisterTxs(w *cardinal.World) error {
+	return cardinal.RegisterMessage[SendMessageTx, SendMessageResult](w, SendMessageName)
+}
+
+func (m *Module) RegisterSystems(w *cardinal.World) error {
+	return cardinal.RegisterSystems(w, m.inboxSystem)
+}
+
+func (m *Module) Init(*cardinal.World) error {
+	if m.capacity <= 0 {
+		return eris.Errorf("inbox capacity must be positive, got %d", m.capacity)
+	}
+	if m.maxLength <= 0 {
+		return eris.Errorf("maximum message length must be positive, got %d", m.maxLength)
+	}
+	return nil
+}
+
+func (m *Module) inboxSystem(wCtx engine.Context) error {
+	return cardinal.EachMessage[SendMessageTx, SendMessageResult](wCtx,
+		func(tx message.TxData[SendMessageTx]) (SendMessageResult, error) {
+			return m.send(wCtx, tx)
+		})
+}
+
+func (m *Module) send(wCtx engine.Context, tx message.TxData[SendMessageTx]) (SendMessageResult, error) {
+	if tx.Tx == nil || tx.Tx.PersonaTag == "" {
+		return SendMessageResult{}, eris.New("messages must be signed by a persona")
+	}
+	sender, recipient := tx.Tx.PersonaTag, tx.Msg.Recipient
+	body := strings.TrimSpace(tx.Msg.Body)
+	if body == "" {
+		return SendMessageResult{}, eris.New("message body must not be empty")
+	}
+	if length := utf8.RuneCountInString(body); length > m.maxLength {
+		return SendMessageResult{}, eris.Errorf("message body is %d characters, the maximum is %d", length, m.maxLength)
+	}
+	if _, err := wCtx.GetSignerForPersonaTag(recipient, 0); err != nil {
+		return SendMessageResult{}, eris.Wrapf(err, "recipient %q does not exist", recipient)
+	}
+
+	id, inbox, found, err := findInbox(wCtx, recipient)
+	if err != nil {
+		return SendMessageResult{}, err
+	}
+	if !found {
+		inbox = &Inbox{Owner: recipient}
+	}
+	mail := inbox.add(Mail{From: sender, Body: body, SentAt: wCtx.CurrentTick()}, m.capacity)
+	if found {
+		err = cardinal.SetComponent[Inbox](wCtx, id, inbox)
+	} else {
+		_, err = cardinal.Create(wCtx, *inbox)
+	}
+	if err != nil {
+		return SendMessageResult{}, err
+	}
+
+	err = cardinal.EmitEventTo(wCtx, []string{recipient}, map[string]any{
+		"type": EventReceived,
+		"id":   mail.ID,
+		"from": sender,
+	})
+	if err != nil {
+		return SendMessageResult{}, err
+	}
+	return SendMessageResult{ID: mail.ID}, nil
+}
@@ -0,0 +1,54 @@
+package inbox
+
+import (
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// DefaultPageSize is the number of messages returned by the messages query when no limit is given.
+const DefaultPageSize = 20
+
+type MessagesRequest struct {
+	PersonaTag string `json:"personaTag"`
+	// Before only returns messages with a lower ID. Pass the Next cursor of the previous page to get the next page.
+	Before uint64 `json:"before,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+type MessagesReply struct {
+	// Messages are returned newest first.
+	Messages []Mail `json:"messages"`
+	// Next is the cursor of the next page, or zero if there are no older messages.
+	Next uint64 `json:"next,omitempty"`
+}
+
+// RegisterReads registers the /query/inbox/messages query.
+func (*Module) RegisterReads(w *cardinal.World) error {
+	return cardinal.RegisterReads(w, ModuleName,
+		cardinal.NewRead[MessagesRequest, MessagesReply]("messages", queryMessages),
+	)
+}
+
+func queryMessages(wCtx engine.Context, req *MessagesRequest) (*MessagesReply, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	reply := &MessagesReply{Messages: []Mail{}}
+	_, inbox, found, err := findInbox(wCtx, req.PersonaTag)
+	if err != nil || !found {
+		return reply, err
+	}
+	for i := len(inbox.Messages) - 1; i >= 0; i-- {
+		mail := inbox.Messages[i]
+		if req.Before != 0 && mail.ID >= req.Before {
+			continue
+		}
+		if len(reply.Messages) == limit {
+			reply.Next = reply.Messages[limit-1].ID
+			break
+		}
+		reply.Messages = append(reply.Messages, mail)
+	}
+	return reply, nil
+}
@@ -77,7 +77,7 @@ func TestWorldLogger(t *testing.T) {
 	log.World(&bufLogger, world, zerolog.InfoLevel)
 	jsonWorldInfoString := `{
 					"level":"info",
-					"total_components":2,
+					"total_components":3,
 					"components":
 						[
 							{
@@ -86,14 +86,25 @@ func TestWorldLogger(t *testing.T) {
 							},
 							{
 								"component_id":2,
+								"component_name":"PersonaReservationComponent"
+							},
+							{
+								"component_id":3,
 								"component_name":"EnergyComp"
 							}
 						],
-					"total_systems":2,
+					"total_systems":9,
 					"systems":
 						[
 							"cardinal.CreatePersonaSystem",
-							"cardinal.AuthorizePersonaAddressSystem"
+							"cardinal.AuthorizePersonaAddressSystem",
+							"cardinal.RevokePersonaAddressSystem",
+							"cardinal.RotatePersonaSignerSystem",
+							"cardinal.TransferPersonaSystem",
+							"cardinal.ChangeSignerSystem",
+							"cardinal.ReservePersonaSystem",
+							"cardinal.ConfirmPersonaSystem",
+							"cardinal.MergePersonaSystem"
 						]
 				}
 `
@@ -130,7 +141,7 @@ func TestWorldLogger(t *testing.T) {
 			{
 				"level":"debug",
 				"components":[{
-				"component_id":2,
+				"component_id":3,
 					"component_name":"EnergyComp"
 				}],
 				"entity_id":0,"archetype_id":0
@@ -148,7 +159,7 @@ func TestWorldLogger(t *testing.T) {
 			"level":"debug",
 			"components":[
 				{
-					"component_id":2,
+					"component_id":3,
 					"component_name":"EnergyComp"
 				}],
 			"entity_id":0,
@@ -178,7 +189,7 @@ func TestWorldLogger(t *testing.T) {
 				"level":"debug",
 				"entity_id":"0",
 				"component_name":"EnergyComp",
-				"component_id":2,
+				"component_id":3,
 				"message":"entity updated",
 				"system":"log_test.testSystemWarningTrigger"
 			}`, logStrings[2],
@@ -196,7 +207,7 @@ func TestWorldLogger(t *testing.T) {
 				"components":
 					[
 						{
-							"component_id":2,
+							"component_id":3,
 							"component_name":"EnergyComp"
 						}
 					],
@@ -211,7 +222,7 @@ func TestWorldLogger(t *testing.T) {
 				"components":
 					[
 						{
-							"component_id":2,
+							"component_id":3,
 							"component_name":"EnergyComp"
 						}
 					],
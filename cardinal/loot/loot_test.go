@@ -0,0 +1,124 @@
+package loot_test
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/loot"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+)
+
+func TestLoadTables(t *testing.T) {
+	tables, err := loot.LoadTables("testdata/tables.json")
+	assert.NilError(t, err)
+	assert.Equal(t, len(tables), 2)
+
+	// Defaults are filled in.
+	boss := tables[1]
+	assert.Equal(t, boss.Rolls, 1)
+	assert.Equal(t, boss.Entries[0].Min, 1)
+	assert.Equal(t, boss.Entries[0].Max, 1)
+}
+
+func TestInvalidTables(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{"no name", `[{"entries": [{"item": "a", "weight": 1}]}]`, "name must not be empty"},
+		{"no entries", `[{"name": "t"}]`, "has no entries"},
+		{"zero weight", `[{"name": "t", "entries": [{"item": "a"}]}]`, "must have a positive weight"},
+		{"bad range", `[{"name": "t", "entries": [{"item": "a", "weight": 1, "min": 3, "max": 2}]}]`, "invalid count"},
+		{
+			"duplicate",
+			`[{"name": "t", "entries": [{"item": "a", "weight": 1}]},
+			  {"name": "t", "entries": [{"item": "a", "weight": 1}]}]`,
+			"defined more than once",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := loot.ParseTables([]byte(tc.data))
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestRollIsDeterministic(t *testing.T) {
+	tables, err := loot.LoadTables("testdata/tables.json")
+	assert.NilError(t, err)
+	chest := tables[0]
+
+	seed := sha256.Sum256([]byte("seed"))
+	first := chest.Roll(loot.NewRNG(seed))
+	second := chest.Roll(loot.NewRNG(seed))
+	assert.DeepEqual(t, first, second)
+	assert.Equal(t, len(first.Draws), 3)
+
+	// A roll can be reproduced from the seed and position in its audit record.
+	rng := loot.NewRNG(seed)
+	chest.Roll(rng)
+	later := chest.Roll(rng)
+	assert.Check(t, later.Position > 0)
+	replay := loot.NewRNG(seed)
+	for replay.Position() < later.Position {
+		replay.Uint64()
+	}
+	assert.DeepEqual(t, chest.Roll(replay), later)
+
+	different := chest.Roll(loot.NewRNG(sha256.Sum256([]byte("other seed"))))
+	assert.Check(t, different.Seed != first.Seed)
+}
+
+func TestRollFollowsWeights(t *testing.T) {
+	table := loot.Table{
+		Name: "coin",
+		Entries: []loot.Entry{
+			{Item: "heads", Weight: 3},
+			{Item: "tails", Weight: 1},
+		},
+	}
+	assert.NilError(t, table.Validate())
+
+	rng := loot.NewRNG(sha256.Sum256([]byte("coin")))
+	counts := map[string]int{}
+	const rolls = 4000
+	for i := 0; i < rolls; i++ {
+		roll := table.Roll(rng)
+		for _, drop := range roll.Drops {
+			counts[drop.Item] += drop.Count
+		}
+	}
+	assert.Equal(t, counts["heads"]+counts["tails"], rolls)
+	// Expect 3000 heads; allow for generous variance.
+	assert.Check(t, counts["heads"] > 2800 && counts["heads"] < 3200, "got %d heads", counts["heads"])
+}
+
+func TestModuleServesTables(t *testing.T) {
+	tables, err := loot.LoadTables("testdata/tables.json")
+	assert.NilError(t, err)
+	module, err := loot.NewModule(tables...)
+	assert.NilError(t, err)
+
+	_, err = module.Roll(loot.NewRNG(sha256.Sum256(nil)), "missing")
+	assert.ErrorContains(t, err, `loot table "missing" does not exist`)
+	roll, err := module.Roll(loot.NewRNG(sha256.Sum256(nil)), "boss")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, roll.Drops, []loot.Drop{{Item: "crown", Count: 1}})
+
+	tf := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, tf.World.UseModule(module))
+	tf.DoTick()
+
+	res := tf.Post("query/loot/tables", loot.TablesRequest{})
+	assert.Equal(t, res.StatusCode, http.StatusOK)
+	var reply loot.TablesReply
+	assert.NilError(t, json.NewDecoder(res.Body).Decode(&reply))
+	assert.Equal(t, len(reply.Tables), 2)
+	assert.Equal(t, reply.Tables[0].Name, "boss")
+	assert.Equal(t, reply.Tables[1].Name, "chest")
+}
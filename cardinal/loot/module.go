@@ -0,0 +1,92 @@
+// Package loot provides weighted loot tables that are rolled with a deterministic RNG, so that drops are identical
+// on every replica and every replay of a tick, and every roll can be audited from the transaction receipt.
+//
+// Add the tables to a world with UseModule, and roll them from a system:
+//
+//	tables, err := loot.LoadTables("loot.json")
+//	...
+//	lootModule, err := loot.NewModule(tables...)
+//	...
+//	err = world.UseModule(lootModule)
+//	...
+//	cardinal.EachMessage[OpenChestMsg, OpenChestResult](wCtx,
+//		func(tx message.TxData[OpenChestMsg]) (OpenChestResult, error) {
+//			roll, err := lootModule.Roll(loot.NewTxRNG(wCtx, tx.Hash), "chest")
+//			...
+//			return OpenChestResult{Loot: roll}, nil
+//		})
+package loot
+
+import (
+	"sort"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+const (
+	ModuleName    = "loot"
+	ModuleVersion = "v1.0.0"
+)
+
+var _ cardinal.Module = &Module{}
+
+// Module makes loot tables available to a world. It serves the table definitions at /query/loot/tables, so clients
+// can show drop rates.
+type Module struct {
+	cardinal.ModuleBase
+	tables map[string]Table
+}
+
+func NewModule(tables ...Table) (*Module, error) {
+	m := &Module{tables: map[string]Table{}}
+	for _, table := range tables {
+		if err := table.Validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := m.tables[table.Name]; ok {
+			return nil, eris.Errorf("loot table %q is defined more than once", table.Name)
+		}
+		m.tables[table.Name] = table
+	}
+	return m, nil
+}
+
+func (*Module) Name() string    { return ModuleName }
+func (*Module) Version() string { return ModuleVersion }
+
+type TablesRequest struct{}
+
+type TablesReply struct {
+	Tables []Table `json:"tables"`
+}
+
+func (m *Module) RegisterReads(w *cardinal.World) error {
+	return cardinal.RegisterReads(w, ModuleName,
+		cardinal.NewRead[TablesRequest, TablesReply]("tables", func(engine.Context, *TablesRequest) (
+			*TablesReply, error,
+		) {
+			return &TablesReply{Tables: m.Tables()}, nil
+		}))
+}
+
+// Tables returns the module's loot tables, sorted by name.
+func (m *Module) Tables() []Table {
+	tables := make([]Table, 0, len(m.tables))
+	for _, table := range m.tables {
+		tables = append(tables, table)
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Name < tables[j].Name })
+	return tables
+}
+
+// Roll rolls the named table. Use NewTxRNG to get an RNG for the transaction that causes the roll.
+func (m *Module) Roll(rng *RNG, tableName string) (Roll, error) {
+	table, ok := m.tables[tableName]
+	if !ok {
+		return Roll{}, eris.Errorf("loot table %q does not exist", tableName)
+	}
+	return table.Roll(rng), nil
+}
@@ -0,0 +1,26 @@
+package loot
+
+import (
+	"crypto/sha256"
+	"strconv"
+
+	"pkg.world.dev/world-engine/cardinal/rng"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// RNG is a deterministic random number generator; see rng.RNG. Within a system, wCtx.Rand returns one that is seeded
+// for the tick and the system.
+type RNG = rng.RNG
+
+func NewRNG(seed [sha256.Size]byte) *RNG {
+	return rng.New(seed)
+}
+
+// NewTxRNG returns an RNG for a single transaction. It is seeded with the world's namespace, the current tick, and
+// the transaction hash, so it produces the same numbers whenever the transaction is processed, and different numbers
+// for every other transaction.
+func NewTxRNG(wCtx engine.Context, txHash types.TxHash) *RNG {
+	seed := wCtx.Namespace() + "/" + strconv.FormatUint(wCtx.CurrentTick(), 10) + "/" + string(txHash)
+	return NewRNG(sha256.Sum256([]byte(seed)))
+}
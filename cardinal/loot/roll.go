@@ -0,0 +1,73 @@
+package loot
+
+// Roll is the outcome of rolling a loot table, along with everything needed to audit it. Return it as, or as part of,
+// the result of the message that caused the roll, so that it is recorded in the transaction's receipt.
+type Roll struct {
+	Table string `json:"table"`
+	// Seed and Position identify the first random number used for the roll. Rolling the same table with an RNG that
+	// has the same seed, at the same position, reproduces the roll exactly.
+	Seed     string `json:"seed"`
+	Position uint64 `json:"position"`
+	Draws    []Draw `json:"draws"`
+	// Drops sums the items of all draws, in the order they were first drawn.
+	Drops []Drop `json:"drops"`
+}
+
+// Draw is a single pick from a loot table.
+type Draw struct {
+	// Value is the random point in [0, total weight) that selected the entry.
+	Value uint64 `json:"value"`
+	Entry int    `json:"entry"`
+	Item  string `json:"item"`
+	Count int    `json:"count"`
+}
+
+type Drop struct {
+	Item  string `json:"item"`
+	Count int    `json:"count"`
+}
+
+// Roll picks the table's entries using the given RNG. The table must be valid; see Validate.
+func (t *Table) Roll(rng *RNG) Roll {
+	roll := Roll{
+		Table:    t.Name,
+		Seed:     rng.Seed(),
+		Position: rng.Position(),
+		Draws:    make([]Draw, 0, t.Rolls),
+		Drops:    []Drop{},
+	}
+	total := t.totalWeight()
+	dropIndex := map[string]int{}
+	for i := 0; i < t.Rolls; i++ {
+		value := rng.Uint64n(total)
+		index := t.pick(value)
+		entry := t.Entries[index]
+		count := entry.Min
+		if entry.Max > entry.Min {
+			count += int(rng.Uint64n(uint64(entry.Max - entry.Min + 1)))
+		}
+		roll.Draws = append(roll.Draws, Draw{Value: value, Entry: index, Item: entry.Item, Count: count})
+
+		if entry.Item == "" {
+			continue
+		}
+		if j, ok := dropIndex[entry.Item]; ok {
+			roll.Drops[j].Count += count
+		} else {
+			dropIndex[entry.Item] = len(roll.Drops)
+			roll.Drops = append(roll.Drops, Drop{Item: entry.Item, Count: count})
+		}
+	}
+	return roll
+}
+
+// pick returns the index of the entry whose cumulative weight range contains value.
+func (t *Table) pick(value uint64) int {
+	for i, entry := range t.Entries {
+		if value < entry.Weight {
+			return i
+		}
+		value -= entry.Weight
+	}
+	return len(t.Entries) - 1
+}
@@ -0,0 +1,119 @@
+package loot
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/rotisserie/eris"
+)
+
+// Table is a weighted loot table. Each roll picks one entry, with a probability proportional to the entry's weight.
+//
+// Tables are usually defined in a JSON data file and loaded with LoadTables:
+//
+//	[
+//	  {
+//	    "name": "chest",
+//	    "rolls": 2,
+//	    "entries": [
+//	      {"item": "gold", "weight": 70, "min": 5, "max": 20},
+//	      {"item": "sword", "weight": 25},
+//	      {"item": "", "weight": 5}
+//	    ]
+//	  }
+//	]
+type Table struct {
+	Name string `json:"name"`
+	// Rolls is the number of entries picked each time the table is rolled. It defaults to 1.
+	Rolls   int     `json:"rolls,omitempty"`
+	Entries []Entry `json:"entries"`
+}
+
+// Entry is a possible outcome of a roll. An entry with an empty item drops nothing.
+type Entry struct {
+	Item   string `json:"item"`
+	Weight uint64 `json:"weight"`
+	// Min and Max bound the number of items that are dropped when the entry is picked. Both default to 1.
+	Min int `json:"min,omitempty"`
+	Max int `json:"max,omitempty"`
+}
+
+// Validate checks that the table can be rolled, and fills in defaults.
+func (t *Table) Validate() error {
+	if t.Name == "" {
+		return eris.New("loot table name must not be empty")
+	}
+	if t.Rolls == 0 {
+		t.Rolls = 1
+	}
+	if t.Rolls < 0 {
+		return eris.Errorf("loot table %q: rolls must be positive, got %d", t.Name, t.Rolls)
+	}
+	if len(t.Entries) == 0 {
+		return eris.Errorf("loot table %q has no entries", t.Name)
+	}
+	var total uint64
+	for i := range t.Entries {
+		entry := &t.Entries[i]
+		if entry.Weight == 0 {
+			return eris.Errorf("loot table %q: entry %d (%q) must have a positive weight", t.Name, i, entry.Item)
+		}
+		if total+entry.Weight < total {
+			return eris.Errorf("loot table %q: total weight overflows", t.Name)
+		}
+		total += entry.Weight
+		if entry.Min == 0 {
+			entry.Min = 1
+		}
+		if entry.Max == 0 {
+			entry.Max = entry.Min
+		}
+		if entry.Min < 0 || entry.Max < entry.Min {
+			return eris.Errorf(
+				"loot table %q: entry %d (%q) has an invalid count range [%d, %d]",
+				t.Name, i, entry.Item, entry.Min, entry.Max,
+			)
+		}
+	}
+	return nil
+}
+
+func (t *Table) totalWeight() uint64 {
+	var total uint64
+	for _, entry := range t.Entries {
+		total += entry.Weight
+	}
+	return total
+}
+
+// ParseTables decodes and validates a JSON array of loot tables.
+func ParseTables(data []byte) ([]Table, error) {
+	var tables []Table
+	if err := json.Unmarshal(data, &tables); err != nil {
+		return nil, eris.Wrap(err, "failed to decode loot tables")
+	}
+	seen := map[string]bool{}
+	for i := range tables {
+		if err := tables[i].Validate(); err != nil {
+			return nil, err
+		}
+		if seen[tables[i].Name] {
+			return nil, eris.Errorf("loot table %q is defined more than once", tables[i].Name)
+		}
+		seen[tables[i].Name] = true
+	}
+	return tables, nil
+}
+
+// LoadTables reads the loot tables from a JSON data file. See Table for the format.
+func LoadTables(path string) ([]Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to read loot tables from %q", path)
+	}
+	tables, err := ParseTables(data)
+	if err != nil {
+		return nil, eris.Wrapf(err, "invalid loot tables in %q", path)
+	}
+	return tables, nil
+}
@@ -41,6 +41,13 @@ type MessageType[In, Out any] struct { //nolint:revive // this is fine for now.
 	group      string
 	inEVMType  *ethereumAbi.Type
 	outEVMType *ethereumAbi.Type
+	// decoding configures how transaction payloads are decoded into In; see WithStrictDecoding and WithFieldNaming.
+	decoding codec.Options
+	codec    *codec.Codec[In]
+	// parallel is set by WithParallelExecution.
+	parallel bool
+	// adminSignature is set by WithAdminSignature.
+	adminSignature bool
 }
 
 // NewMessageType creates a new message type. It accepts two generic type parameters: the first for the message input,
@@ -61,6 +68,7 @@ func NewMessageType[In, Out any](
 	for _, opt := range opts {
 		opt(msg)
 	}
+	msg.codec = codec.NewCodec[In](msg.decoding)
 	if !isValidMessageText(msg.name) || !isValidMessageText(msg.group) {
 		panic(fmt.Sprintf("Invalid MessageType: %q: message group and name must only contain alphanumerics, "+
 			"dashes (-), and/or underscores (_). Must also start/end with an alphanumeric.", msg.FullName()))
@@ -131,19 +139,40 @@ func (t *MessageType[In, Out]) GetReceipt(wCtx engine.Context, hash types.TxHash
 }
 
 func (t *MessageType[In, Out]) Each(wCtx engine.Context, fn func(TxData[In]) (Out, error)) {
+	defer trackHandler(wCtx, t.FullName())()
 	for _, txData := range t.In(wCtx) {
-		if result, err := fn(txData); err != nil {
-			err = eris.Wrap(err, "")
-			wCtx.Logger().Err(err).Msgf("tx %s from %s encountered an error with message=%+v and stack trace:\n %s",
-				txData.Hash,
-				txData.Tx.PersonaTag,
-				txData.Msg,
-				eris.ToString(err, true),
-			)
-			t.AddError(wCtx, txData.Hash, err)
-		} else {
-			t.SetResult(wCtx, txData.Hash, result)
-		}
+		result, err := fn(txData)
+		t.settle(wCtx, txData, result, err)
+	}
+}
+
+// handlerTracker is implemented by the contexts that report which message types' handlers changed the same state.
+type handlerTracker interface {
+	TrackMessageHandler(msgName string) (done func())
+}
+
+// trackHandler attributes the changes that are made through the context to the message, until the returned function
+// is called.
+func trackHandler(wCtx engine.Context, msgName string) func() {
+	if tracker, ok := wCtx.(handlerTracker); ok {
+		return tracker.TrackMessageHandler(msgName)
+	}
+	return func() {}
+}
+
+// settle records the outcome of the transaction as its receipt.
+func (t *MessageType[In, Out]) settle(wCtx engine.Context, txData TxData[In], result Out, err error) {
+	if err != nil {
+		err = eris.Wrap(err, "")
+		wCtx.Logger().Err(err).Msgf("tx %s from %s encountered an error with message=%+v and stack trace:\n %s",
+			txData.Hash,
+			txData.Tx.PersonaTag,
+			txData.Msg,
+			eris.ToString(err, true),
+		)
+		t.AddError(wCtx, txData.Hash, err)
+	} else {
+		t.SetResult(wCtx, txData.Hash, result)
 	}
 }
 
@@ -164,11 +193,11 @@ func (t *MessageType[In, Out]) In(wCtx engine.Context) []TxData[In] {
 }
 
 func (t *MessageType[In, Out]) Encode(a any) ([]byte, error) {
-	return codec.Encode(a)
+	return t.codec.Encode(a)
 }
 
 func (t *MessageType[In, Out]) Decode(bytes []byte) (any, error) {
-	return codec.Decode[In](bytes)
+	return t.codec.Decode(bytes)
 }
 
 // ABIEncode encodes the input to the message's matching evm type. If the input is not either of the message's
@@ -216,9 +245,21 @@ func (t *MessageType[In, Out]) DecodeEVMBytes(bz []byte) (any, error) {
 	return input, nil
 }
 
-// GetInFieldInformation returns a map of the fields of the message's "In" type and it's field types.
+// GetInFieldInformation returns a map of the fields of the message's "In" type and it's field types. The fields are
+// named as the message's field naming policy expects them.
 func (t *MessageType[In, Out]) GetInFieldInformation() map[string]any {
-	return types.GetFieldInformation(reflect.TypeOf(new(In)).Elem())
+	if t.decoding.Naming == codec.FieldNamingGo {
+		return types.GetFieldInformation(reflect.TypeOf(new(In)).Elem())
+	}
+	return types.GetFieldInformationNamed(reflect.TypeOf(new(In)).Elem(), t.decoding.Naming.Key)
+}
+
+func (t *MessageType[In, Out]) InType() reflect.Type {
+	return reflect.TypeOf(new(In)).Elem()
+}
+
+func (t *MessageType[In, Out]) OutType() reflect.Type {
+	return reflect.TypeOf(new(Out)).Elem()
 }
 
 // -------------------------- Options --------------------------
@@ -250,6 +291,36 @@ func WithCustomMessageGroup[In, Out any](group string) MessageOption[In, Out] {
 	}
 }
 
+// WithAdminSignature makes the server accept the message only as a system transaction that is signed by one of the
+// world's admin signers; see cardinal.WithAdminSigners. It is meant for messages that operate on the whole world
+// rather than on the persona that sends them, such as content imports.
+func WithAdminSignature[In, Out any]() MessageOption[In, Out] {
+	return func(mt *MessageType[In, Out]) {
+		mt.adminSignature = true
+	}
+}
+
+// RequiresAdminSignature reports whether the message was declared with WithAdminSignature.
+func (t *MessageType[In, Out]) RequiresAdminSignature() bool {
+	return t.adminSignature
+}
+
+// WithStrictDecoding rejects transaction payloads that have fields the message doesn't have, or that are missing
+// fields tagged `cardinal:"required"`, instead of leaving the fields zero valued.
+func WithStrictDecoding[In, Out any]() MessageOption[In, Out] {
+	return func(mt *MessageType[In, Out]) {
+		mt.decoding.Strict = true
+	}
+}
+
+// WithFieldNaming sets the naming policy of the fields in transaction payloads that don't have a name in their json
+// tag. For example, with codec.FieldNamingSnakeCase, a field named PlayerID is sent as "player_id".
+func WithFieldNaming[In, Out any](naming codec.FieldNaming) MessageOption[In, Out] {
+	return func(mt *MessageType[In, Out]) {
+		mt.decoding.Naming = naming
+	}
+}
+
 // -------------------------- Helpers --------------------------
 
 func isStruct[T any]() bool {
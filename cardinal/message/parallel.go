@@ -0,0 +1,449 @@
+package message
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/attestation"
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/iterators"
+	"pkg.world.dev/world-engine/cardinal/rng"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/sign"
+)
+
+// ErrNotParallelSafe is returned by the store of a context that is passed to a parallel handler, or to a system that
+// runs concurrently with other systems, when it tries to change the state in a way whose outcome depends on the order
+// the handlers or systems run in, such as creating an entity or adding a component to an entity.
+var ErrNotParallelSafe = errors.New("state change is not allowed in a parallel handler")
+
+// WithParallelExecution declares that the handlers of the message only touch the entities that are owned by the
+// persona that submitted the transaction. EachParallel runs the transactions of different personas in parallel
+// goroutines, while the transactions of each persona still run one after another in the order they were submitted.
+//
+// Handlers that run in parallel may read any component, and set the components of the entities they own, but can't
+// create or remove entities, or add or remove components. Their writes are staged per persona: a handler reads the
+// state as it was before the message's handlers ran, along with the writes of its persona's earlier transactions.
+// Their writes, results, errors, events and transactions are applied in the order of the transaction hashes once all
+// of them have run, so the outcome of a tick doesn't depend on how the goroutines were scheduled.
+//
+// Ownership is verified by the writes: if the handlers of several personas set the same component of an entity, the
+// transactions of those personas are run again afterward, one persona after another in the order they first
+// submitted a transaction, each seeing the changes that were applied before it.
+func WithParallelExecution[In, Out any]() MessageOption[In, Out] {
+	return func(mt *MessageType[In, Out]) {
+		mt.parallel = true
+	}
+}
+
+// IsParallel reports whether the message was declared with WithParallelExecution.
+func (t *MessageType[In, Out]) IsParallel() bool {
+	return t.parallel
+}
+
+// EachParallel is like Each, but passes each handler the context it must use. If the message was declared with
+// WithParallelExecution, the handlers of different personas run in parallel, each with a context that stages its
+// changes; otherwise they run one after another with wCtx.
+func (t *MessageType[In, Out]) EachParallel(wCtx engine.Context, fn func(engine.Context, TxData[In]) (Out, error)) {
+	if !t.parallel {
+		t.Each(wCtx, func(txData TxData[In]) (Out, error) {
+			return fn(wCtx, txData)
+		})
+		return
+	}
+
+	defer trackHandler(wCtx, t.FullName())()
+	txs := t.In(wCtx)
+	// Transactions are grouped by persona, keeping the order they were submitted in.
+	var personas [][]int
+	personaIndex := map[string]int{}
+	for i, txData := range txs {
+		j, ok := personaIndex[txData.Tx.PersonaTag]
+		if !ok {
+			j = len(personas)
+			personaIndex[txData.Tx.PersonaTag] = j
+			personas = append(personas, nil)
+		}
+		personas[j] = append(personas[j], i)
+	}
+
+	shared := NewParallelStore(wCtx.StoreManager())
+	// Every handler draws from its own random number generator, since the order of their draws from the system's
+	// would depend on how the goroutines were scheduled.
+	randSeed := wCtx.Rand().Seed()
+	outcomes := make([]parallelOutcome[Out], len(txs))
+	stores := make([]*personaStore, len(personas))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	// A handler that panics is re-panicked in the system's goroutine, where the world handles it as it handles the
+	// panics of systems.
+	var panicOnce sync.Once
+	var panicValue any
+	for range min(runtime.GOMAXPROCS(0), len(personas)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					panicOnce.Do(func() { panicValue = r })
+					for range work { //nolint:revive // drains the work of the other personas
+					}
+				}
+			}()
+			for j := range work {
+				stores[j] = newPersonaStore(shared)
+				runPersona(wCtx, stores[j], randSeed, txs, personas[j], fn, outcomes)
+			}
+		}()
+	}
+	for j := range personas {
+		work <- j
+	}
+	close(work)
+	wg.Wait()
+	if panicValue != nil {
+		panic(panicValue)
+	}
+
+	// The personas whose handlers set the same component of an entity as another persona's are run again.
+	conflicted := make([]bool, len(personas))
+	writers := map[stagedKey]int{}
+	for j, store := range stores {
+		for _, w := range store.writes {
+			key := stagedKey{w.cType.ID(), w.id}
+			if k, ok := writers[key]; ok && k != j {
+				conflicted[j], conflicted[k] = true, true
+			} else if !ok {
+				writers[key] = j
+			}
+		}
+	}
+
+	var order []int
+	for j, store := range stores {
+		if conflicted[j] {
+			continue
+		}
+		if err := store.flush(wCtx.StoreManager()); err != nil {
+			wCtx.Logger().Err(err).Msgf("failed to apply the writes of persona %s", txs[personas[j][0]].Tx.PersonaTag)
+		}
+		order = append(order, personas[j]...)
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return txs[order[a]].Hash < txs[order[b]].Hash
+	})
+	for _, i := range order {
+		t.apply(wCtx, txs[i], outcomes[i])
+	}
+
+	for j, indices := range personas {
+		if !conflicted[j] {
+			continue
+		}
+		store := newPersonaStore(NewParallelStore(wCtx.StoreManager()))
+		runPersona(wCtx, store, randSeed, txs, indices, fn, outcomes)
+		if err := store.flush(wCtx.StoreManager()); err != nil {
+			wCtx.Logger().Err(err).Msgf("failed to apply the writes of persona %s", txs[indices[0]].Tx.PersonaTag)
+		}
+		for _, i := range indices {
+			t.apply(wCtx, txs[i], outcomes[i])
+		}
+	}
+}
+
+// runPersona runs the handlers of a persona's transactions one after another, with contexts that stage their writes
+// in the persona's store.
+func runPersona[In, Out any](
+	wCtx engine.Context, store *personaStore, randSeed string, txs []TxData[In], indices []int,
+	fn func(engine.Context, TxData[In]) (Out, error), outcomes []parallelOutcome[Out],
+) {
+	for _, i := range indices {
+		pCtx := &parallelContext{
+			Context: wCtx,
+			store:   store,
+			rand:    rng.New(sha256.Sum256([]byte(randSeed + "/" + string(txs[i].Hash)))),
+		}
+		outcomes[i].result, outcomes[i].err = fn(pCtx, txs[i])
+		outcomes[i].changes = pCtx.changes
+	}
+}
+
+// apply applies the buffered changes of the transaction's handler, and records its outcome as its receipt.
+func (t *MessageType[In, Out]) apply(wCtx engine.Context, txData TxData[In], outcome parallelOutcome[Out]) {
+	for _, change := range outcome.changes {
+		if err := change(); err != nil {
+			wCtx.Logger().Err(err).Msgf("failed to emit event of tx %s", txData.Hash)
+		}
+	}
+	t.settle(wCtx, txData, outcome.result, outcome.err)
+}
+
+type parallelOutcome[Out any] struct {
+	result  Out
+	err     error
+	changes []func() error
+}
+
+// parallelContext is the context of a handler that runs in parallel with the handlers of other personas. It buffers
+// the changes of the handler so that they can be applied in a deterministic order.
+type parallelContext struct {
+	engine.Context
+	store   *personaStore
+	changes []func() error
+	rand    *rng.RNG
+}
+
+func (ctx *parallelContext) buffer(change func() error) {
+	ctx.changes = append(ctx.changes, change)
+}
+
+func (ctx *parallelContext) EmitEvent(event map[string]any) error {
+	ctx.buffer(func() error { return ctx.Context.EmitEvent(event) })
+	return nil
+}
+
+// EmitEventBounded buffers the event like EmitEvent. The event is checked against the budget of its topic once it is
+// emitted, in the order of the transaction hashes, and a dropped event is logged rather than returned as an error.
+func (ctx *parallelContext) EmitEventBounded(event map[string]any) error {
+	ctx.buffer(func() error { return ctx.Context.EmitEventBounded(event) })
+	return nil
+}
+
+func (ctx *parallelContext) EmitStringEvent(e string) error {
+	ctx.buffer(func() error { return ctx.Context.EmitStringEvent(e) })
+	return nil
+}
+
+func (ctx *parallelContext) EmitPriorityEvent(event map[string]any) error {
+	ctx.buffer(func() error { return ctx.Context.EmitPriorityEvent(event) })
+	return nil
+}
+
+func (ctx *parallelContext) AddMessageError(id types.TxHash, err error) {
+	ctx.buffer(func() error {
+		ctx.Context.AddMessageError(id, err)
+		return nil
+	})
+}
+
+func (ctx *parallelContext) SetMessageResult(id types.TxHash, a any) {
+	ctx.buffer(func() error {
+		ctx.Context.SetMessageResult(id, a)
+		return nil
+	})
+}
+
+// AddTransaction queues the transaction for the next tick once the handler's changes are applied.
+func (ctx *parallelContext) AddTransaction(id types.MessageID, v any, sig *sign.Transaction) (uint64, types.TxHash) {
+	ctx.buffer(func() error {
+		ctx.Context.AddTransaction(id, v, sig)
+		return nil
+	})
+	return ctx.CurrentTick(), types.TxHash(sig.HashHex())
+}
+
+// Attest signs the payload right away. An attestation only depends on the world's key, the tick and the payload, so
+// it is the same whichever order the handlers run in.
+func (ctx *parallelContext) Attest(payload []byte) (attestation.Attestation, error) {
+	return ctx.Context.Attest(payload)
+}
+
+// Unwrap returns the context of the system that runs the handlers.
+func (ctx *parallelContext) Unwrap() engine.Context {
+	return ctx.Context
+}
+
+// Rand returns the random number generator of the handler's transaction, which is seeded with the system's random
+// number generator and the transaction hash.
+func (ctx *parallelContext) Rand() *rng.RNG {
+	return ctx.rand
+}
+
+func (ctx *parallelContext) StoreManager() gamestate.Manager {
+	return ctx.store
+}
+
+func (ctx *parallelContext) StoreReader() gamestate.Reader {
+	return ctx.store
+}
+
+// personaStore stages the writes of a persona's handlers over the shared state, which it only reads.
+type personaStore struct {
+	gamestate.Manager
+	// writes are the values that the handlers set, in the order they first set them.
+	writes []stagedWrite
+	index  map[stagedKey]int
+}
+
+type stagedKey struct {
+	typeID types.ComponentID
+	id     types.EntityID
+}
+
+type stagedWrite struct {
+	cType types.ComponentMetadata
+	id    types.EntityID
+	value any
+}
+
+func newPersonaStore(shared gamestate.Manager) *personaStore {
+	return &personaStore{Manager: shared, index: map[stagedKey]int{}}
+}
+
+// flush applies the staged writes to the store.
+func (s *personaStore) flush(store gamestate.Manager) error {
+	for _, w := range s.writes {
+		if err := store.SetComponentForEntity(w.cType, w.id, w.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *personaStore) ToReadOnly() gamestate.Reader {
+	return s
+}
+
+func (s *personaStore) GetComponentForEntity(cType types.ComponentMetadata, id types.EntityID) (any, error) {
+	if i, ok := s.index[stagedKey{cType.ID(), id}]; ok {
+		return s.writes[i].value, nil
+	}
+	return s.Manager.GetComponentForEntity(cType, id)
+}
+
+func (s *personaStore) GetComponentForEntityInRawJSON(cType types.ComponentMetadata, id types.EntityID) (
+	json.RawMessage, error,
+) {
+	if i, ok := s.index[stagedKey{cType.ID(), id}]; ok {
+		return cType.Encode(s.writes[i].value)
+	}
+	return s.Manager.GetComponentForEntityInRawJSON(cType, id)
+}
+
+// SetComponentForEntity stages the value. The entity must have the component.
+func (s *personaStore) SetComponentForEntity(cType types.ComponentMetadata, id types.EntityID, value any) error {
+	key := stagedKey{cType.ID(), id}
+	if i, ok := s.index[key]; ok {
+		s.writes[i].value = value
+		return nil
+	}
+	if _, err := s.Manager.GetComponentForEntity(cType, id); err != nil {
+		return err
+	}
+	s.index[key] = len(s.writes)
+	s.writes = append(s.writes, stagedWrite{cType: cType, id: id, value: value})
+	return nil
+}
+
+// parallelStore serializes the access of parallel handlers to the world's state, and refuses the changes whose
+// outcome depends on the order the handlers run in.
+type parallelStore struct {
+	gamestate.Manager
+	mu sync.Mutex
+}
+
+// NewParallelStore returns a store that serializes the access of concurrent code to the given store, and refuses the
+// changes whose outcome depends on the order the code runs in with ErrNotParallelSafe.
+func NewParallelStore(store gamestate.Manager) gamestate.Manager {
+	return &parallelStore{Manager: store}
+}
+
+func (s *parallelStore) GetComponentForEntity(cType types.ComponentMetadata, id types.EntityID) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Manager.GetComponentForEntity(cType, id)
+}
+
+func (s *parallelStore) GetComponentForEntityInRawJSON(cType types.ComponentMetadata, id types.EntityID) (
+	json.RawMessage, error,
+) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Manager.GetComponentForEntityInRawJSON(cType, id)
+}
+
+func (s *parallelStore) GetComponentTypesForEntity(id types.EntityID) ([]types.ComponentMetadata, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Manager.GetComponentTypesForEntity(id)
+}
+
+func (s *parallelStore) GetComponentTypesForArchID(archID types.ArchetypeID) ([]types.ComponentMetadata, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Manager.GetComponentTypesForArchID(archID)
+}
+
+func (s *parallelStore) GetArchIDForComponents(components []types.ComponentMetadata) (types.ArchetypeID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Manager.GetArchIDForComponents(components)
+}
+
+func (s *parallelStore) GetEntitiesForArchID(archID types.ArchetypeID) ([]types.EntityID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Manager.GetEntitiesForArchID(archID)
+}
+
+func (s *parallelStore) SearchFrom(filter filter.ComponentFilter, start int) *iterators.ArchetypeIterator {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Manager.SearchFrom(filter, start)
+}
+
+func (s *parallelStore) FindArchetypes(filter filter.ComponentFilter) []types.ArchetypeID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Manager.FindArchetypes(filter)
+}
+
+func (s *parallelStore) LookupFieldIndex(component, field, key string) ([]types.EntityID, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Manager.LookupFieldIndex(component, field, key)
+}
+
+func (s *parallelStore) ArchetypeCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Manager.ArchetypeCount()
+}
+
+func (s *parallelStore) ToReadOnly() gamestate.Reader {
+	return s
+}
+
+func (s *parallelStore) SetComponentForEntity(cType types.ComponentMetadata, id types.EntityID, value any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Manager.SetComponentForEntity(cType, id, value)
+}
+
+func (s *parallelStore) RemoveEntity(types.EntityID) error {
+	return eris.Wrap(ErrNotParallelSafe, "entities can't be removed")
+}
+
+func (s *parallelStore) CreateEntity(...types.ComponentMetadata) (types.EntityID, error) {
+	return 0, eris.Wrap(ErrNotParallelSafe, "entities can't be created")
+}
+
+func (s *parallelStore) CreateManyEntities(int, ...types.ComponentMetadata) ([]types.EntityID, error) {
+	return nil, eris.Wrap(ErrNotParallelSafe, "entities can't be created")
+}
+
+func (s *parallelStore) AddComponentToEntity(types.ComponentMetadata, types.EntityID) error {
+	return eris.Wrap(ErrNotParallelSafe, "components can't be added to entities")
+}
+
+func (s *parallelStore) RemoveComponentFromEntity(types.ComponentMetadata, types.EntityID) error {
+	return eris.Wrap(ErrNotParallelSafe, "components can't be removed from entities")
+}
@@ -0,0 +1,128 @@
+package cardinal
+
+import (
+	"fmt"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// Module is a packaged game feature, such as a leaderboard or an inventory, that registers everything it needs with
+// a single call to World.UseModule. Modules are versioned independently of the game that uses them.
+//
+// Embed ModuleBase to only implement the registration methods a module needs.
+type Module interface {
+	// Name identifies the module. A World can only use one module with a given name.
+	Name() string
+	// Version is reported in the /world endpoint so clients can tell which version of a module a game runs.
+	Version() string
+
+	RegisterComponents(w *World) error
+	// RegisterTxs registers the module's messages.
+	RegisterTxs(w *World) error
+	// RegisterReads registers the module's queries. Modules should use the RegisterReads function with the module's
+	// name as the namespace, so their queries don't collide with the game's or with other modules'.
+	RegisterReads(w *World) error
+	RegisterSystems(w *World) error
+	// Init is called after everything else is registered. It's the place for remaining setup, like registering
+	// init systems that seed the module's state.
+	Init(w *World) error
+}
+
+// ModuleBase provides no-op implementations of the registration methods of Module.
+type ModuleBase struct{}
+
+func (ModuleBase) RegisterComponents(*World) error { return nil }
+func (ModuleBase) RegisterTxs(*World) error        { return nil }
+func (ModuleBase) RegisterReads(*World) error      { return nil }
+func (ModuleBase) RegisterSystems(*World) error    { return nil }
+func (ModuleBase) Init(*World) error               { return nil }
+
+// moduleStoragePrefix is prepended to the keys of a module's storage. See World.ModuleStorage.
+const moduleStoragePrefix = "MODULE:"
+
+// UseModule registers the module's components, messages, queries, and systems, in that order, and then calls the
+// module's Init method.
+//
+// Messages registered by the module are grouped under the module's name by default, and component names must be
+// unique across the game and all of its modules. If the module is an AccessControlledModule, its systems and tx
+// middleware may only use the components that it declares, and its own.
+func (w *World) UseModule(m Module) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"engine state is %s, expected %s to use module",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	if !regexAlphanumeric.MatchString(m.Name()) {
+		return eris.Errorf("module name %q must only contain alphanumerics and dashes", m.Name())
+	}
+	for _, used := range w.modules {
+		if used.Name == m.Name() {
+			return eris.Errorf("module %q (version %s) is already in use", used.Name, used.Version)
+		}
+	}
+
+	steps := []struct {
+		name     string
+		register func(*World) error
+	}{
+		{"components", m.RegisterComponents},
+		{"txs", m.RegisterTxs},
+		{"reads", m.RegisterReads},
+		{"systems", m.RegisterSystems},
+		{"init", m.Init},
+	}
+	w.registeringModule = m.Name()
+	defer func() { w.registeringModule = "" }()
+	for _, step := range steps {
+		if err := step.register(w); err != nil {
+			return eris.Wrapf(err, "failed to register %s of module %q", step.name, m.Name())
+		}
+	}
+
+	if declarer, ok := m.(AccessControlledModule); ok {
+		w.enableModuleAccess().declared[m.Name()] = declarer.ComponentAccess()
+	}
+	w.modules = append(w.modules, servertypes.ModuleInfo{Name: m.Name(), Version: m.Version()})
+	return nil
+}
+
+// GetModules returns the modules the world uses, in the order they were added.
+func (w *World) GetModules() []servertypes.ModuleInfo {
+	return w.modules
+}
+
+// ModuleStorage returns a key-value storage that only the named module can see. Its keys are stored in Redis under a
+// prefix unique to the module, so modules cannot read or overwrite each other's keys, nor the game state.
+// The module must be in use, or currently being registered by UseModule.
+func (w *World) ModuleStorage(name string) (gamestate.PrimitiveStorage[string], error) {
+	if !w.isModuleKnown(name) {
+		return nil, eris.Errorf("module %q is not in use", name)
+	}
+	storage := gamestate.NewRedisPrimitiveStorage(w.redisStorage.Client)
+	return gamestate.NewPrefixedStorage(&storage, moduleStoragePrefix+name+":"), nil
+}
+
+func (w *World) isModuleKnown(name string) bool {
+	if name != "" && name == w.registeringModule {
+		return true
+	}
+	for _, used := range w.modules {
+		if used.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func describeComponentOwner(module string) string {
+	if module == "" {
+		return "the game"
+	}
+	return fmt.Sprintf("module %q", module)
+}
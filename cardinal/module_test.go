@@ -0,0 +1,166 @@
+package cardinal_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type Score struct {
+	Points int `json:"points"`
+}
+
+func (Score) Name() string { return "score" }
+
+type AddPoints struct {
+	Points int `json:"points"`
+}
+
+type AddPointsResult struct{}
+
+type TopScoreRequest struct{}
+
+type TopScoreReply struct {
+	Points int `json:"points"`
+}
+
+// leaderboardModule is a minimal module that keeps a single score.
+type leaderboardModule struct {
+	cardinal.ModuleBase
+	initialized bool
+}
+
+func (*leaderboardModule) Name() string    { return "leaderboard" }
+func (*leaderboardModule) Version() string { return "v1.2.0" }
+
+func (*leaderboardModule) RegisterComponents(w *cardinal.World) error {
+	return cardinal.RegisterComponent[Score](w)
+}
+
+func (*leaderboardModule) RegisterTxs(w *cardinal.World) error {
+	return cardinal.RegisterMessage[AddPoints, AddPointsResult](w, "add-points")
+}
+
+func (*leaderboardModule) RegisterReads(w *cardinal.World) error {
+	return cardinal.RegisterReads(w, "leaderboard",
+		cardinal.NewRead[TopScoreRequest, TopScoreReply]("top", func(wCtx engine.Context, _ *TopScoreRequest) (
+			*TopScoreReply, error,
+		) {
+			reply := &TopScoreReply{}
+			search := cardinal.NewSearch().Entity(filter.Exact(filter.Component[Score]()))
+			err := search.Each(wCtx, func(id types.EntityID) bool {
+				score, err := cardinal.GetComponent[Score](wCtx, id)
+				if err == nil && score.Points > reply.Points {
+					reply.Points = score.Points
+				}
+				return true
+			})
+			return reply, err
+		}))
+}
+
+func (m *leaderboardModule) Init(w *cardinal.World) error {
+	m.initialized = true
+	return cardinal.RegisterInitSystems(w, func(wCtx engine.Context) error {
+		_, err := cardinal.Create(wCtx, Score{Points: 7})
+		return err
+	})
+}
+
+func TestUseModule(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	module := &leaderboardModule{}
+	assert.NilError(t, tf.World.UseModule(module))
+	assert.Check(t, module.initialized)
+
+	err := tf.World.UseModule(&leaderboardModule{})
+	assert.ErrorContains(t, err, `module "leaderboard" (version v1.2.0) is already in use`)
+
+	tf.DoTick()
+
+	res := tf.Post("query/leaderboard/top", TopScoreRequest{})
+	assert.Equal(t, res.StatusCode, http.StatusOK)
+	var reply TopScoreReply
+	assert.NilError(t, json.NewDecoder(res.Body).Decode(&reply))
+	assert.Equal(t, reply.Points, 7)
+
+	res = tf.Get("world")
+	var world handler.GetWorldResponse
+	assert.NilError(t, json.NewDecoder(res.Body).Decode(&world))
+	assert.Equal(t, len(world.Modules), 1)
+	assert.Equal(t, world.Modules[0].Name, "leaderboard")
+	assert.Equal(t, world.Modules[0].Version, "v1.2.0")
+
+	assert.ErrorContains(t, tf.World.UseModule(&leaderboardModule{}), "expected Init to use module")
+}
+
+// inventoryModule also registers the Score component, which clashes with leaderboardModule.
+type inventoryModule struct {
+	cardinal.ModuleBase
+}
+
+func (*inventoryModule) Name() string    { return "inventory" }
+func (*inventoryModule) Version() string { return "v0.1.0" }
+
+func (*inventoryModule) RegisterComponents(w *cardinal.World) error {
+	return cardinal.RegisterComponent[Score](w)
+}
+
+type walletModule struct {
+	cardinal.ModuleBase
+}
+
+func (*walletModule) Name() string    { return "wallet" }
+func (*walletModule) Version() string { return "v1.0.0" }
+
+func TestModuleIsolation(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, world.UseModule(&leaderboardModule{}))
+
+	_, ok := world.GetMessageByFullName("leaderboard.add-points")
+	assert.Check(t, ok, "module messages should be grouped under the module name")
+
+	err := world.UseModule(&inventoryModule{})
+	assert.ErrorContains(t, err, `component "score" is already registered by module "leaderboard"`)
+
+	_, err = world.ModuleStorage("wallet")
+	assert.ErrorContains(t, err, `module "wallet" is not in use`)
+	assert.NilError(t, world.UseModule(&walletModule{}))
+
+	ctx := context.Background()
+	leaderboardStorage, err := world.ModuleStorage("leaderboard")
+	assert.NilError(t, err)
+	walletStorage, err := world.ModuleStorage("wallet")
+	assert.NilError(t, err)
+
+	assert.NilError(t, leaderboardStorage.Set(ctx, "balance", 10))
+	assert.NilError(t, walletStorage.Set(ctx, "balance", 20))
+
+	got, err := leaderboardStorage.GetInt(ctx, "balance")
+	assert.NilError(t, err)
+	assert.Equal(t, got, 10)
+	got, err = walletStorage.GetInt(ctx, "balance")
+	assert.NilError(t, err)
+	assert.Equal(t, got, 20)
+
+	keys, err := walletStorage.Keys(ctx)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, keys, []string{"balance"})
+
+	assert.NilError(t, walletStorage.Clear(ctx))
+	_, err = walletStorage.GetInt(ctx, "balance")
+	assert.IsError(t, err)
+	got, err = leaderboardStorage.GetInt(ctx, "balance")
+	assert.NilError(t, err)
+	assert.Equal(t, got, 10)
+}
@@ -0,0 +1,26 @@
+package movement
+
+// Position is an entity's location on the tile map, in tiles. The entity occupies the tile that contains its
+// position, i.e. tile (floor(X), floor(Y)).
+type Position struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+func (Position) Name() string { return "movement-position" }
+
+// Velocity is the distance an entity moves each tick, in tiles.
+type Velocity struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+func (Velocity) Name() string { return "movement-velocity" }
+
+// Mover marks an entity that can be moved with move transactions.
+type Mover struct {
+	// Owner is the persona tag that is allowed to move the entity.
+	Owner string `json:"owner"`
+}
+
+func (Mover) Name() string { return "movement-mover" }
@@ -0,0 +1,193 @@
+// Package movement is a server-authoritative 2D movement module. Clients only send the velocity they would like an
+// entity to have; the server caps its speed, moves the entity every tick, and stops it at walls of a static tile map.
+//
+// It serves both as a usable base for games with 2D movement and as a reference for writing Cardinal modules:
+//
+//	tiles, err := movement.ParseTileMap(
+//		"#####",
+//		"#...#",
+//		"#####",
+//	)
+//	...
+//	err = world.UseModule(movement.NewModule(tiles, movement.WithMaxSpeed(0.5)))
+//
+// Spawn an entity with Spawn, then move it by sending a movement.MoveMsg to /tx/movement/move.
+package movement
+
+import (
+	"math"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+const (
+	ModuleName    = "movement"
+	ModuleVersion = "v1.0.0"
+
+	MoveMessageName = "move"
+
+	// DefaultMaxSpeed is the default speed cap, in tiles per tick.
+	DefaultMaxSpeed = 1.0
+)
+
+var _ cardinal.Module = &Module{}
+
+// MoveMsg sets the velocity of an entity, in tiles per tick. Velocities faster than the module's speed cap are
+// scaled down to it, keeping their direction. Send a zero velocity to stop.
+type MoveMsg struct {
+	Entity types.EntityID `json:"entity"`
+	X      float64        `json:"x"`
+	Y      float64        `json:"y"`
+}
+
+// MoveResult is the velocity the entity was given, after the speed cap was applied.
+type MoveResult struct {
+	Velocity Velocity `json:"velocity"`
+}
+
+type Option func(*Module)
+
+// WithMaxSpeed sets the maximum speed of entities, in tiles per tick.
+func WithMaxSpeed(speed float64) Option {
+	return func(m *Module) {
+		m.maxSpeed = speed
+	}
+}
+
+type Module struct {
+	cardinal.ModuleBase
+	tiles    TileMap
+	maxSpeed float64
+}
+
+func NewModule(tiles TileMap, opts ...Option) *Module {
+	m := &Module{
+		tiles:    tiles,
+		maxSpeed: DefaultMaxSpeed,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func (*Module) Name() string    { return ModuleName }
+func (*Module) Version() string { return ModuleVersion }
+
+func (*Module) RegisterComponents(w *cardinal.World) error {
+	if err := cardinal.RegisterComponent[Position](w); err != nil {
+		return err
+	}
+	if err := cardinal.RegisterComponent[Velocity](w); err != nil {
+		return err
+	}
+	return cardinal.RegisterComponent[Mover](w)
+}
+
+func (*Module) RegisterTxs(w *cardinal.World) error {
+	return cardinal.RegisterMessage[MoveMsg, MoveResult](w, MoveMessageName)
+}
+
+func (m *Module) RegisterSystems(w *cardinal.World) error {
+	return cardinal.RegisterSystems(w, m.movementSystem)
+}
+
+// Spawn creates an entity at the given position that can be moved by the owner persona tag.
+func Spawn(wCtx engine.Context, owner string, pos Position, components ...types.Component) (types.EntityID, error) {
+	components = append(components, pos, Velocity{}, Mover{Owner: owner})
+	return cardinal.Create(wCtx, components...)
+}
+
+// movementSystem applies this tick's move messages, then moves every entity with a position and velocity.
+func (m *Module) movementSystem(wCtx engine.Context) error {
+	err := cardinal.EachMessage[MoveMsg, MoveResult](wCtx,
+		func(tx message.TxData[MoveMsg]) (MoveResult, error) {
+			return m.handleMove(wCtx, tx)
+		})
+	if err != nil {
+		return err
+	}
+
+	ids, err := cardinal.NewSearch().
+		Entity(filter.Contains(filter.Component[Position](), filter.Component[Velocity]())).
+		Collect(wCtx)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := m.step(wCtx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Module) handleMove(wCtx engine.Context, tx message.TxData[MoveMsg]) (MoveResult, error) {
+	mover, err := cardinal.GetComponent[Mover](wCtx, tx.Msg.Entity)
+	if err != nil {
+		return MoveResult{}, eris.Wrapf(err, "entity %d cannot be moved", tx.Msg.Entity)
+	}
+	if tx.Tx == nil || tx.Tx.PersonaTag != mover.Owner {
+		return MoveResult{}, eris.Errorf("entity %d is not owned by the sender", tx.Msg.Entity)
+	}
+	if math.IsNaN(tx.Msg.X) || math.IsNaN(tx.Msg.Y) || math.IsInf(tx.Msg.X, 0) || math.IsInf(tx.Msg.Y, 0) {
+		return MoveResult{}, eris.New("velocity must be a finite number")
+	}
+
+	vel := Velocity{X: tx.Msg.X, Y: tx.Msg.Y}
+	if speed := math.Hypot(vel.X, vel.Y); speed > m.maxSpeed {
+		vel.X = vel.X * m.maxSpeed / speed
+		vel.Y = vel.Y * m.maxSpeed / speed
+	}
+	if err := cardinal.SetComponent[Velocity](wCtx, tx.Msg.Entity, &vel); err != nil {
+		return MoveResult{}, err
+	}
+	return MoveResult{Velocity: vel}, nil
+}
+
+// step moves the entity by its velocity. The entity is moved in steps of at most one tile, so that it cannot pass
+// through walls no matter how fast it is. Each axis is checked separately, so an entity that runs into a wall at an
+// angle slides along it; the blocked axis of its velocity is zeroed.
+func (m *Module) step(wCtx engine.Context, id types.EntityID) error {
+	vel, err := cardinal.GetComponent[Velocity](wCtx, id)
+	if err != nil {
+		return err
+	}
+	if vel.X == 0 && vel.Y == 0 {
+		return nil
+	}
+	pos, err := cardinal.GetComponent[Position](wCtx, id)
+	if err != nil {
+		return err
+	}
+
+	steps := math.Ceil(math.Max(math.Abs(vel.X), math.Abs(vel.Y)))
+	stepX, stepY := vel.X/steps, vel.Y/steps
+	for i := 0; i < int(steps); i++ {
+		if stepX != 0 {
+			if m.tiles.IsBlocked(pos.X+stepX, pos.Y) {
+				stepX, vel.X = 0, 0
+			} else {
+				pos.X += stepX
+			}
+		}
+		if stepY != 0 {
+			if m.tiles.IsBlocked(pos.X, pos.Y+stepY) {
+				stepY, vel.Y = 0, 0
+			} else {
+				pos.Y += stepY
+			}
+		}
+	}
+
+	if err := cardinal.SetComponent[Position](wCtx, id, pos); err != nil {
+		return err
+	}
+	return cardinal.SetComponent[Velocity](wCtx, id, vel)
+}
@@ -0,0 +1,110 @@
+package movement_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/movement"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func TestParseTileMap(t *testing.T) {
+	tiles, err := movement.ParseTileMap(
+		"###",
+		"#.#",
+	)
+	assert.NilError(t, err)
+	assert.Equal(t, tiles.Width, 3)
+	assert.Equal(t, tiles.Height, 2)
+	assert.Check(t, !tiles.IsBlocked(1.5, 1.99))
+	assert.Check(t, tiles.IsBlocked(0.5, 1.5))
+	assert.Check(t, tiles.IsBlocked(1.5, 2.5), "tiles outside of the map are walls")
+	assert.Check(t, tiles.IsBlocked(-0.5, 1.5), "tiles outside of the map are walls")
+
+	_, err = movement.ParseTileMap("##", "#")
+	assert.ErrorContains(t, err, "row 1 has 1 tiles, expected 2")
+	_, err = movement.ParseTileMap("#x")
+	assert.ErrorContains(t, err, "unknown tile")
+}
+
+func setupMovement(t *testing.T, start movement.Position) (*testutils.TestFixture, types.EntityID) {
+	tf := testutils.NewTestFixture(t, nil)
+	tiles, err := movement.ParseTileMap(
+		"######",
+		"#....#",
+		"#.#..#",
+		"######",
+	)
+	assert.NilError(t, err)
+	assert.NilError(t, tf.World.UseModule(movement.NewModule(tiles, movement.WithMaxSpeed(2))))
+
+	var id types.EntityID
+	assert.NilError(t, cardinal.RegisterInitSystems(tf.World, func(wCtx engine.Context) error {
+		id, err = movement.Spawn(wCtx, "alice", start)
+		return err
+	}))
+	tf.DoTick()
+	return tf, id
+}
+
+func move(tf *testutils.TestFixture, personaTag string, msg movement.MoveMsg) types.TxHash {
+	moveMsg, ok := tf.World.GetMessageByFullName("movement." + movement.MoveMessageName)
+	assert.Check(tf, ok)
+	return tf.AddTransaction(moveMsg.ID(), msg, testutils.UniqueSignatureWithName(personaTag))
+}
+
+func position(t *testing.T, tf *testutils.TestFixture, id types.EntityID) movement.Position {
+	pos, err := cardinal.GetComponent[movement.Position](cardinal.NewReadOnlyWorldContext(tf.World), id)
+	assert.NilError(t, err)
+	return *pos
+}
+
+func TestMovementStopsAtWalls(t *testing.T) {
+	tf, id := setupMovement(t, movement.Position{X: 1.5, Y: 1.5})
+
+	move(tf, "alice", movement.MoveMsg{Entity: id, X: 1})
+	tf.DoTick()
+	assert.Equal(t, position(t, tf, id), movement.Position{X: 2.5, Y: 1.5})
+	tf.DoTick()
+	tf.DoTick()
+	tf.DoTick()
+	// The wall at x=5 stops the entity in the last floor tile.
+	assert.Equal(t, position(t, tf, id), movement.Position{X: 4.5, Y: 1.5})
+
+	vel, err := cardinal.GetComponent[movement.Velocity](cardinal.NewReadOnlyWorldContext(tf.World), id)
+	assert.NilError(t, err)
+	assert.Equal(t, *vel, movement.Velocity{})
+}
+
+func TestMovementSlidesAlongWalls(t *testing.T) {
+	tf, id := setupMovement(t, movement.Position{X: 3.5, Y: 1.5})
+
+	// Moving diagonally into the top wall keeps moving along it.
+	move(tf, "alice", movement.MoveMsg{Entity: id, X: 1, Y: -1})
+	tf.DoTick()
+	assert.Equal(t, position(t, tf, id).Y, 1.5)
+	assert.Check(t, position(t, tf, id).X > 3.5)
+}
+
+func TestMoveIsCappedAndOwned(t *testing.T) {
+	tf, id := setupMovement(t, movement.Position{X: 1.5, Y: 1.5})
+
+	hash := move(tf, "alice", movement.MoveMsg{Entity: id, X: 30, Y: 40})
+	tf.DoTick()
+	receipt, errs, ok := cardinal.NewReadOnlyWorldContext(tf.World).GetTransactionReceipt(hash)
+	assert.Check(t, ok)
+	assert.Equal(t, len(errs), 0)
+	result, ok := receipt.(movement.MoveResult)
+	assert.Check(t, ok)
+	assert.Equal(t, result.Velocity, movement.Velocity{X: 1.2, Y: 1.6})
+
+	hash = move(tf, "mallory", movement.MoveMsg{Entity: id, X: -1})
+	tf.DoTick()
+	_, errs, ok = cardinal.NewReadOnlyWorldContext(tf.World).GetTransactionReceipt(hash)
+	assert.Check(t, ok)
+	assert.Equal(t, len(errs), 1)
+	assert.ErrorContains(t, errs[0], "is not owned by the sender")
+}
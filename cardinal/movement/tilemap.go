@@ -0,0 +1,64 @@
+package movement
+
+import (
+	"math"
+
+	"github.com/rotisserie/eris"
+)
+
+const (
+	floorTile = '.'
+	wallTile  = '#'
+)
+
+// TileMap is a static grid of tiles that entities collide against. Tile (0, 0) is the top left tile; x grows to the
+// right and y grows downwards.
+type TileMap struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Walls  []bool `json:"walls"`
+}
+
+// ParseTileMap creates a tile map from rows of text, where '.' is a floor tile and '#' is a wall. All rows must have
+// the same length.
+//
+//	movement.ParseTileMap(
+//		"#####",
+//		"#...#",
+//		"#####",
+//	)
+func ParseTileMap(rows ...string) (TileMap, error) {
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return TileMap{}, eris.New("tile map must not be empty")
+	}
+	tiles := TileMap{
+		Width:  len(rows[0]),
+		Height: len(rows),
+		Walls:  make([]bool, 0, len(rows)*len(rows[0])),
+	}
+	for y, row := range rows {
+		if len(row) != tiles.Width {
+			return TileMap{}, eris.Errorf("tile map row %d has %d tiles, expected %d", y, len(row), tiles.Width)
+		}
+		for x, tile := range row {
+			switch tile {
+			case floorTile:
+				tiles.Walls = append(tiles.Walls, false)
+			case wallTile:
+				tiles.Walls = append(tiles.Walls, true)
+			default:
+				return TileMap{}, eris.Errorf("tile map has unknown tile %q at (%d, %d)", tile, x, y)
+			}
+		}
+	}
+	return tiles, nil
+}
+
+// IsBlocked reports whether the tile that contains the point is a wall. Everything outside of the map is a wall.
+func (m TileMap) IsBlocked(x, y float64) bool {
+	tileX, tileY := math.Floor(x), math.Floor(y)
+	if tileX < 0 || tileY < 0 || tileX >= float64(m.Width) || tileY >= float64(m.Height) {
+		return true
+	}
+	return m.Walls[int(tileY)*m.Width+int(tileX)]
+}
@@ -1,17 +1,26 @@
 package cardinal
 
 import (
+	"crypto/ecdsa"
+	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
+	"pkg.world.dev/world-engine/cardinal/clock"
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/events"
 	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/persona"
 	"pkg.world.dev/world-engine/cardinal/receipt"
 	"pkg.world.dev/world-engine/cardinal/router"
 	"pkg.world.dev/world-engine/cardinal/server"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+	"pkg.world.dev/world-engine/cardinal/worldclock"
 )
 
 // WorldOption represents an option that can be used to augment how the cardinal.World will be run.
@@ -29,6 +38,37 @@ func WithPort(port string) WorldOption {
 	}
 }
 
+// WithEventCompression compresses the tick results that are sent on the event websocket with permessage-deflate at
+// the compress/flate level, for the clients that ask for compression when they connect, such as the relay. Frames
+// smaller than threshold bytes are sent uncompressed. The bytes that compression saves are reported as a statsd
+// metric. By default, frames are not compressed.
+func WithEventCompression(level, threshold int) WorldOption {
+	return WorldOption{
+		serverOption: server.WithEventCompression(level, threshold),
+	}
+}
+
+// WithNATS makes the relay able to submit transactions and receive events through the NATS server at the URL, instead
+// of HTTP and the event websocket, which is more reliable in container networks that drop connections. Transactions
+// wait in a JetStream stream until Cardinal adds them, and events are kept in a stream for the retention, so that
+// relays that reconnect receive the events they missed. A retention of zero keeps events for
+// server.DefaultNATSEventRetention. The HTTP endpoints and the websocket keep working.
+func WithNATS(url string, eventRetention time.Duration) WorldOption {
+	return WorldOption{
+		serverOption: server.WithNATS(url, eventRetention),
+	}
+}
+
+// WithJWTAuth lets the users that a studio's identity provider authenticates, such as the users of web dashboards,
+// call the query, CQL and debug endpoints with the JSON Web Tokens it issues, instead of engine specific keys. The
+// endpoints require a valid token unless cfg.AllowAnonymous is set, and queries get the persona tag that the token
+// maps to with CallerPersonaTag.
+func WithJWTAuth(cfg server.JWTConfig) WorldOption {
+	return WorldOption{
+		serverOption: server.WithJWTAuth(cfg),
+	}
+}
+
 // WithReceiptHistorySize specifies how many ticks worth of transaction receipts should be kept in memory. The default
 // is 10. A smaller number uses less memory, but limits the amount of historical receipts available.
 func WithReceiptHistorySize(size int) WorldOption {
@@ -39,6 +79,284 @@ func WithReceiptHistorySize(size int) WorldOption {
 	}
 }
 
+// WithEventHistorySize specifies how many of the most recently emitted events should be kept in memory and served by
+// the /query/events/list endpoint. The default is 1000. A size of 0 disables the event history.
+func WithEventHistorySize(size int) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.eventHistory = events.NewHistory(size)
+		},
+	}
+}
+
+// WithTxQueueCapacity sets how many accepted transactions may wait for the next tick. When the queue is full,
+// AddTransaction blocks until the next tick takes the waiting transactions. The capacity must be positive. The default
+// is DefaultTxQueueCapacity.
+func WithTxQueueCapacity(capacity int) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			if capacity <= 0 {
+				log.Fatal().Msgf("transaction queue capacity must be positive, got %d", capacity)
+			}
+			world.txQueue = make(chan txpool.TxData, capacity)
+			world.txQueueSlots = make(chan struct{}, capacity)
+		},
+	}
+}
+
+// WithHooks registers callbacks that are invoked at well-defined points in the tick loop. WithHooks may be given more
+// than once; hooks are called in the order they were registered.
+func WithHooks(hooks Hooks) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.hooks = append(world.hooks, hooks)
+		},
+	}
+}
+
+// WithWorldClock sets the clock that maps ticks to in-game time. The world emits events when in-game days and seasons
+// start, so changing the clock of a running game changes the in-game time of every tick, past and future.
+func WithWorldClock(clock *worldclock.Clock) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.clock = clock
+		},
+	}
+}
+
+// WithEventBudgets sets the number of events of each topic, keyed by the events' "type" field, that systems may emit
+// with EmitEventBounded in a tick. The budget of "" applies to the topics without a budget, and a budget of 0 is
+// unlimited. Topics without a budget get DefaultEventBudget. Events beyond the budget are dropped, and the drops are
+// logged and reported as statsd metrics.
+func WithEventBudgets(budgets map[string]int) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.eventBudget = newEventBudget(budgets)
+		},
+	}
+}
+
+// WithRandomSeed sets the seed of the random number generators that systems get from wCtx.Rand. The seed must not
+// change for the life of the game, since replaying a tick with another seed draws other numbers. It defaults to the
+// world's namespace.
+func WithRandomSeed(seed string) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.randomSeed = seed
+		},
+	}
+}
+
+// WithLimits caps the number of entities, the number of components per entity, and the size of component values.
+// Creating entities or setting components beyond the limits fails with a *LimitError. By default, there are no
+// limits.
+func WithLimits(limits Limits) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.entityStore.SetLimits(limits)
+		},
+	}
+}
+
+// WithComponentCompression stores the component values whose JSON encoding is at least threshold bytes long
+// compressed with zstd, which saves storage memory for games with large components, such as maps or inventories.
+// Compressed values are decompressed transparently when they are read, and compression ratios are reported as statsd
+// metrics. By default, component values are not compressed.
+func WithComponentCompression(threshold int) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			if err := world.entityStore.SetCompressionThreshold(threshold); err != nil {
+				log.Fatal().Err(err).Msg("failed to turn on component compression")
+			}
+		},
+	}
+}
+
+// WithRollback keeps the inputs and state changes of the last ticks in memory, so that late transactions can be
+// added to them with ApplyLateTransaction. Every tick reads the state that it overwrites, so rollback makes ticks
+// slower. By default, there is no rollback.
+func WithRollback(ticks int) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.rollback = newRollbackHistory(ticks)
+			world.entityStore.SetRewindLimit(ticks)
+		},
+	}
+}
+
+// WithTickLog saves the events of every tick with the tick, and keeps the events of the given number of most recent
+// ticks, so that consumers can rebuild their projections by replaying historical events before they switch to live
+// events; see World.ReplayEvents. Event websocket clients replay the log by connecting with the fromTick query
+// parameter. By default, no tick log is kept.
+func WithTickLog(ticks int) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.tickLog = ticks > 0
+			world.entityStore.SetTickLogRetention(ticks)
+		},
+	}
+}
+
+// WithModuleAccess imposes component access policies on modules, keyed by module name, such as third-party modules
+// that don't declare a policy themselves. An imposed policy replaces the one that the module declares; see
+// AccessControlledModule. The systems and tx middleware of a restricted module fail with ErrComponentAccessDenied when
+// they use a component that the policy doesn't allow, and StartGame fails if a policy names a module that is not in
+// use or a component that is not registered.
+func WithModuleAccess(policies map[string]ComponentAccess) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			access := world.enableModuleAccess()
+			for module, policy := range policies {
+				access.imposed[module] = policy
+			}
+		},
+	}
+}
+
+// WithConflictDetection enables or disables the detection of write conflicts: components of an entity that the
+// handlers of more than one message type change in the same tick, which makes the outcome of the tick depend on the
+// order of the systems. Conflicts are logged as warnings, and the conflicts of the most recent tick are returned by
+// World.WriteConflicts. Detection is enabled by default in development mode, when rollup is disabled.
+func WithConflictDetection(enabled bool) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.conflicts = nil
+			if enabled {
+				world.conflicts = newConflictTracker()
+			}
+		},
+	}
+}
+
+// WithStructuralEvents emits an event whenever an entity is created or removed, or its components are added, removed
+// or set, so that external indexers can keep a mirror of the world without polling it. Only the changes to the named
+// components are emitted; with no names, the changes to every component are. Events are emitted at the end of the
+// tick with the final values of the components, so an entity that is created and removed in the same tick has no
+// events. By default, no structural events are emitted.
+func WithStructuralEvents(components ...string) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.structuralEvents = newStructuralEvents(components)
+		},
+	}
+}
+
+// WithStrictInvariants checks the invariants before each tick is committed instead of after, so that with
+// WithInvariantHalt the state that violates them is never committed. The invariants read the state that is about to
+// be committed.
+func WithStrictInvariants() WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.invariantConfig.strict = true
+		},
+	}
+}
+
+// WithInvariantHalt stops the world when an invariant is violated: the tick fails with ErrInvariantViolated, like it
+// does when a system fails. By default, violations are only logged and emitted as events.
+func WithInvariantHalt() WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.invariantConfig.halt = true
+		},
+	}
+}
+
+// WithChaos turns on chaos mode, which injects failures into storage, event connections and the base shard, so that
+// games can check how their clients and operations recover from them in tests and staging. A storage failure fails
+// the tick like any storage error does, which stops the world, so that its recovery can be tested. Never use it in
+// production.
+func WithChaos(config ChaosConfig) WorldOption {
+	c := newChaos(config)
+	return WorldOption{
+		serverOption: server.WithEventConnectionDropper(c.dropEventConnection),
+		cardinalOption: func(world *World) {
+			world.chaos = c
+		},
+	}
+}
+
+// WithMessageDecoding sets how the payloads of every registered message's transactions are decoded: whether unknown
+// and missing required fields are rejected, and the naming policy of fields. Options that are passed to
+// RegisterMessage, such as message.WithStrictDecoding, are applied on top. By default, payloads are decoded like
+// encoding/json decodes them.
+func WithMessageDecoding(opts codec.Options) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.messageDecoding = opts
+		},
+	}
+}
+
+// WithAttestationKey sets the key that the world signs attested events with; see EmitAttestedEvent. Services that
+// verify the attestations need the address of the key, which World.AttestationSigner returns.
+func WithAttestationKey(key *ecdsa.PrivateKey) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.attestationKey = key
+		},
+	}
+}
+
+// WithAdminSigners allows the given signer addresses to sign transactions on behalf of any persona, so that support
+// staff can fix user-specific state through the game's normal messages. Each impersonated transaction is written to
+// the audit log, and the admin signer is recorded in its receipt. Admin signers are also the only signers that may
+// send the messages that were declared with message.WithAdminSignature, such as content imports. WithAdminSigners
+// may be given more than once.
+func WithAdminSigners(addresses ...string) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.adminSigners = append(world.adminSigners, addresses...)
+		},
+	}
+}
+
+// WithImpersonationDisabled rejects transactions that are signed by an admin signer on behalf of a persona, even if
+// admin signers are configured with WithAdminSigners.
+func WithImpersonationDisabled() WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.impersonationDisabled = true
+		},
+	}
+}
+
+// WithGameVersion sets the version of the game that is recorded in the world's fingerprint, and that migrations are
+// registered from; see RegisterMigrations. It defaults to the version or VCS revision of the game binary.
+func WithGameVersion(version string) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.gameVersion = version
+		},
+	}
+}
+
+// WithPersonaNamePolicy replaces the policy that decides which persona tags may be registered, which defaults to
+// persona.DefaultNamePolicy. Use persona.NewAsyncNamePolicy to screen tags with an external service.
+func WithPersonaNamePolicy(policy persona.NamePolicy) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.personaNamePolicy = policy
+		},
+	}
+}
+
+// WithReservedPersonaTags reserves persona tags for the game, such as "admin" or the names of its NPCs, so that no
+// player can register them. Tags are reserved regardless of their case, just as persona tags are unique regardless of
+// their case. The option can be used more than once.
+func WithReservedPersonaTags(personaTags ...string) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			if world.reservedPersonaTags == nil {
+				world.reservedPersonaTags = map[string]struct{}{}
+			}
+			for _, personaTag := range personaTags {
+				world.reservedPersonaTags[strings.ToLower(personaTag)] = struct{}{}
+			}
+		},
+	}
+}
+
 // WithDisableSignatureVerification disables signature verification for the HTTP server. This should only be
 // used for local development.
 func WithDisableSignatureVerification() WorldOption {
@@ -47,9 +365,10 @@ func WithDisableSignatureVerification() WorldOption {
 	}
 }
 
-// WithTickChannel sets the channel that will be used to decide when world.doTick is executed. If unset, a loop interval
-// of 1 second will be set. To set some other time, use: WithTickChannel(time.Tick(<some-duration>)). Tests can pass
-// in a channel controlled by the test for fine-grained control over when ticks are executed.
+// WithTickChannel sets the channel that will be used to decide when world.doTick is executed. If unset, a tick is
+// started every DefaultTickInterval of the world's clock. To set some other time, use:
+// WithTickChannel(time.Tick(<some-duration>)). Tests can pass in a channel controlled by the test for fine-grained
+// control over when ticks are executed.
 func WithTickChannel(ch <-chan time.Time) WorldOption {
 	return WorldOption{
 		cardinalOption: func(world *World) {
@@ -58,6 +377,17 @@ func WithTickChannel(ch <-chan time.Time) WorldOption {
 	}
 }
 
+// WithClock sets the clock that starts ticks, unless WithTickChannel is given, and that timestamps them. Tests can pass
+// a clock.Manual to advance time instantly and deterministically instead of sleeping. By default, the world uses
+// clock.Real.
+func WithClock(c clock.Clock) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.timeSource = c
+		},
+	}
+}
+
 // WithTickDoneChannel sets a channel that will be notified each time a tick completes. The completed tick will be
 // pushed to the channel. This option is useful in tests when assertions need to be performed at the end of a tick.
 func WithTickDoneChannel(ch chan<- uint64) WorldOption {
@@ -118,3 +448,40 @@ func WithPrettyLog() WorldOption {
 		},
 	}
 }
+
+// WithStorageMetrics times the storage operations of systems and tx middleware, and the commit of every tick. The
+// latencies are reported as statsd histograms tagged with the operation and the component, and summed up by
+// World.StorageStats. Operations that take at least slowThreshold are logged with the component, the entity, and the
+// system that performed them; a zero threshold logs none. By default, storage operations are not timed.
+func WithStorageMetrics(slowThreshold time.Duration) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.storageMetrics = newStorageMetrics(slowThreshold, world.currentSystemName)
+		},
+	}
+}
+
+// WithTxLogWriter writes the transactions of every tick that the world commits to the writer, as a stream of JSON
+// encoded TxLogEntry values. Together with a snapshot of the world's state and the hash of the state it leads to, the
+// transaction log lets anyone replay and verify the world's history; see World.VerifyStateCommitment and package
+// verifier. A tick fails if its entry can't be written.
+func WithTxLogWriter(w io.Writer) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.txLog = newTxLogWriter(w)
+		},
+	}
+}
+
+// WithModuleBudgets attributes the time and the storage operations of every tick to the modules whose systems and tx
+// middleware use them, and warns about the modules that exceed their budget. Budgets are keyed by module name, and
+// the game's own systems are budgeted under "". Modules without a budget are accounted, but never exceed it. The
+// usage of every module is reported as statsd metrics and by World.ModuleUsage.
+func WithModuleBudgets(budgets map[string]ModuleBudget) WorldOption {
+	return WorldOption{
+		cardinalOption: func(world *World) {
+			world.budgets = newModuleAccounting(budgets)
+			world.systemManager.SetObserver(world.observeSystem)
+		},
+	}
+}
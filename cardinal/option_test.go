@@ -4,6 +4,7 @@ import (
 	"io"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/goccy/go-json"
 	"github.com/rs/zerolog"
@@ -11,7 +12,9 @@ import (
 
 	"pkg.world.dev/world-engine/assert"
 	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/clock"
 	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
 )
 
 func TestOptionFunctionSignatures(_ *testing.T) {
@@ -45,3 +48,20 @@ func isValidJSON(bz []byte) bool {
 	var js map[string]interface{}
 	return json.Unmarshal(bz, &js) == nil
 }
+
+func TestWithClockTicksOnSimulatedTime(t *testing.T) {
+	start := time.Unix(1_000_000, 0)
+	c := clock.NewManual(start)
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithClock(c), cardinal.WithTickChannel(c.Tick(time.Second)))
+	var timestamps []uint64
+	assert.NilError(t, cardinal.RegisterSystems(tf.World, func(wCtx engine.Context) error {
+		timestamps = append(timestamps, wCtx.Timestamp())
+		return nil
+	}))
+	tf.StartWorld()
+
+	go c.Advance(2 * time.Second)
+	assert.Equal(t, <-tf.DoneTickCh, uint64(0))
+	assert.Equal(t, <-tf.DoneTickCh, uint64(1))
+	assert.DeepEqual(t, timestamps, []uint64{1_000_001, 1_000_002})
+}
@@ -0,0 +1,302 @@
+// Package oracle is a module for data from outside the world, such as prices, weather, or randomness. Each feed has
+// a set of trusted providers, who sign the values they observe:
+//
+//	err = world.UseModule(oracle.NewModule(
+//		oracle.WithFeed("eth-usd", time.Minute, providerAddress),
+//	))
+//
+// A signed report can be delivered by anyone, such as the provider itself or the relay, in a report transaction:
+//
+//	report, err := oracle.SignReport(providerKey, namespace, "eth-usd", []byte(`"3120.55"`), uint64(time.Now().Unix()))
+//	// send report to /tx/oracle/report
+//
+// Reports are checked by tx middleware at the start of the tick they are included in, before any system runs, so
+// every system in a tick sees the same value. A report is rejected unless it is signed by a provider of its feed, is
+// newer than the feed's current value, and is not older than the feed's maximum age. Systems read the current value
+// of a feed with Read, which also reports whether the feed is available:
+//
+//	price, reading, err := oracle.Read[string](wCtx, "eth-usd")
+//	if errors.Is(err, oracle.ErrUnavailable) || errors.Is(err, oracle.ErrStale) {
+//		// pause trading until a fresh price arrives
+//	}
+package oracle
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+)
+
+const (
+	ModuleName    = "oracle"
+	ModuleVersion = "v1.0.0"
+
+	ReportMessageName = "report"
+)
+
+// domain separates report digests from the digests of other things that the same key might sign.
+const domain = "cardinal-oracle-v1"
+
+var (
+	ErrUnknownFeed          = errors.New("unknown oracle feed")
+	ErrUnauthorizedProvider = errors.New("report is not signed by a provider of the feed")
+	ErrOutdatedReport       = errors.New("report is not newer than the current value of the feed")
+	ErrFutureReport         = errors.New("report was observed after the tick")
+	ErrStale                = errors.New("oracle value is stale")
+	ErrUnavailable          = errors.New("oracle feed has no value yet")
+)
+
+var _ cardinal.Module = &Module{}
+
+// Report is a value of a feed, signed by one of the feed's providers.
+type Report struct {
+	Feed string `json:"feed"`
+	// Value is the JSON encoded value. It is sent as a string so that the signed body of the transaction can be
+	// normalized without changing the bytes that the provider signed.
+	Value string `json:"value"`
+	// ObservedAt is the UNIX timestamp at which the provider observed the value.
+	ObservedAt uint64 `json:"observedAt"`
+	Signature  string `json:"signature"`
+}
+
+type ReportResult struct {
+	Accepted bool `json:"accepted"`
+}
+
+// SignReport returns a report of the JSON encoded value, signed by the key for the world with the given namespace.
+func SignReport(key *ecdsa.PrivateKey, namespace, feed string, value []byte, observedAt uint64) (Report, error) {
+	if !json.Valid(value) {
+		return Report{}, eris.New("oracle value must be valid JSON")
+	}
+	r := Report{Feed: feed, Value: string(value), ObservedAt: observedAt}
+	sig, err := crypto.Sign(r.Digest(namespace), key)
+	if err != nil {
+		return Report{}, eris.Wrap(err, "failed to sign report")
+	}
+	r.Signature = hexutil.Encode(sig)
+	return r, nil
+}
+
+// Digest returns the hash that the report's signature signs. It covers the namespace of the world, so that a report
+// for one world can't be replayed in another, the feed, the value, and the observation time.
+func (r Report) Digest(namespace string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(domain)
+	for _, part := range [][]byte{
+		[]byte(namespace), []byte(r.Feed), []byte(r.Value), binary.BigEndian.AppendUint64(nil, r.ObservedAt),
+	} {
+		buf.Write(binary.BigEndian.AppendUint64(nil, uint64(len(part))))
+		buf.Write(part)
+	}
+	return crypto.Keccak256(buf.Bytes())
+}
+
+// Signer returns the address of the key that signed the report.
+func (r Report) Signer(namespace string) (string, error) {
+	sig, err := hexutil.Decode(r.Signature)
+	if err != nil {
+		return "", eris.Wrap(err, "invalid report signature")
+	}
+	pubKey, err := crypto.SigToPub(r.Digest(namespace), sig)
+	if err != nil {
+		return "", eris.Wrap(err, "invalid report signature")
+	}
+	return crypto.PubkeyToAddress(*pubKey).Hex(), nil
+}
+
+// Reading is the current value of a feed.
+type Reading struct {
+	Feed string `json:"feed"`
+	// Value is the JSON encoded value.
+	Value      string `json:"value"`
+	ObservedAt uint64 `json:"observedAt"`
+	// Provider is the address of the provider that signed the value.
+	Provider string `json:"provider"`
+	// Tick is the tick in which the value was reported.
+	Tick uint64 `json:"tick"`
+	// StaleAt is the UNIX timestamp after which the value is stale, or zero if it never is.
+	StaleAt uint64 `json:"staleAt"`
+}
+
+func (Reading) Name() string { return "oracle-reading" }
+
+type feed struct {
+	maxAge    time.Duration
+	providers []string
+}
+
+type Option func(*Module)
+
+// WithFeed adds a feed whose values are signed by the given provider addresses. Values that are older than maxAge are
+// rejected, and become stale once they are; a maxAge of zero means values never go stale.
+func WithFeed(name string, maxAge time.Duration, providers ...string) Option {
+	return func(m *Module) {
+		m.feeds[name] = feed{maxAge: maxAge, providers: providers}
+	}
+}
+
+type Module struct {
+	cardinal.ModuleBase
+	feeds map[string]feed
+	// namespace is the namespace of the world that uses the module, which reports are signed for.
+	namespace string
+}
+
+func NewModule(opts ...Option) *Module {
+	m := &Module{feeds: map[string]feed{}}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func (*Module) Name() string    { return ModuleName }
+func (*Module) Version() string { return ModuleVersion }
+
+func (*Module) RegisterComponents(w *cardinal.World) error {
+	return cardinal.RegisterComponent[Reading](w)
+}
+
+func (*Module) RegisterTxs(w *cardinal.World) error {
+	return cardinal.RegisterMessage[Report, ReportResult](w, ReportMessageName)
+}
+
+func (*Module) RegisterSystems(w *cardinal.World) error {
+	return cardinal.RegisterSystems(w, reportSystem)
+}
+
+// Init validates the feeds and registers the tx middleware that accepts reports.
+func (m *Module) Init(w *cardinal.World) error {
+	for name, f := range m.feeds {
+		if len(f.providers) == 0 {
+			return eris.Errorf("oracle feed %q has no providers", name)
+		}
+		for _, provider := range f.providers {
+			if !common.IsHexAddress(provider) {
+				return eris.Errorf("invalid provider address %q of oracle feed %q", provider, name)
+			}
+		}
+	}
+	m.namespace = w.Namespace()
+	return cardinal.RegisterTxMiddleware(w, m.acceptReport)
+}
+
+// reportSystem records the receipts of the reports that were accepted by the middleware.
+func reportSystem(wCtx engine.Context) error {
+	return cardinal.EachMessage[Report, ReportResult](wCtx, func(message.TxData[Report]) (ReportResult, error) {
+		return ReportResult{Accepted: true}, nil
+	})
+}
+
+// acceptReport makes the value of a valid report the current value of its feed.
+func (m *Module) acceptReport(wCtx engine.Context, _ types.Message, tx txpool.TxData) error {
+	r, ok := tx.Msg.(Report)
+	if !ok {
+		return nil
+	}
+	f, ok := m.feeds[r.Feed]
+	if !ok {
+		return eris.Wrapf(ErrUnknownFeed, "%q", r.Feed)
+	}
+	signer, err := r.Signer(m.namespace)
+	if err != nil {
+		return eris.Wrap(ErrUnauthorizedProvider, err.Error())
+	}
+	authorized := false
+	for _, provider := range f.providers {
+		authorized = authorized || strings.EqualFold(provider, signer)
+	}
+	if !authorized {
+		return eris.Wrapf(ErrUnauthorizedProvider, "signed by %s", signer)
+	}
+
+	now := wCtx.Timestamp()
+	if r.ObservedAt > now {
+		return eris.Wrapf(ErrFutureReport, "observed at %d, tick is at %d", r.ObservedAt, now)
+	}
+	reading := Reading{
+		Feed:       r.Feed,
+		Value:      r.Value,
+		ObservedAt: r.ObservedAt,
+		Provider:   signer,
+		Tick:       wCtx.CurrentTick(),
+	}
+	if f.maxAge > 0 {
+		reading.StaleAt = r.ObservedAt + uint64(f.maxAge/time.Second)
+		if now > reading.StaleAt {
+			return eris.Wrapf(ErrStale, "observed at %d, tick is at %d", r.ObservedAt, now)
+		}
+	}
+
+	id, current, err := find(wCtx, r.Feed)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		_, err = cardinal.Create(wCtx, reading)
+		return err
+	}
+	// Older reports are rejected, so that a report can't be replayed to roll the feed back.
+	if r.ObservedAt <= current.ObservedAt {
+		return eris.Wrapf(ErrOutdatedReport, "observed at %d, current value was observed at %d",
+			r.ObservedAt, current.ObservedAt)
+	}
+	return cardinal.SetComponent[Reading](wCtx, id, &reading)
+}
+
+// Read returns the current value of the feed, decoded as a T. ErrUnavailable is returned if the feed has no value
+// yet. If the value is stale, it is returned together with ErrStale, so that systems can decide whether to use it.
+func Read[T any](wCtx engine.Context, feed string) (T, *Reading, error) {
+	var value T
+	_, reading, err := find(wCtx, feed)
+	if err != nil {
+		return value, nil, err
+	}
+	if reading == nil {
+		return value, nil, eris.Wrapf(ErrUnavailable, "%q", feed)
+	}
+	if err := json.Unmarshal([]byte(reading.Value), &value); err != nil {
+		return value, reading, eris.Wrapf(err, "failed to decode value of oracle feed %q", feed)
+	}
+	if reading.StaleAt > 0 && wCtx.Timestamp() > reading.StaleAt {
+		return value, reading, eris.Wrapf(ErrStale, "%q", feed)
+	}
+	return value, reading, nil
+}
+
+// Available reports whether the feed has a value that is not stale.
+func Available(wCtx engine.Context, feed string) bool {
+	_, reading, err := find(wCtx, feed)
+	return err == nil && reading != nil && (reading.StaleAt == 0 || wCtx.Timestamp() <= reading.StaleAt)
+}
+
+func find(wCtx engine.Context, feed string) (types.EntityID, *Reading, error) {
+	ids, err := cardinal.NewSearch().
+		Entity(filter.Exact(filter.Component[Reading]())).
+		Where(cardinal.FilterFunction[Reading](func(r Reading) bool { return r.Feed == feed })).
+		Collect(wCtx)
+	if err != nil || len(ids) == 0 {
+		return 0, nil, err
+	}
+	reading, err := cardinal.GetComponent[Reading](wCtx, ids[0])
+	if err != nil {
+		return 0, nil, err
+	}
+	return ids[0], reading, nil
+}
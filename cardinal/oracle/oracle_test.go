@@ -0,0 +1,106 @@
+package oracle_test
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/clock"
+	"pkg.world.dev/world-engine/cardinal/oracle"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type oracleFixture struct {
+	*testutils.TestFixture
+	clock    *clock.Manual
+	provider *ecdsa.PrivateKey
+	// price is the price that the game system read in the last tick, and priceErr the error it got.
+	price    float64
+	priceErr error
+}
+
+func newOracleFixture(t *testing.T) *oracleFixture {
+	provider, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	c := clock.NewManual(time.Unix(1_700_000_000, 0))
+	tf := &oracleFixture{
+		TestFixture: testutils.NewTestFixture(t, nil, cardinal.WithClock(c)),
+		clock:       c,
+		provider:    provider,
+	}
+	assert.NilError(t, tf.World.UseModule(oracle.NewModule(
+		oracle.WithFeed("eth-usd", time.Minute, crypto.PubkeyToAddress(provider.PublicKey).Hex()),
+	)))
+	assert.NilError(t, cardinal.RegisterSystems(tf.World, func(wCtx engine.Context) error {
+		tf.price, _, tf.priceErr = oracle.Read[float64](wCtx, "eth-usd")
+		return nil
+	}))
+	tf.DoTick()
+	return tf
+}
+
+func (tf *oracleFixture) report(key *ecdsa.PrivateKey, value string, observedAt time.Time) types.TxHash {
+	r, err := oracle.SignReport(key, tf.World.Namespace(), "eth-usd", []byte(value), uint64(observedAt.Unix()))
+	assert.NilError(tf, err)
+	msgType, ok := tf.World.GetMessageByFullName(oracle.ModuleName + "." + oracle.ReportMessageName)
+	assert.Check(tf, ok)
+	return tf.AddTransaction(msgType.ID(), r, testutils.UniqueSignatureWithName("relay"))
+}
+
+func (tf *oracleFixture) errs(hash types.TxHash) []error {
+	_, errs, ok := cardinal.NewReadOnlyWorldContext(tf.World).GetTransactionReceipt(hash)
+	assert.Check(tf, ok)
+	return errs
+}
+
+func TestOracleValueIsAvailableToSystemsInTheTickItIsReported(t *testing.T) {
+	tf := newOracleFixture(t)
+	assert.Check(t, errors.Is(tf.priceErr, oracle.ErrUnavailable))
+
+	hash := tf.report(tf.provider, "3120.5", tf.clock.Now())
+	tf.DoTick()
+	assert.Equal(t, len(tf.errs(hash)), 0)
+	assert.NilError(t, tf.priceErr)
+	assert.Equal(t, tf.price, 3120.5)
+
+	// The value goes stale once it is older than the feed's maximum age.
+	tf.clock.Advance(2 * time.Minute)
+	tf.DoTick()
+	assert.Check(t, errors.Is(tf.priceErr, oracle.ErrStale))
+	assert.Equal(t, tf.price, 3120.5)
+}
+
+func TestOracleRejectsInvalidReports(t *testing.T) {
+	tf := newOracleFixture(t)
+	now := tf.clock.Now()
+	accepted := tf.report(tf.provider, "3000", now)
+	tf.DoTick()
+	assert.Equal(t, len(tf.errs(accepted)), 0)
+
+	stranger, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	testCases := []struct {
+		name    string
+		hash    types.TxHash
+		wantErr error
+	}{
+		{"unauthorized provider", tf.report(stranger, "1", now.Add(time.Second)), oracle.ErrUnauthorizedProvider},
+		{"replayed report", tf.report(tf.provider, "2", now), oracle.ErrOutdatedReport},
+		{"too old", tf.report(tf.provider, "3", now.Add(-time.Hour)), oracle.ErrStale},
+		{"from the future", tf.report(tf.provider, "4", now.Add(time.Hour)), oracle.ErrFutureReport},
+	}
+	tf.DoTick()
+	for _, tc := range testCases {
+		errs := tf.errs(tc.hash)
+		assert.Equal(t, len(errs), 1, tc.name)
+		assert.Check(t, errors.Is(errs[0], tc.wantErr), tc.name)
+	}
+	assert.Equal(t, tf.price, 3000.0)
+}
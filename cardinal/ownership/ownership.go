@@ -0,0 +1,434 @@
+// Package ownership is a module that records which persona owns an entity, and checks that transactions only act on
+// the entities of the persona that signed them.
+//
+// Systems check ownership themselves with RequireOwner:
+//
+//	err = world.UseModule(ownership.NewModule())
+//	...
+//	if err := ownership.RequireOwner(wCtx, tx.Msg.UnitID, tx.Tx); err != nil {
+//		return MoveResult{}, err
+//	}
+//
+// With WithEnforcement, the module also checks every transaction whose message declares its target entity with the
+// TargetTag, before any system runs, and rejects the transactions whose signer doesn't own the target:
+//
+//	type MoveMsg struct {
+//		UnitID types.EntityID `ownership:"target"`
+//		X, Y   int
+//	}
+//
+// The module finds the tagged messages when it is used, so it must be used after they are registered.
+//
+// The module also tracks the approximate storage used by each persona, as the number of entities it owns and the size
+// of their JSON encoded components. With WithQuota, entities created through the module's Create are rejected once
+// their owner would exceed its quota, so a single player can't fill a shared world with state:
+//
+//	owners := ownership.NewModule(ownership.WithQuota(1_000, 1<<20))
+//	err = world.UseModule(owners)
+//	...
+//	unitID, err := owners.Create(wCtx, tx.Tx.PersonaTag, Unit{}, Health{HP: 100})
+//	if errors.Is(err, ownership.ErrQuotaExceeded) {
+//		return SpawnResult{}, err
+//	}
+package ownership
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+	"pkg.world.dev/world-engine/sign"
+)
+
+const (
+	ModuleName    = "ownership"
+	ModuleVersion = "v1.0.0"
+
+	// TargetTag is the struct tag that marks the field of a message that holds the entity the message acts on:
+	// `ownership:"target"`. The field must be a types.EntityID.
+	TargetTag = "ownership"
+
+	// DefaultUsageRefresh is the default number of ticks between recounts of the storage used by each persona.
+	DefaultUsageRefresh = 100
+)
+
+var _ cardinal.Module = &Module{}
+
+// ErrNotOwner is returned when a transaction acts on an entity that isn't owned by the persona that signed it.
+var ErrNotOwner = errors.New("entity is not owned by the signer")
+
+// ErrQuotaExceeded is returned when an entity can't be created because its owner would exceed its storage quota.
+var ErrQuotaExceeded = errors.New("persona storage quota exceeded")
+
+// Owner records the persona that owns an entity.
+type Owner struct {
+	PersonaTag string `json:"personaTag"`
+}
+
+func (Owner) Name() string { return "owner" }
+
+// OwnerOf returns the persona tag of the owner of the entity, and false if the entity has no owner.
+func OwnerOf(wCtx engine.Context, id types.EntityID) (string, bool, error) {
+	owner, err := cardinal.GetComponent[Owner](wCtx, id)
+	if eris.Is(err, cardinal.ErrComponentNotOnEntity) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+	return owner.PersonaTag, true, nil
+}
+
+// SetOwner makes the persona the owner of the entity, replacing its previous owner, if any. The entity's storage is
+// moved from the previous owner's usage to the persona's. SetOwner doesn't check quotas.
+func SetOwner(wCtx engine.Context, id types.EntityID, personaTag string) error {
+	previous, ok, err := OwnerOf(wCtx, id)
+	if err != nil {
+		return err
+	}
+	if ok && previous == personaTag {
+		return nil
+	}
+	if !ok {
+		if err := cardinal.AddComponentTo[Owner](wCtx, id); err != nil {
+			return err
+		}
+	}
+	if err := cardinal.SetComponent[Owner](wCtx, id, &Owner{PersonaTag: personaTag}); err != nil {
+		return err
+	}
+	size, err := entitySize(wCtx, id)
+	if err != nil {
+		return err
+	}
+	if ok {
+		if err := addUsage(wCtx, previous, -1, -size); err != nil {
+			return err
+		}
+	}
+	return addUsage(wCtx, personaTag, 1, size)
+}
+
+// RequireOwner returns ErrNotOwner unless the entity is owned by the persona that signed the transaction. Entities
+// without an owner are owned by no one.
+func RequireOwner(wCtx engine.Context, id types.EntityID, tx *sign.Transaction) error {
+	owner, ok, err := OwnerOf(wCtx, id)
+	if err != nil {
+		return err
+	}
+	if tx == nil || !ok || owner != tx.PersonaTag {
+		personaTag := ""
+		if tx != nil {
+			personaTag = tx.PersonaTag
+		}
+		return eris.Wrapf(ErrNotOwner, "entity %d is not owned by persona %q", id, personaTag)
+	}
+	return nil
+}
+
+// Usage is the approximate storage used by the entities that a persona owns. It is updated when an entity changes
+// owner, and recounted periodically to account for entities that were removed or whose components changed size.
+type Usage struct {
+	PersonaTag string `json:"personaTag"`
+	Entities   int    `json:"entities"`
+	// Bytes is the total size of the JSON encoded components of the entities.
+	Bytes int `json:"bytes"`
+}
+
+func (Usage) Name() string { return "ownership-usage" }
+
+// UsageOf returns the storage used by the persona.
+func UsageOf(wCtx engine.Context, personaTag string) (Usage, error) {
+	_, usage, err := findUsage(wCtx, personaTag)
+	if err != nil || usage == nil {
+		return Usage{PersonaTag: personaTag}, err
+	}
+	return *usage, nil
+}
+
+func findUsage(wCtx engine.Context, personaTag string) (types.EntityID, *Usage, error) {
+	ids, err := cardinal.NewSearch().
+		Entity(filter.Exact(filter.Component[Usage]())).
+		Where(cardinal.FilterFunction[Usage](func(u Usage) bool { return u.PersonaTag == personaTag })).
+		Collect(wCtx)
+	if err != nil || len(ids) == 0 {
+		return 0, nil, err
+	}
+	usage, err := cardinal.GetComponent[Usage](wCtx, ids[0])
+	if err != nil {
+		return 0, nil, err
+	}
+	return ids[0], usage, nil
+}
+
+func addUsage(wCtx engine.Context, personaTag string, entities, bytes int) error {
+	id, usage, err := findUsage(wCtx, personaTag)
+	if err != nil {
+		return err
+	}
+	if usage == nil {
+		_, err = cardinal.Create(wCtx, Usage{PersonaTag: personaTag, Entities: entities, Bytes: bytes})
+		return err
+	}
+	usage.Entities = max(usage.Entities+entities, 0)
+	usage.Bytes = max(usage.Bytes+bytes, 0)
+	return cardinal.SetComponent[Usage](wCtx, id, usage)
+}
+
+// entitySize returns the total size of the JSON encoded components of the entity.
+func entitySize(wCtx engine.Context, id types.EntityID) (int, error) {
+	store := wCtx.StoreReader()
+	comps, err := store.GetComponentTypesForEntity(id)
+	if err != nil {
+		return 0, err
+	}
+	size := 0
+	for _, comp := range comps {
+		raw, err := store.GetComponentForEntityInRawJSON(comp, id)
+		if err != nil {
+			return 0, err
+		}
+		size += len(raw)
+	}
+	return size, nil
+}
+
+type Option func(*Module)
+
+// WithEnforcement rejects the transactions whose message has a field tagged with TargetTag, unless the persona that
+// signed the transaction owns the entity in that field. Transactions are checked by tx middleware before any system
+// runs, so that the systems of those messages don't need to call RequireOwner.
+func WithEnforcement() Option {
+	return func(m *Module) {
+		m.enforce = true
+	}
+}
+
+// WithQuota limits the number of entities and the bytes of storage that each persona can own. A limit of zero means
+// no limit. The quota is checked by Create and CheckQuota; entities that are given an owner with SetOwner are counted,
+// but never rejected.
+func WithQuota(maxEntities, maxBytes int) Option {
+	return func(m *Module) {
+		m.maxEntities = maxEntities
+		m.maxBytes = maxBytes
+	}
+}
+
+// WithUsageRefresh sets the number of ticks between recounts of the storage used by each persona.
+func WithUsageRefresh(ticks uint64) Option {
+	return func(m *Module) {
+		m.refresh = ticks
+	}
+}
+
+type Module struct {
+	cardinal.ModuleBase
+	enforce bool
+	// targets maps the IDs of the enforced messages to the index of their target field.
+	targets     map[types.MessageID]int
+	maxEntities int
+	maxBytes    int
+	refresh     uint64
+}
+
+func NewModule(opts ...Option) *Module {
+	m := &Module{targets: map[types.MessageID]int{}, refresh: DefaultUsageRefresh}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func (*Module) Name() string    { return ModuleName }
+func (*Module) Version() string { return ModuleVersion }
+
+func (*Module) RegisterComponents(w *cardinal.World) error {
+	if err := cardinal.RegisterComponent[Owner](w); err != nil {
+		return err
+	}
+	return cardinal.RegisterComponent[Usage](w)
+}
+
+func (m *Module) RegisterSystems(w *cardinal.World) error {
+	return cardinal.RegisterSystems(w, m.usageSystem)
+}
+
+// CheckQuota returns ErrQuotaExceeded if the persona would exceed its quota by owning the given number of additional
+// entities and bytes of storage. Games can call it before doing work that creates entities outside of Create.
+func (m *Module) CheckQuota(wCtx engine.Context, personaTag string, entities, bytes int) error {
+	usage, err := UsageOf(wCtx, personaTag)
+	if err != nil {
+		return err
+	}
+	if m.maxEntities > 0 && usage.Entities+entities > m.maxEntities {
+		return eris.Wrapf(ErrQuotaExceeded, "persona %q owns %d entities, and may own at most %d",
+			personaTag, usage.Entities, m.maxEntities)
+	}
+	if m.maxBytes > 0 && usage.Bytes+bytes > m.maxBytes {
+		return eris.Wrapf(ErrQuotaExceeded, "persona %q uses %d bytes, and %d more would exceed its quota of %d",
+			personaTag, usage.Bytes, bytes, m.maxBytes)
+	}
+	return nil
+}
+
+// Create creates an entity with the components that is owned by the persona, unless that would exceed the persona's
+// quota, in which case ErrQuotaExceeded is returned and nothing is created.
+func (m *Module) Create(wCtx engine.Context, personaTag string, components ...types.Component) (types.EntityID, error) {
+	size, err := encodedSize(Owner{PersonaTag: personaTag})
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range components {
+		n, err := encodedSize(c)
+		if err != nil {
+			return 0, err
+		}
+		size += n
+	}
+	if err := m.CheckQuota(wCtx, personaTag, 1, size); err != nil {
+		return 0, err
+	}
+	id, err := cardinal.Create(wCtx, components...)
+	if err != nil {
+		return 0, err
+	}
+	return id, SetOwner(wCtx, id, personaTag)
+}
+
+func encodedSize(c types.Component) (int, error) {
+	buf, err := json.Marshal(c)
+	if err != nil {
+		return 0, eris.Wrapf(err, "failed to encode component %q", c.Name())
+	}
+	return len(buf), nil
+}
+
+// usageSystem periodically recounts the storage used by each persona from the entities it owns.
+func (m *Module) usageSystem(wCtx engine.Context) error {
+	if m.refresh == 0 || wCtx.CurrentTick()%m.refresh != 0 {
+		return nil
+	}
+	counted := map[string]*Usage{}
+	var err error
+	searchErr := cardinal.NewSearch().Entity(filter.Contains(filter.Component[Owner]())).Each(wCtx,
+		func(id types.EntityID) bool {
+			var owner *Owner
+			if owner, err = cardinal.GetComponent[Owner](wCtx, id); err != nil {
+				return false
+			}
+			var size int
+			if size, err = entitySize(wCtx, id); err != nil {
+				return false
+			}
+			u, ok := counted[owner.PersonaTag]
+			if !ok {
+				u = &Usage{PersonaTag: owner.PersonaTag}
+				counted[owner.PersonaTag] = u
+			}
+			u.Entities++
+			u.Bytes += size
+			return true
+		})
+	if err = errors.Join(searchErr, err); err != nil {
+		return err
+	}
+
+	var stale []types.EntityID
+	searchErr = cardinal.NewSearch().Entity(filter.Exact(filter.Component[Usage]())).Each(wCtx,
+		func(id types.EntityID) bool {
+			var usage *Usage
+			if usage, err = cardinal.GetComponent[Usage](wCtx, id); err != nil {
+				return false
+			}
+			u, ok := counted[usage.PersonaTag]
+			if !ok {
+				stale = append(stale, id)
+				return true
+			}
+			delete(counted, usage.PersonaTag)
+			err = cardinal.SetComponent[Usage](wCtx, id, u)
+			return err == nil
+		})
+	if err = errors.Join(searchErr, err); err != nil {
+		return err
+	}
+	for _, id := range stale {
+		if err := cardinal.Remove(wCtx, id); err != nil {
+			return err
+		}
+	}
+	// Usage is created in order of persona tag, so that every node assigns the same entity IDs.
+	personaTags := make([]string, 0, len(counted))
+	for personaTag := range counted {
+		personaTags = append(personaTags, personaTag)
+	}
+	sort.Strings(personaTags)
+	for _, personaTag := range personaTags {
+		if _, err := cardinal.Create(wCtx, *counted[personaTag]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Init finds the messages that declare a target entity, and registers the tx middleware that enforces their
+// ownership, if enforcement is enabled.
+func (m *Module) Init(w *cardinal.World) error {
+	if !m.enforce {
+		return nil
+	}
+	entityIDType := reflect.TypeOf(types.EntityID(0))
+	for _, msg := range w.GetRegisteredMessages() {
+		in := msg.InType()
+		for in.Kind() == reflect.Pointer {
+			in = in.Elem()
+		}
+		if in.Kind() != reflect.Struct {
+			continue
+		}
+		for i := range in.NumField() {
+			if in.Field(i).Tag.Get(TargetTag) != "target" {
+				continue
+			}
+			if in.Field(i).Type != entityIDType {
+				return eris.Errorf("target field %s of message %q must be a types.EntityID",
+					in.Field(i).Name, msg.FullName())
+			}
+			if _, ok := m.targets[msg.ID()]; ok {
+				return eris.Errorf("message %q has more than one target field", msg.FullName())
+			}
+			m.targets[msg.ID()] = i
+		}
+	}
+	return cardinal.RegisterTxMiddleware(w, m.enforceOwnership)
+}
+
+// enforceOwnership rejects the transactions of the messages with a target field whose signer doesn't own the target.
+func (m *Module) enforceOwnership(wCtx engine.Context, msg types.Message, tx txpool.TxData) error {
+	field, ok := m.targets[msg.ID()]
+	if !ok {
+		return nil
+	}
+	v := reflect.ValueOf(tx.Msg)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	id, ok := v.Field(field).Interface().(types.EntityID)
+	if !ok {
+		return nil
+	}
+	return RequireOwner(wCtx, id, tx.Tx)
+}
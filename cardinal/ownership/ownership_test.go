@@ -0,0 +1,163 @@
+package ownership_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/ownership"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type Unit struct{}
+
+func (Unit) Name() string { return "unit" }
+
+type MoveMsg struct {
+	UnitID types.EntityID `ownership:"target"`
+}
+
+type RenameMsg struct {
+	UnitID types.EntityID
+}
+
+type Result struct{}
+
+func TestOwnershipIsEnforced(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Unit](world))
+	assert.NilError(t, cardinal.RegisterMessage[MoveMsg, Result](world, "move"))
+	assert.NilError(t, cardinal.RegisterMessage[RenameMsg, Result](world, "rename"))
+	var unitID types.EntityID
+	assert.NilError(t, cardinal.RegisterInitSystems(world, func(wCtx engine.Context) error {
+		var err error
+		if unitID, err = cardinal.Create(wCtx, Unit{}); err != nil {
+			return err
+		}
+		return ownership.SetOwner(wCtx, unitID, "alice")
+	}))
+	moves := map[string]int{}
+	assert.NilError(t, cardinal.RegisterSystems(world,
+		func(wCtx engine.Context) error {
+			return cardinal.EachMessage[MoveMsg, Result](wCtx, func(tx message.TxData[MoveMsg]) (Result, error) {
+				moves[tx.Tx.PersonaTag]++
+				return Result{}, nil
+			})
+		},
+		func(wCtx engine.Context) error {
+			return cardinal.EachMessage[RenameMsg, Result](wCtx, func(tx message.TxData[RenameMsg]) (Result, error) {
+				return Result{}, ownership.RequireOwner(wCtx, tx.Msg.UnitID, tx.Tx)
+			})
+		},
+	))
+	assert.NilError(t, world.UseModule(ownership.NewModule(ownership.WithEnforcement())))
+	tf.DoTick()
+
+	errorAt := func(hash types.TxHash) error {
+		_, errs, ok := cardinal.NewReadOnlyWorldContext(world).GetTransactionReceipt(hash)
+		assert.Check(t, ok)
+		if len(errs) == 0 {
+			return nil
+		}
+		return errs[0]
+	}
+	move, ok := world.GetMessageByFullName("game.move")
+	assert.Check(t, ok)
+	rename, ok := world.GetMessageByFullName("game.rename")
+	assert.Check(t, ok)
+	aliceMove := tf.AddTransaction(move.ID(), MoveMsg{UnitID: unitID}, testutils.UniqueSignatureWithName("alice"))
+	bobMove := tf.AddTransaction(move.ID(), MoveMsg{UnitID: unitID}, testutils.UniqueSignatureWithName("bob"))
+	aliceRename := tf.AddTransaction(rename.ID(), RenameMsg{UnitID: unitID}, testutils.UniqueSignatureWithName("alice"))
+	bobRename := tf.AddTransaction(rename.ID(), RenameMsg{UnitID: unitID}, testutils.UniqueSignatureWithName("bob"))
+	tf.DoTick()
+
+	assert.NilError(t, errorAt(aliceMove))
+	assert.ErrorIs(t, errorAt(bobMove), ownership.ErrNotOwner)
+	assert.Equal(t, moves["bob"], 0, "rejected transactions must not reach systems")
+	assert.NilError(t, errorAt(aliceRename))
+	assert.ErrorIs(t, errorAt(bobRename), ownership.ErrNotOwner)
+
+	owner, ok, err := ownership.OwnerOf(cardinal.NewReadOnlyWorldContext(world), unitID)
+	assert.NilError(t, err)
+	assert.Check(t, ok)
+	assert.Equal(t, owner, "alice")
+}
+
+type SpawnMsg struct{}
+
+type DespawnMsg struct {
+	UnitID types.EntityID
+}
+
+func TestCreateIsLimitedByQuota(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Unit](world))
+	assert.NilError(t, cardinal.RegisterMessage[SpawnMsg, Result](world, "spawn"))
+	assert.NilError(t, cardinal.RegisterMessage[DespawnMsg, Result](world, "despawn"))
+	owners := ownership.NewModule(ownership.WithQuota(2, 0), ownership.WithUsageRefresh(1))
+	var spawned []types.EntityID
+	assert.NilError(t, cardinal.RegisterSystems(world,
+		func(wCtx engine.Context) error {
+			return cardinal.EachMessage[SpawnMsg, Result](wCtx, func(tx message.TxData[SpawnMsg]) (Result, error) {
+				id, err := owners.Create(wCtx, tx.Tx.PersonaTag, Unit{})
+				if err != nil {
+					return Result{}, err
+				}
+				spawned = append(spawned, id)
+				return Result{}, nil
+			})
+		},
+		func(wCtx engine.Context) error {
+			return cardinal.EachMessage[DespawnMsg, Result](wCtx, func(tx message.TxData[DespawnMsg]) (Result, error) {
+				return Result{}, cardinal.Remove(wCtx, tx.Msg.UnitID)
+			})
+		},
+	))
+	assert.NilError(t, world.UseModule(owners))
+	tf.DoTick()
+
+	spawn, ok := world.GetMessageByFullName("game.spawn")
+	assert.Check(t, ok)
+	var hashes []types.TxHash
+	for _, personaTag := range []string{"alice", "alice", "alice", "bob"} {
+		hashes = append(hashes, tf.AddTransaction(spawn.ID(), SpawnMsg{}, testutils.UniqueSignatureWithName(personaTag)))
+	}
+	tf.DoTick()
+
+	wCtx := cardinal.NewReadOnlyWorldContext(world)
+	for i, hash := range hashes {
+		_, errs, ok := wCtx.GetTransactionReceipt(hash)
+		assert.Check(t, ok)
+		if i == 2 {
+			assert.Equal(t, len(errs), 1)
+			assert.ErrorIs(t, errs[0], ownership.ErrQuotaExceeded)
+		} else {
+			assert.Equal(t, len(errs), 0)
+		}
+	}
+	usage, err := ownership.UsageOf(wCtx, "alice")
+	assert.NilError(t, err)
+	assert.Equal(t, usage.Entities, 2)
+	assert.Check(t, usage.Bytes > 0)
+
+	// Removed entities stop counting against the quota once usage is recounted.
+	despawnMsg, ok := world.GetMessageByFullName("game.despawn")
+	assert.Check(t, ok)
+	despawn := tf.AddTransaction(despawnMsg.ID(), DespawnMsg{UnitID: spawned[0]},
+		testutils.UniqueSignatureWithName("alice"))
+	tf.DoTick()
+	_, errs, _ := wCtx.GetTransactionReceipt(despawn)
+	assert.Equal(t, len(errs), 0)
+	usage, err = ownership.UsageOf(wCtx, "alice")
+	assert.NilError(t, err)
+	assert.Equal(t, usage.Entities, 1)
+	respawn := tf.AddTransaction(spawn.ID(), SpawnMsg{}, testutils.UniqueSignatureWithName("alice"))
+	tf.DoTick()
+	_, errs, _ = wCtx.GetTransactionReceipt(respawn)
+	assert.Equal(t, len(errs), 0)
+}
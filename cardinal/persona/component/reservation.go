@@ -0,0 +1,21 @@
+package component
+
+// PersonaReservationComponent is a hold on a persona tag for a signer address. The hold is valid while the world's
+// tick is less than ExpiresAtTick.
+type PersonaReservationComponent struct {
+	PersonaTag    string
+	SignerAddress string
+	ExpiresAtTick uint64
+	// AwaitingApproval is set when the tag is held because the world's name policy hasn't decided on it yet. The
+	// persona is created for the signer as soon as the policy approves the tag.
+	AwaitingApproval bool
+}
+
+func (PersonaReservationComponent) Name() string {
+	return "PersonaReservationComponent"
+}
+
+// IsHeldAt reports whether the hold is valid at the given tick.
+func (r PersonaReservationComponent) IsHeldAt(tick uint64) bool {
+	return tick < r.ExpiresAtTick
+}
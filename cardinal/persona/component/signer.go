@@ -1,11 +1,73 @@
 package component
 
+import "slices"
+
 type SignerComponent struct {
 	PersonaTag          string
 	SignerAddress       string
 	AuthorizedAddresses []string
+	// SessionKeys are addresses that may sign some of the persona's transactions for a limited time.
+	SessionKeys []SessionKey
+	// PreviousSignerAddress is the signer address that was replaced by the most recent signer rotation. It may
+	// still be used to sign transactions while the world's tick is less than PreviousSignerValidUntilTick.
+	PreviousSignerAddress        string
+	PreviousSignerValidUntilTick uint64
+	// AliasOf is the persona tag of the primary persona that this persona was merged into, if any. Transactions and
+	// lookups on an alias resolve to its primary.
+	AliasOf string
+	// CreatedAtTick is the tick in which the persona was created. It is zero for personas that were created before
+	// it was recorded.
+	CreatedAtTick uint64
 }
 
 func (SignerComponent) Name() string {
 	return "SignerComponent"
 }
+
+// ValidSignerAddresses returns the addresses that may sign transactions for this persona at the given tick. The
+// current signer is always first, followed by the previous signer if its grace period has not yet elapsed.
+func (s SignerComponent) ValidSignerAddresses(tick uint64) []string {
+	addrs := []string{s.SignerAddress}
+	if s.PreviousSignerAddress != "" && tick < s.PreviousSignerValidUntilTick {
+		addrs = append(addrs, s.PreviousSignerAddress)
+	}
+	return addrs
+}
+
+// SessionKey is an address that may sign transactions for a persona until it expires, such as a key that a game client
+// generates so that players don't have to approve every move with their wallet. The key expires at ExpiresAtTick or
+// ExpiresAt, whichever comes first; a zero value means the key doesn't expire by that measure.
+type SessionKey struct {
+	Address       string
+	ExpiresAtTick uint64
+	// ExpiresAt is a UNIX timestamp in seconds.
+	ExpiresAt uint64
+	// Messages are the full names of the messages that the key may sign, such as "game.move". A key without messages
+	// may sign any message, except those that manage the persona.
+	Messages []string
+}
+
+// IsActiveAt reports whether the key is valid at the given tick and UNIX timestamp.
+func (k SessionKey) IsActiveAt(tick, timestamp uint64) bool {
+	if k.ExpiresAtTick != 0 && tick >= k.ExpiresAtTick {
+		return false
+	}
+	return k.ExpiresAt == 0 || timestamp < k.ExpiresAt
+}
+
+// Allows reports whether the key may sign the message with the given full name.
+func (k SessionKey) Allows(msgName string) bool {
+	return len(k.Messages) == 0 || slices.Contains(k.Messages, msgName)
+}
+
+// SessionKeySigners returns the addresses of the session keys that may sign the message with the given full name at
+// the given tick and UNIX timestamp.
+func (s SignerComponent) SessionKeySigners(msgName string, tick, timestamp uint64) []string {
+	var addrs []string
+	for _, k := range s.SessionKeys {
+		if k.IsActiveAt(tick, timestamp) && k.Allows(msgName) {
+			addrs = append(addrs, k.Address)
+		}
+	}
+	return addrs
+}
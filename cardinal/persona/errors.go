@@ -5,4 +5,17 @@ import "errors"
 var (
 	ErrPersonaTagHasNoSigner        = errors.New("persona tag does not have a signer")
 	ErrCreatePersonaTxsNotProcessed = errors.New("create persona txs have not been processed for the given tick")
+	ErrGracePeriodTooLong           = errors.New("signer rotation grace period is too long")
+	ErrPersonaTagReserved           = errors.New("persona tag is reserved by another signer")
+	ErrHoldTooLong                  = errors.New("persona reservation hold is too long")
+	ErrNoReservation                = errors.New("persona tag is not reserved by the signer")
+	ErrPersonaIsAlias               = errors.New("persona is an alias of another persona")
+	ErrNameRejected                 = errors.New("persona tag is not allowed by the name policy")
+	ErrNameReserved                 = errors.New("persona tag is reserved by the game")
+	ErrNamePending                  = errors.New("persona tag is awaiting approval by the name policy")
+	ErrPersonaTagPending            = errors.New("persona tag is held for a signer until it is approved")
+	ErrPersonaTagRegistered         = errors.New("persona tag has already been registered")
+	ErrNotAdminTransaction          = errors.New(
+		"personas can only be merged by a system transaction of an admin signer",
+	)
 )
@@ -0,0 +1,14 @@
+package msg
+
+const ChangeSignerMessageName = "change-signer"
+
+// ChangeSigner replaces the signer address of the persona that sent the transaction with a new key of the same owner.
+// Unlike RotatePersonaSigner, the old signer address can no longer sign for the persona once the change is processed,
+// which is what a signer whose key may have leaked wants. Authorized addresses are kept.
+type ChangeSigner struct {
+	NewSignerAddress string `json:"newSignerAddress"`
+}
+
+type ChangeSignerResult struct {
+	Success bool `json:"success"`
+}
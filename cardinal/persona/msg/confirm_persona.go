@@ -0,0 +1,16 @@
+package msg
+
+const ConfirmPersonaMessageName = "confirm-persona"
+
+// ConfirmPersona creates a persona from a persona tag that the signer address holds with ReservePersona.
+type ConfirmPersona struct {
+	PersonaTag    string `json:"personaTag"`
+	SignerAddress string `json:"signerAddress"`
+}
+
+type ConfirmPersonaResult struct {
+	Success bool `json:"success"`
+	// Pending is set when the persona tag is held for the signer until the world's name policy approves it, at which
+	// point the persona is created.
+	Pending bool `json:"pending,omitempty"`
+}
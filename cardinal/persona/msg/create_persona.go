@@ -8,6 +8,21 @@ type CreatePersona struct {
 	SignerAddress string `json:"signerAddress"`
 }
 
+// The codes of the reasons why a persona tag can't be registered; see CreatePersonaResult.Code. They are registered
+// error codes of the world, so the receipts of the transactions list them too.
+const (
+	CodeTagAlreadyRegistered = "tag-already-registered"
+	CodeTagRejected          = "tag-rejected"
+	CodeTagReserved          = "tag-reserved"
+)
+
 type CreatePersonaResult struct {
 	Success bool `json:"success"`
+	// Pending is set when the persona tag is held for the signer until the world's name policy approves it, at which
+	// point the persona is created.
+	Pending bool `json:"pending,omitempty"`
+	// Error explains why the persona tag can't be registered, when it is invalid, reserved, or already registered.
+	Error string `json:"error,omitempty"`
+	// Code is the code of the reason in Error, which clients can branch on.
+	Code string `json:"code,omitempty"`
 }
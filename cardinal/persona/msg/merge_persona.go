@@ -0,0 +1,14 @@
+package msg
+
+const MergePersonaMessageName = "merge-persona"
+
+// MergePersona makes the alias persona an alias of the primary persona, e.g. to merge duplicate accounts. Once it
+// is processed, transactions and lookups on the alias resolve to the primary. Only admin signers can send it.
+type MergePersona struct {
+	AliasPersonaTag   string `json:"aliasPersonaTag"`
+	PrimaryPersonaTag string `json:"primaryPersonaTag"`
+}
+
+type MergePersonaResult struct {
+	Success bool `json:"success"`
+}
@@ -0,0 +1,19 @@
+package msg
+
+const ReservePersonaMessageName = "reserve-persona"
+
+// ReservePersona holds a persona tag for a signer address for HoldTicks ticks, so that no one else can claim it while
+// the player finishes registering. The hold is turned into a persona by a ConfirmPersona from the same signer, and
+// expires if it isn't confirmed in time. A HoldTicks of zero uses the default hold. Reserving a tag that the signer
+// already holds extends the hold.
+type ReservePersona struct {
+	PersonaTag    string `json:"personaTag"`
+	SignerAddress string `json:"signerAddress"`
+	HoldTicks     uint64 `json:"holdTicks"`
+}
+
+type ReservePersonaResult struct {
+	Success bool `json:"success"`
+	// ExpiresAtTick is the first tick in which the hold is no longer valid.
+	ExpiresAtTick uint64 `json:"expiresAtTick"`
+}
@@ -0,0 +1,9 @@
+package msg
+
+type RevokePersonaAddress struct {
+	Address string `json:"address"`
+}
+
+type RevokePersonaAddressResult struct {
+	Success bool `json:"success"`
+}
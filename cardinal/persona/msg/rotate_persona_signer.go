@@ -0,0 +1,15 @@
+package msg
+
+const RotatePersonaSignerMessageName = "rotate-persona-signer"
+
+// RotatePersonaSigner replaces the signer address of the persona that sent the transaction. The old signer address
+// remains valid for GracePeriodTicks ticks after the rotation is processed so that in-flight transactions signed
+// with the old key are not rejected.
+type RotatePersonaSigner struct {
+	NewSignerAddress string `json:"newSignerAddress"`
+	GracePeriodTicks uint64 `json:"gracePeriodTicks"`
+}
+
+type RotatePersonaSignerResult struct {
+	Success bool `json:"success"`
+}
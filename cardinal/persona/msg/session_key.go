@@ -0,0 +1,33 @@
+package msg
+
+const (
+	AuthorizeSessionKeyMessageName = "authorize-session-key"
+	RevokeSessionKeyMessageName    = "revoke-session-key"
+)
+
+// AuthorizeSessionKey lets Address sign transactions for the persona that sent the transaction until the key expires.
+// ExpiresInTicks and ExpiresAt (a UNIX timestamp in seconds) bound the key's lifetime, and at least one of them must be
+// set. Messages limits the key to the messages with the given full names, such as "game.move"; a key without messages
+// may sign any message except those that manage the persona. Authorizing a key again replaces its expiry and messages.
+type AuthorizeSessionKey struct {
+	Address        string   `json:"address"`
+	ExpiresInTicks uint64   `json:"expiresInTicks,omitempty"`
+	ExpiresAt      uint64   `json:"expiresAt,omitempty"`
+	Messages       []string `json:"messages,omitempty"`
+}
+
+type AuthorizeSessionKeyResult struct {
+	Success bool `json:"success"`
+	// ExpiresAtTick is the tick from which the key can no longer sign transactions, or zero if it only expires at
+	// ExpiresAt.
+	ExpiresAtTick uint64 `json:"expiresAtTick,omitempty"`
+}
+
+// RevokeSessionKey removes a session key of the persona that sent the transaction before it expires.
+type RevokeSessionKey struct {
+	Address string `json:"address"`
+}
+
+type RevokeSessionKeyResult struct {
+	Success bool `json:"success"`
+}
@@ -0,0 +1,15 @@
+package msg
+
+const TransferPersonaMessageName = "transfer-persona"
+
+// TransferPersona hands control of the persona that sent the transaction to a new signer address, such as the
+// address of the persona's new owner. The old signer address can no longer sign for the persona once the transfer is
+// processed. The addresses that were authorized by the old owner are removed, unless KeepAuthorizedAddresses is set.
+type TransferPersona struct {
+	NewSignerAddress        string `json:"newSignerAddress"`
+	KeepAuthorizedAddresses bool   `json:"keepAuthorizedAddresses"`
+}
+
+type TransferPersonaResult struct {
+	Success bool `json:"success"`
+}
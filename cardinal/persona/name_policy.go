@@ -0,0 +1,162 @@
+package persona
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+)
+
+// ScreeningTimeout bounds how long AsyncNamePolicy waits for its screening service to decide on a persona tag.
+const ScreeningTimeout = 10 * time.Second
+
+// NamePolicy decides which persona tags may be registered. Check is called in the tick that registers a persona, so
+// it must not block: a policy that can't decide right away returns ErrNamePending, and the tag is held for its signer
+// until the policy decides, or until NameApprovalTimeoutTicks ticks have passed.
+type NamePolicy interface {
+	Check(personaTag string) error
+}
+
+var _ NamePolicy = RegexNamePolicy{}
+
+// RegexNamePolicy allows the persona tags whose length is within bounds and that match a pattern.
+type RegexNamePolicy struct {
+	Pattern   *regexp.Regexp
+	MinLength int
+	MaxLength int
+}
+
+// DefaultNamePolicy returns the policy that allows the persona tags that IsValidPersonaTag reports as valid.
+func DefaultNamePolicy() RegexNamePolicy {
+	return RegexNamePolicy{
+		Pattern:   personaTagRegexp,
+		MinLength: MinimumPersonaTagLength,
+		MaxLength: MaximumPersonaTagLength,
+	}
+}
+
+func (p RegexNamePolicy) Check(personaTag string) error {
+	if length := len(personaTag); length < p.MinLength || length > p.MaxLength || !p.Pattern.MatchString(personaTag) {
+		return eris.Wrapf(ErrNameRejected,
+			"persona tag %q invalid: must be between %d-%d characters & match %s",
+			personaTag, p.MinLength, p.MaxLength, p.Pattern)
+	}
+	return nil
+}
+
+// ScreenFunc asks an external service, such as a profanity or trademark screening service, whether a persona tag may
+// be registered.
+type ScreenFunc func(ctx context.Context, personaTag string) (approved bool, err error)
+
+// AsyncNamePolicy screens persona tags with an external service without blocking ticks. The first check of a tag
+// starts its screening in the background and returns ErrNamePending, and later checks return the service's decision
+// once it is known. Tags the service fails to screen are screened again on their next check.
+//
+// The service's decisions are not part of the world's state, so a world that is recovered from its transactions may
+// register pending persona tags in different ticks than it did originally.
+type AsyncNamePolicy struct {
+	base   NamePolicy
+	screen ScreenFunc
+
+	mu        sync.Mutex
+	decisions map[string]nameDecision
+}
+
+type nameDecision int
+
+const (
+	namePending nameDecision = iota
+	nameApproved
+	nameRejected
+)
+
+var _ NamePolicy = (*AsyncNamePolicy)(nil)
+
+// NewAsyncNamePolicy returns a policy that screens the persona tags that are allowed by the base policy with the
+// screen function.
+func NewAsyncNamePolicy(base NamePolicy, screen ScreenFunc) *AsyncNamePolicy {
+	return &AsyncNamePolicy{
+		base:      base,
+		screen:    screen,
+		decisions: map[string]nameDecision{},
+	}
+}
+
+func (p *AsyncNamePolicy) Check(personaTag string) error {
+	if err := p.base.Check(personaTag); err != nil {
+		return err
+	}
+	// Persona tags are unique regardless of their case, so they are screened regardless of their case too.
+	key := strings.ToLower(personaTag)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	decision, ok := p.decisions[key]
+	if !ok {
+		p.decisions[key] = namePending
+		go p.run(key, personaTag)
+	}
+	switch decision {
+	case nameApproved:
+		return nil
+	case nameRejected:
+		return eris.Wrapf(ErrNameRejected, "persona tag %q was rejected by the screening service", personaTag)
+	default:
+		return eris.Wrapf(ErrNamePending, "persona tag %q", personaTag)
+	}
+}
+
+func (p *AsyncNamePolicy) run(key, personaTag string) {
+	ctx, cancel := context.WithTimeout(context.Background(), ScreeningTimeout)
+	defer cancel()
+	approved, err := p.screen(ctx, personaTag)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case err != nil:
+		log.Warn().Err(err).Str("persona_tag", personaTag).Msg("failed to screen persona tag")
+		delete(p.decisions, key)
+	case approved:
+		p.decisions[key] = nameApproved
+	default:
+		p.decisions[key] = nameRejected
+	}
+}
+
+// HTTPScreen returns a ScreenFunc that POSTs {"personaTag": "<tag>"} to the URL, and expects a 2xx response with
+// {"approved": <bool>} as its body.
+func HTTPScreen(url string) ScreenFunc {
+	return func(ctx context.Context, personaTag string) (bool, error) {
+		body, err := json.Marshal(map[string]string{"personaTag": personaTag})
+		if err != nil {
+			return false, eris.Wrap(err, "")
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return false, eris.Wrap(err, "")
+		}
+		req.Header.Set("Content-Type", "application/json")
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return false, eris.Wrap(err, "")
+		}
+		defer res.Body.Close()
+		if res.StatusCode < 200 || res.StatusCode >= 300 {
+			return false, eris.Errorf("screening service responded with status %d", res.StatusCode)
+		}
+		var decision struct {
+			Approved bool `json:"approved"`
+		}
+		if err := json.NewDecoder(res.Body).Decode(&decision); err != nil {
+			return false, eris.Wrap(err, "invalid screening service response")
+		}
+		return decision.Approved, nil
+	}
+}
@@ -0,0 +1,57 @@
+package persona_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/persona"
+)
+
+func TestDefaultNamePolicyMatchesIsValidPersonaTag(t *testing.T) {
+	policy := persona.DefaultNamePolicy()
+	for _, tag := range []string{"abc123", "ABC_123", "abc 123", "a", "snow☃man"} {
+		assert.Equal(t, policy.Check(tag) == nil, persona.IsValidPersonaTag(tag), tag)
+	}
+	assert.ErrorIs(t, policy.Check("a"), persona.ErrNameRejected)
+}
+
+func TestAsyncNamePolicyScreensTagsInTheBackground(t *testing.T) {
+	release := make(chan struct{})
+	screening := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		<-release
+		var req struct {
+			PersonaTag string `json:"personaTag"`
+		}
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.NilError(t, json.NewEncoder(rw).Encode(map[string]bool{"approved": req.PersonaTag != "RudeMage"}))
+	}))
+	t.Cleanup(screening.Close)
+	policy := persona.NewAsyncNamePolicy(persona.DefaultNamePolicy(), persona.HTTPScreen(screening.URL))
+
+	// Tags the base policy rejects are never screened.
+	assert.ErrorIs(t, policy.Check("a"), persona.ErrNameRejected)
+
+	assert.ErrorIs(t, policy.Check("CoolMage"), persona.ErrNamePending)
+	assert.ErrorIs(t, policy.Check("RudeMage"), persona.ErrNamePending)
+	close(release)
+
+	decided := func(tag string) error {
+		for range 100 {
+			if err := policy.Check(tag); !errors.Is(err, persona.ErrNamePending) {
+				return err
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("persona tag %q was not screened", tag)
+		return nil
+	}
+	assert.NilError(t, decided("CoolMage"))
+	assert.ErrorIs(t, decided("RudeMage"), persona.ErrNameRejected)
+	// Decisions don't depend on the case of the tag.
+	assert.NilError(t, policy.Check("coolmage"))
+}
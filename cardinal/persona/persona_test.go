@@ -142,6 +142,32 @@ func TestCreatePersonaFailsIfTagIsInvalid(t *testing.T) {
 	assert.Equal(t, count, 0) // Assert that no signer components were found
 }
 
+func TestReservedAndInvalidPersonaTagsAreRejectedWithAReason(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithReservedPersonaTags("Admin"))
+	world := tf.World
+	tf.StartWorld()
+
+	createMsg, ok := world.GetMessageByFullName("persona." + msg.CreatePersonaMessageName)
+	assert.True(t, ok)
+	tf.AddTransaction(createMsg.ID(), msg.CreatePersona{PersonaTag: "aDMIN", SignerAddress: "123_456"})
+	tf.AddTransaction(createMsg.ID(), msg.CreatePersona{PersonaTag: "no spaces", SignerAddress: "123_456"})
+	tf.DoTick()
+	receipts, err := world.GetTransactionReceiptsForTick(world.CurrentTick() - 1)
+	assert.NilError(t, err)
+	assert.Len(t, receipts, 2)
+	wantCodes := []string{msg.CodeTagReserved, msg.CodeTagRejected}
+	for i, r := range receipts {
+		result, ok := r.Result.(msg.CreatePersonaResult)
+		assert.True(t, ok)
+		assert.False(t, result.Success)
+		assert.Check(t, result.Error != "")
+		assert.Equal(t, result.Code, wantCodes[i])
+		assert.Len(t, r.Errs, 1)
+		assert.Equal(t, world.ErrorCodes().Detail(r.Errs[0]).Code, wantCodes[i])
+	}
+	assert.Len(t, getSigners(t, world), 0)
+}
+
 func TestSamePersonaWithDifferentCaseCannotBeClaimed(t *testing.T) {
 	// Verify that the cardinal.CreatePersona is automatically cardinal.Created and registered with a engine.
 	tf := testutils.NewTestFixture(t, nil)
@@ -227,6 +253,344 @@ func TestAuthorizeAddressFailsOnInvalidAddress(t *testing.T) {
 	assert.Equal(t, count, 1)
 }
 
+func TestCanRevokeAuthorizedAddress(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	tf.StartWorld()
+
+	personaTag := "CoolMage"
+	tf.CreatePersona(personaTag, "123_456")
+
+	keptAddr := "0xd5e099c71b797516c10ed0f0d895f429c2781142"
+	revokedAddr := "0x5b38da6a701c568545dcfcb03fcb875f56beddc4"
+	authMsg, ok := world.GetMessageByFullName("game.authorize-persona-address")
+	assert.True(t, ok)
+	for _, addr := range []string{keptAddr, revokedAddr} {
+		tf.AddTransaction(authMsg.ID(), msg.AuthorizePersonaAddress{Address: addr},
+			testutils.UniqueSignatureWithName(personaTag))
+	}
+	tf.DoTick()
+
+	revokeMsg, ok := world.GetMessageByFullName("game.revoke-persona-address")
+	assert.True(t, ok)
+	revokeHash := tf.AddTransaction(revokeMsg.ID(), msg.RevokePersonaAddress{Address: strings.ToUpper(revokedAddr)},
+		testutils.UniqueSignatureWithName(personaTag))
+	tf.DoTick()
+
+	receipt, errs, ok := cardinal.NewReadOnlyWorldContext(world).GetTransactionReceipt(revokeHash)
+	assert.True(t, ok)
+	assert.Len(t, errs, 0)
+	assert.Equal(t, receipt.(msg.RevokePersonaAddressResult).Success, true)
+	signers := getSigners(t, world)
+	assert.Equal(t, len(signers), 1)
+	assert.DeepEqual(t, signers[0].AuthorizedAddresses, []string{keptAddr})
+
+	// Revoking an address that isn't authorized fails.
+	revokeHash = tf.AddTransaction(revokeMsg.ID(), msg.RevokePersonaAddress{Address: revokedAddr},
+		testutils.UniqueSignatureWithName(personaTag))
+	tf.DoTick()
+	receipt, errs, ok = cardinal.NewReadOnlyWorldContext(world).GetTransactionReceipt(revokeHash)
+	assert.True(t, ok)
+	assert.Len(t, errs, 1)
+	assert.Check(t, receipt == nil)
+}
+
+func TestCanRotateSignerWithGracePeriod(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	tf.StartWorld()
+
+	personaTag := "CoolMage"
+	oldSigner := "old_signer"
+	newSigner := "0x1111111111111111111111111111111111111111"
+	tf.CreatePersona(personaTag, oldSigner)
+
+	rotateMsg, ok := world.GetMessageByFullName("persona." + msg.RotatePersonaSignerMessageName)
+	assert.True(t, ok)
+	tf.AddTransaction(
+		rotateMsg.ID(),
+		msg.RotatePersonaSigner{
+			NewSignerAddress: newSigner,
+			GracePeriodTicks: 2,
+		},
+		&sign.Transaction{PersonaTag: personaTag},
+	)
+	tf.DoTick()
+
+	signers := getSigners(t, world)
+	assert.Len(t, signers, 1)
+	assert.Equal(t, signers[0].SignerAddress, newSigner)
+	assert.Equal(t, signers[0].PreviousSignerAddress, oldSigner)
+
+	// Both signers are valid during the grace period.
+	for i := 0; i < 2; i++ {
+		addrs, err := world.GetValidSignersForPersonaTag(personaTag)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, addrs, []string{newSigner, oldSigner})
+		tf.DoTick()
+	}
+
+	// Only the new signer is valid once the grace period has elapsed.
+	addrs, err := world.GetValidSignersForPersonaTag(personaTag)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, addrs, []string{newSigner})
+
+	addr, err := world.GetSignerForPersonaTag(personaTag, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, addr, newSigner)
+}
+
+func TestTransferPersonaEndsGracePeriodAndClearsAuthorizedAddresses(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	tf.StartWorld()
+
+	personaTag := "CoolMage"
+	tf.CreatePersona(personaTag, "old_signer")
+
+	authMsg, ok := world.GetMessageByFullName("game.authorize-persona-address")
+	assert.True(t, ok)
+	tf.AddTransaction(authMsg.ID(), msg.AuthorizePersonaAddress{Address: "0xd5e099c71b797516c10ed0f0d895f429c2781142"},
+		testutils.UniqueSignatureWithName(personaTag))
+	rotateMsg, ok := world.GetMessageByFullName("persona." + msg.RotatePersonaSignerMessageName)
+	assert.True(t, ok)
+	tf.AddTransaction(rotateMsg.ID(),
+		msg.RotatePersonaSigner{NewSignerAddress: "0x2222222222222222222222222222222222222222", GracePeriodTicks: 10},
+		testutils.UniqueSignatureWithName(personaTag))
+	tf.DoTick()
+
+	transferMsg, ok := world.GetMessageByFullName("persona." + msg.TransferPersonaMessageName)
+	assert.True(t, ok)
+	newOwner := "0x3333333333333333333333333333333333333333"
+	txHash := tf.AddTransaction(transferMsg.ID(), msg.TransferPersona{NewSignerAddress: newOwner},
+		testutils.UniqueSignatureWithName(personaTag))
+	tf.DoTick()
+
+	receipt, errs, ok := cardinal.NewReadOnlyWorldContext(world).GetTransactionReceipt(txHash)
+	assert.True(t, ok)
+	assert.Len(t, errs, 0)
+	assert.Equal(t, receipt.(msg.TransferPersonaResult).Success, true)
+
+	addrs, err := world.GetValidSignersForPersonaTag(personaTag)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, addrs, []string{newOwner})
+	signers := getSigners(t, world)
+	assert.Len(t, signers, 1)
+	assert.Len(t, signers[0].AuthorizedAddresses, 0)
+}
+
+func TestChangeSignerKeepsAuthorizedAddresses(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	tf.StartWorld()
+
+	personaTag := "CoolMage"
+	tf.CreatePersona(personaTag, "old_key")
+
+	wantAddr := "0xd5e099c71b797516c10ed0f0d895f429c2781142"
+	authMsg, ok := world.GetMessageByFullName("game.authorize-persona-address")
+	assert.True(t, ok)
+	tf.AddTransaction(authMsg.ID(), msg.AuthorizePersonaAddress{Address: wantAddr},
+		testutils.UniqueSignatureWithName(personaTag))
+	tf.DoTick()
+
+	changeMsg, ok := world.GetMessageByFullName("persona." + msg.ChangeSignerMessageName)
+	assert.True(t, ok)
+	newKey := "0x4444444444444444444444444444444444444abc"
+	txHash := tf.AddTransaction(changeMsg.ID(), msg.ChangeSigner{NewSignerAddress: newKey},
+		testutils.UniqueSignatureWithName(personaTag))
+	// Changing to the current signer fails, however the address is cased.
+	sameHash := tf.AddTransaction(changeMsg.ID(), msg.ChangeSigner{NewSignerAddress: "0x" + strings.ToUpper(newKey[2:])},
+		testutils.UniqueSignatureWithName(personaTag))
+	tf.DoTick()
+
+	_, errs, ok := cardinal.NewReadOnlyWorldContext(world).GetTransactionReceipt(txHash)
+	assert.True(t, ok)
+	assert.Len(t, errs, 0)
+	_, errs, ok = cardinal.NewReadOnlyWorldContext(world).GetTransactionReceipt(sameHash)
+	assert.True(t, ok)
+	assert.Len(t, errs, 1)
+
+	addrs, err := world.GetValidSignersForPersonaTag(personaTag)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, addrs, []string{newKey})
+	signers := getSigners(t, world)
+	assert.DeepEqual(t, signers[0].AuthorizedAddresses, []string{wantAddr})
+}
+
+func TestSignerChangesRejectMalformedAddresses(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	tf.StartWorld()
+
+	personaTag := "CoolMage"
+	tf.CreatePersona(personaTag, "old_key")
+
+	transferMsg, ok := world.GetMessageByFullName("persona." + msg.TransferPersonaMessageName)
+	assert.True(t, ok)
+	changeMsg, ok := world.GetMessageByFullName("persona." + msg.ChangeSignerMessageName)
+	assert.True(t, ok)
+	hashes := []types.TxHash{
+		tf.AddTransaction(transferMsg.ID(), msg.TransferPersona{NewSignerAddress: "new_owner"},
+			testutils.UniqueSignatureWithName(personaTag)),
+		tf.AddTransaction(changeMsg.ID(), msg.ChangeSigner{NewSignerAddress: "0x1234"},
+			testutils.UniqueSignatureWithName(personaTag)),
+	}
+	tf.DoTick()
+
+	for _, hash := range hashes {
+		_, errs, ok := cardinal.NewReadOnlyWorldContext(world).GetTransactionReceipt(hash)
+		assert.True(t, ok)
+		assert.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], "is invalid")
+	}
+	addrs, err := world.GetValidSignersForPersonaTag(personaTag)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, addrs, []string{"old_key"})
+}
+
+func TestRotateSignerFailsOnExcessiveGracePeriod(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	tf.StartWorld()
+
+	personaTag := "CoolMage"
+	signer := "old_signer"
+	tf.CreatePersona(personaTag, signer)
+
+	rotateMsg, ok := world.GetMessageByFullName("persona." + msg.RotatePersonaSignerMessageName)
+	assert.True(t, ok)
+	txHash := tf.AddTransaction(
+		rotateMsg.ID(),
+		msg.RotatePersonaSigner{
+			NewSignerAddress: "0x1111111111111111111111111111111111111111",
+			GracePeriodTicks: persona.MaximumSignerRotationGracePeriodTicks + 1,
+		},
+		&sign.Transaction{PersonaTag: personaTag},
+	)
+	tf.DoTick()
+
+	receipts, err := world.GetTransactionReceiptsForTick(world.CurrentTick() - 1)
+	assert.NilError(t, err)
+	assert.Len(t, receipts, 1)
+	assert.Equal(t, receipts[0].TxHash, txHash)
+	assert.Check(t, len(receipts[0].Errs) > 0)
+
+	signers := getSigners(t, world)
+	assert.Equal(t, signers[0].SignerAddress, signer)
+	assert.Equal(t, signers[0].PreviousSignerAddress, "")
+}
+
+func TestReservedPersonaTagCanOnlyBeClaimedByItsSigner(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	tf.StartWorld()
+
+	personaTag := "CoolMage"
+	holder := "holder_signer"
+	other := "other_signer"
+	reserveMsg, ok := world.GetMessageByFullName("persona." + msg.ReservePersonaMessageName)
+	assert.True(t, ok)
+	confirmMsg, ok := world.GetMessageByFullName("persona." + msg.ConfirmPersonaMessageName)
+	assert.True(t, ok)
+	createMsg, ok := world.GetMessageByFullName("persona." + msg.CreatePersonaMessageName)
+	assert.True(t, ok)
+
+	tf.AddTransaction(reserveMsg.ID(), msg.ReservePersona{PersonaTag: personaTag, SignerAddress: holder, HoldTicks: 5})
+	tf.DoTick()
+	receipts, err := world.GetTransactionReceiptsForTick(world.CurrentTick() - 1)
+	assert.NilError(t, err)
+	assert.Len(t, receipts, 1)
+	assert.Len(t, receipts[0].Errs, 0)
+	assert.Equal(t, receipts[0].Result, msg.ReservePersonaResult{Success: true, ExpiresAtTick: world.CurrentTick() + 5})
+
+	// The held tag can't be created or reserved by another signer, no matter its case.
+	tf.AddTransaction(createMsg.ID(), msg.CreatePersona{PersonaTag: personaTag, SignerAddress: other})
+	tf.AddTransaction(reserveMsg.ID(), msg.ReservePersona{PersonaTag: strings.ToLower(personaTag), SignerAddress: other})
+	tf.AddTransaction(confirmMsg.ID(), msg.ConfirmPersona{PersonaTag: personaTag, SignerAddress: other})
+	tf.DoTick()
+	receipts, err = world.GetTransactionReceiptsForTick(world.CurrentTick() - 1)
+	assert.NilError(t, err)
+	assert.Len(t, receipts, 3)
+	for _, r := range receipts {
+		assert.Check(t, len(r.Errs) > 0)
+	}
+	assert.Len(t, getSigners(t, world), 0)
+
+	tf.AddTransaction(confirmMsg.ID(), msg.ConfirmPersona{PersonaTag: personaTag, SignerAddress: holder})
+	tf.DoTick()
+	signers := getSigners(t, world)
+	assert.Len(t, signers, 1)
+	assert.Equal(t, signers[0].PersonaTag, personaTag)
+	assert.Equal(t, signers[0].SignerAddress, holder)
+
+	// The hold is used up by the confirmation.
+	tf.AddTransaction(confirmMsg.ID(), msg.ConfirmPersona{PersonaTag: personaTag, SignerAddress: holder})
+	tf.DoTick()
+	receipts, err = world.GetTransactionReceiptsForTick(world.CurrentTick() - 1)
+	assert.NilError(t, err)
+	assert.Len(t, receipts, 1)
+	assert.Check(t, len(receipts[0].Errs) > 0)
+}
+
+func TestPersonaReservationExpires(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	tf.StartWorld()
+
+	personaTag := "CoolMage"
+	holder := "holder_signer"
+	other := "other_signer"
+	reserveMsg, ok := world.GetMessageByFullName("persona." + msg.ReservePersonaMessageName)
+	assert.True(t, ok)
+	confirmMsg, ok := world.GetMessageByFullName("persona." + msg.ConfirmPersonaMessageName)
+	assert.True(t, ok)
+
+	tf.AddTransaction(reserveMsg.ID(), msg.ReservePersona{PersonaTag: personaTag, SignerAddress: holder, HoldTicks: 2})
+	tf.DoTick()
+
+	// The tag is held for 2 ticks.
+	for i := 0; i < 2; i++ {
+		tf.AddTransaction(reserveMsg.ID(), msg.ReservePersona{PersonaTag: personaTag, SignerAddress: other})
+		tf.DoTick()
+		receipts, err := world.GetTransactionReceiptsForTick(world.CurrentTick() - 1)
+		assert.NilError(t, err)
+		assert.Len(t, receipts, 1)
+		assert.Check(t, len(receipts[0].Errs) > 0)
+	}
+
+	// Once the hold has expired, the tag can be reserved by another signer, and the original signer can't confirm it.
+	tf.AddTransaction(reserveMsg.ID(), msg.ReservePersona{PersonaTag: personaTag, SignerAddress: other})
+	tf.AddTransaction(confirmMsg.ID(), msg.ConfirmPersona{PersonaTag: personaTag, SignerAddress: holder})
+	tf.DoTick()
+	receipts, err := world.GetTransactionReceiptsForTick(world.CurrentTick() - 1)
+	assert.NilError(t, err)
+	assert.Len(t, receipts, 2)
+	assert.Len(t, receipts[0].Errs, 0)
+	assert.Check(t, len(receipts[1].Errs) > 0)
+	assert.Len(t, getSigners(t, world), 0)
+}
+
+func TestReservePersonaFailsOnExcessiveHold(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	tf.StartWorld()
+
+	reserveMsg, ok := world.GetMessageByFullName("persona." + msg.ReservePersonaMessageName)
+	assert.True(t, ok)
+	tf.AddTransaction(reserveMsg.ID(), msg.ReservePersona{
+		PersonaTag:    "CoolMage",
+		SignerAddress: "holder_signer",
+		HoldTicks:     persona.MaximumReservationHoldTicks + 1,
+	})
+	tf.DoTick()
+
+	receipts, err := world.GetTransactionReceiptsForTick(world.CurrentTick() - 1)
+	assert.NilError(t, err)
+	assert.Len(t, receipts, 1)
+	assert.Check(t, len(receipts[0].Errs) > 0)
+}
+
 func TestQuerySigner(t *testing.T) {
 	tf := testutils.NewTestFixture(t, nil)
 	world := tf.World
@@ -283,6 +647,131 @@ func TestQuerySignerUnknown(t *testing.T) {
 	assert.Equal(t, response.Status, personaQuery.PersonaStatusUnknown)
 }
 
+func TestMergedPersonaResolvesToPrimary(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	tf.StartWorld()
+
+	primaryTag, primarySigner := "CoolMage", "primary_signer"
+	aliasTag, aliasSigner := "CoolMage2", "alias_signer"
+	tf.CreatePersona(primaryTag, primarySigner)
+	tf.CreatePersona(aliasTag, aliasSigner)
+
+	mergeMsg, ok := world.GetMessageByFullName("persona." + msg.MergePersonaMessageName)
+	assert.True(t, ok)
+	// Only admin signers can merge personas.
+	tf.AddTransaction(mergeMsg.ID(), msg.MergePersona{AliasPersonaTag: aliasTag, PrimaryPersonaTag: primaryTag},
+		&sign.Transaction{PersonaTag: aliasTag})
+	tf.DoTick()
+	primary, err := world.ResolvePersonaTag(aliasTag)
+	assert.NilError(t, err)
+	assert.Equal(t, primary, aliasTag)
+
+	tf.AddTransaction(mergeMsg.ID(), msg.MergePersona{AliasPersonaTag: aliasTag, PrimaryPersonaTag: "coolmage"},
+		&sign.Transaction{PersonaTag: sign.SystemPersonaTag})
+	tf.DoTick()
+
+	primary, err = world.ResolvePersonaTag(aliasTag)
+	assert.NilError(t, err)
+	assert.Equal(t, primary, primaryTag)
+	addr, err := world.GetSignerForPersonaTag(aliasTag, 0)
+	assert.NilError(t, err)
+	assert.Equal(t, addr, primarySigner)
+	addrs, err := world.GetValidSignersForPersonaTag(aliasTag)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, addrs, []string{primarySigner})
+
+	query, err := world.GetQueryByName("signer")
+	assert.NilError(t, err)
+	res, err := query.HandleQuery(cardinal.NewReadOnlyWorldContext(world), &personaQuery.PersonaSignerQueryRequest{
+		PersonaTag: aliasTag,
+	})
+	assert.NilError(t, err)
+	response, ok := res.(*personaQuery.PersonaSignerQueryResponse)
+	assert.True(t, ok)
+	assert.Equal(t, response.Status, personaQuery.PersonaStatusAssigned)
+	assert.Equal(t, response.SignerAddress, primarySigner)
+	assert.Equal(t, response.PrimaryPersonaTag, primaryTag)
+
+	// Transactions of the alias are processed as transactions of the primary.
+	rotateMsg, ok := world.GetMessageByFullName("persona." + msg.RotatePersonaSignerMessageName)
+	assert.True(t, ok)
+	tf.AddTransaction(rotateMsg.ID(),
+		msg.RotatePersonaSigner{NewSignerAddress: "0x1111111111111111111111111111111111111111"},
+		&sign.Transaction{PersonaTag: aliasTag})
+	tf.DoTick()
+	for _, signer := range getSigners(t, world) {
+		if signer.PersonaTag == primaryTag {
+			assert.Equal(t, signer.SignerAddress, "0x1111111111111111111111111111111111111111")
+		} else {
+			assert.Equal(t, signer.SignerAddress, aliasSigner)
+			assert.Equal(t, signer.AliasOf, primaryTag)
+		}
+	}
+
+	// Personas can't be merged into aliases.
+	tf.CreatePersona("Newcomer", "newcomer_signer")
+	tf.AddTransaction(mergeMsg.ID(), msg.MergePersona{AliasPersonaTag: "Newcomer", PrimaryPersonaTag: aliasTag},
+		&sign.Transaction{PersonaTag: sign.SystemPersonaTag})
+	tf.DoTick()
+	receipts, err := world.GetTransactionReceiptsForTick(world.CurrentTick() - 1)
+	assert.NilError(t, err)
+	assert.Len(t, receipts, 1)
+	assert.Check(t, len(receipts[0].Errs) > 0)
+}
+
+// fakeNamePolicy rejects the persona tags that map to an error, and approves those that map to nil. Other tags are
+// pending.
+type fakeNamePolicy map[string]error
+
+func (p fakeNamePolicy) Check(personaTag string) error {
+	err, ok := p[personaTag]
+	if !ok {
+		return persona.ErrNamePending
+	}
+	return err
+}
+
+func TestPendingPersonaTagsAreSettledByTheNamePolicy(t *testing.T) {
+	policy := fakeNamePolicy{}
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithPersonaNamePolicy(policy))
+	world := tf.World
+	tf.StartWorld()
+
+	createMsg, ok := world.GetMessageByFullName("persona." + msg.CreatePersonaMessageName)
+	assert.True(t, ok)
+	tf.AddTransaction(createMsg.ID(), msg.CreatePersona{PersonaTag: "CoolMage", SignerAddress: "holder"})
+	tf.AddTransaction(createMsg.ID(), msg.CreatePersona{PersonaTag: "RudeMage", SignerAddress: "holder"})
+	tf.DoTick()
+	receipts, err := world.GetTransactionReceiptsForTick(world.CurrentTick() - 1)
+	assert.NilError(t, err)
+	assert.Len(t, receipts, 2)
+	for _, r := range receipts {
+		assert.Len(t, r.Errs, 0)
+		assert.Equal(t, r.Result, msg.CreatePersonaResult{Pending: true})
+	}
+	_, err = world.GetSignerForPersonaTag("CoolMage", 0)
+	assert.ErrorIs(t, err, persona.ErrPersonaTagPending)
+
+	// Pending tags are held for their signer.
+	tf.AddTransaction(createMsg.ID(), msg.CreatePersona{PersonaTag: "coolmage", SignerAddress: "other"})
+	tf.DoTick()
+	receipts, err = world.GetTransactionReceiptsForTick(world.CurrentTick() - 1)
+	assert.NilError(t, err)
+	assert.Len(t, receipts, 1)
+	assert.ErrorIs(t, receipts[0].Errs[0], persona.ErrPersonaTagReserved)
+
+	policy["CoolMage"] = nil
+	policy["RudeMage"] = persona.ErrNameRejected
+	tf.DoTick()
+	addr, err := world.GetSignerForPersonaTag("CoolMage", 0)
+	assert.NilError(t, err)
+	assert.Equal(t, addr, "holder")
+	_, err = world.GetSignerForPersonaTag("RudeMage", 0)
+	assert.ErrorIs(t, err, persona.ErrPersonaTagHasNoSigner)
+	assert.Len(t, getSigners(t, world), 1)
+}
+
 func getSigners(t *testing.T, world *cardinal.World) []*component.SignerComponent {
 	wCtx := cardinal.NewWorldContext(world)
 	var signers = make([]*component.SignerComponent, 0)
@@ -11,6 +11,7 @@ const (
 	PersonaStatusUnknown   = "unknown"
 	PersonaStatusAvailable = "available"
 	PersonaStatusAssigned  = "assigned"
+	PersonaStatusPending   = "pending"
 )
 
 // PersonaSignerQueryRequest is the desired request body for the query-persona-signer endpoint.
@@ -23,9 +24,14 @@ type PersonaSignerQueryRequest struct {
 // "assigned": The requested persona tag has been assigned the returned SignerAddress
 // "unknown": The game tick has not advanced far enough to know what the signer address. SignerAddress will be empty.
 // "available": The game tick has advanced, and no signer address has been assigned. SignerAddress will be empty.
+// "pending": The persona tag is held for a signer until the world's name policy approves it. SignerAddress will be
+// empty.
+// If the persona tag is an alias, PrimaryPersonaTag is the persona tag it resolves to, and SignerAddress is the signer
+// address of that persona.
 type PersonaSignerQueryResponse struct {
-	Status        string `json:"status"`
-	SignerAddress string `json:"signerAddress"`
+	Status            string `json:"status"`
+	SignerAddress     string `json:"signerAddress"`
+	PrimaryPersonaTag string `json:"primaryPersonaTag,omitempty"`
 }
 
 func PersonaSignerQuery(wCtx engine.Context, req *PersonaSignerQueryRequest) (*PersonaSignerQueryResponse, error) {
@@ -38,6 +44,8 @@ func PersonaSignerQuery(wCtx engine.Context, req *PersonaSignerQueryRequest) (*P
 			status = PersonaStatusAvailable
 		} else if errors.Is(err, persona.ErrCreatePersonaTxsNotProcessed) {
 			status = PersonaStatusUnknown
+		} else if errors.Is(err, persona.ErrPersonaTagPending) {
+			status = PersonaStatusPending
 		} else {
 			return nil, err
 		}
@@ -49,5 +57,14 @@ func PersonaSignerQuery(wCtx engine.Context, req *PersonaSignerQueryRequest) (*P
 		Status:        status,
 		SignerAddress: addr,
 	}
+	if status == PersonaStatusAssigned {
+		primary, err := wCtx.ResolvePersonaTag(req.PersonaTag)
+		if err != nil {
+			return nil, err
+		}
+		if primary != req.PersonaTag {
+			res.PrimaryPersonaTag = primary
+		}
+	}
 	return &res, nil
 }
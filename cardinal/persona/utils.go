@@ -7,6 +7,16 @@ import (
 const (
 	MinimumPersonaTagLength = 3
 	MaximumPersonaTagLength = 16
+	// MaximumSignerRotationGracePeriodTicks bounds how long a rotated-out signer address may continue to sign
+	// transactions for its persona.
+	MaximumSignerRotationGracePeriodTicks = 10_000
+	// DefaultReservationHoldTicks is how long a persona tag is held by a reservation that doesn't ask for a hold.
+	DefaultReservationHoldTicks = 30
+	// MaximumReservationHoldTicks bounds how long a persona tag can be held without being confirmed.
+	MaximumReservationHoldTicks = 600
+	// NameApprovalTimeoutTicks is how long a persona tag is held for its signer while the world's NamePolicy decides
+	// whether it may be registered.
+	NameApprovalTimeoutTicks = 600
 )
 
 var (
@@ -0,0 +1,281 @@
+package pgmirror
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// change is a row of a component table that is written or deleted.
+type change struct {
+	component string
+	entity    types.EntityID
+	// value is the component's value, or nil if the row is deleted.
+	value json.RawMessage
+}
+
+// batch is the changes of a tick, or a snapshot of the state at the end of a tick.
+type batch struct {
+	tick    uint64
+	changes []change
+	// snapshot reports whether the changes are every row of the mirror, so the tables are emptied first.
+	snapshot bool
+}
+
+// mirror writes the world's changes to the database. It is only used by the tick loop, so it needs no locking.
+type mirror struct {
+	*Module
+	// ready reports whether the tables were created, and the mirror's tick checked.
+	ready bool
+	// pending are the batches that weren't written yet, in the order of their ticks.
+	pending []batch
+	// needsSnapshot is set when the pending batches can't bring the mirror up to date.
+	needsSnapshot bool
+}
+
+func newMirror(m *Module) *mirror {
+	return &mirror{Module: m}
+}
+
+func (m *mirror) onTickEnd(results cardinal.TickResults) {
+	b, rollback, err := m.parseEvents(results)
+	if err != nil {
+		log.Warn().Err(err).Uint64("tick", results.Tick).Msg("pgmirror failed to read the structural events")
+		m.needsSnapshot = true
+	}
+	m.pending = append(m.pending, b)
+	if rollback || len(m.pending) > m.maxPending {
+		m.needsSnapshot = true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+	if err := m.sync(ctx, results.Tick); err != nil {
+		log.Warn().Err(err).Uint64("tick", results.Tick).Int("pendingTicks", len(m.pending)).
+			Msg("pgmirror failed to update the mirror; it is retried at the end of the next tick")
+	}
+}
+
+// sync writes the pending batches to the database.
+func (m *mirror) sync(ctx context.Context, tick uint64) error {
+	if m.needsSnapshot {
+		if err := m.takeSnapshot(tick); err != nil {
+			return err
+		}
+	}
+	if !m.ready {
+		mirrored, err := m.prepare(ctx)
+		if err != nil {
+			return err
+		}
+		m.ready = true
+		// Without the ticks between the mirror's and the first pending one, the mirror must be rebuilt.
+		if first := m.pending[0]; !first.snapshot && (!mirrored.Valid || uint64(mirrored.Int64)+1 != first.tick) {
+			if err := m.takeSnapshot(tick); err != nil {
+				return err
+			}
+		}
+	}
+	if err := m.write(ctx); err != nil {
+		return err
+	}
+	m.pending = nil
+	return nil
+}
+
+// prepare creates the mirror's tables, and returns the last tick that was written to the mirror.
+func (m *mirror) prepare(ctx context.Context) (sql.NullInt64, error) {
+	var mirrored sql.NullInt64
+	statements := []string{fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s (namespace TEXT PRIMARY KEY, tick BIGINT NOT NULL)", m.stateTable(),
+	)}
+	for _, name := range m.mirroredComponents() {
+		statements = append(statements, fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS %s (entity BIGINT PRIMARY KEY, value JSONB NOT NULL, tick BIGINT NOT NULL)",
+			m.table(name),
+		))
+	}
+	for _, statement := range statements {
+		if _, err := m.db.ExecContext(ctx, statement); err != nil {
+			return mirrored, eris.Wrap(err, "failed to create the mirror's tables")
+		}
+	}
+	err := m.db.QueryRowContext(ctx,
+		fmt.Sprintf("SELECT tick FROM %s WHERE namespace = $1", m.stateTable()), m.world.Namespace(),
+	).Scan(&mirrored)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return mirrored, eris.Wrap(err, "failed to read the mirror's tick")
+	}
+	return mirrored, nil
+}
+
+// write writes the pending batches in a single transaction.
+func (m *mirror) write(ctx context.Context) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return eris.Wrap(err, "failed to start a transaction")
+	}
+	defer tx.Rollback() //nolint:errcheck // It fails once the transaction is committed.
+
+	for _, b := range m.pending {
+		if b.snapshot {
+			for _, name := range m.mirroredComponents() {
+				if _, err := tx.ExecContext(ctx, "DELETE FROM "+m.table(name)); err != nil {
+					return eris.Wrapf(err, "failed to empty the table of %s", name)
+				}
+			}
+		}
+		for _, c := range b.changes {
+			if c.value == nil {
+				_, err = tx.ExecContext(ctx,
+					fmt.Sprintf("DELETE FROM %s WHERE entity = $1", m.table(c.component)), int64(c.entity),
+				)
+			} else {
+				_, err = tx.ExecContext(ctx, fmt.Sprintf(
+					"INSERT INTO %s (entity, value, tick) VALUES ($1, $2, $3) "+
+						"ON CONFLICT (entity) DO UPDATE SET value = EXCLUDED.value, tick = EXCLUDED.tick",
+					m.table(c.component),
+				), int64(c.entity), string(c.value), int64(b.tick))
+			}
+			if err != nil {
+				return eris.Wrapf(err, "failed to write %s of entity %d", c.component, c.entity)
+			}
+		}
+	}
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(
+		"INSERT INTO %s (namespace, tick) VALUES ($1, $2) ON CONFLICT (namespace) DO UPDATE SET tick = EXCLUDED.tick",
+		m.stateTable(),
+	), m.world.Namespace(), int64(m.pending[len(m.pending)-1].tick))
+	if err != nil {
+		return eris.Wrap(err, "failed to write the mirror's tick")
+	}
+	return eris.Wrap(tx.Commit(), "failed to commit the mirror's changes")
+}
+
+// takeSnapshot replaces the pending batches with a snapshot of the committed state, which is the state at the end of
+// the tick.
+func (m *mirror) takeSnapshot(tick uint64) error {
+	snapshot := batch{tick: tick, snapshot: true}
+	wCtx := cardinal.NewReadOnlyWorldContext(m.world)
+	var readErr error
+	err := cardinal.NewSearch().Entity(filter.All()).Each(wCtx, func(id types.EntityID) bool {
+		comps, err := wCtx.StoreReader().GetComponentTypesForEntity(id)
+		if err != nil {
+			readErr = err
+			return false
+		}
+		for _, c := range comps {
+			if !m.mirrors(c.Name()) {
+				continue
+			}
+			value, err := wCtx.StoreReader().GetComponentForEntityInRawJSON(c, id)
+			if err != nil {
+				readErr = err
+				return false
+			}
+			snapshot.changes = append(snapshot.changes, change{component: c.Name(), entity: id, value: value})
+		}
+		return true
+	})
+	if err == nil {
+		err = readErr
+	}
+	if err != nil {
+		return eris.Wrap(err, "failed to take a snapshot of the state")
+	}
+	m.pending = []batch{snapshot}
+	m.needsSnapshot = false
+	return nil
+}
+
+// structuralEvent is an event of cardinal.WithStructuralEvents.
+type structuralEvent struct {
+	Type       string          `json:"type"`
+	Entity     types.EntityID  `json:"entity"`
+	Components json.RawMessage `json:"components"`
+}
+
+// parseEvents returns the changes of the structural events of a tick, and whether the world was rolled back.
+func (m *mirror) parseEvents(results cardinal.TickResults) (batch, bool, error) {
+	b := batch{tick: results.Tick}
+	rollback := false
+	for _, bz := range results.Events {
+		var event structuralEvent
+		if err := json.Unmarshal(bz, &event); err != nil {
+			// Events don't have to be JSON objects, and those that aren't aren't structural events.
+			continue
+		}
+		switch event.Type {
+		case cardinal.EventRollback:
+			rollback = true
+		case cardinal.EventEntityCreated, cardinal.EventComponentsAdded, cardinal.EventComponentsChanged:
+			var values map[string]json.RawMessage
+			if err := json.Unmarshal(event.Components, &values); err != nil {
+				return b, rollback, eris.Wrapf(err, "invalid %s event", event.Type)
+			}
+			names := make([]string, 0, len(values))
+			for name := range values {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				if m.mirrors(name) {
+					b.changes = append(b.changes, change{component: name, entity: event.Entity, value: values[name]})
+				}
+			}
+		case cardinal.EventEntityRemoved, cardinal.EventComponentsRemoved:
+			var names []string
+			if err := json.Unmarshal(event.Components, &names); err != nil {
+				return b, rollback, eris.Wrapf(err, "invalid %s event", event.Type)
+			}
+			for _, name := range names {
+				if m.mirrors(name) {
+					b.changes = append(b.changes, change{component: name, entity: event.Entity})
+				}
+			}
+		}
+	}
+	return b, rollback, nil
+}
+
+func (m *mirror) mirrors(name string) bool {
+	if m.components == nil {
+		return true
+	}
+	return m.components[name]
+}
+
+func (m *mirror) mirroredComponents() []string {
+	var names []string
+	for _, c := range m.world.GetRegisteredComponents() {
+		if m.mirrors(c.Name()) {
+			names = append(names, c.Name())
+		}
+	}
+	return names
+}
+
+func (m *mirror) table(component string) string {
+	return quoteIdentifier(m.prefix + component)
+}
+
+func (m *mirror) stateTable() string {
+	return quoteIdentifier(m.prefix + "mirror")
+}
+
+// quoteIdentifier quotes a table name, so that component names that aren't valid identifiers, such as ones with
+// dashes, can be used.
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
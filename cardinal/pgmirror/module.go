@@ -0,0 +1,123 @@
+// Package pgmirror is a module that keeps a Postgres mirror of the world's components, so that analytics and LiveOps
+// can query the game with SQL instead of polling the world:
+//
+//	db, err := sql.Open("pgx", os.Getenv("MIRROR_DATABASE_URL"))
+//	...
+//	world, err := cardinal.NewWorld(cardinal.WithStructuralEvents())
+//	...
+//	err = world.UseModule(pgmirror.NewModule(db))
+//
+// The module works with any database/sql driver for Postgres, which the game imports. Every mirrored component has a
+// table, named after the component with a prefix, with a row for every entity that has the component:
+//
+//	CREATE TABLE cardinal_health (entity BIGINT PRIMARY KEY, value JSONB NOT NULL, tick BIGINT NOT NULL)
+//
+// The mirror is kept up to date with the world's structural events (see cardinal.WithStructuralEvents), so the world
+// must emit them for the mirrored components. The changes of a tick are written at the end of the tick, in a single
+// transaction that also records the tick in the cardinal_mirror table. If the database can't be reached, the changes
+// are kept and written with the next tick's; writing a tick again is harmless, so every change is written at least
+// once. When the mirror can't be brought up to date from the events, such as on the first start, after the world was
+// rolled back, or after the database was unreachable for too many ticks, it is rebuilt from a snapshot of the committed
+// state instead.
+package pgmirror
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+)
+
+const (
+	ModuleName    = "pgmirror"
+	ModuleVersion = "v1.0.0"
+
+	// DefaultTablePrefix is prepended to the names of the mirror's tables.
+	DefaultTablePrefix = "cardinal_"
+	// DefaultMaxPendingTicks is the number of ticks whose changes are kept while the database is unreachable, after
+	// which the mirror is rebuilt from a snapshot instead.
+	DefaultMaxPendingTicks = 1000
+	// DefaultTimeout is how long the module waits for the database at the end of a tick.
+	DefaultTimeout = 5 * time.Second
+)
+
+var _ cardinal.Module = &Module{}
+
+type Module struct {
+	cardinal.ModuleBase
+	db         *sql.DB
+	prefix     string
+	components map[string]bool
+	maxPending int
+	timeout    time.Duration
+
+	world  *cardinal.World
+	mirror *mirror
+}
+
+type Option func(*Module)
+
+// WithComponents only mirrors the named components. By default, every registered component is mirrored.
+func WithComponents(names ...string) Option {
+	return func(m *Module) {
+		m.components = make(map[string]bool, len(names))
+		for _, name := range names {
+			m.components[name] = true
+		}
+	}
+}
+
+// WithTablePrefix sets the prefix of the mirror's table names, so that several worlds can be mirrored to the same
+// database.
+func WithTablePrefix(prefix string) Option {
+	return func(m *Module) {
+		m.prefix = prefix
+	}
+}
+
+// WithMaxPendingTicks sets the number of ticks whose changes are kept while the database is unreachable.
+func WithMaxPendingTicks(ticks int) Option {
+	return func(m *Module) {
+		m.maxPending = ticks
+	}
+}
+
+// WithTimeout sets how long the module waits for the database at the end of a tick. The tick loop is blocked while it
+// waits.
+func WithTimeout(timeout time.Duration) Option {
+	return func(m *Module) {
+		m.timeout = timeout
+	}
+}
+
+// NewModule returns a module that mirrors the world's components to the Postgres database.
+func NewModule(db *sql.DB, opts ...Option) *Module {
+	m := &Module{
+		db:         db,
+		prefix:     DefaultTablePrefix,
+		maxPending: DefaultMaxPendingTicks,
+		timeout:    DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func (*Module) Name() string    { return ModuleName }
+func (*Module) Version() string { return ModuleVersion }
+
+// Init adds the hook that writes the changes of every tick to the mirror.
+func (m *Module) Init(w *cardinal.World) error {
+	if m.db == nil {
+		return eris.New("pgmirror needs a database")
+	}
+	if m.maxPending < 1 {
+		return eris.New("pgmirror must keep the changes of at least one tick")
+	}
+	m.world = w
+	m.mirror = newMirror(m)
+	return w.AddHooks(cardinal.Hooks{OnTickEnd: m.mirror.onTickEnd})
+}
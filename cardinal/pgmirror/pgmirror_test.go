@@ -0,0 +1,174 @@
+package pgmirror_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/pgmirror"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type Health struct {
+	HP int
+}
+
+func (Health) Name() string { return "health" }
+
+func TestMirror(t *testing.T) {
+	db := &fakeDB{}
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithStructuralEvents())
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+
+	var hero types.EntityID
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		var err error
+		switch wCtx.CurrentTick() {
+		case 0:
+			hero, err = cardinal.Create(wCtx, Health{HP: 100})
+		case 1:
+			err = cardinal.SetComponent(wCtx, hero, &Health{HP: 90})
+		case 2:
+			err = cardinal.Remove(wCtx, hero)
+		}
+		return err
+	}))
+	assert.NilError(t, world.UseModule(pgmirror.NewModule(sql.OpenDB(db), pgmirror.WithComponents("health"))))
+
+	// The mirror is empty, so it is built from a snapshot.
+	tf.DoTick()
+	assert.DeepEqual(t, db.committed, []string{
+		`DELETE "cardinal_health"`,
+		`INSERT "cardinal_health" 0 {"HP":100} 0`,
+		`tick 0`,
+	})
+
+	// The changes of a tick that failed to be written are written with the next tick's.
+	db.committed = nil
+	db.failCommits = true
+	tf.DoTick()
+	assert.Equal(t, len(db.committed), 0)
+	db.failCommits = false
+	tf.DoTick()
+	assert.DeepEqual(t, db.committed, []string{
+		`INSERT "cardinal_health" 0 {"HP":90} 1`,
+		`DELETE "cardinal_health" 0`,
+		`tick 2`,
+	})
+}
+
+func TestMirrorIsRebuiltWhenItIsAhead(t *testing.T) {
+	five := int64(5)
+	db := &fakeDB{tick: &five}
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithStructuralEvents())
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		if wCtx.CurrentTick() == 1 {
+			_, err := cardinal.Create(wCtx, Health{HP: 100})
+			return err
+		}
+		return nil
+	}))
+	assert.NilError(t, world.UseModule(pgmirror.NewModule(sql.OpenDB(db), pgmirror.WithComponents("health"))))
+
+	// The mirror has ticks that the world doesn't, so it is rebuilt from a snapshot, and then kept up to date.
+	tf.DoTick()
+	assert.DeepEqual(t, db.committed, []string{`DELETE "cardinal_health"`, `tick 0`})
+
+	db.committed = nil
+	tf.DoTick()
+	assert.DeepEqual(t, db.committed, []string{`INSERT "cardinal_health" 0 {"HP":100} 1`, `tick 1`})
+}
+
+// fakeDB is a database/sql connector that records the statements of committed transactions.
+type fakeDB struct {
+	committed   []string
+	failCommits bool
+	// tick is the mirror's tick, or nil if the mirror is empty.
+	tick *int64
+}
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: db}, nil }
+func (db *fakeDB) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct {
+	db      *fakeDB
+	pending []string
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.pending = nil
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	if c.db.failCommits {
+		return errors.New("connection refused")
+	}
+	c.db.committed = append(c.db.committed, c.pending...)
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.pending = nil
+	return nil
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	fields := strings.Fields(s.query)
+	switch {
+	case strings.HasPrefix(s.query, `INSERT INTO "cardinal_mirror"`):
+		s.conn.pending = append(s.conn.pending, fmt.Sprintf("tick %d", args[1]))
+	case fields[0] == "INSERT" || fields[0] == "DELETE":
+		record := fields[0] + " " + fields[2]
+		for _, arg := range args {
+			record += fmt.Sprintf(" %v", arg)
+		}
+		s.conn.pending = append(s.conn.pending, record)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &fakeRows{tick: s.conn.db.tick}, nil
+}
+
+type fakeRows struct {
+	tick *int64
+}
+
+func (r *fakeRows) Columns() []string { return []string{"tick"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.tick == nil {
+		return io.EOF
+	}
+	dest[0] = *r.tick
+	r.tick = nil
+	return nil
+}
@@ -2,6 +2,8 @@ package cardinal
 
 import (
 	"errors"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -13,34 +15,25 @@ import (
 	"pkg.world.dev/world-engine/cardinal/persona/msg"
 	"pkg.world.dev/world-engine/cardinal/persona/query"
 	querylib "pkg.world.dev/world-engine/cardinal/query"
+	"pkg.world.dev/world-engine/cardinal/receipt"
 	"pkg.world.dev/world-engine/cardinal/search"
 	"pkg.world.dev/world-engine/cardinal/search/filter"
 	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
 )
 
-var (
-	_ Plugin = (*personaPlugin)(nil)
-
-	// TODO: Replace these global variables when indexing/fast-searching is supported.
-	// See https://linear.app/arguslabs/issue/WORLD-1057/spec-out-component-indexing
-	// These global variables are used to quickly identify already-created persona tags. The map should exactly match
-	// the persona tag information stored in the ECS layer. When Cardinal restarts, this map needs to be rebuilt.
-	//
-	// globalPersonaTagToAddressIndex keeps track of the mapping of persona-tags->signer-address so it doesn't need to
-	// be recomputed each tick.
-	globalPersonaTagToAddressIndex personaIndex
-	// tickOfPersonaTagToAddressIndex is the tick that the globalPersonaTagToAddressIndex was built on. In normal usage,
-	// wCtx.CurrentTick should always be greater than this number, but during tests the currentTick will be reset.
-	// Tracking this number at the global is easier than updating each test to reset these global value.
-	tickOfPersonaTagToAddressIndex uint64
-)
+var _ Plugin = (*personaPlugin)(nil)
 
 type personaIndex = map[string]personaIndexEntry
 
 type personaIndexEntry struct {
+	// PersonaTag is the persona tag as it was registered, with its case.
+	PersonaTag    string
 	SignerAddress string
 	EntityID      types.EntityID
+	// AliasOf is the persona tag of the primary persona, if the persona is an alias.
+	AliasOf string
 }
 
 type personaPlugin struct {
@@ -67,6 +60,16 @@ func (p *personaPlugin) Register(world *World) error {
 	if err != nil {
 		return err
 	}
+	return p.RegisterErrorCodes(world)
+}
+
+// RegisterErrorCodes registers the codes of the reasons why a persona tag can't be registered.
+func (p *personaPlugin) RegisterErrorCodes(world *World) error {
+	for _, e := range createPersonaErrors {
+		if err := RegisterErrorCode(world, e.code, e.template, e.sentinel); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -81,7 +84,20 @@ func (p *personaPlugin) RegisterQueries(world *World) error {
 }
 
 func (p *personaPlugin) RegisterSystems(world *World) error {
-	err := RegisterSystems(world, CreatePersonaSystem, AuthorizePersonaAddressSystem)
+	err := RegisterSystems(world,
+		CreatePersonaSystem,
+		AuthorizePersonaAddressSystem,
+		RevokePersonaAddressSystem,
+		PruneSessionKeysSystem,
+		AuthorizeSessionKeySystem,
+		RevokeSessionKeySystem,
+		RotatePersonaSignerSystem,
+		TransferPersonaSystem,
+		ChangeSignerSystem,
+		ReservePersonaSystem,
+		ConfirmPersonaSystem,
+		MergePersonaSystem,
+	)
 	if err != nil {
 		return err
 	}
@@ -93,6 +109,10 @@ func (p *personaPlugin) RegisterComponents(world *World) error {
 	if err != nil {
 		return err
 	}
+	err = RegisterComponent[component.PersonaReservationComponent](world)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -106,6 +126,51 @@ func (p *personaPlugin) RegisterMessages(world *World) error {
 		RegisterMessage[msg.AuthorizePersonaAddress, msg.AuthorizePersonaAddressResult](
 			world,
 			"authorize-persona-address",
+		),
+		RegisterMessage[msg.RevokePersonaAddress, msg.RevokePersonaAddressResult](
+			world,
+			"revoke-persona-address",
+		),
+		RegisterMessage[msg.AuthorizeSessionKey, msg.AuthorizeSessionKeyResult](
+			world,
+			msg.AuthorizeSessionKeyMessageName,
+			message.WithCustomMessageGroup[msg.AuthorizeSessionKey, msg.AuthorizeSessionKeyResult]("persona"),
+		),
+		RegisterMessage[msg.RevokeSessionKey, msg.RevokeSessionKeyResult](
+			world,
+			msg.RevokeSessionKeyMessageName,
+			message.WithCustomMessageGroup[msg.RevokeSessionKey, msg.RevokeSessionKeyResult]("persona"),
+		),
+		RegisterMessage[msg.RotatePersonaSigner, msg.RotatePersonaSignerResult](
+			world,
+			msg.RotatePersonaSignerMessageName,
+			message.WithCustomMessageGroup[msg.RotatePersonaSigner, msg.RotatePersonaSignerResult]("persona"),
+		),
+		RegisterMessage[msg.TransferPersona, msg.TransferPersonaResult](
+			world,
+			msg.TransferPersonaMessageName,
+			message.WithCustomMessageGroup[msg.TransferPersona, msg.TransferPersonaResult]("persona"),
+		),
+		RegisterMessage[msg.ChangeSigner, msg.ChangeSignerResult](
+			world,
+			msg.ChangeSignerMessageName,
+			message.WithCustomMessageGroup[msg.ChangeSigner, msg.ChangeSignerResult]("persona"),
+		),
+		RegisterMessage[msg.ReservePersona, msg.ReservePersonaResult](
+			world,
+			msg.ReservePersonaMessageName,
+			message.WithCustomMessageGroup[msg.ReservePersona, msg.ReservePersonaResult]("persona"),
+		),
+		RegisterMessage[msg.ConfirmPersona, msg.ConfirmPersonaResult](
+			world,
+			msg.ConfirmPersonaMessageName,
+			message.WithCustomMessageGroup[msg.ConfirmPersona, msg.ConfirmPersonaResult]("persona"),
+		),
+		RegisterMessage[msg.MergePersona, msg.MergePersonaResult](
+			world,
+			msg.MergePersonaMessageName,
+			message.WithCustomMessageGroup[msg.MergePersona, msg.MergePersonaResult]("persona"),
+			message.WithAdminSignature[msg.MergePersona, msg.MergePersonaResult](),
 		))
 }
 
@@ -117,7 +182,8 @@ func (p *personaPlugin) RegisterMessages(world *World) error {
 // users who want to interact with the game via smart contract can link their EVM address to their persona tag, enabling
 // them to mutate their owned state from the context of the EVM.
 func AuthorizePersonaAddressSystem(wCtx engine.Context) error {
-	if err := buildGlobalPersonaIndex(wCtx); err != nil {
+	index, err := buildPersonaIndex(wCtx)
+	if err != nil {
 		return err
 	}
 	return EachMessage[msg.AuthorizePersonaAddress, msg.AuthorizePersonaAddressResult](
@@ -130,7 +196,7 @@ func AuthorizePersonaAddressSystem(wCtx engine.Context) error {
 
 			// Check if the Persona Tag exists
 			lowerPersona := strings.ToLower(tx.PersonaTag)
-			data, ok := globalPersonaTagToAddressIndex[lowerPersona]
+			data, ok := index.entries[lowerPersona]
 			if !ok {
 				return result, eris.Errorf("persona %s does not exist", tx.PersonaTag)
 			}
@@ -163,54 +229,645 @@ func AuthorizePersonaAddressSystem(wCtx engine.Context) error {
 	)
 }
 
+// RevokePersonaAddressSystem enables users to remove an address that was authorized to their persona tag with
+// AuthorizePersonaAddressSystem, e.g. when the wallet behind it was lost or compromised.
+func RevokePersonaAddressSystem(wCtx engine.Context) error {
+	index, err := buildPersonaIndex(wCtx)
+	if err != nil {
+		return err
+	}
+	return EachMessage[msg.RevokePersonaAddress, msg.RevokePersonaAddressResult](
+		wCtx,
+		func(txData message.TxData[msg.RevokePersonaAddress]) (
+			result msg.RevokePersonaAddressResult, err error,
+		) {
+			txMsg, tx := txData.Msg, txData.Tx
+			result.Success = false
+
+			// Check if the Persona Tag exists
+			lowerPersona := strings.ToLower(tx.PersonaTag)
+			data, ok := index.entries[lowerPersona]
+			if !ok {
+				return result, eris.Errorf("persona %s does not exist", tx.PersonaTag)
+			}
+
+			// Addresses are stored the way AuthorizePersonaAddressSystem normalized them
+			txMsg.Address = strings.ToLower(txMsg.Address)
+			txMsg.Address = strings.ReplaceAll(txMsg.Address, " ", "")
+
+			revoked := false
+			err = UpdateComponent[component.SignerComponent](
+				wCtx, data.EntityID, func(s *component.SignerComponent) *component.SignerComponent {
+					s.AuthorizedAddresses = slices.DeleteFunc(s.AuthorizedAddresses, func(addr string) bool {
+						revoked = revoked || addr == txMsg.Address
+						return addr == txMsg.Address
+					})
+					return s
+				},
+			)
+			if err != nil {
+				return result, eris.Wrap(err, "unable to update signer component with address")
+			}
+			if !revoked {
+				return result, eris.Errorf("address %s is not authorized for persona %s", txMsg.Address, tx.PersonaTag)
+			}
+			result.Success = true
+			return result, nil
+		},
+	)
+}
+
+// PruneSessionKeysSystem removes the session keys of every persona that have expired, at the start of every tick.
+// Expired keys can't sign transactions even before they are removed.
+func PruneSessionKeysSystem(wCtx engine.Context) error {
+	var expired []types.EntityID
+	var errs []error
+	s := search.NewSearch().Entity(filter.Contains(filter.Component[component.SignerComponent]()))
+	err := s.Each(wCtx,
+		func(id types.EntityID) bool {
+			sc, err := GetComponent[component.SignerComponent](wCtx, id)
+			if err != nil {
+				errs = append(errs, err)
+				return true
+			}
+			for _, k := range sc.SessionKeys {
+				if !k.IsActiveAt(wCtx.CurrentTick(), wCtx.Timestamp()) {
+					expired = append(expired, id)
+					break
+				}
+			}
+			return true
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	for _, id := range expired {
+		err = UpdateComponent[component.SignerComponent](
+			wCtx, id, func(s *component.SignerComponent) *component.SignerComponent {
+				s.SessionKeys = slices.DeleteFunc(s.SessionKeys, func(k component.SessionKey) bool {
+					return !k.IsActiveAt(wCtx.CurrentTick(), wCtx.Timestamp())
+				})
+				return s
+			},
+		)
+		if err != nil {
+			return eris.Wrap(err, "unable to remove expired session keys")
+		}
+	}
+	return nil
+}
+
+// AuthorizeSessionKeySystem lets the signer of a persona tag authorize a session key: an address that may sign the
+// persona's transactions until it expires, optionally only for some messages. Session keys can never sign the messages
+// that manage the persona, so a leaked key can't be used to take the persona over.
+func AuthorizeSessionKeySystem(wCtx engine.Context) error {
+	index, err := buildPersonaIndex(wCtx)
+	if err != nil {
+		return err
+	}
+	return EachMessage[msg.AuthorizeSessionKey, msg.AuthorizeSessionKeyResult](
+		wCtx,
+		func(txData message.TxData[msg.AuthorizeSessionKey]) (result msg.AuthorizeSessionKeyResult, err error) {
+			txMsg, tx := txData.Msg, txData.Tx
+			result.Success = false
+
+			lowerPersona := strings.ToLower(tx.PersonaTag)
+			data, ok := index.entries[lowerPersona]
+			if !ok {
+				return result, eris.Errorf("persona %s does not exist", tx.PersonaTag)
+			}
+			address := strings.ReplaceAll(strings.ToLower(txMsg.Address), " ", "")
+			if !common.IsHexAddress(address) {
+				return result, eris.Errorf("eth address %s is invalid", txMsg.Address)
+			}
+			if txMsg.ExpiresInTicks == 0 && txMsg.ExpiresAt == 0 {
+				return result, eris.New("session key must expire: set expiresInTicks or expiresAt")
+			}
+			if txMsg.ExpiresAt != 0 && txMsg.ExpiresAt <= wCtx.Timestamp() {
+				return result, eris.Errorf("session key expiry %d is in the past", txMsg.ExpiresAt)
+			}
+			for _, msgName := range txMsg.Messages {
+				if err = checkSessionKeyMessage(wCtx, msgName); err != nil {
+					return result, err
+				}
+			}
+
+			key := component.SessionKey{
+				Address:   address,
+				ExpiresAt: txMsg.ExpiresAt,
+				Messages:  txMsg.Messages,
+			}
+			if txMsg.ExpiresInTicks != 0 {
+				// The key is authorized once this tick is complete, so its lifetime is counted from the next tick.
+				key.ExpiresAtTick = wCtx.CurrentTick() + 1 + txMsg.ExpiresInTicks
+			}
+			err = UpdateComponent[component.SignerComponent](
+				wCtx, data.EntityID, func(s *component.SignerComponent) *component.SignerComponent {
+					s.SessionKeys = slices.DeleteFunc(s.SessionKeys, func(k component.SessionKey) bool {
+						return k.Address == address
+					})
+					s.SessionKeys = append(s.SessionKeys, key)
+					return s
+				},
+			)
+			if err != nil {
+				return result, eris.Wrap(err, "unable to update signer component with session key")
+			}
+			result.Success = true
+			result.ExpiresAtTick = key.ExpiresAtTick
+			return result, nil
+		},
+	)
+}
+
+// RevokeSessionKeySystem lets the signer of a persona tag remove a session key before it expires.
+func RevokeSessionKeySystem(wCtx engine.Context) error {
+	index, err := buildPersonaIndex(wCtx)
+	if err != nil {
+		return err
+	}
+	return EachMessage[msg.RevokeSessionKey, msg.RevokeSessionKeyResult](
+		wCtx,
+		func(txData message.TxData[msg.RevokeSessionKey]) (result msg.RevokeSessionKeyResult, err error) {
+			txMsg, tx := txData.Msg, txData.Tx
+			result.Success = false
+
+			lowerPersona := strings.ToLower(tx.PersonaTag)
+			data, ok := index.entries[lowerPersona]
+			if !ok {
+				return result, eris.Errorf("persona %s does not exist", tx.PersonaTag)
+			}
+			address := strings.ReplaceAll(strings.ToLower(txMsg.Address), " ", "")
+
+			revoked := false
+			err = UpdateComponent[component.SignerComponent](
+				wCtx, data.EntityID, func(s *component.SignerComponent) *component.SignerComponent {
+					s.SessionKeys = slices.DeleteFunc(s.SessionKeys, func(k component.SessionKey) bool {
+						revoked = revoked || k.Address == address
+						return k.Address == address
+					})
+					return s
+				},
+			)
+			if err != nil {
+				return result, eris.Wrap(err, "unable to update signer component with session key")
+			}
+			if !revoked {
+				return result, eris.Errorf("address %s is not a session key of persona %s", txMsg.Address, tx.PersonaTag)
+			}
+			result.Success = true
+			return result, nil
+		},
+	)
+}
+
+// checkSessionKeyMessage checks that session keys may be limited to the message with the given full name.
+func checkSessionKeyMessage(wCtx engine.Context, msgName string) error {
+	if isPersonaManagementMessage(msgName) {
+		return eris.Errorf("session keys can't sign %s", msgName)
+	}
+	if ctx, ok := wCtx.(*worldContext); ok {
+		if _, ok := ctx.world.GetMessageByFullName(msgName); !ok {
+			return eris.Errorf("message %s is not registered", msgName)
+		}
+	}
+	return nil
+}
+
+// isPersonaManagementMessage reports whether the message with the given full name manages a persona, rather than
+// acting as it. These messages can only be signed by the persona's signers, never by its session keys.
+func isPersonaManagementMessage(msgName string) bool {
+	group, _, _ := strings.Cut(msgName, ".")
+	return group == "persona" || msgName == "game.authorize-persona-address" || msgName == "game.revoke-persona-address"
+}
+
+// RotatePersonaSignerSystem lets the signer of a persona tag hand signing rights over to a new signer address. The
+// replaced address stays valid for the requested grace period so that transactions signed before the rotation can
+// still be accepted.
+func RotatePersonaSignerSystem(wCtx engine.Context) error {
+	index, err := buildPersonaIndex(wCtx)
+	if err != nil {
+		return err
+	}
+	return EachMessage[msg.RotatePersonaSigner, msg.RotatePersonaSignerResult](
+		wCtx,
+		func(txData message.TxData[msg.RotatePersonaSigner]) (result msg.RotatePersonaSignerResult, err error) {
+			txMsg, tx := txData.Msg, txData.Tx
+			result.Success = false
+
+			lowerPersona := strings.ToLower(tx.PersonaTag)
+			data, ok := index.entries[lowerPersona]
+			if !ok {
+				return result, eris.Errorf("persona %s does not exist", tx.PersonaTag)
+			}
+			if txMsg.NewSignerAddress, err = normalizeNewSignerAddress(txMsg.NewSignerAddress); err != nil {
+				return result, err
+			}
+			if strings.EqualFold(txMsg.NewSignerAddress, data.SignerAddress) {
+				return result, eris.Errorf("%s is already the signer of persona %s", txMsg.NewSignerAddress, tx.PersonaTag)
+			}
+			if txMsg.GracePeriodTicks > persona.MaximumSignerRotationGracePeriodTicks {
+				return result, eris.Wrapf(persona.ErrGracePeriodTooLong, "got %d ticks, maximum is %d",
+					txMsg.GracePeriodTicks, persona.MaximumSignerRotationGracePeriodTicks)
+			}
+
+			// The rotation takes effect once this tick is complete, so the grace period is counted from the next tick.
+			validUntil := wCtx.CurrentTick() + 1 + txMsg.GracePeriodTicks
+			err = UpdateComponent[component.SignerComponent](
+				wCtx, data.EntityID, func(s *component.SignerComponent) *component.SignerComponent {
+					s.PreviousSignerAddress = s.SignerAddress
+					s.PreviousSignerValidUntilTick = validUntil
+					s.SignerAddress = txMsg.NewSignerAddress
+					return s
+				},
+			)
+			if err != nil {
+				return result, eris.Wrap(err, "unable to update signer component with new signer")
+			}
+			data.SignerAddress = txMsg.NewSignerAddress
+			index.set(wCtx, lowerPersona, data)
+			result.Success = true
+			return result, nil
+		},
+	)
+}
+
+// TransferPersonaSystem lets the signer of a persona tag hand the persona over to a new signer address. The transfer
+// takes effect immediately: the old signer, and a signer that was rotated out before it, can no longer sign for the
+// persona.
+func TransferPersonaSystem(wCtx engine.Context) error {
+	if _, err := buildPersonaIndex(wCtx); err != nil {
+		return err
+	}
+	return EachMessage[msg.TransferPersona, msg.TransferPersonaResult](
+		wCtx,
+		func(txData message.TxData[msg.TransferPersona]) (result msg.TransferPersonaResult, err error) {
+			txMsg, tx := txData.Msg, txData.Tx
+			err = replacePersonaSigner(wCtx, tx.PersonaTag, txMsg.NewSignerAddress, txMsg.KeepAuthorizedAddresses)
+			result.Success = err == nil
+			return result, err
+		},
+	)
+}
+
+// ChangeSignerSystem lets the signer of a persona tag replace its key with a new one. Unlike
+// RotatePersonaSignerSystem, there is no grace period for the old key.
+func ChangeSignerSystem(wCtx engine.Context) error {
+	if _, err := buildPersonaIndex(wCtx); err != nil {
+		return err
+	}
+	return EachMessage[msg.ChangeSigner, msg.ChangeSignerResult](
+		wCtx,
+		func(txData message.TxData[msg.ChangeSigner]) (result msg.ChangeSignerResult, err error) {
+			err = replacePersonaSigner(wCtx, txData.Tx.PersonaTag, txData.Msg.NewSignerAddress, true)
+			result.Success = err == nil
+			return result, err
+		},
+	)
+}
+
+// replacePersonaSigner makes the new signer address the only signer of the persona.
+func replacePersonaSigner(
+	wCtx engine.Context, personaTag, newSignerAddress string, keepAuthorizedAddresses bool,
+) error {
+	index, err := livePersonaIndexOf(wCtx)
+	if err != nil {
+		return err
+	}
+	lowerPersona := strings.ToLower(personaTag)
+	data, ok := index.entries[lowerPersona]
+	if !ok {
+		return eris.Errorf("persona %s does not exist", personaTag)
+	}
+	if newSignerAddress, err = normalizeNewSignerAddress(newSignerAddress); err != nil {
+		return err
+	}
+	if strings.EqualFold(newSignerAddress, data.SignerAddress) {
+		return eris.Errorf("%s is already the signer of persona %s", newSignerAddress, personaTag)
+	}
+	err = UpdateComponent[component.SignerComponent](
+		wCtx, data.EntityID, func(s *component.SignerComponent) *component.SignerComponent {
+			s.SignerAddress = newSignerAddress
+			s.PreviousSignerAddress = ""
+			s.PreviousSignerValidUntilTick = 0
+			if !keepAuthorizedAddresses {
+				s.AuthorizedAddresses = make([]string, 0)
+			}
+			return s
+		},
+	)
+	if err != nil {
+		return eris.Wrap(err, "unable to update signer component with new signer")
+	}
+	data.SignerAddress = newSignerAddress
+	index.set(wCtx, lowerPersona, data)
+	return nil
+}
+
+// normalizeNewSignerAddress returns the new signer address of a persona in lowercase, without spaces. It returns an
+// error if the address isn't a valid Ethereum address.
+func normalizeNewSignerAddress(address string) (string, error) {
+	if address == "" {
+		return "", eris.New("new signer address must not be empty")
+	}
+	address = strings.ReplaceAll(strings.ToLower(address), " ", "")
+	if !common.IsHexAddress(address) {
+		return "", eris.Errorf("eth address %s is invalid", address)
+	}
+	return address, nil
+}
+
 // -----------------------------------------------------------------------------
 // Persona System
 // -----------------------------------------------------------------------------
 
 // CreatePersonaSystem is a system that will associate persona tags with signature addresses. Each persona tag
 // may have at most 1 signer, so additional attempts to register a signer with a persona tag will be ignored.
+// A persona tag that is held by a reservation can only be created by the signer that holds it. Persona tags that are
+// reserved by the game or not allowed by the name policy are not created, and the result explains why.
 func CreatePersonaSystem(wCtx engine.Context) error {
-	if err := buildGlobalPersonaIndex(wCtx); err != nil {
+	index, err := buildPersonaIndex(wCtx)
+	if err != nil {
 		return err
 	}
+	var reservations personaReservations
 	return EachMessage[msg.CreatePersona, msg.CreatePersonaResult](
 		wCtx,
 		func(txData message.TxData[msg.CreatePersona]) (result msg.CreatePersonaResult, err error) {
 			txMsg := txData.Msg
 			result.Success = false
 
-			if !persona.IsValidPersonaTag(txMsg.PersonaTag) {
-				err := eris.Errorf(
-					"persona tag %q invalid: must be between %d-%d characters & contain only alphanumeric characters and underscores",
-					txMsg.PersonaTag,
-					persona.MinimumPersonaTagLength,
-					persona.MaximumPersonaTagLength)
+			// Reservations are only looked up when there is a persona to create, to keep empty ticks fast
+			if reservations == nil {
+				if reservations, err = getPersonaReservations(wCtx); err != nil {
+					return result, err
+				}
+			}
+			lowerPersona := strings.ToLower(txMsg.PersonaTag)
+			r, reserved := reservations[lowerPersona]
+			if reserved && r.IsHeldAt(wCtx.CurrentTick()) && r.SignerAddress != txMsg.SignerAddress {
+				return result, eris.Wrapf(persona.ErrPersonaTagReserved, "persona tag %s", txMsg.PersonaTag)
+			}
+
+			if err = createPersona(wCtx, txMsg.PersonaTag, txMsg.SignerAddress); errors.Is(err, persona.ErrNamePending) {
+				r, err = holdPendingPersona(wCtx, r, reserved, txMsg.PersonaTag, txMsg.SignerAddress)
+				if err != nil {
+					return result, err
+				}
+				reservations[lowerPersona] = r
+				result.Pending = true
+				return result, nil
+			} else if code, ok := createPersonaErrorCode(err); ok {
+				if code == msg.CodeTagAlreadyRegistered && index.isRegisteredTo(txMsg.PersonaTag, txMsg.SignerAddress) {
+					// The signer retried a registration that succeeded, which it is told about again.
+					result.Success = true
+					return result, nil
+				}
+				// The sender is told why the tag can't be registered, so that it can pick another one. The coded error
+				// is in the receipt too, where clients find the codes of the errors of every transaction.
+				result.Error = err.Error()
+				result.Code = code
+				wCtx.AddMessageError(txData.Hash, receipt.NewError(code, map[string]any{"personaTag": txMsg.PersonaTag}))
+				return result, nil
+			} else if err != nil {
+				return result, err
+			}
+			if reserved {
+				// The signer created the persona it held, so the hold is no longer needed
+				if err = Remove(wCtx, r.EntityID); err != nil {
+					return result, eris.Wrap(err, "unable to remove persona reservation")
+				}
+				delete(reservations, lowerPersona)
+			}
+			result.Success = true
+			return result, nil
+		},
+	)
+}
+
+// createPersonaErrors are the errors of createPersona that are reported in the result of the transaction, with their
+// code, instead of failing it.
+var createPersonaErrors = []struct {
+	code     string
+	template string
+	sentinel error
+}{
+	{msg.CodeTagAlreadyRegistered, "the persona tag has already been registered", persona.ErrPersonaTagRegistered},
+	{msg.CodeTagRejected, "the persona tag is not allowed by the name policy", persona.ErrNameRejected},
+	{msg.CodeTagReserved, "the persona tag is reserved by the game", persona.ErrNameReserved},
+}
+
+// createPersonaErrorCode returns the code of the errors of createPersona that are reported in the result of the
+// transaction instead of as errors.
+func createPersonaErrorCode(err error) (string, bool) {
+	for _, e := range createPersonaErrors {
+		if errors.Is(err, e.sentinel) {
+			return e.code, true
+		}
+	}
+	return "", false
+}
+
+// isRegisteredTo reports whether the persona tag, with the same case, is registered to the signer address.
+func (index *livePersonaIndex) isRegisteredTo(personaTag, signerAddress string) bool {
+	entry, ok := index.entries[strings.ToLower(personaTag)]
+	return ok && entry.AliasOf == "" && entry.PersonaTag == personaTag && entry.SignerAddress == signerAddress
+}
+
+// ReservePersonaSystem holds persona tags for signer addresses until they are confirmed by ConfirmPersonaSystem or
+// the hold expires. Expired holds are removed at the start of every tick, and the personas of held tags that the name
+// policy has approved since are created.
+func ReservePersonaSystem(wCtx engine.Context) error {
+	index, err := buildPersonaIndex(wCtx)
+	if err != nil {
+		return err
+	}
+	reservations, err := getPersonaReservations(wCtx)
+	if err != nil {
+		return err
+	}
+	for lowerPersona, r := range reservations {
+		if r.IsHeldAt(wCtx.CurrentTick()) {
+			continue
+		}
+		if err := Remove(wCtx, r.EntityID); err != nil {
+			return eris.Wrap(err, "unable to remove expired persona reservation")
+		}
+		delete(reservations, lowerPersona)
+	}
+	if err := settlePendingPersonas(wCtx, reservations); err != nil {
+		return err
+	}
+
+	return EachMessage[msg.ReservePersona, msg.ReservePersonaResult](
+		wCtx,
+		func(txData message.TxData[msg.ReservePersona]) (result msg.ReservePersonaResult, err error) {
+			txMsg := txData.Msg
+			result.Success = false
+
+			// Tags can be held while the name policy decides on them, since they are checked again when confirmed.
+			if err = checkPersonaTag(wCtx, txMsg.PersonaTag); err != nil && !errors.Is(err, persona.ErrNamePending) {
 				return result, err
 			}
+			holdTicks := txMsg.HoldTicks
+			if holdTicks == 0 {
+				holdTicks = persona.DefaultReservationHoldTicks
+			}
+			if holdTicks > persona.MaximumReservationHoldTicks {
+				return result, eris.Wrapf(persona.ErrHoldTooLong, "got %d ticks, maximum is %d",
+					holdTicks, persona.MaximumReservationHoldTicks)
+			}
+			lowerPersona := strings.ToLower(txMsg.PersonaTag)
+			if _, ok := index.entries[lowerPersona]; ok {
+				return result, eris.Wrapf(persona.ErrPersonaTagRegistered, "persona tag %s", txMsg.PersonaTag)
+			}
+
+			// The hold takes effect once this tick is complete, so it is counted from the next tick.
+			expiresAt := wCtx.CurrentTick() + 1 + holdTicks
+			r, ok := reservations[lowerPersona]
+			if ok {
+				if r.SignerAddress != txMsg.SignerAddress {
+					return result, eris.Wrapf(persona.ErrPersonaTagReserved, "persona tag %s", txMsg.PersonaTag)
+				}
+				r.ExpiresAtTick = expiresAt
+				if err = SetComponent[component.PersonaReservationComponent](
+					wCtx, r.EntityID, &r.PersonaReservationComponent,
+				); err != nil {
+					return result, eris.Wrap(err, "unable to extend persona reservation")
+				}
+			} else {
+				r.PersonaReservationComponent = component.PersonaReservationComponent{
+					PersonaTag:    txMsg.PersonaTag,
+					SignerAddress: txMsg.SignerAddress,
+					ExpiresAtTick: expiresAt,
+				}
+				if r.EntityID, err = Create(wCtx, r.PersonaReservationComponent); err != nil {
+					return result, eris.Wrap(err, "unable to create persona reservation")
+				}
+			}
+			reservations[lowerPersona] = r
+			result.Success = true
+			result.ExpiresAtTick = expiresAt
+			return result, nil
+		},
+	)
+}
 
-			// Temporarily convert tag to lowercase to check against mapping of lowercase tags
+// ConfirmPersonaSystem creates the personas that are held by ReservePersonaSystem, for the signer addresses that
+// hold them.
+func ConfirmPersonaSystem(wCtx engine.Context) error {
+	if _, err := buildPersonaIndex(wCtx); err != nil {
+		return err
+	}
+	var reservations personaReservations
+	return EachMessage[msg.ConfirmPersona, msg.ConfirmPersonaResult](
+		wCtx,
+		func(txData message.TxData[msg.ConfirmPersona]) (result msg.ConfirmPersonaResult, err error) {
+			txMsg := txData.Msg
+			result.Success = false
+
+			if reservations == nil {
+				if reservations, err = getPersonaReservations(wCtx); err != nil {
+					return result, err
+				}
+			}
 			lowerPersona := strings.ToLower(txMsg.PersonaTag)
-			if _, ok := globalPersonaTagToAddressIndex[lowerPersona]; ok {
-				// This PersonaTag has already been registered. Don't do anything
-				err = eris.Errorf("persona tag %s has already been registered", txMsg.PersonaTag)
+			r, ok := reservations[lowerPersona]
+			if !ok || !r.IsHeldAt(wCtx.CurrentTick()) || r.SignerAddress != txMsg.SignerAddress {
+				return result, eris.Wrapf(persona.ErrNoReservation, "persona tag %s, signer %s",
+					txMsg.PersonaTag, txMsg.SignerAddress)
+			}
+			if err = createPersona(wCtx, r.PersonaTag, r.SignerAddress); errors.Is(err, persona.ErrNamePending) {
+				if r, err = holdPendingPersona(wCtx, r, true, r.PersonaTag, r.SignerAddress); err != nil {
+					return result, err
+				}
+				reservations[lowerPersona] = r
+				result.Pending = true
+				return result, nil
+			} else if err != nil {
 				return result, err
 			}
-			id, err := Create(wCtx, component.SignerComponent{})
+			if err = Remove(wCtx, r.EntityID); err != nil {
+				return result, eris.Wrap(err, "unable to remove persona reservation")
+			}
+			delete(reservations, lowerPersona)
+			result.Success = true
+			return result, nil
+		},
+	)
+}
+
+// MergePersonaSystem makes personas aliases of other personas, as approved by an admin signer. The alias keeps its
+// entity, but transactions and lookups on it resolve to its primary from the next tick on. Aliases of the merged
+// persona become aliases of the primary, so that an alias always resolves in a single step.
+func MergePersonaSystem(wCtx engine.Context) error {
+	index, err := buildPersonaIndex(wCtx)
+	if err != nil {
+		return err
+	}
+	return EachMessage[msg.MergePersona, msg.MergePersonaResult](
+		wCtx,
+		func(txData message.TxData[msg.MergePersona]) (result msg.MergePersonaResult, err error) {
+			txMsg, tx := txData.Msg, txData.Tx
+			result.Success = false
+
+			// The server only accepts system transactions from admin signers.
+			if !tx.IsSystemTransaction() {
+				return result, eris.Wrap(persona.ErrNotAdminTransaction, "")
+			}
+			lowerAlias := strings.ToLower(txMsg.AliasPersonaTag)
+			lowerPrimary := strings.ToLower(txMsg.PrimaryPersonaTag)
+			if lowerAlias == lowerPrimary {
+				return result, eris.Errorf("persona %s cannot be merged into itself", txMsg.AliasPersonaTag)
+			}
+			alias, ok := index.entries[lowerAlias]
+			if !ok {
+				return result, eris.Errorf("persona %s does not exist", txMsg.AliasPersonaTag)
+			}
+			primary, ok := index.entries[lowerPrimary]
+			if !ok {
+				return result, eris.Errorf("persona %s does not exist", txMsg.PrimaryPersonaTag)
+			}
+			if alias.AliasOf != "" {
+				return result, eris.Wrapf(persona.ErrPersonaIsAlias, "persona %s is an alias of %s",
+					txMsg.AliasPersonaTag, alias.AliasOf)
+			}
+			if primary.AliasOf != "" {
+				return result, eris.Wrapf(persona.ErrPersonaIsAlias, "persona %s is an alias of %s",
+					txMsg.PrimaryPersonaTag, primary.AliasOf)
+			}
+			// Aliases are stored with the primary's persona tag as it was created, since lookups are case-sensitive.
+			primarySigner, err := GetComponent[component.SignerComponent](wCtx, primary.EntityID)
 			if err != nil {
-				return result, eris.Wrap(err, "")
+				return result, eris.Wrap(err, "unable to get signer component of primary persona")
 			}
-			if err = SetComponent[component.SignerComponent](
-				wCtx, id, &component.SignerComponent{
-					PersonaTag:          txMsg.PersonaTag,
-					SignerAddress:       txMsg.SignerAddress,
-					AuthorizedAddresses: make([]string, 0),
-				},
-			); err != nil {
-				return result, eris.Wrap(err, "")
+
+			// The personas are updated in the order of their tags, so that replaying the tick updates them in the
+			// same order.
+			var merged []string
+			for lowerPersona, entry := range index.entries {
+				if lowerPersona == lowerAlias || strings.ToLower(entry.AliasOf) == lowerAlias {
+					merged = append(merged, lowerPersona)
+				}
 			}
-			globalPersonaTagToAddressIndex[lowerPersona] = personaIndexEntry{
-				SignerAddress: txMsg.SignerAddress,
-				EntityID:      id,
+			sort.Strings(merged)
+			for _, lowerPersona := range merged {
+				entry := index.entries[lowerPersona]
+				err = UpdateComponent[component.SignerComponent](
+					wCtx, entry.EntityID, func(s *component.SignerComponent) *component.SignerComponent {
+						s.AliasOf = primarySigner.PersonaTag
+						return s
+					},
+				)
+				if err != nil {
+					return result, eris.Wrap(err, "unable to update signer component with alias")
+				}
+				entry.AliasOf = primarySigner.PersonaTag
+				index.set(wCtx, lowerPersona, entry)
 			}
 			result.Success = true
 			return result, nil
@@ -218,20 +875,98 @@ func CreatePersonaSystem(wCtx engine.Context) error {
 	)
 }
 
+// createPersona creates the persona entity for the persona tag and signer address, and adds it to the index.
+// If the name policy hasn't decided on the persona tag yet, an error wrapping persona.ErrNamePending is returned.
+func createPersona(wCtx engine.Context, personaTag, signerAddress string) error {
+	index, err := livePersonaIndexOf(wCtx)
+	if err != nil {
+		return err
+	}
+	// Temporarily convert tag to lowercase to check against mapping of lowercase tags
+	lowerPersona := strings.ToLower(personaTag)
+	if _, ok := index.entries[lowerPersona]; ok {
+		// This PersonaTag has already been registered. Don't do anything
+		return eris.Wrapf(persona.ErrPersonaTagRegistered, "persona tag %s", personaTag)
+	}
+	if err = checkPersonaTag(wCtx, personaTag); err != nil {
+		return err
+	}
+	id, err := Create(wCtx, component.SignerComponent{})
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+	if err = SetComponent[component.SignerComponent](
+		wCtx, id, &component.SignerComponent{
+			PersonaTag:          personaTag,
+			SignerAddress:       signerAddress,
+			AuthorizedAddresses: make([]string, 0),
+			CreatedAtTick:       wCtx.CurrentTick(),
+		},
+	); err != nil {
+		return eris.Wrap(err, "")
+	}
+	index.set(wCtx, lowerPersona, personaIndexEntry{
+		PersonaTag:    personaTag,
+		SignerAddress: signerAddress,
+		EntityID:      id,
+	})
+	return nil
+}
+
+// checkPersonaTag checks the persona tag against the world's reserved persona tags and name policy.
+func checkPersonaTag(wCtx engine.Context, personaTag string) error {
+	var policy persona.NamePolicy = persona.DefaultNamePolicy()
+	if ctx, ok := unwrapWorldContext(wCtx); ok {
+		if _, reserved := ctx.world.reservedPersonaTags[strings.ToLower(personaTag)]; reserved {
+			return eris.Wrapf(persona.ErrNameReserved, "persona tag %q", personaTag)
+		}
+		if ctx.world.personaNamePolicy != nil {
+			policy = ctx.world.personaNamePolicy
+		}
+	}
+	return policy.Check(personaTag)
+}
+
 // -----------------------------------------------------------------------------
 // Persona Index
 // -----------------------------------------------------------------------------
 
-func buildGlobalPersonaIndex(wCtx engine.Context) error {
-	// Rebuild the index if we haven't built it yet OR if we're in test and the CurrentTick has been reset.
-	if globalPersonaTagToAddressIndex != nil && tickOfPersonaTagToAddressIndex < wCtx.CurrentTick() {
-		return nil
+// livePersonaIndex maps the lowercase persona tags of the state that the running tick sees to their entries, so that
+// persona tags don't need to be looked up by scanning the signer components. The persona systems keep it up to date as
+// they change the state, and it is built from the state when the world starts, and again after the state is rolled
+// back. Every world, and every fork of a world, has its own index, which only the goroutine that ticks it uses. The
+// world also keeps a copy of the index as of the last committed tick, and saves its changes with each tick; see
+// World.GetPersonaEntity.
+type livePersonaIndex struct {
+	entries personaIndex
+}
+
+// livePersonaIndexOf returns the live persona index of the world, or world fork, that wCtx belongs to.
+func livePersonaIndexOf(wCtx engine.Context) (*livePersonaIndex, error) {
+	ctx, ok := unwrapWorldContext(wCtx)
+	if !ok {
+		return nil, eris.New("the persona index can only be used with the context of a world")
+	}
+	return &ctx.world.livePersonaIndex, nil
+}
+
+// buildPersonaIndex returns the live persona index of the world that wCtx belongs to, and builds it from the state
+// first if it hasn't been built yet.
+func buildPersonaIndex(wCtx engine.Context) (*livePersonaIndex, error) {
+	index, err := livePersonaIndexOf(wCtx)
+	if err != nil {
+		return nil, err
+	}
+	if index.entries != nil {
+		return index, nil
+	}
+	index.entries = map[string]personaIndexEntry{}
+	if ctx, ok := unwrapWorldContext(wCtx); ok {
+		ctx.world.personaIndex.stageRebuild()
 	}
-	tickOfPersonaTagToAddressIndex = wCtx.CurrentTick()
-	globalPersonaTagToAddressIndex = map[string]personaIndexEntry{}
 	var errs []error
 	s := search.NewSearch().Entity(filter.Exact(filter.Component[component.SignerComponent]()))
-	err := s.Each(wCtx,
+	err = s.Each(wCtx,
 		func(id types.EntityID) bool {
 			sc, err := GetComponent[component.SignerComponent](wCtx, id)
 			if err != nil {
@@ -239,18 +974,157 @@ func buildGlobalPersonaIndex(wCtx engine.Context) error {
 				return true
 			}
 			lowerPersona := strings.ToLower(sc.PersonaTag)
-			globalPersonaTagToAddressIndex[lowerPersona] = personaIndexEntry{
+			index.entries[lowerPersona] = personaIndexEntry{
+				PersonaTag:    sc.PersonaTag,
 				SignerAddress: sc.SignerAddress,
 				EntityID:      id,
+				AliasOf:       sc.AliasOf,
 			}
 			return true
 		},
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if len(errs) != 0 {
-		return errors.Join(errs...)
+		return nil, errors.Join(errs...)
+	}
+	return index, nil
+}
+
+// invalidate drops the index, so that it is built again from the state the next time it is used.
+func (index *livePersonaIndex) invalidate() {
+	index.entries = nil
+}
+
+// set updates the entry of the persona index, and stages the change to be saved with the tick.
+func (index *livePersonaIndex) set(wCtx engine.Context, lowerPersona string, entry personaIndexEntry) {
+	index.entries[lowerPersona] = entry
+	if ctx, ok := unwrapWorldContext(wCtx); ok {
+		ctx.world.personaIndex.stage(lowerPersona)
+	}
+}
+
+// resolvePersonaAliases returns the pool with the persona tags of the transactions that were sent by aliases replaced
+// by the persona tags of their primaries, so that systems only see primary personas. The pool is returned as is if
+// none of its transactions were sent by an alias.
+func resolvePersonaAliases(wCtx engine.Context, txPool *txpool.TxPool) (*txpool.TxPool, error) {
+	index, err := buildPersonaIndex(wCtx)
+	if err != nil {
+		return nil, err
+	}
+	primaryOf := func(tx txpool.TxData) string {
+		if tx.Tx == nil {
+			return ""
+		}
+		return index.entries[strings.ToLower(tx.Tx.PersonaTag)].AliasOf
+	}
+	aliased := false
+	for _, txs := range txPool.Transactions() {
+		for _, tx := range txs {
+			aliased = aliased || primaryOf(tx) != ""
+		}
+	}
+	if !aliased {
+		return txPool, nil
+	}
+	return txPool.Map(func(tx txpool.TxData) txpool.TxData {
+		if primary := primaryOf(tx); primary != "" {
+			// The signed transaction is copied, since the original is still submitted as it was signed.
+			sig := *tx.Tx
+			sig.PersonaTag = primary
+			tx.Tx = &sig
+		}
+		return tx
+	}), nil
+}
+
+// -----------------------------------------------------------------------------
+// Persona Reservations
+// -----------------------------------------------------------------------------
+
+// personaReservations maps lowercase persona tags to the reservations that hold them. Unlike the persona index it is
+// read from the ECS layer in each tick, since there are only ever a few holds at a time.
+type personaReservations = map[string]personaReservationEntry
+
+type personaReservationEntry struct {
+	component.PersonaReservationComponent
+	EntityID types.EntityID
+}
+
+// holdPendingPersona holds the persona tag for the signer address until the name policy decides on it. The hold
+// replaces the reservation r of the tag, if there is one.
+func holdPendingPersona(
+	wCtx engine.Context, r personaReservationEntry, reserved bool, personaTag, signerAddress string,
+) (personaReservationEntry, error) {
+	r.PersonaReservationComponent = component.PersonaReservationComponent{
+		PersonaTag:       personaTag,
+		SignerAddress:    signerAddress,
+		ExpiresAtTick:    wCtx.CurrentTick() + 1 + persona.NameApprovalTimeoutTicks,
+		AwaitingApproval: true,
+	}
+	var err error
+	if reserved {
+		err = SetComponent[component.PersonaReservationComponent](wCtx, r.EntityID, &r.PersonaReservationComponent)
+	} else {
+		r.EntityID, err = Create(wCtx, r.PersonaReservationComponent)
+	}
+	if err != nil {
+		return r, eris.Wrap(err, "unable to hold persona tag awaiting approval")
+	}
+	return r, nil
+}
+
+// settlePendingPersonas creates the personas of the held tags that the name policy has approved, and releases the
+// tags that it has rejected. Tags are settled in order, so that the personas are created in the same order every time.
+func settlePendingPersonas(wCtx engine.Context, reservations personaReservations) error {
+	var pending []string
+	for lowerPersona, r := range reservations {
+		if r.AwaitingApproval {
+			pending = append(pending, lowerPersona)
+		}
+	}
+	sort.Strings(pending)
+	for _, lowerPersona := range pending {
+		r := reservations[lowerPersona]
+		err := createPersona(wCtx, r.PersonaTag, r.SignerAddress)
+		if errors.Is(err, persona.ErrNamePending) {
+			continue
+		}
+		if err != nil {
+			wCtx.Logger().Info().Err(err).Str("persona_tag", r.PersonaTag).Msg("persona tag was not approved")
+		}
+		if err := Remove(wCtx, r.EntityID); err != nil {
+			return eris.Wrap(err, "unable to remove persona reservation")
+		}
+		delete(reservations, lowerPersona)
 	}
 	return nil
 }
+
+func getPersonaReservations(wCtx engine.Context) (personaReservations, error) {
+	reservations := personaReservations{}
+	var errs []error
+	s := search.NewSearch().Entity(filter.Exact(filter.Component[component.PersonaReservationComponent]()))
+	err := s.Each(wCtx,
+		func(id types.EntityID) bool {
+			r, err := GetComponent[component.PersonaReservationComponent](wCtx, id)
+			if err != nil {
+				errs = append(errs, err)
+				return true
+			}
+			reservations[strings.ToLower(r.PersonaTag)] = personaReservationEntry{
+				PersonaReservationComponent: *r,
+				EntityID:                    id,
+			}
+			return true
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(errs) != 0 {
+		return nil, errors.Join(errs...)
+	}
+	return reservations, nil
+}
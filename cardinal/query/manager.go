@@ -1,12 +1,16 @@
 package query
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/rotisserie/eris"
 
 	"pkg.world.dev/world-engine/cardinal/types/engine"
 )
 
 type Manager struct {
+	// registeredQueries maps a query's full name, "<group>.<name>", to the query.
 	registeredQueries map[string]engine.Query
 }
 
@@ -17,40 +21,83 @@ func NewManager() *Manager {
 }
 
 // RegisterQuery registers a query with the query manager.
-// There can only be one query with a given name.
+// There can only be one query with a given name in each group.
 func (m *Manager) RegisterQuery(name string, query engine.Query) error {
+	fullName := FullName(query.Group(), name)
 	// Check that the query is not already registered
-	if err := m.isQueryNameUnique(name); err != nil {
+	if err := m.isQueryNameUnique(fullName); err != nil {
 		return err
 	}
 
 	// Register the query
-	m.registeredQueries[name] = query
+	m.registeredQueries[fullName] = query
 
 	return nil
 }
 
-// GetRegisteredQueries returns all the registered queries.
+// GetRegisteredQueries returns all the registered queries, sorted by full name.
 func (m *Manager) GetRegisteredQueries() []engine.Query {
+	fullNames := make([]string, 0, len(m.registeredQueries))
+	for fullName := range m.registeredQueries {
+		fullNames = append(fullNames, fullName)
+	}
+	sort.Strings(fullNames)
 	registeredQueries := make([]engine.Query, 0, len(m.registeredQueries))
-	for _, query := range m.registeredQueries {
-		registeredQueries = append(registeredQueries, query)
+	for _, fullName := range fullNames {
+		registeredQueries = append(registeredQueries, m.registeredQueries[fullName])
 	}
 	return registeredQueries
 }
 
-// GetQueryByName returns a query corresponding to its name.
-func (m *Manager) GetQueryByName(name string) (engine.Query, error) {
-	query, ok := m.registeredQueries[name]
+// GetQuery returns the query with the given name in the given group.
+func (m *Manager) GetQuery(group, name string) (engine.Query, error) {
+	query, ok := m.registeredQueries[FullName(group, name)]
 	if !ok {
-		return nil, eris.Errorf("query %q is not registered", name)
+		return nil, eris.Errorf("query %q is not registered in group %q", name, group)
 	}
 	return query, nil
 }
 
-func (m *Manager) isQueryNameUnique(name string) error {
-	if _, ok := m.registeredQueries[name]; ok {
-		return eris.Errorf("query %q is already registered", name)
+// GetQueryByName returns a query corresponding to its name. The name may either be a full name, "<group>.<name>",
+// or a bare query name. A bare name refers to the query in the default group if there is one, and otherwise to the
+// only query with that name in any group.
+func (m *Manager) GetQueryByName(name string) (engine.Query, error) {
+	if query, ok := m.registeredQueries[name]; ok {
+		return query, nil
+	}
+	if query, ok := m.registeredQueries[FullName(DefaultGroup, name)]; ok {
+		return query, nil
+	}
+	var matches []engine.Query
+	for _, query := range m.registeredQueries {
+		if query.Name() == name {
+			matches = append(matches, query)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, eris.Errorf("query %q is not registered", name)
+	case 1:
+		return matches[0], nil
+	default:
+		groups := make([]string, 0, len(matches))
+		for _, query := range matches {
+			groups = append(groups, query.Group())
+		}
+		sort.Strings(groups)
+		return nil, eris.Errorf("query %q is registered in groups %s, use <group>.%s to pick one",
+			name, strings.Join(groups, ", "), name)
+	}
+}
+
+// FullName returns the name that identifies a query across all groups.
+func FullName(group, name string) string {
+	return group + "." + name
+}
+
+func (m *Manager) isQueryNameUnique(fullName string) error {
+	if _, ok := m.registeredQueries[fullName]; ok {
+		return eris.Errorf("query %q is already registered", fullName)
 	}
 	return nil
 }
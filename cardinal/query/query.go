@@ -15,6 +15,9 @@ import (
 
 var _ engine.Query = &queryType[struct{}, struct{}]{}
 
+// DefaultGroup is the group that queries are registered under unless WithCustomQueryGroup is used.
+const DefaultGroup = "game"
+
 type Option[Request, Reply any] func(qt *queryType[Request, Reply])
 
 type queryType[Request any, Reply any] struct {
@@ -25,6 +28,9 @@ type queryType[Request any, Reply any] struct {
 	replyABI   *ethereumAbi.Type
 }
 
+// WithQueryEVMSupport generates ABI bindings for the query's Request and Reply types, allowing the query to be
+// serviced from EVM contracts via the router. The Go Request and Reply structs remain the single source of truth for
+// the ABI types; this panics if either struct cannot be represented as an ABI tuple.
 func WithQueryEVMSupport[Request, Reply any]() Option[Request, Reply] {
 	return func(qt *queryType[Request, Reply]) {
 		if err := qt.generateABIBindings(); err != nil {
@@ -33,6 +39,13 @@ func WithQueryEVMSupport[Request, Reply any]() Option[Request, Reply] {
 	}
 }
 
+// WithReadEVMSupport is an alias of WithQueryEVMSupport. EVM contracts and the router call queries "reads", as the
+// counterpart of the transactions that message.WithMsgEVMSupport exposes, so the option is also available under the
+// name that code written against the router looks for.
+func WithReadEVMSupport[Request, Reply any]() Option[Request, Reply] {
+	return WithQueryEVMSupport[Request, Reply]()
+}
+
 // WithCustomQueryGroup sets a custom group for the query.
 // By default, queries are registered under the "game" group which maps it to the /query/game/:queryType route.
 // This option allows you to set a custom group, which allow you to register the query
@@ -54,7 +67,7 @@ func NewQueryType[Request any, Reply any](
 	}
 	r := &queryType[Request, Reply]{
 		name:    name,
-		group:   "game",
+		group:   DefaultGroup,
 		handler: handler,
 	}
 	for _, opt := range opts {
@@ -207,6 +220,14 @@ func (r *queryType[Request, Reply]) GetRequestFieldInformation() map[string]any
 	return types.GetFieldInformation(reflect.TypeOf(new(Request)).Elem())
 }
 
+func (r *queryType[Request, Reply]) RequestType() reflect.Type {
+	return reflect.TypeOf(new(Request)).Elem()
+}
+
+func (r *queryType[Request, Reply]) ReplyType() reflect.Type {
+	return reflect.TypeOf(new(Reply)).Elem()
+}
+
 func validateQuery[Request any, Reply any](
 	name string,
 	handler func(wCtx engine.Context, req *Request) (*Reply, error),
@@ -1,11 +1,14 @@
 package query_test
 
 import (
+	"encoding/json"
 	"errors"
+	"net/http"
 	"testing"
 
 	"pkg.world.dev/world-engine/assert"
 	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
 	"pkg.world.dev/world-engine/cardinal/query"
 	"pkg.world.dev/world-engine/cardinal/search/filter"
 	"pkg.world.dev/world-engine/cardinal/testutils"
@@ -179,6 +182,59 @@ func TestQueryEVM(t *testing.T) {
 	assert.Equal(t, gotReply, expectedReply)
 }
 
+func TestReadEVMSupportGeneratesABIBindings(t *testing.T) {
+	type FooRequest struct {
+		ID string
+	}
+	type FooReply struct {
+		Name string
+	}
+
+	world := testutils.NewTestFixture(t, nil).World
+	err := cardinal.RegisterQuery[FooRequest, FooReply](
+		world,
+		"foo",
+		func(_ engine.Context, _ *FooRequest) (*FooReply, error) {
+			return &FooReply{Name: "Chad"}, nil
+		},
+		query.WithReadEVMSupport[FooRequest, FooReply](),
+	)
+	assert.NilError(t, err)
+
+	fooQuery, err := world.GetQueryByName("foo")
+	assert.NilError(t, err)
+	assert.True(t, fooQuery.IsEVMCompatible())
+	bz, err := fooQuery.EncodeAsABI(FooRequest{ID: "foo"})
+	assert.NilError(t, err)
+	bz, err = world.HandleEVMQuery("foo", bz)
+	assert.NilError(t, err)
+	reply, err := fooQuery.DecodeEVMReply(bz)
+	assert.NilError(t, err)
+	assert.Equal(t, reply, FooReply{Name: "Chad"})
+}
+
+func TestQueryEVMWithoutEVMSupport(t *testing.T) {
+	type FooRequest struct {
+		ID string
+	}
+	type FooReply struct {
+		Name string
+	}
+
+	world := testutils.NewTestFixture(t, nil).World
+	err := cardinal.RegisterQuery[FooRequest, FooReply](
+		world,
+		"foo",
+		func(_ engine.Context, _ *FooRequest) (*FooReply, error) {
+			return &FooReply{}, nil
+		},
+	)
+	assert.NilError(t, err)
+
+	_, err = world.HandleEVMQuery("foo", []byte("foo"))
+	assert.ErrorIs(t, err, message.ErrEVMTypeNotSet)
+}
+
 func TestErrOnNoNameOrHandler(t *testing.T) {
 	type foo struct{}
 	testCases := []struct {
@@ -219,3 +275,47 @@ func TestErrOnNoNameOrHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterReadsNamespacesQueries(t *testing.T) {
+	type RosterRequest struct{}
+	type RosterReply struct {
+		Source string `json:"source"`
+	}
+	rosterFrom := func(source string) func(engine.Context, *RosterRequest) (*RosterReply, error) {
+		return func(engine.Context, *RosterRequest) (*RosterReply, error) {
+			return &RosterReply{Source: source}, nil
+		}
+	}
+
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterQuery[RosterRequest, RosterReply](world, "roster", rosterFrom("game")))
+	assert.NilError(t, cardinal.RegisterReads(world, "guild",
+		cardinal.NewRead[RosterRequest, RosterReply]("roster", rosterFrom("guild"))))
+	assert.NilError(t, cardinal.RegisterReads(world, "party",
+		cardinal.NewRead[RosterRequest, RosterReply]("roster", rosterFrom("party"))))
+
+	// The same name can't be registered twice in one namespace.
+	err := cardinal.RegisterReads(world, "guild", cardinal.NewRead[RosterRequest, RosterReply]("roster", rosterFrom("")))
+	assert.ErrorContains(t, err, "already registered")
+	err = cardinal.RegisterReads(world, "guild/admin")
+	assert.ErrorContains(t, err, "invalid query namespace")
+
+	// A bare name refers to the query in the default group.
+	q, err := world.GetQueryByName("roster")
+	assert.NilError(t, err)
+	assert.Equal(t, q.Group(), "game")
+	q, err = world.GetQueryByName("guild.roster")
+	assert.NilError(t, err)
+	assert.Equal(t, q.Group(), "guild")
+
+	tf.StartWorld()
+	for _, group := range []string{"game", "guild", "party"} {
+		res := tf.Post("query/"+group+"/roster", RosterRequest{})
+		assert.Equal(t, res.StatusCode, http.StatusOK)
+		var reply RosterReply
+		assert.NilError(t, json.NewDecoder(res.Body).Decode(&reply))
+		assert.NilError(t, res.Body.Close())
+		assert.Equal(t, reply.Source, group)
+	}
+}
@@ -0,0 +1,242 @@
+// Package quest is a module that tracks quest progress from the events a game emits. Quests are declared in a data
+// file: each objective counts the events of a type whose fields match, like "enemy-killed" events for goblins, and
+// credits the persona named in the event. Once a persona meets every objective of a quest, the quest's rewards are
+// sent as transactions on the persona's behalf, and the persona receives an EventCompleted event.
+//
+// The module reads the events emitted earlier in the same tick, so it must be used after the game's systems are
+// registered, and after the messages that its rewards send:
+//
+//	quests, err := quest.LoadQuests("quests.json")
+//	...
+//	questModule, err := quest.NewModule(quests...)
+//	...
+//	err = world.UseModule(questModule)
+package quest
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"sort"
+	"strconv"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/sign"
+)
+
+const (
+	ModuleName    = "quest"
+	ModuleVersion = "v1.0.0"
+
+	// EventCompleted is emitted to the persona that completed a quest.
+	EventCompleted = "quest-completed"
+)
+
+var _ cardinal.Module = &Module{}
+
+// Progress is a persona's progress towards a quest. It is created when the persona first makes progress.
+type Progress struct {
+	PersonaTag string `json:"personaTag"`
+	Quest      string `json:"quest"`
+	// Counts holds the number of matching events for each objective, up to the objective's count.
+	Counts      map[string]int `json:"counts"`
+	Completed   bool           `json:"completed"`
+	CompletedAt uint64         `json:"completedAt,omitempty"`
+}
+
+func (Progress) Name() string { return "quest-progress" }
+
+type Module struct {
+	cardinal.ModuleBase
+	quests []Quest
+	// rewards holds the decoded reward messages of each quest. It is filled in by Init.
+	rewards map[string][]reward
+}
+
+type reward struct {
+	msg     types.Message
+	value   any
+	payload json.RawMessage
+}
+
+func NewModule(quests ...Quest) (*Module, error) {
+	m := &Module{rewards: map[string][]reward{}}
+	seen := map[string]bool{}
+	for _, quest := range quests {
+		if err := quest.Validate(); err != nil {
+			return nil, err
+		}
+		if seen[quest.Name] {
+			return nil, eris.Errorf("quest %q is defined more than once", quest.Name)
+		}
+		seen[quest.Name] = true
+		m.quests = append(m.quests, quest)
+	}
+	sort.Slice(m.quests, func(i, j int) bool { return m.quests[i].Name < m.quests[j].Name })
+	return m, nil
+}
+
+func (*Module) Name() string    { return ModuleName }
+func (*Module) Version() string { return ModuleVersion }
+
+// Quests returns the module's quests, sorted by name.
+func (m *Module) Quests() []Quest {
+	return m.quests
+}
+
+func (*Module) RegisterComponents(w *cardinal.World) error {
+	return cardinal.RegisterComponent[Progress](w)
+}
+
+func (m *Module) RegisterSystems(w *cardinal.World) error {
+	return cardinal.RegisterSystems(w, m.questSystem)
+}
+
+// Init decodes the rewards of every quest, so that a quest with a reward that cannot be sent fails at startup rather
+// than when a persona completes it.
+func (m *Module) Init(w *cardinal.World) error {
+	for _, quest := range m.quests {
+		for i, r := range quest.Rewards {
+			msg, ok := w.GetMessageByFullName(r.Message)
+			if !ok {
+				return eris.Errorf("quest %q: reward %d sends unknown message %q", quest.Name, i, r.Message)
+			}
+			value, err := msg.Decode(r.Payload)
+			if err != nil {
+				return eris.Wrapf(err, "quest %q: failed to decode the payload of reward %d", quest.Name, i)
+			}
+			m.rewards[quest.Name] = append(m.rewards[quest.Name], reward{msg: msg, value: value, payload: r.Payload})
+		}
+	}
+	return nil
+}
+
+// credit is the progress a persona made towards a quest during a tick.
+type credit struct {
+	personaTag string
+	quest      *Quest
+	counts     map[string]int
+}
+
+// questSystem matches the events emitted so far in this tick against the objectives of every quest, and updates the
+// progress of the credited personas.
+func (m *Module) questSystem(wCtx engine.Context) error {
+	// Credits are applied in the order the personas first made progress, so that rewards are sent in the same order
+	// on every replica.
+	var credits []*credit
+	index := map[[2]string]*credit{}
+	for _, raw := range wCtx.EmittedEvents() {
+		var event map[string]any
+		if err := json.Unmarshal(raw, &event); err != nil {
+			// String events, and events that are not JSON objects, can't match an objective.
+			continue
+		}
+		for i := range m.quests {
+			quest := &m.quests[i]
+			for _, objective := range quest.Objectives {
+				personaTag, ok := objective.matches(event)
+				if !ok {
+					continue
+				}
+				key := [2]string{personaTag, quest.Name}
+				c, ok := index[key]
+				if !ok {
+					c = &credit{personaTag: personaTag, quest: quest, counts: map[string]int{}}
+					index[key] = c
+					credits = append(credits, c)
+				}
+				c.counts[objective.ID]++
+			}
+		}
+	}
+
+	for _, c := range credits {
+		if err := m.applyCredit(wCtx, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Module) applyCredit(wCtx engine.Context, c *credit) error {
+	id, progress, found, err := findProgress(wCtx, c.personaTag, c.quest.Name)
+	if err != nil {
+		return err
+	}
+	if !found {
+		progress = &Progress{PersonaTag: c.personaTag, Quest: c.quest.Name, Counts: map[string]int{}}
+	}
+	if progress.Completed {
+		return nil
+	}
+
+	completed := true
+	for _, objective := range c.quest.Objectives {
+		progress.Counts[objective.ID] = min(progress.Counts[objective.ID]+c.counts[objective.ID], objective.Count)
+		if progress.Counts[objective.ID] < objective.Count {
+			completed = false
+		}
+	}
+	if completed {
+		progress.Completed = true
+		progress.CompletedAt = wCtx.CurrentTick()
+	}
+
+	if found {
+		err = cardinal.SetComponent[Progress](wCtx, id, progress)
+	} else {
+		_, err = cardinal.Create(wCtx, *progress)
+	}
+	if err != nil || !completed {
+		return err
+	}
+	m.grantRewards(wCtx, c.personaTag, c.quest.Name)
+	return cardinal.EmitEventTo(wCtx, []string{c.personaTag}, map[string]any{
+		"type":       EventCompleted,
+		"quest":      c.quest.Name,
+		"personaTag": c.personaTag,
+	})
+}
+
+// grantRewards sends the quest's rewards on behalf of the persona. They are executed in the next tick.
+func (m *Module) grantRewards(wCtx engine.Context, personaTag, quest string) {
+	for i, r := range m.rewards[quest] {
+		tx := &sign.Transaction{
+			PersonaTag: personaTag,
+			Namespace:  wCtx.Namespace(),
+			Nonce:      rewardNonce(quest, i),
+			Body:       r.payload,
+		}
+		wCtx.AddTransaction(r.msg.ID(), r.value, tx)
+	}
+}
+
+// rewardNonce gives each reward of a quest a distinct nonce, so that identical rewards sent to the same persona by
+// different quests have distinct transaction hashes.
+func rewardNonce(quest string, index int) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(quest + "/" + strconv.Itoa(index)))
+	return h.Sum64()
+}
+
+// findProgress returns the persona's progress towards the quest, if the persona has made any.
+func findProgress(wCtx engine.Context, personaTag, quest string) (types.EntityID, *Progress, bool, error) {
+	ids, err := cardinal.NewSearch().
+		Entity(filter.Contains(filter.Component[Progress]())).
+		Where(cardinal.FilterFunction[Progress](func(p Progress) bool {
+			return p.PersonaTag == personaTag && p.Quest == quest
+		})).
+		Collect(wCtx)
+	if err != nil || len(ids) == 0 {
+		return 0, nil, false, err
+	}
+	progress, err := cardinal.GetComponent[Progress](wCtx, ids[0])
+	if err != nil {
+		return 0, nil, false, err
+	}
+	return ids[0], progress, true, nil
+}
@@ -0,0 +1,145 @@
+package quest
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+
+	"github.com/rotisserie/eris"
+)
+
+// DefaultPersonaField is the event field that holds the persona tag credited with an objective's progress.
+const DefaultPersonaField = "personaTag"
+
+// Quest is a set of objectives that a persona completes by causing matching events. Once every objective has been
+// met, the quest's rewards are granted.
+//
+// Quests are usually defined in a JSON data file and loaded with LoadQuests:
+//
+//	[
+//	  {
+//	    "name": "goblin-slayer",
+//	    "objectives": [
+//	      {"id": "kill-goblins", "event": "enemy-killed", "match": {"enemy": "goblin"}, "count": 10}
+//	    ],
+//	    "rewards": [
+//	      {"message": "game.grant-gold", "payload": {"amount": 100}}
+//	    ]
+//	  }
+//	]
+type Quest struct {
+	Name       string      `json:"name"`
+	Objectives []Objective `json:"objectives"`
+	Rewards    []Reward    `json:"rewards,omitempty"`
+}
+
+// Objective counts the events of a type whose fields match.
+type Objective struct {
+	ID string `json:"id"`
+	// Event is the "type" field of the events that count towards the objective.
+	Event string `json:"event"`
+	// Match lists fields that the event must have, with equal values.
+	Match map[string]any `json:"match,omitempty"`
+	// PersonaField is the event field that holds the persona tag to credit. It defaults to DefaultPersonaField.
+	PersonaField string `json:"personaField,omitempty"`
+	// Count is the number of matching events needed to meet the objective. It defaults to 1.
+	Count int `json:"count,omitempty"`
+}
+
+// Reward is a transaction that is sent on behalf of the persona that completed the quest. The transaction is
+// executed in the tick after the quest was completed.
+type Reward struct {
+	// Message is the full name of the message, for example "game.grant-gold".
+	Message string `json:"message"`
+	// Payload is the JSON encoded message. It defaults to an empty object.
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Validate checks that the quest can be completed, and fills in defaults.
+func (q *Quest) Validate() error {
+	if q.Name == "" {
+		return eris.New("quest name must not be empty")
+	}
+	if len(q.Objectives) == 0 {
+		return eris.Errorf("quest %q has no objectives", q.Name)
+	}
+	seen := map[string]bool{}
+	for i := range q.Objectives {
+		objective := &q.Objectives[i]
+		if objective.ID == "" || objective.Event == "" {
+			return eris.Errorf("quest %q: objective %d must have an id and an event", q.Name, i)
+		}
+		if seen[objective.ID] {
+			return eris.Errorf("quest %q: objective %q is defined more than once", q.Name, objective.ID)
+		}
+		seen[objective.ID] = true
+		if objective.PersonaField == "" {
+			objective.PersonaField = DefaultPersonaField
+		}
+		if objective.Count == 0 {
+			objective.Count = 1
+		}
+		if objective.Count < 0 {
+			return eris.Errorf("quest %q: objective %q must have a positive count", q.Name, objective.ID)
+		}
+	}
+	for i := range q.Rewards {
+		reward := &q.Rewards[i]
+		if reward.Message == "" {
+			return eris.Errorf("quest %q: reward %d must have a message", q.Name, i)
+		}
+		if len(reward.Payload) == 0 {
+			reward.Payload = json.RawMessage("{}")
+		}
+		if !json.Valid(reward.Payload) {
+			return eris.Errorf("quest %q: reward %d has an invalid payload", q.Name, i)
+		}
+	}
+	return nil
+}
+
+// matches reports whether the event counts towards the objective, and returns the persona tag to credit.
+func (o Objective) matches(event map[string]any) (string, bool) {
+	if eventType, _ := event["type"].(string); eventType != o.Event {
+		return "", false
+	}
+	for field, want := range o.Match {
+		if got, ok := event[field]; !ok || !reflect.DeepEqual(got, want) {
+			return "", false
+		}
+	}
+	personaTag, _ := event[o.PersonaField].(string)
+	return personaTag, personaTag != ""
+}
+
+// ParseQuests decodes and validates a JSON array of quests.
+func ParseQuests(data []byte) ([]Quest, error) {
+	var quests []Quest
+	if err := json.Unmarshal(data, &quests); err != nil {
+		return nil, eris.Wrap(err, "failed to decode quests")
+	}
+	seen := map[string]bool{}
+	for i := range quests {
+		if err := quests[i].Validate(); err != nil {
+			return nil, err
+		}
+		if seen[quests[i].Name] {
+			return nil, eris.Errorf("quest %q is defined more than once", quests[i].Name)
+		}
+		seen[quests[i].Name] = true
+	}
+	return quests, nil
+}
+
+// LoadQuests reads the quests from a JSON data file. See Quest for the format.
+func LoadQuests(path string) ([]Quest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to read quests from %q", path)
+	}
+	quests, err := ParseQuests(data)
+	if err != nil {
+		return nil, eris.Wrapf(err, "invalid quests in %q", path)
+	}
+	return quests, nil
+}
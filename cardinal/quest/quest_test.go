@@ -0,0 +1,136 @@
+package quest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/quest"
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type KillMsg struct {
+	Enemy string `json:"enemy"`
+}
+
+type GrantGoldMsg struct {
+	Amount int `json:"amount"`
+}
+
+type Empty struct{}
+
+func TestParseQuests(t *testing.T) {
+	quests, err := quest.LoadQuests("testdata/quests.json")
+	assert.NilError(t, err)
+	assert.Equal(t, len(quests), 2)
+	assert.Equal(t, quests[1].Objectives[0].Count, 1)
+
+	_, err = quest.ParseQuests([]byte(`[{"name": "idle"}]`))
+	assert.ErrorContains(t, err, "has no objectives")
+	_, err = quest.ParseQuests([]byte(`[{"name": "x", "objectives": [{"id": "a"}]}]`))
+	assert.ErrorContains(t, err, "must have an id and an event")
+	_, err = quest.ParseQuests([]byte(`[{"name": "x", "objectives": [{"id": "a", "event": "e"}], "rewards": [{}]}]`))
+	assert.ErrorContains(t, err, "must have a message")
+}
+
+// setupQuests registers a game that emits an event for every kill and keeps track of the gold it grants.
+func setupQuests(t *testing.T) (*testutils.TestFixture, map[string]int) {
+	tf := testutils.NewTestFixture(t, nil)
+	gold := map[string]int{}
+	assert.NilError(t, cardinal.RegisterMessage[KillMsg, Empty](tf.World, "kill"))
+	assert.NilError(t, cardinal.RegisterMessage[GrantGoldMsg, Empty](tf.World, "grant-gold"))
+	assert.NilError(t, cardinal.RegisterSystems(tf.World, func(wCtx engine.Context) error {
+		err := cardinal.EachMessage[KillMsg, Empty](wCtx, func(tx message.TxData[KillMsg]) (Empty, error) {
+			return Empty{}, wCtx.EmitEvent(map[string]any{
+				"type":   "enemy-killed",
+				"enemy":  tx.Msg.Enemy,
+				"killer": tx.Tx.PersonaTag,
+			})
+		})
+		if err != nil {
+			return err
+		}
+		return cardinal.EachMessage[GrantGoldMsg, Empty](wCtx, func(tx message.TxData[GrantGoldMsg]) (Empty, error) {
+			gold[tx.Tx.PersonaTag] += tx.Msg.Amount
+			return Empty{}, nil
+		})
+	}))
+
+	quests, err := quest.LoadQuests("testdata/quests.json")
+	assert.NilError(t, err)
+	module, err := quest.NewModule(quests...)
+	assert.NilError(t, err)
+	assert.NilError(t, tf.World.UseModule(module))
+	tf.DoTick()
+	return tf, gold
+}
+
+func kill(tf *testutils.TestFixture, personaTag, enemy string) {
+	killMsg, ok := tf.World.GetMessageByFullName("game.kill")
+	assert.Check(tf, ok)
+	tf.AddTransaction(killMsg.ID(), KillMsg{Enemy: enemy}, testutils.UniqueSignatureWithName(personaTag))
+}
+
+func progress(tf *testutils.TestFixture, personaTag string) []quest.Progress {
+	res := tf.Post("query/quest/progress", quest.ProgressRequest{PersonaTag: personaTag})
+	assert.Equal(tf, res.StatusCode, http.StatusOK)
+	var reply quest.ProgressReply
+	assert.NilError(tf, json.NewDecoder(res.Body).Decode(&reply))
+	return reply.Quests
+}
+
+func TestQuestProgressAndRewards(t *testing.T) {
+	tf, gold := setupQuests(t)
+
+	kill(tf, "alice", "goblin")
+	kill(tf, "alice", "goblin")
+	kill(tf, "bob", "goblin")
+	tf.DoTick()
+
+	alice := progress(tf, "alice")
+	assert.Equal(t, len(alice), 2)
+	assert.Equal(t, alice[0].Quest, "goblin-slayer")
+	assert.DeepEqual(t, alice[0].Counts, map[string]int{"kill-goblins": 2})
+	assert.Check(t, !alice[0].Completed)
+	assert.Equal(t, alice[1].Quest, "hunter")
+	assert.DeepEqual(t, alice[1].Counts, map[string]int{"kill-goblin": 1, "kill-wolf": 0})
+
+	kill(tf, "alice", "goblin")
+	kill(tf, "alice", "wolf")
+	tf.DoTick()
+	for _, p := range progress(tf, "alice") {
+		assert.Check(t, p.Completed, "quest %q should be completed", p.Quest)
+	}
+	assert.Equal(t, len(gold), 0, "rewards are executed in the next tick")
+
+	tf.DoTick()
+	assert.DeepEqual(t, gold, map[string]int{"alice": 120})
+
+	// Completed quests are not rewarded again.
+	kill(tf, "alice", "goblin")
+	kill(tf, "alice", "goblin")
+	kill(tf, "alice", "goblin")
+	tf.DoTick()
+	tf.DoTick()
+	assert.DeepEqual(t, gold, map[string]int{"alice": 120})
+
+	res := tf.Post("query/events/list", handler.ListEventsRequest{Type: quest.EventCompleted, Recipient: "alice"})
+	var events handler.ListEventsResponse
+	assert.NilError(t, json.NewDecoder(res.Body).Decode(&events))
+	assert.Equal(t, len(events.Events), 2)
+}
+
+func TestQuestRewardsMustBeRegistered(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	quests, err := quest.LoadQuests("testdata/quests.json")
+	assert.NilError(t, err)
+	module, err := quest.NewModule(quests...)
+	assert.NilError(t, err)
+	err = tf.World.UseModule(module)
+	assert.ErrorContains(t, err, `sends unknown message "game.grant-gold"`)
+}
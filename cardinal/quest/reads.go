@@ -0,0 +1,56 @@
+package quest
+
+import (
+	"sort"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type QuestsRequest struct{}
+
+type QuestsReply struct {
+	Quests []Quest `json:"quests"`
+}
+
+type ProgressRequest struct {
+	PersonaTag string `json:"personaTag"`
+}
+
+type ProgressReply struct {
+	// Quests lists the quests the persona has made progress towards, sorted by name.
+	Quests []Progress `json:"quests"`
+}
+
+// RegisterReads registers the /query/quest/quests and /query/quest/progress queries.
+func (m *Module) RegisterReads(w *cardinal.World) error {
+	return cardinal.RegisterReads(w, ModuleName,
+		cardinal.NewRead[QuestsRequest, QuestsReply]("quests", func(engine.Context, *QuestsRequest) (
+			*QuestsReply, error,
+		) {
+			return &QuestsReply{Quests: m.Quests()}, nil
+		}),
+		cardinal.NewRead[ProgressRequest, ProgressReply]("progress", queryProgress),
+	)
+}
+
+func queryProgress(wCtx engine.Context, req *ProgressRequest) (*ProgressReply, error) {
+	ids, err := cardinal.NewSearch().
+		Entity(filter.Contains(filter.Component[Progress]())).
+		Where(cardinal.FilterFunction[Progress](func(p Progress) bool { return p.PersonaTag == req.PersonaTag })).
+		Collect(wCtx)
+	if err != nil {
+		return nil, err
+	}
+	reply := &ProgressReply{Quests: make([]Progress, 0, len(ids))}
+	for _, id := range ids {
+		progress, err := cardinal.GetComponent[Progress](wCtx, id)
+		if err != nil {
+			return nil, err
+		}
+		reply.Quests = append(reply.Quests, *progress)
+	}
+	sort.Slice(reply.Quests, func(i, j int) bool { return reply.Quests[i].Quest < reply.Quests[j].Quest })
+	return reply, nil
+}
@@ -0,0 +1,131 @@
+package receipt
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rotisserie/eris"
+)
+
+// CodeUnknown is the code of the receipt errors that don't have a registered code.
+const CodeUnknown = "unknown"
+
+// CodedError is an error with a code, which clients can branch on, and parameters, which clients can use to show
+// the error in their own language. Systems return it like any other error:
+//
+//	return MoveResult{}, receipt.NewError("out-of-range", map[string]any{"distance": d, "max": maxDistance})
+type CodedError struct {
+	Code   string
+	Params map[string]any
+}
+
+// NewError returns an error with the code and parameters.
+func NewError(code string, params map[string]any) *CodedError {
+	return &CodedError{Code: code, Params: params}
+}
+
+// Error returns the code followed by the parameters in order of their names, such as
+// "out-of-range: distance=7, max=5".
+func (e *CodedError) Error() string {
+	if len(e.Params) == 0 {
+		return e.Code
+	}
+	names := make([]string, 0, len(e.Params))
+	for name := range e.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	params := make([]string, len(names))
+	for i, name := range names {
+		params[i] = fmt.Sprintf("%s=%v", name, e.Params[name])
+	}
+	return e.Code + ": " + strings.Join(params, ", ")
+}
+
+// ErrorDetail is the structured form of a receipt error.
+type ErrorDetail struct {
+	Code   string         `json:"code"`
+	Params map[string]any `json:"params,omitempty"`
+	// Message is the error rendered with the template of its code, if the code has one.
+	Message string `json:"message"`
+}
+
+type errorCode struct {
+	template  string
+	sentinels []error
+}
+
+// ErrorCodes is the registry of the error codes of a world. It turns the errors of receipts into ErrorDetails.
+type ErrorCodes struct {
+	codes map[string]errorCode
+	// order is the order the codes were registered in, in which sentinel errors are matched.
+	order []string
+}
+
+func NewErrorCodes() *ErrorCodes {
+	return &ErrorCodes{codes: map[string]errorCode{}}
+}
+
+// Register registers the code with an optional message template, in which "{name}" is replaced by the parameter of
+// that name. Errors that wrap one of the sentinel errors are given the code, so that the errors of existing packages
+// can be coded without changing them.
+func (c *ErrorCodes) Register(code, template string, sentinels ...error) error {
+	if code == "" || code == CodeUnknown {
+		return eris.Errorf("invalid error code %q", code)
+	}
+	if _, ok := c.codes[code]; ok {
+		return eris.Errorf("error code %q is already registered", code)
+	}
+	c.codes[code] = errorCode{template: template, sentinels: sentinels}
+	c.order = append(c.order, code)
+	return nil
+}
+
+// Detail returns the structured form of the error. Errors with neither a code nor a registered sentinel are given
+// CodeUnknown, and their message is the error string.
+func (c *ErrorCodes) Detail(err error) ErrorDetail {
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		detail := ErrorDetail{Code: coded.Code, Params: coded.Params, Message: err.Error()}
+		if code, ok := c.codes[coded.Code]; ok && code.template != "" {
+			detail.Message = render(code.template, coded.Params)
+		}
+		return detail
+	}
+	for _, name := range c.order {
+		code := c.codes[name]
+		for _, sentinel := range code.sentinels {
+			if !errors.Is(err, sentinel) {
+				continue
+			}
+			detail := ErrorDetail{Code: name, Message: err.Error()}
+			if code.template != "" {
+				detail.Message = render(code.template, nil)
+			}
+			return detail
+		}
+	}
+	return ErrorDetail{Code: CodeUnknown, Message: err.Error()}
+}
+
+// Details returns the structured form of each of the errors, or nil if there are none.
+func (c *ErrorCodes) Details(errs []error) []ErrorDetail {
+	if len(errs) == 0 {
+		return nil
+	}
+	details := make([]ErrorDetail, len(errs))
+	for i, err := range errs {
+		details[i] = c.Detail(err)
+	}
+	return details
+}
+
+func render(template string, params map[string]any) string {
+	replacements := make([]string, 0, 2*len(params))
+	for name, value := range params {
+		replacements = append(replacements, "{"+name+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(replacements...).Replace(template)
+}
@@ -0,0 +1,37 @@
+package receipt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/assert"
+)
+
+func TestErrorCodesDetailErrors(t *testing.T) {
+	errNotOwner := errors.New("not the owner")
+	codes := NewErrorCodes()
+	assert.NilError(t, codes.Register("out-of-range", "{distance} is more than {max}"))
+	assert.NilError(t, codes.Register("not-owner", "", errNotOwner))
+	assert.Check(t, codes.Register("not-owner", "") != nil)
+	assert.Check(t, codes.Register(CodeUnknown, "") != nil)
+
+	coded := NewError("out-of-range", map[string]any{"max": 5, "distance": 7})
+	assert.Equal(t, coded.Error(), "out-of-range: distance=7, max=5")
+	detail := codes.Detail(eris.Wrap(coded, "move failed"))
+	assert.Equal(t, detail.Code, "out-of-range")
+	assert.Equal(t, detail.Message, "7 is more than 5")
+
+	detail = codes.Detail(eris.Wrapf(errNotOwner, "entity %d", 3))
+	assert.Equal(t, detail.Code, "not-owner")
+	assert.Equal(t, detail.Message, "entity 3: not the owner")
+
+	// Codes without a template keep the error's own message.
+	detail = codes.Detail(NewError("unregistered", nil))
+	assert.Equal(t, detail.Code, "unregistered")
+	assert.Equal(t, detail.Message, "unregistered")
+
+	assert.Equal(t, codes.Detail(errors.New("boom")), ErrorDetail{Code: CodeUnknown, Message: "boom"})
+	assert.Check(t, codes.Details(nil) == nil)
+}
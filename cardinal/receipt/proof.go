@@ -0,0 +1,114 @@
+package receipt
+
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"slices"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// Leaves and inner nodes are hashed with different prefixes, so that an inner node can't be passed off as a leaf.
+const (
+	leafPrefix  = 0
+	innerPrefix = 1
+)
+
+// ProofStep is a sibling on the path from a receipt's leaf to the root of a tick's receipt tree.
+type ProofStep struct {
+	Hash []byte `json:"hash"`
+	// Left is true if the sibling is the left child of their parent.
+	Left bool `json:"left"`
+}
+
+// Tree is a Merkle tree of the receipts of a tick. The leaves are the JSON encoded receipts, in the order of their
+// transaction hashes, so the same receipts always make the same tree. A node without a sibling is moved up to the
+// next level unchanged.
+type Tree struct {
+	hashes []types.TxHash
+	leaves [][]byte
+	// levels holds the hashes of the nodes in each level, from the leaves up to the root.
+	levels [][][]byte
+}
+
+// NewTree builds the receipt tree of a tick.
+func NewTree(receipts []Receipt) (*Tree, error) {
+	receipts = slices.Clone(receipts)
+	slices.SortFunc(receipts, func(a, b Receipt) int {
+		return cmp.Compare(a.TxHash, b.TxHash)
+	})
+	t := &Tree{}
+	level := make([][]byte, 0, len(receipts))
+	for _, r := range receipts {
+		leaf, err := r.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		t.hashes = append(t.hashes, r.TxHash)
+		t.leaves = append(t.leaves, leaf)
+		level = append(level, hashNode(leafPrefix, leaf))
+	}
+	t.levels = append(t.levels, level)
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+			} else {
+				next = append(next, hashNode(innerPrefix, level[i], level[i+1]))
+			}
+		}
+		t.levels = append(t.levels, next)
+		level = next
+	}
+	return t, nil
+}
+
+// Root returns the root hash of the tree. The root of a tick without receipts is the hash of nothing.
+func (t *Tree) Root() []byte {
+	if top := t.levels[len(t.levels)-1]; len(top) == 1 {
+		return top[0]
+	}
+	return hashNode(innerPrefix)
+}
+
+// Proof returns the leaf of the transaction's receipt, and the proof that the leaf is included in the tree. False is
+// returned if the tree has no receipt for the transaction.
+func (t *Tree) Proof(txHash types.TxHash) (leaf []byte, proof []ProofStep, ok bool) {
+	index, ok := slices.BinarySearch(t.hashes, txHash)
+	if !ok {
+		return nil, nil, false
+	}
+	leaf = t.leaves[index]
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := index ^ 1
+		if sibling < len(level) {
+			proof = append(proof, ProofStep{Hash: level[sibling], Left: sibling < index})
+		}
+		index /= 2
+	}
+	return leaf, proof, true
+}
+
+// VerifyProof reports whether the proof shows that the leaf is included in the tree with the given root.
+func VerifyProof(root, leaf []byte, proof []ProofStep) bool {
+	hash := hashNode(leafPrefix, leaf)
+	for _, step := range proof {
+		if step.Left {
+			hash = hashNode(innerPrefix, step.Hash, hash)
+		} else {
+			hash = hashNode(innerPrefix, hash, step.Hash)
+		}
+	}
+	return bytes.Equal(hash, root)
+}
+
+func hashNode(prefix byte, parts ...[]byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{prefix})
+	for _, part := range parts {
+		h.Write(part)
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,51 @@
+package receipt_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/receipt"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+func TestReceiptProofs(t *testing.T) {
+	for _, count := range []int{1, 2, 5, 8} {
+		var receipts []receipt.Receipt
+		for i := 0; i < count; i++ {
+			receipts = append(receipts, receipt.Receipt{
+				TxHash: types.TxHash(fmt.Sprintf("tx-%d", i)),
+				Result: map[string]int{"value": i},
+			})
+		}
+		receipts[0].Errs = []error{errors.New("failed")}
+		tree, err := receipt.NewTree(receipts)
+		assert.NilError(t, err)
+
+		// The tree doesn't depend on the order of the receipts.
+		reversed := make([]receipt.Receipt, 0, count)
+		for i := count - 1; i >= 0; i-- {
+			reversed = append(reversed, receipts[i])
+		}
+		other, err := receipt.NewTree(reversed)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, other.Root(), tree.Root())
+
+		for _, r := range receipts {
+			leaf, proof, ok := tree.Proof(r.TxHash)
+			assert.Check(t, ok)
+			want, err := r.MarshalJSON()
+			assert.NilError(t, err)
+			assert.DeepEqual(t, leaf, want)
+			assert.Check(t, receipt.VerifyProof(tree.Root(), leaf, proof), "count %d, receipt %s", count, r.TxHash)
+
+			// A changed receipt doesn't match the proof.
+			forged := append([]byte{}, leaf...)
+			forged[len(forged)-2] = 'x'
+			assert.Check(t, !receipt.VerifyProof(tree.Root(), forged, proof))
+		}
+		_, _, ok := tree.Proof("unknown")
+		assert.Check(t, !ok)
+	}
+}
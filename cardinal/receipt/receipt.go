@@ -31,6 +31,8 @@ type Receipt struct {
 	TxHash types.TxHash
 	Result any
 	Errs   []error
+	// Impersonator is the admin signer address that submitted the transaction on behalf of its persona, if any.
+	Impersonator string
 }
 
 func (r Receipt) MarshalJSON() ([]byte, error) {
@@ -40,13 +42,15 @@ func (r Receipt) MarshalJSON() ([]byte, error) {
 	}
 
 	return codec.Encode(struct {
-		TxHash types.TxHash `json:"txHash"`
-		Result any          `json:"result"`
-		Errs   []string     `json:"errors"`
+		TxHash       types.TxHash `json:"txHash"`
+		Result       any          `json:"result"`
+		Errs         []string     `json:"errors"`
+		Impersonator string       `json:"impersonator,omitempty"`
 	}{
-		TxHash: r.TxHash,
-		Result: r.Result,
-		Errs:   errStrings,
+		TxHash:       r.TxHash,
+		Result:       r.Result,
+		Errs:         errStrings,
+		Impersonator: r.Impersonator,
 	})
 }
 
@@ -83,6 +87,13 @@ func (h *History) SetTick(tick uint64) {
 	h.currTick.Store(tick)
 }
 
+// Rewind makes a past tick the current tick again, and discards its receipts, so that the tick can be executed again.
+// The receipts of the ticks after it are discarded as those ticks are executed again.
+func (h *History) Rewind(tick uint64) {
+	h.currTick.Store(tick)
+	h.history[tick%h.ticksToStore] = map[types.TxHash]Receipt{}
+}
+
 // AddError associates the given error with the given transaction hash. Calling this multiple times will append
 // the error any previously added errors.
 func (h *History) AddError(hash types.TxHash, err error) {
@@ -103,6 +114,15 @@ func (h *History) SetResult(hash types.TxHash, result any) {
 	h.history[tick][hash] = rec
 }
 
+// SetImpersonator records that the given transaction hash was submitted by an admin signer on behalf of its persona.
+func (h *History) SetImpersonator(hash types.TxHash, impersonator string) {
+	tick := int(h.currTick.Load() % h.ticksToStore)
+	rec := h.history[tick][hash]
+	rec.TxHash = hash
+	rec.Impersonator = impersonator
+	h.history[tick][hash] = rec
+}
+
 // GetReceipt gets the receipt (the transaction result and the list of errors) for the given transaction hash in the
 // current tick. To get receipts from previous ticks use GetReceiptsForTick.
 func (h *History) GetReceipt(hash types.TxHash) (Receipt, bool) {
@@ -0,0 +1,76 @@
+// Package rng provides a deterministic random number generator for systems.
+package rng
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+)
+
+// RNG is a deterministic random number generator. The n-th number it produces is derived from the SHA-256 hash of its
+// seed and n, so any draw can be reproduced from the seed and the draw's position alone.
+//
+// Never use math/rand or a time-based seed in a system: every Cardinal replica, and every replay of the tick during
+// recovery, must see the same random numbers. An RNG is not safe for concurrent use.
+type RNG struct {
+	seed    [sha256.Size]byte
+	counter uint64
+}
+
+func New(seed [sha256.Size]byte) *RNG {
+	return &RNG{seed: seed}
+}
+
+// Seed returns the hex encoded seed of the RNG.
+func (r *RNG) Seed() string {
+	return hex.EncodeToString(r.seed[:])
+}
+
+// Position returns the number of values the RNG has produced.
+func (r *RNG) Position() uint64 {
+	return r.counter
+}
+
+// Uint64 returns the next random number.
+func (r *RNG) Uint64() uint64 {
+	var buf [sha256.Size + 8]byte
+	copy(buf[:], r.seed[:])
+	binary.BigEndian.PutUint64(buf[sha256.Size:], r.counter)
+	r.counter++
+	sum := sha256.Sum256(buf[:])
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// Uint64n returns a uniformly distributed random number in [0, n). It returns 0 if n is 0.
+func (r *RNG) Uint64n(n uint64) uint64 {
+	if n == 0 {
+		return 0
+	}
+	// Reject the lowest values so that the remaining range is a multiple of n and the result is not biased.
+	threshold := -n % n
+	for {
+		if v := r.Uint64(); v >= threshold {
+			return v % n
+		}
+	}
+}
+
+// Intn returns a uniformly distributed random number in [0, n), such as Intn(6)+1 for a die roll. It returns 0 if n is
+// not positive.
+func (r *RNG) Intn(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return int(r.Uint64n(uint64(n)))
+}
+
+// Float64 returns a uniformly distributed random number in [0.0, 1.0).
+func (r *RNG) Float64() float64 {
+	// The top 53 bits fill the mantissa, so every value is equally likely.
+	return float64(r.Uint64()>>11) / (1 << 53)
+}
+
+// Chance returns true with the given probability, such as Chance(0.25) for a 25% drop chance.
+func (r *RNG) Chance(p float64) bool {
+	return r.Float64() < p
+}
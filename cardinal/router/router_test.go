@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -73,6 +74,14 @@ func (f *mockMsg) GetInFieldInformation() map[string]any {
 	return map[string]any{"foo": "bar"}
 }
 
+func (f *mockMsg) InType() reflect.Type {
+	return reflect.TypeOf(f.msgValue)
+}
+
+func (f *mockMsg) OutType() reflect.Type {
+	return nil
+}
+
 var _ shard.TransactionHandlerClient = &fakeTxHandler{}
 
 type fakeTxHandler struct {
@@ -0,0 +1,164 @@
+// Package sandbox is a module that runs systems compiled to WebAssembly in a sandbox, so that a world can run
+// untrusted third-party game logic, such as user-generated mini-games, next to its own systems:
+//
+//	err = world.UseModule(sandbox.NewModule(sandbox.System{
+//		Name:       "minigame",
+//		WASM:       wasm,
+//		Components: []string{"minigame-score"},
+//	}))
+//
+// A sandboxed system can only use the host API below, and only with the components that it was granted, which the
+// world must register itself. It can only see and change the entities that it created: every entity that it creates
+// is given an Owner component with the system's name. Component values are passed as JSON.
+//
+// The guest imports the host API from the "cardinal" module, and exports its linear memory as "memory" and a run
+// function, which is called once per tick. Pointers and lengths refer to the guest's memory:
+//
+//	tick() i64
+//	log(ptr, len i32)
+//	search(name_ptr, name_len, out_ptr, out_cap i32) i32
+//	create_entity(name_ptr, name_len, value_ptr, value_len i32) i64
+//	get_component(entity i64, name_ptr, name_len, out_ptr, out_cap i32) i32
+//	set_component(entity i64, name_ptr, name_len, value_ptr, value_len i32) i32
+//	emit_event(ptr, len i32) i32
+//
+// search writes the IDs of the system's entities that have the component to out as little-endian 64 bit integers, and
+// returns how many there are; get_component writes the component's value to out and returns its length. Neither
+// writes anything if out is too small, so the guest can retry with a bigger buffer. create_entity returns the new
+// entity's ID, and set_component adds the component to the entity if it doesn't have it yet. Negative results are
+// the error codes below.
+//
+// The guest is instantiated afresh for every run, so it can't keep state in its memory between ticks; it must keep
+// it in components. Its changes are only applied once run returns: if it traps, or exceeds one of its Limits, none
+// of them are, and the error is logged instead of failing the tick. Entities created during a run get temporary IDs
+// until then, which can be used with the host API in the same run. The guest is not given WASI, so it has no clock,
+// randomness or file system, and must be built for a freestanding target, such as wasm32-unknown-unknown.
+package sandbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+const (
+	ModuleName    = "sandbox"
+	ModuleVersion = "v1.0.0"
+)
+
+// Error codes that the host API returns to the guest.
+const (
+	// ErrCodeNotFound means that the entity doesn't exist, doesn't belong to the system, or doesn't have the component.
+	ErrCodeNotFound int32 = -1
+	// ErrCodeDenied means that the component was not granted to the system.
+	ErrCodeDenied int32 = -2
+	// ErrCodeInvalid means that a component value or event is not valid JSON for it.
+	ErrCodeInvalid int32 = -3
+)
+
+// Limits are the resources that a sandboxed system can use in a tick. Zero fields are taken from DefaultLimits.
+type Limits struct {
+	// MemoryPages is the most linear memory that the guest can use, in 64 KiB pages.
+	MemoryPages uint32
+	// HostCalls is the most calls that the guest can make to the host API.
+	HostCalls int
+	// Entities is the most entities that the guest can create.
+	Entities int
+	// Timeout is the longest that the guest can run for. Unlike the other limits it depends on the machine, so a
+	// guest that hits it on one replica may not on another; it should be well above the guest's usual run time, as a
+	// guard against guests that never return.
+	Timeout time.Duration
+}
+
+var DefaultLimits = Limits{
+	MemoryPages: 256,
+	HostCalls:   10_000,
+	Entities:    100,
+	Timeout:     100 * time.Millisecond,
+}
+
+func (l Limits) withDefaults() Limits {
+	if l.MemoryPages == 0 {
+		l.MemoryPages = DefaultLimits.MemoryPages
+	}
+	if l.HostCalls == 0 {
+		l.HostCalls = DefaultLimits.HostCalls
+	}
+	if l.Entities == 0 {
+		l.Entities = DefaultLimits.Entities
+	}
+	if l.Timeout == 0 {
+		l.Timeout = DefaultLimits.Timeout
+	}
+	return l
+}
+
+// System is a system compiled to WebAssembly.
+type System struct {
+	// Name identifies the system in logs and events, and owns the entities that it creates. It must be unique.
+	Name string
+	// WASM is the compiled module.
+	WASM []byte
+	// Components are the names of the components that the system can use.
+	Components []string
+	Limits     Limits
+}
+
+// Owner is the component of the entities that a sandboxed system created.
+type Owner struct {
+	System string `json:"system"`
+}
+
+func (Owner) Name() string { return "sandbox-owner" }
+
+var _ cardinal.Module = &Module{}
+
+type Module struct {
+	cardinal.ModuleBase
+	systems []System
+	loaded  []*sandboxedSystem
+}
+
+func NewModule(systems ...System) *Module {
+	return &Module{systems: systems}
+}
+
+func (*Module) Name() string    { return ModuleName }
+func (*Module) Version() string { return ModuleVersion }
+
+func (*Module) RegisterComponents(w *cardinal.World) error {
+	return cardinal.RegisterComponent[Owner](w)
+}
+
+// RegisterSystems compiles the modules of the systems, and registers a system that runs them in order.
+func (m *Module) RegisterSystems(w *cardinal.World) error {
+	seen := map[string]bool{}
+	for _, s := range m.systems {
+		if s.Name == "" {
+			return eris.New("sandboxed systems must have a name")
+		}
+		if seen[s.Name] {
+			return eris.Errorf("sandboxed system %q is used more than once", s.Name)
+		}
+		seen[s.Name] = true
+		sys, err := newSandboxedSystem(context.Background(), s)
+		if err != nil {
+			return eris.Wrapf(err, "failed to load sandboxed system %q", s.Name)
+		}
+		m.loaded = append(m.loaded, sys)
+	}
+	return cardinal.RegisterSystems(w, m.runSystems)
+}
+
+func (m *Module) runSystems(wCtx engine.Context) error {
+	for _, sys := range m.loaded {
+		if err := sys.run(wCtx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
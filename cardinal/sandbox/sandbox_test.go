@@ -0,0 +1,87 @@
+package sandbox_test
+
+import (
+	"os"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/sandbox"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+type Score struct {
+	Points int
+}
+
+func (Score) Name() string { return "sandbox-test-score" }
+
+type Health struct {
+	HP int
+}
+
+func (Health) Name() string { return "health" }
+
+func newSandboxFixture(t *testing.T, limits sandbox.Limits) *testutils.TestFixture {
+	wasm, err := os.ReadFile("testdata/score.wasm")
+	assert.NilError(t, err)
+	tf := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, cardinal.RegisterComponent[Score](tf.World))
+	assert.NilError(t, cardinal.RegisterComponent[Health](tf.World))
+	assert.NilError(t, tf.World.UseModule(sandbox.NewModule(sandbox.System{
+		Name:       "score",
+		WASM:       wasm,
+		Components: []string{Score{}.Name()},
+		Limits:     limits,
+	})))
+	return tf
+}
+
+// scores returns the scores of the entities that have one, and checks that they belong to the sandboxed system.
+func scores(t *testing.T, tf *testutils.TestFixture) []int {
+	wCtx := cardinal.NewReadOnlyWorldContext(tf.World)
+	var points []int
+	err := cardinal.NewSearch().Entity(filter.Contains(filter.Component[Score]())).Each(wCtx,
+		func(id types.EntityID) bool {
+			score, err := cardinal.GetComponent[Score](wCtx, id)
+			assert.NilError(t, err)
+			owner, err := cardinal.GetComponent[sandbox.Owner](wCtx, id)
+			assert.NilError(t, err)
+			assert.Equal(t, owner.System, "score")
+			points = append(points, score.Points)
+			return true
+		})
+	assert.NilError(t, err)
+	return points
+}
+
+func TestSandboxedSystem(t *testing.T) {
+	tf := newSandboxFixture(t, sandbox.Limits{})
+
+	// See testdata/score.wat: the first run creates a score, and the second one increments it.
+	tf.DoTick()
+	assert.DeepEqual(t, scores(t, tf), []int{1})
+	tf.DoTick()
+	assert.DeepEqual(t, scores(t, tf), []int{2})
+
+	// Later runs trap after setting the score, so their change is discarded, and the world keeps ticking.
+	tf.DoTick()
+	tf.DoTick()
+	assert.DeepEqual(t, scores(t, tf), []int{2})
+}
+
+func TestSandboxedSystemLimits(t *testing.T) {
+	// The guest needs two host calls to create its score.
+	tf := newSandboxFixture(t, sandbox.Limits{HostCalls: 1})
+	tf.DoTick()
+	tf.DoTick()
+	assert.Equal(t, len(scores(t, tf)), 0)
+}
+
+func TestSandboxedSystemMustExportRun(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	err := tf.World.UseModule(sandbox.NewModule(sandbox.System{Name: "empty", WASM: []byte("\x00asm\x01\x00\x00\x00")}))
+	assert.ErrorContains(t, err, "doesn't export a run function")
+}
@@ -0,0 +1,394 @@
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"github.com/rotisserie/eris"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+var (
+	ErrLimitExceeded = errors.New("sandboxed system exceeded its limits")
+	ErrOutOfBounds   = errors.New("sandboxed system passed memory that is out of bounds")
+)
+
+const (
+	hostModuleName = "cardinal"
+	runFunction    = "run"
+	memoryExport   = "memory"
+
+	// pendingEntityBit marks the temporary IDs of the entities that are created during a run. Real entity IDs never get
+	// this high, and the bit keeps temporary IDs positive, so they can't be mistaken for error codes.
+	pendingEntityBit types.EntityID = 1 << 62
+)
+
+// sandboxedSystem is a compiled System, with the runtime that it runs in.
+type sandboxedSystem struct {
+	name       string
+	components map[string]bool
+	limits     Limits
+	runtime    wazero.Runtime
+	compiled   wazero.CompiledModule
+}
+
+func newSandboxedSystem(ctx context.Context, s System) (*sandboxedSystem, error) {
+	sys := &sandboxedSystem{
+		name:       s.Name,
+		components: map[string]bool{},
+		limits:     s.Limits.withDefaults(),
+	}
+	for _, name := range s.Components {
+		if name == (Owner{}).Name() {
+			return nil, eris.New("the owner component can't be granted to sandboxed systems")
+		}
+		sys.components[name] = true
+	}
+
+	sys.runtime = wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(sys.limits.MemoryPages).
+		WithCloseOnContextDone(true))
+	_, err := sys.runtime.NewHostModuleBuilder(hostModuleName).
+		NewFunctionBuilder().WithFunc(hostTick).Export("tick").
+		NewFunctionBuilder().WithFunc(hostLog).Export("log").
+		NewFunctionBuilder().WithFunc(hostSearch).Export("search").
+		NewFunctionBuilder().WithFunc(hostCreateEntity).Export("create_entity").
+		NewFunctionBuilder().WithFunc(hostGetComponent).Export("get_component").
+		NewFunctionBuilder().WithFunc(hostSetComponent).Export("set_component").
+		NewFunctionBuilder().WithFunc(hostEmitEvent).Export("emit_event").
+		Instantiate(ctx)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to instantiate the host API")
+	}
+
+	sys.compiled, err = sys.runtime.CompileModule(ctx, s.WASM)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to compile module")
+	}
+	if _, ok := sys.compiled.ExportedFunctions()[runFunction]; !ok {
+		return nil, eris.Errorf("module doesn't export a %s function", runFunction)
+	}
+	if _, ok := sys.compiled.ExportedMemories()[memoryExport]; !ok {
+		return nil, eris.Errorf("module doesn't export its memory as %q", memoryExport)
+	}
+	return sys, nil
+}
+
+// run runs the guest for a tick, and applies its changes if it succeeds. The guest's own failures are logged rather
+// than returned, so that an untrusted system can't fail the world's ticks.
+func (s *sandboxedSystem) run(wCtx engine.Context) error {
+	state := &runState{
+		wCtx:   wCtx,
+		sys:    s,
+		values: map[entityComponent]json.RawMessage{},
+	}
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), runStateKey{}, state), s.limits.Timeout)
+	defer cancel()
+
+	if err := s.call(ctx); err != nil {
+		wCtx.Logger().Warn().Err(err).Str("system", s.name).Msg("sandboxed system failed; its changes were discarded")
+		return nil
+	}
+	return state.apply()
+}
+
+func (s *sandboxedSystem) call(ctx context.Context) error {
+	// The start functions are skipped, since a guest that was built as a command would run its main function.
+	mod, err := s.runtime.InstantiateModule(ctx, s.compiled, wazero.NewModuleConfig().WithName("").WithStartFunctions())
+	if err != nil {
+		return eris.Wrap(err, "failed to instantiate module")
+	}
+	defer mod.Close(ctx)
+	_, err = mod.ExportedFunction(runFunction).Call(ctx)
+	return eris.Wrapf(err, "%s failed", runFunction)
+}
+
+type runStateKey struct{}
+
+type entityComponent struct {
+	id   types.EntityID
+	name string
+}
+
+type pendingWrite struct {
+	id    types.EntityID
+	comp  types.ComponentMetadata
+	value types.Component
+}
+
+// runState is the state of a single run of a guest: the changes that it made, which are applied once it returns, and
+// the resources that it used.
+type runState struct {
+	wCtx engine.Context
+	sys  *sandboxedSystem
+
+	calls   int
+	created int
+	writes  []pendingWrite
+	values  map[entityComponent]json.RawMessage
+	events  []map[string]any
+
+	// owned are the existing entities that the system created in earlier ticks, in order of their IDs. They are
+	// looked up on first use.
+	owned    []types.EntityID
+	ownedSet map[types.EntityID]bool
+}
+
+func getRunState(ctx context.Context) *runState {
+	state := ctx.Value(runStateKey{}).(*runState)
+	// Panicking makes the runtime stop the guest, and return the error from its run function.
+	state.calls++
+	if state.calls > state.sys.limits.HostCalls {
+		panic(eris.Wrapf(ErrLimitExceeded, "more than %d host calls", state.sys.limits.HostCalls))
+	}
+	return state
+}
+
+func read(m api.Module, ptr, length uint32) []byte {
+	bz, ok := m.Memory().Read(ptr, length)
+	if !ok {
+		panic(eris.Wrap(ErrOutOfBounds, ""))
+	}
+	return bytes.Clone(bz)
+}
+
+func write(m api.Module, ptr uint32, bz []byte) {
+	if !m.Memory().Write(ptr, bz) {
+		panic(eris.Wrap(ErrOutOfBounds, ""))
+	}
+}
+
+func hostTick(ctx context.Context) uint64 {
+	return getRunState(ctx).wCtx.CurrentTick()
+}
+
+func hostLog(ctx context.Context, m api.Module, ptr, length uint32) {
+	state := getRunState(ctx)
+	state.wCtx.Logger().Info().Str("system", state.sys.name).Msg(string(read(m, ptr, length)))
+}
+
+func hostSearch(ctx context.Context, m api.Module, namePtr, nameLen, outPtr, outCap uint32) int32 {
+	state := getRunState(ctx)
+	comp, code := state.component(string(read(m, namePtr, nameLen)))
+	if code != 0 {
+		return code
+	}
+	ids := state.entitiesWith(comp)
+	out := make([]byte, 0, 8*len(ids))
+	for _, id := range ids {
+		out = binary.LittleEndian.AppendUint64(out, uint64(id))
+	}
+	if len(out) <= int(outCap) {
+		write(m, outPtr, out)
+	}
+	return int32(len(ids))
+}
+
+func hostCreateEntity(ctx context.Context, m api.Module, namePtr, nameLen, valuePtr, valueLen uint32) int64 {
+	state := getRunState(ctx)
+	if state.created >= state.sys.limits.Entities {
+		panic(eris.Wrapf(ErrLimitExceeded, "more than %d entities created", state.sys.limits.Entities))
+	}
+	comp, code := state.component(string(read(m, namePtr, nameLen)))
+	if code != 0 {
+		return int64(code)
+	}
+	id := pendingEntityBit | types.EntityID(state.created)
+	if code := state.set(id, comp, read(m, valuePtr, valueLen)); code != 0 {
+		return int64(code)
+	}
+	state.created++
+	return int64(id)
+}
+
+func hostGetComponent(ctx context.Context, m api.Module, entity uint64, namePtr, nameLen, outPtr, outCap uint32) int32 {
+	state := getRunState(ctx)
+	comp, code := state.component(string(read(m, namePtr, nameLen)))
+	if code != 0 {
+		return code
+	}
+	value, code := state.get(types.EntityID(entity), comp)
+	if code != 0 {
+		return code
+	}
+	if len(value) <= int(outCap) {
+		write(m, outPtr, value)
+	}
+	return int32(len(value))
+}
+
+func hostSetComponent(
+	ctx context.Context, m api.Module, entity uint64, namePtr, nameLen, valuePtr, valueLen uint32,
+) int32 {
+	state := getRunState(ctx)
+	comp, code := state.component(string(read(m, namePtr, nameLen)))
+	if code != 0 {
+		return code
+	}
+	id := types.EntityID(entity)
+	if !state.owns(id) {
+		return ErrCodeNotFound
+	}
+	return state.set(id, comp, read(m, valuePtr, valueLen))
+}
+
+func hostEmitEvent(ctx context.Context, m api.Module, ptr, length uint32) int32 {
+	state := getRunState(ctx)
+	var event map[string]any
+	if err := json.Unmarshal(read(m, ptr, length), &event); err != nil || event == nil {
+		return ErrCodeInvalid
+	}
+	// Events are tagged with the system, so that clients can tell them apart from the world's own events.
+	event["sandbox"] = state.sys.name
+	state.events = append(state.events, event)
+	return 0
+}
+
+// component returns the metadata of the named component, if it was granted to the system.
+func (r *runState) component(name string) (types.ComponentMetadata, int32) {
+	if !r.sys.components[name] {
+		return nil, ErrCodeDenied
+	}
+	comp, err := r.wCtx.GetComponentByName(name)
+	if err != nil {
+		return nil, ErrCodeDenied
+	}
+	return comp, 0
+}
+
+func (r *runState) loadOwned() {
+	if r.ownedSet != nil {
+		return
+	}
+	r.ownedSet = map[types.EntityID]bool{}
+	err := cardinal.NewSearch().Entity(filter.Contains(filter.Component[Owner]())).Each(r.wCtx,
+		func(id types.EntityID) bool {
+			owner, err := cardinal.GetComponent[Owner](r.wCtx, id)
+			if err == nil && owner.System == r.sys.name {
+				r.owned = append(r.owned, id)
+				r.ownedSet[id] = true
+			}
+			return true
+		})
+	if err != nil {
+		panic(eris.Wrap(err, "failed to look up the system's entities"))
+	}
+	slices.Sort(r.owned)
+}
+
+func (r *runState) owns(id types.EntityID) bool {
+	if id&pendingEntityBit != 0 {
+		return id&^pendingEntityBit < types.EntityID(r.created)
+	}
+	r.loadOwned()
+	return r.ownedSet[id]
+}
+
+// entitiesWith returns the system's entities that have the component, including the ones that were created or given
+// the component during the run.
+func (r *runState) entitiesWith(comp types.ComponentMetadata) []types.EntityID {
+	r.loadOwned()
+	var ids []types.EntityID
+	for _, id := range r.owned {
+		if _, ok := r.values[entityComponent{id, comp.Name()}]; ok {
+			ids = append(ids, id)
+			continue
+		}
+		comps, err := r.wCtx.StoreReader().GetComponentTypesForEntity(id)
+		if err != nil {
+			panic(eris.Wrapf(err, "failed to get the components of entity %d", id))
+		}
+		if slices.ContainsFunc(comps, func(c types.ComponentMetadata) bool { return c.Name() == comp.Name() }) {
+			ids = append(ids, id)
+		}
+	}
+	for i := 0; i < r.created; i++ {
+		id := pendingEntityBit | types.EntityID(i)
+		if _, ok := r.values[entityComponent{id, comp.Name()}]; ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (r *runState) get(id types.EntityID, comp types.ComponentMetadata) (json.RawMessage, int32) {
+	if !r.owns(id) {
+		return nil, ErrCodeNotFound
+	}
+	if value, ok := r.values[entityComponent{id, comp.Name()}]; ok {
+		return value, 0
+	}
+	if id&pendingEntityBit != 0 {
+		return nil, ErrCodeNotFound
+	}
+	value, err := r.wCtx.StoreReader().GetComponentForEntityInRawJSON(comp, id)
+	if err != nil {
+		return nil, ErrCodeNotFound
+	}
+	return value, 0
+}
+
+func (r *runState) set(id types.EntityID, comp types.ComponentMetadata, raw []byte) int32 {
+	value, err := comp.Decode(raw)
+	if err != nil {
+		return ErrCodeInvalid
+	}
+	encoded, err := comp.Encode(value)
+	if err != nil {
+		return ErrCodeInvalid
+	}
+	r.writes = append(r.writes, pendingWrite{id: id, comp: comp, value: value})
+	r.values[entityComponent{id, comp.Name()}] = encoded
+	return 0
+}
+
+// apply makes the changes of a run that succeeded.
+func (r *runState) apply() error {
+	created := make([]types.EntityID, r.created)
+	for i := range created {
+		id, err := cardinal.Create(r.wCtx, Owner{System: r.sys.name})
+		if err != nil {
+			return eris.Wrapf(err, "failed to create an entity for sandboxed system %q", r.sys.name)
+		}
+		created[i] = id
+	}
+	for _, w := range r.writes {
+		id := w.id
+		if id&pendingEntityBit != 0 {
+			id = created[id&^pendingEntityBit]
+		}
+		if err := setComponent(r.wCtx, id, w.comp, w.value); err != nil {
+			return eris.Wrapf(err, "failed to apply a change of sandboxed system %q", r.sys.name)
+		}
+	}
+	for _, event := range r.events {
+		if err := r.wCtx.EmitEvent(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setComponent sets the entity's value for the component, adding the component to the entity if it doesn't have it.
+func setComponent(wCtx engine.Context, id types.EntityID, comp types.ComponentMetadata, value types.Component) error {
+	comps, err := wCtx.StoreReader().GetComponentTypesForEntity(id)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(comps, func(c types.ComponentMetadata) bool { return c.Name() == comp.Name() }) {
+		if err := wCtx.StoreManager().AddComponentToEntity(comp, id); err != nil {
+			return err
+		}
+	}
+	return wCtx.StoreManager().SetComponentForEntity(comp, id, value)
+}
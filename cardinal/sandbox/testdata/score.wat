@@ -0,0 +1,35 @@
+;; score.wasm is assembled from this module. On its first run it creates an entity with a score of 1, on its second
+;; run it sets the score to 2, and on every later run it sets the score to 3 and traps, so the change is discarded.
+(module
+  (import "cardinal" "search" (func $search (param i32 i32 i32 i32) (result i32)))
+  (import "cardinal" "create_entity" (func $create_entity (param i32 i32 i32 i32) (result i64)))
+  (import "cardinal" "get_component" (func $get_component (param i64 i32 i32 i32 i32) (result i32)))
+  (import "cardinal" "set_component" (func $set_component (param i64 i32 i32 i32 i32) (result i32)))
+  (memory (export "memory") 1)
+  (data (i32.const 0) "sandbox-test-score")
+  (data (i32.const 32) "health")
+  (data (i32.const 64) "{\"Points\":1}")
+  (data (i32.const 80) "{\"Points\":2}")
+  (data (i32.const 96) "{\"Points\":3}")
+
+  (func (export "run")
+    (local $id i64)
+    (if (i32.eqz (call $search (i32.const 0) (i32.const 18) (i32.const 256) (i32.const 8)))
+      (then
+        (drop (call $create_entity (i32.const 0) (i32.const 18) (i32.const 64) (i32.const 12)))
+        (return)))
+    (local.set $id (i64.load (i32.const 256)))
+
+    ;; The health component was not granted to this system.
+    (if (i32.ne (call $get_component (local.get $id) (i32.const 32) (i32.const 6) (i32.const 512) (i32.const 64))
+                (i32.const -2))
+      (then (unreachable)))
+
+    ;; The score is {"Points":N}, so N is the 11th byte.
+    (drop (call $get_component (local.get $id) (i32.const 0) (i32.const 18) (i32.const 512) (i32.const 64)))
+    (if (i32.eq (i32.load8_u (i32.const 522)) (i32.const 49))
+      (then
+        (drop (call $set_component (local.get $id) (i32.const 0) (i32.const 18) (i32.const 80) (i32.const 12)))
+        (return)))
+    (drop (call $set_component (local.get $id) (i32.const 0) (i32.const 18) (i32.const 96) (i32.const 12)))
+    (unreachable)))
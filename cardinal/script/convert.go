@@ -0,0 +1,101 @@
+package script
+
+import (
+	"encoding/json"
+
+	"github.com/rotisserie/eris"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// toJSON encodes a Lua value as JSON. Tables whose keys are 1 to n become arrays, other tables become objects, and
+// empty tables become null, which decodes into empty slices, maps and structs alike.
+func toJSON(lv lua.LValue) ([]byte, error) {
+	v, err := toGo(lv, 0)
+	if err != nil {
+		return nil, err
+	}
+	bz, err := json.Marshal(v)
+	return bz, eris.Wrap(err, "")
+}
+
+// maxDepth stops tables that contain themselves.
+const maxDepth = 64
+
+func toGo(lv lua.LValue, depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, eris.New("tables are nested too deeply")
+	}
+	switch v := lv.(type) {
+	case *lua.LNilType:
+		return nil, nil
+	case lua.LBool:
+		return bool(v), nil
+	case lua.LNumber:
+		return float64(v), nil
+	case lua.LString:
+		return string(v), nil
+	case *lua.LTable:
+		n := v.MaxN()
+		count := 0
+		v.ForEach(func(lua.LValue, lua.LValue) { count++ })
+		if count == 0 {
+			return nil, nil
+		}
+		if n == count {
+			arr := make([]any, 0, n)
+			for i := 1; i <= n; i++ {
+				elem, err := toGo(v.RawGetInt(i), depth+1)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, elem)
+			}
+			return arr, nil
+		}
+		obj := make(map[string]any, count)
+		var err error
+		v.ForEach(func(k, val lua.LValue) {
+			if err != nil {
+				return
+			}
+			obj[k.String()], err = toGo(val, depth+1)
+		})
+		return obj, err
+	default:
+		return nil, eris.Errorf("%s values can't be encoded", lv.Type())
+	}
+}
+
+// fromJSON decodes JSON into a Lua value.
+func fromJSON(L *lua.LState, bz []byte) (lua.LValue, error) {
+	var v any
+	if err := json.Unmarshal(bz, &v); err != nil {
+		return lua.LNil, eris.Wrap(err, "")
+	}
+	return toLua(L, v), nil
+}
+
+func toLua(L *lua.LState, v any) lua.LValue {
+	switch v := v.(type) {
+	case bool:
+		return lua.LBool(v)
+	case float64:
+		return lua.LNumber(v)
+	case string:
+		return lua.LString(v)
+	case []any:
+		tbl := L.CreateTable(len(v), 0)
+		for _, elem := range v {
+			tbl.Append(toLua(L, elem))
+		}
+		return tbl
+	case map[string]any:
+		tbl := L.CreateTable(0, len(v))
+		for k, elem := range v {
+			tbl.RawSetString(k, toLua(L, elem))
+		}
+		return tbl
+	default:
+		return lua.LNil
+	}
+}
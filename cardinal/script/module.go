@@ -0,0 +1,192 @@
+// Package script is a module that runs systems written in Lua, so that designers can prototype systems without
+// recompiling the game:
+//
+//	err = world.UseModule(script.NewModule("scripts", script.WithHotReload()))
+//
+// Every .lua file in the directory is a system. The file returns the function that is called once per tick; the
+// scripts run in the order of their file names, after the systems that were registered before the module:
+//
+//	return function()
+//		cardinal.each_tx("game.heal", function(tx)
+//			local health = cardinal.get(tx.msg.Target, "health")
+//			health.HP = health.HP + 10
+//			cardinal.set(tx.msg.Target, "health", health)
+//			return { HP = health.HP }
+//		end)
+//	end
+//
+// Scripts use the world through the cardinal table, in which component values, messages, results and events are Lua
+// tables with the same fields as their JSON encoding:
+//
+//	cardinal.tick()                      -- the current tick
+//	cardinal.log(message)                -- logs a message; print does the same
+//	cardinal.get(entity, name)           -- the entity's value for the component, or nil
+//	cardinal.set(entity, name, value)    -- sets the value, adding the component to the entity if needed
+//	cardinal.create({ [name] = value })  -- creates an entity with the components, and returns its ID
+//	cardinal.search(name, ...)           -- the IDs of the entities that have all the components
+//	cardinal.each_tx(message, fn)        -- calls fn with each transaction of the message, such as "game.heal"
+//	cardinal.emit(event)                 -- emits an event
+//
+// each_tx passes fn a table with the transaction's hash, persona and msg. What fn returns becomes the transaction's
+// result; if it returns nil and an error message, or raises an error, the transaction fails instead. Errors that a
+// script raises outside of each_tx fail the tick, like the errors of Go systems.
+//
+// Scripts only get Lua's base, string, table and math libraries, without math.random, so that every replica runs them
+// in the same way. Their sources are recorded in the world's config hash (see cardinal.World.ConfigHash), so replicas
+// and replays that run different scripts can be told apart. With hot reload, the directory is checked for changes at
+// the start of every tick; if a changed script fails to load, the error is logged and the old scripts keep running.
+package script
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+const (
+	ModuleName    = "script"
+	ModuleVersion = "v1.0.0"
+)
+
+var _ cardinal.Module = &Module{}
+
+type Module struct {
+	cardinal.ModuleBase
+	dir       string
+	hotReload bool
+
+	world   *cardinal.World
+	sources []source
+	runtime *runtime
+	// rejected are the sources that last failed to reload, so that they are only reported once.
+	rejected []source
+}
+
+type Option func(*Module)
+
+// WithHotReload makes the module check the script directory for changes at the start of every tick, and reload the
+// scripts when they change.
+func WithHotReload() Option {
+	return func(m *Module) {
+		m.hotReload = true
+	}
+}
+
+// NewModule returns a module that runs the Lua scripts in dir.
+func NewModule(dir string, opts ...Option) *Module {
+	m := &Module{dir: dir}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func (*Module) Name() string    { return ModuleName }
+func (*Module) Version() string { return ModuleVersion }
+
+// RegisterSystems loads the scripts, and registers a system that runs them.
+func (m *Module) RegisterSystems(w *cardinal.World) error {
+	m.world = w
+	sources, err := readSources(m.dir)
+	if err != nil {
+		return err
+	}
+	if err := m.load(sources); err != nil {
+		return err
+	}
+	return cardinal.RegisterSystems(w, m.runScripts)
+}
+
+func (m *Module) runScripts(wCtx engine.Context) error {
+	if m.hotReload {
+		m.reload(wCtx)
+	}
+	return m.runtime.run(wCtx)
+}
+
+// source is the source of a script.
+type source struct {
+	name string
+	code []byte
+}
+
+// readSources reads the .lua files in dir, in the order of their names.
+func readSources(dir string) ([]source, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to read the script directory")
+	}
+	var sources []source
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".lua") {
+			continue
+		}
+		code, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, eris.Wrapf(err, "failed to read script %s", entry.Name())
+		}
+		sources = append(sources, source{name: entry.Name(), code: code})
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].name < sources[j].name })
+	return sources, nil
+}
+
+// load compiles the scripts, and replaces the running ones with them if they all compile.
+func (m *Module) load(sources []source) error {
+	rt, err := newRuntime(m.world, sources)
+	if err != nil {
+		return err
+	}
+	if m.runtime != nil {
+		m.runtime.close()
+	}
+	m.runtime = rt
+	m.sources = sources
+
+	var record bytes.Buffer
+	for _, s := range sources {
+		record.WriteString(s.name)
+		record.WriteByte(0)
+		record.Write(s.code)
+		record.WriteByte(0)
+	}
+	m.world.RecordConfig(ModuleName, record.Bytes())
+	return nil
+}
+
+func (m *Module) reload(wCtx engine.Context) {
+	sources, err := readSources(m.dir)
+	if err != nil {
+		wCtx.Logger().Warn().Err(err).Msg("failed to check the scripts for changes")
+		return
+	}
+	if sameSources(sources, m.sources) || sameSources(sources, m.rejected) {
+		return
+	}
+	if err := m.load(sources); err != nil {
+		m.rejected = sources
+		wCtx.Logger().Warn().Err(err).Msg("failed to reload the scripts; the old scripts keep running")
+		return
+	}
+	m.rejected = nil
+	wCtx.Logger().Info().Int("scripts", len(sources)).Msg("reloaded the scripts")
+}
+
+func sameSources(a, b []source) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].name != b[i].name || !bytes.Equal(a[i].code, b[i].code) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,277 @@
+package script
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	lua "github.com/yuin/gopher-lua"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// runtime is a Lua state with the scripts loaded into it.
+type runtime struct {
+	world   *cardinal.World
+	state   *lua.LState
+	systems []*lua.LFunction
+	names   []string
+
+	// wCtx is the context of the tick that the scripts are running in.
+	wCtx engine.Context
+}
+
+func newRuntime(world *cardinal.World, sources []source) (*runtime, error) {
+	rt := &runtime{world: world, state: lua.NewState(lua.Options{SkipOpenLibs: true})}
+	rt.openLibs()
+	for _, s := range sources {
+		chunk, err := rt.state.Load(bytes.NewReader(s.code), s.name)
+		if err != nil {
+			rt.close()
+			return nil, eris.Wrapf(err, "failed to load script %s", s.name)
+		}
+		err = rt.state.CallByParam(lua.P{Fn: chunk, NRet: 1, Protect: true})
+		if err != nil {
+			rt.close()
+			return nil, eris.Wrapf(err, "failed to load script %s", s.name)
+		}
+		fn, ok := rt.state.Get(-1).(*lua.LFunction)
+		rt.state.Pop(1)
+		if !ok {
+			rt.close()
+			return nil, eris.Errorf("script %s must return the function of its system", s.name)
+		}
+		rt.systems = append(rt.systems, fn)
+		rt.names = append(rt.names, s.name)
+	}
+	return rt, nil
+}
+
+// openLibs opens the libraries that behave in the same way on every replica, and the cardinal table.
+func (rt *runtime) openLibs() {
+	L := rt.state
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		L.Push(L.NewFunction(lib.open))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	if math, ok := L.GetGlobal(lua.MathLibName).(*lua.LTable); ok {
+		math.RawSetString("random", lua.LNil)
+		math.RawSetString("randomseed", lua.LNil)
+	}
+	for _, name := range []string{"dofile", "loadfile"} {
+		L.SetGlobal(name, lua.LNil)
+	}
+
+	L.SetGlobal("print", L.NewFunction(rt.log))
+	L.SetGlobal("cardinal", L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"tick":    rt.tick,
+		"log":     rt.log,
+		"get":     rt.get,
+		"set":     rt.set,
+		"create":  rt.create,
+		"search":  rt.search,
+		"each_tx": rt.eachTx,
+		"emit":    rt.emit,
+	}))
+}
+
+func (rt *runtime) close() {
+	rt.state.Close()
+}
+
+func (rt *runtime) run(wCtx engine.Context) error {
+	rt.wCtx = wCtx
+	defer func() { rt.wCtx = nil }()
+	for i, fn := range rt.systems {
+		if err := rt.state.CallByParam(lua.P{Fn: fn, NRet: 0, Protect: true}); err != nil {
+			return eris.Wrapf(err, "script %s failed", rt.names[i])
+		}
+	}
+	return nil
+}
+
+func (rt *runtime) tick(L *lua.LState) int {
+	L.Push(lua.LNumber(rt.wCtx.CurrentTick()))
+	return 1
+}
+
+func (rt *runtime) log(L *lua.LState) int {
+	parts := make([]string, 0, L.GetTop())
+	for i := 1; i <= L.GetTop(); i++ {
+		parts = append(parts, L.ToStringMeta(L.Get(i)).String())
+	}
+	rt.wCtx.Logger().Info().Str("module", ModuleName).Msg(strings.Join(parts, " "))
+	return 0
+}
+
+func (rt *runtime) component(L *lua.LState, n int) types.ComponentMetadata {
+	comp, err := rt.wCtx.GetComponentByName(L.CheckString(n))
+	if err != nil {
+		L.ArgError(n, err.Error())
+	}
+	return comp
+}
+
+func checkEntity(L *lua.LState, n int) types.EntityID {
+	return types.EntityID(L.CheckNumber(n))
+}
+
+func (rt *runtime) get(L *lua.LState) int {
+	id, comp := checkEntity(L, 1), rt.component(L, 2)
+	bz, err := rt.wCtx.StoreReader().GetComponentForEntityInRawJSON(comp, id)
+	if err != nil {
+		L.Push(lua.LNil)
+		return 1
+	}
+	value, err := fromJSON(L, bz)
+	if err != nil {
+		L.RaiseError("failed to decode %s: %s", comp.Name(), err)
+	}
+	L.Push(value)
+	return 1
+}
+
+func (rt *runtime) set(L *lua.LState) int {
+	id, comp := checkEntity(L, 1), rt.component(L, 2)
+	value := rt.decodeComponent(L, comp, L.Get(3))
+	comps, err := rt.wCtx.StoreReader().GetComponentTypesForEntity(id)
+	if err != nil {
+		L.RaiseError("entity %d: %s", id, err)
+	}
+	if !slices.ContainsFunc(comps, func(c types.ComponentMetadata) bool { return c.Name() == comp.Name() }) {
+		if err := rt.wCtx.StoreManager().AddComponentToEntity(comp, id); err != nil {
+			L.RaiseError("entity %d: %s", id, err)
+		}
+	}
+	if err := rt.wCtx.StoreManager().SetComponentForEntity(comp, id, value); err != nil {
+		L.RaiseError("entity %d: %s", id, err)
+	}
+	return 0
+}
+
+func (rt *runtime) create(L *lua.LState) int {
+	tbl := L.CheckTable(1)
+	// Components are created in the order of their names, so that the entity is the same on every replica.
+	var names []string
+	tbl.ForEach(func(k, _ lua.LValue) {
+		names = append(names, k.String())
+	})
+	slices.Sort(names)
+	values := make([]types.Component, 0, len(names))
+	for _, name := range names {
+		comp, err := rt.wCtx.GetComponentByName(name)
+		if err != nil {
+			L.ArgError(1, err.Error())
+		}
+		values = append(values, rt.decodeComponent(L, comp, tbl.RawGetString(name)))
+	}
+	id, err := cardinal.Create(rt.wCtx, values...)
+	if err != nil {
+		L.RaiseError("failed to create entity: %s", err)
+	}
+	L.Push(lua.LNumber(id))
+	return 1
+}
+
+func (rt *runtime) decodeComponent(L *lua.LState, comp types.ComponentMetadata, lv lua.LValue) types.Component {
+	bz, err := toJSON(lv)
+	if err != nil {
+		L.RaiseError("invalid value for %s: %s", comp.Name(), err)
+	}
+	value, err := comp.Decode(bz)
+	if err != nil {
+		L.RaiseError("invalid value for %s: %s", comp.Name(), err)
+	}
+	return value
+}
+
+func (rt *runtime) search(L *lua.LState) int {
+	comps := make([]types.ComponentMetadata, 0, L.GetTop())
+	for i := 1; i <= L.GetTop(); i++ {
+		comps = append(comps, rt.component(L, i))
+	}
+	if len(comps) == 0 {
+		L.ArgError(1, "at least one component is needed")
+	}
+	ids := L.NewTable()
+	err := cardinal.NewSearch().
+		Entity(filter.Contains(filter.ConvertComponentMetadatasToComponentWrappers(comps)...)).
+		Each(rt.wCtx, func(id types.EntityID) bool {
+			ids.Append(lua.LNumber(id))
+			return true
+		})
+	if err != nil {
+		L.RaiseError("search failed: %s", err)
+	}
+	L.Push(ids)
+	return 1
+}
+
+func (rt *runtime) eachTx(L *lua.LState) int {
+	name, fn := L.CheckString(1), L.CheckFunction(2)
+	msg, ok := rt.world.GetMessageByFullName(name)
+	if !ok {
+		L.ArgError(1, "no message named "+name)
+	}
+	for _, tx := range rt.wCtx.GetTxPool().ForID(msg.ID()) {
+		in, err := msg.Encode(tx.Msg)
+		if err != nil {
+			L.RaiseError("failed to encode %s: %s", name, err)
+		}
+		txMsg, err := fromJSON(L, in)
+		if err != nil {
+			L.RaiseError("failed to encode %s: %s", name, err)
+		}
+		txTable := L.NewTable()
+		txTable.RawSetString("hash", lua.LString(tx.TxHash))
+		txTable.RawSetString("persona", lua.LString(tx.Tx.PersonaTag))
+		txTable.RawSetString("msg", txMsg)
+
+		if err := L.CallByParam(lua.P{Fn: fn, NRet: 2, Protect: true}, txTable); err != nil {
+			rt.wCtx.AddMessageError(tx.TxHash, eris.Wrap(err, ""))
+			continue
+		}
+		result, failure := L.Get(-2), L.Get(-1)
+		L.Pop(2)
+		if result == lua.LNil && failure != lua.LNil {
+			rt.wCtx.AddMessageError(tx.TxHash, eris.New(failure.String()))
+			continue
+		}
+		bz, err := toJSON(result)
+		if err != nil {
+			rt.wCtx.AddMessageError(tx.TxHash, eris.Wrap(err, "invalid result"))
+			continue
+		}
+		rt.wCtx.SetMessageResult(tx.TxHash, json.RawMessage(bz))
+	}
+	return 0
+}
+
+func (rt *runtime) emit(L *lua.LState) int {
+	bz, err := toJSON(L.CheckTable(1))
+	if err != nil {
+		L.ArgError(1, err.Error())
+	}
+	var event map[string]any
+	if err := json.Unmarshal(bz, &event); err != nil || event == nil {
+		L.ArgError(1, "events must be tables with named fields")
+	}
+	if err := rt.wCtx.EmitEvent(event); err != nil {
+		L.RaiseError("failed to emit event: %s", err)
+	}
+	return 0
+}
@@ -0,0 +1,119 @@
+package script_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/script"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type Health struct {
+	HP int
+}
+
+func (Health) Name() string { return "health" }
+
+type HealMsg struct {
+	Target types.EntityID
+	Amount int
+}
+
+type HealResult struct {
+	HP int
+}
+
+const healScript = `
+return function()
+	cardinal.each_tx("game.heal", function(tx)
+		local health = cardinal.get(tx.msg.Target, "health")
+		if health == nil then
+			return nil, "no such entity"
+		end
+		health.HP = health.HP + tx.msg.Amount * %d
+		cardinal.set(tx.msg.Target, "health", health)
+		return { HP = health.HP }
+	end)
+end
+`
+
+func writeScript(t *testing.T, dir, name, code string) {
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, name), []byte(code), 0o600))
+}
+
+func setupScripts(t *testing.T, dir string, opts ...script.Option) (*testutils.TestFixture, types.EntityID) {
+	tf := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, cardinal.RegisterComponent[Health](tf.World))
+	assert.NilError(t, cardinal.RegisterMessage[HealMsg, HealResult](tf.World, "heal"))
+	assert.NilError(t, tf.World.UseModule(script.NewModule(dir, opts...)))
+
+	var id types.EntityID
+	assert.NilError(t, cardinal.RegisterInitSystems(tf.World, func(wCtx engine.Context) error {
+		var err error
+		id, err = cardinal.Create(wCtx, Health{HP: 10})
+		return err
+	}))
+	tf.DoTick()
+	return tf, id
+}
+
+func heal(t *testing.T, tf *testutils.TestFixture, msg HealMsg) (json.RawMessage, []error) {
+	healMsg, ok := tf.World.GetMessageByFullName("game.heal")
+	assert.Check(t, ok)
+	hash := tf.AddTransaction(healMsg.ID(), msg)
+	tf.DoTick()
+	result, errs, ok := cardinal.NewReadOnlyWorldContext(tf.World).GetTransactionReceipt(hash)
+	assert.Check(t, ok)
+	raw, _ := result.(json.RawMessage)
+	return raw, errs
+}
+
+func health(t *testing.T, tf *testutils.TestFixture, id types.EntityID) int {
+	h, err := cardinal.GetComponent[Health](cardinal.NewReadOnlyWorldContext(tf.World), id)
+	assert.NilError(t, err)
+	return h.HP
+}
+
+func TestScriptHandlesTransactions(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "heal.lua", fmt.Sprintf(healScript, 1))
+	tf, id := setupScripts(t, dir)
+
+	result, errs := heal(t, tf, HealMsg{Target: id, Amount: 5})
+	assert.Equal(t, len(errs), 0)
+	assert.Equal(t, string(result), `{"HP":15}`)
+	assert.Equal(t, health(t, tf, id), 15)
+
+	_, errs = heal(t, tf, HealMsg{Target: id + 100, Amount: 5})
+	assert.Equal(t, len(errs), 1)
+	assert.ErrorContains(t, errs[0], "no such entity")
+}
+
+func TestScriptsAreHotReloaded(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "heal.lua", fmt.Sprintf(healScript, 1))
+	tf, id := setupScripts(t, dir, script.WithHotReload())
+	hash := tf.World.ConfigHash()
+
+	// The changed script is picked up in the next tick, and changes the config hash.
+	writeScript(t, dir, "heal.lua", fmt.Sprintf(healScript, 2))
+	_, errs := heal(t, tf, HealMsg{Target: id, Amount: 5})
+	assert.Equal(t, len(errs), 0)
+	assert.Equal(t, health(t, tf, id), 20)
+	assert.Check(t, tf.World.ConfigHash() != hash)
+
+	// A script that doesn't load leaves the old ones running.
+	hash = tf.World.ConfigHash()
+	writeScript(t, dir, "heal.lua", "return function(")
+	_, errs = heal(t, tf, HealMsg{Target: id, Amount: 5})
+	assert.Equal(t, len(errs), 0)
+	assert.Equal(t, health(t, tf, id), 30)
+	assert.Equal(t, tf.World.ConfigHash(), hash)
+}
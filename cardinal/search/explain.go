@@ -0,0 +1,129 @@
+package search
+
+import (
+	"fmt"
+
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// Explanation describes how a search is evaluated against the current state, to help find out why a search is slow.
+type Explanation struct {
+	// Kind is "search" for a search created with NewSearch, or "and", "or" or "not" for a composed search.
+	Kind string `json:"kind"`
+	// Filter is the signature of the search's component filter. It is empty for composed searches.
+	Filter string `json:"filter,omitempty"`
+	// Cached reports whether the archetypes that match the filter are cached between searches, so that only new
+	// archetypes are checked. Custom filters aren't cached, so every search checks every archetype against them.
+	Cached bool `json:"cached"`
+	// Index is the component field whose index the search looks up the entities in, as "component.Field". Only
+	// the entities in the index are checked against the search, instead of every entity of the matching archetypes.
+	Index string `json:"index,omitempty"`
+	// Where reports whether the search has Where clauses, which decode a component of every entity of the matching
+	// archetypes.
+	Where bool `json:"where"`
+	// Archetypes are the archetypes that match the search.
+	Archetypes []ArchetypeExplanation `json:"archetypes"`
+	// TotalArchetypes is the number of archetypes in the state.
+	TotalArchetypes int `json:"totalArchetypes"`
+	// EstimatedEntities is the number of entities in the matching archetypes, or in the index if one is used. It is
+	// exact unless the search has Where clauses or Eq filters, in which case it is an upper bound.
+	EstimatedEntities int `json:"estimatedEntities"`
+	// FullScan reports whether the search visits every entity in the state.
+	FullScan bool `json:"fullScan"`
+	// Searches are the explanations of the searches that a composed search is made of.
+	Searches []Explanation `json:"searches,omitempty"`
+}
+
+// ArchetypeExplanation is an archetype that matches a search.
+type ArchetypeExplanation struct {
+	ID         types.ArchetypeID `json:"id"`
+	Components []string          `json:"components"`
+	Entities   int               `json:"entities"`
+}
+
+// Explain describes how the search is evaluated, without evaluating its Where clauses.
+func (s *Search) Explain(eCtx engine.Context) (Explanation, error) {
+	sig, cached := filter.Signature(s.filter)
+	if !cached {
+		sig = fmt.Sprintf("custom(%T)", s.filter)
+	}
+	e := Explanation{Kind: "search", Filter: sig, Cached: cached, Where: s.componentPropertyFilter != nil}
+	if err := explainArchetypes(eCtx, &e, s.evaluateSearch(eCtx)); err != nil {
+		return e, err
+	}
+	conds, err := filter.Conditions(s.filter)
+	if err != nil {
+		return e, err
+	}
+	ids, indexed, err := lookupIndex(eCtx, conds)
+	if err != nil || indexed == nil {
+		return e, err
+	}
+	e.Index = indexed.Component.Name() + "." + indexed.Field
+	e.EstimatedEntities = min(e.EstimatedEntities, len(ids))
+	e.FullScan = false
+	return e, nil
+}
+
+func (orSearch *OrSearch) Explain(eCtx engine.Context) (Explanation, error) {
+	return explainComposed(eCtx, "or", orSearch.searches, orSearch.evaluateSearch(eCtx))
+}
+
+func (andSearch *AndSearch) Explain(eCtx engine.Context) (Explanation, error) {
+	return explainComposed(eCtx, "and", andSearch.searches, andSearch.evaluateSearch(eCtx))
+}
+
+// Explain describes the search. Not searches collect every entity, and then remove the ones that the negated search
+// matches, so they are always full scans.
+func (notSearch *NotSearch) Explain(eCtx engine.Context) (Explanation, error) {
+	all := NewSearch().Entity(filter.All()).evaluateSearch(eCtx)
+	return explainComposed(eCtx, "not", []Searchable{notSearch.search}, all)
+}
+
+func explainComposed(
+	eCtx engine.Context, kind string, searches []Searchable, archIDs []types.ArchetypeID,
+) (Explanation, error) {
+	e := Explanation{Kind: kind, Cached: true}
+	for _, s := range searches {
+		child, err := s.Explain(eCtx)
+		if err != nil {
+			return e, err
+		}
+		e.Cached = e.Cached && child.Cached
+		e.Where = e.Where || child.Where
+		e.Searches = append(e.Searches, child)
+	}
+	return e, explainArchetypes(eCtx, &e, archIDs)
+}
+
+func explainArchetypes(eCtx engine.Context, e *Explanation, archIDs []types.ArchetypeID) error {
+	reader := eCtx.StoreReader()
+	e.TotalArchetypes = reader.ArchetypeCount()
+	e.Archetypes = make([]ArchetypeExplanation, 0, len(archIDs))
+	seen := map[types.ArchetypeID]bool{}
+	for _, archID := range archIDs {
+		// The archetypes of an or search may match more than one of its searches.
+		if seen[archID] {
+			continue
+		}
+		seen[archID] = true
+		comps, err := reader.GetComponentTypesForArchID(archID)
+		if err != nil {
+			return err
+		}
+		ids, err := reader.GetEntitiesForArchID(archID)
+		if err != nil {
+			return err
+		}
+		names := make([]string, 0, len(comps))
+		for _, c := range comps {
+			names = append(names, c.Name())
+		}
+		e.Archetypes = append(e.Archetypes, ArchetypeExplanation{ID: archID, Components: names, Entities: len(ids)})
+		e.EstimatedEntities += len(ids)
+	}
+	e.FullScan = len(e.Archetypes) == e.TotalArchetypes
+	return nil
+}
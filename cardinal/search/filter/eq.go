@@ -0,0 +1,106 @@
+package filter
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// EqFilter matches the entities whose component has a field that is equal to a value. See Eq.
+type EqFilter struct {
+	Component types.Component
+	Field     string
+	Value     any
+}
+
+// Eq matches the entities that have the component T, and whose field of T with the given name is equal to the value.
+// Values are compared by their JSON encoding, so 5 and int64(5) are equal. Searches with an Eq filter look up the
+// matching entities in the field's index, if one was registered, instead of checking every entity of every archetype
+// with T:
+//
+//	ids, err := cardinal.NewSearch().Entity(filter.Eq[SignerComponent]("PersonaTag", "alice")).Collect(wCtx)
+func Eq[T types.Component](field string, value any) EqFilter {
+	var x T
+	return EqFilter{Component: x, Field: field, Value: value}
+}
+
+// MatchesComponents returns true if the archetype has the filter's component. Whether the field is equal to the value
+// can only be checked for each entity, with Key and FieldKey.
+func (f EqFilter) MatchesComponents(components []types.Component) bool {
+	return CreateComponentMatcher(components)(f.Component)
+}
+
+// Key returns the key of the filter's value in the index of the field.
+func (f EqFilter) Key() (string, error) {
+	return valueKey(f.Value)
+}
+
+// Conditions returns the Eq filters that the filter is made of: the filter itself if it is one, or those in it if it
+// is an And filter. Eq filters can't be negated or combined with Or, since the field conditions are not checked
+// against archetypes, so an error is returned for filters that contain one that way.
+func Conditions(f ComponentFilter) ([]EqFilter, error) {
+	switch f := f.(type) {
+	case EqFilter:
+		return []EqFilter{f}, nil
+	case *and:
+		var conds []EqFilter
+		for _, child := range f.filters {
+			childConds, err := Conditions(child)
+			if err != nil {
+				return nil, err
+			}
+			conds = append(conds, childConds...)
+		}
+		return conds, nil
+	case *or:
+		for _, child := range f.filters {
+			if hasEq(child) {
+				return nil, eris.New("filter.Eq can't be combined with filter.Or")
+			}
+		}
+	case *not:
+		if hasEq(f.filter) {
+			return nil, eris.New("filter.Eq can't be negated")
+		}
+	}
+	return nil, nil
+}
+
+func hasEq(f ComponentFilter) bool {
+	switch f := f.(type) {
+	case EqFilter:
+		return true
+	case *and:
+		return slices.ContainsFunc(f.filters, hasEq)
+	case *or:
+		return slices.ContainsFunc(f.filters, hasEq)
+	case *not:
+		return hasEq(f.filter)
+	}
+	return false
+}
+
+// FieldKey returns the key that the field with the given name of the component value is indexed under.
+func FieldKey(component any, field string) (string, error) {
+	v := reflect.Indirect(reflect.ValueOf(component))
+	if v.Kind() != reflect.Struct {
+		return "", eris.Errorf("can't index field %q of %T, which is not a struct", field, component)
+	}
+	fieldValue := v.FieldByName(field)
+	if !fieldValue.IsValid() || !fieldValue.CanInterface() {
+		return "", eris.Errorf("%T has no exported field %q", component, field)
+	}
+	return valueKey(fieldValue.Interface())
+}
+
+func valueKey(value any) (string, error) {
+	bz, err := json.Marshal(value)
+	if err != nil {
+		return "", eris.Wrap(err, "failed to encode indexed value")
+	}
+	return string(bz), nil
+}
@@ -358,3 +358,26 @@ func helperArchetypeFilter(b *testing.B, relevantCount, ignoreCount int) {
 		assert.Equal(b, count, relevantCount)
 	}
 }
+
+type customFilter struct{}
+
+func (customFilter) MatchesComponents([]types.Component) bool { return true }
+
+func TestFilterSignature(t *testing.T) {
+	sig := func(f filter.ComponentFilter) string {
+		s, ok := filter.Signature(f)
+		assert.Check(t, ok)
+		return s
+	}
+	alphaBeta := filter.Contains(filter.Component[Alpha](), filter.Component[Beta]())
+	betaAlpha := filter.Contains(filter.Component[Beta](), filter.Component[Alpha]())
+	assert.Equal(t, sig(alphaBeta), sig(betaAlpha))
+	assert.Equal(t, sig(alphaBeta), `contains("alpha","beta")`)
+	assert.Check(t, sig(alphaBeta) != sig(filter.Exact(filter.Component[Alpha](), filter.Component[Beta]())))
+	assert.Equal(t,
+		sig(filter.Or(filter.Not(alphaBeta), filter.All())),
+		`or(not(contains("alpha","beta")),all)`)
+
+	_, ok := filter.Signature(filter.And(alphaBeta, customFilter{}))
+	assert.Check(t, !ok)
+}
@@ -0,0 +1,59 @@
+package filter
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// Signature returns a string that identifies the archetypes the filter matches: two filters with the same signature
+// match the same archetypes. Signatures are used as cache keys for the results of archetype searches.
+//
+// Only the filters in this package have a signature. False is returned for other implementations of ComponentFilter,
+// and for filters that contain one.
+func Signature(f ComponentFilter) (string, bool) {
+	switch f := f.(type) {
+	case *all:
+		return "all", true
+	case *contains:
+		return componentsSignature("contains", f.components), true
+	case EqFilter:
+		// The field condition is checked for each entity, so it matches the same archetypes as Contains.
+		return componentsSignature("contains", []types.Component{f.Component}), true
+	case exact:
+		return componentsSignature("exact", f.components), true
+	case *not:
+		sig, ok := Signature(f.filter)
+		return "not(" + sig + ")", ok
+	case *and:
+		return filtersSignature("and", f.filters)
+	case *or:
+		return filtersSignature("or", f.filters)
+	default:
+		return "", false
+	}
+}
+
+func componentsSignature(op string, components []types.Component) string {
+	names := make([]string, 0, len(components))
+	for _, c := range components {
+		names = append(names, strconv.Quote(c.Name()))
+	}
+	// The order of components doesn't change which archetypes match.
+	sort.Strings(names)
+	return op + "(" + strings.Join(names, ",") + ")"
+}
+
+func filtersSignature(op string, filters []ComponentFilter) (string, bool) {
+	sigs := make([]string, 0, len(filters))
+	for _, f := range filters {
+		sig, ok := Signature(f)
+		if !ok {
+			return "", false
+		}
+		sigs = append(sigs, sig)
+	}
+	return op + "(" + strings.Join(sigs, ",") + ")", true
+}
@@ -3,8 +3,6 @@ package search
 import (
 	"slices"
 
-	"github.com/rotisserie/eris"
-
 	"pkg.world.dev/world-engine/cardinal/iterators"
 	"pkg.world.dev/world-engine/cardinal/search/filter"
 	"pkg.world.dev/world-engine/cardinal/types"
@@ -13,19 +11,13 @@ import (
 
 type CallbackFn func(types.EntityID) bool
 
-type cache struct {
-	archetypes []types.ArchetypeID
-	seen       int
-}
-
 // Search represents a search for entities.
 // It is used to filter entities based on their components.
 // It receives arbitrary filters that are used to filter entities.
-// It contains a cache that is used to avoid re-evaluating the search.
-// So it is not recommended to create a new search every time you want
-// to filter entities with the same search.
+// The archetypes that match a filter are cached by the world's state
+// manager, so searches with the same filter can be created in every
+// system without recomputing the matches every tick.
 type Search struct {
-	archMatches             *cache
 	filter                  filter.ComponentFilter
 	componentPropertyFilter filterFn
 }
@@ -99,6 +91,8 @@ type Searchable interface {
 	MustFirst(eCtx engine.Context) types.EntityID
 	Count(eCtx engine.Context) (int, error)
 	Collect(eCtx engine.Context) ([]types.EntityID, error)
+	// Explain describes how the search is evaluated against the current state.
+	Explain(eCtx engine.Context) (Explanation, error)
 }
 
 // NewSearch creates a new search.
@@ -110,7 +104,6 @@ func NewSearch() searchBuilder {
 // TODO: should deprecate this in the future.
 func NewLegacySearch(componentFilter filter.ComponentFilter) EntitySearch {
 	return &Search{
-		archMatches:             &cache{},
 		filter:                  componentFilter,
 		componentPropertyFilter: nil,
 	}
@@ -131,7 +124,6 @@ func (s *Search) Where(componentFilter filterFn) EntitySearch {
 		componentPropertyFilter = componentFilter
 	}
 	return &Search{
-		archMatches:             &cache{},
 		filter:                  s.filter,
 		componentPropertyFilter: componentPropertyFilter,
 	}
@@ -142,33 +134,7 @@ func (s *Search) Where(componentFilter filterFn) EntitySearch {
 func (s *Search) Each(eCtx engine.Context, callback CallbackFn) (err error) {
 	defer func() { defer panicOnFatalError(eCtx, err) }()
 
-	result := s.evaluateSearch(eCtx)
-	iter := iterators.NewEntityIterator(0, eCtx.StoreReader(), result)
-	for iter.HasNext() {
-		entities, err := iter.Next()
-		if err != nil {
-			return err
-		}
-		for _, id := range entities {
-			var filterValue bool
-			if s.componentPropertyFilter != nil {
-				filterValue, err = s.componentPropertyFilter(eCtx, id)
-				if err != nil {
-					continue
-				}
-			} else {
-				filterValue = true
-			}
-
-			if filterValue {
-				cont := callback(id)
-				if !cont {
-					return nil
-				}
-			}
-		}
-	}
-	return nil
+	return s.eachMatch(eCtx, callback)
 }
 
 func fastSortIDs(ids []types.EntityID) {
@@ -192,27 +158,12 @@ func (s *Search) Collect(eCtx engine.Context) ([]types.EntityID, error) {
 func (s *Search) Count(eCtx engine.Context) (ret int, err error) {
 	defer func() { defer panicOnFatalError(eCtx, err) }()
 
-	result := s.evaluateSearch(eCtx)
-	iter := iterators.NewEntityIterator(0, eCtx.StoreReader(), result)
-	for iter.HasNext() {
-		entities, err := iter.Next()
-		if err != nil {
-			return 0, err
-		}
-		for _, id := range entities {
-			var filterValue bool
-			if s.componentPropertyFilter != nil {
-				filterValue, err = s.componentPropertyFilter(eCtx, id)
-				if err != nil {
-					continue
-				}
-			} else {
-				filterValue = true
-			}
-			if filterValue {
-				ret++
-			}
-		}
+	err = s.eachMatch(eCtx, func(types.EntityID) bool {
+		ret++
+		return true
+	})
+	if err != nil {
+		return 0, err
 	}
 	return ret, nil
 }
@@ -221,32 +172,15 @@ func (s *Search) Count(eCtx engine.Context) (ret int, err error) {
 func (s *Search) First(eCtx engine.Context) (id types.EntityID, err error) {
 	defer func() { defer panicOnFatalError(eCtx, err) }()
 
-	result := s.evaluateSearch(eCtx)
-	iter := iterators.NewEntityIterator(0, eCtx.StoreReader(), result)
-	if !iter.HasNext() {
-		return iterators.BadID, eris.Wrap(err, "")
-	}
-	for iter.HasNext() {
-		entities, err := iter.Next()
-		if err != nil {
-			return 0, err
-		}
-		for _, id := range entities {
-			var filterValue bool
-			if s.componentPropertyFilter != nil {
-				filterValue, err = s.componentPropertyFilter(eCtx, id)
-				if err != nil {
-					continue
-				}
-			} else {
-				filterValue = true
-			}
-			if filterValue {
-				return id, nil
-			}
-		}
+	id = iterators.BadID
+	err = s.eachMatch(eCtx, func(match types.EntityID) bool {
+		id = match
+		return false
+	})
+	if err != nil {
+		return 0, err
 	}
-	return iterators.BadID, eris.Wrap(err, "")
+	return id, nil
 }
 
 func (s *Search) MustFirst(eCtx engine.Context) types.EntityID {
@@ -258,10 +192,58 @@ func (s *Search) MustFirst(eCtx engine.Context) types.EntityID {
 }
 
 func (s *Search) evaluateSearch(eCtx engine.Context) []types.ArchetypeID {
-	cache := s.archMatches
-	for it := eCtx.StoreReader().SearchFrom(s.filter, cache.seen); it.HasNext(); {
-		cache.archetypes = append(cache.archetypes, it.Next())
+	return eCtx.StoreReader().FindArchetypes(s.filter)
+}
+
+// eachMatch calls fn for each entity that matches the search, until fn returns false. If the filter has Eq
+// conditions, the entities are looked up in the index of the first condition's field if it has one, and only the
+// entities of the matching archetypes that meet every condition are passed to fn.
+func (s *Search) eachMatch(eCtx engine.Context, fn CallbackFn) error {
+	conds, err := filter.Conditions(s.filter)
+	if err != nil {
+		return err
+	}
+	matches := func(id types.EntityID) bool {
+		ok, err := meetsConditions(eCtx, conds, id)
+		if err != nil || !ok {
+			return false
+		}
+		if s.componentPropertyFilter != nil {
+			ok, err = s.componentPropertyFilter(eCtx, id)
+			return err == nil && ok
+		}
+		return true
+	}
+
+	ids, indexed, err := lookupIndex(eCtx, conds)
+	if err != nil {
+		return err
 	}
-	cache.seen = eCtx.StoreReader().ArchetypeCount()
-	return cache.archetypes
+	if indexed != nil {
+		for _, id := range ids {
+			comps, err := eCtx.StoreReader().GetComponentTypesForEntity(id)
+			if err != nil {
+				return err
+			}
+			inArchetype := s.filter.MatchesComponents(types.ConvertComponentMetadatasToComponents(comps))
+			if inArchetype && matches(id) && !fn(id) {
+				return nil
+			}
+		}
+		return nil
+	}
+
+	iter := iterators.NewEntityIterator(0, eCtx.StoreReader(), s.evaluateSearch(eCtx))
+	for iter.HasNext() {
+		entities, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		for _, id := range entities {
+			if matches(id) && !fn(id) {
+				return nil
+			}
+		}
+	}
+	return nil
 }
@@ -4,6 +4,8 @@ import (
 	"github.com/rotisserie/eris"
 
 	"pkg.world.dev/world-engine/cardinal/iterators"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/cardinal/types/engine"
 )
 
@@ -30,3 +32,48 @@ func isFatalError(err error) bool {
 	}
 	return true
 }
+
+// lookupIndex returns the entities that meet the first of the conditions whose field is indexed, and that condition.
+// A nil condition is returned if none of them is indexed.
+func lookupIndex(eCtx engine.Context, conds []filter.EqFilter) ([]types.EntityID, *filter.EqFilter, error) {
+	for i, cond := range conds {
+		key, err := cond.Key()
+		if err != nil {
+			return nil, nil, err
+		}
+		ids, ok, err := eCtx.StoreReader().LookupFieldIndex(cond.Component.Name(), cond.Field, key)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			return ids, &conds[i], nil
+		}
+	}
+	return nil, nil, nil
+}
+
+// meetsConditions reports whether the entity's fields are equal to the values of the conditions.
+func meetsConditions(eCtx engine.Context, conds []filter.EqFilter, id types.EntityID) (bool, error) {
+	for _, cond := range conds {
+		cType, err := eCtx.GetComponentByName(cond.Component.Name())
+		if err != nil {
+			return false, err
+		}
+		value, err := eCtx.StoreReader().GetComponentForEntity(cType, id)
+		if err != nil {
+			return false, err
+		}
+		got, err := filter.FieldKey(value, cond.Field)
+		if err != nil {
+			return false, err
+		}
+		want, err := cond.Key()
+		if err != nil {
+			return false, err
+		}
+		if got != want {
+			return false, nil
+		}
+	}
+	return true, nil
+}
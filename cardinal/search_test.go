@@ -499,3 +499,97 @@ func TestWhereClauseOnSearch(t *testing.T) {
 	assert.NilError(t, err)
 	assert.Equal(t, amt, 40)
 }
+
+func TestSearchExplain(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[AlphaTest](world))
+	assert.NilError(t, cardinal.RegisterComponent[BetaTest](world))
+	tf.StartWorld()
+
+	wCtx := cardinal.NewWorldContext(world)
+	_, err := cardinal.CreateMany(wCtx, 3, AlphaTest{})
+	assert.NilError(t, err)
+	_, err = cardinal.CreateMany(wCtx, 2, BetaTest{})
+	assert.NilError(t, err)
+	_, err = cardinal.CreateMany(wCtx, 1, AlphaTest{}, BetaTest{})
+	assert.NilError(t, err)
+
+	alpha := cardinal.NewSearch().Entity(filter.Contains(filter.Component[AlphaTest]()))
+	e, err := alpha.Explain(wCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, e.Kind, "search")
+	assert.Equal(t, e.Filter, `contains("alpha")`)
+	assert.Check(t, e.Cached)
+	assert.Check(t, !e.Where)
+	assert.Equal(t, len(e.Archetypes), 2)
+	assert.Equal(t, e.EstimatedEntities, 4)
+	assert.Check(t, !e.FullScan)
+
+	// Where clauses don't change the estimate, which becomes an upper bound.
+	e, err = alpha.Where(cardinal.FilterFunction[AlphaTest](func(AlphaTest) bool { return false })).Explain(wCtx)
+	assert.NilError(t, err)
+	assert.Check(t, e.Where)
+	assert.Equal(t, e.EstimatedEntities, 4)
+
+	e, err = search.Not(alpha).Explain(wCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, e.Kind, "not")
+	assert.Equal(t, len(e.Searches), 1)
+	assert.Equal(t, e.EstimatedEntities, 6)
+	assert.Check(t, e.FullScan)
+}
+
+func TestSearchEqUsesFieldIndex(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[AlphaTest](world))
+	assert.NilError(t, cardinal.RegisterComponent[BetaTest](world))
+	assert.NilError(t, cardinal.RegisterIndex[AlphaTest](world, "Name1"))
+	assert.ErrorContains(t, cardinal.RegisterIndex[AlphaTest](world, "Name1"), "already registered")
+	assert.ErrorContains(t, cardinal.RegisterIndex[BetaTest](world, "Name2"), "no exported field")
+	tf.StartWorld()
+
+	wCtx := cardinal.NewWorldContext(world)
+	a, err := cardinal.Create(wCtx, AlphaTest{Name1: "a"})
+	assert.NilError(t, err)
+	b, err := cardinal.Create(wCtx, AlphaTest{Name1: "b"}, BetaTest{Name1: "b"})
+	assert.NilError(t, err)
+	_, err = cardinal.CreateMany(wCtx, 3, AlphaTest{})
+	assert.NilError(t, err)
+
+	named := func(name string) []types.EntityID {
+		ids, err := cardinal.NewSearch().Entity(filter.Eq[AlphaTest]("Name1", name)).Collect(wCtx)
+		assert.NilError(t, err)
+		return ids
+	}
+	assert.DeepEqual(t, named("a"), []types.EntityID{a})
+	assert.DeepEqual(t, named("b"), []types.EntityID{b})
+	assert.Equal(t, len(named("")), 3)
+
+	e, err := cardinal.NewSearch().Entity(filter.Eq[AlphaTest]("Name1", "a")).Explain(wCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, e.Index, "alpha.Name1")
+	assert.Equal(t, e.Filter, `contains("alpha")`)
+	assert.Equal(t, e.EstimatedEntities, 1)
+
+	// Changes made in the same tick are seen by later searches.
+	assert.NilError(t, cardinal.UpdateComponent[AlphaTest](wCtx, a, func(alpha *AlphaTest) *AlphaTest {
+		alpha.Name1 = "b"
+		return alpha
+	}))
+	assert.Equal(t, len(named("a")), 0)
+	assert.DeepEqual(t, named("b"), []types.EntityID{a, b})
+	assert.NilError(t, cardinal.Remove(wCtx, b))
+	assert.DeepEqual(t, named("b"), []types.EntityID{a})
+
+	// Fields without an index are compared for each entity, and Eq filters combine with the other filters.
+	c, err := cardinal.Create(wCtx, AlphaTest{Name1: "b"}, BetaTest{Name1: "c"})
+	assert.NilError(t, err)
+	ids, err := cardinal.NewSearch().Entity(filter.And(
+		filter.Eq[AlphaTest]("Name1", "b"),
+		filter.Eq[BetaTest]("Name1", "c"),
+	)).Collect(wCtx)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ids, []types.EntityID{c})
+}
@@ -0,0 +1,47 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/component"
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+)
+
+type HealthComponent struct {
+	Current int `json:"current"`
+	Max     int `json:"max,omitempty"`
+}
+
+func (HealthComponent) Name() string { return "health" }
+
+func (s *ServerTestSuite) TestGetComponents() {
+	s.setupWorld()
+	err := cardinal.RegisterComponent[HealthComponent](s.world,
+		component.WithDoc[HealthComponent]("The hit points of an entity."),
+		component.WithFieldDoc[HealthComponent]("Current", "Remaining hit points."),
+	)
+	s.Require().NoError(err)
+	s.fixture.DoTick()
+
+	res := s.fixture.Get("/world/components")
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+	var result handler.GetComponentsResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&result))
+	s.Require().Len(result.Components, len(s.world.GetRegisteredComponents()))
+
+	var health *handler.ComponentDetail
+	for i := range result.Components {
+		if result.Components[i].Name == "health" {
+			health = &result.Components[i]
+		}
+	}
+	s.Require().NotNil(health)
+	s.Require().Equal("The hit points of an entity.", health.Doc)
+	s.Require().NotEmpty(health.Schema)
+	s.Require().Equal([]handler.ComponentField{
+		{Name: "current", GoName: "Current", Type: "int", Doc: "Remaining hit points."},
+		{Name: "max", GoName: "Max", Type: "int", Optional: true},
+	}, health.Fields)
+}
@@ -0,0 +1,38 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+)
+
+func (s *ServerTestSuite) TestEventHistoryQuery() {
+	s.setupWorld()
+	world := s.world
+	err := cardinal.RegisterSystems(world, func(ctx cardinal.WorldContext) error {
+		return ctx.EmitEvent(map[string]any{
+			"type":       "tick",
+			"personaTag": "alpha",
+			"tick":       ctx.CurrentTick(),
+		})
+	})
+	s.Require().NoError(err)
+	s.fixture.DoTick()
+	s.fixture.DoTick()
+	s.fixture.DoTick()
+
+	res := s.fixture.Post("query/events/list", handler.ListEventsRequest{Limit: 2, Type: "tick"})
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+	var reply handler.ListEventsResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&reply))
+	s.Require().Len(reply.Events, 2)
+	s.Require().Equal(uint64(1), reply.Events[0].Tick)
+	s.Require().Equal(uint64(2), reply.Events[1].Tick)
+
+	res = s.fixture.Post("query/events/list", handler.ListEventsRequest{PersonaTag: "beta"})
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&reply))
+	s.Require().Len(reply.Events, 0)
+}
@@ -1,8 +1,13 @@
 package server_test
 
 import (
+	"bufio"
+	"compress/flate"
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -119,3 +124,183 @@ func TestEventsThroughSystems(t *testing.T) {
 func wsURL(addr, path string) string {
 	return fmt.Sprintf("ws://%s/%s", addr, path)
 }
+
+func TestEventsBinaryEncoding(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world, addr := tf.World, tf.BaseURL
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		return wCtx.EmitEvent(map[string]any{"message": "test"})
+	}))
+	tf.StartWorld()
+
+	binaryConn, _, err := websocket.DefaultDialer.Dial(wsURL(addr, "events?encoding=binary"), nil)
+	assert.NilError(t, err)
+	jsonConn, _, err := websocket.DefaultDialer.Dial(wsURL(addr, "events"), nil)
+	assert.NilError(t, err)
+	tf.DoTick()
+
+	mode, message, err := binaryConn.ReadMessage()
+	assert.NilError(t, err)
+	assert.Equal(t, mode, websocket.BinaryMessage)
+	var binaryResults cardinal.TickResults
+	assert.NilError(t, binaryResults.UnmarshalBinary(message))
+	assert.Equal(t, len(binaryResults.Events), 1)
+	assert.Equal(t, string(binaryResults.Events[0]), `{"message":"test"}`)
+
+	mode, message, err = jsonConn.ReadMessage()
+	assert.NilError(t, err)
+	assert.Equal(t, mode, websocket.TextMessage)
+	var jsonResults cardinal.TickResults
+	assert.NilError(t, json.Unmarshal(message, &jsonResults))
+	assert.Equal(t, jsonResults.Tick, binaryResults.Tick)
+
+	_, res, err := websocket.DefaultDialer.Dial(wsURL(addr, "events?encoding=xml"), nil)
+	assert.Check(t, err != nil)
+	assert.Equal(t, res.StatusCode, http.StatusBadRequest)
+	assert.NilError(t, res.Body.Close())
+}
+
+func TestEventsCompression(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithEventCompression(flate.BestSpeed, 64))
+	world, addr := tf.World, tf.BaseURL
+	message := strings.Repeat("compressible ", 100)
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		return wCtx.EmitEvent(map[string]any{"message": message})
+	}))
+	tf.StartWorld()
+
+	dialer := *websocket.DefaultDialer
+	dialer.EnableCompression = true
+	conn, res, err := dialer.Dial(wsURL(addr, "events"), nil)
+	assert.NilError(t, err)
+	assert.Check(t, strings.Contains(res.Header.Get("Sec-Websocket-Extensions"), "permessage-deflate"))
+	plainConn, res, err := websocket.DefaultDialer.Dial(wsURL(addr, "events"), nil)
+	assert.NilError(t, err)
+	assert.Equal(t, res.Header.Get("Sec-Websocket-Extensions"), "")
+	tf.DoTick()
+
+	for _, c := range []*websocket.Conn{conn, plainConn} {
+		_, bz, err := c.ReadMessage()
+		assert.NilError(t, err)
+		var results cardinal.TickResults
+		assert.NilError(t, json.Unmarshal(bz, &results))
+		assert.Equal(t, len(results.Events), 1)
+		var event map[string]any
+		assert.NilError(t, json.Unmarshal(results.Events[0], &event))
+		assert.Equal(t, event["message"], message)
+	}
+}
+
+func TestEventsBackfillFromTickLog(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithTickLog(2))
+	world, addr := tf.World, tf.BaseURL
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		return wCtx.EmitEvent(map[string]any{"tick": wCtx.CurrentTick()})
+	}))
+	for range 3 {
+		tf.DoTick()
+	}
+
+	// Only the two most recent ticks are kept.
+	pruned, _, err := websocket.DefaultDialer.Dial(wsURL(addr, "events?fromTick=0"), nil)
+	assert.NilError(t, err)
+	_, _, err = pruned.ReadMessage()
+	assert.Check(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation))
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL(addr, "events?fromTick=1"), nil)
+	assert.NilError(t, err)
+	readTick := func() uint64 {
+		_, bz, err := conn.ReadMessage()
+		assert.NilError(t, err)
+		var results cardinal.TickResults
+		assert.NilError(t, json.Unmarshal(bz, &results))
+		assert.Equal(t, len(results.Events), 1)
+		var event map[string]uint64
+		assert.NilError(t, json.Unmarshal(results.Events[0], &event))
+		assert.Equal(t, event["tick"], results.Tick)
+		return results.Tick
+	}
+	// The logged ticks are replayed, and the live ticks follow.
+	assert.Equal(t, readTick(), uint64(1))
+	assert.Equal(t, readTick(), uint64(2))
+	tf.DoTick()
+	assert.Equal(t, readTick(), uint64(3))
+
+	_, res, err := websocket.DefaultDialer.Dial(wsURL(addr, "events?fromTick=soon"), nil)
+	assert.Check(t, err != nil)
+	assert.Equal(t, res.StatusCode, http.StatusBadRequest)
+	assert.NilError(t, res.Body.Close())
+}
+
+func TestEventsServerSentEvents(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithTickLog(2))
+	world, addr := tf.World, tf.BaseURL
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		return wCtx.EmitEvent(map[string]any{"tick": wCtx.CurrentTick()})
+	}))
+	tf.DoTick()
+
+	stream := func(query, lastEventID string) *bufio.Reader {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+"/events/sse"+query, http.NoBody)
+		assert.NilError(t, err)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		res, err := http.DefaultClient.Do(req)
+		assert.NilError(t, err)
+		assert.Equal(t, res.StatusCode, http.StatusOK)
+		assert.Equal(t, res.Header.Get("Content-Type"), "text/event-stream")
+		t.Cleanup(func() { _ = res.Body.Close() })
+		return bufio.NewReader(res.Body)
+	}
+	readTick := func(r *bufio.Reader) uint64 {
+		var id, data string
+		for {
+			line, err := r.ReadString('\n')
+			assert.NilError(t, err)
+			line = strings.TrimSuffix(line, "\n")
+			if line == "" && data != "" {
+				break
+			}
+			if v, ok := strings.CutPrefix(line, "id: "); ok {
+				id = v
+			} else if v, ok := strings.CutPrefix(line, "data: "); ok {
+				data = v
+			}
+		}
+		var results cardinal.TickResults
+		assert.NilError(t, json.Unmarshal([]byte(data), &results))
+		assert.Equal(t, id, fmt.Sprint(results.Tick))
+		return results.Tick
+	}
+
+	// The logged ticks are replayed, and the live ticks follow.
+	r := stream("?fromTick=0", "")
+	assert.Equal(t, readTick(r), uint64(0))
+	tf.DoTick()
+	assert.Equal(t, readTick(r), uint64(1))
+
+	// A client that reconnects is replayed the ticks after the last one it received.
+	resumed := stream("", "0")
+	assert.Equal(t, readTick(resumed), uint64(1))
+
+	// The websocket endpoint keeps serving the same stream next to the server-sent events.
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL(addr, "events?fromTick=1"), nil)
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	readWebSocketTick := func() uint64 {
+		_, bz, err := conn.ReadMessage()
+		assert.NilError(t, err)
+		var results cardinal.TickResults
+		assert.NilError(t, json.Unmarshal(bz, &results))
+		return results.Tick
+	}
+	assert.Equal(t, readWebSocketTick(), uint64(1))
+
+	tf.DoTick()
+	assert.Equal(t, readTick(resumed), uint64(2))
+	assert.Equal(t, readTick(r), uint64(2))
+	assert.Equal(t, readWebSocketTick(), uint64(2))
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+type GetComponentsResponse struct {
+	Components []ComponentDetail `json:"components"`
+}
+
+type ComponentDetail struct {
+	ID        types.ComponentID `json:"id"`
+	Name      string            `json:"name"`
+	Doc       string            `json:"doc,omitempty"`
+	Ephemeral bool              `json:"ephemeral"`
+	Fields    []ComponentField  `json:"fields"`
+	// Schema is the JSON schema of the component.
+	Schema json.RawMessage `json:"schema" swaggertype:"object"`
+}
+
+type ComponentField struct {
+	Name     string `json:"name"`   // name of the field as it appears in JSON
+	GoName   string `json:"goName"` // name of the field in the Go struct
+	Type     string `json:"type"`   // Go type of the field
+	Doc      string `json:"doc,omitempty"`
+	Optional bool   `json:"optional"` // whether the field is omitted from JSON when empty
+}
+
+// GetComponents godoc
+//
+//	@Summary      Retrieves the registered components
+//	@Description  Lists all registered components with their field names, types, docs, and JSON schemas
+//	@Produce      application/json
+//	@Success      200  {object}  GetComponentsResponse  "Details of the registered components"
+//	@Router       /world/components [get]
+func GetComponents(components []types.ComponentMetadata) func(*fiber.Ctx) error {
+	details := make([]ComponentDetail, 0, len(components))
+	for _, component := range components {
+		c, _ := component.Decode(component.GetSchema())
+		details = append(details, ComponentDetail{
+			ID:        component.ID(),
+			Name:      component.Name(),
+			Doc:       component.Doc(),
+			Ephemeral: component.IsEphemeral(),
+			Fields:    getComponentFields(reflect.TypeOf(c), component.FieldDocs()),
+			Schema:    component.GetSchema(),
+		})
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		return ctx.JSON(GetComponentsResponse{Components: details})
+	}
+}
+
+func getComponentFields(t reflect.Type, docs map[string]string) []ComponentField {
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := make([]ComponentField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, optional := field.Name, false
+		if tag := field.Tag.Get("json"); tag != "" {
+			tagName, opts, _ := strings.Cut(tag, ",")
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+			optional = strings.Contains(opts, "omitempty")
+		}
+		fields = append(fields, ComponentField{
+			Name:     name,
+			GoName:   field.Name,
+			Type:     field.Type.String(),
+			Doc:      docs[field.Name],
+			Optional: optional,
+		})
+	}
+	return fields
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 
 	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/server/handler/cql"
 	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
 	"pkg.world.dev/world-engine/cardinal/types"
 )
@@ -64,3 +65,39 @@ func GetDebugState(provider servertypes.Provider) func(*fiber.Ctx) error {
 		return ctx.JSON(&result)
 	}
 }
+
+type DebugExplainRequest struct {
+	// CQL is the search to explain, such as "CONTAINS(health)".
+	CQL string
+}
+
+// PostDebugExplain godoc
+//
+// @Summary      Explains how a CQL search is evaluated
+// @Description  Lists the archetypes that match the search, how many entities they have, and whether the search
+// @Description  is a full scan
+// @Accept       application/json
+// @Produce      application/json
+// @Param        search  body      DebugExplainRequest  true  "CQL search to explain"
+// @Success      200     {object}  search.Explanation   "How the search is evaluated"
+// @Failure      400     {string}  string               "Invalid request parameters"
+// @Router       /debug/explain [post]
+func PostDebugExplain(provider servertypes.Provider) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		req := new(DebugExplainRequest)
+		if err := ctx.BodyParser(req); err != nil {
+			return err
+		}
+		resultFilter, err := cql.Parse(req.CQL, func(name string) (types.Component, error) {
+			return provider.GetComponentByName(name)
+		})
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		explanation, err := provider.Search(resultFilter).Explain(provider.GetReadOnlyCtx())
+		if err != nil {
+			return err
+		}
+		return ctx.JSON(&explanation)
+	}
+}
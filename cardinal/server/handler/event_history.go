@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"pkg.world.dev/world-engine/cardinal/events"
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+)
+
+// ListEventsRequest narrows down the events returned by the event history endpoint. The tick interval is closed on
+// StartTick and open on EndTick: i.e. [StartTick, EndTick). An EndTick of 0 means there is no upper bound.
+// Type and PersonaTag only match events that are JSON objects with a "type" or "personaTag" field, respectively.
+type ListEventsRequest struct {
+	Limit      int    `json:"limit"`
+	StartTick  uint64 `json:"startTick"`
+	EndTick    uint64 `json:"endTick"`
+	Type       string `json:"type"`
+	PersonaTag string `json:"personaTag"`
+	// Recipient only returns the events that were addressed to the given persona tag.
+	Recipient string `json:"recipient"`
+}
+
+// ListEventsResponse contains the matching events, ordered from oldest to newest.
+type ListEventsResponse struct {
+	Events []events.Entry `json:"events"`
+}
+
+// GetEvents godoc
+//
+//	@Summary      Retrieves the most recently emitted events
+//	@Description  Retrieves the most recently emitted events from the in-memory event history
+//	@Accept       application/json
+//	@Produce      application/json
+//	@Param        ListEventsRequest  body      ListEventsRequest   true  "Query body"
+//	@Success      200                {object}  ListEventsResponse  "List of events"
+//	@Failure      400                {string}  string              "Invalid request body"
+//	@Router       /query/events/list [post]
+func GetEvents(provider servertypes.Provider) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		req := new(ListEventsRequest)
+		if err := ctx.BodyParser(req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+		}
+
+		fields := map[string]string{}
+		if req.Type != "" {
+			fields["type"] = req.Type
+		}
+		if req.PersonaTag != "" {
+			fields["personaTag"] = req.PersonaTag
+		}
+		if req.Recipient != "" {
+			fields[events.RecipientsField] = req.Recipient
+		}
+		entries := provider.QueryEvents(events.Filter{
+			StartTick: req.StartTick,
+			EndTick:   req.EndTick,
+			Fields:    fields,
+			Limit:     req.Limit,
+		})
+		if entries == nil {
+			entries = []events.Entry{}
+		}
+		return ctx.JSON(ListEventsResponse{Events: entries})
+	}
+}
@@ -1,31 +1,467 @@
 package handler
 
 import (
-	"github.com/gofiber/contrib/socketio"
+	"bufio"
+	"compress/flate"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/statsd"
 )
 
+const (
+	// EventEncodingJSON sends tick results as JSON text frames. This is the default.
+	EventEncodingJSON = "json"
+	// EventEncodingBinary sends tick results as protobuf encoded binary frames. See cardinal.TickResults.MarshalBinary
+	// for the schema.
+	EventEncodingBinary = "binary"
+
+	// EventStreamKeepAlive is how often a comment is sent to the clients of the server-sent event stream when there
+	// are no events, so that proxies don't close the idle stream, and closed streams are noticed.
+	EventStreamKeepAlive = 15 * time.Second
+
+	// EventClientBuffer is the number of frames that can be waiting to be sent to an event websocket client. Clients
+	// that fall further behind are disconnected, so that one slow client can't hold back the others.
+	EventClientBuffer = 64
+
+	eventEncodingLocal    = "eventEncoding"
+	eventCompressionLocal = "eventCompression"
+	eventFromTickLocal    = "eventFromTick"
+)
+
+// EventCompression configures the permessage-deflate compression of the event websocket. Compression is only used
+// with the clients that ask for it when they connect.
+type EventCompression struct {
+	// Level is the compress/flate level frames are compressed with.
+	Level int
+	// Threshold is the size, in bytes, below which frames are sent uncompressed, since compressing small frames
+	// costs more than it saves.
+	Threshold int
+}
+
+// TickEvent is implemented by the events that belong to a tick, such as tick results. Clients that replayed the
+// tick log skip the live events of the ticks that they already replayed.
+type TickEvent interface {
+	EventTick() uint64
+}
+
+// ReplayFunc calls fn with the logged events of every tick from fromTick on, and returns the tick after the last one
+// that was replayed.
+type ReplayFunc func(fromTick uint64, fn func(event any) error) (next uint64, err error)
+
+// EventClients keeps track of the websocket connections to the event stream, and of the event encoding that each
+// of them negotiated when it connected.
+type EventClients struct {
+	compression *EventCompression
+	replay      ReplayFunc
+
+	mu      sync.Mutex
+	clients map[*eventClient]struct{}
+}
+
+type eventClient struct {
+	encoding string
+	compress bool
+	frames   chan eventFrame
+	// next is the first tick whose live events the client is sent. The events of earlier ticks were replayed.
+	next uint64
+}
+
+type eventFrame struct {
+	data        []byte
+	messageType int
+	compress    bool
+	tick        uint64
+	ticked      bool
+}
+
+func NewEventClients(compression *EventCompression, replay ReplayFunc) *EventClients {
+	return &EventClients{
+		compression: compression,
+		replay:      replay,
+		clients:     map[*eventClient]struct{}{},
+	}
+}
+
+// Encodings returns the event encodings of the connected clients.
+func (c *EventClients) Encodings() map[string]bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	encodings := map[string]bool{}
+	for client := range c.clients {
+		encodings[client.encoding] = true
+	}
+	return encodings
+}
+
+// Broadcast queues a frame of the event for each client, in the client's encoding. Clients that negotiated the binary
+// encoding receive the event's binary encoding if it implements encoding.BinaryMarshaler, and JSON otherwise. Clients
+// that fell too far behind, and the clients for which drop returns true, are disconnected instead.
+func (c *EventClients) Broadcast(event any, drop func() bool) error {
+	encodings := c.Encodings()
+	if len(encodings) == 0 {
+		return nil
+	}
+	frames, err := encodeEvent(event, encodings)
+	if err != nil {
+		return err
+	}
+	tickEvent, ticked := event.(TickEvent)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var saved int64
+	savedByEncoding := map[string]int64{}
+	for client := range c.clients {
+		data, ok := frames[client.encoding]
+		if !ok {
+			continue
+		}
+		if drop != nil && drop() {
+			c.disconnect(client)
+			continue
+		}
+		frame := c.frame(client, data)
+		if ticked {
+			frame.tick, frame.ticked = tickEvent.EventTick(), true
+		}
+		select {
+		case client.frames <- frame:
+		default:
+			log.Warn().Msg("Disconnecting an event client that fell behind")
+			c.disconnect(client)
+			continue
+		}
+		if frame.compress {
+			s, ok := savedByEncoding[client.encoding]
+			if !ok {
+				s = int64(len(data) - compressedSize(data, c.compression.Level))
+				savedByEncoding[client.encoding] = s
+			}
+			saved += s
+		}
+	}
+	if saved != 0 {
+		if err := statsd.Client().Count("event_compression_bytes_saved", saved, nil, 1); err != nil {
+			log.Warn().Msgf("failed to emit count stat:%v", err)
+		}
+	}
+	return nil
+}
+
+// frame returns the frame that sends the data to the client.
+func (c *EventClients) frame(client *eventClient, data []byte) eventFrame {
+	frame := eventFrame{data: data, messageType: websocket.TextMessage}
+	if client.encoding == EventEncodingBinary {
+		frame.messageType = websocket.BinaryMessage
+	}
+	frame.compress = client.compress && len(data) >= c.compression.Threshold
+	return frame
+}
+
+// encodeEvent returns the frame data of the event in each of the encodings.
+func encodeEvent(event any, encodings map[string]bool) (map[string][]byte, error) {
+	jsonBz, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	frames := map[string][]byte{
+		EventEncodingJSON:   jsonBz,
+		EventEncodingBinary: jsonBz,
+	}
+	if marshaler, ok := event.(encoding.BinaryMarshaler); ok && encodings[EventEncodingBinary] {
+		frames[EventEncodingBinary], err = marshaler.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return frames, nil
+}
+
+// backfill replays the tick log to the client from the tick, and registers the client for live events once the
+// replay is about to catch up. The log is replayed a second time from where the first replay ended, after the client
+// is registered, so that no tick falls between the replay and the live events; the client skips the live events of
+// the ticks that were replayed.
+func (c *EventClients) backfill(client *eventClient, fromTick uint64, send func(eventFrame) error) error {
+	write := func(event any) error {
+		frames, err := encodeEvent(event, map[string]bool{client.encoding: true})
+		if err != nil {
+			return err
+		}
+		frame := c.frame(client, frames[client.encoding])
+		if tickEvent, ok := event.(TickEvent); ok {
+			frame.tick, frame.ticked = tickEvent.EventTick(), true
+		}
+		return send(frame)
+	}
+	next, err := c.replay(fromTick, write)
+	if err != nil {
+		return err
+	}
+	c.add(client)
+	if client.next, err = c.replay(next, write); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CloseAll disconnects every client.
+func (c *EventClients) CloseAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for client := range c.clients {
+		c.disconnect(client)
+	}
+}
+
+func (c *EventClients) add(client *eventClient) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clients[client] = struct{}{}
+}
+
+func (c *EventClients) remove(client *eventClient) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.clients[client]; ok {
+		c.disconnect(client)
+	}
+}
+
+// disconnect removes the client, and tells its connection to close. c.mu must be held.
+func (c *EventClients) disconnect(client *eventClient) {
+	delete(c.clients, client)
+	close(client.frames)
+}
+
+// compressedSize returns the size of the data once it's compressed at the level, which is what sending it with
+// permessage-deflate costs, give or take a few bytes.
+func compressedSize(data []byte, level int) int {
+	var counter byteCounter
+	w, err := flate.NewWriter(&counter, level)
+	if err != nil {
+		return len(data)
+	}
+	_, _ = w.Write(data)
+	_ = w.Close()
+	return int(counter)
+}
+
+type byteCounter int
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
+
 // WebSocketEvents godoc
 //
 //	@Summary      Establishes a new websocket connection to retrieve system events
-//	@Description  Establishes a new websocket connection to retrieve system events
+//	@Description  Establishes a new websocket connection to retrieve system events. Tick results are sent as JSON text
+//	@Description  frames unless the binary encoding is requested, in which case they are sent as protobuf binary frames.
+//	@Description  Frames are compressed with permessage-deflate if the client asks for it and the server enables it.
+//	@Description  If fromTick is given, the events of the ticks from fromTick on are first replayed from the tick log,
+//	@Description  without their receipts, after which the live tick results follow without a gap or a repeated tick.
 //	@Produce      application/json
-//	@Success      101  {string}  string  "Switch protocol to ws"
+//	@Param        encoding  query     string  false  "Encoding of tick results"  Enums(json, binary)
+//	@Param        fromTick  query     int     false  "Tick to replay the logged events from"
+//	@Success      101       {string}  string  "Switch protocol to ws"
+//	@Failure      400       {string}  string  "Unsupported encoding or invalid tick"
 //	@Router       /events [get]
-func WebSocketEvents() func(c *fiber.Ctx) error {
-	return socketio.New(func(_ *socketio.Websocket) {
-		log.Debug().Msg("new websocket connection established")
-	})
+func WebSocketEvents(clients *EventClients) func(c *fiber.Ctx) error {
+	config := websocket.Config{EnableCompression: clients.compression != nil}
+	return websocket.New(func(conn *websocket.Conn) {
+		encoding, _ := conn.Locals(eventEncodingLocal).(string)
+		if encoding == "" {
+			encoding = EventEncodingJSON
+		}
+		client := &eventClient{
+			encoding: encoding,
+			frames:   make(chan eventFrame, EventClientBuffer),
+		}
+		if offered, _ := conn.Locals(eventCompressionLocal).(bool); offered && clients.compression != nil {
+			client.compress = true
+			if err := conn.SetCompressionLevel(clients.compression.Level); err != nil {
+				log.Warn().Err(err).Msg("invalid event compression level")
+			}
+		}
+		defer clients.remove(client)
+		if fromTick, ok := conn.Locals(eventFromTickLocal).(uint64); ok {
+			send := func(frame eventFrame) error {
+				conn.EnableWriteCompression(frame.compress)
+				return conn.WriteMessage(frame.messageType, frame.data)
+			}
+			if err := clients.backfill(client, fromTick, send); err != nil {
+				log.Debug().Err(err).Msg("failed to replay events to websocket connection")
+				msg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, err.Error())
+				if err := conn.WriteMessage(websocket.CloseMessage, msg); err != nil {
+					log.Debug().Err(err).Msg("failed to close event connection")
+				}
+				return
+			}
+		} else {
+			clients.add(client)
+		}
+		log.Debug().Str("encoding", encoding).Bool("compression", client.compress).
+			Msg("new websocket connection established")
+
+		// Clients don't send anything, but reading is how a closed connection is noticed.
+		left := make(chan struct{})
+		go func() {
+			defer close(left)
+			for {
+				if _, _, err := conn.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+
+		for {
+			select {
+			case <-left:
+				return
+			case frame, ok := <-client.frames:
+				if !ok {
+					if err := conn.WriteMessage(websocket.CloseMessage, []byte("")); err != nil {
+						log.Debug().Err(err).Msg("failed to close event connection")
+					}
+					return
+				}
+				if frame.ticked && frame.tick < client.next {
+					continue
+				}
+				conn.EnableWriteCompression(frame.compress)
+				if err := conn.WriteMessage(frame.messageType, frame.data); err != nil {
+					return
+				}
+			}
+		}
+	}, config)
 }
 
 func WebSocketUpgrader(c *fiber.Ctx) error {
 	// IsWebSocketUpgrade returns true if the client
 	// requested upgrade to the WebSocket protocol.
 	if websocket.IsWebSocketUpgrade(c) {
+		encoding := c.Query("encoding", EventEncodingJSON)
+		if encoding != EventEncodingJSON && encoding != EventEncodingBinary {
+			return fiber.NewError(fiber.StatusBadRequest, "unsupported event encoding: "+encoding)
+		}
+		if from := c.Query("fromTick"); from != "" {
+			fromTick, err := strconv.ParseUint(from, 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid fromTick: "+from)
+			}
+			c.Locals(eventFromTickLocal, fromTick)
+		}
 		c.Locals("allowed", true)
+		c.Locals(eventEncodingLocal, encoding)
+		c.Locals(eventCompressionLocal, strings.Contains(c.Get("Sec-WebSocket-Extensions"), "permessage-deflate"))
 		return c.Next()
 	}
 	return fiber.ErrUpgradeRequired
 }
+
+// ServerSentEvents godoc
+//
+//	@Summary      Streams system events as server-sent events
+//	@Description  Streams the same events as the /events websocket as server-sent events, for clients that can't open
+//	@Description  websockets, such as clients behind proxies that block them. Events are JSON encoded, and tick results
+//	@Description  carry their tick as the event ID. If fromTick is given, the events of the ticks from fromTick on are
+//	@Description  first replayed from the tick log, like they are for the websocket. A client that reconnects with the
+//	@Description  Last-Event-ID header is replayed the ticks it missed, if the world keeps a tick log.
+//	@Produce      text/event-stream
+//	@Param        fromTick  query     int     false  "Tick to replay the logged events from"
+//	@Success      200       {string}  string  "Event stream"
+//	@Failure      400       {string}  string  "Invalid tick"
+//	@Router       /events/sse [get]
+func ServerSentEvents(clients *EventClients) func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		var fromTick uint64
+		replay, required := false, false
+		if from := c.Query("fromTick"); from != "" {
+			tick, err := strconv.ParseUint(from, 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid fromTick: "+from)
+			}
+			fromTick, replay, required = tick, true, true
+		} else if lastID := c.Get("Last-Event-ID"); lastID != "" {
+			// A client that reconnects resumes after the last tick it received, if it can be replayed.
+			if tick, err := strconv.ParseUint(lastID, 10, 64); err == nil {
+				fromTick, replay = tick+1, true
+			}
+		}
+
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		c.Set(fiber.HeaderConnection, "keep-alive")
+		c.Set("X-Accel-Buffering", "no")
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			client := &eventClient{
+				encoding: EventEncodingJSON,
+				frames:   make(chan eventFrame, EventClientBuffer),
+			}
+			defer clients.remove(client)
+			send := func(frame eventFrame) error {
+				if frame.ticked {
+					if _, err := fmt.Fprintf(w, "id: %d\n", frame.tick); err != nil {
+						return err
+					}
+				}
+				if _, err := fmt.Fprintf(w, "data: %s\n\n", frame.data); err != nil {
+					return err
+				}
+				return w.Flush()
+			}
+			if replay {
+				if err := clients.backfill(client, fromTick, send); err != nil {
+					if required {
+						log.Debug().Err(err).Msg("failed to replay events to event stream")
+						_, _ = fmt.Fprintf(w, "event: error\ndata: %s\n\n", strconv.Quote(err.Error()))
+						_ = w.Flush()
+						return
+					}
+					// Resuming is best effort, like connecting without fromTick.
+					clients.add(client)
+				}
+			} else {
+				clients.add(client)
+			}
+			log.Debug().Msg("new event stream established")
+
+			keepAlive := time.NewTicker(EventStreamKeepAlive)
+			defer keepAlive.Stop()
+			for {
+				select {
+				case frame, ok := <-client.frames:
+					if !ok {
+						return
+					}
+					if frame.ticked && frame.tick < client.next {
+						continue
+					}
+					if err := send(frame); err != nil {
+						return
+					}
+				case <-keepAlive.C:
+					if _, err := w.WriteString(": keep-alive\n\n"); err != nil {
+						return
+					}
+					if err := w.Flush(); err != nil {
+						return
+					}
+				}
+			}
+		})
+		return nil
+	}
+}
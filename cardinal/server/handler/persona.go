@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"pkg.world.dev/world-engine/cardinal/persona"
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+)
+
+type GetPersonaExistsResponse struct {
+	Exists bool `json:"exists"`
+}
+
+// GetPersonaExists godoc
+//
+//	@Summary      Checks whether a persona tag has been registered
+//	@Description  Checks whether a persona tag has been registered, ignoring case, as of the last committed tick.
+//	@Description  It is meant for checking names as they are typed, before a create-persona transaction is sent.
+//	@Produce      application/json
+//	@Param        tag  query     string                    true  "Persona tag to check"
+//	@Success      200  {object}  GetPersonaExistsResponse  "Whether the persona tag exists"
+//	@Failure      400  {string}  string                    "Missing persona tag"
+//	@Failure      503  {string}  string                    "No tick has been committed yet"
+//	@Router       /query/persona/exists [get]
+func GetPersonaExists(provider servertypes.Provider) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		tag := ctx.Query("tag")
+		if tag == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "tag is required")
+		}
+		exists, err := provider.PersonaTagExists(tag)
+		if errors.Is(err, persona.ErrCreatePersonaTxsNotProcessed) {
+			return fiber.NewError(fiber.StatusServiceUnavailable, err.Error())
+		} else if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		return ctx.JSON(GetPersonaExistsResponse{Exists: exists})
+	}
+}
+
+type GetPersonaInfoResponse struct {
+	PersonaTag string `json:"personaTag"`
+	// PrimaryPersonaTag is the persona tag that the requested persona tag resolves to, if it is an alias. The rest of
+	// the response describes the primary persona.
+	PrimaryPersonaTag   string                  `json:"primaryPersonaTag,omitempty"`
+	SignerAddress       string                  `json:"signerAddress"`
+	AuthorizedAddresses []string                `json:"authorizedAddresses"`
+	SessionKeys         []PersonaSessionKeyInfo `json:"sessionKeys"`
+	CreatedAtTick       uint64                  `json:"createdAtTick"`
+}
+
+// PersonaSessionKeyInfo is a session key of a persona. A zero expiry means that the key doesn't expire by that measure.
+type PersonaSessionKeyInfo struct {
+	Address       string   `json:"address"`
+	ExpiresAtTick uint64   `json:"expiresAtTick,omitempty"`
+	ExpiresAt     uint64   `json:"expiresAt,omitempty"`
+	Messages      []string `json:"messages,omitempty"`
+}
+
+// GetPersonaInfo godoc
+//
+//	@Summary      Returns the signers of a persona
+//	@Description  Returns the signer address, authorized addresses and session keys of a persona tag, and the tick in
+//	@Description  which it was created, as of the last committed tick. The persona tag must have the case it was
+//	@Description  registered with. Session keys that have expired are left out.
+//	@Produce      application/json
+//	@Param        tag  query     string                  true  "Persona tag to look up"
+//	@Success      200  {object}  GetPersonaInfoResponse  "Signers of the persona"
+//	@Failure      400  {string}  string                  "Missing persona tag"
+//	@Failure      404  {string}  string                  "Persona tag not found"
+//	@Failure      503  {string}  string                  "No tick has been committed yet"
+//	@Router       /query/persona/info [get]
+func GetPersonaInfo(provider servertypes.Provider) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		tag := ctx.Query("tag")
+		if tag == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "tag is required")
+		}
+		wCtx := provider.GetReadOnlyCtx()
+		if wCtx.CurrentTick() == 0 {
+			return fiber.NewError(fiber.StatusServiceUnavailable, persona.ErrCreatePersonaTxsNotProcessed.Error())
+		}
+		sc, err := provider.GetSignerComponentForPersona(tag)
+		if err != nil {
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		}
+
+		res := GetPersonaInfoResponse{
+			PersonaTag:          tag,
+			SignerAddress:       sc.SignerAddress,
+			AuthorizedAddresses: sc.AuthorizedAddresses,
+			SessionKeys:         make([]PersonaSessionKeyInfo, 0, len(sc.SessionKeys)),
+			CreatedAtTick:       sc.CreatedAtTick,
+		}
+		if sc.PersonaTag != tag {
+			res.PrimaryPersonaTag = sc.PersonaTag
+		}
+		if res.AuthorizedAddresses == nil {
+			res.AuthorizedAddresses = make([]string, 0)
+		}
+		for _, k := range sc.SessionKeys {
+			if !k.IsActiveAt(wCtx.CurrentTick(), wCtx.Timestamp()) {
+				continue
+			}
+			res.SessionKeys = append(res.SessionKeys, PersonaSessionKeyInfo{
+				Address:       k.Address,
+				ExpiresAtTick: k.ExpiresAtTick,
+				ExpiresAt:     k.ExpiresAt,
+				Messages:      k.Messages,
+			})
+		}
+		return ctx.JSON(res)
+	}
+}
+
+const (
+	defaultPersonaListLimit = 100
+	maxPersonaListLimit     = 1000
+)
+
+// ListPersonasRequest searches the personas by the prefix of their tags, ignoring case, such as "al" or "al*". An
+// empty prefix lists every persona. Cursor is the NextCursor of the previous page, if any. Limit defaults to 100, and
+// is at most 1000.
+type ListPersonasRequest struct {
+	Prefix string `json:"prefix"`
+	Cursor string `json:"cursor"`
+	Limit  int    `json:"limit"`
+}
+
+// ListPersonasResponse contains a page of the matching personas, ordered by their lowercase tags. NextCursor is empty
+// on the last page.
+type ListPersonasResponse struct {
+	Personas   []servertypes.PersonaSummary `json:"personas"`
+	NextCursor string                       `json:"nextCursor,omitempty"`
+}
+
+// ListPersonas godoc
+//
+//	@Summary      Lists the personas
+//	@Description  Lists the personas whose tags start with a prefix, a page at a time, as of the last committed tick
+//	@Accept       application/json
+//	@Produce      application/json
+//	@Param        ListPersonasRequest  body      ListPersonasRequest   true  "Query body"
+//	@Success      200                  {object}  ListPersonasResponse  "Page of personas"
+//	@Failure      400                  {string}  string                "Invalid request body"
+//	@Failure      503                  {string}  string                "No tick has been committed yet"
+//	@Router       /query/persona/list [post]
+func ListPersonas(provider servertypes.Provider) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		req := new(ListPersonasRequest)
+		if err := ctx.BodyParser(req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+		}
+		if req.Limit < 0 || req.Limit > maxPersonaListLimit {
+			return fiber.NewError(fiber.StatusBadRequest, "limit must be between 0 and 1000")
+		}
+		if req.Limit == 0 {
+			req.Limit = defaultPersonaListLimit
+		}
+		personas, next, err := provider.ListPersonas(req.Prefix, req.Cursor, req.Limit)
+		if errors.Is(err, persona.ErrCreatePersonaTxsNotProcessed) {
+			return fiber.NewError(fiber.StatusServiceUnavailable, err.Error())
+		} else if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		return ctx.JSON(ListPersonasResponse{Personas: personas, NextCursor: next})
+	}
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/rotisserie/eris"
+)
+
+// projection is the tree of the fields to keep in a JSON value. A field without children is kept whole.
+type projection map[string]projection
+
+// parseProjection parses a comma separated list of field paths, such as "name,stats.hp,items.id". The segments of a
+// path are object keys; a path into an array applies to each of its elements.
+func parseProjection(fields string) (projection, error) {
+	p := projection{}
+	for _, path := range strings.Split(fields, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		node := p
+		segments := strings.Split(path, ".")
+		for i, segment := range segments {
+			if segment == "" {
+				return nil, eris.Errorf("invalid field path %q", path)
+			}
+			child, ok := node[segment]
+			switch {
+			case ok && child == nil:
+				// A parent of the path is already kept whole.
+			case i == len(segments)-1:
+				node[segment] = nil
+			case !ok:
+				child = projection{}
+				node[segment] = child
+			}
+			if child == nil {
+				break
+			}
+			node = child
+		}
+	}
+	if len(p) == 0 {
+		return nil, eris.New("no fields to keep")
+	}
+	return p, nil
+}
+
+// apply returns the JSON value with only the projected fields. Fields that the value doesn't have are left out.
+func (p projection) apply(bz []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	// Numbers are kept as they are written, so that large integers such as entity IDs are not rounded.
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, eris.Wrap(err, "failed to decode query result")
+	}
+	out, err := json.Marshal(p.project(v))
+	return out, eris.Wrap(err, "")
+}
+
+func (p projection) project(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(p))
+		for key, child := range p {
+			value, ok := v[key]
+			if !ok {
+				continue
+			}
+			if child == nil {
+				out[key] = value
+			} else {
+				out[key] = child.project(value)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, elem := range v {
+			out[i] = p.project(elem)
+		}
+		return out
+	}
+	// Scalars have no fields, so a path into them is ignored.
+	return v
+}
@@ -6,6 +6,20 @@ import (
 	"pkg.world.dev/world-engine/cardinal/types/engine"
 )
 
+// CallerPersonaLocal is the key of the fiber local that holds the persona tag of the caller of a request, when the
+// caller is authenticated by a token that maps to a persona.
+const CallerPersonaLocal = "callerPersonaTag"
+
+// callerContext is the context of a query whose caller is authenticated as a persona.
+type callerContext struct {
+	engine.Context
+	personaTag string
+}
+
+func (c callerContext) CallerPersonaTag() string {
+	return c.personaTag
+}
+
 // PostQuery godoc
 //
 //	@Summary      Executes a query
@@ -15,6 +29,7 @@ import (
 //	@Param        queryGroup  path      string  true  "Query group"
 //	@Param        queryName   path      string  true  "Name of a registered query"
 //	@Param        queryBody   body      object  true  "Query to be executed"
+//	@Param        fields      query     string  false "Comma separated paths of the result fields to keep"
 //	@Success      200         {object}  object  "Results of the executed query"
 //	@Failure      400         {string}  string  "Invalid request parameters"
 //	@Router       /query/{queryGroup}/{queryName} [post]
@@ -25,11 +40,30 @@ func PostQuery(queries map[string]map[string]engine.Query, wCtx engine.Context)
 			return fiber.NewError(fiber.StatusNotFound, "query name not found")
 		}
 
+		// Clients that only need a few fields of a large result can ask for just those fields
+		var fields projection
+		if fieldsParam := ctx.Query("fields"); fieldsParam != "" {
+			var err error
+			if fields, err = parseProjection(fieldsParam); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid fields: "+err.Error())
+			}
+		}
+
+		queryCtx := wCtx
+		if personaTag, ok := ctx.Locals(CallerPersonaLocal).(string); ok {
+			queryCtx = callerContext{Context: wCtx, personaTag: personaTag}
+		}
+
 		ctx.Set("Content-Type", "application/json")
-		resBz, err := query.HandleQueryRaw(wCtx, ctx.Body())
+		resBz, err := query.HandleQueryRaw(queryCtx, ctx.Body())
 		if err != nil {
 			return fiber.NewError(fiber.StatusBadRequest, "encountered an error in query: "+err.Error())
 		}
+		if fields != nil {
+			if resBz, err = fields.apply(resBz); err != nil {
+				return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+			}
+		}
 
 		return ctx.Send(resBz)
 	}
@@ -44,6 +78,7 @@ func PostQuery(queries map[string]map[string]engine.Query, wCtx engine.Context)
 //	@Produce      application/json
 //	@Param        queryName   path      string  true  "Name of a registered query"
 //	@Param        queryBody   body      object  true  "Query to be executed"
+//	@Param        fields      query     string  false "Comma separated paths of the result fields to keep"
 //	@Success      200         {object}  object  "Results of the executed query"
 //	@Failure      400         {string}  string  "Invalid request parameters"
 //	@Router       /query/game/{queryName} [post]
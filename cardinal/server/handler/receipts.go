@@ -3,6 +3,8 @@ package handler
 import (
 	"github.com/gofiber/fiber/v2"
 
+	"pkg.world.dev/world-engine/cardinal/receipt"
+	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/cardinal/types/engine"
 )
 
@@ -26,6 +28,11 @@ type ReceiptEntry struct {
 	Tick   uint64   `json:"tick"`
 	Result any      `json:"result"`
 	Errors []string `json:"errors"`
+	// ErrorDetails are the codes and parameters of the errors, in the same order, for clients that localize errors or
+	// branch on them.
+	ErrorDetails []receipt.ErrorDetail `json:"errorDetails,omitempty"`
+	// Impersonator is the admin signer address that submitted the transaction on behalf of its persona, if any.
+	Impersonator string `json:"impersonator,omitempty"`
 }
 
 // GetReceipts godoc
@@ -38,7 +45,7 @@ type ReceiptEntry struct {
 //	@Success      200                    {object}  ListTxReceiptsResponse "List of receipts"
 //	@Failure      400                    {string}  string                 "Invalid request body"
 //	@Router       /query/receipts/list [post]
-func GetReceipts(wCtx engine.Context) func(*fiber.Ctx) error {
+func GetReceipts(wCtx engine.Context, codes *receipt.ErrorCodes) func(*fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		req := new(ListTxReceiptsRequest)
 		if err := ctx.BodyParser(req); err != nil {
@@ -67,18 +74,79 @@ func GetReceipts(wCtx engine.Context) func(*fiber.Ctx) error {
 				continue
 			}
 			for _, r := range currReceipts {
-				reply.Receipts = append(reply.Receipts, ReceiptEntry{
-					TxHash: string(r.TxHash),
-					Tick:   t,
-					Result: r.Result,
-					Errors: convertErrorsToStrings(r.Errs),
-				})
+				reply.Receipts = append(reply.Receipts, newReceiptEntry(r, t, codes))
 			}
 		}
 		return ctx.JSON(reply)
 	}
 }
 
+type ReceiptProofRequest struct {
+	TxHash string `json:"txHash"`
+	Tick   uint64 `json:"tick"`
+}
+
+// ReceiptProofResponse holds a transaction's receipt, and the proof that it is included in the receipt tree of its
+// tick. Leaf is the encoded receipt that the proof is for: receipt.VerifyProof(Root, Leaf, Proof) reports whether
+// the receipt is included in the tree with the given root.
+type ReceiptProofResponse struct {
+	Receipt ReceiptEntry        `json:"receipt"`
+	Root    []byte              `json:"root"`
+	Leaf    []byte              `json:"leaf"`
+	Proof   []receipt.ProofStep `json:"proof"`
+}
+
+// GetReceiptProof godoc
+//
+//	@Summary      Retrieves a transaction receipt and its inclusion proof
+//	@Description  Retrieves a transaction receipt and the proof that it is included in the receipt tree of its tick
+//	@Accept       application/json
+//	@Produce      application/json
+//	@Param        ReceiptProofRequest  body      ReceiptProofRequest   true  "Transaction hash and tick"
+//	@Success      200                  {object}  ReceiptProofResponse  "Receipt and inclusion proof"
+//	@Failure      400                  {string}  string                "Invalid request body"
+//	@Failure      404                  {string}  string                "Receipt not found"
+//	@Router       /query/receipts/proof [post]
+func GetReceiptProof(wCtx engine.Context, codes *receipt.ErrorCodes) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		req := new(ReceiptProofRequest)
+		if err := ctx.BodyParser(req); err != nil {
+			return err
+		}
+		receipts, err := wCtx.GetTransactionReceiptsForTick(req.Tick)
+		if err != nil {
+			return fiber.NewError(fiber.StatusNotFound, "no receipts for tick: "+err.Error())
+		}
+		tree, err := receipt.NewTree(receipts)
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to build receipt tree: "+err.Error())
+		}
+		leaf, proof, ok := tree.Proof(types.TxHash(req.TxHash))
+		if !ok {
+			return fiber.NewError(fiber.StatusNotFound, "no receipt for transaction in tick")
+		}
+		reply := ReceiptProofResponse{Root: tree.Root(), Leaf: leaf, Proof: proof}
+		for _, r := range receipts {
+			if r.TxHash == types.TxHash(req.TxHash) {
+				reply.Receipt = newReceiptEntry(r, req.Tick, codes)
+			}
+		}
+		return ctx.JSON(reply)
+	}
+}
+
+// newReceiptEntry returns the entry of the receipt of a transaction that was executed in the given tick.
+func newReceiptEntry(r receipt.Receipt, tick uint64, codes *receipt.ErrorCodes) ReceiptEntry {
+	return ReceiptEntry{
+		TxHash:       string(r.TxHash),
+		Tick:         tick,
+		Result:       r.Result,
+		Errors:       convertErrorsToStrings(r.Errs),
+		ErrorDetails: codes.Details(r.Errs),
+		Impersonator: r.Impersonator,
+	}
+}
+
 func convertErrorsToStrings(errs []error) []string {
 	if len(errs) == 0 {
 		return nil
@@ -18,6 +18,7 @@ var (
 	ErrWrongNamespace             = errors.New("incorrect namespace")
 	ErrSystemTransactionRequired  = errors.New("system transaction required")
 	ErrSystemTransactionForbidden = errors.New("system transaction forbidden")
+	ErrNotAdminSigner             = errors.New("system transaction is not signed by an admin signer")
 )
 
 // PostTransactionResponse is the HTTP response for a successful transaction submission
@@ -51,44 +52,90 @@ func PostTransaction(
 
 		// Parse the request body into a sign.Transaction struct
 		tx := new(Transaction)
-		if err := ctx.BodyParser(tx); err != nil {
-			return fiber.NewError(fiber.StatusBadRequest, "failed to parse request body: "+err.Error())
+		reject := func(err error) error {
+			provider.NotifyTxRejected(msgType.FullName(), tx, err)
+			return err
 		}
-
-		// Validate the transaction
-		if err := validateTx(tx); err != nil {
-			return fiber.NewError(fiber.StatusBadRequest, "invalid transaction payload: "+err.Error())
+		if err := ctx.BodyParser(tx); err != nil {
+			return reject(fiber.NewError(fiber.StatusBadRequest, "failed to parse request body: "+err.Error()))
 		}
 
-		// Decode the message from the transaction
-		msg, err := msgType.Decode(tx.Body)
+		res, err := SubmitTransaction(provider, msgType, tx, disableSigVerification)
 		if err != nil {
-			return fiber.NewError(fiber.StatusBadRequest, "failed to decode message from transaction")
+			return err
 		}
+		return ctx.JSON(res)
+	}
+}
 
-		if !disableSigVerification {
-			var signerAddress string
-			// TODO(scott): don't hardcode this
-			if msgType.Name() == "create-persona" {
-				// don't need to check the cast bc we already validated this above
-				createPersonaMsg, _ := msg.(personaMsg.CreatePersona)
-				signerAddress = createPersonaMsg.SignerAddress
-			}
+// SubmitTransaction validates the transaction, checks its signature unless signature verification is disabled, and
+// adds it to the world. The errors it returns are fiber errors with the HTTP status of the failure, and they are
+// reported to the provider as rejections.
+func SubmitTransaction(
+	provider servertypes.Provider, msgType types.Message, tx *Transaction, disableSigVerification bool,
+) (*PostTransactionResponse, error) {
+	reject := func(err error) (*PostTransactionResponse, error) {
+		provider.NotifyTxRejected(msgType.FullName(), tx, err)
+		return nil, err
+	}
 
-			if err = lookupSignerAndValidateSignature(provider, signerAddress, tx); err != nil {
-				return err
+	// Validate the transaction
+	if err := validateTx(tx); err != nil {
+		return reject(fiber.NewError(fiber.StatusBadRequest, "invalid transaction payload: "+err.Error()))
+	}
+
+	// Decode the message from the transaction
+	msg, err := msgType.Decode(tx.Body)
+	if err != nil {
+		return reject(fiber.NewError(fiber.StatusBadRequest,
+			"failed to decode message from transaction: "+err.Error()))
+	}
+
+	var impersonator string
+	if !disableSigVerification {
+		// Messages that claim a persona tag are signed by the signer they name, since the persona doesn't
+		// exist yet
+		signerAddress, isClaim := personaClaimSigner(msg)
+		switch {
+		case isClaim:
+			err = lookupSignerAndValidateSignature(provider, signerAddress, tx)
+		case isSignerChange(msg):
+			// Only the current signer may hand the persona to a new signer
+			err = lookupCurrentSignerAndValidateSignature(provider, tx)
+		case requiresAdminSignature(msgType):
+			// Messages that operate on the whole world must be system transactions signed by an admin signer
+			err = validateAdminSignature(provider, tx)
+		default:
+			if err = lookupSignerAndValidateSignature(provider, "", tx); err != nil {
+				// The transaction may have been signed by a session key of the persona, or by an admin signer on its
+				// behalf
+				if err = lookupSessionKey(provider, msgType.FullName(), tx, err); err != nil {
+					impersonator, err = lookupImpersonator(provider, tx, err)
+				}
 			}
 		}
+		if err != nil {
+			return reject(err)
+		}
+	}
 
-		// Add the transaction to the engine
-		// TODO(scott): this should just deal with txpool instead of having to go through engine
-		tick, hash := provider.AddTransaction(msgType.ID(), msg, tx)
-
-		return ctx.JSON(&PostTransactionResponse{
-			TxHash: string(hash),
-			Tick:   tick,
-		})
+	// Add the transaction to the engine
+	// TODO(scott): this should just deal with txpool instead of having to go through engine
+	var tick uint64
+	var hash types.TxHash
+	if impersonator != "" {
+		tick, hash, err = provider.AddImpersonatedTransaction(msgType.ID(), msg, tx, impersonator)
+		if err != nil {
+			return reject(fiber.NewError(fiber.StatusForbidden, err.Error()))
+		}
+	} else {
+		tick, hash = provider.AddTransaction(msgType.ID(), msg, tx)
 	}
+
+	return &PostTransactionResponse{
+		TxHash: string(hash),
+		Tick:   tick,
+	}, nil
 }
 
 // NOTE: duplication for cleaner swagger docs
@@ -126,16 +173,59 @@ func PostPersonaTransaction(
 	return PostTransaction(provider, msgs, disableSigVerification)
 }
 
+// personaClaimSigner returns the signer address named by a message that claims a persona tag, and whether the message
+// is such a claim.
+func personaClaimSigner(msg any) (string, bool) {
+	switch m := msg.(type) {
+	case personaMsg.CreatePersona:
+		return m.SignerAddress, true
+	case personaMsg.ReservePersona:
+		return m.SignerAddress, true
+	case personaMsg.ConfirmPersona:
+		return m.SignerAddress, true
+	default:
+		return "", false
+	}
+}
+
+// isSignerChange reports whether the message replaces the signer of the persona that sends it. Only the current
+// signer may do so, since a signer in a rotation's grace period could otherwise lock the new signer out.
+func isSignerChange(msg any) bool {
+	switch msg.(type) {
+	case personaMsg.TransferPersona, personaMsg.ChangeSigner, personaMsg.RotatePersonaSigner:
+		return true
+	default:
+		return false
+	}
+}
+
+// lookupCurrentSignerAndValidateSignature validates the signature of the transaction against the current signer of
+// its persona. Signers in a rotation's grace period and admin signers are not accepted.
+func lookupCurrentSignerAndValidateSignature(provider servertypes.Provider, tx *Transaction) error {
+	signers, err := provider.GetValidSignersForPersonaTag(tx.PersonaTag)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "could not get signer for persona: "+err.Error())
+	}
+	return lookupSignerAndValidateSignature(provider, signers[0], tx)
+}
+
 func lookupSignerAndValidateSignature(provider servertypes.Provider, signerAddress string, tx *Transaction) error {
 	var err error
+	candidates := []string{signerAddress}
 	if signerAddress == "" {
-		signerAddress, err = provider.GetSignerForPersonaTag(tx.PersonaTag, 0)
+		// A persona may have more than one valid signer while a signer rotation's grace period is in effect.
+		candidates, err = provider.GetValidSignersForPersonaTag(tx.PersonaTag)
 		if err != nil {
 			return fiber.NewError(fiber.StatusBadRequest, "could not get signer for persona: "+err.Error())
 		}
 	}
-	if err = validateSignature(tx, signerAddress, provider.Namespace(),
-		tx.IsSystemTransaction()); err != nil {
+	for _, candidate := range candidates {
+		if err = validateSignature(tx, candidate, provider.Namespace(), tx.IsSystemTransaction()); err == nil {
+			signerAddress = candidate
+			break
+		}
+	}
+	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "failed to validate transaction: "+err.Error())
 	}
 	// TODO(scott): this should be refactored; it should be the responsibility of the engine tx processor
@@ -146,6 +236,73 @@ func lookupSignerAndValidateSignature(provider servertypes.Provider, signerAddre
 	return nil
 }
 
+// lookupSessionKey checks that the transaction was signed by a session key of its persona that may sign the message.
+// If no such key signed it, signatureErr, the error of validating the signature against the persona's signers, is
+// returned.
+func lookupSessionKey(provider servertypes.Provider, msgName string, tx *Transaction, signatureErr error) error {
+	keys, err := provider.GetSessionKeySignersForPersonaTag(tx.PersonaTag, msgName)
+	if err != nil {
+		return signatureErr
+	}
+	for _, key := range keys {
+		if validateSignature(tx, key, provider.Namespace(), false) != nil {
+			continue
+		}
+		if err = provider.UseNonce(key, tx.Nonce); err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to use nonce: "+err.Error())
+		}
+		return nil
+	}
+	return signatureErr
+}
+
+// lookupImpersonator returns the admin signer that signed the transaction on behalf of its persona. If no admin
+// signer signed it, signatureErr, the error of validating the signature against the persona's signers, is returned.
+func lookupImpersonator(provider servertypes.Provider, tx *Transaction, signatureErr error) (string, error) {
+	if tx.IsSystemTransaction() {
+		return "", signatureErr
+	}
+	// Only existing personas can be impersonated
+	if _, err := provider.GetValidSignersForPersonaTag(tx.PersonaTag); err != nil {
+		return "", signatureErr
+	}
+	for _, admin := range provider.ImpersonationSigners() {
+		if validateSignature(tx, admin, provider.Namespace(), false) != nil {
+			continue
+		}
+		if err := provider.UseNonce(admin, tx.Nonce); err != nil {
+			return "", fiber.NewError(fiber.StatusInternalServerError, "failed to use nonce: "+err.Error())
+		}
+		return admin, nil
+	}
+	return "", signatureErr
+}
+
+// requiresAdminSignature reports whether the message was declared with message.WithAdminSignature.
+func requiresAdminSignature(msgType types.Message) bool {
+	m, ok := msgType.(interface{ RequiresAdminSignature() bool })
+	return ok && m.RequiresAdminSignature()
+}
+
+// validateAdminSignature checks that the transaction is a system transaction signed by one of the world's admin
+// signers.
+func validateAdminSignature(provider servertypes.Provider, tx *Transaction) error {
+	if !tx.IsSystemTransaction() {
+		return fiber.NewError(fiber.StatusBadRequest, "failed to validate transaction: "+
+			ErrSystemTransactionRequired.Error())
+	}
+	for _, admin := range provider.AdminSigners() {
+		if validateSignature(tx, admin, provider.Namespace(), true) != nil {
+			continue
+		}
+		if err := provider.UseNonce(admin, tx.Nonce); err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to use nonce: "+err.Error())
+		}
+		return nil
+	}
+	return fiber.NewError(fiber.StatusBadRequest, "failed to validate transaction: "+ErrNotAdminSigner.Error())
+}
+
 // validateTx validates the transaction payload
 func validateTx(tx *Transaction) error {
 	// TODO(scott): we should use the validator package here
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rotisserie/eris"
+
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/sign"
+)
+
+var (
+	ErrPersonaCreationInBatch = errors.New("persona tags cannot be claimed in a batch")
+	ErrSignerChangeInBatch    = errors.New("the signer of a persona cannot be changed in a batch")
+)
+
+// PostBatchResponse is the HTTP response for a successful batch submission. The transactions are in the order of
+// the batch.
+type PostBatchResponse struct {
+	Transactions []PostTransactionResponse
+}
+
+type Batch = sign.Batch
+
+// PostBatch godoc
+//
+//	@Summary      Submits a batch of transactions
+//	@Description  Submits a batch of transactions from one persona that are signed with a single signature. The
+//	@Description  transactions are queued in order, or, if any of them is invalid, not at all.
+//	@Accept       application/json
+//	@Produce      application/json
+//	@Param        batch  body      Batch              true  "Batch details & messages to be submitted"
+//	@Success      200    {object}  PostBatchResponse  "Transaction hashes and ticks"
+//	@Failure      400    {string}  string             "Invalid request parameter"
+//	@Router       /tx/batch [post]
+func PostBatch(
+	provider servertypes.Provider, msgs map[string]map[string]types.Message, disableSigVerification bool,
+) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		batch := new(Batch)
+		var txs []*Transaction
+		reject := func(err error) error {
+			for i, tx := range txs {
+				provider.NotifyTxRejected(batch.Transactions[i].Message, tx, err)
+			}
+			return err
+		}
+		if err := ctx.BodyParser(batch); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "failed to parse request body: "+err.Error())
+		}
+		txs = batch.Unpack()
+
+		// Validate the batch
+		if batch.PersonaTag == "" {
+			return reject(fiber.NewError(fiber.StatusBadRequest, "invalid batch payload: "+ErrNoPersonaTag.Error()))
+		}
+		if len(batch.Transactions) == 0 {
+			return reject(fiber.NewError(fiber.StatusBadRequest, "invalid batch payload: "+sign.ErrEmptyBatch.Error()))
+		}
+
+		// Decode every message before any of them is queued, so that the batch is queued either in full or not at all
+		msgTypes := make([]types.Message, len(txs))
+		decoded := make([]any, len(txs))
+		for i, entry := range batch.Transactions {
+			group, name, _ := strings.Cut(entry.Message, ".")
+			msgType, ok := msgs[group][name]
+			if !ok {
+				return reject(fiber.NewError(fiber.StatusNotFound, "message type not found: "+entry.Message))
+			}
+			msg, err := msgType.Decode(entry.Body)
+			if err != nil {
+				return reject(fiber.NewError(fiber.StatusBadRequest,
+					"failed to decode message "+entry.Message+" from batch: "+err.Error()))
+			}
+			if _, ok := personaClaimSigner(msg); ok {
+				return reject(fiber.NewError(fiber.StatusBadRequest, ErrPersonaCreationInBatch.Error()))
+			}
+			if isSignerChange(msg) {
+				// Batches may be signed by a signer in its rotation grace period, which must not change the signer; see
+				// isSignerChange
+				return reject(fiber.NewError(fiber.StatusBadRequest, ErrSignerChangeInBatch.Error()))
+			}
+			msgTypes[i], decoded[i] = msgType, msg
+		}
+
+		if !disableSigVerification {
+			if err := lookupSignerAndValidateBatch(provider, batch); err != nil {
+				return reject(err)
+			}
+		}
+
+		res := PostBatchResponse{Transactions: make([]PostTransactionResponse, 0, len(txs))}
+		for i, tx := range txs {
+			tick, hash := provider.AddTransaction(msgTypes[i].ID(), decoded[i], tx)
+			res.Transactions = append(res.Transactions, PostTransactionResponse{TxHash: string(hash), Tick: tick})
+		}
+		return ctx.JSON(&res)
+	}
+}
+
+// lookupSignerAndValidateBatch validates the signature of the batch against the signers of its persona, and uses the
+// nonce of each of its transactions.
+func lookupSignerAndValidateBatch(provider servertypes.Provider, batch *Batch) error {
+	candidates, err := provider.GetValidSignersForPersonaTag(batch.PersonaTag)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "could not get signer for persona: "+err.Error())
+	}
+	var signerAddress string
+	for _, candidate := range candidates {
+		if err = validateBatchSignature(batch, candidate, provider.Namespace()); err == nil {
+			signerAddress = candidate
+			break
+		}
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "failed to validate batch: "+err.Error())
+	}
+	for i := range batch.Transactions {
+		if err = provider.UseNonce(signerAddress, batch.Nonce+uint64(i)); err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "failed to use nonce: "+err.Error())
+		}
+	}
+	return nil
+}
+
+// validateBatchSignature validates that the signature of the batch is valid
+func validateBatchSignature(batch *Batch, signerAddr string, namespace string) error {
+	if batch.Namespace != namespace {
+		return eris.Wrap(ErrWrongNamespace, fmt.Sprintf("expected %q got %q", namespace, batch.Namespace))
+	}
+	if batch.PersonaTag == sign.SystemPersonaTag {
+		return eris.Wrap(ErrSystemTransactionForbidden, "")
+	}
+	return eris.Wrap(batch.Verify(signerAddr), "")
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
 	"pkg.world.dev/world-engine/cardinal/server/utils"
 	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/cardinal/types/engine"
@@ -15,6 +16,10 @@ type GetWorldResponse struct {
 	Components []FieldDetail `json:"components"` // list of component names
 	Messages   []FieldDetail `json:"messages"`
 	Queries    []FieldDetail `json:"queries"`
+	// Modules are the modules the world uses, along with their versions.
+	Modules []servertypes.ModuleInfo `json:"modules"`
+	// ConfigHash identifies the world's configuration; replicas with the same config hash execute ticks alike.
+	ConfigHash string `json:"configHash"`
 }
 
 type FieldDetail struct {
@@ -26,7 +31,7 @@ type FieldDetail struct {
 // GetWorld godoc
 //
 //	@Summary      Retrieves details of the game world
-//	@Description  Contains the registered components, messages, queries, and namespace
+//	@Description  Contains the registered components, messages, queries, modules, and namespace
 //	@Accept       application/json
 //	@Produce      application/json
 //	@Success      200  {object}  GetWorldResponse  "Details of the game world"
@@ -34,8 +39,12 @@ type FieldDetail struct {
 //	@Router       /world [get]
 func GetWorld(
 	components []types.ComponentMetadata, messages []types.Message,
-	queries []engine.Query, namespace string,
+	queries []engine.Query, modules []servertypes.ModuleInfo, namespace string, configHash func() string,
 ) func(*fiber.Ctx) error {
+	if modules == nil {
+		modules = []servertypes.ModuleInfo{}
+	}
+
 	// Collecting name of all registered components
 	comps := make([]FieldDetail, 0, len(components))
 	for _, component := range components {
@@ -74,6 +83,8 @@ func GetWorld(
 			Components: comps,
 			Messages:   messagesFields,
 			Queries:    queriesFields,
+			Modules:    modules,
+			ConfigHash: configHash(),
 		})
 	}
 }
@@ -0,0 +1,377 @@
+package server
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+)
+
+// DefaultJWTPaths are the endpoints that require a token by default: queries, CQL, and the debug endpoints.
+var DefaultJWTPaths = []string{"/query", "/cql", "/debug"}
+
+const (
+	// jwtLeeway is how far the clocks of the identity provider and Cardinal may drift apart.
+	jwtLeeway = time.Minute
+	// jwksMaxAge is how long the keys of the identity provider are used before they are fetched again.
+	jwksMaxAge = time.Hour
+	// jwksMinRefresh is how long to wait before fetching the keys again, when a token is signed by an unknown key.
+	jwksMinRefresh = time.Minute
+	jwksTimeout    = 10 * time.Second
+)
+
+// JWTConfig configures the validation of the JSON Web Tokens that an identity provider issues, so that the users it
+// authenticates, such as the users of a studio's web dashboards, can call the endpoints in Paths. Tokens are sent in
+// the Authorization header as bearer tokens, and must be signed with RS256, RS384, RS512, ES256, ES384 or ES512.
+type JWTConfig struct {
+	// Issuer must be the "iss" claim of the tokens.
+	Issuer string
+	// Audience, if set, must be in the "aud" claim of the tokens.
+	Audience string
+	// JWKSURL is the URL of the identity provider's JSON Web Key Set, which has the keys the tokens are signed with.
+	JWKSURL string
+	// PersonaClaim is the claim that holds the persona tag of the caller. Queries get the persona tag with
+	// cardinal.CallerPersonaTag, and tokens without it are rejected. If empty, tokens are not mapped to personas.
+	PersonaClaim string
+	// Paths are the path prefixes of the endpoints that require a token. DefaultJWTPaths are used if it is empty.
+	Paths []string
+	// AllowAnonymous lets requests without an Authorization header through, so that the clients that don't have
+	// tokens, such as the relay, can keep calling the endpoints. Requests with an invalid token are still rejected.
+	AllowAnonymous bool
+}
+
+func (cfg *JWTConfig) validate() error {
+	if cfg.Issuer == "" {
+		return eris.New("JWT issuer must be set")
+	}
+	if cfg.JWKSURL == "" {
+		return eris.New("JWT JWKS URL must be set")
+	}
+	return nil
+}
+
+// jwtVerifier checks the tokens of the requests to the protected endpoints.
+type jwtVerifier struct {
+	cfg  JWTConfig
+	keys *jwks
+}
+
+func newJWTVerifier(cfg JWTConfig) (*jwtVerifier, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if len(cfg.Paths) == 0 {
+		cfg.Paths = DefaultJWTPaths
+	}
+	return &jwtVerifier{
+		cfg:  cfg,
+		keys: &jwks{url: cfg.JWKSURL, client: &http.Client{Timeout: jwksTimeout}},
+	}, nil
+}
+
+func (v *jwtVerifier) middleware(ctx *fiber.Ctx) error {
+	header := ctx.Get(fiber.HeaderAuthorization)
+	if header == "" && v.cfg.AllowAnonymous {
+		return ctx.Next()
+	}
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "missing bearer token")
+	}
+	claims, err := v.verify(token, time.Now())
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "invalid token: "+err.Error())
+	}
+	if v.cfg.PersonaClaim != "" {
+		personaTag, _ := claims[v.cfg.PersonaClaim].(string)
+		if personaTag == "" {
+			return fiber.NewError(fiber.StatusForbidden, "token has no persona tag in claim "+v.cfg.PersonaClaim)
+		}
+		ctx.Locals(handler.CallerPersonaLocal, personaTag)
+	}
+	return ctx.Next()
+}
+
+// verify checks the signature and the claims of the token, and returns its claims.
+func (v *jwtVerifier) verify(token string, now time.Time) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 { //nolint:gomnd // header.payload.signature
+		return nil, eris.New("malformed token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, eris.Wrap(err, "malformed header")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, eris.Wrap(err, "malformed signature")
+	}
+	key, err := v.keys.get(header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], sig); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, eris.Wrap(err, "malformed claims")
+	}
+	if iss, _ := claims["iss"].(string); iss != v.cfg.Issuer {
+		return nil, eris.Errorf("issuer %q is not trusted", iss)
+	}
+	if v.cfg.Audience != "" && !hasAudience(claims["aud"], v.cfg.Audience) {
+		return nil, eris.Errorf("token is not meant for audience %q", v.cfg.Audience)
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, eris.New("token has no expiration")
+	}
+	if now.Add(-jwtLeeway).After(time.Unix(int64(exp), 0)) {
+		return nil, eris.New("token is expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(jwtLeeway).Before(time.Unix(int64(nbf), 0)) {
+		return nil, eris.New("token is not valid yet")
+	}
+	return claims, nil
+}
+
+func decodeSegment(segment string, v any) error {
+	bz, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bz, v)
+}
+
+// hasAudience reports whether the "aud" claim, which is either a string or an array of strings, has the audience.
+func hasAudience(aud any, audience string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == audience
+	case []any:
+		return slices.Contains(aud, any(audience))
+	}
+	return false
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signed string, sig []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		return eris.Errorf("unsupported signing algorithm %q", alg)
+	}
+	h := hash.New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	errInvalid := eris.New("invalid signature")
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") || rsa.VerifyPKCS1v15(key, hash, digest, sig) != nil {
+			return errInvalid
+		}
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8 //nolint:gomnd // bits to bytes
+		if !strings.HasPrefix(alg, "ES") || len(sig) != 2*size {
+			return errInvalid
+		}
+		r, s := new(big.Int).SetBytes(sig[:size]), new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return errInvalid
+		}
+	default:
+		return errInvalid
+	}
+	return nil
+}
+
+// jwks caches the keys of a JSON Web Key Set by their IDs. The keys are fetched again when they are older than
+// jwksMaxAge, or when a token is signed by an unknown key, which happens when the identity provider rotates its keys.
+// Keys are fetched without holding the lock, and one fetch at a time: tokens signed by cached keys are verified while
+// the keys are refreshed, and only tokens signed by an unknown key wait for the fetch.
+type jwks struct {
+	url    string
+	client *http.Client
+
+	mu      sync.Mutex
+	keys    map[string]crypto.PublicKey
+	fetched time.Time
+	// attempted is when the keys were last fetched, whether or not the fetch succeeded, and err is the error of the
+	// fetch if it failed.
+	attempted time.Time
+	err       error
+	// fetching is closed once the fetch in progress, if any, is done.
+	fetching chan struct{}
+}
+
+func (j *jwks) get(kid string) (crypto.PublicKey, error) {
+	j.mu.Lock()
+	key, ok := j.keys[kid]
+	var done chan struct{}
+	if (!ok || time.Since(j.fetched) > jwksMaxAge) && (j.attempted.IsZero() || time.Since(j.attempted) > jwksMinRefresh) {
+		done = j.refresh()
+	}
+	j.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+	if done != nil {
+		<-done
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if key, ok = j.keys[kid]; ok {
+		return key, nil
+	}
+	if j.err != nil {
+		return nil, j.err
+	}
+	return nil, eris.Errorf("unknown signing key %q", kid)
+}
+
+// refresh starts fetching the keys, unless they are already being fetched, and returns a channel that is closed once
+// the fetch is done. j.mu must be held.
+func (j *jwks) refresh() chan struct{} {
+	if j.fetching != nil {
+		return j.fetching
+	}
+	done := make(chan struct{})
+	j.fetching = done
+	go func() {
+		keys, err := j.fetch()
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		j.attempted, j.err = time.Now(), err
+		if err != nil {
+			log.Warn().Err(err).Msg("failed to refresh JWKS")
+		} else {
+			j.keys, j.fetched = keys, j.attempted
+		}
+		j.fetching = nil
+		close(done)
+	}()
+	return done
+}
+
+func (j *jwks) fetch() (map[string]crypto.PublicKey, error) {
+	//nolint:noctx // the client has a timeout
+	resp, err := j.client.Get(j.url)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to fetch JWKS")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, eris.Errorf("failed to fetch JWKS: %s", resp.Status)
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, eris.Wrap(err, "failed to decode JWKS")
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			log.Warn().Err(err).Msgf("skipping JWKS key %q", k.Kid)
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	return keys, nil
+}
+
+// jwk is a public key of a JSON Web Key Set.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// N and E are the modulus and exponent of RSA keys.
+	N string `json:"n"`
+	E string `json:"e"`
+	// Crv, X and Y are the curve and coordinates of EC keys.
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, eris.New("RSA exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, eris.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, eris.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	bz, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(bz) == 0 {
+		return nil, errors.New("empty key parameter")
+	}
+	return new(big.Int).SetBytes(bz), nil
+}
@@ -0,0 +1,162 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/server"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type WhoAmIRequest struct{}
+
+type WhoAmIResponse struct {
+	PersonaTag string
+}
+
+func (s *ServerTestSuite) TestJWTAuth() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "key-1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	}))
+	defer jwks.Close()
+
+	s.setupWorld(cardinal.WithJWTAuth(server.JWTConfig{
+		Issuer:       "https://idp.example.com",
+		Audience:     "dashboard",
+		JWKSURL:      jwks.URL,
+		PersonaClaim: "persona_tag",
+	}))
+	err = cardinal.RegisterQuery[WhoAmIRequest, WhoAmIResponse](
+		s.world,
+		"whoami",
+		func(wCtx engine.Context, _ *WhoAmIRequest) (*WhoAmIResponse, error) {
+			personaTag, _ := cardinal.CallerPersonaTag(wCtx)
+			return &WhoAmIResponse{PersonaTag: personaTag}, nil
+		},
+	)
+	s.Require().NoError(err)
+	s.fixture.DoTick()
+
+	claims := func(overrides map[string]any) map[string]any {
+		c := map[string]any{
+			"iss":         "https://idp.example.com",
+			"aud":         []string{"dashboard", "admin"},
+			"exp":         time.Now().Add(time.Hour).Unix(),
+			"persona_tag": "alice",
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+			} else {
+				c[k] = v
+			}
+		}
+		return c
+	}
+
+	res := s.postWithToken("query/game/whoami", signJWT(s, key, "key-1", claims(nil)))
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+	var whoami WhoAmIResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&whoami))
+	s.Require().Equal("alice", whoami.PersonaTag)
+
+	testCases := []struct {
+		name       string
+		token      string
+		wantStatus int
+	}{
+		{"missing token", "", fiber.StatusUnauthorized},
+		{"untrusted issuer", signJWT(s, key, "key-1", claims(map[string]any{"iss": "https://evil.example.com"})),
+			fiber.StatusUnauthorized},
+		{"wrong audience", signJWT(s, key, "key-1", claims(map[string]any{"aud": "game"})),
+			fiber.StatusUnauthorized},
+		{"expired", signJWT(s, key, "key-1", claims(map[string]any{"exp": time.Now().Add(-time.Hour).Unix()})),
+			fiber.StatusUnauthorized},
+		{"unknown key", signJWT(s, key, "key-2", claims(nil)), fiber.StatusUnauthorized},
+		{"no persona tag", signJWT(s, key, "key-1", claims(map[string]any{"persona_tag": nil})),
+			fiber.StatusForbidden},
+	}
+	for _, tc := range testCases {
+		res := s.postWithToken("query/game/whoami", tc.token)
+		s.Require().Equal(tc.wantStatus, res.StatusCode, tc.name)
+	}
+
+	// Debug endpoints are protected too, but transactions are not.
+	res = s.postWithToken("debug/state", "")
+	s.Require().Equal(fiber.StatusUnauthorized, res.StatusCode)
+	res = s.fixture.Get("health")
+	s.Require().Equal(fiber.StatusOK, res.StatusCode)
+}
+
+func (s *ServerTestSuite) TestJWTAuthAllowsAnonymousRequests() {
+	s.setupWorld(cardinal.WithJWTAuth(server.JWTConfig{
+		Issuer:         "https://idp.example.com",
+		JWKSURL:        "http://127.0.0.1:1/jwks",
+		PersonaClaim:   "persona_tag",
+		AllowAnonymous: true,
+	}))
+	err := cardinal.RegisterQuery[WhoAmIRequest, WhoAmIResponse](
+		s.world,
+		"whoami",
+		func(wCtx engine.Context, _ *WhoAmIRequest) (*WhoAmIResponse, error) {
+			_, ok := cardinal.CallerPersonaTag(wCtx)
+			s.Require().False(ok)
+			return &WhoAmIResponse{}, nil
+		},
+	)
+	s.Require().NoError(err)
+	s.fixture.DoTick()
+
+	res := s.postWithToken("query/game/whoami", "")
+	s.Require().Equal(fiber.StatusOK, res.StatusCode)
+	res = s.postWithToken("query/game/whoami", "not-a-token")
+	s.Require().Equal(fiber.StatusUnauthorized, res.StatusCode)
+}
+
+func (s *ServerTestSuite) postWithToken(path, token string) *http.Response {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost,
+		fmt.Sprintf("http://%s/%s", s.fixture.BaseURL, path), bytes.NewReader([]byte("{}")))
+	s.Require().NoError(err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res, err := http.DefaultClient.Do(req)
+	s.Require().NoError(err)
+	return res
+}
+
+// signJWT returns an RS256 token with the claims, signed by the key.
+func signJWT(s *ServerTestSuite, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid})
+	s.Require().NoError(err)
+	payload, err := json.Marshal(claims)
+	s.Require().NoError(err)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	s.Require().NoError(err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nats-io/nats.go"
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+)
+
+// The NATS transport lets the relay submit transactions and receive events through NATS JetStream instead of HTTP and
+// the event websocket. Transactions are kept in a stream until Cardinal has processed them, so that they survive
+// network partitions and restarts, and events are kept in a stream that relays read with durable consumers. The
+// subjects and headers below are shared with the relay.
+const (
+	// NATSReplyHeader is the header of a transaction message that names the subject Cardinal publishes the
+	// transaction's response to.
+	NATSReplyHeader = "Cardinal-Reply-To"
+	// NATSStatusHeader is the header of a transaction response that holds its HTTP status code. The response's data
+	// is the body that the HTTP endpoint would have replied with.
+	NATSStatusHeader = "Cardinal-Status"
+	// NATSEncodingHeader is the header of an event message that holds the encoding of the tick results in its data,
+	// which is either handler.EventEncodingJSON or handler.EventEncodingBinary.
+	NATSEncodingHeader = "Cardinal-Encoding"
+
+	// DefaultNATSEventRetention is how long events are kept in the event stream by default.
+	DefaultNATSEventRetention = 24 * time.Hour
+
+	// natsConsumer is the durable consumer that Cardinal reads the transaction stream with.
+	natsConsumer = "cardinal"
+	// natsDuplicateWindow is how long the streams remember message IDs to discard the messages that are published
+	// again.
+	natsDuplicateWindow = 10 * time.Minute
+)
+
+// NATSTxSubject returns the subject that transactions of the message are published to.
+func NATSTxSubject(namespace, group, name string) string {
+	return fmt.Sprintf("cardinal.%s.tx.%s.%s", namespace, group, name)
+}
+
+// NATSEventSubject returns the subject that Cardinal publishes its tick results to.
+func NATSEventSubject(namespace string) string {
+	return fmt.Sprintf("cardinal.%s.events", namespace)
+}
+
+type natsTransport struct {
+	url            string
+	namespace      string
+	eventRetention time.Duration
+	// submit adds a transaction of the message to the world.
+	submit func(group, name string, tx *handler.Transaction) (*handler.PostTransactionResponse, error)
+
+	conn *nats.Conn
+	js   nats.JetStreamContext
+	sub  *nats.Subscription
+}
+
+// start connects to NATS, creates the streams if they don't exist, and starts consuming transactions.
+func (t *natsTransport) start() error {
+	conn, err := nats.Connect(t.url,
+		nats.Name("cardinal-"+t.namespace),
+		nats.MaxReconnects(-1),
+		nats.RetryOnFailedConnect(true),
+	)
+	if err != nil {
+		return eris.Wrapf(err, "failed to connect to NATS at %s", t.url)
+	}
+	t.conn = conn
+	if t.js, err = conn.JetStream(); err != nil {
+		return eris.Wrap(err, "failed to use JetStream")
+	}
+	txSubjects := NATSTxSubject(t.namespace, "*", "*")
+	if err := t.ensureStream(&nats.StreamConfig{
+		Name:       "CARDINAL-TX-" + t.namespace,
+		Subjects:   []string{txSubjects},
+		Retention:  nats.WorkQueuePolicy,
+		Storage:    nats.FileStorage,
+		Duplicates: natsDuplicateWindow,
+	}); err != nil {
+		return err
+	}
+	if err := t.ensureStream(&nats.StreamConfig{
+		Name:       "CARDINAL-EVENTS-" + t.namespace,
+		Subjects:   []string{NATSEventSubject(t.namespace)},
+		Retention:  nats.LimitsPolicy,
+		Storage:    nats.FileStorage,
+		MaxAge:     t.eventRetention,
+		Duplicates: natsDuplicateWindow,
+	}); err != nil {
+		return err
+	}
+	t.sub, err = t.js.QueueSubscribe(txSubjects, natsConsumer, t.handleTx,
+		nats.Durable(natsConsumer), nats.ManualAck(), nats.AckExplicit())
+	if err != nil {
+		return eris.Wrap(err, "failed to consume the transaction stream")
+	}
+	log.Info().Msgf("consuming transactions from NATS at %s", t.url)
+	return nil
+}
+
+func (t *natsTransport) ensureStream(cfg *nats.StreamConfig) error {
+	_, err := t.js.AddStream(cfg)
+	if errors.Is(err, nats.ErrStreamNameAlreadyInUse) {
+		_, err = t.js.UpdateStream(cfg)
+	}
+	return eris.Wrapf(err, "failed to create stream %s", cfg.Name)
+}
+
+// handleTx submits the transaction in the message and publishes the response. The message is acknowledged once the
+// transaction is added to the world or rejected, so that transactions that were never processed are redelivered.
+// Transactions that are delivered again after they were added are rejected by their nonce.
+func (t *natsTransport) handleTx(msg *nats.Msg) {
+	status, body := fiber.StatusOK, []byte(nil)
+	tokens := strings.Split(msg.Subject, ".")
+	group, name := tokens[len(tokens)-2], tokens[len(tokens)-1]
+	tx := new(handler.Transaction)
+	if err := json.Unmarshal(msg.Data, tx); err != nil {
+		status, body = fiber.StatusBadRequest, []byte("failed to parse transaction: "+err.Error())
+	} else if res, err := t.submit(group, name, tx); err != nil {
+		status, body = fiber.StatusInternalServerError, []byte(err.Error())
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			status = fiberErr.Code
+		}
+	} else if body, err = json.Marshal(res); err != nil {
+		status, body = fiber.StatusInternalServerError, []byte(err.Error())
+	}
+
+	if replyTo := msg.Header.Get(NATSReplyHeader); replyTo != "" {
+		reply := nats.NewMsg(replyTo)
+		reply.Header.Set(NATSStatusHeader, strconv.Itoa(status))
+		reply.Data = body
+		if err := t.conn.PublishMsg(reply); err != nil {
+			log.Warn().Err(err).Msgf("failed to reply to transaction on %s", msg.Subject)
+		}
+	}
+	if err := msg.Ack(); err != nil {
+		log.Warn().Err(err).Msgf("failed to acknowledge transaction on %s", msg.Subject)
+	}
+}
+
+// publishEvent publishes the event to the event stream, in its binary encoding if it implements
+// encoding.BinaryMarshaler, and as JSON otherwise.
+func (t *natsTransport) publishEvent(event any) error {
+	msg := nats.NewMsg(NATSEventSubject(t.namespace))
+	var err error
+	if marshaler, ok := event.(encoding.BinaryMarshaler); ok {
+		msg.Header.Set(NATSEncodingHeader, handler.EventEncodingBinary)
+		msg.Data, err = marshaler.MarshalBinary()
+	} else {
+		msg.Header.Set(NATSEncodingHeader, handler.EventEncodingJSON)
+		msg.Data, err = json.Marshal(event)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = t.js.PublishMsg(msg)
+	return eris.Wrap(err, "failed to publish event to NATS")
+}
+
+func (t *natsTransport) close() error {
+	if t.conn == nil {
+		return nil
+	}
+	return eris.Wrap(t.conn.Drain(), "")
+}
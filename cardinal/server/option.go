@@ -1,5 +1,11 @@
 package server
 
+import (
+	"time"
+
+	"pkg.world.dev/world-engine/cardinal/server/handler"
+)
+
 type Option func(s *Server)
 
 // WithPort allows the server to run on a specified port.
@@ -22,3 +28,40 @@ func DisableSwagger() Option {
 		s.config.isSwaggerDisabled = true
 	}
 }
+
+// WithEventCompression compresses the frames of the event websocket that are at least threshold bytes long with
+// permessage-deflate at the compress/flate level, for the clients that ask for compression when they connect.
+func WithEventCompression(level, threshold int) Option {
+	return func(s *Server) {
+		s.config.eventCompression = &handler.EventCompression{Level: level, Threshold: threshold}
+	}
+}
+
+// WithEventConnectionDropper closes the websocket connections for which drop returns true, instead of sending them
+// the event, whenever an event is broadcast. It is used to test how clients recover from dropped connections.
+func WithEventConnectionDropper(drop func() bool) Option {
+	return func(s *Server) {
+		s.config.dropEventConnection = drop
+	}
+}
+
+// WithNATS accepts transactions from, and publishes events to, the NATS server at the URL, in addition to the HTTP
+// endpoints and the event websocket. The NATS server must have JetStream enabled. Events are kept in the event stream
+// for the retention, or for DefaultNATSEventRetention if it is zero.
+func WithNATS(url string, eventRetention time.Duration) Option {
+	return func(s *Server) {
+		if eventRetention <= 0 {
+			eventRetention = DefaultNATSEventRetention
+		}
+		s.config.natsURL = url
+		s.config.natsEventRetention = eventRetention
+	}
+}
+
+// WithJWTAuth requires the requests to the endpoints in the config's paths to carry a JSON Web Token that is issued by
+// the configured identity provider.
+func WithJWTAuth(cfg JWTConfig) Option {
+	return func(s *Server) {
+		s.config.jwt = &cfg
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/rotisserie/eris"
+
 	"pkg.world.dev/world-engine/cardinal"
 	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/receipt"
 	"pkg.world.dev/world-engine/cardinal/server/handler"
 	"pkg.world.dev/world-engine/sign"
 )
@@ -62,6 +65,9 @@ func (s *ServerTestSuite) TestReceiptsQuery() {
 		Tick:   1,
 		Result: nil,
 		Errors: []string{wantErrorMessage},
+		ErrorDetails: []receipt.ErrorDetail{
+			{Code: receipt.CodeUnknown, Message: wantErrorMessage},
+		},
 	}
 	expectedJSON2, err := json.Marshal(expectedReceipt2)
 	s.Require().NoError(err)
@@ -78,3 +84,78 @@ func (s *ServerTestSuite) TestReceiptsQuery() {
 	s.Require().Equal(string(expectedJSON1), string(json1))
 	s.Require().Equal(string(expectedJSON2), string(json2))
 }
+
+func (s *ServerTestSuite) TestReceiptsHaveErrorCodes() {
+	s.setupWorld()
+	world := s.world
+	type fooIn struct{ Distance int }
+	type fooOut struct{}
+	errTooTired := errors.New("too tired")
+	s.Require().NoError(cardinal.RegisterMessage[fooIn, fooOut](world, "foo"))
+	s.Require().NoError(cardinal.RegisterErrorCode(world, "out-of-range", "{distance} tiles is more than {max}"))
+	s.Require().NoError(cardinal.RegisterErrorCode(world, "too-tired", "", errTooTired))
+	err := cardinal.RegisterSystems(world, func(ctx cardinal.WorldContext) error {
+		return cardinal.EachMessage[fooIn, fooOut](ctx, func(tx message.TxData[fooIn]) (fooOut, error) {
+			if tx.Msg.Distance > 5 {
+				return fooOut{}, receipt.NewError("out-of-range", map[string]any{"distance": tx.Msg.Distance, "max": 5})
+			}
+			return fooOut{}, eris.Wrap(errTooTired, "")
+		})
+	})
+	s.Require().NoError(err)
+
+	fooMsg, ok := world.GetMessageByFullName("game.foo")
+	s.Require().True(ok)
+	world.AddTransaction(fooMsg.ID(), fooIn{Distance: 7}, &sign.Transaction{PersonaTag: "alpha"})
+	world.AddTransaction(fooMsg.ID(), fooIn{Distance: 1}, &sign.Transaction{PersonaTag: "beta"})
+	s.fixture.DoTick()
+
+	res := s.fixture.Post("query/receipts/list", handler.ListTxReceiptsRequest{})
+	s.Require().Equal(res.StatusCode, http.StatusOK)
+	var reply handler.ListTxReceiptsResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&reply))
+	details := map[string]receipt.ErrorDetail{}
+	for _, r := range reply.Receipts {
+		s.Require().Len(r.ErrorDetails, 1)
+		details[r.ErrorDetails[0].Code] = r.ErrorDetails[0]
+	}
+	s.Require().Equal("7 tiles is more than 5", details["out-of-range"].Message)
+	s.Require().Equal(map[string]any{"distance": 7.0, "max": 5.0}, details["out-of-range"].Params)
+	s.Require().Equal("too tired", details["too-tired"].Message)
+}
+
+func (s *ServerTestSuite) TestReceiptProofQuery() {
+	s.setupWorld()
+	world := s.world
+	type fooIn struct{ X int }
+	type fooOut struct{ Y int }
+	err := cardinal.RegisterMessage[fooIn, fooOut](world, "foo")
+	s.Require().NoError(err)
+	err = cardinal.RegisterSystems(world, func(ctx cardinal.WorldContext) error {
+		return cardinal.EachMessage[fooIn, fooOut](ctx, func(tx message.TxData[fooIn]) (fooOut, error) {
+			return fooOut{Y: tx.Msg.X * 2}, nil
+		})
+	})
+	s.Require().NoError(err)
+
+	fooMsg, ok := world.GetMessageByFullName("game.foo")
+	s.Require().True(ok)
+	tick, txHash := world.AddTransaction(fooMsg.ID(), fooIn{X: 1}, &sign.Transaction{PersonaTag: "alpha"})
+	world.AddTransaction(fooMsg.ID(), fooIn{X: 2}, &sign.Transaction{PersonaTag: "beta"})
+	world.AddTransaction(fooMsg.ID(), fooIn{X: 3}, &sign.Transaction{PersonaTag: "gamma"})
+	s.fixture.DoTick()
+
+	res := s.fixture.Post("query/receipts/proof", handler.ReceiptProofRequest{TxHash: string(txHash), Tick: tick})
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+	var reply handler.ReceiptProofResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&reply))
+	s.Require().Equal(string(txHash), reply.Receipt.TxHash)
+	s.Require().True(receipt.VerifyProof(reply.Root, reply.Leaf, reply.Proof))
+
+	root, err := world.ReceiptsRoot(tick)
+	s.Require().NoError(err)
+	s.Require().Equal(root, reply.Root)
+
+	res = s.fixture.Post("query/receipts/proof", handler.ReceiptProofRequest{TxHash: "unknown", Tick: tick})
+	s.Require().Equal(http.StatusNotFound, res.StatusCode)
+}
@@ -1,10 +1,10 @@
 package server
 
 import (
-	"encoding/json"
+	"errors"
 	"os"
+	"time"
 
-	"github.com/gofiber/contrib/socketio"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/swagger"
@@ -27,11 +27,19 @@ type config struct {
 	port                            string
 	isSignatureVerificationDisabled bool
 	isSwaggerDisabled               bool
+	dropEventConnection             func() bool
+	eventCompression                *handler.EventCompression
+	natsURL                         string
+	natsEventRetention              time.Duration
+	jwt                             *JWTConfig
 }
 
 type Server struct {
-	app    *fiber.App
-	config config
+	app          *fiber.App
+	config       config
+	eventClients *handler.EventClients
+	nats         *natsTransport
+	jwt          *jwtVerifier
 }
 
 // New returns an HTTP server with handlers for all QueryTypes and MessageTypes.
@@ -54,10 +62,29 @@ func New(
 	for _, opt := range opts {
 		opt(s)
 	}
+	s.eventClients = handler.NewEventClients(s.config.eventCompression, provider.ReplayEvents)
+	if s.config.natsURL != "" {
+		s.nats = &natsTransport{
+			url:            s.config.natsURL,
+			namespace:      wCtx.Namespace(),
+			eventRetention: s.config.natsEventRetention,
+		}
+	}
 
 	// Enable CORS
 	app.Use(cors.New())
 
+	// Require tokens on the protected endpoints
+	if s.config.jwt != nil {
+		var err error
+		if s.jwt, err = newJWTVerifier(*s.config.jwt); err != nil {
+			return nil, err
+		}
+		for _, path := range s.jwt.cfg.Paths {
+			app.Use(path, s.jwt.middleware)
+		}
+	}
+
 	// Register routes
 	s.setupRoutes(provider, wCtx, messages, queries, components)
 
@@ -72,6 +99,12 @@ func (s *Server) Serve() error {
 		return eris.Wrap(err, "error getting hostname")
 	}
 
+	if s.nats != nil {
+		if err := s.nats.start(); err != nil {
+			return err
+		}
+	}
+
 	// Start server
 	log.Info().Msgf("serving at %s:%s", hostname, s.config.port)
 	err = s.app.Listen(":" + s.config.port)
@@ -82,13 +115,21 @@ func (s *Server) Serve() error {
 	return nil
 }
 
+// BroadcastEvent sends the event to every websocket client. Clients that negotiated the binary encoding receive
+// the event's binary encoding if it implements encoding.BinaryMarshaler, and JSON otherwise. The event is also
+// published to NATS if the NATS transport is enabled.
 func (s *Server) BroadcastEvent(event any) error {
-	eventBz, err := json.Marshal(event)
-	if err != nil {
-		return err
+	err := s.eventClients.Broadcast(event, s.config.dropEventConnection)
+	if s.nats != nil {
+		err = errors.Join(err, s.nats.publishEvent(event))
 	}
-	socketio.Broadcast(eventBz)
-	return nil
+	return err
+}
+
+// DisconnectEventClients closes the connections of all event websocket clients, which can reconnect to catch up on
+// the events that they missed.
+func (s *Server) DisconnectEventClients() {
+	s.eventClients.CloseAll()
 }
 
 // Shutdown gracefully shuts down the server and closes all active websocket connections.
@@ -96,8 +137,13 @@ func (s *Server) Shutdown() error {
 	log.Info().Msg("Shutting down server")
 
 	// Close websocket connections
-	socketio.Broadcast([]byte(""), socketio.CloseMessage)
-	socketio.Fire(socketio.EventClose, nil)
+	s.eventClients.CloseAll()
+
+	if s.nats != nil {
+		if err := s.nats.close(); err != nil {
+			log.Error().Err(err).Msg("error closing NATS connection")
+		}
+	}
 
 	// Gracefully shutdown Fiber server
 	if err := s.app.Shutdown(); err != nil {
@@ -146,6 +192,15 @@ func (s *Server) setupRoutes(
 		}
 		msgIndex[msg.Group()][msg.Name()] = msg
 	}
+	if s.nats != nil {
+		s.nats.submit = func(group, name string, tx *handler.Transaction) (*handler.PostTransactionResponse, error) {
+			msgType, ok := msgIndex[group][name]
+			if !ok {
+				return nil, fiber.NewError(fiber.StatusNotFound, "message type not found")
+			}
+			return handler.SubmitTransaction(provider, msgType, tx, s.config.isSignatureVerificationDisabled)
+		}
+	}
 
 	// Route: /swagger/
 	if !s.config.isSwaggerDisabled {
@@ -153,27 +208,41 @@ func (s *Server) setupRoutes(
 	}
 
 	// Route: /events/
+	// Events are streamed over a websocket at /events, and as server-sent events at /events/sse for clients that can't
+	// open websockets. The server-sent events are routed first, since the websocket upgrader rejects every other
+	// request under /events.
+	s.app.Get("/events/sse", handler.ServerSentEvents(s.eventClients))
 	s.app.Use("/events", handler.WebSocketUpgrader)
-	s.app.Get("/events", handler.WebSocketEvents())
+	s.app.Get("/events", handler.WebSocketEvents(s.eventClients))
 
 	// Route: /world
-	s.app.Get("/world", handler.GetWorld(components, messages, queries, wCtx.Namespace()))
+	s.app.Get("/world", handler.GetWorld(
+		components, messages, queries, provider.GetModules(), wCtx.Namespace(), provider.ConfigHash,
+	))
+	s.app.Get("/world/components", handler.GetComponents(components))
 
 	// Route: /...
-	s.app.Get("/health", handler.GetHealth())
+	s.app.Get("/health", handler.GetHealth(provider))
 
 	// Route: /query/...
 	query := s.app.Group("/query")
-	query.Post("/receipts/list", handler.GetReceipts(wCtx))
+	query.Post("/receipts/list", handler.GetReceipts(wCtx, provider.ErrorCodes()))
+	query.Post("/receipts/proof", handler.GetReceiptProof(wCtx, provider.ErrorCodes()))
+	query.Post("/events/list", handler.GetEvents(provider))
+	query.Get("/persona/exists", handler.GetPersonaExists(provider))
+	query.Get("/persona/info", handler.GetPersonaInfo(provider))
+	query.Post("/persona/list", handler.ListPersonas(provider))
 	query.Post("/:group/:name", handler.PostQuery(queryIndex, wCtx))
 
 	// Route: /tx/...
 	tx := s.app.Group("/tx")
+	tx.Post("/batch", handler.PostBatch(provider, msgIndex, s.config.isSignatureVerificationDisabled))
 	tx.Post("/:group/:name", handler.PostTransaction(provider, msgIndex, s.config.isSignatureVerificationDisabled))
 
 	// Route: /cql
 	s.app.Post("/cql", handler.PostCQL(provider))
 
-	// Route: /debug/state
+	// Route: /debug/...
 	s.app.Post("/debug/state", handler.GetDebugState(provider))
+	s.app.Post("/debug/explain", handler.PostDebugExplain(provider))
 }
@@ -19,9 +19,11 @@ import (
 
 	"pkg.world.dev/world-engine/assert"
 	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/codec"
 	"pkg.world.dev/world-engine/cardinal/message"
 	"pkg.world.dev/world-engine/cardinal/persona/msg"
 	"pkg.world.dev/world-engine/cardinal/query"
+	"pkg.world.dev/world-engine/cardinal/search"
 	"pkg.world.dev/world-engine/cardinal/server/handler"
 	"pkg.world.dev/world-engine/cardinal/server/utils"
 	"pkg.world.dev/world-engine/cardinal/testutils"
@@ -218,6 +220,154 @@ func (s *ServerTestSuite) TestQueryCustomGroup() {
 	s.Require().True(called)
 }
 
+func (s *ServerTestSuite) TestQueryFieldProjection() {
+	type Item struct {
+		ID    types.EntityID
+		Name  string
+		Stats map[string]int
+	}
+	type InventoryRequest struct{}
+	type InventoryResponse struct {
+		Owner string
+		Items []Item
+	}
+	s.setupWorld()
+	err := cardinal.RegisterQuery[InventoryRequest, InventoryResponse](
+		s.world,
+		"inventory",
+		func(_ engine.Context, _ *InventoryRequest) (*InventoryResponse, error) {
+			return &InventoryResponse{
+				Owner: "alice",
+				Items: []Item{
+					{ID: 1<<62 + 1, Name: "sword", Stats: map[string]int{"attack": 5, "weight": 3}},
+					{ID: 2, Name: "shield", Stats: map[string]int{"defense": 4}},
+				},
+			}, nil
+		},
+	)
+	s.Require().NoError(err)
+	s.fixture.DoTick()
+
+	res := s.fixture.Post(utils.GetQueryURL("game", "inventory")+"?fields=Items.ID,Items.Stats.attack", InventoryRequest{})
+	s.Require().Equal(fiber.StatusOK, res.StatusCode)
+	s.Require().JSONEq(
+		`{"Items":[{"ID":4611686018427387905,"Stats":{"attack":5}},{"ID":2,"Stats":{}}]}`,
+		s.readBody(res.Body),
+	)
+
+	res = s.fixture.Post(utils.GetQueryURL("game", "inventory")+"?fields=Owner,Items..Name", InventoryRequest{})
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode)
+}
+
+func (s *ServerTestSuite) TestPersonaExists() {
+	s.setupWorld()
+	s.fixture.DoTick()
+	exists := func(tag string) bool {
+		res := s.fixture.Get("query/persona/exists?tag=" + tag)
+		s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+		var body handler.GetPersonaExistsResponse
+		s.Require().NoError(json.NewDecoder(res.Body).Decode(&body))
+		return body.Exists
+	}
+	s.Require().False(exists("Alice"))
+	s.createPersona("Alice")
+	s.Require().True(exists("Alice"))
+	s.Require().True(exists("aLiCe"))
+	s.Require().False(exists("Bob"))
+
+	res := s.fixture.Get("query/persona/exists")
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode)
+}
+
+func (s *ServerTestSuite) TestPersonaInfo() {
+	s.setupWorld()
+	s.fixture.DoTick()
+	s.createPersona("Alice")
+	authorizeMessage, ok := s.world.GetMessageByFullName("game.authorize-persona-address")
+	s.Require().True(ok)
+	authorizedAddr := "0x" + strings.Repeat("ab", 20)
+	s.runTx("Alice", authorizeMessage, msg.AuthorizePersonaAddress{Address: authorizedAddr})
+	sessionKeyMessage, ok := s.world.GetMessageByFullName("persona." + msg.AuthorizeSessionKeyMessageName)
+	s.Require().True(ok)
+	sessionAddr := "0x" + strings.Repeat("cd", 20)
+	s.runTx("Alice", sessionKeyMessage, msg.AuthorizeSessionKey{Address: sessionAddr, ExpiresInTicks: 100})
+
+	res := s.fixture.Get("query/persona/info?tag=Alice")
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+	var info handler.GetPersonaInfoResponse
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(&info))
+	s.Require().Equal("Alice", info.PersonaTag)
+	s.Require().Equal(s.signerAddr, info.SignerAddress)
+	s.Require().Equal([]string{authorizedAddr}, info.AuthorizedAddresses)
+	s.Require().Len(info.SessionKeys, 1)
+	s.Require().Equal(sessionAddr, info.SessionKeys[0].Address)
+	s.Require().NotZero(info.SessionKeys[0].ExpiresAtTick)
+	s.Require().NotZero(info.CreatedAtTick)
+
+	res = s.fixture.Get("query/persona/info?tag=Bob")
+	s.Require().Equal(fiber.StatusNotFound, res.StatusCode)
+	res = s.fixture.Get("query/persona/info")
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode)
+}
+
+func (s *ServerTestSuite) TestListPersonas() {
+	s.setupWorld()
+	s.fixture.DoTick()
+	for _, tag := range []string{"Alice", "alfred", "Albert", "Bob"} {
+		s.createPersona(tag)
+	}
+	list := func(req handler.ListPersonasRequest) handler.ListPersonasResponse {
+		res := s.fixture.Post("query/persona/list", req)
+		s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+		var body handler.ListPersonasResponse
+		s.Require().NoError(json.NewDecoder(res.Body).Decode(&body))
+		return body
+	}
+	tags := func(res handler.ListPersonasResponse) []string {
+		var tags []string
+		for _, p := range res.Personas {
+			tags = append(tags, p.PersonaTag)
+		}
+		return tags
+	}
+
+	res := list(handler.ListPersonasRequest{Prefix: "AL*", Limit: 2})
+	s.Require().Equal([]string{"Albert", "alfred"}, tags(res))
+	s.Require().Equal(s.signerAddr, res.Personas[0].SignerAddress)
+	s.Require().NotEmpty(res.NextCursor)
+	res = list(handler.ListPersonasRequest{Prefix: "AL*", Limit: 2, Cursor: res.NextCursor})
+	s.Require().Equal([]string{"Alice"}, tags(res))
+	s.Require().Empty(res.NextCursor)
+
+	res = list(handler.ListPersonasRequest{})
+	s.Require().Equal([]string{"Albert", "alfred", "Alice", "Bob"}, tags(res))
+}
+
+func (s *ServerTestSuite) TestStrictMessageDecodingRejectsMalformedPayloads() {
+	s.setupWorld(
+		cardinal.WithDisableSignatureVerification(),
+		cardinal.WithMessageDecoding(codec.Options{Strict: true, Naming: codec.FieldNamingCamelCase}),
+	)
+	s.fixture.DoTick()
+	moveMessage, ok := s.world.GetMessageByFullName("game." + moveMsgName)
+	s.Require().True(ok)
+	url := utils.GetTxURL(moveMessage.Group(), moveMessage.Name())
+
+	post := func(body string) *http.Response {
+		return s.fixture.Post(url, &sign.Transaction{PersonaTag: "some-persona", Body: json.RawMessage(body)})
+	}
+	res := post(`{"direction":"up"}`)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+
+	res = post(`{"Direction":"up"}`)
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode)
+	s.Require().Contains(s.readBody(res.Body), `unknown field "Direction"`)
+
+	res = post(`{"direction":"up","speed":3}`)
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode)
+	s.Require().Contains(s.readBody(res.Body), `unknown field "speed"`)
+}
+
 func (s *ServerTestSuite) TestMissingSignerAddressIsOKWhenSigVerificationIsDisabled() {
 	t := s.T()
 	s.setupWorld(cardinal.WithDisableSignatureVerification())
@@ -248,6 +398,252 @@ func (s *ServerTestSuite) TestSignerAddressIsRequiredWhenSigVerificationIsDisabl
 	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 }
 
+func (s *ServerTestSuite) TestCanSendBatchOfTransactions() {
+	s.setupWorld()
+	s.fixture.DoTick()
+	personaTag := s.CreateRandomPersona()
+	moveMessage, ok := s.world.GetMessageByFullName("game." + moveMsgName)
+	s.Require().True(ok)
+
+	var entries []sign.BatchEntry
+	for _, direction := range []string{"up", "up", "right"} {
+		entry, err := sign.NewBatchEntry(moveMessage.FullName(), MoveMsgInput{Direction: direction})
+		s.Require().NoError(err)
+		entries = append(entries, entry)
+	}
+	batch, err := sign.NewBatch(s.privateKey, personaTag, s.world.Namespace(), s.nonce, entries...)
+	s.Require().NoError(err)
+
+	// A batch whose transactions don't match its signature is rejected.
+	tampered := *batch
+	tampered.Transactions = []sign.BatchEntry{entries[0], entries[0], entries[0]}
+	res := s.fixture.Post("/tx/batch", &tampered)
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode, s.readBody(res.Body))
+
+	res = s.fixture.Post("/tx/batch", batch)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode)
+	var batchRes handler.PostBatchResponse
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &batchRes))
+	s.Require().Len(batchRes.Transactions, 3)
+	for i, tx := range batch.Unpack() {
+		s.Require().Equal(tx.HashHex(), batchRes.Transactions[i].TxHash)
+	}
+	s.fixture.DoTick()
+	s.nonce += uint64(len(entries))
+
+	res = s.fixture.Post("query/game/location", QueryLocationRequest{Persona: personaTag})
+	var loc LocationComponent
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &loc))
+	s.Require().Equal(LocationComponent{1, 2}, loc)
+
+	// The nonces of the batch were used, so it can't be replayed.
+	res = s.fixture.Post("/tx/batch", batch)
+	s.Require().NotEqual(fiber.StatusOK, res.StatusCode)
+}
+
+func (s *ServerTestSuite) TestAdminSignerCanImpersonatePersona() {
+	adminKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	adminAddr := crypto.PubkeyToAddress(adminKey.PublicKey).Hex()
+	s.setupWorld(cardinal.WithAdminSigners(adminAddr))
+	s.fixture.DoTick()
+	personaTag := s.CreateRandomPersona()
+	moveMessage, ok := s.world.GetMessageByFullName("game." + moveMsgName)
+	s.Require().True(ok)
+	url := utils.GetTxURL(moveMessage.Group(), moveMessage.Name())
+
+	// A signer that is neither the persona's signer nor an admin signer is rejected.
+	otherKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	tx, err := sign.NewTransaction(otherKey, personaTag, s.world.Namespace(), 0, MoveMsgInput{Direction: "up"})
+	s.Require().NoError(err)
+	res := s.fixture.Post(url, tx)
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode, s.readBody(res.Body))
+
+	tx, err = sign.NewTransaction(adminKey, personaTag, s.world.Namespace(), 0, MoveMsgInput{Direction: "up"})
+	s.Require().NoError(err)
+	res = s.fixture.Post(url, tx)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+	var txRes handler.PostTransactionResponse
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &txRes))
+	s.fixture.DoTick()
+
+	res = s.fixture.Post("query/game/location", QueryLocationRequest{Persona: personaTag})
+	var loc LocationComponent
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &loc))
+	s.Require().Equal(LocationComponent{0, 1}, loc)
+
+	// The impersonation is recorded in the receipt.
+	res = s.fixture.Post("query/receipts/list", handler.ListTxReceiptsRequest{StartTick: txRes.Tick})
+	var receipts handler.ListTxReceiptsResponse
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &receipts))
+	s.Require().Len(receipts.Receipts, 1)
+	s.Require().Equal(txRes.TxHash, receipts.Receipts[0].TxHash)
+	s.Require().Equal(adminAddr, receipts.Receipts[0].Impersonator)
+
+	// Transactions signed by the persona's own signer are not marked as impersonated.
+	tx, err = sign.NewTransaction(s.privateKey, personaTag, s.world.Namespace(), s.nonce, MoveMsgInput{Direction: "up"})
+	s.Require().NoError(err)
+	res = s.fixture.Post(url, tx)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &txRes))
+	s.fixture.DoTick()
+	res = s.fixture.Post("query/receipts/list", handler.ListTxReceiptsRequest{StartTick: txRes.Tick})
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &receipts))
+	s.Require().Len(receipts.Receipts, 1)
+	s.Require().Equal("", receipts.Receipts[0].Impersonator)
+}
+
+func (s *ServerTestSuite) TestImpersonationCanBeDisabled() {
+	adminKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	adminAddr := crypto.PubkeyToAddress(adminKey.PublicKey).Hex()
+	s.setupWorld(cardinal.WithAdminSigners(adminAddr), cardinal.WithImpersonationDisabled())
+	s.fixture.DoTick()
+	personaTag := s.CreateRandomPersona()
+	moveMessage, ok := s.world.GetMessageByFullName("game." + moveMsgName)
+	s.Require().True(ok)
+
+	tx, err := sign.NewTransaction(adminKey, personaTag, s.world.Namespace(), 0, MoveMsgInput{Direction: "up"})
+	s.Require().NoError(err)
+	res := s.fixture.Post(utils.GetTxURL(moveMessage.Group(), moveMessage.Name()), tx)
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode, s.readBody(res.Body))
+}
+
+func (s *ServerTestSuite) TestSessionKeyCanSignScopedMessagesUntilItExpires() {
+	s.setupWorld()
+	s.fixture.DoTick()
+	personaTag := s.CreateRandomPersona()
+	moveMessage, ok := s.world.GetMessageByFullName("game." + moveMsgName)
+	s.Require().True(ok)
+	authorizeMessage, ok := s.world.GetMessageByFullName("persona." + msg.AuthorizeSessionKeyMessageName)
+	s.Require().True(ok)
+	moveURL := utils.GetTxURL(moveMessage.Group(), moveMessage.Name())
+
+	sessionKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	sessionAddr := crypto.PubkeyToAddress(sessionKey.PublicKey).Hex()
+	s.runTx(personaTag, authorizeMessage, msg.AuthorizeSessionKey{
+		Address:        sessionAddr,
+		ExpiresInTicks: 2,
+		Messages:       []string{moveMessage.FullName()},
+	})
+
+	tx, err := sign.NewTransaction(sessionKey, personaTag, s.world.Namespace(), 0, MoveMsgInput{Direction: "up"})
+	s.Require().NoError(err)
+	res := s.fixture.Post(moveURL, tx)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+	s.fixture.DoTick()
+
+	// Session keys can't manage the persona, even if they are not limited to some messages.
+	tx, err = sign.NewTransaction(sessionKey, personaTag, s.world.Namespace(), 1,
+		msg.AuthorizeSessionKey{Address: sessionAddr, ExpiresInTicks: 100})
+	s.Require().NoError(err)
+	res = s.fixture.Post(utils.GetTxURL(authorizeMessage.Group(), authorizeMessage.Name()), tx)
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode, s.readBody(res.Body))
+
+	// Once the key expires, it can no longer sign transactions, and it is removed from the persona.
+	s.fixture.DoTick()
+	s.fixture.DoTick()
+	tx, err = sign.NewTransaction(sessionKey, personaTag, s.world.Namespace(), 2, MoveMsgInput{Direction: "up"})
+	s.Require().NoError(err)
+	res = s.fixture.Post(moveURL, tx)
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode, s.readBody(res.Body))
+	sc, err := s.world.GetSignerComponentForPersona(personaTag)
+	s.Require().NoError(err)
+	s.Require().Empty(sc.SessionKeys)
+}
+
+func (s *ServerTestSuite) TestSignerInGracePeriodCannotRotateSigner() {
+	s.setupWorld()
+	s.fixture.DoTick()
+	personaTag := s.CreateRandomPersona()
+	rotateMessage, ok := s.world.GetMessageByFullName("persona." + msg.RotatePersonaSignerMessageName)
+	s.Require().True(ok)
+	moveMessage, ok := s.world.GetMessageByFullName("game." + moveMsgName)
+	s.Require().True(ok)
+
+	newKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	s.runTx(personaTag, rotateMessage, msg.RotatePersonaSigner{
+		NewSignerAddress: crypto.PubkeyToAddress(newKey.PublicKey).Hex(),
+		GracePeriodTicks: 100,
+	})
+
+	// The old signer can still send game transactions during the grace period...
+	tx, err := sign.NewTransaction(s.privateKey, personaTag, s.world.Namespace(), s.nonce, MoveMsgInput{Direction: "up"})
+	s.Require().NoError(err)
+	res := s.fixture.Post(utils.GetTxURL(moveMessage.Group(), moveMessage.Name()), tx)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+	s.nonce++
+
+	// ...but can't rotate the signer again, which would lock the new signer out.
+	attackerKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	tx, err = sign.NewTransaction(s.privateKey, personaTag, s.world.Namespace(), s.nonce, msg.RotatePersonaSigner{
+		NewSignerAddress: crypto.PubkeyToAddress(attackerKey.PublicKey).Hex(),
+	})
+	s.Require().NoError(err)
+	res = s.fixture.Post(utils.GetTxURL(rotateMessage.Group(), rotateMessage.Name()), tx)
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode, s.readBody(res.Body))
+
+	// Nor can it rotate the signer in a batch.
+	entry, err := sign.NewBatchEntry(rotateMessage.FullName(), msg.RotatePersonaSigner{
+		NewSignerAddress: crypto.PubkeyToAddress(attackerKey.PublicKey).Hex(),
+	})
+	s.Require().NoError(err)
+	batch, err := sign.NewBatch(s.privateKey, personaTag, s.world.Namespace(), s.nonce, entry)
+	s.Require().NoError(err)
+	res = s.fixture.Post("/tx/batch", batch)
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode, s.readBody(res.Body))
+
+	// The new signer can.
+	tx, err = sign.NewTransaction(newKey, personaTag, s.world.Namespace(), 0, msg.RotatePersonaSigner{
+		NewSignerAddress: crypto.PubkeyToAddress(attackerKey.PublicKey).Hex(),
+	})
+	s.Require().NoError(err)
+	res = s.fixture.Post(utils.GetTxURL(rotateMessage.Group(), rotateMessage.Name()), tx)
+	s.Require().Equal(fiber.StatusOK, res.StatusCode, s.readBody(res.Body))
+}
+
+func (s *ServerTestSuite) TestSignerInGracePeriodCannotTransferPersonaOrChangeSigner() {
+	s.setupWorld()
+	s.fixture.DoTick()
+	personaTag := s.CreateRandomPersona()
+	rotateMessage, ok := s.world.GetMessageByFullName("persona." + msg.RotatePersonaSignerMessageName)
+	s.Require().True(ok)
+	newKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	s.runTx(personaTag, rotateMessage, msg.RotatePersonaSigner{
+		NewSignerAddress: crypto.PubkeyToAddress(newKey.PublicKey).Hex(),
+		GracePeriodTicks: 100,
+	})
+
+	attackerKey, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	attackerAddr := crypto.PubkeyToAddress(attackerKey.PublicKey).Hex()
+	signerChanges := map[string]any{
+		msg.TransferPersonaMessageName: msg.TransferPersona{NewSignerAddress: attackerAddr},
+		msg.ChangeSignerMessageName:    msg.ChangeSigner{NewSignerAddress: attackerAddr},
+	}
+	for name, payload := range signerChanges {
+		changeMessage, ok := s.world.GetMessageByFullName("persona." + name)
+		s.Require().True(ok)
+
+		tx, err := sign.NewTransaction(s.privateKey, personaTag, s.world.Namespace(), s.nonce, payload)
+		s.Require().NoError(err)
+		res := s.fixture.Post(utils.GetTxURL(changeMessage.Group(), changeMessage.Name()), tx)
+		s.Require().Equal(fiber.StatusBadRequest, res.StatusCode, name+": "+s.readBody(res.Body))
+
+		entry, err := sign.NewBatchEntry(changeMessage.FullName(), payload)
+		s.Require().NoError(err)
+		batch, err := sign.NewBatch(s.privateKey, personaTag, s.world.Namespace(), s.nonce, entry)
+		s.Require().NoError(err)
+		res = s.fixture.Post("/tx/batch", batch)
+		s.Require().Equal(fiber.StatusBadRequest, res.StatusCode, name+": "+s.readBody(res.Body))
+	}
+}
+
 // Creates a transaction with the given message, and runs it in a tick.
 func (s *ServerTestSuite) runTx(personaTag string, msg types.Message, payload any) {
 	tx, err := sign.NewTransaction(s.privateKey, personaTag, s.world.Namespace(), s.nonce, payload)
@@ -392,6 +788,28 @@ func (s *ServerTestSuite) TestCQL_InvalidFormat() {
 	s.Require().Error(err)
 }
 
+func (s *ServerTestSuite) TestDebugExplain() {
+	s.setupWorld()
+	s.fixture.DoTick()
+
+	wCtx := cardinal.NewWorldContext(s.world)
+	_, err := cardinal.CreateMany(wCtx, 10, LocationComponent{})
+	assert.NilError(s.T(), err)
+
+	s.fixture.DoTick()
+
+	res := s.fixture.Post("/debug/explain", handler.DebugExplainRequest{CQL: "CONTAINS(location)"})
+	s.Require().Equal(fiber.StatusOK, res.StatusCode)
+	var result search.Explanation
+	s.Require().NoError(json.Unmarshal([]byte(s.readBody(res.Body)), &result))
+	s.Require().Len(result.Archetypes, 1)
+	s.Require().Equal([]string{"location"}, result.Archetypes[0].Components)
+	s.Require().Equal(10, result.EstimatedEntities)
+
+	res = s.fixture.Post("/debug/explain", handler.DebugExplainRequest{CQL: "CONTAINS(meow)"})
+	s.Require().Equal(fiber.StatusBadRequest, res.StatusCode)
+}
+
 func (s *ServerTestSuite) TestCQL_NonExistentComponent() {
 	s.setupWorld()
 	s.fixture.DoTick()
@@ -1,7 +1,12 @@
 package types
 
 import (
+	"time"
+
+	"pkg.world.dev/world-engine/cardinal/events"
 	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/persona/component"
+	"pkg.world.dev/world-engine/cardinal/receipt"
 	"pkg.world.dev/world-engine/cardinal/search"
 	"pkg.world.dev/world-engine/cardinal/search/filter"
 	"pkg.world.dev/world-engine/cardinal/types"
@@ -12,10 +17,57 @@ import (
 type Provider interface {
 	UseNonce(signerAddress string, nonce uint64) error
 	GetSignerForPersonaTag(personaTag string, tick uint64) (addr string, err error)
+	GetValidSignersForPersonaTag(personaTag string) ([]string, error)
+	GetSessionKeySignersForPersonaTag(personaTag, msgName string) ([]string, error)
+	PersonaTagExists(personaTag string) (bool, error)
+	GetSignerComponentForPersona(personaTag string) (*component.SignerComponent, error)
+	ListPersonas(prefix, cursor string, limit int) (personas []PersonaSummary, next string, err error)
 	AddTransaction(id types.MessageID, v any, sig *sign.Transaction) (uint64, types.TxHash)
+	AdminSigners() []string
+	ImpersonationSigners() []string
+	AddImpersonatedTransaction(id types.MessageID, v any, sig *sign.Transaction, adminSigner string) (
+		uint64, types.TxHash, error)
 	Namespace() string
 	GetComponentByName(name string) (types.ComponentMetadata, error)
 	Search(filter filter.ComponentFilter) search.EntitySearch
 	StoreReader() gamestate.Reader
 	GetReadOnlyCtx() engine.Context
+	QueryEvents(filter events.Filter) []events.Entry
+	ReplayEvents(fromTick uint64, fn func(event any) error) (next uint64, err error)
+	RecoveryStatus() RecoveryStatus
+	NotifyTxRejected(msgName string, tx *sign.Transaction, err error)
+	GetModules() []ModuleInfo
+	ConfigHash() string
+	ErrorCodes() *receipt.ErrorCodes
+}
+
+// PersonaSummary is a persona tag, as it was registered, and its signer. If the persona is an alias, SignerAddress is
+// the signer it had when it was merged, and transactions use the signer of the persona in AliasOf.
+type PersonaSummary struct {
+	PersonaTag    string `json:"personaTag"`
+	SignerAddress string `json:"signerAddress"`
+	AliasOf       string `json:"aliasOf,omitempty"`
+}
+
+// ModuleInfo identifies a module that the world uses.
+type ModuleInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// RecoveryStatus describes the progress of the world's startup recovery.
+type RecoveryStatus struct {
+	Recovering bool `json:"recovering"`
+	// StartTick is the tick the world was at when recovery began.
+	StartTick uint64 `json:"startTick"`
+	// CurrentTick is the tick the world has recovered up to.
+	CurrentTick uint64 `json:"currentTick"`
+	// TargetTick is the tick the world will be at once all known recovery data has been replayed. During recovery
+	// from the base shard, it advances as more transaction batches are fetched.
+	TargetTick uint64 `json:"targetTick"`
+	// TicksPerSecond is the average rate at which ticks have been replayed so far.
+	TicksPerSecond      float64    `json:"ticksPerSecond"`
+	StartedAt           *time.Time `json:"startedAt,omitempty"`
+	EstimatedCompletion *time.Time `json:"estimatedCompletion,omitempty"`
+	CompletedAt         *time.Time `json:"completedAt,omitempty"`
 }
@@ -0,0 +1,307 @@
+// Package snapshot compares snapshots of a world's state, such as the state of a shard before and after a migration,
+// or the states of the same tick in two environments. A snapshot is the reply of a shard's /debug/state endpoint.
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// Snapshot is the state of a world: every entity, and the values of its components.
+type Snapshot []Entity
+
+// Entity is an entity of a Snapshot. Components maps the names of the entity's components to their values.
+type Entity struct {
+	ID         types.EntityID             `json:"id"`
+	Components map[string]json.RawMessage `json:"components"`
+}
+
+// Decode reads a snapshot in the format of the /debug/state endpoint.
+func Decode(r io.Reader) (Snapshot, error) {
+	var s Snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, eris.Wrap(err, "failed to decode snapshot")
+	}
+	return s, nil
+}
+
+// Diff is how the state changed from one snapshot to another. Entities are sorted by ID.
+type Diff struct {
+	Added   []Entity     `json:"added,omitempty"`
+	Removed []Entity     `json:"removed,omitempty"`
+	Changed []EntityDiff `json:"changed,omitempty"`
+}
+
+// IsEmpty reports whether the snapshots were the same.
+func (d Diff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// EntityDiff is how the components of an entity that is in both snapshots changed.
+type EntityDiff struct {
+	ID                types.EntityID             `json:"id"`
+	AddedComponents   map[string]json.RawMessage `json:"addedComponents,omitempty"`
+	RemovedComponents map[string]json.RawMessage `json:"removedComponents,omitempty"`
+	// Fields are the changed fields of the components that the entity has in both snapshots, sorted by component and
+	// path.
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+// FieldChange is a changed value in a component. Path locates the value in the component, e.g. "position.x" or
+// "items[2]"; it is empty if the component's value changed as a whole. Before or After is absent if the value
+// didn't exist in that snapshot.
+type FieldChange struct {
+	Component string          `json:"component"`
+	Path      string          `json:"path"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+}
+
+// Compare returns how the state changed from the before snapshot to the after snapshot. If components are given, only
+// those components are compared, and the entities that have none of them are ignored.
+func Compare(before, after Snapshot, components ...string) (Diff, error) {
+	keep := func(name string) bool {
+		return len(components) == 0 || slices.Contains(components, name)
+	}
+	beforeByID, err := index(before, keep)
+	if err != nil {
+		return Diff{}, err
+	}
+	afterByID, err := index(after, keep)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	var diff Diff
+	for _, id := range sortedIDs(beforeByID) {
+		if _, ok := afterByID[id]; !ok {
+			diff.Removed = append(diff.Removed, Entity{ID: id, Components: beforeByID[id]})
+		}
+	}
+	for _, id := range sortedIDs(afterByID) {
+		afterComps := afterByID[id]
+		beforeComps, ok := beforeByID[id]
+		if !ok {
+			diff.Added = append(diff.Added, Entity{ID: id, Components: afterComps})
+			continue
+		}
+		entityDiff, err := compareEntity(id, beforeComps, afterComps)
+		if err != nil {
+			return Diff{}, err
+		}
+		if entityDiff != nil {
+			diff.Changed = append(diff.Changed, *entityDiff)
+		}
+	}
+	return diff, nil
+}
+
+// index maps the IDs of the entities to their kept components. Entities without kept components are left out.
+func index(s Snapshot, keep func(string) bool) (map[types.EntityID]map[string]json.RawMessage, error) {
+	byID := make(map[types.EntityID]map[string]json.RawMessage, len(s))
+	for _, e := range s {
+		if _, ok := byID[e.ID]; ok {
+			return nil, eris.Errorf("snapshot has entity %d more than once", e.ID)
+		}
+		comps := map[string]json.RawMessage{}
+		for name, value := range e.Components {
+			if keep(name) {
+				comps[name] = value
+			}
+		}
+		if len(comps) > 0 {
+			byID[e.ID] = comps
+		}
+	}
+	return byID, nil
+}
+
+func sortedIDs[V any](m map[types.EntityID]V) []types.EntityID {
+	ids := make([]types.EntityID, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func compareEntity(id types.EntityID, before, after map[string]json.RawMessage) (*EntityDiff, error) {
+	d := EntityDiff{ID: id}
+	for name, value := range before {
+		if _, ok := after[name]; !ok {
+			if d.RemovedComponents == nil {
+				d.RemovedComponents = map[string]json.RawMessage{}
+			}
+			d.RemovedComponents[name] = value
+		}
+	}
+	names := make([]string, 0, len(after))
+	for name := range after {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		beforeValue, ok := before[name]
+		if !ok {
+			if d.AddedComponents == nil {
+				d.AddedComponents = map[string]json.RawMessage{}
+			}
+			d.AddedComponents[name] = after[name]
+			continue
+		}
+		b, err := decodeValue(beforeValue)
+		if err != nil {
+			return nil, eris.Wrapf(err, "invalid value of component %q of entity %d", name, id)
+		}
+		a, err := decodeValue(after[name])
+		if err != nil {
+			return nil, eris.Wrapf(err, "invalid value of component %q of entity %d", name, id)
+		}
+		d.Fields = appendChanges(d.Fields, name, "", b, a)
+	}
+	if d.AddedComponents == nil && d.RemovedComponents == nil && d.Fields == nil {
+		return nil, nil //nolint:nilnil // an unchanged entity has no diff
+	}
+	return &d, nil
+}
+
+// decodeValue decodes a component value, keeping its numbers as they are written, so that large integers such as
+// entity IDs are compared exactly.
+func decodeValue(bz json.RawMessage) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// appendChanges appends the changes between the decoded JSON values at the path.
+func appendChanges(changes []FieldChange, component, path string, before, after any) []FieldChange {
+	switch b := before.(type) {
+	case map[string]any:
+		a, ok := after.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(b)+len(a))
+		for k := range b {
+			keys = append(keys, k)
+		}
+		for k := range a {
+			if _, ok := b[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			bv, inBefore := b[k]
+			av, inAfter := a[k]
+			p := joinPath(path, k)
+			switch {
+			case !inBefore:
+				changes = append(changes, FieldChange{Component: component, Path: p, After: mustMarshal(av)})
+			case !inAfter:
+				changes = append(changes, FieldChange{Component: component, Path: p, Before: mustMarshal(bv)})
+			default:
+				changes = appendChanges(changes, component, p, bv, av)
+			}
+		}
+		return changes
+	case []any:
+		a, ok := after.([]any)
+		if !ok {
+			break
+		}
+		for i := range max(len(a), len(b)) {
+			p := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(b):
+				changes = append(changes, FieldChange{Component: component, Path: p, After: mustMarshal(a[i])})
+			case i >= len(a):
+				changes = append(changes, FieldChange{Component: component, Path: p, Before: mustMarshal(b[i])})
+			default:
+				changes = appendChanges(changes, component, p, b[i], a[i])
+			}
+		}
+		return changes
+	}
+	bz, az := mustMarshal(before), mustMarshal(after)
+	if bytes.Equal(bz, az) {
+		return changes
+	}
+	return append(changes, FieldChange{Component: component, Path: path, Before: bz, After: az})
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// mustMarshal encodes a value that was decoded by decodeValue, which can't fail.
+func mustMarshal(v any) json.RawMessage {
+	bz, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return bz
+}
+
+// WriteText writes the diff in a form that is meant to be read by people: added entities are marked with +, removed
+// entities with -, and changed entities with ~.
+func (d Diff) WriteText(w io.Writer) error {
+	var sb strings.Builder
+	for _, e := range d.Removed {
+		fmt.Fprintf(&sb, "- entity %d\n", e.ID)
+		writeComponents(&sb, "    ", e.Components)
+	}
+	for _, e := range d.Added {
+		fmt.Fprintf(&sb, "+ entity %d\n", e.ID)
+		writeComponents(&sb, "    ", e.Components)
+	}
+	for _, e := range d.Changed {
+		fmt.Fprintf(&sb, "~ entity %d\n", e.ID)
+		writeComponents(&sb, "    - ", e.RemovedComponents)
+		writeComponents(&sb, "    + ", e.AddedComponents)
+		for _, f := range e.Fields {
+			name := f.Component
+			if f.Path != "" && !strings.HasPrefix(f.Path, "[") {
+				name += "."
+			}
+			name += f.Path
+			fmt.Fprintf(&sb, "    %s: %s -> %s\n", name, textValue(f.Before), textValue(f.After))
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return eris.Wrap(err, "")
+}
+
+func writeComponents(sb *strings.Builder, prefix string, comps map[string]json.RawMessage) {
+	names := make([]string, 0, len(comps))
+	for name := range comps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(sb, "%s%s: %s\n", prefix, name, comps[name])
+	}
+}
+
+func textValue(v json.RawMessage) string {
+	if v == nil {
+		return "(none)"
+	}
+	return string(v)
+}
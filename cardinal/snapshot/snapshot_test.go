@@ -0,0 +1,76 @@
+package snapshot_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/snapshot"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+const before = `[
+	{"id": 1, "components": {"health": {"hp": 10}, "position": {"x": 1, "y": 2}}},
+	{"id": 2, "components": {"health": {"hp": 5}, "inventory": {"items": ["sword"]}}},
+	{"id": 3, "components": {"position": {"x": 0, "y": 0}}}
+]`
+
+const after = `[
+	{"id": 1, "components": {"health": {"hp": 7}, "position": {"x": 1, "y": 2}}},
+	{"id": 2, "components": {"health": {"hp": 5, "shield": 3}, "inventory": {"items": ["sword", "bow"]}, "tag": "hero"}},
+	{"id": 4, "components": {"position": {"x": 9, "y": 9}}}
+]`
+
+func decode(t *testing.T, s string) snapshot.Snapshot {
+	snap, err := snapshot.Decode(strings.NewReader(s))
+	assert.NilError(t, err)
+	return snap
+}
+
+func TestCompare(t *testing.T) {
+	diff, err := snapshot.Compare(decode(t, before), decode(t, after))
+	assert.NilError(t, err)
+	assert.Equal(t, len(diff.Removed), 1)
+	assert.Equal(t, diff.Removed[0].ID, types.EntityID(3))
+	assert.Equal(t, len(diff.Added), 1)
+	assert.Equal(t, diff.Added[0].ID, types.EntityID(4))
+	assert.Equal(t, len(diff.Changed), 2)
+
+	assert.DeepEqual(t, diff.Changed[0].Fields, []snapshot.FieldChange{
+		{Component: "health", Path: "hp", Before: json.RawMessage("10"), After: json.RawMessage("7")},
+	})
+	second := diff.Changed[1]
+	assert.DeepEqual(t, second.AddedComponents, map[string]json.RawMessage{"tag": json.RawMessage(`"hero"`)})
+	assert.DeepEqual(t, second.Fields, []snapshot.FieldChange{
+		{Component: "health", Path: "shield", After: json.RawMessage("3")},
+		{Component: "inventory", Path: "items[1]", After: json.RawMessage(`"bow"`)},
+	})
+
+	var text strings.Builder
+	assert.NilError(t, diff.WriteText(&text))
+	assert.Equal(t, text.String(), `- entity 3
+    position: {"x": 0, "y": 0}
++ entity 4
+    position: {"x": 9, "y": 9}
+~ entity 1
+    health.hp: 10 -> 7
+~ entity 2
+    + tag: "hero"
+    health.shield: (none) -> 3
+    inventory.items[1]: (none) -> "bow"
+`)
+}
+
+func TestCompareFiltersComponents(t *testing.T) {
+	diff, err := snapshot.Compare(decode(t, before), decode(t, after), "inventory")
+	assert.NilError(t, err)
+	assert.Equal(t, len(diff.Added), 0)
+	assert.Equal(t, len(diff.Removed), 0)
+	assert.Equal(t, len(diff.Changed), 1)
+	assert.Equal(t, diff.Changed[0].ID, types.EntityID(2))
+
+	diff, err = snapshot.Compare(decode(t, before), decode(t, before))
+	assert.NilError(t, err)
+	assert.Check(t, diff.IsEmpty())
+}
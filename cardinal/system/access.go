@@ -0,0 +1,40 @@
+package system
+
+import (
+	"slices"
+
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// Access is the set of components, by name, that a system reads and writes.
+type Access struct {
+	Read  []string
+	Write []string
+}
+
+// conflicts reports whether the outcome of the two systems could depend on the order they run in, because one of
+// them writes a component that the other reads or writes.
+func (a Access) conflicts(b Access) bool {
+	for _, c := range a.Write {
+		if slices.Contains(b.Write, c) || slices.Contains(b.Read, c) {
+			return true
+		}
+	}
+	for _, c := range b.Write {
+		if slices.Contains(a.Read, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsolatedContext is the context of a system that runs concurrently with other systems. Flush applies the changes
+// that the system buffered, such as its events and transaction results, to the context of the tick.
+type IsolatedContext interface {
+	engine.Context
+	Flush() error
+}
+
+// Isolation returns the contexts of the systems, by name, that run concurrently starting at the given position in
+// the tick.
+type Isolation func(wCtx engine.Context, first int, systemNames []string) []IsolatedContext
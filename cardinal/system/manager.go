@@ -2,10 +2,12 @@ package system
 
 import (
 	"fmt"
+	"maps"
 	"path/filepath"
 	"reflect"
 	"runtime"
 	"slices"
+	"sync"
 	"time"
 
 	"github.com/rotisserie/eris"
@@ -28,6 +30,18 @@ type Manager struct {
 
 	// currentSystem is the name of the system that is currently running.
 	currentSystem *string
+	// currentIndex is the position of the running system in the tick's run order, or -1.
+	currentIndex int
+
+	// observer is told when each system starts running, and returns the function to call when it finishes.
+	observer func(systemName string) (done func())
+
+	// access is the declared access of the systems that may run concurrently with other systems.
+	access map[string]Access
+	// isolation returns the contexts of systems that run concurrently. Systems only run concurrently if it is set.
+	isolation Isolation
+	// prefetch loads the components that systems declared they use before the systems run.
+	prefetch func(access Access) error
 }
 
 // NewManager creates a new system manager.
@@ -35,7 +49,9 @@ func NewManager() *Manager {
 	return &Manager{
 		registeredSystems: make([]string, 0),
 		systemFn:          make(map[string]System),
+		access:            make(map[string]Access),
 		currentSystem:     nil,
+		currentIndex:      -1,
 	}
 }
 
@@ -53,13 +69,24 @@ func (m *Manager) RegisterInitSystems(systems ...System) error {
 	return m.registerSystems(true, systems...)
 }
 
+// RegisterSystemsWithAccess registers systems that only use the components of the given access. Consecutive systems
+// whose accesses don't conflict run concurrently; see SetIsolation.
+func (m *Manager) RegisterSystemsWithAccess(access Access, systems ...System) error {
+	if err := m.registerSystems(false, systems...); err != nil {
+		return err
+	}
+	for _, sys := range systems {
+		m.access[Name(sys)] = access
+	}
+	return nil
+}
+
 func (m *Manager) registerSystems(isInit bool, systems ...System) error {
 	// Iterate through all the systems and check if they are already registered.
 	// This is done before registering any of the systems to ensure that all are registered or none of them are.
 	systemNames := make([]string, 0, len(systems))
 	for _, sys := range systems {
-		// Obtain the name of the system function using reflection.
-		systemName := filepath.Base(runtime.FuncForPC(reflect.ValueOf(sys).Pointer()).Name())
+		systemName := Name(sys)
 
 		// Check for duplicate system names within the list of systems to be registered
 		if slices.Contains(systemNames, systemName) {
@@ -89,7 +116,31 @@ func (m *Manager) registerSystems(isInit bool, systems ...System) error {
 	return nil
 }
 
-// RunSystems runs all the registered system in the order that they were registered.
+// Name returns the name that the system is registered under, which is derived from the function name.
+func Name(sys System) string {
+	return filepath.Base(runtime.FuncForPC(reflect.ValueOf(sys).Pointer()).Name())
+}
+
+// SetObserver makes the manager call observer before it runs each system, and the function that observer returns
+// after the system finishes.
+func (m *Manager) SetObserver(observer func(systemName string) (done func())) {
+	m.observer = observer
+}
+
+// SetIsolation makes the manager run consecutive systems with non-conflicting accesses concurrently, each with the
+// context that isolation returns for it. Systems run one after another while an observer is set.
+func (m *Manager) SetIsolation(isolation Isolation) {
+	m.isolation = isolation
+}
+
+// SetPrefetch makes the manager call prefetch with the declared access of each system, or the combined access of
+// systems that run concurrently, before they run. Systems without a declared access aren't prefetched for.
+func (m *Manager) SetPrefetch(prefetch func(access Access) error) {
+	m.prefetch = prefetch
+}
+
+// RunSystems runs all the registered system in the order that they were registered. Systems that run concurrently
+// apply their buffered changes in that order too, so the outcome of the tick doesn't depend on how they were scheduled.
 func (m *Manager) RunSystems(wCtx engine.Context) error {
 	var systemsToRun []string
 	if wCtx.CurrentTick() == 0 {
@@ -100,28 +151,27 @@ func (m *Manager) RunSystems(wCtx engine.Context) error {
 	}
 
 	allSystemStartTime := time.Now()
-	for _, systemName := range systemsToRun {
-		// Explicit memory aliasing
-		sysName := systemName
-		m.currentSystem = &sysName
-
-		// Inject the system name into the logger
-		wCtx.SetLogger(wCtx.Logger().With().Str("system", systemName).Logger())
-
-		// Executes the system function that the user registered
-		systemStartTime := time.Now()
-		err := m.systemFn[systemName](wCtx)
+	for i := 0; i < len(systemsToRun); {
+		batch := m.nextBatch(systemsToRun[i:])
+		err := m.prefetchFor(batch)
+		if err == nil {
+			if len(batch) > 1 {
+				err = m.runConcurrently(wCtx, i, batch)
+			} else {
+				err = m.runSystem(wCtx, i, batch[0])
+			}
+		}
 		if err != nil {
 			m.currentSystem = nil
-			return eris.Wrapf(err, "system %s generated an error", systemName)
+			m.currentIndex = -1
+			return err
 		}
-
-		// Emit the total time it took to run `systemName`
-		statsd.EmitTickStat(systemStartTime, systemName)
+		i += len(batch)
 	}
 
 	// Set the current system to nil to indicate that no system is currently running
 	m.currentSystem = nil
+	m.currentIndex = -1
 
 	// Emit the total time it took to run all systems
 	statsd.EmitTickStat(allSystemStartTime, "all_systems")
@@ -129,6 +179,130 @@ func (m *Manager) RunSystems(wCtx engine.Context) error {
 	return nil
 }
 
+// nextBatch returns the systems at the start of the list that can run concurrently: consecutive systems with declared
+// accesses that don't conflict with each other. Otherwise, it returns the first system.
+func (m *Manager) nextBatch(systemNames []string) []string {
+	if m.isolation == nil || m.observer != nil {
+		return systemNames[:1]
+	}
+	var accesses []Access
+	for i, systemName := range systemNames {
+		access, ok := m.access[systemName]
+		if !ok || slices.ContainsFunc(accesses, access.conflicts) {
+			return systemNames[:max(i, 1)]
+		}
+		accesses = append(accesses, access)
+	}
+	return systemNames
+}
+
+// prefetchFor prefetches the components that the systems, which are about to run, declared they use.
+func (m *Manager) prefetchFor(systemNames []string) error {
+	if m.prefetch == nil {
+		return nil
+	}
+	var combined Access
+	for _, systemName := range systemNames {
+		access, ok := m.access[systemName]
+		if !ok {
+			continue
+		}
+		combined.Read = append(combined.Read, access.Read...)
+		combined.Write = append(combined.Write, access.Write...)
+	}
+	if len(combined.Read) == 0 && len(combined.Write) == 0 {
+		return nil
+	}
+	return eris.Wrap(m.prefetch(combined), "failed to prefetch the components of systems")
+}
+
+func (m *Manager) runSystem(wCtx engine.Context, i int, systemName string) error {
+	// Explicit memory aliasing
+	sysName := systemName
+	m.currentSystem = &sysName
+	m.currentIndex = i
+
+	// Inject the system name into the logger
+	wCtx.SetLogger(wCtx.Logger().With().Str("system", systemName).Logger())
+
+	// Executes the system function that the user registered
+	systemStartTime := time.Now()
+	var done func()
+	if m.observer != nil {
+		done = m.observer(systemName)
+	}
+	err := m.systemFn[systemName](wCtx)
+	if done != nil {
+		done()
+	}
+	if err != nil {
+		return eris.Wrapf(err, "system %s generated an error", systemName)
+	}
+
+	// Emit the total time it took to run `systemName`
+	statsd.EmitTickStat(systemStartTime, systemName)
+	return nil
+}
+
+// runConcurrently runs the systems, which start at the given position in the tick, in parallel goroutines. No system
+// is current while they run. Once all of them have finished, their changes are applied in order, up to the first
+// system that failed.
+func (m *Manager) runConcurrently(wCtx engine.Context, first int, systemNames []string) error {
+	m.currentSystem = nil
+	m.currentIndex = -1
+
+	ctxs := m.isolation(wCtx, first, systemNames)
+	errs := make([]error, len(systemNames))
+	var wg sync.WaitGroup
+	// A system that panics is re-panicked in the tick's goroutine, where the world handles it as it handles the panics
+	// of systems that run one after another.
+	var panicOnce sync.Once
+	var panicValue any
+	for j, systemName := range systemNames {
+		ctxs[j].SetLogger(wCtx.Logger().With().Str("system", systemName).Logger())
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					panicOnce.Do(func() { panicValue = r })
+				}
+			}()
+			systemStartTime := time.Now()
+			errs[j] = m.systemFn[systemName](ctxs[j])
+			statsd.EmitTickStat(systemStartTime, systemName)
+		}()
+	}
+	wg.Wait()
+	if panicValue != nil {
+		panic(panicValue)
+	}
+
+	for j, systemName := range systemNames {
+		if errs[j] != nil {
+			return eris.Wrapf(errs[j], "system %s generated an error", systemName)
+		}
+		if err := ctxs[j].Flush(); err != nil {
+			return eris.Wrapf(err, "failed to apply the changes of system %s", systemName)
+		}
+	}
+	return nil
+}
+
+// Copy returns a manager that runs the same systems, and tracks its own current system. Systems that are registered
+// with either manager after the copy is made are not shared, and the copy has no observer, isolation or prefetch, so
+// its systems run one after another.
+func (m *Manager) Copy() *Manager {
+	return &Manager{
+		registeredSystems:     slices.Clip(m.registeredSystems),
+		registeredInitSystems: slices.Clip(m.registeredInitSystems),
+		systemFn:              maps.Clone(m.systemFn),
+		access:                maps.Clone(m.access),
+		currentSystem:         nil,
+		currentIndex:          -1,
+	}
+}
+
 func (m *Manager) GetRegisteredSystemNames() []string {
 	return m.registeredSystems
 }
@@ -140,6 +314,12 @@ func (m *Manager) GetCurrentSystem() string {
 	return *m.currentSystem
 }
 
+// GetCurrentSystemIndex returns the position of the running system in the order that the systems of the tick run in,
+// with the init systems first in tick 0, or -1 if no system is running.
+func (m *Manager) GetCurrentSystemIndex() int {
+	return m.currentIndex
+}
+
 // isSystemNameUnique checks if the system name already exists in the system map
 func (m *Manager) isSystemNameUnique(systemName string) error {
 	if _, ok := m.systemFn[systemName]; ok {
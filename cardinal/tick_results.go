@@ -2,16 +2,47 @@ package cardinal
 
 import (
 	"encoding/json"
+	"errors"
 
 	"github.com/rotisserie/eris"
+	"google.golang.org/protobuf/encoding/protowire"
 
 	"pkg.world.dev/world-engine/cardinal/receipt"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// Field numbers of the binary encoding of TickResults, which is the protobuf encoding of:
+//
+//	message TickResults {
+//	  uint64 tick = 1;
+//	  repeated Receipt receipts = 2;
+//	  repeated bytes events = 3;
+//	  repeated bytes priority_events = 4;
+//	}
+//
+//	message Receipt {
+//	  string tx_hash = 1;
+//	  bytes result = 2; // JSON encoded
+//	  repeated string errors = 3;
+//	}
+const (
+	tickResultsTickField           protowire.Number = 1
+	tickResultsReceiptsField       protowire.Number = 2
+	tickResultsEventsField         protowire.Number = 3
+	tickResultsPriorityEventsField protowire.Number = 4
+
+	receiptTxHashField protowire.Number = 1
+	receiptResultField protowire.Number = 2
+	receiptErrorsField protowire.Number = 3
 )
 
 type TickResults struct {
 	Tick     uint64
 	Receipts []receipt.Receipt
 	Events   [][]byte
+	// PriorityEvents are the events that are broadcast ahead of the queued tick results of earlier ticks; see
+	// engine.Context.EmitPriorityEvent.
+	PriorityEvents [][]byte `json:",omitempty"`
 }
 
 func NewTickResults(initialTick uint64) *TickResults {
@@ -36,6 +67,25 @@ func (tr *TickResults) AddStringEvent(e string) error {
 	return nil
 }
 
+func (tr *TickResults) AddPriorityEvent(event any) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return eris.Wrap(err, "must use a json serializable type for emitting events")
+	}
+	tr.PriorityEvents = append(tr.PriorityEvents, data)
+	return nil
+}
+
+// splitPriority returns the results without their priority events, and the results that only have the priority
+// events, if there are any.
+func (tr TickResults) splitPriority() (bulk TickResults, priority *TickResults) {
+	if len(tr.PriorityEvents) > 0 {
+		priority = &TickResults{Tick: tr.Tick, PriorityEvents: tr.PriorityEvents}
+	}
+	tr.PriorityEvents = nil
+	return tr, priority
+}
+
 func (tr *TickResults) SetReceipts(newReceipts []receipt.Receipt) {
 	tr.Receipts = newReceipts
 }
@@ -44,8 +94,122 @@ func (tr *TickResults) SetTick(tick uint64) {
 	tr.Tick = tick
 }
 
+// EventTick returns the tick of the results, so that event clients that replayed the tick log can skip the results of
+// the ticks that they already replayed.
+func (tr *TickResults) EventTick() uint64 {
+	return tr.Tick
+}
+
 func (tr *TickResults) Clear() {
 	tr.Tick = 0
 	tr.Receipts = nil
 	tr.Events = nil
+	tr.PriorityEvents = nil
+}
+
+// MarshalBinary encodes the tick results in protobuf wire format. Events are stored as raw bytes rather than the
+// base64 strings they become in JSON, which makes this encoding considerably smaller for high frequency events.
+func (tr *TickResults) MarshalBinary() ([]byte, error) {
+	var b []byte
+	b = protowire.AppendTag(b, tickResultsTickField, protowire.VarintType)
+	b = protowire.AppendVarint(b, tr.Tick)
+	for _, r := range tr.Receipts {
+		result, err := json.Marshal(r.Result)
+		if err != nil {
+			return nil, eris.Wrapf(err, "failed to marshal result of receipt %s", r.TxHash)
+		}
+		var rb []byte
+		rb = protowire.AppendTag(rb, receiptTxHashField, protowire.BytesType)
+		rb = protowire.AppendString(rb, string(r.TxHash))
+		rb = protowire.AppendTag(rb, receiptResultField, protowire.BytesType)
+		rb = protowire.AppendBytes(rb, result)
+		for _, e := range r.Errs {
+			rb = protowire.AppendTag(rb, receiptErrorsField, protowire.BytesType)
+			rb = protowire.AppendString(rb, e.Error())
+		}
+		b = protowire.AppendTag(b, tickResultsReceiptsField, protowire.BytesType)
+		b = protowire.AppendBytes(b, rb)
+	}
+	for _, event := range tr.Events {
+		b = protowire.AppendTag(b, tickResultsEventsField, protowire.BytesType)
+		b = protowire.AppendBytes(b, event)
+	}
+	for _, event := range tr.PriorityEvents {
+		b = protowire.AppendTag(b, tickResultsPriorityEventsField, protowire.BytesType)
+		b = protowire.AppendBytes(b, event)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary decodes tick results that were encoded with MarshalBinary. Receipt results are decoded as
+// json.RawMessage.
+func (tr *TickResults) UnmarshalBinary(data []byte) error {
+	*tr = TickResults{}
+	return consumeFields(data, func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) error {
+		switch {
+		case num == tickResultsTickField && typ == protowire.VarintType:
+			tr.Tick = varint
+		case num == tickResultsReceiptsField && typ == protowire.BytesType:
+			r, err := unmarshalBinaryReceipt(value)
+			if err != nil {
+				return err
+			}
+			tr.Receipts = append(tr.Receipts, r)
+		case num == tickResultsEventsField && typ == protowire.BytesType:
+			tr.Events = append(tr.Events, append([]byte(nil), value...))
+		case num == tickResultsPriorityEventsField && typ == protowire.BytesType:
+			tr.PriorityEvents = append(tr.PriorityEvents, append([]byte(nil), value...))
+		}
+		return nil
+	})
+}
+
+func unmarshalBinaryReceipt(data []byte) (receipt.Receipt, error) {
+	var r receipt.Receipt
+	err := consumeFields(data, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case receiptTxHashField:
+			r.TxHash = types.TxHash(value)
+		case receiptResultField:
+			r.Result = json.RawMessage(append([]byte(nil), value...))
+		case receiptErrorsField:
+			r.Errs = append(r.Errs, errors.New(string(value)))
+		}
+		return nil
+	})
+	return r, err
+}
+
+// consumeFields calls fn with each field in the given protobuf message. Fields of unknown types are skipped.
+func consumeFields(
+	data []byte, fn func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) error,
+) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return eris.Wrap(protowire.ParseError(n), "invalid binary tick results")
+		}
+		data = data[n:]
+		var value []byte
+		var varint uint64
+		switch typ { //nolint:exhaustive // other types are skipped
+		case protowire.VarintType:
+			varint, n = protowire.ConsumeVarint(data)
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return eris.Wrap(protowire.ParseError(n), "invalid binary tick results")
+		}
+		data = data[n:]
+		if err := fn(num, typ, value, varint); err != nil {
+			return err
+		}
+	}
+	return nil
 }
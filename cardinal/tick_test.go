@@ -1,10 +1,13 @@
 package cardinal_test
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
 
 	"pkg.world.dev/world-engine/assert"
 	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/receipt"
 	"pkg.world.dev/world-engine/cardinal/search/filter"
 	"pkg.world.dev/world-engine/cardinal/testutils"
 )
@@ -83,3 +86,31 @@ func TestCanModifyArchetypeAndGetEntity(t *testing.T) {
 	assert.NilError(t, cardinal.RemoveComponentFrom[Beta](wCtx, wantID))
 	verifyCanFindEntity()
 }
+
+func TestTickResultsBinaryRoundTrip(t *testing.T) {
+	original := cardinal.TickResults{
+		Tick: 42,
+		Receipts: []receipt.Receipt{
+			{TxHash: "0xabc", Result: map[string]any{"success": true}},
+			{TxHash: "0xdef", Errs: []error{errors.New("out of energy")}},
+		},
+		Events:         [][]byte{[]byte(`{"type":"moved"}`), []byte("plain text")},
+		PriorityEvents: [][]byte{[]byte(`{"type":"your-turn"}`)},
+	}
+	bz, err := original.MarshalBinary()
+	assert.NilError(t, err)
+
+	var decoded cardinal.TickResults
+	assert.NilError(t, decoded.UnmarshalBinary(bz))
+	assert.Equal(t, decoded.Tick, uint64(42))
+	assert.DeepEqual(t, decoded.Events, original.Events)
+	assert.DeepEqual(t, decoded.PriorityEvents, original.PriorityEvents)
+	assert.Equal(t, len(decoded.Receipts), 2)
+	assert.Equal(t, decoded.Receipts[0].TxHash, original.Receipts[0].TxHash)
+	assert.DeepEqual(t, decoded.Receipts[0].Result, json.RawMessage(`{"success":true}`))
+	assert.Equal(t, decoded.Receipts[1].Errs[0].Error(), "out of energy")
+
+	jsonBz, err := json.Marshal(original)
+	assert.NilError(t, err)
+	assert.Check(t, len(bz) < len(jsonBz))
+}
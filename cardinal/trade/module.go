@@ -0,0 +1,332 @@
+// Package trade is a module for trading items between two personas. One persona proposes a trade to another, both
+// add the items they offer, and the items are swapped in a single tick once both have confirmed.
+//
+// Trading is where games most often introduce item duplication bugs, so the swap is all-or-nothing: when the second
+// confirmation arrives, both offers are checked against the current inventories, and if either persona no longer has
+// the items the trade is cancelled without moving anything. Offered items are not locked, so a persona can still use
+// them while a trade is pending.
+//
+// Items are held in crafting inventories; see crafting.AddItems.
+package trade
+
+import (
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/crafting"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+const (
+	ModuleName    = "trade"
+	ModuleVersion = "v1.0.0"
+
+	ProposeMessageName  = "propose"
+	AddItemsMessageName = "add-items"
+	ConfirmMessageName  = "confirm"
+	CancelMessageName   = "cancel"
+
+	// DefaultExpiry is the default number of ticks a trade stays open after its last change.
+	DefaultExpiry = 600
+)
+
+// Event types, which are emitted to both parties of a trade.
+const (
+	EventProposed  = "trade-proposed"
+	EventUpdated   = "trade-updated"
+	EventCompleted = "trade-completed"
+	EventCancelled = "trade-cancelled"
+	EventExpired   = "trade-expired"
+)
+
+var _ cardinal.Module = &Module{}
+
+// ProposeMsg proposes a trade to another persona.
+type ProposeMsg struct {
+	PersonaTag string `json:"personaTag"`
+}
+
+// AddItemsMsg adds items to the sender's offer. Both confirmations are reset.
+type AddItemsMsg struct {
+	Trade types.EntityID   `json:"trade"`
+	Items []crafting.Stack `json:"items"`
+}
+
+// ConfirmMsg accepts the current offers. The trade executes once both parties have confirmed.
+type ConfirmMsg struct {
+	Trade types.EntityID `json:"trade"`
+}
+
+// CancelMsg cancels the trade. Either party can cancel.
+type CancelMsg struct {
+	Trade types.EntityID `json:"trade"`
+}
+
+type Result struct {
+	Trade types.EntityID `json:"trade"`
+	// Executed is true if the message caused the items to be swapped.
+	Executed bool `json:"executed"`
+}
+
+type Option func(*Module)
+
+// WithExpiry sets the number of ticks a trade stays open after it was last changed.
+func WithExpiry(ticks uint64) Option {
+	return func(m *Module) {
+		m.expiry = ticks
+	}
+}
+
+type Module struct {
+	cardinal.ModuleBase
+	expiry uint64
+}
+
+func NewModule(opts ...Option) *Module {
+	m := &Module{expiry: DefaultExpiry}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func (*Module) Name() string    { return ModuleName }
+func (*Module) Version() string { return ModuleVersion }
+
+func (*Module) RegisterComponents(w *cardinal.World) error {
+	return cardinal.RegisterComponent[Trade](w)
+}
+
+func (*Module) RegisterTxs(w *cardinal.World) error {
+	if err := cardinal.RegisterMessage[ProposeMsg, Result](w, ProposeMessageName); err != nil {
+		return err
+	}
+	if err := cardinal.RegisterMessage[AddItemsMsg, Result](w, AddItemsMessageName); err != nil {
+		return err
+	}
+	if err := cardinal.RegisterMessage[ConfirmMsg, Result](w, ConfirmMessageName); err != nil {
+		return err
+	}
+	return cardinal.RegisterMessage[CancelMsg, Result](w, CancelMessageName)
+}
+
+func (*Module) RegisterReads(w *cardinal.World) error {
+	return registerReads(w)
+}
+
+func (m *Module) RegisterSystems(w *cardinal.World) error {
+	return cardinal.RegisterSystems(w, m.tradeSystem)
+}
+
+// tradeSystem processes this tick's trade messages, then expires stale trades.
+func (m *Module) tradeSystem(wCtx engine.Context) error {
+	if err := cardinal.EachMessage[ProposeMsg, Result](wCtx, func(tx message.TxData[ProposeMsg]) (Result, error) {
+		return m.propose(wCtx, tx)
+	}); err != nil {
+		return err
+	}
+	if err := cardinal.EachMessage[AddItemsMsg, Result](wCtx, func(tx message.TxData[AddItemsMsg]) (Result, error) {
+		return m.addItems(wCtx, tx)
+	}); err != nil {
+		return err
+	}
+	if err := cardinal.EachMessage[ConfirmMsg, Result](wCtx, func(tx message.TxData[ConfirmMsg]) (Result, error) {
+		return confirm(wCtx, tx)
+	}); err != nil {
+		return err
+	}
+	if err := cardinal.EachMessage[CancelMsg, Result](wCtx, func(tx message.TxData[CancelMsg]) (Result, error) {
+		return cancel(wCtx, tx)
+	}); err != nil {
+		return err
+	}
+	return expireTrades(wCtx)
+}
+
+func sender[In any](tx message.TxData[In]) (string, error) {
+	if tx.Tx == nil || tx.Tx.PersonaTag == "" {
+		return "", eris.New("trade messages must be signed by a persona")
+	}
+	return tx.Tx.PersonaTag, nil
+}
+
+// tradeFor returns the trade if the sender is one of its parties.
+func tradeFor(wCtx engine.Context, id types.EntityID, personaTag string) (*Trade, error) {
+	trade, err := cardinal.GetComponent[Trade](wCtx, id)
+	if err != nil {
+		return nil, eris.Wrapf(err, "trade %d does not exist", id)
+	}
+	if !trade.isParty(personaTag) {
+		return nil, eris.Errorf("persona %q is not a party to trade %d", personaTag, id)
+	}
+	return trade, nil
+}
+
+func emit(wCtx engine.Context, id types.EntityID, trade *Trade, eventType string) error {
+	return cardinal.EmitEventTo(wCtx, trade.parties(), map[string]any{
+		"type":  eventType,
+		"trade": id,
+	})
+}
+
+func (m *Module) propose(wCtx engine.Context, tx message.TxData[ProposeMsg]) (Result, error) {
+	proposer, err := sender(tx)
+	if err != nil {
+		return Result{}, err
+	}
+	if tx.Msg.PersonaTag == "" || tx.Msg.PersonaTag == proposer {
+		return Result{}, eris.New("a trade must be proposed to another persona")
+	}
+	trade := Trade{
+		Proposer:          proposer,
+		Counterparty:      tx.Msg.PersonaTag,
+		ProposerOffer:     []crafting.Stack{},
+		CounterpartyOffer: []crafting.Stack{},
+		ExpiresAt:         wCtx.CurrentTick() + m.expiry,
+	}
+	id, err := cardinal.Create(wCtx, trade)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Trade: id}, emit(wCtx, id, &trade, EventProposed)
+}
+
+func (m *Module) addItems(wCtx engine.Context, tx message.TxData[AddItemsMsg]) (Result, error) {
+	personaTag, err := sender(tx)
+	if err != nil {
+		return Result{}, err
+	}
+	trade, err := tradeFor(wCtx, tx.Msg.Trade, personaTag)
+	if err != nil {
+		return Result{}, err
+	}
+	for _, stack := range tx.Msg.Items {
+		if stack.Item == "" || stack.Count <= 0 {
+			return Result{}, eris.Errorf("invalid stack: %d of %q", stack.Count, stack.Item)
+		}
+	}
+	offer := trade.offerOf(personaTag)
+	combined := append(append([]crafting.Stack{}, *offer...), tx.Msg.Items...)
+	ok, err := crafting.HasItems(wCtx, personaTag, combined...)
+	if err != nil {
+		return Result{}, err
+	}
+	if !ok {
+		return Result{}, eris.Errorf("persona %q does not have the offered items", personaTag)
+	}
+
+	*offer = combined
+	trade.resetConfirmations()
+	trade.ExpiresAt = wCtx.CurrentTick() + m.expiry
+	if err := cardinal.SetComponent[Trade](wCtx, tx.Msg.Trade, trade); err != nil {
+		return Result{}, err
+	}
+	return Result{Trade: tx.Msg.Trade}, emit(wCtx, tx.Msg.Trade, trade, EventUpdated)
+}
+
+func confirm(wCtx engine.Context, tx message.TxData[ConfirmMsg]) (Result, error) {
+	personaTag, err := sender(tx)
+	if err != nil {
+		return Result{}, err
+	}
+	id := tx.Msg.Trade
+	trade, err := tradeFor(wCtx, id, personaTag)
+	if err != nil {
+		return Result{}, err
+	}
+	trade.confirm(personaTag)
+	if !trade.ProposerConfirmed || !trade.CounterpartyConfirmed {
+		if err := cardinal.SetComponent[Trade](wCtx, id, trade); err != nil {
+			return Result{}, err
+		}
+		return Result{Trade: id}, emit(wCtx, id, trade, EventUpdated)
+	}
+
+	// Both parties have confirmed. Check both offers before moving anything, so the swap either happens completely
+	// or not at all.
+	for _, party := range trade.parties() {
+		ok, err := crafting.HasItems(wCtx, party, *trade.offerOf(party)...)
+		if err != nil {
+			return Result{}, err
+		}
+		if !ok {
+			if err := removeTrade(wCtx, id, trade, EventCancelled); err != nil {
+				return Result{}, err
+			}
+			return Result{Trade: id}, eris.Errorf(
+				"trade %d was cancelled because persona %q no longer has the offered items", id, party,
+			)
+		}
+	}
+	if err := swap(wCtx, trade); err != nil {
+		return Result{}, err
+	}
+	return Result{Trade: id, Executed: true}, removeTrade(wCtx, id, trade, EventCompleted)
+}
+
+// swap moves both offers. The offers must have been checked against the inventories in the same tick.
+func swap(wCtx engine.Context, trade *Trade) error {
+	if err := crafting.RemoveItems(wCtx, trade.Proposer, trade.ProposerOffer...); err != nil {
+		return err
+	}
+	if err := crafting.RemoveItems(wCtx, trade.Counterparty, trade.CounterpartyOffer...); err != nil {
+		return err
+	}
+	if err := crafting.AddItems(wCtx, trade.Counterparty, trade.ProposerOffer...); err != nil {
+		return err
+	}
+	return crafting.AddItems(wCtx, trade.Proposer, trade.CounterpartyOffer...)
+}
+
+func cancel(wCtx engine.Context, tx message.TxData[CancelMsg]) (Result, error) {
+	personaTag, err := sender(tx)
+	if err != nil {
+		return Result{}, err
+	}
+	trade, err := tradeFor(wCtx, tx.Msg.Trade, personaTag)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Trade: tx.Msg.Trade}, removeTrade(wCtx, tx.Msg.Trade, trade, EventCancelled)
+}
+
+func removeTrade(wCtx engine.Context, id types.EntityID, trade *Trade, eventType string) error {
+	if err := cardinal.Remove(wCtx, id); err != nil {
+		return err
+	}
+	return emit(wCtx, id, trade, eventType)
+}
+
+// expireTrades removes the trades that have not changed or executed in time.
+func expireTrades(wCtx engine.Context) error {
+	tick := wCtx.CurrentTick()
+	ids, err := findTrades(wCtx, func(trade Trade) bool { return trade.ExpiresAt < tick })
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		trade, err := cardinal.GetComponent[Trade](wCtx, id)
+		if err != nil {
+			return err
+		}
+		if err := removeTrade(wCtx, id, trade, EventExpired); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func findTrades(wCtx engine.Context, match func(Trade) bool) ([]types.EntityID, error) {
+	ids, err := cardinal.NewSearch().
+		Entity(filter.Contains(filter.Component[Trade]())).
+		Where(cardinal.FilterFunction[Trade](match)).
+		Collect(wCtx)
+	if err != nil {
+		return nil, err
+	}
+	sortIDs(ids)
+	return ids, nil
+}
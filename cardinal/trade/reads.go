@@ -0,0 +1,50 @@
+package trade
+
+import (
+	"sort"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type TradesRequest struct {
+	PersonaTag string `json:"personaTag"`
+}
+
+type TradeInfo struct {
+	Trade
+	ID types.EntityID `json:"id"`
+}
+
+type TradesReply struct {
+	// Trades lists the open trades the persona is a party to, oldest first.
+	Trades []TradeInfo `json:"trades"`
+}
+
+// registerReads registers the /query/trade/list query.
+func registerReads(w *cardinal.World) error {
+	return cardinal.RegisterReads(w, ModuleName,
+		cardinal.NewRead[TradesRequest, TradesReply]("list", queryTrades),
+	)
+}
+
+func queryTrades(wCtx engine.Context, req *TradesRequest) (*TradesReply, error) {
+	ids, err := findTrades(wCtx, func(trade Trade) bool { return trade.isParty(req.PersonaTag) })
+	if err != nil {
+		return nil, err
+	}
+	reply := &TradesReply{Trades: make([]TradeInfo, 0, len(ids))}
+	for _, id := range ids {
+		trade, err := cardinal.GetComponent[Trade](wCtx, id)
+		if err != nil {
+			return nil, err
+		}
+		reply.Trades = append(reply.Trades, TradeInfo{Trade: *trade, ID: id})
+	}
+	return reply, nil
+}
+
+func sortIDs(ids []types.EntityID) {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+}
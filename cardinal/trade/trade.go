@@ -0,0 +1,52 @@
+package trade
+
+import (
+	"pkg.world.dev/world-engine/cardinal/crafting"
+)
+
+// Trade is a proposed exchange of items between two personas. Items stay in their owners' inventories until the
+// trade executes, and are only moved once both personas have confirmed the current offers.
+type Trade struct {
+	Proposer     string `json:"proposer"`
+	Counterparty string `json:"counterparty"`
+	// ProposerOffer is what the proposer gives to the counterparty, and CounterpartyOffer what they get in return.
+	ProposerOffer     []crafting.Stack `json:"proposerOffer"`
+	CounterpartyOffer []crafting.Stack `json:"counterpartyOffer"`
+	// Confirmations are reset whenever either offer changes, so nobody can swap out items after the other side
+	// has confirmed.
+	ProposerConfirmed     bool `json:"proposerConfirmed"`
+	CounterpartyConfirmed bool `json:"counterpartyConfirmed"`
+	// ExpiresAt is the tick after which the trade is cancelled if it has not executed.
+	ExpiresAt uint64 `json:"expiresAt"`
+}
+
+func (Trade) Name() string { return "trade" }
+
+func (t *Trade) isParty(personaTag string) bool {
+	return personaTag == t.Proposer || personaTag == t.Counterparty
+}
+
+func (t *Trade) parties() []string {
+	return []string{t.Proposer, t.Counterparty}
+}
+
+// offerOf returns the offer of the given party.
+func (t *Trade) offerOf(personaTag string) *[]crafting.Stack {
+	if personaTag == t.Proposer {
+		return &t.ProposerOffer
+	}
+	return &t.CounterpartyOffer
+}
+
+func (t *Trade) confirm(personaTag string) {
+	if personaTag == t.Proposer {
+		t.ProposerConfirmed = true
+	} else {
+		t.CounterpartyConfirmed = true
+	}
+}
+
+func (t *Trade) resetConfirmations() {
+	t.ProposerConfirmed = false
+	t.CounterpartyConfirmed = false
+}
@@ -0,0 +1,167 @@
+package trade_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/crafting"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/trade"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type tradeFixture struct {
+	*testutils.TestFixture
+}
+
+func newTradeFixture(t *testing.T, opts ...trade.Option) tradeFixture {
+	tf := testutils.NewTestFixture(t, nil)
+	craftingModule, err := crafting.NewModule()
+	assert.NilError(t, err)
+	assert.NilError(t, tf.World.UseModule(craftingModule))
+	assert.NilError(t, tf.World.UseModule(trade.NewModule(opts...)))
+	assert.NilError(t, cardinal.RegisterInitSystems(tf.World, func(wCtx engine.Context) error {
+		if err := crafting.AddItems(wCtx, "alice", crafting.Stack{Item: "iron", Count: 3}); err != nil {
+			return err
+		}
+		if err := crafting.AddItems(wCtx, "bob", crafting.Stack{Item: "wood", Count: 2}); err != nil {
+			return err
+		}
+		return crafting.AddItems(wCtx, "carol", crafting.Stack{Item: "gold", Count: 1})
+	}))
+	tf.DoTick()
+	return tradeFixture{tf}
+}
+
+func (tf tradeFixture) send(personaTag, name string, msg any) types.TxHash {
+	msgType, ok := tf.World.GetMessageByFullName(trade.ModuleName + "." + name)
+	assert.Check(tf, ok, "message %q is not registered", name)
+	return tf.AddTransaction(msgType.ID(), msg, testutils.UniqueSignatureWithName(personaTag))
+}
+
+func (tf tradeFixture) result(hash types.TxHash) (trade.Result, []error) {
+	receipt, errs, ok := cardinal.NewReadOnlyWorldContext(tf.World).GetTransactionReceipt(hash)
+	assert.Check(tf, ok)
+	result, _ := receipt.(trade.Result)
+	return result, errs
+}
+
+func (tf tradeFixture) items(personaTag string) map[string]int {
+	items, err := crafting.Items(cardinal.NewReadOnlyWorldContext(tf.World), personaTag)
+	assert.NilError(tf, err)
+	return items
+}
+
+func (tf tradeFixture) trades(personaTag string) []trade.TradeInfo {
+	res := tf.Post("query/trade/list", trade.TradesRequest{PersonaTag: personaTag})
+	assert.Equal(tf, res.StatusCode, http.StatusOK)
+	var reply trade.TradesReply
+	assert.NilError(tf, json.NewDecoder(res.Body).Decode(&reply))
+	return reply.Trades
+}
+
+// propose opens a trade between alice and the counterparty with the given offers.
+func (tf tradeFixture) propose(counterparty string, aliceOffer, counterpartyOffer crafting.Stack) types.EntityID {
+	hash := tf.send("alice", trade.ProposeMessageName, trade.ProposeMsg{PersonaTag: counterparty})
+	tf.DoTick()
+	result, errs := tf.result(hash)
+	assert.Equal(tf, len(errs), 0)
+
+	tf.send("alice", trade.AddItemsMessageName, trade.AddItemsMsg{
+		Trade: result.Trade,
+		Items: []crafting.Stack{aliceOffer},
+	})
+	tf.send(counterparty, trade.AddItemsMessageName, trade.AddItemsMsg{
+		Trade: result.Trade,
+		Items: []crafting.Stack{counterpartyOffer},
+	})
+	tf.DoTick()
+	return result.Trade
+}
+
+func TestTradeSwapsItems(t *testing.T) {
+	tf := newTradeFixture(t)
+	id := tf.propose("bob", crafting.Stack{Item: "iron", Count: 2}, crafting.Stack{Item: "wood", Count: 2})
+
+	tf.send("alice", trade.ConfirmMessageName, trade.ConfirmMsg{Trade: id})
+	// bob changes his offer after alice confirmed, which resets her confirmation.
+	hash := tf.send("bob", trade.AddItemsMessageName, trade.AddItemsMsg{
+		Trade: id,
+		Items: []crafting.Stack{{Item: "wood", Count: 5}},
+	})
+	tf.DoTick()
+	_, errs := tf.result(hash)
+	assert.ErrorContains(t, errs[0], `persona "bob" does not have the offered items`)
+	trades := tf.trades("bob")
+	assert.Equal(t, len(trades), 1)
+	assert.Check(t, trades[0].ProposerConfirmed)
+
+	tf.send("bob", trade.AddItemsMessageName, trade.AddItemsMsg{Trade: id, Items: []crafting.Stack{}})
+	tf.DoTick()
+	assert.Check(t, !tf.trades("bob")[0].ProposerConfirmed)
+
+	tf.send("alice", trade.ConfirmMessageName, trade.ConfirmMsg{Trade: id})
+	hash = tf.send("bob", trade.ConfirmMessageName, trade.ConfirmMsg{Trade: id})
+	tf.DoTick()
+	result, errs := tf.result(hash)
+	assert.Equal(t, len(errs), 0)
+	assert.Check(t, result.Executed)
+
+	assert.DeepEqual(t, tf.items("alice"), map[string]int{"iron": 1, "wood": 2})
+	assert.DeepEqual(t, tf.items("bob"), map[string]int{"iron": 2})
+	assert.Equal(t, len(tf.trades("alice")), 0)
+}
+
+func TestTradeCannotDuplicateItems(t *testing.T) {
+	tf := newTradeFixture(t)
+	// alice offers the same iron to both bob and carol.
+	withBob := tf.propose("bob", crafting.Stack{Item: "iron", Count: 3}, crafting.Stack{Item: "wood", Count: 1})
+	withCarol := tf.propose("carol", crafting.Stack{Item: "iron", Count: 3}, crafting.Stack{Item: "gold", Count: 1})
+
+	for _, id := range []types.EntityID{withBob, withCarol} {
+		tf.send("alice", trade.ConfirmMessageName, trade.ConfirmMsg{Trade: id})
+	}
+	bobHash := tf.send("bob", trade.ConfirmMessageName, trade.ConfirmMsg{Trade: withBob})
+	carolHash := tf.send("carol", trade.ConfirmMessageName, trade.ConfirmMsg{Trade: withCarol})
+	tf.DoTick()
+
+	result, errs := tf.result(bobHash)
+	assert.Equal(t, len(errs), 0)
+	assert.Check(t, result.Executed)
+	result, errs = tf.result(carolHash)
+	assert.Check(t, !result.Executed)
+	assert.ErrorContains(t, errs[0], `persona "alice" no longer has the offered items`)
+
+	// The failed trade moved nothing.
+	assert.DeepEqual(t, tf.items("alice"), map[string]int{"wood": 1})
+	assert.DeepEqual(t, tf.items("bob"), map[string]int{"iron": 3, "wood": 1})
+	assert.DeepEqual(t, tf.items("carol"), map[string]int{"gold": 1})
+	assert.Equal(t, len(tf.trades("carol")), 0)
+}
+
+func TestTradeExpires(t *testing.T) {
+	tf := newTradeFixture(t, trade.WithExpiry(2))
+	hash := tf.send("alice", trade.ProposeMessageName, trade.ProposeMsg{PersonaTag: "bob"})
+	tf.DoTick()
+	result, _ := tf.result(hash)
+	assert.Equal(t, len(tf.trades("bob")), 1)
+
+	tf.DoTick()
+	tf.DoTick()
+	tf.DoTick()
+	assert.Equal(t, len(tf.trades("bob")), 0)
+
+	hash = tf.send("bob", trade.ConfirmMessageName, trade.ConfirmMsg{Trade: result.Trade})
+	tf.DoTick()
+	_, errs := tf.result(hash)
+	assert.ErrorContains(t, errs[0], "does not exist")
+
+	hash = tf.send("alice", trade.ProposeMessageName, trade.ProposeMsg{PersonaTag: "alice"})
+	tf.DoTick()
+	_, errs = tf.result(hash)
+	assert.ErrorContains(t, errs[0], "must be proposed to another persona")
+}
@@ -0,0 +1,62 @@
+package cardinal
+
+import (
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// TxMiddleware is called for every transaction at the start of the tick it is processed in, before any systems run.
+// Returning an error rejects the transaction: the error is recorded in the transaction's receipt, and no system sees
+// the transaction.
+//
+// Middleware is called in the same order on every replica and during recovery, so it may read and change game state
+// like a system, e.g. to charge the sender for the transaction.
+type TxMiddleware func(wCtx engine.Context, msg types.Message, tx txpool.TxData) error
+
+// RegisterTxMiddleware adds middleware that checks every transaction before systems run. Middleware is called in the
+// order it was registered, and a transaction is rejected by the first middleware that returns an error.
+func RegisterTxMiddleware(w *World, middleware ...TxMiddleware) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"engine state is %s, expected %s to register tx middleware",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	for _, fn := range middleware {
+		w.txMiddleware = append(w.txMiddleware, registeredTxMiddleware{fn: fn, module: w.registeringModule})
+	}
+	return nil
+}
+
+type registeredTxMiddleware struct {
+	fn TxMiddleware
+	// module is the name of the module that registered the middleware, or "" for the game itself.
+	module string
+}
+
+// applyTxMiddleware returns the transactions of the pool that were accepted by every middleware.
+func (w *World) applyTxMiddleware(wCtx engine.Context, pool *txpool.TxPool) *txpool.TxPool {
+	return pool.Filter(func(tx txpool.TxData) bool {
+		msg, ok := w.GetMessageByID(tx.MsgID)
+		if !ok {
+			return true
+		}
+		for _, middleware := range w.txMiddleware {
+			doneAccess := w.access.start(middleware.module)
+			doneBudget := w.budgets.start(middleware.module)
+			err := middleware.fn(wCtx, msg, tx)
+			doneBudget()
+			doneAccess()
+			if err != nil {
+				wCtx.AddMessageError(tx.TxHash, err)
+				return false
+			}
+		}
+		return true
+	})
+}
@@ -31,6 +31,13 @@ type ComponentMetadata interface { //revive:disable-line:exported
 	Decode([]byte) (Component, error)
 	GetSchema() []byte
 	ValidateAgainstSchema(targetSchema []byte) error
+	// IsEphemeral reports whether the component's values are kept only in process memory. Ephemeral component values
+	// are never persisted and are excluded from state hashes.
+	IsEphemeral() bool
+	// Doc returns the description of the component supplied at registration, if any.
+	Doc() string
+	// FieldDocs returns the descriptions of the component's fields supplied at registration, keyed by Go field name.
+	FieldDocs() map[string]string
 
 	Component
 }
@@ -5,10 +5,13 @@ import (
 
 	"github.com/rs/zerolog"
 
+	"pkg.world.dev/world-engine/cardinal/attestation"
 	"pkg.world.dev/world-engine/cardinal/gamestate"
 	"pkg.world.dev/world-engine/cardinal/receipt"
+	"pkg.world.dev/world-engine/cardinal/rng"
 	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/cardinal/types/txpool"
+	"pkg.world.dev/world-engine/cardinal/worldclock"
 	"pkg.world.dev/world-engine/sign"
 )
 
@@ -22,11 +25,31 @@ type Context interface {
 	Logger() *zerolog.Logger
 	// EmitEvent emits an event that will be broadcast to all websocket subscribers.
 	EmitEvent(map[string]any) error
+	// EmitEventBounded emits an event like EmitEvent, unless the event's topic, the value of its "type" field, has
+	// used up its budget for the tick, in which case the event is dropped and cardinal.ErrEventBudgetExceeded is
+	// returned. Systems that may emit many events should use it, and stop emitting once it fails, so that a burst of
+	// events can't exhaust the world's memory or flood its clients. See cardinal.WithEventBudgets.
+	EmitEventBounded(map[string]any) error
 	// EmitStringEvent emits a string event that will be broadcast to all websocket subscribers.
 	// This method is provided for backwards compatability. EmitEvent should be used for most cases.
 	EmitStringEvent(string) error
+	// EmitPriorityEvent emits an event that is broadcast ahead of the events of earlier ticks that are still queued,
+	// and that subscribers receive ahead of the other events they haven't received yet. It is meant for the few
+	// events whose latency matters, such as the start of a match, and not for bulk events.
+	EmitPriorityEvent(map[string]any) error
+	// EmittedEvents returns the JSON encoded events that have been emitted so far in the current tick, in the order
+	// they were emitted.
+	EmittedEvents() [][]byte
+	// Attest signs the JSON encoded payload, together with the namespace and the current tick, with the world's
+	// attestation key. It fails if the world has no attestation key, or if the context is read only.
+	Attest(payload []byte) (attestation.Attestation, error)
 	// Namespace returns the namespace of the world.
 	Namespace() string
+	// WorldClock returns the clock that maps ticks to in-game time.
+	WorldClock() *worldclock.Clock
+	// Rand returns the random number generator of the running system. It is seeded with the world's random seed, the
+	// tick, and the system's position in the tick, so a replay of the tick draws the same numbers. See WithRandomSeed.
+	Rand() *rng.RNG
 
 	// For internal use.
 
@@ -38,6 +61,7 @@ type Context interface {
 	GetMessageByType(mType reflect.Type) (types.Message, bool)
 	GetTransactionReceipt(id types.TxHash) (any, []error, bool)
 	GetSignerForPersonaTag(personaTag string, tick uint64) (addr string, err error)
+	ResolvePersonaTag(personaTag string) (string, error)
 	GetTransactionReceiptsForTick(tick uint64) ([]receipt.Receipt, error)
 	ReceiptHistorySize() uint64
 	AddTransaction(id types.MessageID, v any, sig *sign.Transaction) (uint64, types.TxHash)
@@ -9,10 +9,13 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	zerolog "github.com/rs/zerolog"
+	attestation "pkg.world.dev/world-engine/cardinal/attestation"
 	gamestate "pkg.world.dev/world-engine/cardinal/gamestate"
 	receipt "pkg.world.dev/world-engine/cardinal/receipt"
+	rng "pkg.world.dev/world-engine/cardinal/rng"
 	types "pkg.world.dev/world-engine/cardinal/types"
 	txpool "pkg.world.dev/world-engine/cardinal/types/txpool"
+	worldclock "pkg.world.dev/world-engine/cardinal/worldclock"
 	sign "pkg.world.dev/world-engine/sign"
 )
 
@@ -66,6 +69,21 @@ func (mr *MockContextMockRecorder) AddTransaction(id, v, sig interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTransaction", reflect.TypeOf((*MockContext)(nil).AddTransaction), id, v, sig)
 }
 
+// Attest mocks base method.
+func (m *MockContext) Attest(payload []byte) (attestation.Attestation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Attest", payload)
+	ret0, _ := ret[0].(attestation.Attestation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Attest indicates an expected call of Attest.
+func (mr *MockContextMockRecorder) Attest(payload interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Attest", reflect.TypeOf((*MockContext)(nil).Attest), payload)
+}
+
 // CurrentTick mocks base method.
 func (m *MockContext) CurrentTick() uint64 {
 	m.ctrl.T.Helper()
@@ -94,6 +112,34 @@ func (mr *MockContextMockRecorder) EmitEvent(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmitEvent", reflect.TypeOf((*MockContext)(nil).EmitEvent), arg0)
 }
 
+// EmitPriorityEvent mocks base method.
+func (m *MockContext) EmitPriorityEvent(arg0 map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmitPriorityEvent", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EmitPriorityEvent indicates an expected call of EmitPriorityEvent.
+func (mr *MockContextMockRecorder) EmitPriorityEvent(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmitPriorityEvent", reflect.TypeOf((*MockContext)(nil).EmitPriorityEvent), arg0)
+}
+
+// EmitEventBounded mocks base method.
+func (m *MockContext) EmitEventBounded(arg0 map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmitEventBounded", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EmitEventBounded indicates an expected call of EmitEventBounded.
+func (mr *MockContextMockRecorder) EmitEventBounded(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmitEventBounded", reflect.TypeOf((*MockContext)(nil).EmitEventBounded), arg0)
+}
+
 // EmitStringEvent mocks base method.
 func (m *MockContext) EmitStringEvent(arg0 string) error {
 	m.ctrl.T.Helper()
@@ -108,6 +154,20 @@ func (mr *MockContextMockRecorder) EmitStringEvent(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmitStringEvent", reflect.TypeOf((*MockContext)(nil).EmitStringEvent), arg0)
 }
 
+// EmittedEvents mocks base method.
+func (m *MockContext) EmittedEvents() [][]byte {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmittedEvents")
+	ret0, _ := ret[0].([][]byte)
+	return ret0
+}
+
+// EmittedEvents indicates an expected call of EmittedEvents.
+func (mr *MockContextMockRecorder) EmittedEvents() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmittedEvents", reflect.TypeOf((*MockContext)(nil).EmittedEvents))
+}
+
 // GetComponentByName mocks base method.
 func (m *MockContext) GetComponentByName(name string) (types.ComponentMetadata, error) {
 	m.ctrl.T.Helper()
@@ -268,6 +328,21 @@ func (mr *MockContextMockRecorder) ReceiptHistorySize() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiptHistorySize", reflect.TypeOf((*MockContext)(nil).ReceiptHistorySize))
 }
 
+// ResolvePersonaTag mocks base method.
+func (m *MockContext) ResolvePersonaTag(personaTag string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolvePersonaTag", personaTag)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolvePersonaTag indicates an expected call of ResolvePersonaTag.
+func (mr *MockContextMockRecorder) ResolvePersonaTag(personaTag interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolvePersonaTag", reflect.TypeOf((*MockContext)(nil).ResolvePersonaTag), personaTag)
+}
+
 // SetLogger mocks base method.
 func (m *MockContext) SetLogger(logger zerolog.Logger) {
 	m.ctrl.T.Helper()
@@ -333,3 +408,31 @@ func (mr *MockContextMockRecorder) Timestamp() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Timestamp", reflect.TypeOf((*MockContext)(nil).Timestamp))
 }
+
+// Rand mocks base method.
+func (m *MockContext) Rand() *rng.RNG {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rand")
+	ret0, _ := ret[0].(*rng.RNG)
+	return ret0
+}
+
+// Rand indicates an expected call of Rand.
+func (mr *MockContextMockRecorder) Rand() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rand", reflect.TypeOf((*MockContext)(nil).Rand))
+}
+
+// WorldClock mocks base method.
+func (m *MockContext) WorldClock() *worldclock.Clock {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorldClock")
+	ret0, _ := ret[0].(*worldclock.Clock)
+	return ret0
+}
+
+// WorldClock indicates an expected call of WorldClock.
+func (mr *MockContextMockRecorder) WorldClock() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorldClock", reflect.TypeOf((*MockContext)(nil).WorldClock))
+}
@@ -1,5 +1,7 @@
 package engine
 
+import "reflect"
+
 type Query interface {
 	// Name returns the name of the query.
 	Name() string
@@ -22,4 +24,8 @@ type Query interface {
 	IsEVMCompatible() bool
 	// GetRequestFieldInformation returns a map of the fields of the query's request type and their types.
 	GetRequestFieldInformation() map[string]any
+	// RequestType returns the Go type of the query's request.
+	RequestType() reflect.Type
+	// ReplyType returns the Go type of the query's reply.
+	ReplyType() reflect.Type
 }
@@ -1,5 +1,7 @@
 package types
 
+import "reflect"
+
 type Message interface {
 	SetID(MessageID) error
 	Name() string
@@ -21,6 +23,10 @@ type Message interface {
 
 	// GetInFieldInformation returns a map of the fields of the message's "In" type and it's field types.
 	GetInFieldInformation() map[string]any
+	// InType returns the Go type of the message's input.
+	InType() reflect.Type
+	// OutType returns the Go type of the message's output.
+	OutType() reflect.Type
 }
 
 // MessageID represents a message's id.
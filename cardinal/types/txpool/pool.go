@@ -1,6 +1,7 @@
 package txpool
 
 import (
+	"sort"
 	"sync"
 
 	"pkg.world.dev/world-engine/cardinal/types"
@@ -16,6 +17,8 @@ type TxData struct {
 	Tx     *sign.Transaction
 	// EVMSourceTxHash is the tx hash of the EVM tx that triggered this tx.
 	EVMSourceTxHash string
+	// Impersonator is the admin signer address that signed this tx on behalf of its persona, if any.
+	Impersonator string
 }
 
 type TxPool struct {
@@ -61,19 +64,25 @@ func (t *TxPool) AddEVMTransaction(id types.MessageID, v any, sig *sign.Transact
 	return t.addTransaction(id, v, sig, evmTxHash)
 }
 
+// AddImpersonatedTransaction adds a transaction that an admin signer signed on behalf of the transaction's persona.
+func (t *TxPool) AddImpersonatedTransaction(
+	id types.MessageID, v any, sig *sign.Transaction, impersonator string,
+) types.TxHash {
+	return t.Add(TxData{MsgID: id, Msg: v, Tx: sig, Impersonator: impersonator})
+}
+
 func (t *TxPool) addTransaction(id types.MessageID, v any, sig *sign.Transaction, evmTxHash string) types.TxHash {
+	return t.Add(TxData{MsgID: id, Msg: v, Tx: sig, EVMSourceTxHash: evmTxHash})
+}
+
+// Add adds the transaction to the pool. Its TxHash is set from its signed transaction.
+func (t *TxPool) Add(data TxData) types.TxHash {
 	t.mux.Lock()
 	defer t.mux.Unlock()
-	txHash := types.TxHash(sig.HashHex())
-	t.m[id] = append(t.m[id], TxData{
-		MsgID:           id,
-		TxHash:          txHash,
-		Msg:             v,
-		Tx:              sig,
-		EVMSourceTxHash: evmTxHash,
-	})
+	data.TxHash = types.TxHash(data.Tx.HashHex())
+	t.m[data.MsgID] = append(t.m[data.MsgID], data)
 	t.txsInPool++
-	return txHash
+	return data.TxHash
 }
 
 func (t *TxPool) Transactions() TxMap {
@@ -97,3 +106,41 @@ func (t *TxPool) reset() {
 func (t *TxPool) ForID(id types.MessageID) []TxData {
 	return t.m[id]
 }
+
+// Filter returns a new TxPool with only the transactions for which keep returns true. Message IDs are visited in
+// ascending order, and the transactions of each message in the order they were added, so keep is called in the same
+// order every time.
+// NOTE: like GetEVMTxs, this is only called on the copied tx queue in world.doTick, so we do not need the mutex here.
+func (t *TxPool) Filter(keep func(TxData) bool) *TxPool {
+	ids := make([]types.MessageID, 0, len(t.m))
+	for id := range t.m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	filtered := New()
+	for _, id := range ids {
+		for _, tx := range t.m[id] {
+			if keep(tx) {
+				filtered.m[id] = append(filtered.m[id], tx)
+				filtered.txsInPool++
+			}
+		}
+	}
+	return filtered
+}
+
+// Map returns a new TxPool with the transactions replaced by what fn returns for them. The message ID of a returned
+// transaction must not change.
+// NOTE: like Filter, this is only called on the copied tx queue in world.doTick, so we do not need the mutex here.
+func (t *TxPool) Map(fn func(TxData) TxData) *TxPool {
+	mapped := New()
+	for id, txs := range t.m {
+		mapped.m[id] = make([]TxData, len(txs))
+		for i, tx := range txs {
+			mapped.m[id][i] = fn(tx)
+		}
+	}
+	mapped.txsInPool = t.txsInPool
+	return mapped
+}
@@ -4,6 +4,18 @@ import "reflect"
 
 // GetFieldInformation returns a map of the fields of a struct and their types.
 func GetFieldInformation(t reflect.Type) map[string]any {
+	return GetFieldInformationNamed(t, func(field reflect.StructField) string {
+		// Check if the field has a json tag
+		if tag := field.Tag.Get("json"); tag != "" {
+			return tag
+		}
+		return field.Name
+	})
+}
+
+// GetFieldInformationNamed is like GetFieldInformation, but the fields of the struct, and of nested structs, are
+// named by the given function.
+func GetFieldInformationNamed(t reflect.Type, name func(reflect.StructField) string) map[string]any {
 	if t.Kind() != reflect.Struct {
 		return nil
 	}
@@ -12,15 +24,10 @@ func GetFieldInformation(t reflect.Type) map[string]any {
 
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
-		fieldName := field.Name
-
-		// Check if the field has a json tag
-		if tag := field.Tag.Get("json"); tag != "" {
-			fieldName = tag
-		}
+		fieldName := name(field)
 
 		if field.Type.Kind() == reflect.Struct {
-			fieldMap[fieldName] = GetFieldInformation(field.Type)
+			fieldMap[fieldName] = GetFieldInformationNamed(field.Type, name)
 		} else {
 			fieldMap[fieldName] = field.Type.String()
 		}
@@ -12,6 +12,8 @@ var NonFatalError = []error{
 	ErrComponentNotOnEntity,
 	ErrComponentAlreadyOnEntity,
 	ErrEntityMustHaveAtLeastOneComponent,
+	ErrLimitExceeded,
+	ErrNotParallelSafe,
 }
 
 // separateOptions separates the given options into ecs options, server options, and cardinal (this package) options.
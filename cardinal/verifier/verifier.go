@@ -0,0 +1,81 @@
+// Package verifier is the command that verifies the claimed history of a world: it loads a snapshot of the world's
+// state, replays the transaction log that was written after it, and checks that the hash of the resulting state
+// matches a published commitment. See cardinal.WithTxLogWriter and World.StateHash for how transaction logs and
+// commitments are produced.
+//
+// Replaying ticks runs the game's systems, so the command is built from the game's code. A game adds a main package
+// that creates and sets up its world as it normally would, and hands the world to Main instead of starting it:
+//
+//	func main() {
+//		world, err := cardinal.NewWorld()
+//		...
+//		// Register the game's components, messages and systems.
+//		...
+//		verifier.Main(world)
+//	}
+//
+// The snapshot is the world's storage, so the command is run against a Redis server that was restored from the
+// snapshot, such as the dump that was saved when the world was shut down, or against an empty one to verify the
+// world's full history. The transaction log is a file, or "-" for the standard input:
+//
+//	verify -log txs.jsonl -commitment 5f2c...
+//
+// The command exits with status 1 if the hash doesn't match the commitment.
+package verifier
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+)
+
+// Main runs the command with the world, and exits.
+func Main(world *cardinal.World) {
+	txLog := flag.String("log", "", `the transaction log to replay, or "-" for the standard input`)
+	commitment := flag.String("commitment", "", "the hex encoded state hash that the replay must lead to")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s -log <transaction log> -commitment <hash>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *txLog == "" || *commitment == "" || flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2) //nolint:gomnd // usage error
+	}
+
+	err := run(world, *txLog, *commitment)
+	if errors.Is(err, cardinal.ErrStateCommitmentMismatch) {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2) //nolint:gomnd // failure
+	}
+	fmt.Printf("state at tick %d matches the commitment\n", world.CurrentTick())
+}
+
+func run(world *cardinal.World, txLogPath, commitment string) error {
+	hash, err := hex.DecodeString(commitment)
+	if err != nil {
+		return eris.Wrap(err, "invalid commitment")
+	}
+	var txLog io.Reader = os.Stdin
+	if txLogPath != "-" {
+		f, err := os.Open(txLogPath)
+		if err != nil {
+			return eris.Wrap(err, "")
+		}
+		defer f.Close()
+		txLog = f
+	}
+	return world.VerifyStateCommitment(context.Background(), txLog, hash)
+}
@@ -2,12 +2,15 @@ package cardinal
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"sync"
 	"sync/atomic"
 	"syscall"
 	"time"
@@ -17,10 +20,14 @@ import (
 	"github.com/rs/zerolog/log"
 	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
 
+	"pkg.world.dev/world-engine/cardinal/clock"
+	"pkg.world.dev/world-engine/cardinal/codec"
 	"pkg.world.dev/world-engine/cardinal/component"
+	"pkg.world.dev/world-engine/cardinal/events"
 	"pkg.world.dev/world-engine/cardinal/gamestate"
 	ecslog "pkg.world.dev/world-engine/cardinal/log"
 	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/persona"
 	"pkg.world.dev/world-engine/cardinal/query"
 	"pkg.world.dev/world-engine/cardinal/receipt"
 	"pkg.world.dev/world-engine/cardinal/router"
@@ -34,12 +41,15 @@ import (
 	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/cardinal/types/engine"
 	"pkg.world.dev/world-engine/cardinal/types/txpool"
+	"pkg.world.dev/world-engine/cardinal/worldclock"
 	"pkg.world.dev/world-engine/cardinal/worldstage"
 	"pkg.world.dev/world-engine/sign"
 )
 
 const (
 	DefaultHistoricalTicksToStore = 10
+	DefaultEventHistorySize       = 1000
+	DefaultTickInterval           = time.Second
 	RedisDialTimeOut              = 15
 )
 
@@ -65,16 +75,148 @@ type World struct {
 	componentManager *component.Manager
 	queryManager     *query.Manager
 	router           router.Router
-	txPool           *txpool.TxPool
+	// txPool holds the transactions of the next tick. The goroutine that ticks adds transactions to it directly; other
+	// goroutines hand transactions off through txQueue.
+	txPool  *txpool.TxPool
+	txQueue chan txpool.TxData
+	// txQueueSlots holds a token for every transaction in txBatch and txQueue. Goroutines wait for a free slot before
+	// they take txQueueMu, so that sending to txQueue never blocks.
+	txQueueSlots chan struct{}
+	// txBatch holds the accepted transactions that are yet to be persisted and handed off to txQueue, in the order
+	// they were accepted. txQueueMu only guards txBatch, so it is never held during I/O or while waiting for the tick.
+	txBatch   []txpool.TxData
+	txQueueMu sync.Mutex
+	// txFlushMu is held while a batch is persisted to the transaction queue and handed off to txQueue, so that
+	// batches are persisted in the same order they are handed off.
+	txFlushMu sync.Mutex
+	// broadcaster sends tick results to the websocket clients once the server is started.
+	broadcaster *broadcaster
+
+	// Derived components are recomputed at the end of each tick; see DeriveComponent.
+	derivedComponents []derivedComponent
+	// postCommit runs read-only systems after each tick is committed; see RegisterPostCommitSystems.
+	postCommit *postCommitStage
 
 	// Receipt
 	receiptHistory *receipt.History
 	evmTxReceipts  map[string]EVMTxReceipt
+	// errorCodes turns the errors of receipts into structured errors; see RegisterErrorCode.
+	errorCodes *receipt.ErrorCodes
+
+	// Events
+	eventHistory *events.History
+
+	// Recovery
+	recovery recoveryTracker
+
+	// Hooks
+	hooks []Hooks
+
+	// txMiddleware checks transactions before systems run; see RegisterTxMiddleware.
+	txMiddleware []registeredTxMiddleware
+
+	// eventBudget drops the events that systems emit with EmitEventBounded beyond their topics' budgets; see
+	// WithEventBudgets.
+	eventBudget *eventBudget
+
+	// clock maps ticks to in-game time; see WithWorldClock.
+	clock *worldclock.Clock
+	// randomSeed seeds the random number generators of the systems; see WithRandomSeed.
+	randomSeed string
+
+	// rollback keeps the inputs of recent ticks, so they can be replayed with late transactions; see WithRollback.
+	rollback *rollbackHistory
+
+	// messageDecoding is applied to every message that is registered; see WithMessageDecoding.
+	messageDecoding codec.Options
+
+	// attestationKey signs the events of EmitAttestedEvent; see WithAttestationKey.
+	attestationKey *ecdsa.PrivateKey
+
+	// contentIDs indexes the entities with a ContentID; see GetByContentID.
+	contentIDs *contentIDIndex
+
+	// config is the configuration that modules recorded for the config hash; see RecordConfig.
+	config *configRecords
+
+	// arena holds the temporary buffers of the current tick; see TickArena.
+	arena *TickArena
+
+	// invariants are checked after every tick; see RegisterInvariant.
+	invariants      []invariant
+	invariantConfig invariantConfig
+
+	// chaos injects failures; see WithChaos.
+	chaos *chaos
+
+	// watchRules are checked at the end of every tick; see RegisterWatchRules.
+	watchRules []WatchRule
+
+	// webhooks are sent the receipts of matching transactions; see RegisterReceiptWebhooks.
+	webhooks      []*webhookSender
+	webhookRunner *webhooks
+
+	// structuralEvents are the components whose changes are emitted as events; see WithStructuralEvents.
+	structuralEvents *structuralEvents
+
+	// adminSigners may sign transactions on behalf of any persona, unless impersonation is disabled; see
+	// WithAdminSigners.
+	adminSigners          []string
+	impersonationDisabled bool
+
+	// personaNamePolicy decides which persona tags may be registered; see WithPersonaNamePolicy.
+	personaNamePolicy persona.NamePolicy
+	// reservedPersonaTags are the lowercase persona tags that can't be registered; see WithReservedPersonaTags.
+	reservedPersonaTags map[string]struct{}
+	// livePersonaIndex is the persona index of the state that the running tick sees; see buildPersonaIndex.
+	livePersonaIndex livePersonaIndex
+	// personaIndex is the persona index as of the last committed tick; see GetPersonaEntity.
+	personaIndex committedPersonaIndex
+
+	// Fingerprint; see world_fingerprint.go.
+	gameVersion              string
+	migrations               map[string]Migration
+	migration                func(engine.Context) error // migration is run in the next tick.
+	fingerprintPinned        bool
+	fingerprintConfigVersion uint64
+
+	// tickLog is set if the events of every tick are saved with it; see WithTickLog.
+	tickLog bool
+
+	// conflicts tracks the write conflicts between message types; see WithConflictDetection.
+	conflicts *conflictTracker
+
+	// budgets accounts the tick time and storage operations of each module; see WithModuleBudgets.
+	budgets *moduleAccounting
+
+	// access enforces the component access policies of modules; see AccessControlledModule and WithModuleAccess.
+	access *moduleAccess
+
+	// storageMetrics times the storage operations of every tick; see WithStorageMetrics.
+	storageMetrics *storageMetrics
+
+	// Modules
+	modules []servertypes.ModuleInfo
+	// registeringModule is the name of the module that UseModule is registering, if any.
+	registeringModule string
+	// componentOwners maps each component name to the module that registered it, or to "" for the game itself.
+	componentOwners map[string]string
+	// fieldIndexes are the component fields that have an index, as "component.Field"; see RegisterIndex.
+	fieldIndexes map[string]bool
+	// systemOwners maps the names of the systems that modules registered to their modules.
+	systemOwners map[string]string
+	// txLog receives the transactions of every committed tick, if set; see WithTxLogWriter.
+	txLog *txLogWriter
+
+	// systemAccess is the declared component access of the systems that may run concurrently; see
+	// RegisterSystemsWithAccess.
+	systemAccess map[string]ComponentAccess
 
 	// Tick
 	tick            *atomic.Uint64
 	timestamp       *atomic.Uint64
 	tickResults     *TickResults
+	timeSource      clock.Clock // timeSource times and timestamps ticks; see WithClock.
 	tickChannel     <-chan time.Time
 	tickDoneChannel chan<- uint64
 	// addChannelWaitingForNextTick accepts a channel which will be closed after a tick has been completed.
@@ -133,17 +275,32 @@ func NewWorld(opts ...WorldOption) (*World, error) {
 		queryManager:     query.NewManager(),
 		router:           nil, // Will be set if run mode is production or its injected via options
 		txPool:           txpool.New(),
+		txQueue:          make(chan txpool.TxData, DefaultTxQueueCapacity),
+		txQueueSlots:     make(chan struct{}, DefaultTxQueueCapacity),
+		postCommit:       newPostCommitStage(),
+		contentIDs:       newContentIDIndex(),
+		config:           newConfigRecords(),
+		arena:            newTickArena(),
+		gameVersion:      defaultGameVersion(),
+		migrations:       map[string]Migration{},
 
 		// Receipt
 		receiptHistory: receipt.NewHistory(tick.Load(), DefaultHistoricalTicksToStore),
 		evmTxReceipts:  make(map[string]EVMTxReceipt),
+		errorCodes:     receipt.NewErrorCodes(),
+
+		// Events
+		eventHistory: events.NewHistory(DefaultEventHistorySize),
 
 		// Tick
 		tick:                         tick,
 		timestamp:                    new(atomic.Uint64),
 		tickResults:                  NewTickResults(tick.Load()),
-		tickChannel:                  time.Tick(time.Second), //nolint:staticcheck // its ok.
-		tickDoneChannel:              nil,                    // Will be injected via options
+		clock:                        worldclock.Default(),
+		timeSource:                   clock.Real(),
+		eventBudget:                  newEventBudget(nil),
+		tickChannel:                  nil, // Will be injected via options, or set from the timeSource
+		tickDoneChannel:              nil, // Will be injected via options
 		addChannelWaitingForNextTick: make(chan chan struct{}),
 	}
 
@@ -160,12 +317,23 @@ func NewWorld(opts ...WorldOption) (*World, error) {
 		}
 	}
 
+	// Write conflicts are reported in development mode, unless WithConflictDetection says otherwise.
+	if !cfg.CardinalRollupEnabled {
+		world.conflicts = newConflictTracker()
+	}
+
 	// Apply options
 	for _, opt := range cardinalOptions {
 		opt(world)
 	}
+	if world.tickChannel == nil {
+		world.tickChannel = world.timeSource.Tick(DefaultTickInterval)
+	}
 
+	world.systemManager.SetIsolation(world.isolateSystems)
+	world.systemManager.SetPrefetch(world.prefetchComponents)
 	world.RegisterPlugin(newPersonaPlugin())
+	world.RegisterPlugin(newSchedulePlugin())
 
 	var metricTags []string
 	metricTags = append(metricTags, "cardinal_namespace:"+cfg.CardinalNamespace)
@@ -191,14 +359,14 @@ func (w *World) CurrentTick() uint64 {
 	return w.tick.Load()
 }
 
+// WorldClock returns the clock that maps the world's ticks to in-game time.
+func (w *World) WorldClock() *worldclock.Clock {
+	return w.clock
+}
+
 // doTick performs one game tick. This consists of taking a snapshot of all pending transactions, then calling
 // each System in turn with the snapshot of transactions.
 func (w *World) doTick(ctx context.Context, timestamp uint64) (err error) {
-	// Record tick start time for statsd.
-	// Not to be confused with `timestamp` that represents the time context for the tick
-	// that is injected into system via WorldContext.Timestamp() and recorded into the DA.
-	startTime := time.Now()
-
 	// The world can only perform a tick if:
 	// - We're in a recovery tick
 	// - The world is currently running
@@ -219,11 +387,54 @@ func (w *World) doTick(ctx context.Context, timestamp uint64) (err error) {
 		span.Finish()
 	}()
 
+	// Late transactions change the state that this tick starts from, so they are applied first.
+	if err := w.replayLateTransactions(ctx); err != nil {
+		return err
+	}
+
 	log.Info().Int("tick", int(w.CurrentTick())).Msg("Tick started")
 
-	// Copy the transactions from the pool so that we can safely modify the pool while the tick is running.
+	// Take the transactions that were handed off since the last tick. Transactions that are handed off from now on
+	// are executed in the next tick.
+	w.drainTxQueue()
 	txPool := w.txPool.CopyTransactions()
 
+	return w.executeTick(ctx, timestamp, txPool, false)
+}
+
+// newSystemsContext returns the engine context to inject into systems. Systems see the transactions of the pool and
+// the scheduled transactions that are due, with persona aliases resolved to their primaries, and only those that were
+// accepted by the middleware.
+func (w *World) newSystemsContext(txPool *txpool.TxPool) (engine.Context, error) {
+	wCtx := newWorldContextForTick(w, txPool)
+	txPool, err := w.withScheduledTxs(wCtx, txPool)
+	if err != nil {
+		return nil, err
+	}
+	wCtx = newWorldContextForTick(w, txPool)
+	resolved, err := resolvePersonaAliases(wCtx, txPool)
+	if err != nil {
+		return nil, err
+	}
+	if resolved != txPool {
+		wCtx = newWorldContextForTick(w, resolved)
+	}
+	if len(w.txMiddleware) > 0 {
+		// Systems only see the transactions that were accepted by the middleware.
+		wCtx = newWorldContextForTick(w, w.applyTxMiddleware(wCtx, resolved))
+	}
+	return wCtx, nil
+}
+
+// executeTick executes the transactions in a tick, and commits the resulting state. Replayed ticks were already
+// executed before the world rewound to them, so their transactions aren't submitted again, and hooks aren't called.
+func (w *World) executeTick(ctx context.Context, timestamp uint64, txPool *txpool.TxPool, replay bool) error {
+	// Record tick start time for statsd.
+	// Not to be confused with `timestamp` that represents the time context for the tick
+	// that is injected into system via WorldContext.Timestamp() and recorded into the DA.
+	startTime := time.Now()
+	defer w.arena.reset()
+
 	if err := w.entityStore.StartNextTick(w.msgManager.GetRegisteredMessages(), txPool); err != nil {
 		return err
 	}
@@ -231,28 +442,101 @@ func (w *World) doTick(ctx context.Context, timestamp uint64) (err error) {
 	// Store the timestamp for this tick
 	w.timestamp.Store(timestamp)
 
+	callHooks := w.worldStage.Current() != worldstage.Recovering && !replay
+	if callHooks {
+		w.callTickStartHooks(w.CurrentTick(), timestamp)
+	}
+
+	w.recordImpersonations(txPool)
+
 	// Create the engine context to inject into systems
-	wCtx := newWorldContextForTick(w, txPool)
+	wCtx, err := w.newSystemsContext(txPool)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range w.clock.BoundaryEvents(w.CurrentTick()) {
+		if err := w.tickResults.AddEvent(event); err != nil {
+			return err
+		}
+	}
+
+	if w.migration != nil {
+		if err := w.migration(wCtx); err != nil {
+			return eris.Wrap(err, "failed to migrate state")
+		}
+		w.migration = nil
+	}
 
 	// Run all registered systems.
 	// This will run the registered init systems if the current tick is 0
 	if err := w.systemManager.RunSystems(wCtx); err != nil {
 		return err
 	}
+	if w.conflicts != nil {
+		w.conflicts.finish(w.CurrentTick())
+	}
+	if w.budgets != nil {
+		w.budgets.finish(w.CurrentTick())
+	}
+	w.eventBudget.finish(w.CurrentTick())
+
+	// Recompute derived components whose inputs were changed by the systems.
+	if err := w.recomputeDerivedComponents(wCtx); err != nil {
+		return err
+	}
+
+	if err := w.checkWatchRules(); err != nil {
+		return err
+	}
 
+	if err := w.emitStructuralEvents(); err != nil {
+		return err
+	}
+
+	if err := w.checkInvariants(true); err != nil {
+		return err
+	}
+
+	// The post-commit systems of the previous tick read the committed state, so it must not change before they finish.
+	w.waitForPostCommitSystems()
+
+	if err := w.refreshFingerprint(); err != nil {
+		return err
+	}
+	w.logTickEvents()
+	if err := w.savePersonaIndex(); err != nil {
+		return err
+	}
 	finalizeTickStartTime := time.Now()
 	if err := w.entityStore.FinalizeTick(ctx); err != nil {
 		return err
 	}
 	statsd.EmitTickStat(finalizeTickStartTime, "finalize")
+	if w.storageMetrics != nil {
+		w.storageMetrics.observe("finalize", nil, 0, w.CurrentTick(), finalizeTickStartTime)
+		w.storageMetrics.finish(w.CurrentTick())
+	}
+	w.personaIndex.commit(w.livePersonaIndex.entries)
+
+	if err := w.checkInvariants(false); err != nil {
+		return err
+	}
 
 	w.setEvmResults(txPool.GetEVMTxs())
+	w.rollback.record(w.CurrentTick(), timestamp, txPool)
+	if w.txLog != nil && !replay {
+		if err := w.txLog.write(w.CurrentTick(), timestamp, w.msgManager.GetRegisteredMessages(), txPool); err != nil {
+			return err
+		}
+	}
 
 	// Handle tx data blob submission
 	// Only submit transactions when the following criteria is satisfied:
 	// 1. The shard router is set
 	// 2. The world is not in the recovering stage (we don't want to resubmit past transactions)
-	if w.router != nil && w.worldStage.Current() != worldstage.Recovering {
+	// 3. The tick is not a replay of a tick that was already submitted
+	if w.router != nil && w.worldStage.Current() != worldstage.Recovering && !replay {
 		err := w.router.SubmitTxBlob(ctx, txPool.Transactions(), w.tick.Load(), w.timestamp.Load())
 		if err != nil {
 			return fmt.Errorf("failed to submit transactions to base shard: %w", err)
@@ -262,12 +546,23 @@ func (w *World) doTick(ctx context.Context, timestamp uint64) (err error) {
 	// Increment the tick
 	w.tick.Add(1)
 	w.receiptHistory.NextTick() // todo(scott): use channels
+	if w.worldStage.Current() == worldstage.Recovering {
+		w.recovery.progress(w.CurrentTick())
+	}
 
 	// Populate world.TickResults for the current tick and emit it as an Event
 	flushEventStart := time.Now()
 	w.populateAndBroadcastTickResults()
+	// Priority events are recorded ahead of the other events of the tick, in the order subscribers receive them.
+	w.eventHistory.Add(w.tickResults.Tick, append(slices.Clone(w.tickResults.PriorityEvents), w.tickResults.Events...))
 	statsd.EmitTickStat(flushEventStart, "flush_events")
 
+	if callHooks {
+		w.callTickEndHooks(*w.tickResults)
+		w.sendReceiptWebhooks(txPool, w.tickResults.Tick, w.tickResults.Receipts)
+		w.startPostCommitSystems()
+	}
+
 	// Clear the TickResults for this tick in preparation for the next Tick
 	w.tickResults.Clear()
 
@@ -291,6 +586,16 @@ func (w *World) StartGame() error {
 		return errors.New("game has already been started")
 	}
 
+	if w.chaos != nil {
+		w.chaos.install(w)
+	}
+	if err := w.validateModuleAccess(); err != nil {
+		return err
+	}
+	if err := w.validateSystemAccess(); err != nil {
+		return err
+	}
+
 	// TODO(scott): entityStore.RegisterComponents is ambiguous with cardinal.RegisterComponent.
 	//  We should probably rename this to LoadComponents or osmething.
 	if err := w.entityStore.RegisterComponents(w.componentManager.GetComponents()); err != nil {
@@ -300,6 +605,10 @@ func (w *World) StartGame() error {
 		}
 		return err
 	}
+	// Refuse to resume from state that was produced by incompatible code, before any tick is run.
+	if err := w.pinFingerprint(); err != nil {
+		return err
+	}
 
 	// Start router if it is set
 	if w.router != nil {
@@ -324,6 +633,12 @@ func (w *World) StartGame() error {
 			return eris.Wrap(err, "failed to recover from chain")
 		}
 	}
+
+	// Requeue transactions that were accepted, but not executed before the world was last stopped.
+	if err := w.requeueTransactions(); err != nil {
+		return eris.Wrap(err, "failed to requeue transactions")
+	}
+	w.recovery.finish(w.CurrentTick())
 	w.worldStage.Store(worldstage.Ready)
 
 	// TODO(scott): i find this manual tracking and incrementing of the tick very footgunny. Why can't we just
@@ -340,6 +655,8 @@ func (w *World) StartGame() error {
 	if err != nil {
 		return err
 	}
+	w.broadcaster = startBroadcaster(w.server)
+	w.startWebhooks()
 
 	// Warn when no components, messages, queries, or systems are registered
 	if len(w.componentManager.GetComponents()) == 0 {
@@ -400,6 +717,7 @@ func (w *World) startGameLoop(ctx context.Context, tickStart <-chan time.Time, t
 			case <-w.worldStage.NotifyOnStage(worldstage.ShuttingDown):
 				w.drainChannelsWaitingForNextTick()
 				closeAllChannels(waitingChs)
+				w.drainTxQueue()
 				if w.txPool.GetAmountOfTxs() > 0 {
 					// immediately tick if pool is not empty to process all txs if queue is not empty.
 					w.tickTheEngine(ctx, tickDone)
@@ -421,7 +739,7 @@ func (w *World) tickTheEngine(ctx context.Context, tickDone chan<- uint64) {
 	// this is the final point where errors bubble up and hit a panic. There are other places where this occurs
 	// but this is the highest terminal point.
 	// the panic may point you to here, (or the tick function) but the real stack trace is in the error message.
-	err := w.doTick(ctx, uint64(time.Now().Unix()))
+	err := w.doTick(ctx, uint64(w.timeSource.Now().Unix()))
 	if err != nil {
 		bytes, errMarshal := json.Marshal(eris.ToJSON(err, true))
 		if errMarshal != nil {
@@ -455,6 +773,11 @@ func (w *World) Shutdown() error {
 
 	// Block until the world has stopped ticking
 	<-w.worldStage.NotifyOnStage(worldstage.ShutDown)
+	w.waitForPostCommitSystems()
+	if w.broadcaster != nil {
+		w.broadcaster.stop()
+	}
+	w.stopWebhooks()
 
 	if w.server != nil {
 		if err := w.server.Shutdown(); err != nil {
@@ -530,11 +853,7 @@ func (w *World) drainChannelsWaitingForNextTick() {
 func (w *World) AddTransaction(id types.MessageID, v any, sig *sign.Transaction) (
 	tick uint64, txHash types.TxHash,
 ) {
-	// TODO: There's no locking between getting the tick and adding the transaction, so there's no guarantee that this
-	// transaction is actually added to the returned tick.
-	tick = w.CurrentTick()
-	txHash = w.txPool.AddTransaction(id, v, sig)
-	return tick, txHash
+	return w.addTransaction(txpool.TxData{MsgID: id, Msg: v, Tx: sig})
 }
 
 func (w *World) AddEVMTransaction(
@@ -545,9 +864,91 @@ func (w *World) AddEVMTransaction(
 ) (
 	tick uint64, txHash types.TxHash,
 ) {
+	return w.addTransaction(txpool.TxData{MsgID: id, Msg: v, Tx: sig, EVMSourceTxHash: evmTxHash})
+}
+
+// addTransaction hands a transaction off to the next tick. While the game is running, the transaction is also
+// persisted to the transaction queue so that it survives a crash that happens before it is executed. It blocks while
+// the queue is full, so it must not be called by the goroutine that ticks; see addTransactionToPool.
+func (w *World) addTransaction(txData txpool.TxData) (tick uint64, txHash types.TxHash) {
+	txData.TxHash = types.TxHash(txData.Tx.HashHex())
+	if !w.reserveTxQueueSlot(txData) {
+		return w.CurrentTick(), txData.TxHash
+	}
+
+	w.txQueueMu.Lock()
+	// TODO: There's no locking between getting the tick and adding the transaction, so there's no guarantee that this
+	// transaction is actually added to the returned tick.
 	tick = w.CurrentTick()
-	txHash = w.txPool.AddEVMTransaction(id, v, sig, evmTxHash)
-	return tick, txHash
+	w.txBatch = append(w.txBatch, txData)
+	w.txQueueMu.Unlock()
+
+	w.flushTxBatch()
+	return tick, txData.TxHash
+}
+
+// flushTxBatch persists the accepted transactions that are waiting in txBatch and hands them off to txQueue, in the
+// order they were accepted. Transactions that are accepted while a batch is being persisted wait for the next
+// flush, which takes all of them at once. By the time flushTxBatch returns, every transaction that was accepted
+// before it was called has been handed off.
+func (w *World) flushTxBatch() {
+	w.txFlushMu.Lock()
+	defer w.txFlushMu.Unlock()
+
+	w.txQueueMu.Lock()
+	batch := w.txBatch
+	w.txBatch = nil
+	w.txQueueMu.Unlock()
+
+	for _, txData := range batch {
+		w.persistTransaction(txData)
+	}
+	for _, txData := range batch {
+		// A slot was reserved for every transaction in the batch, so this never blocks.
+		w.txQueue <- txData
+	}
+}
+
+// addTransactionToPool adds a transaction straight to the pool of the next tick, without waiting for room in the
+// queue. It is used by the code that runs on the goroutine that ticks, such as systems and recovery, which would
+// otherwise wait for a tick that only they can run.
+func (w *World) addTransactionToPool(txData txpool.TxData) (tick uint64, txHash types.TxHash) {
+	txData.TxHash = types.TxHash(txData.Tx.HashHex())
+	w.txFlushMu.Lock()
+	tick = w.CurrentTick()
+	w.persistTransaction(txData)
+	w.txFlushMu.Unlock()
+	w.txPool.Add(txData)
+	return tick, txData.TxHash
+}
+
+// persistTransaction saves the transaction to the transaction queue while the game is running. w.txFlushMu must be
+// held.
+func (w *World) persistTransaction(txData txpool.TxData) {
+	stage := w.worldStage.Current()
+	if msg, ok := w.GetMessageByID(txData.MsgID); ok && (stage == worldstage.Running || stage == worldstage.ShuttingDown) {
+		if err := w.entityStore.QueueTransaction(msg, txData); err != nil {
+			log.Err(err).Msgf("failed to persist transaction %s to the transaction queue", txData.TxHash)
+		}
+	}
+}
+
+// requeueTransactions adds the transactions that were accepted, but never executed before the world was last
+// stopped, back to the transaction pool.
+func (w *World) requeueTransactions() error {
+	queued, err := w.entityStore.RecoverQueuedTransactions(w.msgManager.GetRegisteredMessages())
+	if err != nil {
+		return err
+	}
+	for _, txs := range queued.Transactions() {
+		for _, tx := range txs {
+			w.txPool.Add(tx)
+		}
+	}
+	if n := queued.GetAmountOfTxs(); n > 0 {
+		log.Info().Msgf("Requeued %d transactions that were accepted before the last shutdown", n)
+	}
+	return nil
 }
 
 func (w *World) UseNonce(signerAddress string, nonce uint64) error {
@@ -579,7 +980,7 @@ func (w *World) HandleEVMQuery(name string, abiRequest []byte) ([]byte, error) {
 	}
 	req, err := qry.DecodeEVMRequest(abiRequest)
 	if err != nil {
-		return nil, err
+		return nil, eris.Wrapf(err, "query %q", name)
 	}
 
 	reply, err := qry.HandleQuery(NewReadOnlyWorldContext(w), req)
@@ -614,10 +1015,18 @@ func (w *World) GetRegisteredSystemNames() []string {
 func (w *World) GetReadOnlyCtx() engine.Context {
 	return NewReadOnlyWorldContext(w)
 }
+
+// GetQueryByName returns the query with the given name. The name may be qualified with its group, as in
+// "guild.roster"; a bare name refers to the query in the default group, or to the only query with that name.
 func (w *World) GetQueryByName(name string) (engine.Query, error) {
 	return w.queryManager.GetQueryByName(name)
 }
 
+// GetQuery returns the query with the given name in the given group.
+func (w *World) GetQuery(group, name string) (engine.Query, error) {
+	return w.queryManager.GetQuery(group, name)
+}
+
 func (w *World) GetMessageByID(id types.MessageID) (types.Message, bool) {
 	msg := w.msgManager.GetMessageByID(id)
 	return msg, msg != nil
@@ -631,6 +1040,16 @@ func (w *World) GetComponentByName(name string) (types.ComponentMetadata, error)
 	return w.componentManager.GetComponentByName(name)
 }
 
+// RecoveryStatus returns the progress of the world's startup recovery.
+func (w *World) RecoveryStatus() servertypes.RecoveryStatus {
+	return w.recovery.status()
+}
+
+// QueryEvents returns the most recently emitted events that match the given filter.
+func (w *World) QueryEvents(filter events.Filter) []events.Entry {
+	return w.eventHistory.Query(filter)
+}
+
 func (w *World) populateAndBroadcastTickResults() {
 	receipts, err := w.receiptHistory.GetReceiptsForTick(w.CurrentTick() - 1)
 	if err != nil {
@@ -639,9 +1058,9 @@ func (w *World) populateAndBroadcastTickResults() {
 	w.tickResults.SetReceipts(receipts)
 	w.tickResults.SetTick(w.CurrentTick() - 1)
 
-	// Broadcast the tick results to all clients
-	err = w.server.BroadcastEvent(w.tickResults)
-	if err != nil {
-		log.Err(err).Msgf("failed to broadcast tick results")
+	// Broadcast a snapshot of the tick results to all clients. TickResults.Clear replaces, rather than truncates,
+	// its slices, so the snapshot isn't changed by later ticks.
+	if w.broadcaster != nil {
+		w.broadcaster.publish(*w.tickResults)
 	}
 }
@@ -0,0 +1,226 @@
+package cardinal
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// ErrComponentAccessDenied is returned when a module's system or tx middleware uses a component that its access policy
+// doesn't allow.
+var ErrComponentAccessDenied = errors.New("module is not allowed to access component")
+
+// ComponentAccess is the access policy of a module: the components, by name, that its systems and tx middleware may
+// use besides the module's own components, which they may always read and write. Components that the module may write
+// may also be read.
+type ComponentAccess struct {
+	Read  []string
+	Write []string
+}
+
+// AccessControlledModule is a Module that declares the components it uses. Once the module is in use, its systems and
+// tx middleware can't use other components, so that a third-party module can't change components such as the
+// SignerComponent of personas. Operators can impose a policy on modules that don't declare one; see WithModuleAccess.
+type AccessControlledModule interface {
+	Module
+	ComponentAccess() ComponentAccess
+}
+
+func (a ComponentAccess) canRead(component string) bool {
+	return slices.Contains(a.Read, component) || a.canWrite(component)
+}
+
+func (a ComponentAccess) canWrite(component string) bool {
+	return slices.Contains(a.Write, component)
+}
+
+// moduleAccess enforces the access policies of the modules while their systems and tx middleware run.
+type moduleAccess struct {
+	// imposed are the policies of WithModuleAccess, which take precedence over the ones that modules declare.
+	imposed  map[string]ComponentAccess
+	declared map[string]ComponentAccess
+	// owners maps each component name to the module that registered it; see World.componentOwners.
+	owners func() map[string]string
+
+	mu sync.Mutex
+	// active is the module whose code is running, if any.
+	active   string
+	isActive bool
+}
+
+func newModuleAccess(owners func() map[string]string) *moduleAccess {
+	return &moduleAccess{
+		imposed:  map[string]ComponentAccess{},
+		declared: map[string]ComponentAccess{},
+		owners:   owners,
+	}
+}
+
+// enableModuleAccess makes the world enforce the access policies of its modules, and returns the enforcer.
+func (w *World) enableModuleAccess() *moduleAccess {
+	if w.access == nil {
+		w.access = newModuleAccess(func() map[string]string { return w.componentOwners })
+		w.systemManager.SetObserver(w.observeSystem)
+	}
+	return w.access
+}
+
+// policy returns the access policy of the module, and false if the module is not restricted.
+func (a *moduleAccess) policy(module string) (ComponentAccess, bool) {
+	if policy, ok := a.imposed[module]; ok {
+		return policy, true
+	}
+	policy, ok := a.declared[module]
+	return policy, ok
+}
+
+// start restricts the storage operations to the module's policy until the returned function is called. It is a no-op
+// if access control is disabled.
+func (a *moduleAccess) start(module string) func() {
+	if a == nil {
+		return func() {}
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.active, a.isActive = module, true
+	return func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.active, a.isActive = "", false
+	}
+}
+
+// restricted reports whether the running code is restricted by an access policy.
+func (a *moduleAccess) restricted() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.isActive || a.active == "" {
+		return false
+	}
+	_, ok := a.policy(a.active)
+	return ok
+}
+
+// check returns ErrComponentAccessDenied if the running module may not read, or write, the component.
+func (a *moduleAccess) check(cType types.ComponentMetadata, write bool) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.isActive || a.active == "" {
+		return nil
+	}
+	policy, ok := a.policy(a.active)
+	if !ok || a.owners()[cType.Name()] == a.active {
+		return nil
+	}
+	if write && !policy.canWrite(cType.Name()) {
+		return eris.Wrapf(ErrComponentAccessDenied, "module %q may not write component %q", a.active, cType.Name())
+	}
+	if !write && !policy.canRead(cType.Name()) {
+		return eris.Wrapf(ErrComponentAccessDenied, "module %q may not read component %q", a.active, cType.Name())
+	}
+	return nil
+}
+
+// validateModuleAccess checks that the policies only name modules that are in use and components that are registered.
+func (w *World) validateModuleAccess() error {
+	if w.access == nil {
+		return nil
+	}
+	for _, policies := range []map[string]ComponentAccess{w.access.imposed, w.access.declared} {
+		for module, policy := range policies {
+			if !w.isModuleKnown(module) {
+				return eris.Errorf("component access policy names module %q, which is not in use", module)
+			}
+			for _, name := range slices.Concat(policy.Read, policy.Write) {
+				if _, err := w.GetComponentByName(name); err != nil {
+					return eris.Errorf("component access policy of module %q names unknown component %q", module, name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// accessStore denies the storage operations of the running module that its access policy doesn't allow.
+type accessStore struct {
+	gamestate.Manager
+	access *moduleAccess
+}
+
+func (s *accessStore) checkAll(comps []types.ComponentMetadata) error {
+	for _, cType := range comps {
+		if err := s.access.check(cType, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *accessStore) GetComponentForEntity(cType types.ComponentMetadata, id types.EntityID) (any, error) {
+	if err := s.access.check(cType, false); err != nil {
+		return nil, err
+	}
+	return s.Manager.GetComponentForEntity(cType, id)
+}
+
+func (s *accessStore) GetComponentForEntityInRawJSON(
+	cType types.ComponentMetadata, id types.EntityID,
+) (json.RawMessage, error) {
+	if err := s.access.check(cType, false); err != nil {
+		return nil, err
+	}
+	return s.Manager.GetComponentForEntityInRawJSON(cType, id)
+}
+
+func (s *accessStore) CreateEntity(comps ...types.ComponentMetadata) (types.EntityID, error) {
+	if err := s.checkAll(comps); err != nil {
+		return 0, err
+	}
+	return s.Manager.CreateEntity(comps...)
+}
+
+func (s *accessStore) CreateManyEntities(num int, comps ...types.ComponentMetadata) ([]types.EntityID, error) {
+	if err := s.checkAll(comps); err != nil {
+		return nil, err
+	}
+	return s.Manager.CreateManyEntities(num, comps...)
+}
+
+func (s *accessStore) SetComponentForEntity(cType types.ComponentMetadata, id types.EntityID, value any) error {
+	if err := s.access.check(cType, true); err != nil {
+		return err
+	}
+	return s.Manager.SetComponentForEntity(cType, id, value)
+}
+
+func (s *accessStore) AddComponentToEntity(cType types.ComponentMetadata, id types.EntityID) error {
+	if err := s.access.check(cType, true); err != nil {
+		return err
+	}
+	return s.Manager.AddComponentToEntity(cType, id)
+}
+
+func (s *accessStore) RemoveComponentFromEntity(cType types.ComponentMetadata, id types.EntityID) error {
+	if err := s.access.check(cType, true); err != nil {
+		return err
+	}
+	return s.Manager.RemoveComponentFromEntity(cType, id)
+}
+
+// RemoveEntity removes the entity only if the module may write all of its components.
+func (s *accessStore) RemoveEntity(id types.EntityID) error {
+	comps, err := s.Manager.GetComponentTypesForEntity(id)
+	if err != nil {
+		return err
+	}
+	if err = s.checkAll(comps); err != nil {
+		return err
+	}
+	return s.Manager.RemoveEntity(id)
+}
@@ -0,0 +1,103 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type Vault struct {
+	Gold int
+}
+
+func (Vault) Name() string { return "vault" }
+
+type Badge struct {
+	Label string
+}
+
+func (Badge) Name() string { return "badge" }
+
+// badgeModule is a module that hands out badges, and tries to help itself to the game's vaults.
+type badgeModule struct {
+	cardinal.ModuleBase
+	access   *cardinal.ComponentAccess
+	vault    types.EntityID
+	readErr  error
+	writeErr error
+}
+
+func (*badgeModule) Name() string    { return "badges" }
+func (*badgeModule) Version() string { return "v0.1.0" }
+
+func (*badgeModule) RegisterComponents(w *cardinal.World) error {
+	return cardinal.RegisterComponent[Badge](w)
+}
+
+func (m *badgeModule) RegisterSystems(w *cardinal.World) error {
+	return cardinal.RegisterSystems(w, func(wCtx engine.Context) error {
+		if _, err := cardinal.Create(wCtx, Badge{Label: "rich"}); err != nil {
+			return err
+		}
+		_, m.readErr = cardinal.GetComponent[Vault](wCtx, m.vault)
+		m.writeErr = cardinal.SetComponent[Vault](wCtx, m.vault, &Vault{Gold: 0})
+		return nil
+	})
+}
+
+// accessControlledBadgeModule is the badge module with a declared access policy.
+type accessControlledBadgeModule struct {
+	*badgeModule
+}
+
+func (m accessControlledBadgeModule) ComponentAccess() cardinal.ComponentAccess {
+	return *m.access
+}
+
+// newVaultWorld returns a world that uses the module, and whose game creates a vault for the badge module to raid.
+func newVaultWorld(
+	t *testing.T, m cardinal.Module, badges *badgeModule, opts ...cardinal.WorldOption,
+) *testutils.TestFixture {
+	tf := testutils.NewTestFixture(t, nil, opts...)
+	assert.NilError(t, cardinal.RegisterComponent[Vault](tf.World))
+	assert.NilError(t, cardinal.RegisterInitSystems(tf.World, func(wCtx engine.Context) error {
+		var err error
+		badges.vault, err = cardinal.Create(wCtx, Vault{Gold: 100})
+		return err
+	}))
+	assert.NilError(t, tf.World.UseModule(m))
+	return tf
+}
+
+func TestModuleAccessPolicyIsEnforced(t *testing.T) {
+	m := &badgeModule{access: &cardinal.ComponentAccess{Read: []string{"vault"}}}
+	tf := newVaultWorld(t, accessControlledBadgeModule{m}, m)
+	tf.DoTick()
+
+	// The module may use its own components, and read the vault, but not write it.
+	assert.NilError(t, m.readErr)
+	assert.ErrorIs(t, m.writeErr, cardinal.ErrComponentAccessDenied)
+	vault, err := cardinal.GetComponent[Vault](cardinal.NewReadOnlyWorldContext(tf.World), m.vault)
+	assert.NilError(t, err)
+	assert.Equal(t, 100, vault.Gold)
+}
+
+func TestModuleAccessCanBeImposed(t *testing.T) {
+	m := &badgeModule{}
+	tf := newVaultWorld(t, m, m, cardinal.WithModuleAccess(map[string]cardinal.ComponentAccess{"badges": {}}))
+	tf.DoTick()
+
+	assert.ErrorIs(t, m.readErr, cardinal.ErrComponentAccessDenied)
+	assert.ErrorIs(t, m.writeErr, cardinal.ErrComponentAccessDenied)
+}
+
+func TestModuleAccessPolicyMustNameRegisteredComponents(t *testing.T) {
+	m := &badgeModule{access: &cardinal.ComponentAccess{Write: []string{"treasury"}}}
+	tf := newVaultWorld(t, accessControlledBadgeModule{m}, m)
+	err := tf.World.StartGame()
+	assert.ErrorContains(t, err, `unknown component "treasury"`)
+}
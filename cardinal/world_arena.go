@@ -0,0 +1,123 @@
+package cardinal
+
+import (
+	"reflect"
+	"sync"
+)
+
+// arenaChunkSize is the size of the chunks that the tick arena allocates byte buffers from. Larger buffers are
+// allocated normally.
+const arenaChunkSize = 64 << 10
+
+// TickArena hands out temporary buffers that are only valid until the end of the tick they were allocated in. The
+// arena is reset when the tick ends, after which its memory is reused by the following ticks, so hot systems that need
+// scratch space every tick don't allocate it anew and leave it to the garbage collector.
+//
+// Buffers must not be kept, or shared with anything that keeps them, past the end of the tick; this includes the
+// post-commit systems, which run after the arena is reset. The arena is safe for concurrent use.
+type TickArena struct {
+	mu sync.Mutex
+
+	chunks [][]byte
+	// chunk is the index of the chunk that buffers are allocated from, and offset is where its free space starts.
+	chunk  int
+	offset int
+
+	typed map[reflect.Type]arenaPool
+}
+
+// arenaPool is the part of the arena that allocates slices of a single type.
+type arenaPool interface {
+	reset()
+}
+
+func newTickArena() *TickArena {
+	return &TickArena{typed: map[reflect.Type]arenaPool{}}
+}
+
+// TickArena returns the world's tick arena.
+func (w *World) TickArena() *TickArena {
+	return w.arena
+}
+
+// Alloc returns a zeroed buffer of n bytes that is valid until the end of the tick.
+func (a *TickArena) Alloc(n int) []byte {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if n > arenaChunkSize {
+		return make([]byte, n)
+	}
+	for a.chunk < len(a.chunks) && a.offset+n > len(a.chunks[a.chunk]) {
+		a.chunk++
+		a.offset = 0
+	}
+	if a.chunk == len(a.chunks) {
+		a.chunks = append(a.chunks, make([]byte, arenaChunkSize))
+		a.offset = 0
+	}
+	buf := a.chunks[a.chunk][a.offset : a.offset+n : a.offset+n]
+	a.offset += n
+	clear(buf)
+	return buf
+}
+
+// ArenaSlice returns a zeroed slice of n values of T that is valid until the end of the tick. Values that hold
+// pointers are cleared when the arena is reset, so they don't keep what they point to alive.
+func ArenaSlice[T any](a *TickArena, n int) []T {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	typ := reflect.TypeFor[T]()
+	pool, ok := a.typed[typ].(*typedArenaPool[T])
+	if !ok {
+		pool = &typedArenaPool[T]{}
+		a.typed[typ] = pool
+	}
+	return pool.alloc(n)
+}
+
+// reset makes the arena's memory available again. Chunks that weren't needed in the tick are released, so that a
+// single busy tick doesn't hold on to its memory forever.
+func (a *TickArena) reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if used := min(a.chunk+1, len(a.chunks)); used < len(a.chunks) {
+		clear(a.chunks[used:])
+		a.chunks = a.chunks[:used]
+	}
+	a.chunk = 0
+	a.offset = 0
+	for _, pool := range a.typed {
+		pool.reset()
+	}
+}
+
+// typedArenaPool allocates slices of T from a single backing slice, which grows to fit the largest tick.
+type typedArenaPool[T any] struct {
+	buf  []T
+	used int
+	// overflow are the slices that didn't fit in buf. They are released at the reset, and buf grows to fit them.
+	overflow int
+}
+
+func (p *typedArenaPool[T]) alloc(n int) []T {
+	if p.used+n > len(p.buf) {
+		p.overflow += n
+		return make([]T, n)
+	}
+	s := p.buf[p.used : p.used+n : p.used+n]
+	p.used += n
+	clear(s)
+	return s
+}
+
+func (p *typedArenaPool[T]) reset() {
+	clear(p.buf[:p.used])
+	if p.overflow > 0 {
+		p.buf = make([]T, p.used+p.overflow)
+	}
+	p.used = 0
+	p.overflow = 0
+}
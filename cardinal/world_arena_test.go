@@ -0,0 +1,37 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func TestTickArenaIsReusedAcrossTicks(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+
+	var bufs [][]byte
+	var slices [][]int
+	assert.NilError(t, cardinal.RegisterSystems(world, func(engine.Context) error {
+		buf := world.TickArena().Alloc(16)
+		ints := cardinal.ArenaSlice[int](world.TickArena(), 4)
+		// Buffers start out zeroed, even though the previous tick wrote to them.
+		assert.DeepEqual(t, buf, make([]byte, 16))
+		assert.DeepEqual(t, ints, make([]int, 4))
+		buf[0], ints[0] = 1, 1
+		bufs = append(bufs, buf)
+		slices = append(slices, ints)
+		return nil
+	}))
+	for i := 0; i < 3; i++ {
+		tf.DoTick()
+	}
+
+	assert.Equal(t, &bufs[0][0], &bufs[1][0])
+	assert.Equal(t, &bufs[1][0], &bufs[2][0])
+	// The typed pool grows to fit the first tick, and is reused from then on.
+	assert.Equal(t, &slices[1][0], &slices[2][0])
+}
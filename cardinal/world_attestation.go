@@ -0,0 +1,54 @@
+package cardinal
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/attestation"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// EventAttestationField is the event field that holds the attestation of an event emitted with EmitAttestedEvent.
+const EventAttestationField = "attestation"
+
+var ErrNoAttestationKey = errors.New("world has no attestation key; see WithAttestationKey")
+
+// AttestationSigner returns the address of the key that the world signs attested events with, or "" if the world has
+// no attestation key. Services that trust the world's attestations should get the address from the game's operator,
+// rather than from the attestations themselves.
+func (w *World) AttestationSigner() string {
+	if w.attestationKey == nil {
+		return ""
+	}
+	return crypto.PubkeyToAddress(w.attestationKey.PublicKey).Hex()
+}
+
+// EmitAttestedEvent emits an event that is signed with the world's attestation key, for outcomes such as tournament
+// results or rare drops that other services need to trust without trusting how the event reached them. The emitted
+// event is the given event with the attestation added in the EventAttestationField field. The attestation's payload
+// is the JSON encoded event, without the attestation, so the attestation can be verified on its own.
+func EmitAttestedEvent(wCtx engine.Context, event map[string]any) (attestation.Attestation, error) {
+	if _, ok := event[EventAttestationField]; ok {
+		return attestation.Attestation{}, eris.Errorf("attested events can't have a %q field", EventAttestationField)
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return attestation.Attestation{}, eris.Wrap(err, "failed to encode attested event")
+	}
+	a, err := wCtx.Attest(payload)
+	if err != nil {
+		return attestation.Attestation{}, err
+	}
+	attested := make(map[string]any, len(event)+1)
+	for k, v := range event {
+		attested[k] = v
+	}
+	attested[EventAttestationField] = a
+	if err := wCtx.EmitEvent(attested); err != nil {
+		return attestation.Attestation{}, err
+	}
+	return a, nil
+}
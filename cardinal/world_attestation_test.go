@@ -0,0 +1,69 @@
+package cardinal_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/attestation"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func TestAttestedEventsCanBeVerified(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithAttestationKey(key))
+	world := tf.World
+
+	var emitted []byte
+	var attestedTick uint64
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		if wCtx.CurrentTick() != 1 {
+			return nil
+		}
+		attestedTick = wCtx.CurrentTick()
+		_, err := cardinal.EmitAttestedEvent(wCtx, map[string]any{"type": "tournament-result", "winner": "alice"})
+		if err != nil {
+			return err
+		}
+		events := wCtx.EmittedEvents()
+		emitted = events[len(events)-1]
+		return nil
+	}))
+	tf.DoTick()
+	tf.DoTick()
+
+	var event struct {
+		Type        string                  `json:"type"`
+		Attestation attestation.Attestation `json:"attestation"`
+	}
+	assert.NilError(t, json.Unmarshal(emitted, &event))
+	assert.Equal(t, event.Type, "tournament-result")
+
+	a := event.Attestation
+	assert.NilError(t, a.Verify(world.AttestationSigner()))
+	assert.Equal(t, a.Namespace, world.Namespace())
+	assert.Equal(t, a.Tick, attestedTick)
+	var payload map[string]any
+	assert.NilError(t, json.Unmarshal(a.Payload, &payload))
+	assert.DeepEqual(t, payload, map[string]any{"type": "tournament-result", "winner": "alice"})
+}
+
+func TestAttestedEventsNeedAKey(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.Equal(t, world.AttestationSigner(), "")
+
+	var attestErr error
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		_, attestErr = cardinal.EmitAttestedEvent(wCtx, map[string]any{"winner": "alice"})
+		return nil
+	}))
+	tf.DoTick()
+	assert.Check(t, eris.Is(attestErr, cardinal.ErrNoAttestationKey))
+}
@@ -0,0 +1,226 @@
+package cardinal
+
+import (
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/statsd"
+	"pkg.world.dev/world-engine/cardinal/system"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// ModuleBudget is how much of every tick a module may use. Modules that exceed their budget are not stopped, but they
+// are logged and reported by World.ModuleUsage, so that operators who host third-party modules can find the expensive
+// ones. Zero values are unlimited.
+type ModuleBudget struct {
+	// TickTime is the time that the module's systems and tx middleware may take.
+	TickTime time.Duration
+	// StorageOps is the number of components and entities that the module's systems and tx middleware may read, add,
+	// set, or remove.
+	StorageOps int
+}
+
+// ModuleUsage is how much of a tick a module used.
+type ModuleUsage struct {
+	Tick uint64 `json:"tick"`
+	// Module is the name of the module, or "" for the systems and tx middleware of the game itself.
+	Module     string        `json:"module"`
+	TickTime   time.Duration `json:"tickTime"`
+	StorageOps int           `json:"storageOps"`
+	// OverBudget is set if the module exceeded its budget.
+	OverBudget bool `json:"overBudget"`
+}
+
+// ModuleUsage returns the usage of every module that ran a system or tx middleware in the most recent tick, sorted by
+// module name, if module budgets are enabled; see WithModuleBudgets.
+func (w *World) ModuleUsage() []ModuleUsage {
+	if w.budgets == nil {
+		return nil
+	}
+	w.budgets.mu.Lock()
+	defer w.budgets.mu.Unlock()
+	return slices.Clone(w.budgets.report)
+}
+
+func (w *World) recordSystemOwners(systems []system.System) {
+	if w.registeringModule == "" {
+		return
+	}
+	if w.systemOwners == nil {
+		w.systemOwners = map[string]string{}
+	}
+	for _, sys := range systems {
+		w.systemOwners[system.Name(sys)] = w.registeringModule
+	}
+}
+
+// moduleAccounting attributes the time and the storage operations of a tick to the modules whose systems and tx
+// middleware use them.
+type moduleAccounting struct {
+	budgets map[string]ModuleBudget
+
+	mu sync.Mutex
+	// active is the module whose code is running, if any. The game itself is active as "".
+	active    string
+	isActive  bool
+	started   time.Time
+	usage     map[string]*ModuleUsage
+	usedOrder []string
+	report    []ModuleUsage
+}
+
+func newModuleAccounting(budgets map[string]ModuleBudget) *moduleAccounting {
+	return &moduleAccounting{budgets: budgets, usage: map[string]*ModuleUsage{}}
+}
+
+// start attributes the time and storage operations to the module until the returned function is called. It is a
+// no-op if accounting is disabled.
+func (a *moduleAccounting) start(module string) func() {
+	if a == nil {
+		return func() {}
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.active, a.isActive, a.started = module, true, time.Now()
+	return func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.use(module).TickTime += time.Since(a.started)
+		a.active, a.isActive = "", false
+	}
+}
+
+// use returns the usage of the module in the current tick. The caller must hold the mutex.
+func (a *moduleAccounting) use(module string) *ModuleUsage {
+	u, ok := a.usage[module]
+	if !ok {
+		u = &ModuleUsage{Module: module}
+		a.usage[module] = u
+		a.usedOrder = append(a.usedOrder, module)
+	}
+	return u
+}
+
+func (a *moduleAccounting) running() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.isActive
+}
+
+func (a *moduleAccounting) countStorageOps(n int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.isActive {
+		a.use(a.active).StorageOps += n
+	}
+}
+
+// finish reports the usage of the tick, warns about the modules that exceeded their budgets, and starts over.
+func (a *moduleAccounting) finish(tick uint64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	slices.Sort(a.usedOrder)
+	a.report = make([]ModuleUsage, 0, len(a.usedOrder))
+	for _, module := range a.usedOrder {
+		u := *a.usage[module]
+		u.Tick = tick
+		budget := a.budgets[module]
+		u.OverBudget = (budget.TickTime > 0 && u.TickTime > budget.TickTime) ||
+			(budget.StorageOps > 0 && u.StorageOps > budget.StorageOps)
+		a.report = append(a.report, u)
+
+		tags := []string{"module:" + moduleTag(module)}
+		if err := statsd.Client().Gauge("module.tick_time_ms", float64(u.TickTime.Milliseconds()), tags, 1); err != nil {
+			log.Warn().Msgf("failed to emit gauge stat:%v", err)
+		}
+		if err := statsd.Client().Gauge("module.storage_ops", float64(u.StorageOps), tags, 1); err != nil {
+			log.Warn().Msgf("failed to emit gauge stat:%v", err)
+		}
+		if u.OverBudget {
+			log.Warn().Uint64("tick", tick).Str("module", moduleTag(module)).
+				Dur("tick_time", u.TickTime).Dur("tick_time_budget", budget.TickTime).
+				Int("storage_ops", u.StorageOps).Int("storage_ops_budget", budget.StorageOps).
+				Msg("module exceeded its tick budget")
+		}
+	}
+	a.usage = map[string]*ModuleUsage{}
+	a.usedOrder = nil
+}
+
+// moduleTag names the module in logs and metrics.
+func moduleTag(module string) string {
+	if module == "" {
+		return "game"
+	}
+	return module
+}
+
+// observeSystem attributes the system's time to the module that registered it, and holds the system to the module's
+// access policy.
+func (w *World) observeSystem(systemName string) func() {
+	module := w.systemOwners[systemName]
+	doneAccess := w.access.start(module)
+	doneBudget := w.budgets.start(module)
+	return func() {
+		doneBudget()
+		doneAccess()
+	}
+}
+
+// budgetStore counts the storage operations of the running module.
+type budgetStore struct {
+	gamestate.Manager
+	accounting *moduleAccounting
+}
+
+func (s *budgetStore) GetComponentForEntity(cType types.ComponentMetadata, id types.EntityID) (any, error) {
+	s.accounting.countStorageOps(1)
+	return s.Manager.GetComponentForEntity(cType, id)
+}
+
+func (s *budgetStore) GetComponentForEntityInRawJSON(
+	cType types.ComponentMetadata, id types.EntityID,
+) (json.RawMessage, error) {
+	s.accounting.countStorageOps(1)
+	return s.Manager.GetComponentForEntityInRawJSON(cType, id)
+}
+
+func (s *budgetStore) GetComponentTypesForEntity(id types.EntityID) ([]types.ComponentMetadata, error) {
+	s.accounting.countStorageOps(1)
+	return s.Manager.GetComponentTypesForEntity(id)
+}
+
+func (s *budgetStore) CreateEntity(comps ...types.ComponentMetadata) (types.EntityID, error) {
+	s.accounting.countStorageOps(1)
+	return s.Manager.CreateEntity(comps...)
+}
+
+func (s *budgetStore) CreateManyEntities(num int, comps ...types.ComponentMetadata) ([]types.EntityID, error) {
+	s.accounting.countStorageOps(num)
+	return s.Manager.CreateManyEntities(num, comps...)
+}
+
+func (s *budgetStore) SetComponentForEntity(cType types.ComponentMetadata, id types.EntityID, value any) error {
+	s.accounting.countStorageOps(1)
+	return s.Manager.SetComponentForEntity(cType, id, value)
+}
+
+func (s *budgetStore) AddComponentToEntity(cType types.ComponentMetadata, id types.EntityID) error {
+	s.accounting.countStorageOps(1)
+	return s.Manager.AddComponentToEntity(cType, id)
+}
+
+func (s *budgetStore) RemoveComponentFromEntity(cType types.ComponentMetadata, id types.EntityID) error {
+	s.accounting.countStorageOps(1)
+	return s.Manager.RemoveComponentFromEntity(cType, id)
+}
+
+func (s *budgetStore) RemoveEntity(id types.EntityID) error {
+	s.accounting.countStorageOps(1)
+	return s.Manager.RemoveEntity(id)
+}
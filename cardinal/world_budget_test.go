@@ -0,0 +1,86 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+)
+
+type HogState struct {
+	Value int
+}
+
+func (HogState) Name() string { return "hog_state" }
+
+// hogModule is a module whose system sets a component over and over.
+type hogModule struct {
+	cardinal.ModuleBase
+	writes int
+}
+
+func (*hogModule) Name() string    { return "hog" }
+func (*hogModule) Version() string { return "v0.0.1" }
+
+func (*hogModule) RegisterComponents(w *cardinal.World) error {
+	return cardinal.RegisterComponent[HogState](w)
+}
+
+func (m *hogModule) RegisterSystems(w *cardinal.World) error {
+	return cardinal.RegisterSystems(w, func(wCtx engine.Context) error {
+		id, err := cardinal.Create(wCtx, HogState{})
+		if err != nil {
+			return err
+		}
+		for i := range m.writes {
+			if err := cardinal.SetComponent[HogState](wCtx, id, &HogState{Value: i}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (*hogModule) Init(w *cardinal.World) error {
+	return cardinal.RegisterTxMiddleware(w, func(engine.Context, types.Message, txpool.TxData) error {
+		return nil
+	})
+}
+
+func TestModuleBudgets(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithModuleBudgets(map[string]cardinal.ModuleBudget{
+		"hog": {StorageOps: 10},
+		"":    {StorageOps: 1000},
+	}))
+	world := tf.World
+	assert.NilError(t, world.UseModule(&hogModule{writes: 20}))
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		_, err := cardinal.Create(wCtx, Health{Value: 1})
+		return err
+	}))
+	tf.DoTick()
+
+	usage := world.ModuleUsage()
+	assert.Equal(t, len(usage), 2)
+	game, hog := usage[0], usage[1]
+	assert.Equal(t, game.Module, "")
+	assert.Check(t, game.StorageOps > 0)
+	assert.Check(t, !game.OverBudget)
+	assert.Equal(t, hog.Module, "hog")
+	assert.Check(t, hog.StorageOps > 20)
+	assert.Check(t, hog.TickTime > 0)
+	assert.Check(t, hog.OverBudget)
+	assert.Equal(t, hog.Tick, game.Tick)
+}
+
+func TestModuleUsageIsNilWithoutBudgets(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, tf.World.UseModule(&hogModule{writes: 1}))
+	tf.DoTick()
+	assert.Check(t, tf.World.ModuleUsage() == nil)
+}
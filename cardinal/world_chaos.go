@@ -0,0 +1,169 @@
+package cardinal
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/router"
+	"pkg.world.dev/world-engine/cardinal/statsd"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+)
+
+// ErrChaos is the error of the failures that chaos mode injects.
+var ErrChaos = errors.New("failure injected by chaos mode")
+
+// ChaosConfig configures the failures that chaos mode injects; see WithChaos. Rates are probabilities from 0 to 1,
+// and zero values inject nothing.
+type ChaosConfig struct {
+	// Seed seeds the choice of the failures, so that a run can be repeated.
+	Seed int64
+
+	// StorageFailureRate is the rate of storage commands that fail at once.
+	StorageFailureRate float64
+	// StorageTimeoutRate is the rate of storage commands that time out: they fail after StorageTimeout, or when their
+	// context is done if that is sooner.
+	StorageTimeoutRate float64
+	StorageTimeout     time.Duration
+
+	// EventDropRate is the rate at which each websocket connection is closed when tick results are broadcast, as if
+	// the network dropped it.
+	EventDropRate float64
+
+	// BaseShardAckDelay delays every submission of transactions to the base shard.
+	BaseShardAckDelay time.Duration
+	// BaseShardFailureRate is the rate of submissions to the base shard that fail after the delay.
+	BaseShardFailureRate float64
+}
+
+// chaos injects the failures of a ChaosConfig.
+type chaos struct {
+	config ChaosConfig
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func newChaos(config ChaosConfig) *chaos {
+	return &chaos{config: config, rng: rand.New(rand.NewSource(config.Seed))} //nolint:gosec // Not for security.
+}
+
+// roll reports whether a failure with the given rate happens.
+func (c *chaos) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rng.Float64() < rate
+}
+
+// inject counts and returns an injected failure.
+func (c *chaos) inject(kind, msg string) error {
+	log.Debug().Str("kind", kind).Msg("chaos mode injected a failure")
+	if err := statsd.Client().Count("chaos.injected_failures", 1, []string{"kind:" + kind}, 1); err != nil {
+		log.Warn().Msgf("failed to emit count stat:%v", err)
+	}
+	return eris.Wrap(ErrChaos, msg)
+}
+
+// wait waits for the duration, or until the context is done.
+func wait(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return eris.Wrap(ctx.Err(), "")
+	}
+}
+
+// install injects failures into the world's storage and base shard router. Event connections are dropped by the
+// server, through the server option of WithChaos.
+func (c *chaos) install(w *World) {
+	log.Warn().Interface("config", c.config).Msg("chaos mode is on; failures are injected on purpose")
+	if w.redisStorage != nil && w.redisStorage.Client != nil {
+		w.redisStorage.Client.AddHook(chaosRedisHook{c})
+	}
+	if w.router != nil {
+		w.router = &chaosRouter{Router: w.router, chaos: c}
+	}
+}
+
+// storageFailure returns the failure of a storage command, if any.
+func (c *chaos) storageFailure(ctx context.Context) error {
+	if c.roll(c.config.StorageTimeoutRate) {
+		if err := wait(ctx, c.config.StorageTimeout); err != nil {
+			return err
+		}
+		return c.inject("storage_timeout", "storage command timed out")
+	}
+	if c.roll(c.config.StorageFailureRate) {
+		return c.inject("storage_failure", "storage command failed")
+	}
+	return nil
+}
+
+func (c *chaos) dropEventConnection() bool {
+	if !c.roll(c.config.EventDropRate) {
+		return false
+	}
+	_ = c.inject("event_connection_dropped", "event connection dropped")
+	return true
+}
+
+// chaosRedisHook injects failures into the commands of a Redis client.
+type chaosRedisHook struct {
+	*chaos
+}
+
+func (h chaosRedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h chaosRedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := h.storageFailure(ctx); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (h chaosRedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := h.storageFailure(ctx); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		return next(ctx, cmds)
+	}
+}
+
+// chaosRouter delays, and injects failures into, the submissions of transactions to the base shard.
+type chaosRouter struct {
+	router.Router
+	chaos *chaos
+}
+
+func (r *chaosRouter) SubmitTxBlob(ctx context.Context, txs txpool.TxMap, epoch, unixTimestamp uint64) error {
+	if r.chaos.config.BaseShardAckDelay > 0 {
+		if err := wait(ctx, r.chaos.config.BaseShardAckDelay); err != nil {
+			return err
+		}
+	}
+	if r.chaos.roll(r.chaos.config.BaseShardFailureRate) {
+		return r.chaos.inject("base_shard_failure", "base shard did not acknowledge the transactions")
+	}
+	return r.Router.SubmitTxBlob(ctx, txs, epoch, unixTimestamp)
+}
@@ -0,0 +1,60 @@
+package cardinal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"pkg.world.dev/world-engine/assert"
+)
+
+func newChaosClient(t *testing.T, config ChaosConfig) *redis.Client {
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	client.AddHook(chaosRedisHook{newChaos(config)})
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestChaosStorageFailures(t *testing.T) {
+	ctx := context.Background()
+
+	client := newChaosClient(t, ChaosConfig{})
+	assert.NilError(t, client.Set(ctx, "key", "value", 0).Err())
+
+	client = newChaosClient(t, ChaosConfig{StorageFailureRate: 1})
+	assert.ErrorIs(t, client.Set(ctx, "key", "value", 0).Err(), ErrChaos)
+	_, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, "key", "value", 0)
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrChaos)
+}
+
+func TestChaosStorageTimeouts(t *testing.T) {
+	ctx := context.Background()
+	client := newChaosClient(t, ChaosConfig{StorageTimeoutRate: 1, StorageTimeout: 20 * time.Millisecond})
+
+	start := time.Now()
+	assert.ErrorIs(t, client.Get(ctx, "key").Err(), ErrChaos)
+	assert.Check(t, time.Since(start) >= 20*time.Millisecond)
+
+	// A command whose context is done sooner fails with the context's error.
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, client.Get(ctx, "key").Err(), context.DeadlineExceeded)
+}
+
+func TestChaosIsRepeatable(t *testing.T) {
+	rolls := func() []bool {
+		c := newChaos(ChaosConfig{Seed: 42, EventDropRate: 0.5})
+		var dropped []bool
+		for range 20 {
+			dropped = append(dropped, c.dropEventConnection())
+		}
+		return dropped
+	}
+	assert.DeepEqual(t, rolls(), rolls())
+}
@@ -0,0 +1,342 @@
+package cardinal
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/iterators"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/rng"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/system"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+	"pkg.world.dev/world-engine/sign"
+)
+
+// RegisterSystemsWithAccess registers systems that only use the components of the given access, by name, which lets
+// consecutive systems whose accesses don't conflict run concurrently. Two accesses conflict if one of them writes a
+// component that the other reads or writes.
+//
+// Systems that run concurrently fail with ErrComponentAccessDenied when they read or write a component that their
+// access doesn't allow, and, like parallel message handlers, with ErrNotParallelSafe when they create or remove
+// entities, or add or remove components; see message.WithParallelExecution. Searches only see the components that
+// they may read. Their component writes, events, transaction results and transactions are applied in the order the
+// systems were registered in once all of them have run, so the outcome of a tick doesn't depend on how the systems
+// were scheduled. The components of the access are loaded from storage in batches before the systems run, rather
+// than one value at a time as the systems read them. Systems run one after another when module budgets or access
+// policies are enabled.
+func RegisterSystemsWithAccess(w *World, access ComponentAccess, sys ...system.System) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"engine state is %s, expected %s to register systems",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	if err := w.systemManager.RegisterSystemsWithAccess(system.Access(access), sys...); err != nil {
+		return err
+	}
+	w.recordSystemOwners(sys)
+	if w.systemAccess == nil {
+		w.systemAccess = map[string]ComponentAccess{}
+	}
+	for _, s := range sys {
+		w.systemAccess[system.Name(s)] = access
+	}
+	return nil
+}
+
+// validateSystemAccess checks that the accesses of systems only name components that are registered.
+func (w *World) validateSystemAccess() error {
+	for systemName, access := range w.systemAccess {
+		for _, name := range slices.Concat(access.Read, access.Write) {
+			if _, err := w.GetComponentByName(name); err != nil {
+				return eris.Errorf("component access of system %q names unknown component %q", systemName, name)
+			}
+		}
+	}
+	return nil
+}
+
+// prefetchComponents loads the values of the components of a declared access, by name, before the systems that
+// declared it run, in as few round trips to storage as possible.
+func (w *World) prefetchComponents(access system.Access) error {
+	var comps []types.ComponentMetadata
+	for _, name := range slices.Concat(access.Read, access.Write) {
+		comp, err := w.GetComponentByName(name)
+		if err != nil {
+			return err
+		}
+		if !slices.ContainsFunc(comps, func(c types.ComponentMetadata) bool { return c.ID() == comp.ID() }) {
+			comps = append(comps, comp)
+		}
+	}
+	return w.entityStore.Prefetch(comps)
+}
+
+// isolateSystems returns the contexts of systems that run concurrently. The systems share a store that serializes
+// their access to the world's state.
+func (w *World) isolateSystems(wCtx engine.Context, first int, systemNames []string) []system.IsolatedContext {
+	store := message.NewParallelStore(wCtx.StoreManager())
+	ctxs := make([]system.IsolatedContext, len(systemNames))
+	for i, systemName := range systemNames {
+		ctxs[i] = &isolatedContext{
+			Context: wCtx,
+			store:   &isolatedStore{Manager: store, system: systemName, access: w.systemAccess[systemName]},
+			logger:  *wCtx.Logger(),
+			rand:    w.newSystemRand(wCtx.CurrentTick(), first+i),
+		}
+	}
+	return ctxs
+}
+
+// isolatedContext is the context of a system that runs concurrently with other systems. It buffers the changes of
+// the system that other systems could observe in another order, such as its events, so that they can be applied in
+// the order the systems were registered in.
+type isolatedContext struct {
+	engine.Context
+	store   *isolatedStore
+	logger  zerolog.Logger
+	rand    *rng.RNG
+	changes []func() error
+}
+
+func (ctx *isolatedContext) buffer(change func() error) {
+	ctx.changes = append(ctx.changes, change)
+}
+
+// Flush applies the staged writes and the buffered changes to the context of the tick. Events beyond their topic's
+// budget are logged rather than returned as an error, like the bounded events of parallel message handlers.
+func (ctx *isolatedContext) Flush() error {
+	if err := ctx.store.flush(); err != nil {
+		return err
+	}
+	for _, change := range ctx.changes {
+		if err := change(); err != nil {
+			return err
+		}
+	}
+	ctx.changes = nil
+	return nil
+}
+
+// Unwrap returns the context of the tick.
+func (ctx *isolatedContext) Unwrap() engine.Context {
+	return ctx.Context
+}
+
+func (ctx *isolatedContext) Logger() *zerolog.Logger {
+	return &ctx.logger
+}
+
+func (ctx *isolatedContext) SetLogger(logger zerolog.Logger) {
+	ctx.logger = logger
+}
+
+// Rand returns the random number generator of the system's position in the tick, as if it ran on its own.
+func (ctx *isolatedContext) Rand() *rng.RNG {
+	return ctx.rand
+}
+
+func (ctx *isolatedContext) StoreManager() gamestate.Manager {
+	return ctx.store
+}
+
+func (ctx *isolatedContext) StoreReader() gamestate.Reader {
+	return ctx.store
+}
+
+func (ctx *isolatedContext) EmitEvent(event map[string]any) error {
+	ctx.buffer(func() error { return ctx.Context.EmitEvent(event) })
+	return nil
+}
+
+func (ctx *isolatedContext) EmitEventBounded(event map[string]any) error {
+	ctx.buffer(func() error {
+		if err := ctx.Context.EmitEventBounded(event); err != nil {
+			ctx.logger.Err(err).Msg("failed to emit event")
+		}
+		return nil
+	})
+	return nil
+}
+
+func (ctx *isolatedContext) EmitStringEvent(e string) error {
+	ctx.buffer(func() error { return ctx.Context.EmitStringEvent(e) })
+	return nil
+}
+
+func (ctx *isolatedContext) EmitPriorityEvent(event map[string]any) error {
+	ctx.buffer(func() error { return ctx.Context.EmitPriorityEvent(event) })
+	return nil
+}
+
+func (ctx *isolatedContext) AddMessageError(id types.TxHash, err error) {
+	ctx.buffer(func() error {
+		ctx.Context.AddMessageError(id, err)
+		return nil
+	})
+}
+
+func (ctx *isolatedContext) SetMessageResult(id types.TxHash, a any) {
+	ctx.buffer(func() error {
+		ctx.Context.SetMessageResult(id, a)
+		return nil
+	})
+}
+
+// AddTransaction queues the transaction for the next tick once the system's changes are applied.
+func (ctx *isolatedContext) AddTransaction(id types.MessageID, v any, sig *sign.Transaction) (uint64, types.TxHash) {
+	ctx.buffer(func() error {
+		ctx.Context.AddTransaction(id, v, sig)
+		return nil
+	})
+	return ctx.CurrentTick(), types.TxHash(sig.HashHex())
+}
+
+// isolatedStore holds a system that runs concurrently with other systems to its declared access. The components of
+// entities that the system may not read are hidden from it, and its writes are staged until its changes are applied,
+// so that the writes of a system that runs after a failed one are never applied.
+type isolatedStore struct {
+	gamestate.Manager
+	system string
+	access ComponentAccess
+
+	// staged are the values that the system set, in the order it first set them.
+	staged []stagedValue
+}
+
+type stagedValue struct {
+	cType types.ComponentMetadata
+	id    types.EntityID
+	value any
+}
+
+func (s *isolatedStore) check(cType types.ComponentMetadata, write bool) error {
+	if write && !s.access.canWrite(cType.Name()) {
+		return eris.Wrapf(ErrComponentAccessDenied, "system %q may not write component %q", s.system, cType.Name())
+	}
+	if !write && !s.access.canRead(cType.Name()) {
+		return eris.Wrapf(ErrComponentAccessDenied, "system %q may not read component %q", s.system, cType.Name())
+	}
+	return nil
+}
+
+// readable returns the components that the system may read.
+func (s *isolatedStore) readable(comps []types.ComponentMetadata) []types.ComponentMetadata {
+	return slices.DeleteFunc(slices.Clone(comps), func(c types.ComponentMetadata) bool {
+		return !s.access.canRead(c.Name())
+	})
+}
+
+// flush applies the staged writes.
+func (s *isolatedStore) flush() error {
+	for _, v := range s.staged {
+		if err := s.Manager.SetComponentForEntity(v.cType, v.id, v.value); err != nil {
+			return err
+		}
+	}
+	s.staged = nil
+	return nil
+}
+
+func (s *isolatedStore) ToReadOnly() gamestate.Reader {
+	return s
+}
+
+func (s *isolatedStore) GetComponentForEntity(cType types.ComponentMetadata, id types.EntityID) (any, error) {
+	if err := s.check(cType, false); err != nil {
+		return nil, err
+	}
+	for _, v := range s.staged {
+		if v.cType.ID() == cType.ID() && v.id == id {
+			return v.value, nil
+		}
+	}
+	return s.Manager.GetComponentForEntity(cType, id)
+}
+
+func (s *isolatedStore) GetComponentForEntityInRawJSON(
+	cType types.ComponentMetadata, id types.EntityID,
+) (json.RawMessage, error) {
+	value, err := s.GetComponentForEntity(cType, id)
+	if err != nil {
+		return nil, err
+	}
+	return cType.Encode(value)
+}
+
+// SetComponentForEntity stages the value. The entity must have the component.
+func (s *isolatedStore) SetComponentForEntity(cType types.ComponentMetadata, id types.EntityID, value any) error {
+	if err := s.check(cType, true); err != nil {
+		return err
+	}
+	i := slices.IndexFunc(s.staged, func(v stagedValue) bool { return v.cType.ID() == cType.ID() && v.id == id })
+	if i >= 0 {
+		s.staged[i].value = value
+		return nil
+	}
+	if _, err := s.Manager.GetComponentForEntity(cType, id); err != nil {
+		return err
+	}
+	s.staged = append(s.staged, stagedValue{cType: cType, id: id, value: value})
+	return nil
+}
+
+func (s *isolatedStore) GetComponentTypesForEntity(id types.EntityID) ([]types.ComponentMetadata, error) {
+	comps, err := s.Manager.GetComponentTypesForEntity(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.readable(comps), nil
+}
+
+func (s *isolatedStore) GetComponentTypesForArchID(archID types.ArchetypeID) ([]types.ComponentMetadata, error) {
+	comps, err := s.Manager.GetComponentTypesForArchID(archID)
+	if err != nil {
+		return nil, err
+	}
+	return s.readable(comps), nil
+}
+
+func (s *isolatedStore) GetArchIDForComponents(comps []types.ComponentMetadata) (types.ArchetypeID, error) {
+	for _, cType := range comps {
+		if err := s.check(cType, false); err != nil {
+			return 0, err
+		}
+	}
+	return s.Manager.GetArchIDForComponents(comps)
+}
+
+func (s *isolatedStore) SearchFrom(f filter.ComponentFilter, start int) *iterators.ArchetypeIterator {
+	return s.Manager.SearchFrom(readableFilter{ComponentFilter: f, access: s.access}, start)
+}
+
+func (s *isolatedStore) FindArchetypes(f filter.ComponentFilter) []types.ArchetypeID {
+	return s.Manager.FindArchetypes(readableFilter{ComponentFilter: f, access: s.access})
+}
+
+func (s *isolatedStore) LookupFieldIndex(component, field, key string) ([]types.EntityID, bool, error) {
+	if !s.access.canRead(component) {
+		return nil, true, eris.Wrapf(ErrComponentAccessDenied, "system %q may not read component %q", s.system, component)
+	}
+	return s.Manager.LookupFieldIndex(component, field, key)
+}
+
+// readableFilter matches archetypes by the components that a system may read, as if they had no others.
+type readableFilter struct {
+	filter.ComponentFilter
+	access ComponentAccess
+}
+
+func (f readableFilter) MatchesComponents(components []types.Component) bool {
+	return f.ComponentFilter.MatchesComponents(slices.DeleteFunc(slices.Clone(components), func(c types.Component) bool {
+		return !f.access.canRead(c.Name())
+	}))
+}
@@ -0,0 +1,128 @@
+package cardinal_test
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// rendezvous is passed by concurrent systems, and times out if they run one after another.
+type rendezvous struct {
+	wg sync.WaitGroup
+}
+
+func (r *rendezvous) meet() error {
+	r.wg.Done()
+	met := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(met)
+	}()
+	select {
+	case <-met:
+		return nil
+	case <-time.After(5 * time.Second):
+		return errors.New("systems did not run concurrently")
+	}
+}
+
+func TestSystemsWithDisjointAccessRunConcurrently(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterComponent[HogState](world))
+
+	var id types.EntityID
+	assert.NilError(t, cardinal.RegisterInitSystems(world, func(wCtx engine.Context) error {
+		var err error
+		id, err = cardinal.Create(wCtx, Health{}, HogState{})
+		return err
+	}))
+
+	r := &rendezvous{}
+	var readErr, createErr error
+	var hidden, staged int
+	assert.NilError(t, cardinal.RegisterSystemsWithAccess(world, cardinal.ComponentAccess{Write: []string{"health"}},
+		func(wCtx engine.Context) error {
+			if err := r.meet(); err != nil {
+				return err
+			}
+			_, readErr = cardinal.GetComponent[HogState](wCtx, id)
+			_, createErr = cardinal.Create(wCtx, Health{})
+			var err error
+			if hidden, err = cardinal.NewSearch().Entity(
+				filter.Contains(filter.Component[HogState]())).Count(wCtx); err != nil {
+				return err
+			}
+			if err := cardinal.SetComponent[Health](wCtx, id, &Health{Value: 1}); err != nil {
+				return err
+			}
+			// The system reads its own writes before they are applied.
+			health, err := cardinal.GetComponent[Health](wCtx, id)
+			if err != nil {
+				return err
+			}
+			staged = health.Value
+			return wCtx.EmitEvent(map[string]any{"type": "first"})
+		}))
+	assert.NilError(t, cardinal.RegisterSystemsWithAccess(world, cardinal.ComponentAccess{Write: []string{"hog_state"}},
+		func(wCtx engine.Context) error {
+			if err := r.meet(); err != nil {
+				return err
+			}
+			if err := cardinal.SetComponent[HogState](wCtx, id, &HogState{Value: 2}); err != nil {
+				return err
+			}
+			return wCtx.EmitEvent(map[string]any{"type": "second"})
+		}))
+
+	// Events are emitted in the order the systems were registered in, whichever system finished first.
+	var order []string
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		order = nil
+		for _, e := range wCtx.EmittedEvents() {
+			var event struct{ Type string }
+			if err := json.Unmarshal(e, &event); err != nil {
+				return err
+			}
+			order = append(order, event.Type)
+		}
+		return nil
+	}))
+
+	for range 2 {
+		r.wg.Add(2)
+		tf.DoTick()
+		assert.DeepEqual(t, order, []string{"first", "second"})
+		assert.ErrorIs(t, readErr, cardinal.ErrComponentAccessDenied)
+		assert.ErrorIs(t, createErr, cardinal.ErrNotParallelSafe)
+		// Searches don't see the components that the system may not read.
+		assert.Equal(t, hidden, 0)
+		assert.Equal(t, staged, 1)
+	}
+
+	wCtx := cardinal.NewReadOnlyWorldContext(world)
+	health, err := cardinal.GetComponent[Health](wCtx, id)
+	assert.NilError(t, err)
+	assert.Equal(t, health.Value, 1)
+	hog, err := cardinal.GetComponent[HogState](wCtx, id)
+	assert.NilError(t, err)
+	assert.Equal(t, hog.Value, 2)
+}
+
+func TestSystemAccessMustNameRegisteredComponents(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, cardinal.RegisterSystemsWithAccess(tf.World, cardinal.ComponentAccess{Read: []string{"mana"}},
+		func(engine.Context) error { return nil }))
+	err := tf.World.StartGame()
+	assert.ErrorContains(t, err, `names unknown component "mana"`)
+}
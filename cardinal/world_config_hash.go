@@ -0,0 +1,71 @@
+package cardinal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// configRecords is the configuration that modules recorded with RecordConfig.
+type configRecords struct {
+	mu      sync.RWMutex
+	records map[string][]byte
+	// changes counts the records, so that the world can tell when its fingerprint changed.
+	changes uint64
+}
+
+func newConfigRecords() *configRecords {
+	return &configRecords{records: map[string][]byte{}}
+}
+
+// RecordConfig adds configuration that changes how the world executes ticks, but that the world can't see for itself,
+// such as the source of a script, to the world's config hash. Recording a key again replaces its value. It is safe
+// to call while the world is running.
+func (w *World) RecordConfig(key string, value []byte) {
+	w.config.mu.Lock()
+	defer w.config.mu.Unlock()
+	w.config.records[key] = append([]byte(nil), value...)
+	w.config.changes++
+}
+
+func (c *configRecords) version() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.changes
+}
+
+// ConfigHash returns a hex encoded hash of everything that determines how the world executes ticks apart from its
+// state and transactions: its registered components, messages and systems, its modules, and the configuration
+// recorded with RecordConfig. Replicas that report the same config hash execute the same transactions in the same
+// way, so comparing it is a quick check that replicas or replays are set up alike.
+func (w *World) ConfigHash() string {
+	h := sha256.New()
+	for _, c := range w.GetRegisteredComponents() {
+		fmt.Fprintf(h, "component %q\n", c.Name())
+	}
+	for _, m := range w.GetRegisteredMessages() {
+		fmt.Fprintf(h, "message %q\n", m.FullName())
+	}
+	for _, name := range w.GetRegisteredSystemNames() {
+		fmt.Fprintf(h, "system %q\n", name)
+	}
+	for _, m := range w.GetModules() {
+		fmt.Fprintf(h, "module %q %q\n", m.Name, m.Version)
+	}
+
+	w.config.mu.RLock()
+	defer w.config.mu.RUnlock()
+	keys := make([]string, 0, len(w.config.records))
+	for key := range w.config.records {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := w.config.records[key]
+		fmt.Fprintf(h, "config %q %d\n", key, len(value))
+		h.Write(value)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,171 @@
+package cardinal
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// WriteConflict is a component of an entity that the handlers of more than one message type changed in the same tick.
+// The outcome of such a tick depends on the order the systems that handle the messages run in, which is a common
+// source of bugs when systems are reordered.
+type WriteConflict struct {
+	Tick      uint64         `json:"tick"`
+	EntityID  types.EntityID `json:"entityId"`
+	Component string         `json:"component"`
+	// Messages are the full names of the message types whose handlers changed the component, in the order they first
+	// changed it.
+	Messages []string `json:"messages"`
+}
+
+// WriteConflicts returns the write conflicts of the most recent tick, if conflict detection is enabled; see
+// WithConflictDetection.
+func (w *World) WriteConflicts() []WriteConflict {
+	if w.conflicts == nil {
+		return nil
+	}
+	w.conflicts.mu.Lock()
+	defer w.conflicts.mu.Unlock()
+	return slices.Clone(w.conflicts.report)
+}
+
+type conflictKey struct {
+	id        types.EntityID
+	component string
+}
+
+// conflictTracker records which message types' handlers changed which components during a tick. Message handlers
+// tell the tracker which message they handle through the context; see TrackMessageHandler.
+type conflictTracker struct {
+	mu sync.Mutex
+	// active is the full name of the message whose handlers are running, if any.
+	active string
+	writes map[conflictKey][]string
+	order  []conflictKey
+	report []WriteConflict
+}
+
+func newConflictTracker() *conflictTracker {
+	return &conflictTracker{writes: map[conflictKey][]string{}}
+}
+
+// start makes the tracker attribute the changes to the message, until the returned function is called.
+func (t *conflictTracker) start(msgName string) func() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	prev := t.active
+	t.active = msgName
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.active = prev
+	}
+}
+
+func (t *conflictTracker) isActive() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.active != ""
+}
+
+func (t *conflictTracker) record(id types.EntityID, cType types.ComponentMetadata) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.active == "" {
+		return
+	}
+	key := conflictKey{id: id, component: cType.Name()}
+	msgs, ok := t.writes[key]
+	if !ok {
+		t.order = append(t.order, key)
+	}
+	if !slices.Contains(msgs, t.active) {
+		t.writes[key] = append(msgs, t.active)
+	}
+}
+
+// finish reports the conflicts of the tick, and forgets its changes.
+func (t *conflictTracker) finish(tick uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.report = nil
+	for _, key := range t.order {
+		msgs := t.writes[key]
+		if len(msgs) < 2 {
+			continue
+		}
+		t.report = append(t.report, WriteConflict{
+			Tick:      tick,
+			EntityID:  key.id,
+			Component: key.component,
+			Messages:  msgs,
+		})
+		log.Warn().Uint64("tick", tick).Uint64("entity_id", uint64(key.id)).Str("component", key.component).
+			Strs("messages", msgs).
+			Msg("handlers of more than one message type changed the same component; " +
+				"the outcome depends on the order of the systems that handle them")
+	}
+	t.writes = map[conflictKey][]string{}
+	t.order = nil
+}
+
+// conflictStore records the changes that message handlers make to the state.
+type conflictStore struct {
+	gamestate.Manager
+	tracker *conflictTracker
+}
+
+func (s *conflictStore) ToReadOnly() gamestate.Reader {
+	return s.Manager.ToReadOnly()
+}
+
+func (s *conflictStore) SetComponentForEntity(cType types.ComponentMetadata, id types.EntityID, value any) error {
+	if err := s.Manager.SetComponentForEntity(cType, id, value); err != nil {
+		return err
+	}
+	s.tracker.record(id, cType)
+	return nil
+}
+
+func (s *conflictStore) AddComponentToEntity(cType types.ComponentMetadata, id types.EntityID) error {
+	if err := s.Manager.AddComponentToEntity(cType, id); err != nil {
+		return err
+	}
+	s.tracker.record(id, cType)
+	return nil
+}
+
+func (s *conflictStore) RemoveComponentFromEntity(cType types.ComponentMetadata, id types.EntityID) error {
+	if err := s.Manager.RemoveComponentFromEntity(cType, id); err != nil {
+		return err
+	}
+	s.tracker.record(id, cType)
+	return nil
+}
+
+func (s *conflictStore) RemoveEntity(id types.EntityID) error {
+	comps, err := s.Manager.GetComponentTypesForEntity(id)
+	if err != nil {
+		return err
+	}
+	if err := s.Manager.RemoveEntity(id); err != nil {
+		return err
+	}
+	for _, cType := range comps {
+		s.tracker.record(id, cType)
+	}
+	return nil
+}
+
+// TrackMessageHandler attributes the changes to the state that are made until the returned function is called to
+// the message, so that write conflicts between message types can be reported.
+func (ctx *worldContext) TrackMessageHandler(msgName string) func() {
+	if ctx.world.conflicts == nil || ctx.readOnly {
+		return func() {}
+	}
+	return ctx.world.conflicts.start(msgName)
+}
@@ -0,0 +1,75 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/sign"
+)
+
+type DamageMsg struct {
+	Amount int
+}
+
+type DamageResult struct{}
+
+func TestWriteConflictsBetweenMessageTypesAreReported(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithConflictDetection(true))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterMessage[DamageMsg, DamageResult](world, "damage"))
+	assert.NilError(t, cardinal.RegisterMessage[HealMsg, HealResult](world, "heal"))
+
+	var id types.EntityID
+	assert.NilError(t, cardinal.RegisterInitSystems(world, func(wCtx engine.Context) error {
+		var err error
+		id, err = cardinal.Create(wCtx, Health{})
+		return err
+	}))
+	changeHealth := func(wCtx engine.Context, amount int) error {
+		return cardinal.UpdateComponent[Health](wCtx, id, func(h *Health) *Health {
+			h.Value += amount
+			return h
+		})
+	}
+	assert.NilError(t, cardinal.RegisterSystems(world,
+		func(wCtx engine.Context) error {
+			return cardinal.EachMessage[DamageMsg, DamageResult](wCtx,
+				func(tx message.TxData[DamageMsg]) (DamageResult, error) {
+					return DamageResult{}, changeHealth(wCtx, -tx.Msg.Amount)
+				})
+		},
+		func(wCtx engine.Context) error {
+			return cardinal.EachMessage[HealMsg, HealResult](wCtx,
+				func(tx message.TxData[HealMsg]) (HealResult, error) {
+					return HealResult{}, changeHealth(wCtx, tx.Msg.Amount)
+				})
+		},
+	))
+	tf.DoTick()
+
+	damage, ok := world.GetMessageByFullName("game.damage")
+	assert.True(t, ok)
+	heal, ok := world.GetMessageByFullName("game.heal")
+	assert.True(t, ok)
+
+	// Transactions of one message type don't conflict with each other.
+	tf.AddTransaction(damage.ID(), DamageMsg{Amount: 1}, &sign.Transaction{PersonaTag: "a"})
+	tf.AddTransaction(damage.ID(), DamageMsg{Amount: 2}, &sign.Transaction{PersonaTag: "b"})
+	tf.DoTick()
+	assert.Equal(t, len(world.WriteConflicts()), 0)
+
+	tf.AddTransaction(damage.ID(), DamageMsg{Amount: 1}, &sign.Transaction{PersonaTag: "a"})
+	tf.AddTransaction(heal.ID(), HealMsg{Amount: 1}, &sign.Transaction{PersonaTag: "b"})
+	tf.DoTick()
+	conflicts := world.WriteConflicts()
+	assert.Equal(t, len(conflicts), 1)
+	assert.Equal(t, conflicts[0].EntityID, id)
+	assert.Equal(t, conflicts[0].Component, Health{}.Name())
+	assert.DeepEqual(t, conflicts[0].Messages, []string{"game.damage", "game.heal"})
+}
@@ -0,0 +1,101 @@
+package cardinal
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+var (
+	ErrContentIDNotFound  = errors.New("no entity has the content ID")
+	ErrDuplicateContentID = errors.New("more than one entity has the content ID")
+)
+
+// ContentID is a stable, designer-assigned ID of an entity. Entity IDs are assigned by the world in the order that
+// entities are created, so they differ between worlds and change whenever content is recreated; content IDs let
+// content pipelines refer to the same entity everywhere. ContentID is optional: register it with
+// RegisterComponent[ContentID], or use the content module, which registers it.
+type ContentID struct {
+	ID string `json:"id"`
+}
+
+func (ContentID) Name() string { return "content-id" }
+
+// contentIDIndex maps content IDs to entities. State only changes in ticks, so the index is rebuilt at most once per
+// tick, when it is first used.
+type contentIDIndex struct {
+	mu    sync.Mutex
+	built bool
+	tick  uint64
+	ids   map[string]types.EntityID
+	// duplicates holds the content IDs that more than one entity has.
+	duplicates map[string]bool
+}
+
+func newContentIDIndex() *contentIDIndex {
+	return &contentIDIndex{}
+}
+
+// invalidate makes the index rebuild when it is next used, for when the world's state changes without the tick
+// advancing, as in a rollback.
+func (idx *contentIDIndex) invalidate() {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.built = false
+}
+
+// GetByContentID returns the entity with the given content ID, as of the last completed tick. Systems that need to
+// see the entities created earlier in the same tick should search for the ContentID component instead.
+func (w *World) GetByContentID(contentID string) (types.EntityID, error) {
+	idx := w.contentIDs
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if !idx.built || idx.tick != w.CurrentTick() {
+		if err := w.buildContentIDIndex(idx); err != nil {
+			return 0, err
+		}
+	}
+	if idx.duplicates[contentID] {
+		return 0, eris.Wrapf(ErrDuplicateContentID, "content ID %q", contentID)
+	}
+	id, ok := idx.ids[contentID]
+	if !ok {
+		return 0, eris.Wrapf(ErrContentIDNotFound, "content ID %q", contentID)
+	}
+	return id, nil
+}
+
+func (w *World) buildContentIDIndex(idx *contentIDIndex) error {
+	if _, err := w.GetComponentByName(ContentID{}.Name()); err != nil {
+		return eris.Wrap(err, "content IDs are not registered; see ContentID")
+	}
+	ids := map[string]types.EntityID{}
+	duplicates := map[string]bool{}
+	wCtx := NewReadOnlyWorldContext(w)
+	tick := w.CurrentTick()
+	var eachErr error
+	err := NewSearch().Entity(filter.Contains(filter.Component[ContentID]())).Each(wCtx, func(id types.EntityID) bool {
+		var contentID *ContentID
+		contentID, eachErr = GetComponent[ContentID](wCtx, id)
+		if eachErr != nil {
+			return false
+		}
+		if _, ok := ids[contentID.ID]; ok {
+			duplicates[contentID.ID] = true
+		}
+		ids[contentID.ID] = id
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if eachErr != nil {
+		return eachErr
+	}
+	idx.built, idx.tick, idx.ids, idx.duplicates = true, tick, ids, duplicates
+	return nil
+}
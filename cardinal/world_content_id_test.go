@@ -0,0 +1,53 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func TestGetByContentID(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[cardinal.ContentID](world))
+	assert.NilError(t, cardinal.RegisterComponent[CounterComponent](world))
+
+	var goblin types.EntityID
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		var err error
+		switch wCtx.CurrentTick() {
+		case 0:
+			goblin, err = cardinal.Create(wCtx, cardinal.ContentID{ID: "goblin"}, CounterComponent{})
+			if err == nil {
+				_, err = cardinal.Create(wCtx, CounterComponent{})
+			}
+		case 2:
+			// A content ID is only meant to be used by one entity.
+			_, err = cardinal.Create(wCtx, cardinal.ContentID{ID: "goblin"})
+		}
+		return err
+	}))
+	tf.DoTick()
+
+	id, err := world.GetByContentID("goblin")
+	assert.NilError(t, err)
+	assert.Equal(t, id, goblin)
+	_, err = world.GetByContentID("dragon")
+	assert.ErrorIs(t, err, cardinal.ErrContentIDNotFound)
+
+	tf.DoTick()
+	tf.DoTick()
+	_, err = world.GetByContentID("goblin")
+	assert.ErrorIs(t, err, cardinal.ErrDuplicateContentID)
+}
+
+func TestGetByContentIDRequiresTheComponent(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	tf.DoTick()
+	_, err := tf.World.GetByContentID("goblin")
+	assert.ErrorContains(t, err, "content IDs are not registered")
+}
@@ -3,14 +3,18 @@ package cardinal
 import (
 	"reflect"
 
+	"github.com/rotisserie/eris"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
+	"pkg.world.dev/world-engine/cardinal/attestation"
 	"pkg.world.dev/world-engine/cardinal/gamestate"
 	"pkg.world.dev/world-engine/cardinal/receipt"
+	"pkg.world.dev/world-engine/cardinal/rng"
 	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/cardinal/types/engine"
 	"pkg.world.dev/world-engine/cardinal/types/txpool"
+	"pkg.world.dev/world-engine/cardinal/worldclock"
 	"pkg.world.dev/world-engine/cardinal/worldstage"
 	"pkg.world.dev/world-engine/sign"
 )
@@ -18,11 +22,36 @@ import (
 // interface guard
 var _ engine.Context = (*worldContext)(nil)
 
+// contextWrapper is implemented by the contexts that wrap the context of the tick, such as the contexts of systems
+// that run concurrently and of parallel message handlers.
+type contextWrapper interface {
+	Unwrap() engine.Context
+}
+
+// unwrapWorldContext returns the world context that wCtx is, or wraps.
+func unwrapWorldContext(wCtx engine.Context) (*worldContext, bool) {
+	for {
+		switch ctx := wCtx.(type) {
+		case *worldContext:
+			return ctx, true
+		case contextWrapper:
+			wCtx = ctx.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
+
 type worldContext struct {
 	world    *World
 	txPool   *txpool.TxPool
 	logger   *zerolog.Logger
 	readOnly bool
+
+	// rand is the random number generator of the system at randIndex in the tick randTick.
+	rand      *rng.RNG
+	randTick  uint64
+	randIndex int
 }
 
 func newWorldContextForTick(world *World, txPool *txpool.TxPool) engine.Context {
@@ -99,14 +128,33 @@ func (ctx *worldContext) EmitEvent(event map[string]any) error {
 	return ctx.world.tickResults.AddEvent(event)
 }
 
+func (ctx *worldContext) EmitEventBounded(event map[string]any) error {
+	if err := ctx.world.eventBudget.admit(eventTopic(event)); err != nil {
+		return err
+	}
+	return ctx.world.tickResults.AddEvent(event)
+}
+
 func (ctx *worldContext) EmitStringEvent(e string) error {
 	return ctx.world.tickResults.AddStringEvent(e)
 }
 
+func (ctx *worldContext) EmitPriorityEvent(event map[string]any) error {
+	return ctx.world.tickResults.AddPriorityEvent(event)
+}
+
+func (ctx *worldContext) EmittedEvents() [][]byte {
+	return ctx.world.tickResults.Events
+}
+
 func (ctx *worldContext) GetSignerForPersonaTag(personaTag string, tick uint64) (addr string, err error) {
 	return ctx.world.GetSignerForPersonaTag(personaTag, tick)
 }
 
+func (ctx *worldContext) ResolvePersonaTag(personaTag string) (string, error) {
+	return ctx.world.ResolvePersonaTag(personaTag)
+}
+
 func (ctx *worldContext) GetTransactionReceiptsForTick(tick uint64) ([]receipt.Receipt, error) {
 	return ctx.world.GetTransactionReceiptsForTick(tick)
 }
@@ -115,12 +163,34 @@ func (ctx *worldContext) ReceiptHistorySize() uint64 {
 	return ctx.world.receiptHistory.Size()
 }
 
+func (ctx *worldContext) Attest(payload []byte) (attestation.Attestation, error) {
+	if ctx.readOnly {
+		return attestation.Attestation{}, eris.New("attestations can't be made in a read only context")
+	}
+	if ctx.world.attestationKey == nil {
+		return attestation.Attestation{}, ErrNoAttestationKey
+	}
+	return attestation.Sign(ctx.world.attestationKey, ctx.Namespace(), ctx.CurrentTick(), payload)
+}
+
 func (ctx *worldContext) Namespace() string {
 	return ctx.world.Namespace()
 }
 
+func (ctx *worldContext) WorldClock() *worldclock.Clock {
+	return ctx.world.clock
+}
+
+func (ctx *worldContext) Rand() *rng.RNG {
+	tick, index := ctx.CurrentTick(), ctx.world.systemManager.GetCurrentSystemIndex()
+	if ctx.rand == nil || ctx.randTick != tick || ctx.randIndex != index {
+		ctx.rand, ctx.randTick, ctx.randIndex = ctx.world.newSystemRand(tick, index), tick, index
+	}
+	return ctx.rand
+}
+
 func (ctx *worldContext) AddTransaction(id types.MessageID, v any, sig *sign.Transaction) (uint64, types.TxHash) {
-	return ctx.world.AddTransaction(id, v, sig)
+	return ctx.world.addTransactionToPool(txpool.TxData{MsgID: id, Msg: v, Tx: sig})
 }
 
 func (ctx *worldContext) GetTxPool() *txpool.TxPool {
@@ -132,7 +202,23 @@ func (ctx *worldContext) IsReadOnly() bool {
 }
 
 func (ctx *worldContext) StoreManager() gamestate.Manager {
-	return ctx.world.entityStore
+	var store gamestate.Manager = ctx.world.entityStore
+	if ctx.readOnly {
+		return store
+	}
+	if m := ctx.world.storageMetrics; m != nil {
+		store = &metricsStore{Manager: store, metrics: m, tick: ctx.CurrentTick()}
+	}
+	if c := ctx.world.conflicts; c != nil && c.isActive() {
+		store = &conflictStore{Manager: store, tracker: c}
+	}
+	if b := ctx.world.budgets; b != nil && b.running() {
+		store = &budgetStore{Manager: store, accounting: b}
+	}
+	if a := ctx.world.access; a != nil && a.restricted() {
+		store = &accessStore{Manager: store, access: a}
+	}
+	return store
 }
 
 func (ctx *worldContext) StoreReader() gamestate.Reader {
@@ -0,0 +1,39 @@
+package cardinal
+
+import (
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/receipt"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// RegisterErrorCode registers a code for the errors of transaction receipts, so that clients can branch on errors and
+// show them in their own language instead of parsing English strings. Receipts list each error's code, parameters,
+// and message next to the error string.
+//
+// Systems return coded errors with receipt.NewError. The template, if not empty, is the message of the code's errors,
+// in which "{name}" is replaced by the parameter of that name:
+//
+//	err := cardinal.RegisterErrorCode(world, "out-of-range", "target is {distance} tiles away, at most {max} allowed")
+//
+// Errors that wrap one of the sentinel errors are given the code too, which codes the errors of modules and other
+// packages without changing them:
+//
+//	err := cardinal.RegisterErrorCode(world, "not-owner", "", ownership.ErrNotOwner)
+//
+// Errors that have no registered code are given the code receipt.CodeUnknown.
+func RegisterErrorCode(w *World, code, template string, sentinels ...error) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"engine state is %s, expected %s to register error codes",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	return w.errorCodes.Register(code, template, sentinels...)
+}
+
+// ErrorCodes returns the registry of the world's error codes.
+func (w *World) ErrorCodes() *receipt.ErrorCodes {
+	return w.errorCodes
+}
@@ -0,0 +1,85 @@
+package cardinal
+
+import (
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/statsd"
+)
+
+// DefaultEventBudget is the number of events of each topic that systems may emit with EmitEventBounded in a tick,
+// unless WithEventBudgets says otherwise.
+const DefaultEventBudget = 10_000
+
+// ErrEventBudgetExceeded is returned by EmitEventBounded when the event's topic has used up its budget for the tick.
+// The event is dropped.
+var ErrEventBudgetExceeded = errors.New("event budget of the topic is exhausted for this tick")
+
+// eventBudget counts the events that systems emit with EmitEventBounded in a tick, by topic, and drops the events
+// beyond the budgets of their topics.
+type eventBudget struct {
+	// budgets are keyed by topic. The budget of "" applies to the topics without one.
+	budgets map[string]int
+
+	mu      sync.Mutex
+	emitted map[string]int
+	dropped map[string]int
+}
+
+func newEventBudget(budgets map[string]int) *eventBudget {
+	return &eventBudget{budgets: budgets, emitted: map[string]int{}, dropped: map[string]int{}}
+}
+
+// budget returns the number of events of the topic that may be emitted in a tick, or 0 if it is unlimited.
+func (b *eventBudget) budget(topic string) int {
+	if budget, ok := b.budgets[topic]; ok {
+		return budget
+	}
+	if budget, ok := b.budgets[""]; ok {
+		return budget
+	}
+	return DefaultEventBudget
+}
+
+// admit counts an event of the topic, and returns ErrEventBudgetExceeded if the topic has used up its budget.
+func (b *eventBudget) admit(topic string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if budget := b.budget(topic); budget > 0 && b.emitted[topic] >= budget {
+		b.dropped[topic]++
+		return eris.Wrapf(ErrEventBudgetExceeded, "topic %q may emit %d events per tick", topic, budget)
+	}
+	b.emitted[topic]++
+	return nil
+}
+
+// finish warns about the topics whose events were dropped in the tick, and starts over.
+func (b *eventBudget) finish(tick uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	topics := make([]string, 0, len(b.dropped))
+	for topic := range b.dropped {
+		topics = append(topics, topic)
+	}
+	slices.Sort(topics)
+	for _, topic := range topics {
+		tags := []string{"topic:" + topic}
+		if err := statsd.Client().Count("events.dropped", int64(b.dropped[topic]), tags, 1); err != nil {
+			log.Warn().Msgf("failed to emit count stat:%v", err)
+		}
+		log.Warn().Uint64("tick", tick).Str("topic", topic).Int("emitted", b.emitted[topic]).
+			Int("dropped", b.dropped[topic]).Msg("events were dropped because their topic exceeded its budget")
+	}
+	b.emitted = map[string]int{}
+	b.dropped = map[string]int{}
+}
+
+// eventTopic returns the topic of the event, which is the value of its "type" field, like event subscribers see it.
+func eventTopic(event map[string]any) string {
+	topic, _ := event["type"].(string)
+	return topic
+}
@@ -0,0 +1,43 @@
+package cardinal_test
+
+import (
+	"errors"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func TestEmitEventBoundedDropsEventsBeyondTheBudget(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithEventBudgets(map[string]int{"spam": 3}))
+	var emitted, dropped, otherDropped int
+	assert.NilError(t, cardinal.RegisterSystems(tf.World, func(wCtx engine.Context) error {
+		before := len(wCtx.EmittedEvents())
+		dropped, otherDropped = 0, 0
+		for range 5 {
+			err := wCtx.EmitEventBounded(map[string]any{"type": "spam"})
+			if errors.Is(err, cardinal.ErrEventBudgetExceeded) {
+				dropped++
+			} else if err != nil {
+				return err
+			}
+		}
+		for range 5 {
+			if err := wCtx.EmitEventBounded(map[string]any{"type": "loot"}); err != nil {
+				otherDropped++
+			}
+		}
+		emitted = len(wCtx.EmittedEvents()) - before
+		return nil
+	}))
+
+	// The budget is per tick, so every tick emits the same events.
+	for range 2 {
+		tf.DoTick()
+		assert.Equal(t, dropped, 2)
+		assert.Equal(t, otherDropped, 0)
+		assert.Equal(t, emitted, 8)
+	}
+}
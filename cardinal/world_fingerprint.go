@@ -0,0 +1,186 @@
+package cardinal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/invopop/jsonschema"
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// ErrIncompatibleState is returned by StartGame when the stored state was produced by another version of the game whose
+// fingerprint isn't compatible with the world's, and no migration is registered from that version.
+var ErrIncompatibleState = errors.New("stored state was produced by an incompatible version of the game")
+
+// Fingerprint identifies the code that produced a world's state. It is saved with the world's genesis and with every
+// tick, so that a world doesn't silently diverge by resuming from state that was produced by incompatible code.
+type Fingerprint struct {
+	// GameVersion is the version of the game binary; see WithGameVersion.
+	GameVersion string `json:"gameVersion"`
+	// SchemaHash is a hex encoded hash of the JSON schemas of the registered components and messages.
+	SchemaHash string `json:"schemaHash"`
+	// ConfigHash is the world's ConfigHash.
+	ConfigHash string `json:"configHash"`
+}
+
+// IsCompatible reports whether a world with the fingerprint can resume from state that was produced by code with the
+// other fingerprint, which is when their schemas are the same. Configuration changes, such as new systems, don't
+// change how the state is stored, so they are compatible.
+func (f Fingerprint) IsCompatible(other Fingerprint) bool {
+	return f.SchemaHash == other.SchemaHash
+}
+
+// Migration lets a world resume from state that was produced by an incompatible version of the game.
+type Migration struct {
+	// FromVersion is the game version that produced the state.
+	FromVersion string
+	// Migrate updates the state for the current code. It is run at the start of the first tick after the world
+	// resumes, before any system, and its changes are committed with that tick. It may be nil if the current code can
+	// use the state as it is.
+	Migrate func(wCtx engine.Context) error
+}
+
+// RegisterMigrations registers migrations from earlier versions of the game. Without a migration, StartGame refuses
+// to resume from state that was produced by another game version whose fingerprint isn't compatible. State that was
+// produced by the same game version is resumed as is, since that is how development builds restart.
+func RegisterMigrations(w *World, migrations ...Migration) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"engine state is %s, expected %s to register migrations",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	for _, m := range migrations {
+		if _, ok := w.migrations[m.FromVersion]; ok {
+			return eris.Errorf("a migration from game version %q is already registered", m.FromVersion)
+		}
+		w.migrations[m.FromVersion] = m
+	}
+	return nil
+}
+
+// Fingerprint returns the fingerprint of the world's code. It is only complete once the world has started, since the
+// configuration recorded by RecordConfig may change until then.
+func (w *World) Fingerprint() Fingerprint {
+	return Fingerprint{
+		GameVersion: w.gameVersion,
+		SchemaHash:  w.schemaHash(),
+		ConfigHash:  w.ConfigHash(),
+	}
+}
+
+// GenesisFingerprint returns the fingerprint of the code that ran the world's first tick, and false if it isn't
+// known, either because the world never ticked or because its state predates fingerprints.
+func (w *World) GenesisFingerprint() (Fingerprint, bool, error) {
+	genesis, _, err := w.entityStore.GetFingerprints()
+	if err != nil || genesis == nil {
+		return Fingerprint{}, false, err
+	}
+	f, err := codec.Decode[Fingerprint](genesis)
+	if err != nil {
+		return Fingerprint{}, false, err
+	}
+	return f, true, nil
+}
+
+func (w *World) schemaHash() string {
+	h := sha256.New()
+	for _, c := range w.GetRegisteredComponents() {
+		fmt.Fprintf(h, "component %q %s\n", c.Name(), c.GetSchema())
+	}
+	for _, m := range w.GetRegisteredMessages() {
+		in, _ := jsonschema.ReflectFromType(m.InType()).MarshalJSON()
+		out, _ := jsonschema.ReflectFromType(m.OutType()).MarshalJSON()
+		fmt.Fprintf(h, "message %q %s %s\n", m.FullName(), in, out)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// pinFingerprint checks the world's fingerprint against the fingerprint that was saved with the stored state, and
+// saves it with every tick from now on. A registered migration from the stored game version is scheduled to run in
+// the next tick.
+func (w *World) pinFingerprint() error {
+	current := w.Fingerprint()
+	_, end, err := w.entityStore.GetTickNumbers()
+	if err != nil {
+		return err
+	}
+	genesis, latest, err := w.entityStore.GetFingerprints()
+	if err != nil {
+		return err
+	}
+	if latest == nil && end > 0 {
+		log.Warn().Msg("stored state has no fingerprint, so it can't be checked against the game version")
+	}
+	if latest != nil {
+		stored, err := codec.Decode[Fingerprint](latest)
+		if err != nil {
+			return err
+		}
+		switch {
+		case current.IsCompatible(stored):
+			if current.ConfigHash != stored.ConfigHash {
+				log.Info().Msgf("config hash changed from %s to %s", stored.ConfigHash, current.ConfigHash)
+			}
+		case current.GameVersion == stored.GameVersion:
+			log.Warn().Msgf("schemas of game version %q changed since the state was stored", current.GameVersion)
+		default:
+			migration, ok := w.migrations[stored.GameVersion]
+			if !ok {
+				return eris.Wrapf(ErrIncompatibleState,
+					"state was produced by game version %q with schema hash %s, but game version %q has schema hash %s; "+
+						"register a migration from %q to resume",
+					stored.GameVersion, stored.SchemaHash, current.GameVersion, current.SchemaHash, stored.GameVersion)
+			}
+			log.Info().Msgf("migrating state from game version %q to %q", stored.GameVersion, current.GameVersion)
+			w.migration = migration.Migrate
+		}
+	}
+	return w.saveFingerprint(current, genesis == nil && end == 0)
+}
+
+// refreshFingerprint saves the world's fingerprint again if configuration was recorded since it was pinned.
+func (w *World) refreshFingerprint() error {
+	if !w.fingerprintPinned || w.config.version() == w.fingerprintConfigVersion {
+		return nil
+	}
+	return w.saveFingerprint(w.Fingerprint(), false)
+}
+
+func (w *World) saveFingerprint(f Fingerprint, genesis bool) error {
+	w.fingerprintConfigVersion = w.config.version()
+	bz, err := codec.Encode(f)
+	if err != nil {
+		return err
+	}
+	w.entityStore.SetFingerprint(bz, genesis)
+	w.fingerprintPinned = true
+	return nil
+}
+
+// defaultGameVersion returns the version of the main module of the binary, or its VCS revision if it was built from a
+// working copy.
+func defaultGameVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+	return info.Main.Version
+}
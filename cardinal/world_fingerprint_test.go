@@ -0,0 +1,60 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type RenameMsgV1 struct {
+	Name string
+}
+
+type RenameMsgV2 struct {
+	FirstName string
+	LastName  string
+}
+
+type RenameResult struct{}
+
+func TestWorldRefusesToResumeFromIncompatibleState(t *testing.T) {
+	tf1 := testutils.NewTestFixture(t, nil, cardinal.WithGameVersion("v1"))
+	assert.NilError(t, cardinal.RegisterMessage[RenameMsgV1, RenameResult](tf1.World, "rename"))
+	tf1.DoTick()
+	genesis, ok, err := tf1.World.GenesisFingerprint()
+	assert.NilError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, genesis, tf1.World.Fingerprint())
+
+	// The schema of the rename message changed, so v2 can't resume from the state of v1 without a migration.
+	tf2 := testutils.NewTestFixture(t, tf1.Redis, cardinal.WithGameVersion("v2"))
+	assert.NilError(t, cardinal.RegisterMessage[RenameMsgV2, RenameResult](tf2.World, "rename"))
+	err = tf2.World.StartGame() // We start this manually instead of tf2.StartWorld() because StartWorld panics on err
+	assert.ErrorIs(t, err, cardinal.ErrIncompatibleState)
+
+	migrations := 0
+	tf3 := testutils.NewTestFixture(t, tf1.Redis, cardinal.WithGameVersion("v2"))
+	assert.NilError(t, cardinal.RegisterMessage[RenameMsgV2, RenameResult](tf3.World, "rename"))
+	assert.NilError(t, cardinal.RegisterMigrations(tf3.World, cardinal.Migration{
+		FromVersion: "v1",
+		Migrate: func(engine.Context) error {
+			migrations++
+			return nil
+		},
+	}))
+	tf3.DoTick()
+	tf3.DoTick()
+	assert.Equal(t, migrations, 1)
+
+	// The state is now pinned to v2, and its genesis to v1.
+	tf4 := testutils.NewTestFixture(t, tf1.Redis, cardinal.WithGameVersion("v2"))
+	assert.NilError(t, cardinal.RegisterMessage[RenameMsgV2, RenameResult](tf4.World, "rename"))
+	tf4.StartWorld()
+	genesis, ok, err = tf4.World.GenesisFingerprint()
+	assert.NilError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, genesis.GameVersion, "v1")
+}
@@ -0,0 +1,168 @@
+package cardinal
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/events"
+	"pkg.world.dev/world-engine/cardinal/receipt"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+	"pkg.world.dev/world-engine/sign"
+)
+
+var ErrForkDiscarded = errors.New("world fork has been discarded")
+
+// WorldFork is a copy-on-write view of a world's state, in which transactions can be executed speculatively, e.g. to
+// dry-run a transaction, or to let an AI plan or a bot simulate its next moves. The fork starts from the world's last
+// committed state, keeps every change it makes in memory, and never commits them: neither the world, nor other forks,
+// ever see the changes. Forking is cheap, because state is only read from storage when the fork first needs it.
+//
+// A fork runs the world's systems, middleware and derived components, but it doesn't call hooks, persist or submit
+// transactions, or broadcast tick results. A WorldFork must not be used concurrently.
+type WorldFork struct {
+	world     *World
+	discarded bool
+}
+
+// Fork returns a fork of the world's committed state. Changes that systems are making in a tick that is running while
+// the world is forked are not visible in the fork.
+func (w *World) Fork() (*WorldFork, error) {
+	if stage := w.worldStage.Current(); stage != worldstage.Ready && stage != worldstage.Running {
+		return nil, eris.Errorf("can't fork the world in stage %s", stage)
+	}
+	store, err := w.entityStore.Fork()
+	if err != nil {
+		return nil, err
+	}
+
+	tick := new(atomic.Uint64)
+	tick.Store(w.CurrentTick())
+	timestamp := new(atomic.Uint64)
+	timestamp.Store(w.timestamp.Load())
+	stage := worldstage.NewManager()
+	stage.Store(worldstage.Running)
+
+	forked := &World{ //nolint:exhaustruct // The fork doesn't network, recover, or call hooks.
+		namespace:     w.namespace,
+		rollupEnabled: w.rollupEnabled,
+
+		// Storage
+		redisStorage: w.redisStorage,
+		entityStore:  store,
+
+		// Core modules
+		worldStage:        stage,
+		msgManager:        w.msgManager,
+		systemManager:     w.systemManager.Copy(),
+		componentManager:  w.componentManager,
+		queryManager:      w.queryManager,
+		txPool:            txpool.New(),
+		txQueue:           make(chan txpool.TxData, cap(w.txQueue)),
+		txQueueSlots:      make(chan struct{}, cap(w.txQueue)),
+		postCommit:        newPostCommitStage(),
+		contentIDs:        newContentIDIndex(),
+		config:            w.config,
+		arena:             newTickArena(),
+		derivedComponents: w.derivedComponents,
+		txMiddleware:      w.txMiddleware,
+		clock:             w.clock,
+		randomSeed:        w.randomSeed,
+		eventBudget:       newEventBudget(w.eventBudget.budgets),
+		timeSource:        w.timeSource,
+		attestationKey:    w.attestationKey,
+		componentOwners:   w.componentOwners,
+
+		// Persona
+		// The fork builds its own persona index from its state, and never commits it, so the personas it creates are
+		// never seen by the world. Reserved persona tags and the name policy are only changed before the world
+		// starts, so they are shared.
+		livePersonaIndex:    livePersonaIndex{},
+		reservedPersonaTags: w.reservedPersonaTags,
+		personaNamePolicy:   w.personaNamePolicy,
+
+		// Receipt
+		receiptHistory: receipt.NewHistory(tick.Load(), DefaultHistoricalTicksToStore),
+		evmTxReceipts:  make(map[string]EVMTxReceipt),
+		errorCodes:     w.errorCodes,
+		// The events of the fork's ticks are returned by WorldFork.Tick, and not recorded.
+		eventHistory: events.NewHistory(0),
+
+		// Tick
+		tick:        tick,
+		timestamp:   timestamp,
+		tickResults: NewTickResults(tick.Load()),
+	}
+	return &WorldFork{world: forked}, nil
+}
+
+// Context returns a context that reads and changes the fork's state.
+func (f *WorldFork) Context() engine.Context {
+	return NewWorldContext(f.world)
+}
+
+// CurrentTick returns the tick that the fork executes next. It starts at the world's current tick.
+func (f *WorldFork) CurrentTick() uint64 {
+	return f.world.CurrentTick()
+}
+
+// AddTransaction adds a transaction to the fork's next tick. The transaction is not added to the world.
+func (f *WorldFork) AddTransaction(id types.MessageID, v any, sig *sign.Transaction) types.TxHash {
+	_, txHash := f.world.addTransactionToPool(txpool.TxData{MsgID: id, Msg: v, Tx: sig})
+	return txHash
+}
+
+// Tick executes the transactions that were added to the fork in a speculative tick, and returns the receipts and
+// events of the tick. Init systems are only run if the world was forked before its first tick.
+func (f *WorldFork) Tick() (TickResults, error) {
+	if f.discarded {
+		return TickResults{}, eris.Wrap(ErrForkDiscarded, "")
+	}
+	w := f.world
+	w.drainTxQueue()
+	txPool := w.txPool.CopyTransactions()
+	w.timestamp.Store(uint64(w.timeSource.Now().Unix()))
+
+	wCtx, err := w.newSystemsContext(txPool)
+	if err != nil {
+		return TickResults{}, err
+	}
+	for _, event := range w.clock.BoundaryEvents(w.CurrentTick()) {
+		if err := w.tickResults.AddEvent(event); err != nil {
+			return TickResults{}, err
+		}
+	}
+	if err := w.systemManager.RunSystems(wCtx); err != nil {
+		return TickResults{}, err
+	}
+	if err := w.recomputeDerivedComponents(wCtx); err != nil {
+		return TickResults{}, err
+	}
+
+	w.tick.Add(1)
+	w.receiptHistory.NextTick()
+	receipts, err := w.receiptHistory.GetReceiptsForTick(w.CurrentTick() - 1)
+	if err != nil {
+		return TickResults{}, err
+	}
+	w.tickResults.SetReceipts(receipts)
+	w.tickResults.SetTick(w.CurrentTick() - 1)
+	results := *w.tickResults
+	w.tickResults.Clear()
+	return results, nil
+}
+
+// Discard drops the fork's state changes and transactions. The fork can't be used after it is discarded.
+func (f *WorldFork) Discard() error {
+	if f.discarded {
+		return nil
+	}
+	f.discarded = true
+	f.world.drainTxQueue()
+	f.world.txPool.CopyTransactions()
+	return f.world.entityStore.DiscardPending()
+}
@@ -0,0 +1,108 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/persona"
+	"pkg.world.dev/world-engine/cardinal/persona/msg"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/sign"
+)
+
+func TestForkExecutesTransactionsWithoutChangingTheWorld(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[ScoreComponent](world))
+	assert.NilError(t, cardinal.RegisterMessage[*ModifyScoreMsg, *EmptyMsgResult](world, "modify_score"))
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		return cardinal.EachMessage[*ModifyScoreMsg, *EmptyMsgResult](wCtx,
+			func(tx message.TxData[*ModifyScoreMsg]) (*EmptyMsgResult, error) {
+				return &EmptyMsgResult{}, cardinal.UpdateComponent[ScoreComponent](wCtx, tx.Msg.PlayerID,
+					func(s *ScoreComponent) *ScoreComponent {
+						s.Score += tx.Msg.Amount
+						return s
+					})
+			})
+	}))
+	tf.StartWorld()
+
+	wCtx := cardinal.NewWorldContext(world)
+	id, err := cardinal.Create(wCtx, ScoreComponent{Score: 10})
+	assert.NilError(t, err)
+	tf.DoTick()
+	modifyScoreMsg, err := testutils.GetMessage[*ModifyScoreMsg, *EmptyMsgResult](wCtx)
+	assert.NilError(t, err)
+
+	fork, err := world.Fork()
+	assert.NilError(t, err)
+	assert.Equal(t, fork.CurrentTick(), world.CurrentTick())
+	txHash := fork.AddTransaction(modifyScoreMsg.ID(), &ModifyScoreMsg{PlayerID: id, Amount: 5},
+		testutils.UniqueSignature())
+	results, err := fork.Tick()
+	assert.NilError(t, err)
+	assert.Equal(t, len(results.Receipts), 1)
+	assert.Equal(t, results.Receipts[0].TxHash, txHash)
+	assert.Equal(t, len(results.Receipts[0].Errs), 0)
+
+	score, err := cardinal.GetComponent[ScoreComponent](fork.Context(), id)
+	assert.NilError(t, err)
+	assert.Equal(t, score.Score, 15)
+	_, err = cardinal.Create(fork.Context(), ScoreComponent{})
+	assert.NilError(t, err)
+
+	// The world neither sees the fork's state changes, nor executes its transactions.
+	tickBeforeFork := world.CurrentTick()
+	tf.DoTick()
+	score, err = cardinal.GetComponent[ScoreComponent](wCtx, id)
+	assert.NilError(t, err)
+	assert.Equal(t, score.Score, 10)
+	count, err := cardinal.NewSearch().Entity(filter.All()).Count(wCtx)
+	assert.NilError(t, err)
+	assert.Equal(t, count, 1)
+	receipts, err := world.GetTransactionReceiptsForTick(tickBeforeFork)
+	assert.NilError(t, err)
+	assert.Equal(t, len(receipts), 0)
+
+	assert.NilError(t, fork.Discard())
+	_, err = fork.Tick()
+	assert.Check(t, eris.Is(err, cardinal.ErrForkDiscarded))
+}
+
+func TestForkPersonasAreNotSeenByTheWorld(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	tf.StartWorld()
+	tf.CreatePersona("alice", "alice-signer")
+	createPersonaMsg, exists := world.GetMessageByFullName("persona." + msg.CreatePersonaMessageName)
+	assert.True(t, exists)
+
+	fork, err := world.Fork()
+	assert.NilError(t, err)
+	fork.AddTransaction(createPersonaMsg.ID(), msg.CreatePersona{PersonaTag: "bob", SignerAddress: "fork-signer"},
+		&sign.Transaction{})
+	results, err := fork.Tick()
+	assert.NilError(t, err)
+	assert.Equal(t, len(results.Receipts), 1)
+	assert.Equal(t, len(results.Receipts[0].Errs), 0)
+
+	// The persona created in the fork is neither found by the world, nor does it keep the world from creating it.
+	tf.DoTick()
+	_, err = world.GetSignerForPersonaTag("bob", 0)
+	assert.ErrorIs(t, err, persona.ErrPersonaTagHasNoSigner)
+	tf.CreatePersona("bob", "world-signer")
+	addr, err := world.GetSignerForPersonaTag("bob", 0)
+	assert.NilError(t, err)
+	assert.Equal(t, addr, "world-signer")
+	addr, err = world.GetSignerForPersonaTag("alice", 0)
+	assert.NilError(t, err)
+	assert.Equal(t, addr, "alice-signer")
+
+	assert.NilError(t, fork.Discard())
+}
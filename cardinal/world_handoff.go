@@ -0,0 +1,134 @@
+package cardinal
+
+import (
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/server"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+const (
+	// DefaultTxQueueCapacity is how many accepted transactions may wait for the next tick before AddTransaction
+	// blocks; see WithTxQueueCapacity.
+	DefaultTxQueueCapacity = 1 << 14
+	// broadcastQueueCapacity is how many ticks the broadcaster may fall behind before it is considered stalled.
+	broadcastQueueCapacity = 64
+)
+
+// The tick runs on the game loop's goroutine, and never shares a lock with the goroutines that accept transactions
+// and broadcast tick results. Accepted transactions are handed off to the tick through a bounded queue, which the
+// tick drains into its own pool when it starts. Tick results are handed off to the broadcaster as snapshots that the
+// tick no longer changes.
+
+// reserveTxQueueSlot waits until there is room in the queue for the transaction. It returns false if the world has
+// shut down, in which case no tick drains the queue anymore and the transaction is dropped.
+func (w *World) reserveTxQueueSlot(txData txpool.TxData) bool {
+	select {
+	case w.txQueueSlots <- struct{}{}:
+		return true
+	case <-w.worldStage.NotifyOnStage(worldstage.ShutDown):
+		log.Warn().Msgf("dropped transaction %s that was added after the world shut down", txData.TxHash)
+		return false
+	}
+}
+
+// drainTxQueue moves the transactions that were handed off since the last tick into the pool, in the order they were
+// accepted, and frees their slots. It must only be called by the goroutine that ticks.
+func (w *World) drainTxQueue() {
+	for {
+		select {
+		case txData := <-w.txQueue:
+			w.txPool.Add(txData)
+			<-w.txQueueSlots
+		default:
+			return
+		}
+	}
+}
+
+// broadcaster sends tick results to the websocket clients on its own goroutine, so that a slow client never delays a
+// tick. Priority events are broadcast in their own frames, through a lane that is always drained before the queued
+// results of earlier ticks.
+type broadcaster struct {
+	server   *server.Server
+	results  chan TickResults
+	priority chan TickResults
+	done     chan struct{}
+}
+
+func startBroadcaster(s *server.Server) *broadcaster {
+	b := &broadcaster{
+		server:   s,
+		results:  make(chan TickResults, broadcastQueueCapacity),
+		priority: make(chan TickResults, broadcastQueueCapacity),
+		done:     make(chan struct{}),
+	}
+	broadcast := func(results TickResults) {
+		if err := s.BroadcastEvent(&results); err != nil {
+			log.Err(err).Msgf("failed to broadcast tick results")
+		}
+	}
+	go func() {
+		defer close(b.done)
+		results, priority := b.results, b.priority
+		for results != nil || priority != nil {
+			select {
+			case r, ok := <-priority:
+				if !ok {
+					priority = nil
+					continue
+				}
+				broadcast(r)
+				continue
+			default:
+			}
+			select {
+			case r, ok := <-priority:
+				if !ok {
+					priority = nil
+					continue
+				}
+				broadcast(r)
+			case r, ok := <-results:
+				if !ok {
+					results = nil
+					continue
+				}
+				broadcast(r)
+			}
+		}
+	}()
+	return b
+}
+
+// publish hands the results of a tick off to the broadcaster. The results must not be changed afterward. It never
+// blocks the tick: if the broadcaster has stalled, the results are dropped and the event clients are disconnected, so
+// that they reconnect and catch up on the tick log rather than silently miss the tick. The priority and bulk frames
+// of a tick are handed off together or not at all. publish must only be called by the goroutine that ticks.
+func (b *broadcaster) publish(results TickResults) {
+	bulk, priority := results.splitPriority()
+	// The broadcaster only ever takes results out of the lanes, so as long as the goroutine that ticks is the only one
+	// that publishes, a lane that has room now still has room when it is sent to below.
+	if len(b.results) == cap(b.results) || (priority != nil && len(b.priority) == cap(b.priority)) {
+		b.stalled(results.Tick)
+		return
+	}
+	if priority != nil {
+		b.priority <- *priority
+	}
+	b.results <- bulk
+}
+
+func (b *broadcaster) stalled(tick uint64) {
+	log.Warn().Uint64("tick", tick).
+		Msg("dropped tick results because the broadcaster fell behind; disconnecting event clients")
+	b.server.DisconnectEventClients()
+}
+
+// stop waits until the results that were already published are broadcast.
+func (b *broadcaster) stop() {
+	close(b.priority)
+	close(b.results)
+	<-b.done
+}
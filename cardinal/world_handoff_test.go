@@ -0,0 +1,126 @@
+package cardinal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+	"pkg.world.dev/world-engine/sign"
+)
+
+func TestFullTxQueueBlocksUntilTheNextTick(t *testing.T) {
+	rs := miniredis.RunT(t)
+	t.Setenv("REDIS_ADDRESS", rs.Addr())
+	ctx := context.Background()
+
+	world, err := NewWorld(WithPort(getOpenPort(t)), WithTickChannel(make(chan time.Time)), WithTxQueueCapacity(1))
+	assert.NilError(t, err)
+	assert.NilError(t, RegisterMessage[PowerComp, PowerComp](world, "change_power"))
+	var executed []float64
+	assert.NilError(t, RegisterSystems(world, func(wCtx engine.Context) error {
+		msg, err := getMessage[PowerComp, PowerComp](wCtx)
+		if err != nil {
+			return err
+		}
+		for _, tx := range msg.In(wCtx) {
+			executed = append(executed, tx.Msg.Val)
+		}
+		return nil
+	}))
+	go func() {
+		assert.NilError(t, world.StartGame())
+	}()
+	<-world.worldStage.NotifyOnStage(worldstage.Running)
+	t.Cleanup(func() { assert.NilError(t, world.Shutdown()) })
+
+	msg, ok := world.GetMessageByFullName("game.change_power")
+	assert.True(t, ok)
+	world.AddTransaction(msg.ID(), PowerComp{1}, &sign.Transaction{PersonaTag: "a"})
+
+	added := make(chan types.TxHash)
+	go func() {
+		_, hash := world.AddTransaction(msg.ID(), PowerComp{2}, &sign.Transaction{PersonaTag: "b"})
+		added <- hash
+	}()
+	select {
+	case <-added:
+		t.Fatal("the transaction was added to a full queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// The tick takes the first transaction, which makes room for the second one.
+	world.tickTheEngine(ctx, nil)
+	<-added
+	world.tickTheEngine(ctx, nil)
+	assert.DeepEqual(t, executed, []float64{1, 2})
+}
+
+func TestSystemsAddTransactionsWithoutWaitingForTheQueue(t *testing.T) {
+	rs := miniredis.RunT(t)
+	t.Setenv("REDIS_ADDRESS", rs.Addr())
+	ctx := context.Background()
+
+	world, err := NewWorld(WithPort(getOpenPort(t)), WithTickChannel(make(chan time.Time)), WithTxQueueCapacity(1))
+	assert.NilError(t, err)
+	assert.NilError(t, RegisterMessage[PowerComp, PowerComp](world, "change_power"))
+	var executed []float64
+	assert.NilError(t, RegisterSystems(world, func(wCtx engine.Context) error {
+		msg, err := getMessage[PowerComp, PowerComp](wCtx)
+		if err != nil {
+			return err
+		}
+		for _, tx := range msg.In(wCtx) {
+			executed = append(executed, tx.Msg.Val)
+		}
+		if wCtx.CurrentTick() == 1 {
+			// More transactions than the queue holds; the tick would wait for itself if they went through the queue.
+			for i := range 3 {
+				wCtx.AddTransaction(msg.ID(), PowerComp{float64(i)}, &sign.Transaction{PersonaTag: "a", Nonce: uint64(i)})
+			}
+		}
+		return nil
+	}))
+	go func() {
+		assert.NilError(t, world.StartGame())
+	}()
+	<-world.worldStage.NotifyOnStage(worldstage.Running)
+	t.Cleanup(func() { assert.NilError(t, world.Shutdown()) })
+
+	for range 3 {
+		world.tickTheEngine(ctx, nil)
+	}
+	assert.DeepEqual(t, executed, []float64{0, 1, 2})
+}
+
+func TestPublishDropsTheWholeTickWhenALaneIsFull(t *testing.T) {
+	rs := miniredis.RunT(t)
+	t.Setenv("REDIS_ADDRESS", rs.Addr())
+
+	world, err := NewWorld(WithPort(getOpenPort(t)), WithTickChannel(make(chan time.Time)))
+	assert.NilError(t, err)
+	go func() {
+		assert.NilError(t, world.StartGame())
+	}()
+	<-world.worldStage.NotifyOnStage(worldstage.Running)
+	t.Cleanup(func() { assert.NilError(t, world.Shutdown()) })
+
+	// A broadcaster without its goroutine never drains the lanes, like one that has stalled.
+	b := &broadcaster{
+		server:   world.server,
+		results:  make(chan TickResults, 1),
+		priority: make(chan TickResults, 1),
+	}
+	b.publish(TickResults{Tick: 1})
+	b.publish(TickResults{Tick: 2, PriorityEvents: [][]byte{[]byte(`{"type":"your-turn"}`)}})
+
+	// The bulk lane was full, so the priority frame of tick 2 must not be broadcast without the rest of the tick.
+	assert.Equal(t, len(b.priority), 0)
+	assert.Equal(t, len(b.results), 1)
+	assert.Equal(t, (<-b.results).Tick, uint64(1))
+}
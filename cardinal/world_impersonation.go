@@ -0,0 +1,64 @@
+package cardinal
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+	"pkg.world.dev/world-engine/sign"
+)
+
+var ErrNotImpersonationSigner = errors.New("signer is not allowed to impersonate personas")
+
+// AdminSigners returns the admin signer addresses of the world; see WithAdminSigners.
+func (w *World) AdminSigners() []string {
+	return w.adminSigners
+}
+
+// ImpersonationSigners returns the admin signer addresses that may sign transactions on behalf of any persona, or nil
+// if impersonation is disabled.
+func (w *World) ImpersonationSigners() []string {
+	if w.impersonationDisabled {
+		return nil
+	}
+	return w.adminSigners
+}
+
+// AddImpersonatedTransaction adds a transaction that an admin signer signed on behalf of the transaction's persona.
+// The impersonation is written to the audit log when the transaction is accepted, and recorded in the transaction's
+// receipt when it is executed. The caller must have verified that the admin signer signed the transaction.
+func (w *World) AddImpersonatedTransaction(id types.MessageID, v any, sig *sign.Transaction, adminSigner string) (
+	tick uint64, txHash types.TxHash, err error,
+) {
+	if !slices.Contains(w.ImpersonationSigners(), adminSigner) {
+		return 0, "", eris.Wrapf(ErrNotImpersonationSigner, "signer %s", adminSigner)
+	}
+	tick, txHash = w.addTransaction(txpool.TxData{MsgID: id, Msg: v, Tx: sig, Impersonator: adminSigner})
+
+	ev := log.Info().
+		Bool("audit", true).
+		Str("admin_signer", adminSigner).
+		Str("persona_tag", sig.PersonaTag).
+		Str("tx_hash", string(txHash)).
+		Uint64("tick", tick)
+	if msg, ok := w.GetMessageByID(id); ok {
+		ev = ev.Str("message", msg.FullName())
+	}
+	ev.Msg("admin signer submitted a transaction on behalf of a persona")
+	return tick, txHash, nil
+}
+
+// recordImpersonations records the admin signers of the impersonated transactions of the tick in their receipts.
+func (w *World) recordImpersonations(txPool *txpool.TxPool) {
+	for _, txs := range txPool.Transactions() {
+		for _, tx := range txs {
+			if tx.Impersonator != "" {
+				w.receiptHistory.SetImpersonator(tx.TxHash, tx.Impersonator)
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package cardinal
+
+import (
+	"reflect"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// RegisterIndex registers an index of the field with the given name of the component T, which must already be
+// registered. Searches with a filter.Eq condition on the field look up the matching entities in the index, instead of
+// checking every entity with T:
+//
+//	err := cardinal.RegisterIndex[component.SignerComponent](world, "PersonaTag")
+//	// ...in a system:
+//	ids, err := cardinal.NewSearch().Entity(filter.Eq[component.SignerComponent]("PersonaTag", tag)).Collect(wCtx)
+//
+// The index is kept in memory, and is built from the state the first time it is searched. It is updated by every
+// change to the state, so searches in a system see the changes made earlier in the same tick. Searches in read only
+// contexts, such as queries, don't use the index.
+func RegisterIndex[T types.Component](w *World, field string) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"engine state is %s, expected %s to register index",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	var t T
+	c, err := w.GetComponentByName(t.Name())
+	if err != nil {
+		return eris.Wrapf(err, "component %q must be registered before its fields are indexed", t.Name())
+	}
+	typ := reflect.TypeOf(t)
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return eris.Errorf("can't index field %q of component %q, which is not a struct", field, t.Name())
+	}
+	if f, ok := typ.FieldByName(field); !ok || !f.IsExported() {
+		return eris.Errorf("component %q has no exported field %q", t.Name(), field)
+	}
+	name := t.Name() + "." + field
+	if w.fieldIndexes[name] {
+		return eris.Errorf("index %s is already registered", name)
+	}
+	if w.fieldIndexes == nil {
+		w.fieldIndexes = map[string]bool{}
+	}
+	w.fieldIndexes[name] = true
+	w.entityStore.AddFieldIndex(c, field)
+	return nil
+}
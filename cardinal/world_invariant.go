@@ -0,0 +1,104 @@
+package cardinal
+
+import (
+	"errors"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/statsd"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// EventInvariantViolated is emitted in every tick whose state violates an invariant, once per violated invariant.
+const EventInvariantViolated = "invariant-violated"
+
+var ErrInvariantViolated = errors.New("invariant violated")
+
+// WorldReader is a read-only context, which can search and get components, but not change the state.
+type WorldReader = engine.Context
+
+// invariant is a check of the state that must pass after every tick; see RegisterInvariant.
+type invariant struct {
+	name  string
+	check func(WorldReader) error
+}
+
+// invariantConfig is how invariants are checked; see WithStrictInvariants and WithInvariantHalt.
+type invariantConfig struct {
+	strict bool
+	halt   bool
+}
+
+// RegisterInvariant registers a check of the state that must pass after every tick, as a safety net against bugs
+// that corrupt the state, such as an item that is in two inventories at once. The check returns an error that
+// describes the violation. Invariants are checked in the order they were registered, after the tick is committed, or
+// before it is committed with WithStrictInvariants.
+//
+// A violation is logged, emitted as an EventInvariantViolated event, and counted in the invariant_violations metric,
+// tagged with the invariant's name. With WithInvariantHalt, it also stops the world.
+func (w *World) RegisterInvariant(name string, check func(WorldReader) error) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"engine state is %s, expected %s to register invariants",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	if name == "" {
+		return eris.New("invariants must have a name")
+	}
+	if check == nil {
+		return eris.Errorf("invariant %q must have a check", name)
+	}
+	for _, inv := range w.invariants {
+		if inv.name == name {
+			return eris.Errorf("invariant %q is already registered", name)
+		}
+	}
+	w.invariants = append(w.invariants, invariant{name: name, check: check})
+	return nil
+}
+
+// readOnlyPendingContext reads the state that the tick is about to commit, but can't change it.
+type readOnlyPendingContext struct {
+	engine.Context
+}
+
+func (readOnlyPendingContext) IsReadOnly() bool {
+	return true
+}
+
+// checkInvariants checks the invariants, if they are checked at the given point of the tick. It returns an error if
+// an invariant is violated and the world halts on violations.
+func (w *World) checkInvariants(beforeCommit bool) error {
+	if len(w.invariants) == 0 || w.invariantConfig.strict != beforeCommit {
+		return nil
+	}
+	reader := NewReadOnlyWorldContext(w)
+	if beforeCommit {
+		reader = readOnlyPendingContext{NewWorldContext(w)}
+	}
+	for _, inv := range w.invariants {
+		err := inv.check(reader)
+		if err == nil {
+			continue
+		}
+		log.Error().Err(err).Uint64("tick", w.CurrentTick()).Str("invariant", inv.name).Msg("invariant violated")
+		if err := statsd.Client().Count("invariant_violations", 1, []string{"invariant:" + inv.name}, 1); err != nil {
+			log.Warn().Msgf("failed to emit count stat:%v", err)
+		}
+		if addErr := w.tickResults.AddEvent(map[string]any{
+			"type":      EventInvariantViolated,
+			"invariant": inv.name,
+			"error":     err.Error(),
+		}); addErr != nil {
+			return addErr
+		}
+		if w.invariantConfig.halt {
+			return eris.Wrapf(ErrInvariantViolated, "invariant %q: %s", inv.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package cardinal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// newInvariantWorld starts a world whose system increments the power of an entity every tick, and whose invariant is
+// that the power stays below 3.
+func newInvariantWorld(t *testing.T, opts ...WorldOption) (*World, types.EntityID, *[]string) {
+	rs := miniredis.RunT(t)
+	t.Setenv("REDIS_ADDRESS", rs.Addr())
+
+	var events []string
+	opts = append(opts, WithPort(getOpenPort(t)), WithHooks(Hooks{OnTickEnd: func(results TickResults) {
+		for _, event := range results.Events {
+			events = append(events, string(event))
+		}
+	}}))
+	world, err := NewWorld(opts...)
+	assert.NilError(t, err)
+	assert.NilError(t, RegisterComponent[onePowerComponent](world))
+
+	var id types.EntityID
+	assert.NilError(t, RegisterSystems(world, func(wCtx engine.Context) error {
+		return UpdateComponent[onePowerComponent](wCtx, id, func(p *onePowerComponent) *onePowerComponent {
+			p.Power++
+			return p
+		})
+	}))
+	assert.NilError(t, world.RegisterInvariant("power-below-3", func(r WorldReader) error {
+		p, err := GetComponent[onePowerComponent](r, id)
+		if err != nil {
+			return err
+		}
+		if p.Power >= 3 {
+			return eris.Errorf("power is %d", p.Power)
+		}
+		return nil
+	}))
+
+	go func() {
+		assert.NilError(t, world.StartGame())
+	}()
+	<-world.worldStage.NotifyOnStage(worldstage.Running)
+	t.Cleanup(func() { assert.NilError(t, world.Shutdown()) })
+
+	id, err = Create(NewWorldContext(world), onePowerComponent{})
+	assert.NilError(t, err)
+	return world, id, &events
+}
+
+func TestInvariantViolationsAreEmitted(t *testing.T) {
+	ctx := context.Background()
+	world, id, events := newInvariantWorld(t)
+
+	world.tickTheEngine(ctx, nil)
+	world.tickTheEngine(ctx, nil)
+	assert.Equal(t, len(*events), 0)
+
+	// The violation doesn't stop the world.
+	world.tickTheEngine(ctx, nil)
+	assert.DeepEqual(t, *events, []string{
+		`{"error":"power is 3","invariant":"power-below-3","type":"invariant-violated"}`,
+	})
+	p, err := GetComponent[onePowerComponent](NewReadOnlyWorldContext(world), id)
+	assert.NilError(t, err)
+	assert.Equal(t, p.Power, 3)
+}
+
+func TestStrictInvariantsHaltBeforeCommit(t *testing.T) {
+	ctx := context.Background()
+	world, id, _ := newInvariantWorld(t, WithStrictInvariants(), WithInvariantHalt())
+
+	world.tickTheEngine(ctx, nil)
+	world.tickTheEngine(ctx, nil)
+	err := doTickCapturePanic(ctx, world)
+	assert.ErrorContains(t, err, ErrInvariantViolated.Error())
+
+	// The state that violates the invariant was never committed.
+	p, err := GetComponent[onePowerComponent](NewReadOnlyWorldContext(world), id)
+	assert.NilError(t, err)
+	assert.Equal(t, p.Power, 2)
+}
+
+func TestRegisterInvariantRejectsDuplicates(t *testing.T) {
+	world, err := NewWorld(WithMockRedis())
+	assert.NilError(t, err)
+	check := func(WorldReader) error { return nil }
+	assert.NilError(t, world.RegisterInvariant("a", check))
+	assert.ErrorContains(t, world.RegisterInvariant("a", check), "already registered")
+	assert.ErrorContains(t, world.RegisterInvariant("", check), "must have a name")
+}
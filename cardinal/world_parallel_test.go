@@ -0,0 +1,159 @@
+package cardinal_test
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/sign"
+)
+
+type HealMsg struct {
+	Amount int
+	Spawn  bool
+}
+
+type HealResult struct {
+	Value int
+}
+
+func TestParallelMessagesRunPerPersonaWithDeterministicResults(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterMessage[HealMsg, HealResult](world, "heal",
+		message.WithParallelExecution[HealMsg, HealResult]()))
+
+	personas := []string{"a", "b", "c", "d", "e"}
+	owned := map[string]types.EntityID{}
+	assert.NilError(t, cardinal.RegisterInitSystems(world, func(wCtx engine.Context) error {
+		for _, personaTag := range personas {
+			id, err := cardinal.Create(wCtx, Health{})
+			if err != nil {
+				return err
+			}
+			owned[personaTag] = id
+		}
+		return nil
+	}))
+	var events [][]byte
+	assert.NilError(t, cardinal.RegisterSystems(world,
+		func(wCtx engine.Context) error {
+			return cardinal.EachMessageParallel[HealMsg, HealResult](wCtx,
+				func(pCtx engine.Context, tx message.TxData[HealMsg]) (HealResult, error) {
+					if tx.Msg.Spawn {
+						_, err := cardinal.Create(pCtx, Health{})
+						return HealResult{}, err
+					}
+					id := owned[tx.Tx.PersonaTag]
+					health, err := cardinal.GetComponent[Health](pCtx, id)
+					if err != nil {
+						return HealResult{}, err
+					}
+					health.Value += tx.Msg.Amount
+					if err := cardinal.SetComponent[Health](pCtx, id, health); err != nil {
+						return HealResult{}, err
+					}
+					return HealResult{Value: health.Value}, pCtx.EmitStringEvent(string(tx.Hash))
+				})
+		},
+		func(wCtx engine.Context) error {
+			events = wCtx.EmittedEvents()
+			return nil
+		},
+	))
+	tf.StartWorld()
+
+	heal, ok := world.GetMessageByFullName("game.heal")
+	assert.True(t, ok)
+	var hashes []types.TxHash
+	for _, personaTag := range personas {
+		for amount := 1; amount <= 3; amount++ {
+			sig := &sign.Transaction{PersonaTag: personaTag, Nonce: uint64(amount)}
+			hashes = append(hashes, tf.AddTransaction(heal.ID(), HealMsg{Amount: amount}, sig))
+		}
+	}
+	spawnHash := tf.AddTransaction(heal.ID(), HealMsg{Spawn: true}, &sign.Transaction{PersonaTag: "a", Nonce: 4})
+	tf.DoTick()
+
+	receipts, err := world.GetTransactionReceiptsForTick(world.CurrentTick() - 1)
+	assert.NilError(t, err)
+	results := map[types.TxHash]any{}
+	for _, rec := range receipts {
+		if rec.TxHash == spawnHash {
+			assert.Equal(t, len(rec.Errs), 1)
+			assert.ErrorIs(t, rec.Errs[0], cardinal.ErrNotParallelSafe)
+			continue
+		}
+		assert.Equal(t, len(rec.Errs), 0)
+		results[rec.TxHash] = rec.Result
+	}
+	// The transactions of each persona ran in the order they were submitted.
+	for i, hash := range hashes {
+		assert.Equal(t, results[hash], HealResult{Value: []int{1, 3, 6}[i%3]}, fmt.Sprint(i))
+	}
+
+	// Events were emitted in the order of the transaction hashes.
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+	assert.Equal(t, len(events), len(hashes))
+	for i, event := range events {
+		assert.Equal(t, types.TxHash(event), hashes[i])
+	}
+}
+
+func TestParallelMessagesOfPersonasThatWriteTheSameEntityRunAgainInTurn(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterMessage[HealMsg, HealResult](world, "heal",
+		message.WithParallelExecution[HealMsg, HealResult]()))
+
+	var shared types.EntityID
+	assert.NilError(t, cardinal.RegisterInitSystems(world, func(wCtx engine.Context) error {
+		var err error
+		shared, err = cardinal.Create(wCtx, Health{})
+		return err
+	}))
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		return cardinal.EachMessageParallel[HealMsg, HealResult](wCtx,
+			func(pCtx engine.Context, tx message.TxData[HealMsg]) (HealResult, error) {
+				health, err := cardinal.GetComponent[Health](pCtx, shared)
+				if err != nil {
+					return HealResult{}, err
+				}
+				health.Value += tx.Msg.Amount
+				return HealResult{Value: health.Value}, cardinal.SetComponent[Health](pCtx, shared, health)
+			})
+	}))
+	tf.StartWorld()
+
+	heal, ok := world.GetMessageByFullName("game.heal")
+	assert.True(t, ok)
+	hashes := []types.TxHash{
+		tf.AddTransaction(heal.ID(), HealMsg{Amount: 1}, &sign.Transaction{PersonaTag: "a", Nonce: 1}),
+		tf.AddTransaction(heal.ID(), HealMsg{Amount: 2}, &sign.Transaction{PersonaTag: "b", Nonce: 1}),
+		tf.AddTransaction(heal.ID(), HealMsg{Amount: 4}, &sign.Transaction{PersonaTag: "a", Nonce: 2}),
+	}
+	tf.DoTick()
+
+	// Neither persona's writes were lost: the transactions ran as if persona a's ran before persona b's.
+	health, err := cardinal.GetComponent[Health](cardinal.NewReadOnlyWorldContext(world), shared)
+	assert.NilError(t, err)
+	assert.Equal(t, health.Value, 7)
+	receipts, err := world.GetTransactionReceiptsForTick(world.CurrentTick() - 1)
+	assert.NilError(t, err)
+	results := map[types.TxHash]any{}
+	for _, rec := range receipts {
+		assert.Equal(t, len(rec.Errs), 0)
+		results[rec.TxHash] = rec.Result
+	}
+	for i, hash := range hashes {
+		assert.Equal(t, results[hash], HealResult{Value: []int{1, 7, 5}[i]}, fmt.Sprint(i))
+	}
+}
@@ -1,76 +1,348 @@
 package cardinal
 
 import (
-	"errors"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/rotisserie/eris"
 
+	"pkg.world.dev/world-engine/cardinal/codec"
 	"pkg.world.dev/world-engine/cardinal/persona"
 	"pkg.world.dev/world-engine/cardinal/persona/component"
 	"pkg.world.dev/world-engine/cardinal/search"
 	"pkg.world.dev/world-engine/cardinal/search/filter"
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
 	"pkg.world.dev/world-engine/cardinal/types"
 )
 
 // GetSignerForPersonaTag returns the signer address that has been registered for the given persona tag after the
 // given tick. If the engine's tick is less than or equal to the given tick, ErrorCreatePersonaTXsNotProcessed is
-// returned. If the given personaTag has no signer address, ErrPersonaTagHasNoSigner is returned.
+// returned. If the given personaTag has no signer address, ErrPersonaTagHasNoSigner is returned, or
+// ErrPersonaTagPending if the tag is held for a signer until the name policy approves it. The signer of an alias is
+// the signer of its primary persona.
 func (w *World) GetSignerForPersonaTag(personaTag string, tick uint64) (addr string, err error) {
 	if tick >= w.CurrentTick() {
 		return "", persona.ErrCreatePersonaTxsNotProcessed
 	}
-	var errs []error
+	entry, ok, err := w.lookupPersona(personaTag)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		if w.isPersonaTagPending(personaTag) {
+			return "", persona.ErrPersonaTagPending
+		}
+		return "", persona.ErrPersonaTagHasNoSigner
+	}
+	if entry.AliasOf != "" {
+		return w.GetSignerForPersonaTag(entry.AliasOf, tick)
+	}
+	return entry.SignerAddress, nil
+}
+
+// GetPersonaEntity returns the ID of the entity that holds the signer component of the persona tag, as of the last
+// committed tick. The entity of an alias is its own, not its primary's. If the persona tag has no signer address,
+// ErrPersonaTagHasNoSigner is returned.
+func (w *World) GetPersonaEntity(personaTag string) (types.EntityID, error) {
+	if w.CurrentTick() == 0 {
+		return 0, persona.ErrCreatePersonaTxsNotProcessed
+	}
+	entry, ok, err := w.lookupPersona(personaTag)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, eris.Wrapf(persona.ErrPersonaTagHasNoSigner, "persona tag %q", personaTag)
+	}
+	return entry.EntityID, nil
+}
+
+// lookupPersona returns the index entry of the persona tag as of the last committed tick. Like the ECS lookups it
+// replaces, it only finds the persona tag as it was registered, with the same case. Until the index is built in the
+// first tick after a restart, entries are read from the index that was saved with the state.
+func (w *World) lookupPersona(personaTag string) (personaIndexEntry, bool, error) {
+	lowerPersona := strings.ToLower(personaTag)
+	entry, ok, built := w.personaIndex.get(lowerPersona)
+	if !built {
+		bz, err := w.entityStore.GetIndexEntry(personaIndexName, lowerPersona)
+		if err != nil || bz == nil {
+			return personaIndexEntry{}, false, err
+		}
+		if entry, err = codec.Decode[personaIndexEntry](bz); err != nil {
+			return personaIndexEntry{}, false, err
+		}
+		ok = true
+	}
+	return entry, ok && entry.PersonaTag == personaTag, nil
+}
+
+// isPersonaTagPending reports whether the persona tag is held for a signer until the name policy approves it.
+func (w *World) isPersonaTagPending(personaTag string) bool {
+	pending := false
 	wCtx := NewReadOnlyWorldContext(w)
-	s := search.NewSearch().Entity(filter.Exact(filter.Component[component.SignerComponent]()))
-	err = s.Each(wCtx,
+	s := search.NewSearch().Entity(filter.Exact(filter.Component[component.PersonaReservationComponent]()))
+	err := s.Each(wCtx,
 		func(id types.EntityID) bool {
-			sc, err := GetComponent[component.SignerComponent](wCtx, id)
+			r, err := GetComponent[component.PersonaReservationComponent](wCtx, id)
 			if err != nil {
-				errs = append(errs, err)
-			}
-			if sc != nil && sc.PersonaTag == personaTag {
-				addr = sc.SignerAddress
-				return false
+				return true
 			}
-			return true
+			pending = r.AwaitingApproval && r.IsHeldAt(w.CurrentTick()) && strings.EqualFold(r.PersonaTag, personaTag)
+			return !pending
 		},
 	)
-	errs = append(errs, err)
-	if addr == "" {
-		return "", persona.ErrPersonaTagHasNoSigner
+	return err == nil && pending
+}
+
+// GetValidSignersForPersonaTag returns every address that may currently sign transactions for the given persona tag.
+// The current signer is always first; a signer that was rotated out is included until its grace period elapses.
+func (w *World) GetValidSignersForPersonaTag(personaTag string) ([]string, error) {
+	if w.CurrentTick() == 0 {
+		return nil, persona.ErrCreatePersonaTxsNotProcessed
 	}
-	return addr, errors.Join(errs...)
+	sc, err := w.GetSignerComponentForPersona(personaTag)
+	if err != nil {
+		return nil, eris.Wrap(persona.ErrPersonaTagHasNoSigner, err.Error())
+	}
+	return sc.ValidSignerAddresses(w.CurrentTick()), nil
+}
+
+// GetSessionKeySignersForPersonaTag returns the addresses of the session keys of the given persona tag that may
+// currently sign the message with the given full name. Session keys never sign the messages that manage the persona.
+func (w *World) GetSessionKeySignersForPersonaTag(personaTag, msgName string) ([]string, error) {
+	if w.CurrentTick() == 0 {
+		return nil, persona.ErrCreatePersonaTxsNotProcessed
+	}
+	if isPersonaManagementMessage(msgName) {
+		return nil, nil
+	}
+	sc, err := w.GetSignerComponentForPersona(personaTag)
+	if err != nil {
+		return nil, eris.Wrap(persona.ErrPersonaTagHasNoSigner, err.Error())
+	}
+	return sc.SessionKeySigners(msgName, w.CurrentTick(), uint64(w.timeSource.Now().Unix())), nil
 }
 
+// GetSignerComponentForPersona returns the signer component of the given persona tag. The signer component of an alias
+// is the signer component of its primary persona.
 func (w *World) GetSignerComponentForPersona(personaTag string) (*component.SignerComponent, error) {
-	var sc *component.SignerComponent
-	wCtx := NewReadOnlyWorldContext(w)
-	q := search.NewSearch().Entity(filter.Exact(filter.Component[component.SignerComponent]()))
-	var getComponentErr error
-	searchIterationErr := eris.Wrap(
-		q.Each(wCtx,
-			func(id types.EntityID) bool {
-				var signerComp *component.SignerComponent
-				signerComp, getComponentErr = GetComponent[component.SignerComponent](wCtx, id)
-				if getComponentErr != nil {
-					return false
-				}
-				if signerComp.PersonaTag == personaTag {
-					sc = signerComp
-					return false
-				}
-				return true
-			},
-		), "",
-	)
-	if getComponentErr != nil {
-		return nil, getComponentErr
+	sc, err := w.findSignerComponent(personaTag)
+	if err != nil {
+		return nil, err
 	}
-	if searchIterationErr != nil {
-		return nil, searchIterationErr
+	if sc.AliasOf != "" {
+		return w.findSignerComponent(sc.AliasOf)
 	}
-	if sc == nil {
+	return sc, nil
+}
+
+// ResolvePersonaTag returns the persona tag of the primary persona of the given persona tag, which is the given persona
+// tag itself unless it is an alias. If the given persona tag has no signer address, ErrPersonaTagHasNoSigner is
+// returned.
+func (w *World) ResolvePersonaTag(personaTag string) (string, error) {
+	sc, err := w.findSignerComponent(personaTag)
+	if err != nil {
+		return "", eris.Wrap(persona.ErrPersonaTagHasNoSigner, err.Error())
+	}
+	if sc.AliasOf != "" {
+		return sc.AliasOf, nil
+	}
+	return sc.PersonaTag, nil
+}
+
+// findSignerComponent returns the signer component of the given persona tag, without resolving aliases.
+func (w *World) findSignerComponent(personaTag string) (*component.SignerComponent, error) {
+	entry, ok, err := w.lookupPersona(personaTag)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
 		return nil, eris.Errorf("persona tag %q not found", personaTag)
 	}
-	return sc, nil
+	return GetComponent[component.SignerComponent](NewReadOnlyWorldContext(w), entry.EntityID)
+}
+
+// PersonaTagExists reports whether the persona tag, or an alias by that name, has been registered, ignoring case. It
+// looks the tag up in the persona index of the last committed tick, so it is cheap enough to call as a user types a
+// name, and can be called while a tick is running. If no tick has been committed since the world started,
+// ErrCreatePersonaTxsNotProcessed is returned.
+func (w *World) PersonaTagExists(personaTag string) (bool, error) {
+	lowerPersona := strings.ToLower(personaTag)
+	_, ok, built := w.personaIndex.get(lowerPersona)
+	if built {
+		return ok, nil
+	}
+	if w.CurrentTick() == 0 {
+		return false, persona.ErrCreatePersonaTxsNotProcessed
+	}
+	bz, err := w.entityStore.GetIndexEntry(personaIndexName, lowerPersona)
+	return bz != nil, err
+}
+
+// maxPersonaListLimit is the most personas that ListPersonas returns at once.
+const maxPersonaListLimit = 1000
+
+// ListPersonas returns up to limit personas whose tags start with the prefix, ignoring case, ordered by their
+// lowercase tags, as of the last committed tick. A trailing "*" in the prefix is ignored, so "al*" and "al" are the
+// same search. The personas after the cursor are returned, and next is the cursor of the following page, or empty if
+// there are no more personas. A limit that isn't positive, or is above 1000, is 1000. If no tick has been committed
+// since the world started, ErrCreatePersonaTxsNotProcessed is returned.
+func (w *World) ListPersonas(prefix, cursor string, limit int) ([]servertypes.PersonaSummary, string, error) {
+	if limit <= 0 || limit > maxPersonaListLimit {
+		limit = maxPersonaListLimit
+	}
+	entries, next, built := w.personaIndex.list(
+		strings.ToLower(strings.TrimSuffix(prefix, "*")), strings.ToLower(cursor), limit,
+	)
+	if !built {
+		return nil, "", persona.ErrCreatePersonaTxsNotProcessed
+	}
+	personas := make([]servertypes.PersonaSummary, 0, len(entries))
+	for _, entry := range entries {
+		personas = append(personas, servertypes.PersonaSummary{
+			PersonaTag:    entry.PersonaTag,
+			SignerAddress: entry.SignerAddress,
+			AliasOf:       entry.AliasOf,
+		})
+	}
+	return personas, next, nil
+}
+
+// personaIndexName is the name of the lookup index that the persona index is saved in.
+const personaIndexName = "persona"
+
+// committedPersonaIndex is a copy of the live persona index as of the last committed tick, which can be read from
+// any goroutine. The persona systems stage their changes to the index during a tick, the world saves the changes with
+// the tick, and publishes them once the tick is committed.
+type committedPersonaIndex struct {
+	mu      sync.RWMutex
+	entries personaIndex
+	// sorted are the lowercase persona tags of the entries in order, which list seeks in.
+	sorted []string
+
+	// staged are the lowercase persona tags whose entries changed in the running tick. If rebuilt is set, the index
+	// was rebuilt from the state instead, and the whole index is saved and copied.
+	staged  []string
+	rebuilt bool
+}
+
+// get returns the entry of the lowercase persona tag. built is false if the index hasn't been built since the world
+// started.
+func (s *committedPersonaIndex) get(lowerPersona string) (entry personaIndexEntry, ok, built bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.entries == nil {
+		return personaIndexEntry{}, false, false
+	}
+	entry, ok = s.entries[lowerPersona]
+	return entry, ok, true
+}
+
+// list returns up to limit entries whose lowercase persona tags start with the prefix and come after the cursor, in
+// order, and the cursor of the next page. built is false if the index hasn't been built since the world started.
+func (s *committedPersonaIndex) list(
+	prefix, cursor string, limit int,
+) (entries []personaIndexEntry, next string, built bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.entries == nil {
+		return nil, "", false
+	}
+	i, _ := slices.BinarySearch(s.sorted, prefix)
+	if cursor >= prefix {
+		var found bool
+		if i, found = slices.BinarySearch(s.sorted, cursor); found {
+			i++
+		}
+	}
+	for ; i < len(s.sorted) && strings.HasPrefix(s.sorted[i], prefix); i++ {
+		if len(entries) == limit {
+			next = s.sorted[i-1]
+			break
+		}
+		entries = append(entries, s.entries[s.sorted[i]])
+	}
+	return entries, next, true
+}
+
+func (s *committedPersonaIndex) stage(lowerPersona string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.staged = append(s.staged, lowerPersona)
+}
+
+func (s *committedPersonaIndex) stageRebuild() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.staged, s.rebuilt = nil, true
+}
+
+// changes returns the encoded entries of the staged persona tags in the live index, to be saved with the tick. It
+// must be called from the goroutine that runs the tick, which owns the live index.
+func (s *committedPersonaIndex) changes(live personaIndex) (map[string][]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	staged := s.staged
+	if s.rebuilt || s.entries == nil {
+		staged = make([]string, 0, len(live))
+		for lowerPersona := range live {
+			staged = append(staged, lowerPersona)
+		}
+	}
+	changes := make(map[string][]byte, len(staged))
+	for _, lowerPersona := range staged {
+		entry, ok := live[lowerPersona]
+		if !ok {
+			continue
+		}
+		bz, err := codec.Encode(entry)
+		if err != nil {
+			return nil, err
+		}
+		changes[lowerPersona] = bz
+	}
+	return changes, nil
+}
+
+// commit publishes the staged changes of the live index. It must be called from the goroutine that runs the tick,
+// which owns the live index.
+func (s *committedPersonaIndex) commit(live personaIndex) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rebuilt || s.entries == nil {
+		s.entries = make(personaIndex, len(live))
+		s.sorted = make([]string, 0, len(live))
+		for lowerPersona, entry := range live {
+			s.entries[lowerPersona] = entry
+			s.sorted = append(s.sorted, lowerPersona)
+		}
+		slices.Sort(s.sorted)
+	}
+	for _, lowerPersona := range s.staged {
+		entry, ok := live[lowerPersona]
+		i, found := slices.BinarySearch(s.sorted, lowerPersona)
+		switch {
+		case ok && !found:
+			s.sorted = slices.Insert(s.sorted, i, lowerPersona)
+		case !ok && found:
+			s.sorted = slices.Delete(s.sorted, i, i+1)
+		}
+		if ok {
+			s.entries[lowerPersona] = entry
+		} else {
+			delete(s.entries, lowerPersona)
+		}
+	}
+	s.staged, s.rebuilt = nil, false
+}
+
+// savePersonaIndex stages the changes to the persona index of the running tick, so that they are saved with it.
+func (w *World) savePersonaIndex() error {
+	changes, err := w.personaIndex.changes(w.livePersonaIndex.entries)
+	if err != nil {
+		return err
+	}
+	w.entityStore.SetIndexEntries(personaIndexName, changes)
+	return nil
 }
@@ -8,8 +8,10 @@ import (
 
 	"pkg.world.dev/world-engine/assert"
 	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/persona"
 	"pkg.world.dev/world-engine/cardinal/persona/component"
 	"pkg.world.dev/world-engine/cardinal/persona/msg"
+	"pkg.world.dev/world-engine/cardinal/receipt"
 	"pkg.world.dev/world-engine/cardinal/search"
 	"pkg.world.dev/world-engine/cardinal/search/filter"
 	"pkg.world.dev/world-engine/cardinal/testutils"
@@ -43,6 +45,77 @@ func TestGetSignerComponentForPersona(t *testing.T) {
 	assert.Nil(t, sc)
 }
 
+func TestGetPersonaEntityUsesSavedIndexAfterRestart(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	msgType, exists := world.GetMessageByFullName("persona.create-persona")
+	assert.True(t, exists)
+	world.AddTransaction(msgType.ID(), msg.CreatePersona{PersonaTag: "tyler", SignerAddress: "foobar"},
+		&sign.Transaction{})
+	tf.DoTick()
+
+	id, err := world.GetPersonaEntity("tyler")
+	assert.NilError(t, err)
+	sc, err := cardinal.GetComponent[component.SignerComponent](cardinal.NewReadOnlyWorldContext(world), id)
+	assert.NilError(t, err)
+	assert.Equal(t, sc.PersonaTag, "tyler")
+	_, err = world.GetPersonaEntity("Tyler")
+	assert.ErrorIs(t, err, persona.ErrPersonaTagHasNoSigner)
+
+	// After a restart, personas are found in the saved index before the first tick rebuilds it.
+	tf = testutils.NewTestFixture(t, tf.Redis)
+	tf.StartWorld()
+	restartedID, err := tf.World.GetPersonaEntity("tyler")
+	assert.NilError(t, err)
+	assert.Equal(t, restartedID, id)
+	addr, err := tf.World.GetSignerForPersonaTag("tyler", 0)
+	assert.NilError(t, err)
+	assert.Equal(t, addr, "foobar")
+	exists, err = tf.World.PersonaTagExists("TYLER")
+	assert.NilError(t, err)
+	assert.True(t, exists)
+}
+
+func TestListPersonasPagesThroughPersonasCreatedInLaterTicks(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	msgType, exists := world.GetMessageByFullName("persona.create-persona")
+	assert.True(t, exists)
+	createPersonas := func(tags ...string) {
+		for _, tag := range tags {
+			world.AddTransaction(msgType.ID(), msg.CreatePersona{PersonaTag: tag, SignerAddress: "foobar"},
+				&sign.Transaction{})
+		}
+		tf.DoTick()
+	}
+	listTags := func(prefix, cursor string, limit int) ([]string, string) {
+		personas, next, err := world.ListPersonas(prefix, cursor, limit)
+		assert.NilError(t, err)
+		tags := make([]string, 0, len(personas))
+		for _, p := range personas {
+			tags = append(tags, p.PersonaTag)
+		}
+		return tags, next
+	}
+
+	createPersonas("Bob", "alfred")
+	createPersonas("Alice", "Carol")
+	createPersonas("Albert")
+
+	tags, next := listTags("al", "", 2)
+	assert.DeepEqual(t, tags, []string{"Albert", "alfred"})
+	tags, next = listTags("al", next, 2)
+	assert.DeepEqual(t, tags, []string{"Alice"})
+	assert.Equal(t, next, "")
+
+	// A cursor before the prefix starts at the prefix, and a limit that isn't positive lists up to the maximum.
+	tags, next = listTags("b", "a", 0)
+	assert.DeepEqual(t, tags, []string{"Bob"})
+	assert.Equal(t, next, "")
+	tags, _ = listTags("", "alice", 0)
+	assert.DeepEqual(t, tags, []string{"Bob", "Carol"})
+}
+
 func TestCreatePersonaSystem_WithNoPersonaTagCreateTxs_TickShouldBeFast(t *testing.T) {
 	tf := testutils.NewTestFixture(t, nil)
 
@@ -149,17 +222,35 @@ func TestCreatePersonaSystem_WhenCardinalIsRestarted_PersonaTagsAreStillRegister
 
 	// This persona tag has already been registered, so it should fail to register this time.
 	repeatPersonaTag := "pt5"
-	tf.World.AddTransaction(msgType.ID(), msg.CreatePersona{
-		PersonaTag:    repeatPersonaTag,
-		SignerAddress: "some-sa",
-	}, &sign.Transaction{})
-	tf.DoTick()
+	createPersona := func(signerAddress string) (receipt.Receipt, msg.CreatePersonaResult) {
+		tf.World.AddTransaction(msgType.ID(), msg.CreatePersona{
+			PersonaTag:    repeatPersonaTag,
+			SignerAddress: signerAddress,
+		}, &sign.Transaction{})
+		tf.DoTick()
+		receipts, err := tf.World.GetTransactionReceiptsForTick(tf.World.CurrentTick() - 1)
+		assert.NilError(t, err)
+		assert.Len(t, receipts, 1)
+		result, ok := receipts[0].Result.(msg.CreatePersonaResult)
+		assert.True(t, ok)
+		return receipts[0], result
+	}
 
 	// Make sure the receipt from the previous tick talks about the failed persona tag registration.
-	receipts, err := tf.World.GetTransactionReceiptsForTick(tf.World.CurrentTick() - 1)
-	assert.NilError(t, err)
-	assert.Len(t, receipts, 1)
-	errs := receipts[0].Errs
-	assert.Len(t, errs, 1)
-	assert.ErrorContains(t, errs[0], "persona tag pt5 has already been registered")
+	rec, taken := createPersona("some-sa")
+	assert.False(t, taken.Success)
+	assert.Equal(t, taken.Code, msg.CodeTagAlreadyRegistered)
+	assert.Contains(t, taken.Error, "persona tag pt5")
+	assert.Len(t, rec.Errs, 1)
+	assert.DeepEqual(t, tf.World.ErrorCodes().Detail(rec.Errs[0]), receipt.ErrorDetail{
+		Code:    msg.CodeTagAlreadyRegistered,
+		Params:  map[string]any{"personaTag": repeatPersonaTag},
+		Message: "the persona tag has already been registered",
+	})
+
+	// The signer that the tag is registered to can retry the registration, which succeeds without registering it again.
+	rec, retried := createPersona("sa5")
+	assert.True(t, retried.Success)
+	assert.Equal(t, retried.Code, "")
+	assert.Len(t, rec.Errs, 0)
 }
@@ -0,0 +1,89 @@
+package cardinal
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/statsd"
+	"pkg.world.dev/world-engine/cardinal/system"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// postCommitStage runs read-only systems, such as analytics, projections or leaderboard recomputes, after a tick has
+// been committed. They run in the background while the next tick's systems run, and read the state of the tick that
+// was just committed: the next tick waits for them before it commits its own state, so that storage doesn't change
+// underneath them.
+type postCommitStage struct {
+	systems *system.Manager
+	running sync.WaitGroup
+}
+
+func newPostCommitStage() *postCommitStage {
+	return &postCommitStage{systems: system.NewManager()}
+}
+
+// snapshotContext is a read-only context that keeps reporting the tick and timestamp of the committed snapshot while
+// the world moves on to the next tick.
+type snapshotContext struct {
+	engine.Context
+	tick      uint64
+	timestamp uint64
+}
+
+func (ctx *snapshotContext) CurrentTick() uint64 {
+	return ctx.tick
+}
+
+func (ctx *snapshotContext) Timestamp() uint64 {
+	return ctx.timestamp
+}
+
+// RegisterPostCommitSystems registers read-only systems that run after each tick is committed, off the critical path
+// of the tick loop. The systems get a read-only context, so they can search and get components but not change state,
+// emit events, or add transactions. An error is logged, and doesn't stop the world.
+//
+// The systems of a tick run concurrently with the systems of the next tick, and the next tick only waits for them
+// before it commits, so a post-commit stage that takes longer than a tick's systems still delays the tick loop.
+func RegisterPostCommitSystems(w *World, sys ...system.System) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"engine state is %s, expected %s to register post-commit systems",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	return w.postCommit.systems.RegisterSystems(sys...)
+}
+
+// startPostCommitSystems runs the post-commit systems against the state that was just committed.
+func (w *World) startPostCommitSystems() {
+	if len(w.postCommit.systems.GetRegisteredSystemNames()) == 0 {
+		return
+	}
+	wCtx := &snapshotContext{
+		Context:   NewReadOnlyWorldContext(w),
+		tick:      w.CurrentTick(),
+		timestamp: w.timestamp.Load(),
+	}
+	w.postCommit.running.Add(1)
+	go func() {
+		defer w.postCommit.running.Done()
+		startTime := time.Now()
+		if err := w.postCommit.systems.RunSystems(wCtx); err != nil {
+			log.Error().Err(err).Uint64("tick", wCtx.tick).
+				Msgf("post-commit systems failed: %s", eris.ToString(err, true))
+		}
+		statsd.EmitTickStat(startTime, "post_commit")
+	}()
+}
+
+// waitForPostCommitSystems blocks until the post-commit systems of the previous tick are done.
+func (w *World) waitForPostCommitSystems() {
+	startTime := time.Now()
+	w.postCommit.running.Wait()
+	statsd.EmitTickStat(startTime, "post_commit_wait")
+}
@@ -0,0 +1,89 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func TestPostCommitSystemsRunConcurrentlyWithTheNextTick(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[CounterComponent](world))
+
+	type snapshot struct {
+		tick      uint64
+		count     int
+		getErr    error
+		createErr error
+	}
+	var id types.EntityID
+	systemRan := make(chan uint64, 10)
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		defer func() { systemRan <- wCtx.CurrentTick() }()
+		if wCtx.CurrentTick() == 0 {
+			var err error
+			id, err = cardinal.Create(wCtx, CounterComponent{Count: 1})
+			return err
+		}
+		return cardinal.UpdateComponent[CounterComponent](wCtx, id, func(c *CounterComponent) *CounterComponent {
+			c.Count++
+			return c
+		})
+	}))
+
+	snapshots := make(chan snapshot, 10)
+	release := make(chan struct{})
+	assert.NilError(t, cardinal.RegisterPostCommitSystems(world, func(wCtx engine.Context) error {
+		var s snapshot
+		s.tick = wCtx.CurrentTick()
+		var c *CounterComponent
+		if c, s.getErr = cardinal.GetComponent[CounterComponent](wCtx, id); s.getErr == nil {
+			s.count = c.Count
+		}
+		_, s.createErr = cardinal.Create(wCtx, CounterComponent{})
+		snapshots <- s
+		<-release
+		return nil
+	}))
+
+	tf.DoTick()
+	assert.Equal(t, <-systemRan, uint64(0))
+	first := <-snapshots
+	assert.NilError(t, first.getErr)
+	assert.Equal(t, first.tick, uint64(1))
+	assert.Equal(t, first.count, 1)
+	assert.ErrorIs(t, first.createErr, cardinal.ErrEntityMutationOnReadOnly)
+
+	// The next tick's systems run while the post-commit systems of the last tick are still running, but the tick
+	// doesn't commit until they are done.
+	tickDone := make(chan struct{})
+	go func() {
+		tf.DoTick()
+		close(tickDone)
+	}()
+	assert.Equal(t, <-systemRan, uint64(1))
+	select {
+	case <-tickDone:
+		t.Fatal("tick committed before the post-commit systems of the previous tick were done")
+	default:
+	}
+	close(release)
+	<-tickDone
+
+	second := <-snapshots
+	assert.NilError(t, second.getErr)
+	assert.Equal(t, second.tick, uint64(2))
+	assert.Equal(t, second.count, 2)
+}
+
+func TestPostCommitSystemsMustBeRegisteredBeforeStart(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	tf.StartWorld()
+	err := cardinal.RegisterPostCommitSystems(tf.World, func(engine.Context) error { return nil })
+	assert.ErrorContains(t, err, "to register post-commit systems")
+}
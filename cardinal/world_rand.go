@@ -0,0 +1,19 @@
+package cardinal
+
+import (
+	"crypto/sha256"
+	"strconv"
+
+	"pkg.world.dev/world-engine/cardinal/rng"
+)
+
+// newSystemRand returns the random number generator of the system at the given position in the tick. Code that runs
+// outside of a system, such as queries and tx middleware, is at position -1.
+func (w *World) newSystemRand(tick uint64, index int) *rng.RNG {
+	seed := w.randomSeed
+	if seed == "" {
+		seed = w.Namespace()
+	}
+	seed += "/" + strconv.FormatUint(tick, 10) + "/" + strconv.Itoa(index)
+	return rng.New(sha256.Sum256([]byte(seed)))
+}
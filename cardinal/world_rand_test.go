@@ -0,0 +1,48 @@
+package cardinal_test
+
+import (
+	"slices"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+// rollDice runs a world with two systems that roll a die twice every tick, and returns the rolls of each system.
+func rollDice(t *testing.T, ticks int, opts ...cardinal.WorldOption) (first, second []int) {
+	tf := testutils.NewTestFixture(t, nil, opts...)
+	roll := func(wCtx engine.Context) []int {
+		return []int{wCtx.Rand().Intn(6) + 1, wCtx.Rand().Intn(6) + 1}
+	}
+	assert.NilError(t, cardinal.RegisterSystems(tf.World,
+		func(wCtx engine.Context) error {
+			first = append(first, roll(wCtx)...)
+			return nil
+		},
+		func(wCtx engine.Context) error {
+			second = append(second, roll(wCtx)...)
+			return nil
+		},
+	))
+	for range ticks {
+		tf.DoTick()
+	}
+	return first, second
+}
+
+func TestSystemRandIsDeterministic(t *testing.T) {
+	first, second := rollDice(t, 5, cardinal.WithRandomSeed("dice"))
+	replayFirst, replaySecond := rollDice(t, 5, cardinal.WithRandomSeed("dice"))
+	assert.DeepEqual(t, first, replayFirst)
+	assert.DeepEqual(t, second, replaySecond)
+	for _, r := range first {
+		assert.Check(t, r >= 1 && r <= 6)
+	}
+
+	// Every system, and every seed, draws other numbers.
+	assert.Check(t, !slices.Equal(first, second))
+	otherFirst, _ := rollDice(t, 5, cardinal.WithRandomSeed("other dice"))
+	assert.Check(t, !slices.Equal(first, otherFirst))
+}
@@ -15,6 +15,20 @@ func (w *World) GetTransactionReceiptsForTick(tick uint64) ([]receipt.Receipt, e
 	return w.receiptHistory.GetReceiptsForTick(tick)
 }
 
+// ReceiptsRoot returns the root of the Merkle tree of the tick's receipts, which can be published with the state hash,
+// so that anyone can verify the receipt proofs of the tick's transactions; see receipt.VerifyProof.
+func (w *World) ReceiptsRoot(tick uint64) ([]byte, error) {
+	receipts, err := w.GetTransactionReceiptsForTick(tick)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := receipt.NewTree(receipts)
+	if err != nil {
+		return nil, err
+	}
+	return tree.Root(), nil
+}
+
 // ConsumeEVMMsgResult consumes a tx result from an EVM originated Cardinal message.
 // It will fetch the receipt from the map, and then delete ('consume') it from the map.
 func (w *World) ConsumeEVMMsgResult(evmTxHash string) ([]byte, []error, string, bool) {
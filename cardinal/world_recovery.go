@@ -2,13 +2,22 @@ package cardinal
 
 import (
 	"context"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
 
 	"pkg.world.dev/world-engine/cardinal/router/iterator"
+	servertypes "pkg.world.dev/world-engine/cardinal/server/types"
+	"pkg.world.dev/world-engine/cardinal/statsd"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
 )
 
+// recoveryReportInterval is how often recovery progress is logged while the world is recovering.
+const recoveryReportInterval = 5 * time.Second
+
 // recoverAndExecutePendingTxs checks whether the last tick is successfully completed. If not, it will recover
 // the pending transactions.
 func (w *World) recoverAndExecutePendingTxs() error {
@@ -17,6 +26,7 @@ func (w *World) recoverAndExecutePendingTxs() error {
 		return err
 	}
 	w.tick.Store(end)
+	w.recovery.begin(end)
 	// We successfully completed the last tick. Everything is fine
 	if start == end {
 		return nil
@@ -29,11 +39,12 @@ func (w *World) recoverAndExecutePendingTxs() error {
 
 	// If there is recovered transactions, we need to reprocess them
 	if recoveredTxs != nil {
+		w.recovery.setTarget(end + 1)
 		w.txPool = recoveredTxs
 		// TODO(scott): this is hacky, but i dont want to fix this now because it's PR scope creep.
 		//  but we ideally don't want to treat this as a special tick and should just let it execute normally
 		//  from the game loop.
-		if err = w.doTick(context.Background(), uint64(time.Now().Unix())); err != nil {
+		if err = w.doTick(context.Background(), uint64(w.timeSource.Now().Unix())); err != nil {
 			return err
 		}
 	}
@@ -54,6 +65,7 @@ func (w *World) RecoverFromChain(ctx context.Context) error {
 
 	start := w.CurrentTick()
 	err := w.router.TransactionIterator().Each(func(batches []*iterator.TxBatch, tick, timestamp uint64) error {
+		w.recovery.setTarget(tick + 1)
 		for w.CurrentTick() != tick {
 			if err := w.doTick(ctx, timestamp); err != nil {
 				return eris.Wrap(err, "failed to tick engine")
@@ -61,7 +73,7 @@ func (w *World) RecoverFromChain(ctx context.Context) error {
 		}
 
 		for _, batch := range batches {
-			w.AddTransaction(batch.MsgID, batch.MsgValue, batch.Tx)
+			w.addTransactionToPool(txpool.TxData{MsgID: batch.MsgID, Msg: batch.MsgValue, Tx: batch.Tx})
 		}
 
 		if err := w.doTick(ctx, timestamp); err != nil {
@@ -74,3 +86,111 @@ func (w *World) RecoverFromChain(ctx context.Context) error {
 	}
 	return nil
 }
+
+// recoveryTracker records the progress of startup recovery so that operators can tell whether a world is still
+// recovering or has stalled. Progress is logged and emitted as statsd gauges while recovering, and is available
+// afterward via World.RecoveryStatus.
+type recoveryTracker struct {
+	mu         sync.RWMutex
+	startTick  uint64
+	current    uint64
+	target     uint64
+	startedAt  time.Time
+	finishedAt time.Time
+	lastReport time.Time
+}
+
+func (r *recoveryTracker) begin(tick uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.startTick, r.current, r.target = tick, tick, tick
+	r.startedAt = time.Now()
+	r.finishedAt = time.Time{}
+	r.lastReport = r.startedAt
+}
+
+func (r *recoveryTracker) setTarget(tick uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.target = max(r.target, tick)
+}
+
+func (r *recoveryTracker) progress(tick uint64) {
+	r.mu.Lock()
+	r.current = tick
+	r.target = max(r.target, tick)
+	target := r.target
+	report := time.Since(r.lastReport) >= recoveryReportInterval
+	if report {
+		r.lastReport = time.Now()
+	}
+	r.mu.Unlock()
+
+	emitRecoveryGauge("recovery.current_tick", tick)
+	emitRecoveryGauge("recovery.target_tick", target)
+	if report {
+		s := r.status()
+		ev := log.Info().Uint64("tick", s.CurrentTick).Uint64("target_tick", s.TargetTick).
+			Float64("ticks_per_second", s.TicksPerSecond)
+		if s.EstimatedCompletion != nil {
+			ev = ev.Time("estimated_completion", *s.EstimatedCompletion)
+		}
+		ev.Msg("Recovery in progress")
+	}
+}
+
+func (r *recoveryTracker) finish(tick uint64) {
+	r.mu.Lock()
+	r.current = tick
+	r.target = max(r.target, tick)
+	r.finishedAt = time.Now()
+	recovered := r.current - r.startTick
+	elapsed := r.finishedAt.Sub(r.startedAt)
+	r.mu.Unlock()
+
+	emitRecoveryGauge("recovery.current_tick", tick)
+	log.Info().Uint64("tick", tick).Uint64("recovered_ticks", recovered).Dur("duration", elapsed).
+		Msg("Recovery complete, world is live")
+	if err := statsd.Client().SimpleEvent("world live",
+		"recovered "+strconv.FormatUint(recovered, 10)+" ticks in "+elapsed.String()); err != nil {
+		log.Warn().Msgf("failed to emit world live event: %v", err)
+	}
+}
+
+func (r *recoveryTracker) status() servertypes.RecoveryStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s := servertypes.RecoveryStatus{
+		Recovering:  !r.startedAt.IsZero() && r.finishedAt.IsZero(),
+		StartTick:   r.startTick,
+		CurrentTick: r.current,
+		TargetTick:  r.target,
+	}
+	if r.startedAt.IsZero() {
+		return s
+	}
+	startedAt := r.startedAt
+	s.StartedAt = &startedAt
+
+	end := time.Now()
+	if !r.finishedAt.IsZero() {
+		finishedAt := r.finishedAt
+		s.CompletedAt = &finishedAt
+		end = finishedAt
+	}
+	if elapsed := end.Sub(r.startedAt).Seconds(); elapsed > 0 {
+		s.TicksPerSecond = float64(r.current-r.startTick) / elapsed
+	}
+	if s.Recovering && s.TicksPerSecond > 0 && r.target > r.current {
+		remaining := time.Duration(float64(r.target-r.current) / s.TicksPerSecond * float64(time.Second))
+		eta := end.Add(remaining)
+		s.EstimatedCompletion = &eta
+	}
+	return s
+}
+
+func emitRecoveryGauge(name string, tick uint64) {
+	if err := statsd.Client().Gauge(name, float64(tick), nil, 1); err != nil {
+		log.Warn().Msgf("failed to emit %s gauge: %v", name, err)
+	}
+}
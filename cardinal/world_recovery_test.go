@@ -97,6 +97,14 @@ func TestWorldRecovery(t *testing.T) {
 				// Check that tick 0 is run with the timestamp from recovery data
 				g.Assert(cardinal.NewWorldContext(world).Timestamp()).Equal(timestamp)
 
+				// Check that recovery is reported as complete once the world is live
+				status := world.RecoveryStatus()
+				g.Assert(status.Recovering).IsFalse()
+				g.Assert(status.StartTick).Equal(uint64(0))
+				g.Assert(status.CurrentTick).Equal(uint64(1))
+				g.Assert(status.TargetTick).Equal(uint64(1))
+				g.Assert(status.CompletedAt == nil).IsFalse()
+
 				controller.Finish()
 			})
 		})
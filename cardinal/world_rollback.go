@@ -0,0 +1,162 @@
+package cardinal
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+	"pkg.world.dev/world-engine/sign"
+)
+
+// EventRollback is emitted in the first tick that is replayed after the world rolled back to add late transactions.
+// Clients should replace what they know about the replayed ticks with the tick results that follow it.
+const EventRollback = "world-rollback"
+
+var ErrTickNotRewindable = errors.New("tick is outside of the rollback window")
+
+// tickInput is what a tick needs to be executed again.
+type tickInput struct {
+	tick      uint64
+	timestamp uint64
+	txs       *txpool.TxPool
+}
+
+type lateTransaction struct {
+	tick uint64
+	id   types.MessageID
+	msg  any
+	sig  *sign.Transaction
+}
+
+// rollbackHistory keeps the inputs of the most recent ticks, and the late transactions that they must be replayed
+// with. A nil rollbackHistory keeps nothing.
+type rollbackHistory struct {
+	limit int
+
+	mu     sync.Mutex
+	inputs []tickInput
+	late   []lateTransaction
+}
+
+func newRollbackHistory(ticks int) *rollbackHistory {
+	if ticks <= 0 {
+		return nil
+	}
+	return &rollbackHistory{limit: ticks}
+}
+
+func (h *rollbackHistory) record(tick, timestamp uint64, txs *txpool.TxPool) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.inputs = append(h.inputs, tickInput{tick: tick, timestamp: timestamp, txs: txs})
+	if len(h.inputs) > h.limit {
+		h.inputs = h.inputs[len(h.inputs)-h.limit:]
+	}
+}
+
+// takeReplay returns the ticks that must be replayed to add the late transactions, starting at the earliest tick that
+// a late transaction was added to. The late transactions are added to the returned inputs, which are removed from
+// the history.
+func (h *rollbackHistory) takeReplay() []tickInput {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	late := h.late
+	h.late = nil
+	if len(late) == 0 || len(h.inputs) == 0 {
+		return nil
+	}
+
+	oldest := h.inputs[0].tick
+	from := h.inputs[len(h.inputs)-1].tick + 1
+	for _, tx := range late {
+		from = min(from, tx.tick)
+	}
+	if from < oldest {
+		// ApplyLateTransaction checks the window, which only moves when a tick is recorded after the replay.
+		log.Error().Msgf("dropping late transactions for tick %d, which is no longer in the rollback window", from)
+		from = oldest
+	}
+	replay := h.inputs[from-oldest:]
+	h.inputs = h.inputs[:from-oldest]
+	for _, tx := range late {
+		if tx.tick >= from {
+			replay[tx.tick-from].txs.AddTransaction(tx.id, tx.msg, tx.sig)
+		}
+	}
+	return replay
+}
+
+// ApplyLateTransaction adds an authoritative transaction, such as one that the base shard sequenced late, to a past
+// tick. At the start of the next tick, the world rolls back its state to the start of the given tick, and replays
+// that tick and every tick after it, with the transaction included. The corrected results of the replayed ticks are
+// broadcast to clients again, after an EventRollback event. Replaying a tick must give the same result each time
+// except for the late transactions, so systems must be deterministic.
+//
+// Transactions for the current tick are added to it as usual. Ticks that are further in the past than the window of
+// WithRollback can't be changed.
+func (w *World) ApplyLateTransaction(tick uint64, id types.MessageID, msg any, sig *sign.Transaction) (
+	types.TxHash, error,
+) {
+	if w.rollback == nil {
+		return "", eris.New("rollback is disabled; see WithRollback")
+	}
+	if _, ok := w.GetMessageByID(id); !ok {
+		return "", eris.Errorf("message with id %d is not registered", id)
+	}
+	if tick >= w.CurrentTick() {
+		_, txHash := w.addTransactionToPool(txpool.TxData{MsgID: id, Msg: msg, Tx: sig})
+		return txHash, nil
+	}
+
+	h := w.rollback
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.inputs) == 0 || tick < h.inputs[0].tick {
+		return "", eris.Wrapf(ErrTickNotRewindable, "tick %d", tick)
+	}
+	h.late = append(h.late, lateTransaction{tick: tick, id: id, msg: msg, sig: sig})
+	return types.TxHash(sig.HashHex()), nil
+}
+
+// replayLateTransactions rolls the world back to the earliest tick that late transactions were added to, and
+// replays the ticks since then with the late transactions.
+func (w *World) replayLateTransactions(ctx context.Context) error {
+	replay := w.rollback.takeReplay()
+	if len(replay) == 0 {
+		return nil
+	}
+	from, to := replay[0].tick, w.CurrentTick()
+	// The post-commit systems of the last tick are still reading the state that is about to be rolled back.
+	w.waitForPostCommitSystems()
+	if err := w.entityStore.Rewind(len(replay)); err != nil {
+		return eris.Wrapf(err, "failed to roll back to tick %d", from)
+	}
+	w.tick.Store(from)
+	w.receiptHistory.Rewind(from)
+	w.contentIDs.invalidate()
+	w.livePersonaIndex.invalidate()
+	w.tickResults.Clear()
+	log.Info().Msgf("Rolled back to tick %d to replay %d ticks with late transactions", from, len(replay))
+
+	err := w.tickResults.AddEvent(map[string]any{"type": EventRollback, "fromTick": from, "toTick": to})
+	if err != nil {
+		return err
+	}
+	for _, input := range replay {
+		if err := w.executeTick(ctx, input.timestamp, input.txs, true); err != nil {
+			return eris.Wrapf(err, "failed to replay tick %d", input.tick)
+		}
+	}
+	return nil
+}
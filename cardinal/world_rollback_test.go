@@ -0,0 +1,74 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func TestLateTransactionsAreReplayedAfterRollback(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithRollback(3))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[ScoreComponent](world))
+	assert.NilError(t, cardinal.RegisterMessage[*ModifyScoreMsg, *EmptyMsgResult](world, "modify_score"))
+	var id types.EntityID
+	assert.NilError(t, cardinal.RegisterInitSystems(world, func(wCtx engine.Context) error {
+		var err error
+		id, err = cardinal.Create(wCtx, ScoreComponent{})
+		return err
+	}))
+	// Every tick doubles the score before the transactions are applied, so the result depends on when the late
+	// transaction is applied.
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		if err := cardinal.UpdateComponent[ScoreComponent](wCtx, id, func(s *ScoreComponent) *ScoreComponent {
+			s.Score *= 2
+			return s
+		}); err != nil {
+			return err
+		}
+		return cardinal.EachMessage[*ModifyScoreMsg, *EmptyMsgResult](wCtx,
+			func(tx message.TxData[*ModifyScoreMsg]) (*EmptyMsgResult, error) {
+				return &EmptyMsgResult{}, cardinal.UpdateComponent[ScoreComponent](wCtx, tx.Msg.PlayerID,
+					func(s *ScoreComponent) *ScoreComponent {
+						s.Score += tx.Msg.Amount
+						return s
+					})
+			})
+	}))
+	tf.DoTick()
+	wCtx := cardinal.NewReadOnlyWorldContext(world)
+	modifyScoreMsg, err := testutils.GetMessage[*ModifyScoreMsg, *EmptyMsgResult](wCtx)
+	assert.NilError(t, err)
+
+	lateTick := world.CurrentTick()
+	tf.DoTick()
+	tf.AddTransaction(modifyScoreMsg.ID(), &ModifyScoreMsg{PlayerID: id, Amount: 1}, testutils.UniqueSignature())
+	tf.DoTick()
+	score, err := cardinal.GetComponent[ScoreComponent](wCtx, id)
+	assert.NilError(t, err)
+	assert.Equal(t, score.Score, 1)
+
+	tick := world.CurrentTick()
+	_, err = world.ApplyLateTransaction(lateTick, modifyScoreMsg.ID(), &ModifyScoreMsg{PlayerID: id, Amount: 10},
+		testutils.UniqueSignature())
+	assert.NilError(t, err)
+	tf.DoTick()
+
+	// The late transaction was added to the tick before the last two ticks, which both doubled the score.
+	score, err = cardinal.GetComponent[ScoreComponent](wCtx, id)
+	assert.NilError(t, err)
+	assert.Equal(t, score.Score, ((10*2)+1)*2)
+	assert.Equal(t, world.CurrentTick(), tick+1)
+
+	// Only the last 3 ticks are kept.
+	_, err = world.ApplyLateTransaction(tick-3, modifyScoreMsg.ID(), &ModifyScoreMsg{PlayerID: id, Amount: 10},
+		testutils.UniqueSignature())
+	assert.Check(t, eris.Is(err, cardinal.ErrTickNotRewindable))
+}
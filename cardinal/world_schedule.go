@@ -0,0 +1,140 @@
+package cardinal
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/search"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+	"pkg.world.dev/world-engine/sign"
+)
+
+var _ Plugin = (*schedulePlugin)(nil)
+
+// ScheduledTxComponent is a transaction that a system scheduled to be executed in a later tick; see ScheduleTx. The
+// schedule is part of the world's state, so pending transactions survive restarts, and are executed again when ticks
+// are replayed.
+type ScheduledTxComponent struct {
+	AtTick uint64
+	// Message is the full name of the transaction's message.
+	Message    string
+	PersonaTag string
+	Body       json.RawMessage
+}
+
+func (ScheduledTxComponent) Name() string {
+	return "ScheduledTxComponent"
+}
+
+type schedulePlugin struct{}
+
+func newSchedulePlugin() *schedulePlugin {
+	return &schedulePlugin{}
+}
+
+func (p *schedulePlugin) Register(world *World) error {
+	if err := RegisterComponent[ScheduledTxComponent](world); err != nil {
+		return err
+	}
+	// The transactions that are due are looked up by their tick, instead of checking every scheduled transaction.
+	return RegisterIndex[ScheduledTxComponent](world, "AtTick")
+}
+
+// ScheduleTx schedules the message to be executed as a transaction of the persona at the given tick, and returns the
+// id of the entity that holds it, which can be passed to CancelScheduledTx. Like EachMessage, it's parameterized by
+// the message's input and output types, which must be registered, e.g.
+//
+//	cardinal.ScheduleTx[AttackMsg, AttackResult](wCtx, wCtx.CurrentTick()+10, "jeff", AttackMsg{Target: "mage"})
+//
+// The tick must be in the future. Scheduled transactions aren't signed, so the persona tag they are executed for must
+// be given explicitly. Systems see scheduled transactions as any other transaction of their tick, and their results
+// and errors end up in the tick's receipts.
+func ScheduleTx[In any, Out any](
+	wCtx engine.Context, atTick uint64, personaTag string, msg In,
+) (types.EntityID, error) {
+	if atTick <= wCtx.CurrentTick() {
+		return 0, eris.Errorf("can't schedule a transaction at tick %d in tick %d", atTick, wCtx.CurrentTick())
+	}
+	if personaTag == "" {
+		return 0, eris.New("can't schedule a transaction without a persona tag")
+	}
+	var msgType message.MessageType[In, Out]
+	registered, ok := wCtx.GetMessageByType(reflect.TypeOf(msgType))
+	if !ok {
+		return 0, eris.Errorf("Could not find %s, Message may not be registered.", msgType.Name())
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return 0, eris.Wrap(err, "failed to encode scheduled message")
+	}
+	return Create(wCtx, ScheduledTxComponent{
+		AtTick:     atTick,
+		Message:    registered.FullName(),
+		PersonaTag: personaTag,
+		Body:       body,
+	})
+}
+
+// CancelScheduledTx removes the transaction that was scheduled with ScheduleTx, so that it is not executed.
+func CancelScheduledTx(wCtx engine.Context, id types.EntityID) error {
+	if _, err := GetComponent[ScheduledTxComponent](wCtx, id); err != nil {
+		return eris.Wrapf(err, "entity %d is not a scheduled transaction", id)
+	}
+	return Remove(wCtx, id)
+}
+
+// withScheduledTxs takes the transactions that are due in the current tick off the schedule, and returns a copy of the
+// pool with them added, in the order they were scheduled. The pool is returned as is if nothing is due. Transactions
+// can only be scheduled for later ticks, and every tick takes those that are due, so none are ever overdue.
+func (w *World) withScheduledTxs(wCtx engine.Context, txPool *txpool.TxPool) (*txpool.TxPool, error) {
+	due, err := search.NewSearch().
+		Entity(filter.Eq[ScheduledTxComponent]("AtTick", wCtx.CurrentTick())).
+		Collect(wCtx)
+	if err != nil {
+		return nil, err
+	}
+	if len(due) == 0 {
+		return txPool, nil
+	}
+	slices.Sort(due)
+
+	pool := txPool.Filter(func(txpool.TxData) bool { return true })
+	for _, id := range due {
+		scheduled, err := GetComponent[ScheduledTxComponent](wCtx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err = Remove(wCtx, id); err != nil {
+			return nil, err
+		}
+		msgType, ok := w.GetMessageByFullName(scheduled.Message)
+		if !ok {
+			wCtx.Logger().Warn().Uint64("entity", uint64(id)).Str("message", scheduled.Message).
+				Msg("dropping scheduled transaction of an unknown message")
+			continue
+		}
+		msg, err := msgType.Decode(scheduled.Body)
+		if err != nil {
+			return nil, eris.Wrapf(err, "failed to decode scheduled transaction %d", id)
+		}
+		// Scheduled transactions aren't signed. The entity id is their nonce, so that each has its own hash.
+		pool.Add(txpool.TxData{
+			MsgID: msgType.ID(),
+			Msg:   msg,
+			Tx: &sign.Transaction{
+				PersonaTag: scheduled.PersonaTag,
+				Namespace:  w.Namespace(),
+				Nonce:      uint64(id),
+				Body:       scheduled.Body,
+			},
+		})
+	}
+	return pool, nil
+}
@@ -0,0 +1,63 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type ExplodeMsg struct {
+	Power int
+}
+
+type ExplodeResult struct{}
+
+func TestScheduledTxsRunAtTheirTick(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	assert.NilError(t, cardinal.RegisterMessage[ExplodeMsg, ExplodeResult](tf.World, "explode"))
+
+	var cancelled types.EntityID
+	exploded := map[uint64]int{}
+	var personaTag string
+	assert.NilError(t, cardinal.RegisterSystems(tf.World, func(wCtx engine.Context) error {
+		if wCtx.CurrentTick() == 1 {
+			if _, err := cardinal.ScheduleTx[ExplodeMsg, ExplodeResult](wCtx, 3, "jeff", ExplodeMsg{Power: 9}); err != nil {
+				return err
+			}
+			var err error
+			cancelled, err = cardinal.ScheduleTx[ExplodeMsg, ExplodeResult](wCtx, 3, "jeff", ExplodeMsg{Power: 1})
+			if err != nil {
+				return err
+			}
+			if _, err = cardinal.ScheduleTx[ExplodeMsg, ExplodeResult](wCtx, 1, "jeff", ExplodeMsg{}); err == nil {
+				t.Error("expected scheduling in the current tick to fail")
+			}
+			// Scheduled transactions are never executed for a persona that wasn't named, such as the system persona.
+			if _, err = cardinal.ScheduleTx[ExplodeMsg, ExplodeResult](wCtx, 3, "", ExplodeMsg{Power: 5}); err == nil {
+				t.Error("expected scheduling without a persona tag to fail")
+			}
+		}
+		if wCtx.CurrentTick() == 2 {
+			if err := cardinal.CancelScheduledTx(wCtx, cancelled); err != nil {
+				return err
+			}
+		}
+		return cardinal.EachMessage[ExplodeMsg, ExplodeResult](wCtx,
+			func(tx message.TxData[ExplodeMsg]) (ExplodeResult, error) {
+				exploded[wCtx.CurrentTick()] += tx.Msg.Power
+				personaTag = tx.Tx.PersonaTag
+				return ExplodeResult{}, nil
+			})
+	}))
+
+	for range 5 {
+		tf.DoTick()
+	}
+	assert.DeepEqual(t, exploded, map[uint64]int{3: 9})
+	assert.Equal(t, personaTag, "jeff")
+}
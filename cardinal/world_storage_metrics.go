@@ -0,0 +1,194 @@
+package cardinal
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/statsd"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+// StorageOpStats is how the storage operations of one kind, on one component, performed in a tick.
+type StorageOpStats struct {
+	Tick uint64 `json:"tick"`
+	// Op is the kind of operation, such as "get" or "set".
+	Op string `json:"op"`
+	// Component is the name of the component, or "" for operations on whole entities.
+	Component string        `json:"component"`
+	Count     int           `json:"count"`
+	Total     time.Duration `json:"total"`
+	Max       time.Duration `json:"max"`
+	// MaxSystem is the system that performed the slowest operation, or "" if no system was running.
+	MaxSystem string `json:"maxSystem"`
+	// Slow is the number of operations that took at least the slow operation threshold; see WithStorageMetrics.
+	Slow int `json:"slow"`
+}
+
+// StorageStats returns the stats of the storage operations of the most recent tick, sorted by operation and
+// component, if storage metrics are enabled; see WithStorageMetrics.
+func (w *World) StorageStats() []StorageOpStats {
+	if w.storageMetrics == nil {
+		return nil
+	}
+	w.storageMetrics.mu.Lock()
+	defer w.storageMetrics.mu.Unlock()
+	return slices.Clone(w.storageMetrics.report)
+}
+
+type storageOpKey struct {
+	op        string
+	component string
+}
+
+// storageMetrics times the storage operations of systems and tx middleware, and of committing ticks.
+type storageMetrics struct {
+	slowThreshold time.Duration
+	// currentSystem returns the name of the running system.
+	currentSystem func() string
+
+	mu     sync.Mutex
+	stats  map[storageOpKey]*StorageOpStats
+	report []StorageOpStats
+}
+
+func newStorageMetrics(slowThreshold time.Duration, currentSystem func() string) *storageMetrics {
+	return &storageMetrics{
+		slowThreshold: slowThreshold,
+		currentSystem: currentSystem,
+		stats:         map[storageOpKey]*StorageOpStats{},
+	}
+}
+
+// observe records an operation that started at the given time. cType is nil for operations on whole entities.
+func (m *storageMetrics) observe(
+	op string, cType types.ComponentMetadata, id types.EntityID, tick uint64, started time.Time,
+) {
+	took := time.Since(started)
+	component := ""
+	if cType != nil {
+		component = cType.Name()
+	}
+	system := m.currentSystem()
+
+	tags := []string{"op:" + op, "component:" + componentTag(component)}
+	if err := statsd.Client().Histogram("storage.op_ms", float64(took)/float64(time.Millisecond), tags, 1); err != nil {
+		log.Warn().Msgf("failed to emit histogram stat:%v", err)
+	}
+	slow := m.slowThreshold > 0 && took >= m.slowThreshold
+	if slow {
+		log.Warn().Uint64("tick", tick).Str("op", op).Str("component", componentTag(component)).
+			Uint64("entity", uint64(id)).Str("system", system).Dur("took", took).
+			Msg("slow storage operation")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := storageOpKey{op: op, component: component}
+	s, ok := m.stats[key]
+	if !ok {
+		s = &StorageOpStats{Op: op, Component: component}
+		m.stats[key] = s
+	}
+	s.Count++
+	s.Total += took
+	if took > s.Max {
+		s.Max, s.MaxSystem = took, system
+	}
+	if slow {
+		s.Slow++
+	}
+}
+
+// finish reports the stats of the tick, and starts over.
+func (m *storageMetrics) finish(tick uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.report = make([]StorageOpStats, 0, len(m.stats))
+	for _, s := range m.stats {
+		s.Tick = tick
+		m.report = append(m.report, *s)
+	}
+	slices.SortFunc(m.report, func(a, b StorageOpStats) int {
+		if c := strings.Compare(a.Op, b.Op); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Component, b.Component)
+	})
+	m.stats = map[storageOpKey]*StorageOpStats{}
+}
+
+// componentTag names the component in logs and metrics.
+func componentTag(component string) string {
+	if component == "" {
+		return "entity"
+	}
+	return component
+}
+
+// currentSystemName returns the name of the running system, or "" if no system is running.
+func (w *World) currentSystemName() string {
+	if w.systemManager.GetCurrentSystemIndex() < 0 {
+		return ""
+	}
+	return w.systemManager.GetCurrentSystem()
+}
+
+// metricsStore times the storage operations.
+type metricsStore struct {
+	gamestate.Manager
+	metrics *storageMetrics
+	tick    uint64
+}
+
+func (s *metricsStore) GetComponentForEntity(cType types.ComponentMetadata, id types.EntityID) (any, error) {
+	defer s.metrics.observe("get", cType, id, s.tick, time.Now())
+	return s.Manager.GetComponentForEntity(cType, id)
+}
+
+func (s *metricsStore) GetComponentForEntityInRawJSON(
+	cType types.ComponentMetadata, id types.EntityID,
+) (json.RawMessage, error) {
+	defer s.metrics.observe("get", cType, id, s.tick, time.Now())
+	return s.Manager.GetComponentForEntityInRawJSON(cType, id)
+}
+
+func (s *metricsStore) GetComponentTypesForEntity(id types.EntityID) ([]types.ComponentMetadata, error) {
+	defer s.metrics.observe("get_types", nil, id, s.tick, time.Now())
+	return s.Manager.GetComponentTypesForEntity(id)
+}
+
+func (s *metricsStore) CreateEntity(comps ...types.ComponentMetadata) (types.EntityID, error) {
+	defer s.metrics.observe("create", nil, 0, s.tick, time.Now())
+	return s.Manager.CreateEntity(comps...)
+}
+
+func (s *metricsStore) CreateManyEntities(num int, comps ...types.ComponentMetadata) ([]types.EntityID, error) {
+	defer s.metrics.observe("create", nil, 0, s.tick, time.Now())
+	return s.Manager.CreateManyEntities(num, comps...)
+}
+
+func (s *metricsStore) SetComponentForEntity(cType types.ComponentMetadata, id types.EntityID, value any) error {
+	defer s.metrics.observe("set", cType, id, s.tick, time.Now())
+	return s.Manager.SetComponentForEntity(cType, id, value)
+}
+
+func (s *metricsStore) AddComponentToEntity(cType types.ComponentMetadata, id types.EntityID) error {
+	defer s.metrics.observe("add", cType, id, s.tick, time.Now())
+	return s.Manager.AddComponentToEntity(cType, id)
+}
+
+func (s *metricsStore) RemoveComponentFromEntity(cType types.ComponentMetadata, id types.EntityID) error {
+	defer s.metrics.observe("remove", cType, id, s.tick, time.Now())
+	return s.Manager.RemoveComponentFromEntity(cType, id)
+}
+
+func (s *metricsStore) RemoveEntity(id types.EntityID) error {
+	defer s.metrics.observe("remove", nil, id, s.tick, time.Now())
+	return s.Manager.RemoveEntity(id)
+}
@@ -0,0 +1,51 @@
+package cardinal_test
+
+import (
+	"testing"
+	"time"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func TestStorageStats(t *testing.T) {
+	// Every operation takes at least a nanosecond, so all of them are slow.
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithStorageMetrics(time.Nanosecond))
+	assert.NilError(t, cardinal.RegisterComponent[HogState](tf.World))
+	assert.NilError(t, cardinal.RegisterSystems(tf.World, func(wCtx engine.Context) error {
+		id, err := cardinal.Create(wCtx, HogState{})
+		if err != nil {
+			return err
+		}
+		for i := range 3 {
+			if err = cardinal.SetComponent[HogState](wCtx, id, &HogState{Value: i}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	tf.DoTick()
+
+	stats := map[string]cardinal.StorageOpStats{}
+	for _, s := range tf.World.StorageStats() {
+		stats[s.Op+"/"+s.Component] = s
+	}
+	set, ok := stats["set/hog_state"]
+	assert.Check(t, ok)
+	// Creating the entity sets its initial value.
+	assert.Equal(t, set.Count, 4)
+	assert.Equal(t, set.Slow, 4)
+	assert.Check(t, set.Max > 0 && set.Total >= set.Max)
+	assert.Check(t, set.MaxSystem != "")
+	assert.Equal(t, stats["create/"].Count, 1)
+	assert.Equal(t, stats["finalize/"].Count, 1)
+	assert.Equal(t, set.Tick, stats["finalize/"].Tick)
+}
+
+func TestStorageStatsAreNilWithoutMetrics(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	tf.DoTick()
+	assert.Check(t, tf.World.StorageStats() == nil)
+}
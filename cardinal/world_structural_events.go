@@ -0,0 +1,115 @@
+package cardinal
+
+import (
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+)
+
+const (
+	// EventEntityCreated is emitted for every entity that was created in a tick, with the values of its components.
+	EventEntityCreated = "entity-created"
+	// EventEntityRemoved is emitted for every entity that was removed in a tick, with the names of its components.
+	EventEntityRemoved = "entity-removed"
+	// EventComponentsAdded is emitted for every entity that components were added to in a tick, with their values.
+	EventComponentsAdded = "entity-components-added"
+	// EventComponentsRemoved is emitted for every entity that components were removed from in a tick, with their
+	// names.
+	EventComponentsRemoved = "entity-components-removed"
+	// EventComponentsChanged is emitted for every entity whose component values were set in a tick, with the new
+	// values.
+	EventComponentsChanged = "entity-components-changed"
+)
+
+// structuralEvents are the components whose changes are emitted as events; see WithStructuralEvents.
+type structuralEvents struct {
+	// components are the names of the components, or nil for every component.
+	components map[string]bool
+}
+
+func newStructuralEvents(components []string) *structuralEvents {
+	s := &structuralEvents{}
+	if len(components) > 0 {
+		s.components = make(map[string]bool, len(components))
+		for _, name := range components {
+			s.components[name] = true
+		}
+	}
+	return s
+}
+
+func (s *structuralEvents) filter(comps []types.ComponentMetadata) []types.ComponentMetadata {
+	if s.components == nil {
+		return comps
+	}
+	var allowed []types.ComponentMetadata
+	for _, c := range comps {
+		if s.components[c.Name()] {
+			allowed = append(allowed, c)
+		}
+	}
+	return allowed
+}
+
+// emitStructuralEvents emits the events of the entities whose components changed in the tick. The events are emitted
+// in ascending order of entity ID, and for each entity in the order of the constants above.
+//
+//	{"type": "entity-created", "entity": 1, "components": {"health": {"HP": 100}}}
+//	{"type": "entity-components-removed", "entity": 2, "components": ["poisoned"]}
+func (w *World) emitStructuralEvents() error {
+	if w.structuralEvents == nil {
+		return nil
+	}
+	changes, err := w.entityStore.GetPendingChanges()
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		if err := w.emitEntityChange(change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *World) emitEntityChange(change gamestate.EntityChange) error {
+	emitValues := func(eventType string, comps []types.ComponentMetadata) error {
+		comps = w.structuralEvents.filter(comps)
+		if len(comps) == 0 {
+			return nil
+		}
+		values := make(map[string]any, len(comps))
+		for _, c := range comps {
+			value, err := w.entityStore.GetComponentForEntityInRawJSON(c, change.ID)
+			if err != nil {
+				return err
+			}
+			values[c.Name()] = value
+		}
+		return w.tickResults.AddEvent(map[string]any{"type": eventType, "entity": change.ID, "components": values})
+	}
+	emitNames := func(eventType string, comps []types.ComponentMetadata) error {
+		comps = w.structuralEvents.filter(comps)
+		if len(comps) == 0 {
+			return nil
+		}
+		names := make([]string, 0, len(comps))
+		for _, c := range comps {
+			names = append(names, c.Name())
+		}
+		return w.tickResults.AddEvent(map[string]any{"type": eventType, "entity": change.ID, "components": names})
+	}
+
+	switch {
+	case change.Created:
+		return emitValues(EventEntityCreated, change.Added)
+	case change.Removed:
+		return emitNames(EventEntityRemoved, change.RemovedComponents)
+	}
+	if err := emitValues(EventComponentsAdded, change.Added); err != nil {
+		return err
+	}
+	if err := emitNames(EventComponentsRemoved, change.RemovedComponents); err != nil {
+		return err
+	}
+	return emitValues(EventComponentsChanged, change.Changed)
+}
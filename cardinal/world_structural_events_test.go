@@ -0,0 +1,85 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func TestStructuralEvents(t *testing.T) {
+	var events []string
+	tf := testutils.NewTestFixture(t, nil,
+		cardinal.WithStructuralEvents(Health{}.Name(), Foo{}.Name()),
+		cardinal.WithHooks(cardinal.Hooks{OnTickEnd: func(results cardinal.TickResults) {
+			for _, event := range results.Events {
+				events = append(events, string(event))
+			}
+		}}),
+	)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterComponent[Foo](world))
+	assert.NilError(t, cardinal.RegisterComponent[Bar](world))
+
+	var hero, villager types.EntityID
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		var err error
+		switch wCtx.CurrentTick() {
+		case 0:
+			if hero, err = cardinal.Create(wCtx, Health{Value: 100}, Bar{}); err != nil {
+				return err
+			}
+			// Bar isn't in the allow-list, so creating the villager emits no event.
+			if villager, err = cardinal.Create(wCtx, Bar{}); err != nil {
+				return err
+			}
+			// Entities that only exist within a tick aren't reported.
+			ghost, err := cardinal.Create(wCtx, Health{Value: 1})
+			if err != nil {
+				return err
+			}
+			return cardinal.Remove(wCtx, ghost)
+		case 1:
+			if err = cardinal.SetComponent(wCtx, hero, &Health{Value: 90}); err != nil {
+				return err
+			}
+			if err = cardinal.AddComponentTo[Foo](wCtx, hero); err != nil {
+				return err
+			}
+			if err = cardinal.RemoveComponentFrom[Bar](wCtx, hero); err != nil {
+				return err
+			}
+			return cardinal.AddComponentTo[Health](wCtx, villager)
+		case 2:
+			return cardinal.Remove(wCtx, hero)
+		}
+		return nil
+	}))
+
+	tf.DoTick()
+	assert.DeepEqual(t, events, []string{
+		`{"components":{"health":{"Value":100}},"entity":0,"type":"entity-created"}`,
+	})
+
+	events = nil
+	tf.DoTick()
+	assert.DeepEqual(t, events, []string{
+		`{"components":{"foo":{}},"entity":0,"type":"entity-components-added"}`,
+		`{"components":{"health":{"Value":90}},"entity":0,"type":"entity-components-changed"}`,
+		`{"components":{"health":{"Value":0}},"entity":1,"type":"entity-components-added"}`,
+	})
+
+	events = nil
+	tf.DoTick()
+	assert.DeepEqual(t, events, []string{
+		`{"components":["health","foo"],"entity":0,"type":"entity-removed"}`,
+	})
+
+	events = nil
+	tf.DoTick()
+	assert.Equal(t, len(events), 0)
+}
@@ -19,6 +19,7 @@ import (
 	"pkg.world.dev/world-engine/assert"
 	"pkg.world.dev/world-engine/cardinal/iterators"
 	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/persona"
 	"pkg.world.dev/world-engine/cardinal/search/filter"
 	"pkg.world.dev/world-engine/cardinal/types"
 	"pkg.world.dev/world-engine/cardinal/types/engine"
@@ -563,3 +564,15 @@ func getOpenPort(t testing.TB) string {
 	assert.NilError(t, err)
 	return fmt.Sprintf("%d", tcpAddr.Port)
 }
+
+func TestPersonaTagChecksSeeThroughWrappedContexts(t *testing.T) {
+	miniRedis := miniredis.RunT(t)
+	t.Setenv("REDIS_ADDRESS", miniRedis.Addr())
+	world, err := NewWorld(WithReservedPersonaTags("admin"))
+	assert.NilError(t, err)
+
+	// The context of a system that runs concurrently with other systems wraps the context of the tick.
+	wCtx := &isolatedContext{Context: NewWorldContext(world)}
+	assert.ErrorIs(t, checkPersonaTag(wCtx, "Admin"), persona.ErrNameReserved)
+	assert.NilError(t, checkPersonaTag(wCtx, "player"))
+}
@@ -0,0 +1,59 @@
+package cardinal
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+)
+
+// ErrTickLogPruned is returned by ReplayEvents when the events of the first tick to replay aren't kept anymore.
+var ErrTickLogPruned = errors.New("tick is older than the oldest tick in the tick log")
+
+// ErrTickLogDisabled is returned by ReplayEvents when the world doesn't keep a tick log; see WithTickLog.
+var ErrTickLogDisabled = gamestate.ErrTickLogDisabled
+
+// ReplayEvents calls fn with the tick results of every logged tick from fromTick on that has events, in tick order.
+// The replayed results only have their tick and events; receipts aren't logged. Priority events are replayed ahead of
+// the other events of their tick, in the order they were broadcast.
+//
+// ReplayEvents returns the tick after the last one that was replayed, from which the live tick results take over.
+// Events that are emitted after the tick is finalized, such as the events of invariant checks, aren't logged.
+func (w *World) ReplayEvents(fromTick uint64, fn func(event any) error) (next uint64, err error) {
+	first, end, err := w.entityStore.GetTickLogRange()
+	if err != nil {
+		return 0, err
+	}
+	if fromTick < first {
+		return 0, eris.Wrapf(ErrTickLogPruned, "tick %d was requested, but the tick log starts at tick %d",
+			fromTick, first)
+	}
+	for tick := fromTick; tick < end; tick++ {
+		events, err := w.entityStore.GetTickEvents(tick)
+		if err != nil {
+			return 0, err
+		}
+		if len(events) == 0 {
+			continue
+		}
+		if err := fn(&TickResults{Tick: tick, Events: events}); err != nil {
+			return 0, err
+		}
+	}
+	return max(fromTick, end), nil
+}
+
+// logTickEvents hands the events of the tick off to the tick log, if the world keeps one, so that they are saved when
+// the tick is finalized.
+func (w *World) logTickEvents() {
+	if !w.tickLog {
+		return
+	}
+	events := w.tickResults.Events
+	if len(w.tickResults.PriorityEvents) > 0 {
+		events = append(slices.Clone(w.tickResults.PriorityEvents), events...)
+	}
+	w.entityStore.SetTickEvents(w.CurrentTick(), events)
+}
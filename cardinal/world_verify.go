@@ -0,0 +1,141 @@
+package cardinal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal/gamestate"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+	"pkg.world.dev/world-engine/sign"
+)
+
+var ErrStateCommitmentMismatch = errors.New("state hash does not match the published commitment")
+
+// TxLogEntry is a tick of a world's transaction history: the tick's timestamp, and the transactions it executed. A
+// transaction log is a stream of JSON encoded entries in the order of their ticks; see WithTxLogWriter.
+type TxLogEntry struct {
+	Tick      uint64    `json:"tick"`
+	Timestamp uint64    `json:"timestamp"`
+	Txs       []TxLogTx `json:"txs,omitempty"`
+}
+
+// TxLogTx is a transaction of a TxLogEntry. Message is the full name of the transaction's message, and Body is the
+// message as the systems saw it.
+type TxLogTx struct {
+	Message string            `json:"message"`
+	Body    json.RawMessage   `json:"body"`
+	Tx      *sign.Transaction `json:"tx"`
+}
+
+// StateHash returns a deterministic hash of the world's current entity and component state. Two worlds that hold
+// identical state will always report the same hash, which makes it suitable for publishing as a state commitment.
+func (w *World) StateHash() ([]byte, error) {
+	return gamestate.StateHash(w.entityStore)
+}
+
+// VerifyStateCommitment loads the persisted state, which is the snapshot that the history is verified from, replays
+// the ticks of the transaction log that come after it, and asserts that the resulting state hash matches the given
+// commitment. It is meant to be called in place of StartGame, e.g. by the command of package verifier. The world is
+// left in the Ready stage and must not be started afterward.
+func (w *World) VerifyStateCommitment(ctx context.Context, txLog io.Reader, commitment []byte) error {
+	ok := w.worldStage.CompareAndSwap(worldstage.Init, worldstage.Starting)
+	if !ok {
+		return errors.New("game has already been started")
+	}
+	if err := w.validateSystemAccess(); err != nil {
+		return err
+	}
+	if err := w.entityStore.RegisterComponents(w.componentManager.GetComponents()); err != nil {
+		return err
+	}
+
+	w.worldStage.Store(worldstage.Recovering)
+	if err := w.recoverAndExecutePendingTxs(); err != nil {
+		return err
+	}
+	if err := w.replayTxLog(ctx, txLog); err != nil {
+		return err
+	}
+	w.recovery.finish(w.CurrentTick())
+	w.worldStage.Store(worldstage.Ready)
+
+	hash, err := w.StateHash()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(hash, commitment) {
+		return eris.Wrapf(ErrStateCommitmentMismatch, "expected %x, got %x at tick %d",
+			commitment, hash, w.CurrentTick())
+	}
+	return nil
+}
+
+// replayTxLog executes the ticks of the transaction log that come after the world's current tick. Ticks that are
+// missing from the log are executed without transactions, with the timestamp of the next tick in the log.
+func (w *World) replayTxLog(ctx context.Context, txLog io.Reader) error {
+	dec := json.NewDecoder(txLog)
+	for {
+		var entry TxLogEntry
+		if err := dec.Decode(&entry); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return eris.Wrap(err, "failed to decode the transaction log")
+		}
+		if entry.Tick < w.CurrentTick() {
+			// The tick is part of the snapshot.
+			continue
+		}
+		w.recovery.setTarget(entry.Tick + 1)
+		for w.CurrentTick() != entry.Tick {
+			if err := w.doTick(ctx, entry.Timestamp); err != nil {
+				return eris.Wrap(err, "failed to tick engine")
+			}
+		}
+		for _, tx := range entry.Txs {
+			msgType, ok := w.GetMessageByFullName(tx.Message)
+			if !ok {
+				return eris.Errorf("tick %d has a transaction of unknown message %q", entry.Tick, tx.Message)
+			}
+			msg, err := msgType.Decode(tx.Body)
+			if err != nil {
+				return eris.Wrapf(err, "failed to decode a transaction of tick %d", entry.Tick)
+			}
+			w.addTransactionToPool(txpool.TxData{MsgID: msgType.ID(), Msg: msg, Tx: tx.Tx})
+		}
+		if err := w.doTick(ctx, entry.Timestamp); err != nil {
+			return eris.Wrap(err, "failed to tick engine")
+		}
+	}
+}
+
+// txLogWriter writes the transactions of every tick that the world commits to the transaction log.
+type txLogWriter struct {
+	enc *json.Encoder
+}
+
+func newTxLogWriter(w io.Writer) *txLogWriter {
+	return &txLogWriter{enc: json.NewEncoder(w)}
+}
+
+// write writes the tick's entry. Transactions are written in the order of their messages' registration, and in the
+// order they were added within a message, which is the order that systems see them in.
+func (l *txLogWriter) write(tick, timestamp uint64, msgs []types.Message, txPool *txpool.TxPool) error {
+	entry := TxLogEntry{Tick: tick, Timestamp: timestamp}
+	for _, msgType := range msgs {
+		for _, txData := range txPool.ForID(msgType.ID()) {
+			body, err := msgType.Encode(txData.Msg)
+			if err != nil {
+				return err
+			}
+			entry.Txs = append(entry.Txs, TxLogTx{Message: msgType.FullName(), Body: body, Tx: txData.Tx})
+		}
+	}
+	return eris.Wrap(l.enc.Encode(entry), "failed to write the transaction log")
+}
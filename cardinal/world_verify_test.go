@@ -0,0 +1,88 @@
+package cardinal_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type Tally struct {
+	Total int
+}
+
+func (Tally) Name() string {
+	return "tally"
+}
+
+type AddToTallyMsg struct {
+	Amount int
+}
+
+type AddToTallyResult struct{}
+
+// newTallyFixture returns a world that adds the amounts of AddToTallyMsg transactions to a tally.
+func newTallyFixture(t *testing.T, opts ...cardinal.WorldOption) *testutils.TestFixture {
+	tf := testutils.NewTestFixture(t, nil, opts...)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Tally](world))
+	assert.NilError(t, cardinal.RegisterMessage[AddToTallyMsg, AddToTallyResult](world, "add-to-tally"))
+	assert.NilError(t, cardinal.RegisterInitSystems(world, func(wCtx engine.Context) error {
+		_, err := cardinal.Create(wCtx, Tally{})
+		return err
+	}))
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		return cardinal.EachMessage[AddToTallyMsg, AddToTallyResult](wCtx,
+			func(tx message.TxData[AddToTallyMsg]) (AddToTallyResult, error) {
+				id, err := cardinal.NewSearch().Entity(filter.Exact(filter.Component[Tally]())).First(wCtx)
+				if err != nil {
+					return AddToTallyResult{}, err
+				}
+				return AddToTallyResult{}, cardinal.UpdateComponent[Tally](wCtx, id, func(tally *Tally) *Tally {
+					tally.Total += tx.Msg.Amount
+					return tally
+				})
+			})
+	}))
+	return tf
+}
+
+func TestTxLogReplaysToTheSameStateHash(t *testing.T) {
+	var txLog bytes.Buffer
+	tf := newTallyFixture(t, cardinal.WithTxLogWriter(&txLog))
+	msgType, ok := tf.World.GetMessageByFullName("game.add-to-tally")
+	assert.True(t, ok)
+	tf.DoTick()
+	for _, amount := range []int{3, 4, 5} {
+		tf.AddTransaction(msgType.ID(), AddToTallyMsg{Amount: amount}, testutils.UniqueSignature())
+		tf.DoTick()
+	}
+	tf.DoTick()
+	commitment, err := tf.World.StateHash()
+	assert.NilError(t, err)
+
+	verifier := newTallyFixture(t)
+	assert.NilError(t, verifier.World.VerifyStateCommitment(
+		context.Background(), bytes.NewReader(txLog.Bytes()), commitment,
+	))
+	assert.Equal(t, verifier.World.CurrentTick(), tf.World.CurrentTick())
+	wCtx := cardinal.NewReadOnlyWorldContext(verifier.World)
+	id, err := cardinal.NewSearch().Entity(filter.Exact(filter.Component[Tally]())).First(wCtx)
+	assert.NilError(t, err)
+	tally, err := cardinal.GetComponent[Tally](wCtx, id)
+	assert.NilError(t, err)
+	assert.Equal(t, tally.Total, 12)
+
+	// A history that doesn't lead to the commitment is rejected.
+	tampered := bytes.Replace(txLog.Bytes(), []byte(`{"Amount":5}`), []byte(`{"Amount":6}`), 1)
+	assert.Check(t, !bytes.Equal(tampered, txLog.Bytes()))
+	verifier = newTallyFixture(t)
+	err = verifier.World.VerifyStateCommitment(context.Background(), bytes.NewReader(tampered), commitment)
+	assert.ErrorIs(t, err, cardinal.ErrStateCommitmentMismatch)
+}
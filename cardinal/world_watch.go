@@ -0,0 +1,154 @@
+package cardinal
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/statsd"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+// EventWatchAlert is emitted when a component value changes by more than a watch rule allows in a tick.
+const EventWatchAlert = "watch-alert"
+
+// WatchRule is a tripwire for a numeric field of a component that changes faster than it should, such as gold that
+// an exploit duplicates:
+//
+//	cardinal.WatchRule{Name: "gold-spike", Component: "gold", Field: "Amount", MaxIncrease: 10_000}
+type WatchRule struct {
+	// Name identifies the rule in alerts and metrics.
+	Name string
+	// Component is the name of the watched component.
+	Component string
+	// Field is the path of a numeric field in the component's JSON encoding, with dots between the names of nested
+	// fields, e.g. "Amount" or "Wallet.Gold".
+	Field string
+	// MaxIncrease and MaxDecrease are how much the field may increase and decrease in a tick. Zero means no limit.
+	MaxIncrease float64
+	MaxDecrease float64
+}
+
+// RegisterWatchRules registers rules that are checked at the end of every tick, after the systems and the derived
+// components, for every entity whose watched component was set in the tick. The value at the end of the tick is
+// compared with the committed value, or with zero if the entity didn't have the component. When the field changed by
+// more than a rule allows, an EventWatchAlert event is emitted, and the watch_alerts metric, tagged with the rule's
+// name, is incremented:
+//
+//	{"type": "watch-alert", "rule": "gold-spike", "entity": 7, "component": "gold", "field": "Amount",
+//	 "from": 120, "to": 50120, "change": 50000}
+//
+// Values whose field isn't a number are ignored. Alerts don't change the tick in any way.
+func RegisterWatchRules(w *World, rules ...WatchRule) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"engine state is %s, expected %s to register watch rules",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	for _, rule := range rules {
+		if rule.Name == "" {
+			return eris.New("watch rules must have a name")
+		}
+		for _, other := range w.watchRules {
+			if other.Name == rule.Name {
+				return eris.Errorf("watch rule %q is already registered", rule.Name)
+			}
+		}
+		if _, err := w.GetComponentByName(rule.Component); err != nil {
+			return eris.Wrapf(err, "component of watch rule %q must be registered first", rule.Name)
+		}
+		if rule.Field == "" {
+			return eris.Errorf("watch rule %q must have a field", rule.Name)
+		}
+		if rule.MaxIncrease <= 0 && rule.MaxDecrease <= 0 {
+			return eris.Errorf("watch rule %q must limit the increase or the decrease of its field", rule.Name)
+		}
+		w.watchRules = append(w.watchRules, rule)
+	}
+	return nil
+}
+
+// checkWatchRules emits the alerts of the watch rules for the changes of the current tick.
+func (w *World) checkWatchRules() error {
+	if len(w.watchRules) == 0 {
+		return nil
+	}
+	committed := w.entityStore.ToReadOnly()
+	for _, rule := range w.watchRules {
+		comp, err := w.GetComponentByName(rule.Component)
+		if err != nil {
+			return err
+		}
+		ids, err := w.entityStore.GetChangedEntities(comp)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			bz, err := w.entityStore.GetComponentForEntityInRawJSON(comp, id)
+			if err != nil {
+				return err
+			}
+			to, ok := watchedField(bz, rule.Field)
+			if !ok {
+				continue
+			}
+			var from float64
+			if bz, err := committed.GetComponentForEntityInRawJSON(comp, id); err == nil {
+				if from, ok = watchedField(bz, rule.Field); !ok {
+					continue
+				}
+			}
+			change := to - from
+			if (rule.MaxIncrease <= 0 || change <= rule.MaxIncrease) &&
+				(rule.MaxDecrease <= 0 || -change <= rule.MaxDecrease) {
+				continue
+			}
+			if err := w.emitWatchAlert(rule, id, from, to); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *World) emitWatchAlert(rule WatchRule, id types.EntityID, from, to float64) error {
+	err := w.tickResults.AddEvent(map[string]any{
+		"type":      EventWatchAlert,
+		"rule":      rule.Name,
+		"entity":    id,
+		"component": rule.Component,
+		"field":     rule.Field,
+		"from":      from,
+		"to":        to,
+		"change":    to - from,
+	})
+	if err != nil {
+		return err
+	}
+	if err := statsd.Client().Count("watch_alerts", 1, []string{"rule:" + rule.Name}, 1); err != nil {
+		log.Warn().Msgf("failed to emit count stat:%v", err)
+	}
+	return nil
+}
+
+// watchedField returns the number at the path in a component's JSON encoding.
+func watchedField(bz json.RawMessage, path string) (float64, bool) {
+	var value any
+	if err := json.Unmarshal(bz, &value); err != nil {
+		return 0, false
+	}
+	for _, name := range strings.Split(path, ".") {
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return 0, false
+		}
+		value = fields[name]
+	}
+	number, ok := value.(float64)
+	return number, ok
+}
@@ -0,0 +1,71 @@
+package cardinal_test
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+func TestWatchRules(t *testing.T) {
+	var events []string
+	tf := testutils.NewTestFixture(t, nil, cardinal.WithHooks(cardinal.Hooks{
+		OnTickEnd: func(results cardinal.TickResults) {
+			for _, event := range results.Events {
+				events = append(events, string(event))
+			}
+		},
+	}))
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+	assert.NilError(t, cardinal.RegisterWatchRules(world, cardinal.WatchRule{
+		Name:        "health-spike",
+		Component:   Health{}.Name(),
+		Field:       "Value",
+		MaxIncrease: 50,
+		MaxDecrease: 200,
+	}))
+
+	var id types.EntityID
+	values := []int{10, 100, 0}
+	assert.NilError(t, cardinal.RegisterSystems(world, func(wCtx engine.Context) error {
+		var err error
+		tick := wCtx.CurrentTick()
+		if tick == 0 {
+			id, err = cardinal.Create(wCtx, Health{Value: values[0]})
+		} else if tick < uint64(len(values)) {
+			err = cardinal.SetComponent(wCtx, id, &Health{Value: values[tick]})
+		}
+		return err
+	}))
+
+	tf.DoTick()
+	assert.Equal(t, len(events), 0)
+	tf.DoTick()
+	assert.DeepEqual(t, events, []string{
+		`{"change":90,"component":"health","entity":0,"field":"Value","from":10,"rule":"health-spike",` +
+			`"to":100,"type":"watch-alert"}`,
+	})
+	events = nil
+	tf.DoTick()
+	assert.Equal(t, len(events), 0)
+}
+
+func TestWatchRulesAreValidated(t *testing.T) {
+	tf := testutils.NewTestFixture(t, nil)
+	world := tf.World
+	assert.NilError(t, cardinal.RegisterComponent[Health](world))
+
+	err := cardinal.RegisterWatchRules(world, cardinal.WatchRule{Name: "a", Component: "gold", Field: "Amount",
+		MaxIncrease: 1})
+	assert.ErrorContains(t, err, "must be registered first")
+	err = cardinal.RegisterWatchRules(world, cardinal.WatchRule{Name: "a", Component: "health", Field: "Value"})
+	assert.ErrorContains(t, err, "must limit the increase or the decrease")
+
+	rule := cardinal.WatchRule{Name: "a", Component: "health", Field: "Value", MaxDecrease: 1}
+	assert.NilError(t, cardinal.RegisterWatchRules(world, rule))
+	assert.ErrorContains(t, cardinal.RegisterWatchRules(world, rule), "is already registered")
+}
@@ -0,0 +1,309 @@
+package cardinal
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"github.com/rs/zerolog/log"
+
+	"pkg.world.dev/world-engine/cardinal/codec"
+	"pkg.world.dev/world-engine/cardinal/receipt"
+	"pkg.world.dev/world-engine/cardinal/statsd"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/txpool"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+)
+
+const (
+	// WebhookSignatureHeader holds the HMAC-SHA256 of a webhook's body, keyed with the webhook's secret, as
+	// "sha256=<hex>"; see VerifyWebhookSignature.
+	WebhookSignatureHeader = "X-Cardinal-Signature"
+	// WebhookDeliveryHeader identifies a delivery by the hash of its transaction, so that receivers can ignore the
+	// retries of a delivery they already handled.
+	WebhookDeliveryHeader = "X-Cardinal-Delivery"
+
+	webhookQueueSize   = 1024
+	webhookMaxAttempts = 5
+	webhookTimeout     = 10 * time.Second
+	webhookBackoff     = 500 * time.Millisecond
+)
+
+// ReceiptWebhook is an endpoint that is sent the receipts of matching transactions, so that backend services, such as
+// payments or fulfillment, can react to in-game outcomes without consuming the event stream.
+type ReceiptWebhook struct {
+	// Name identifies the webhook in logs and metrics.
+	Name string
+	// URL is where the receipts are POSTed.
+	URL string
+	// Secret is the key of the HMAC-SHA256 signature of every delivery.
+	Secret []byte
+	// Messages are the full names of the messages whose receipts are sent, e.g. "game.buy-item". Empty means every
+	// message.
+	Messages []string
+	// Personas are path.Match patterns of the persona tags whose receipts are sent, e.g. "vendor-*". Empty means every
+	// persona.
+	Personas []string
+}
+
+// WebhookReceipt is the body of a webhook delivery.
+type WebhookReceipt struct {
+	Webhook    string       `json:"webhook"`
+	Tick       uint64       `json:"tick"`
+	TxHash     types.TxHash `json:"txHash"`
+	Message    string       `json:"message"`
+	PersonaTag string       `json:"personaTag"`
+	Result     any          `json:"result"`
+	Errors     []string     `json:"errors"`
+	// ErrorDetails are the codes and parameters of the errors; see RegisterErrorCode.
+	ErrorDetails []receipt.ErrorDetail `json:"errorDetails,omitempty"`
+}
+
+// RegisterReceiptWebhooks registers webhooks that are sent the receipts of the transactions that match them, after
+// the tick that produced the receipts is committed. Each receipt is POSTed as a JSON WebhookReceipt, signed in the
+// WebhookSignatureHeader header.
+//
+// Deliveries don't delay ticks: each webhook is sent its receipts in order from its own goroutine. A delivery that
+// fails with a network error or a 429 or 5xx status is retried with exponential backoff, up to 5 attempts, so
+// receipts are delivered at least once. Failed and dropped deliveries are counted in the webhook_failures metric,
+// tagged with the webhook's name. Receipts of ticks that are replayed during recovery are not sent again.
+func RegisterReceiptWebhooks(w *World, webhooks ...ReceiptWebhook) error {
+	if w.worldStage.Current() != worldstage.Init {
+		return eris.Errorf(
+			"engine state is %s, expected %s to register receipt webhooks",
+			w.worldStage.Current(),
+			worldstage.Init,
+		)
+	}
+	for _, webhook := range webhooks {
+		if webhook.Name == "" {
+			return eris.New("receipt webhooks must have a name")
+		}
+		for _, other := range w.webhooks {
+			if other.Name == webhook.Name {
+				return eris.Errorf("receipt webhook %q is already registered", webhook.Name)
+			}
+		}
+		if !strings.HasPrefix(webhook.URL, "http://") && !strings.HasPrefix(webhook.URL, "https://") {
+			return eris.Errorf("receipt webhook %q must have an http or https URL", webhook.Name)
+		}
+		if len(webhook.Secret) == 0 {
+			return eris.Errorf("receipt webhook %q must have a secret", webhook.Name)
+		}
+		for _, pattern := range webhook.Personas {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return eris.Wrapf(err, "receipt webhook %q has an invalid persona pattern %q", webhook.Name, pattern)
+			}
+		}
+		w.webhooks = append(w.webhooks, &webhookSender{
+			ReceiptWebhook: webhook,
+			client:         &http.Client{Timeout: webhookTimeout},
+			backoff:        webhookBackoff,
+			queue:          make(chan webhookDelivery, webhookQueueSize),
+		})
+	}
+	return nil
+}
+
+// SignWebhook returns the value of the WebhookSignatureHeader header of a webhook delivery with the body.
+func SignWebhook(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature reports whether the value of the WebhookSignatureHeader header of a delivery is the
+// signature of its body with the secret.
+func VerifyWebhookSignature(secret, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignWebhook(secret, body)), []byte(signature))
+}
+
+// webhookSender delivers the receipts of one webhook.
+type webhookSender struct {
+	ReceiptWebhook
+	client  *http.Client
+	backoff time.Duration
+	queue   chan webhookDelivery
+}
+
+type webhookDelivery struct {
+	id   string
+	body []byte
+}
+
+func (s *webhookSender) matches(msgName, personaTag string) bool {
+	if len(s.Messages) > 0 && !slices.Contains(s.Messages, msgName) {
+		return false
+	}
+	if len(s.Personas) == 0 {
+		return true
+	}
+	for _, pattern := range s.Personas {
+		if ok, _ := path.Match(pattern, personaTag); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// enqueue queues the delivery, or drops it if the webhook has fallen too far behind.
+func (s *webhookSender) enqueue(delivery webhookDelivery) {
+	select {
+	case s.queue <- delivery:
+	default:
+		s.fail("dropped", eris.New("too many receipts are waiting to be delivered"))
+	}
+}
+
+func (s *webhookSender) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case delivery := <-s.queue:
+			s.deliver(ctx, delivery)
+		}
+	}
+}
+
+// deliver POSTs the body, retrying failures that may be temporary.
+func (s *webhookSender) deliver(ctx context.Context, delivery webhookDelivery) {
+	backoff := s.backoff
+	var err error
+	for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+		var retry bool
+		if retry, err = s.post(ctx, delivery); err == nil || !retry {
+			break
+		}
+		if attempt < webhookMaxAttempts {
+			if wait(ctx, backoff) != nil {
+				break
+			}
+			backoff *= 2
+		}
+	}
+	if err != nil {
+		s.fail("failed", err)
+	}
+}
+
+// post sends the body once. It returns whether a failure may be temporary.
+func (s *webhookSender) post(ctx context.Context, delivery webhookDelivery) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(delivery.body))
+	if err != nil {
+		return false, eris.Wrap(err, "")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(s.Secret, delivery.body))
+	req.Header.Set(WebhookDeliveryHeader, delivery.id)
+	res, err := s.client.Do(req)
+	if err != nil {
+		return true, eris.Wrap(err, "")
+	}
+	_ = res.Body.Close()
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return false, nil
+	}
+	retry := res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+	return retry, eris.Errorf("webhook responded with status %d", res.StatusCode)
+}
+
+func (s *webhookSender) fail(reason string, err error) {
+	log.Warn().Err(err).Str("webhook", s.Name).Msgf("receipt webhook delivery %s", reason)
+	tags := []string{"webhook:" + s.Name, "reason:" + reason}
+	if err := statsd.Client().Count("webhook_failures", 1, tags, 1); err != nil {
+		log.Warn().Msgf("failed to emit count stat:%v", err)
+	}
+}
+
+// webhooks runs the senders of the receipt webhooks.
+type webhooks struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// startWebhooks starts delivering the receipts of the registered webhooks.
+func (w *World) startWebhooks() {
+	if len(w.webhooks) == 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w.webhookRunner = &webhooks{cancel: cancel}
+	for _, s := range w.webhooks {
+		w.webhookRunner.wg.Add(1)
+		go func() {
+			defer w.webhookRunner.wg.Done()
+			s.run(ctx)
+		}()
+	}
+}
+
+// stopWebhooks stops delivering receipts. Deliveries that are still queued are abandoned.
+func (w *World) stopWebhooks() {
+	if w.webhookRunner == nil {
+		return
+	}
+	w.webhookRunner.cancel()
+	w.webhookRunner.wg.Wait()
+}
+
+// sendReceiptWebhooks queues the receipts of the tick's transactions for the webhooks that match them.
+func (w *World) sendReceiptWebhooks(txPool *txpool.TxPool, tick uint64, receipts []receipt.Receipt) {
+	if len(w.webhooks) == 0 || len(receipts) == 0 {
+		return
+	}
+	type txInfo struct{ msgName, personaTag string }
+	infos := make(map[types.TxHash]txInfo, txPool.GetAmountOfTxs())
+	for msgID, txs := range txPool.Transactions() {
+		msg, ok := w.GetMessageByID(msgID)
+		if !ok {
+			continue
+		}
+		for _, tx := range txs {
+			info := txInfo{msgName: msg.FullName()}
+			if tx.Tx != nil {
+				info.personaTag = tx.Tx.PersonaTag
+			}
+			infos[tx.TxHash] = info
+		}
+	}
+	for _, rec := range receipts {
+		info, ok := infos[rec.TxHash]
+		if !ok {
+			continue
+		}
+		errs := make([]string, len(rec.Errs))
+		for i, err := range rec.Errs {
+			errs[i] = err.Error()
+		}
+		for _, s := range w.webhooks {
+			if !s.matches(info.msgName, info.personaTag) {
+				continue
+			}
+			body, err := codec.Encode(WebhookReceipt{
+				Webhook:      s.Name,
+				Tick:         tick,
+				TxHash:       rec.TxHash,
+				Message:      info.msgName,
+				PersonaTag:   info.personaTag,
+				Result:       rec.Result,
+				Errors:       errs,
+				ErrorDetails: w.errorCodes.Details(rec.Errs),
+			})
+			if err != nil {
+				s.fail("dropped", err)
+				continue
+			}
+			s.enqueue(webhookDelivery{id: string(rec.TxHash), body: body})
+		}
+	}
+}
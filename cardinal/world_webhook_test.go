@@ -0,0 +1,105 @@
+package cardinal
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/worldstage"
+	"pkg.world.dev/world-engine/sign"
+)
+
+func TestReceiptWebhooksAreSignedAndRetried(t *testing.T) {
+	rs := miniredis.RunT(t)
+	t.Setenv("REDIS_ADDRESS", rs.Addr())
+	ctx := context.Background()
+	secret := []byte("secret")
+
+	var mu sync.Mutex
+	attempts := 0
+	delivered := make(chan WebhookReceipt, 10)
+	endpoint := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NilError(t, err)
+		assert.Check(t, VerifyWebhookSignature(secret, body, r.Header.Get(WebhookSignatureHeader)))
+		mu.Lock()
+		attempts++
+		first := attempts == 1
+		mu.Unlock()
+		if first {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var rec WebhookReceipt
+		assert.NilError(t, json.Unmarshal(body, &rec))
+		assert.Equal(t, r.Header.Get(WebhookDeliveryHeader), string(rec.TxHash))
+		delivered <- rec
+	}))
+	t.Cleanup(endpoint.Close)
+
+	world, err := NewWorld(WithPort(getOpenPort(t)))
+	assert.NilError(t, err)
+	assert.NilError(t, RegisterMessage[PowerComp, PowerComp](world, "change_power"))
+	assert.NilError(t, RegisterSystems(world, func(wCtx engine.Context) error {
+		return EachMessage[PowerComp, PowerComp](wCtx, func(tx message.TxData[PowerComp]) (PowerComp, error) {
+			return tx.Msg, nil
+		})
+	}))
+	assert.NilError(t, RegisterReceiptWebhooks(world, ReceiptWebhook{
+		Name:     "payments",
+		URL:      endpoint.URL,
+		Secret:   secret,
+		Messages: []string{"game.change_power"},
+		Personas: []string{"vendor-*"},
+	}))
+	world.webhooks[0].backoff = time.Millisecond
+	go func() {
+		assert.NilError(t, world.StartGame())
+	}()
+	<-world.worldStage.NotifyOnStage(worldstage.Running)
+	t.Cleanup(func() { assert.NilError(t, world.Shutdown()) })
+
+	msg, ok := world.GetMessageByFullName("game.change_power")
+	assert.True(t, ok)
+	_, hash := world.AddTransaction(msg.ID(), PowerComp{Val: 7}, &sign.Transaction{PersonaTag: "vendor-1"})
+	world.AddTransaction(msg.ID(), PowerComp{Val: 8}, &sign.Transaction{PersonaTag: "player-1"})
+	world.tickTheEngine(ctx, nil)
+
+	select {
+	case rec := <-delivered:
+		assert.Equal(t, rec.Webhook, "payments")
+		assert.Equal(t, rec.TxHash, hash)
+		assert.Equal(t, rec.Message, "game.change_power")
+		assert.Equal(t, rec.PersonaTag, "vendor-1")
+		assert.DeepEqual(t, rec.Result, any(map[string]any{"Val": float64(7)}))
+	case <-time.After(5 * time.Second):
+		t.Fatal("the receipt was not delivered")
+	}
+	// The receipt of the other persona isn't sent.
+	select {
+	case rec := <-delivered:
+		t.Fatalf("unexpected delivery of %s", rec.TxHash)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestRegisterReceiptWebhooksValidates(t *testing.T) {
+	world, err := NewWorld(WithMockRedis())
+	assert.NilError(t, err)
+	webhook := ReceiptWebhook{Name: "a", URL: "https://example.com/hook", Secret: []byte("s")}
+	assert.NilError(t, RegisterReceiptWebhooks(world, webhook))
+	assert.ErrorContains(t, RegisterReceiptWebhooks(world, webhook), "already registered")
+	assert.ErrorContains(t, RegisterReceiptWebhooks(world, ReceiptWebhook{Name: "b", URL: "ftp://x", Secret: []byte("s")}),
+		"http or https URL")
+	assert.ErrorContains(t, RegisterReceiptWebhooks(world, ReceiptWebhook{Name: "c", URL: "https://x"}), "secret")
+}
@@ -0,0 +1,166 @@
+// Package worldclock maps ticks to in-game time. Every world has a Clock, which systems get with
+// engine.Context.WorldClock, so that games agree on what time and season it is in the game without converting ticks
+// themselves.
+package worldclock
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+const (
+	// EventDayStarted is emitted by the world in the first tick of every in-game day.
+	EventDayStarted = "world-clock-day-started"
+	// EventSeasonStarted is emitted by the world in the first tick of every season.
+	EventSeasonStarted = "world-clock-season-started"
+
+	// DefaultTickDuration is the in-game time that passes in each tick of the default clock.
+	DefaultTickDuration = time.Minute
+	// DefaultSeasonLength is the number of in-game days in each season of the default clock.
+	DefaultSeasonLength = 30
+
+	day = 24 * time.Hour
+)
+
+var (
+	// DefaultEpoch is the in-game time at tick 0 of the default clock.
+	DefaultEpoch = time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC)
+	// DefaultSeasons are the seasons of the default clock, in order.
+	DefaultSeasons = []string{"spring", "summer", "autumn", "winter"}
+)
+
+// Clock maps ticks to in-game time. In-game time starts at the clock's epoch at tick 0, and advances by a fixed
+// duration every tick. Days start at midnight, in the location of the epoch.
+type Clock struct {
+	epoch        time.Time
+	tickDuration time.Duration
+	seasonLength uint64
+	seasons      []string
+}
+
+type Option func(*Clock)
+
+// WithSeasons sets the number of in-game days in a season, and the names of the seasons in a year. A season length
+// of zero disables seasons.
+func WithSeasons(length uint64, names ...string) Option {
+	return func(c *Clock) {
+		c.seasonLength = length
+		c.seasons = names
+	}
+}
+
+// New returns a clock that is at the epoch at tick 0, and advances by tickDuration every tick.
+func New(epoch time.Time, tickDuration time.Duration, opts ...Option) (*Clock, error) {
+	c := &Clock{
+		epoch:        epoch,
+		tickDuration: tickDuration,
+		seasonLength: DefaultSeasonLength,
+		seasons:      DefaultSeasons,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if tickDuration <= 0 {
+		return nil, eris.Errorf("in-game tick duration must be positive, got %s", tickDuration)
+	}
+	if c.seasonLength > 0 && len(c.seasons) == 0 {
+		return nil, eris.New("seasons must have names")
+	}
+	return c, nil
+}
+
+// Default returns a clock that starts at DefaultEpoch and advances by DefaultTickDuration every tick.
+func Default() *Clock {
+	c, _ := New(DefaultEpoch, DefaultTickDuration)
+	return c
+}
+
+// Time returns the in-game time at the tick.
+func (c *Clock) Time(tick uint64) time.Time {
+	// A time.Duration only spans 292 years, which a fast clock reaches in a few months of ticks, so the elapsed time
+	// is computed in nanoseconds without converting it to a Duration.
+	elapsed := new(big.Int).Mul(new(big.Int).SetUint64(tick), big.NewInt(int64(c.tickDuration)))
+	seconds, nanos := elapsed.QuoRem(elapsed, big.NewInt(int64(time.Second)), new(big.Int))
+	return time.Unix(c.epoch.Unix()+seconds.Int64(), int64(c.epoch.Nanosecond())+nanos.Int64()).In(c.epoch.Location())
+}
+
+// TickAt returns the first tick at or after the in-game time. Times before the epoch map to tick 0.
+func (c *Clock) TickAt(t time.Time) uint64 {
+	elapsed := new(big.Int).Mul(big.NewInt(t.Unix()-c.epoch.Unix()), big.NewInt(int64(time.Second)))
+	elapsed.Add(elapsed, big.NewInt(int64(t.Nanosecond()-c.epoch.Nanosecond())))
+	if elapsed.Sign() <= 0 {
+		return 0
+	}
+	elapsed.Add(elapsed, big.NewInt(int64(c.tickDuration-1)))
+	return elapsed.Quo(elapsed, big.NewInt(int64(c.tickDuration))).Uint64()
+}
+
+// NextTimeOfDay returns the first tick after the given tick at which the in-game time of day is at or past the
+// offset from midnight. For example, NextTimeOfDay(tick, 6*time.Hour) is the tick of the next in-game sunrise.
+func (c *Clock) NextTimeOfDay(tick uint64, offset time.Duration) uint64 {
+	now := c.Time(tick)
+	next := midnight(now).Add(offset)
+	if !next.After(now) {
+		next = midnight(now.Add(day)).Add(offset)
+	}
+	return max(c.TickAt(next), tick+1)
+}
+
+// Day returns the number of in-game days that have started since the epoch's day. The day of the epoch is day 0.
+func (c *Clock) Day(tick uint64) uint64 {
+	return uint64(civilDay(c.Time(tick)) - civilDay(c.epoch))
+}
+
+// Season is a season of an in-game year.
+type Season struct {
+	Name string `json:"name"`
+	// Index is the position of the season in the year, starting at 0.
+	Index int `json:"index"`
+	// Year is the number of in-game years since the epoch, starting at 0.
+	Year uint64 `json:"year"`
+}
+
+// Season returns the season of the tick. The epoch's day is the first day of the first season. If the clock has no
+// seasons, false is returned.
+func (c *Clock) Season(tick uint64) (Season, bool) {
+	if c.seasonLength == 0 {
+		return Season{}, false
+	}
+	seasons := c.Day(tick) / c.seasonLength
+	index := int(seasons % uint64(len(c.seasons)))
+	return Season{Name: c.seasons[index], Index: index, Year: seasons / uint64(len(c.seasons))}, true
+}
+
+// BoundaryEvents returns the events to emit in the tick: an EventDayStarted event if an in-game day started in the
+// tick, and an EventSeasonStarted event if a season started. No events are emitted in tick 0.
+func (c *Clock) BoundaryEvents(tick uint64) []map[string]any {
+	if tick == 0 || c.Day(tick) == c.Day(tick-1) {
+		return nil
+	}
+	events := []map[string]any{{
+		"type": EventDayStarted,
+		"day":  c.Day(tick),
+		"time": c.Time(tick),
+	}}
+	season, ok := c.Season(tick)
+	if previous, _ := c.Season(tick - 1); ok && season != previous {
+		events = append(events, map[string]any{
+			"type":   EventSeasonStarted,
+			"season": season.Name,
+			"index":  season.Index,
+			"year":   season.Year,
+		})
+	}
+	return events
+}
+
+// civilDay numbers the calendar day of t, ignoring its time of day and daylight saving time.
+func civilDay(t time.Time) int64 {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / int64(day/time.Second)
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
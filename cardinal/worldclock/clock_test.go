@@ -0,0 +1,59 @@
+package worldclock_test
+
+import (
+	"testing"
+	"time"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal/worldclock"
+)
+
+var epoch = time.Date(1200, time.March, 1, 18, 0, 0, 0, time.UTC)
+
+func TestClockMapsTicksToTime(t *testing.T) {
+	clock, err := worldclock.New(epoch, time.Hour, worldclock.WithSeasons(2, "wet", "dry"))
+	assert.NilError(t, err)
+
+	assert.Check(t, clock.Time(0).Equal(epoch))
+	assert.Check(t, clock.Time(30).Equal(epoch.Add(30*time.Hour)))
+	assert.Equal(t, clock.TickAt(epoch.Add(30*time.Hour)), uint64(30))
+	assert.Equal(t, clock.TickAt(epoch.Add(30*time.Hour+time.Minute)), uint64(31))
+	assert.Equal(t, clock.TickAt(epoch.Add(-time.Hour)), uint64(0))
+
+	// The epoch is at 18:00, so the first day ends after 6 ticks.
+	assert.Equal(t, clock.Day(5), uint64(0))
+	assert.Equal(t, clock.Day(6), uint64(1))
+	assert.Equal(t, clock.NextTimeOfDay(0, 6*time.Hour), uint64(12))
+	assert.Equal(t, clock.NextTimeOfDay(12, 6*time.Hour), uint64(36))
+
+	season, ok := clock.Season(6 + 2*24)
+	assert.Check(t, ok)
+	assert.Equal(t, season, worldclock.Season{Name: "dry", Index: 1, Year: 0})
+	season, _ = clock.Season(6 + 4*24)
+	assert.Equal(t, season, worldclock.Season{Name: "wet", Index: 0, Year: 1})
+
+	// Ticks far beyond the range of a time.Duration still map to in-game time.
+	farTick := uint64(10_000_000)
+	assert.Check(t, clock.Time(farTick).After(epoch.AddDate(1000, 0, 0)))
+	assert.Equal(t, clock.TickAt(clock.Time(farTick)), farTick)
+}
+
+func TestClockBoundaryEvents(t *testing.T) {
+	clock, err := worldclock.New(epoch, time.Hour, worldclock.WithSeasons(2, "wet", "dry"))
+	assert.NilError(t, err)
+
+	assert.Equal(t, len(clock.BoundaryEvents(0)), 0)
+	assert.Equal(t, len(clock.BoundaryEvents(5)), 0)
+	events := clock.BoundaryEvents(6)
+	assert.Equal(t, len(events), 1)
+	assert.Equal(t, events[0]["type"], worldclock.EventDayStarted)
+	assert.Equal(t, events[0]["day"], uint64(1))
+
+	events = clock.BoundaryEvents(6 + 24)
+	assert.Equal(t, len(events), 2)
+	assert.Equal(t, events[1]["type"], worldclock.EventSeasonStarted)
+	assert.Equal(t, events[1]["season"], "dry")
+
+	_, err = worldclock.New(epoch, 0)
+	assert.ErrorContains(t, err, "must be positive")
+}
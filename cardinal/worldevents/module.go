@@ -0,0 +1,143 @@
+// Package worldevents is a module for global events that run for a range of ticks, such as a double-XP weekend:
+//
+//	err = world.UseModule(worldevents.NewModule())
+//
+// Events are scheduled and cancelled by the schedule and cancel messages, which must be sent as system transactions
+// signed by one of the world's admin signers (see cardinal.WithAdminSigners):
+//
+//	tx, err := sign.NewSystemTransaction(adminKey, namespace, nonce, worldevents.ScheduleMsg{
+//		ID: "xp-weekend-12", Kind: "double-xp", StartTick: 86_400, EndTick: 259_200,
+//	})
+//	// POST tx to /tx/worldevents/schedule
+//
+// Scheduled events are stored as entities, so they survive restarts and are replayed during recovery like any other
+// state. An event is active from its start tick until, but not including, its end tick. The module emits an
+// EventStarted event to every client in the first tick of an event, and an EventEnded event in the tick it ends or is
+// cancelled in, after which the event is removed.
+//
+// Systems gate behavior on events with IsActive, and clients list them with the /query/worldevents/list query.
+package worldevents
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/message"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+const (
+	ModuleName    = "worldevents"
+	ModuleVersion = "v1.0.0"
+
+	ScheduleMessageName = "schedule"
+	CancelMessageName   = "cancel"
+)
+
+// Event types, which are emitted to every client.
+const (
+	EventStarted = "world-event-started"
+	EventEnded   = "world-event-ended"
+)
+
+var ErrNotAdminTransaction = errors.New(
+	"world events can only be scheduled or cancelled by a system transaction of an admin signer",
+)
+
+var _ cardinal.Module = &Module{}
+
+// ScheduleMsg schedules an event. Only admin signers can send it.
+type ScheduleMsg struct {
+	// ID identifies the event, so that it can be cancelled. It must not be the ID of another scheduled event.
+	ID string `json:"id"`
+	// Kind is what systems check for with IsActive, such as "double-xp".
+	Kind string `json:"kind"`
+	// StartTick is the first tick of the event. An event whose start tick has passed starts in the tick it is scheduled
+	// in.
+	StartTick uint64 `json:"startTick"`
+	// EndTick is the first tick after the event. It must be after the start tick and the current tick.
+	EndTick uint64 `json:"endTick"`
+	// Data is passed on to clients, e.g. to describe the event.
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// CancelMsg cancels a scheduled event. Only admin signers can send it.
+type CancelMsg struct {
+	ID string `json:"id"`
+}
+
+type Result struct {
+	Event ScheduledEvent `json:"event"`
+}
+
+type Module struct {
+	cardinal.ModuleBase
+}
+
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (*Module) Name() string    { return ModuleName }
+func (*Module) Version() string { return ModuleVersion }
+
+func (*Module) RegisterComponents(w *cardinal.World) error {
+	return cardinal.RegisterComponent[ScheduledEvent](w)
+}
+
+func (*Module) RegisterTxs(w *cardinal.World) error {
+	if err := cardinal.RegisterMessage[ScheduleMsg, Result](w, ScheduleMessageName,
+		message.WithAdminSignature[ScheduleMsg, Result]()); err != nil {
+		return err
+	}
+	return cardinal.RegisterMessage[CancelMsg, Result](w, CancelMessageName,
+		message.WithAdminSignature[CancelMsg, Result]())
+}
+
+func (*Module) RegisterReads(w *cardinal.World) error {
+	return registerReads(w)
+}
+
+func (*Module) RegisterSystems(w *cardinal.World) error {
+	return cardinal.RegisterSystems(w, worldEventsSystem)
+}
+
+// worldEventsSystem processes this tick's schedule and cancel messages, and then starts and ends the events whose
+// boundaries are reached.
+func worldEventsSystem(wCtx engine.Context) error {
+	if err := cardinal.EachMessage[ScheduleMsg, Result](wCtx, func(tx message.TxData[ScheduleMsg]) (Result, error) {
+		// The server only accepts system transactions from admin signers.
+		if !tx.Tx.IsSystemTransaction() {
+			return Result{}, eris.Wrap(ErrNotAdminTransaction, "")
+		}
+		event := ScheduledEvent{
+			ID:        tx.Msg.ID,
+			Kind:      tx.Msg.Kind,
+			StartTick: tx.Msg.StartTick,
+			EndTick:   tx.Msg.EndTick,
+			Data:      tx.Msg.Data,
+		}
+		if err := Schedule(wCtx, event); err != nil {
+			return Result{}, err
+		}
+		return Result{Event: event}, nil
+	}); err != nil {
+		return err
+	}
+	if err := cardinal.EachMessage[CancelMsg, Result](wCtx, func(tx message.TxData[CancelMsg]) (Result, error) {
+		if !tx.Tx.IsSystemTransaction() {
+			return Result{}, eris.Wrap(ErrNotAdminTransaction, "")
+		}
+		event, err := Cancel(wCtx, tx.Msg.ID)
+		if err != nil {
+			return Result{}, err
+		}
+		return Result{Event: *event}, nil
+	}); err != nil {
+		return err
+	}
+	return advance(wCtx)
+}
@@ -0,0 +1,38 @@
+package worldevents
+
+import (
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+type ListRequest struct {
+	// ActiveOnly leaves out the events that aren't active in the current tick.
+	ActiveOnly bool `json:"activeOnly"`
+}
+
+type ListReply struct {
+	// Tick is the tick that the events were read in.
+	Tick uint64 `json:"tick"`
+	// Events are in the order of their IDs.
+	Events []ScheduledEvent `json:"events"`
+}
+
+// registerReads registers the /query/worldevents/list query.
+func registerReads(w *cardinal.World) error {
+	return cardinal.RegisterReads(w, ModuleName, cardinal.NewRead[ListRequest, ListReply]("list", queryList))
+}
+
+func queryList(wCtx engine.Context, req *ListRequest) (*ListReply, error) {
+	events, err := List(wCtx)
+	if err != nil {
+		return nil, err
+	}
+	reply := &ListReply{Tick: wCtx.CurrentTick(), Events: []ScheduledEvent{}}
+	for _, event := range events {
+		if req.ActiveOnly && !event.IsActiveAt(wCtx.CurrentTick()) {
+			continue
+		}
+		reply.Events = append(reply.Events, event)
+	}
+	return reply, nil
+}
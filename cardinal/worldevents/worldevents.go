@@ -0,0 +1,183 @@
+package worldevents
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/search/filter"
+	"pkg.world.dev/world-engine/cardinal/types"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+)
+
+var (
+	ErrInvalidEvent = errors.New("invalid world event")
+	ErrUnknownEvent = errors.New("unknown world event")
+)
+
+// ScheduledEvent is a global event that is active from its start tick until, but not including, its end tick.
+type ScheduledEvent struct {
+	ID        string          `json:"id"`
+	Kind      string          `json:"kind"`
+	StartTick uint64          `json:"startTick"`
+	EndTick   uint64          `json:"endTick"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	// Started is set in the tick the event starts in, when its EventStarted event is emitted.
+	Started bool `json:"started"`
+}
+
+func (ScheduledEvent) Name() string { return "worldevents-scheduled-event" }
+
+// IsActiveAt reports whether the event is active in the tick.
+func (e ScheduledEvent) IsActiveAt(tick uint64) bool {
+	return e.StartTick <= tick && tick < e.EndTick
+}
+
+// each calls fn with every scheduled event, in the order of their IDs.
+func each(wCtx engine.Context, fn func(types.EntityID, *ScheduledEvent) error) error {
+	type scheduled struct {
+		id    types.EntityID
+		event *ScheduledEvent
+	}
+	var events []scheduled
+	var readErr error
+	err := cardinal.NewSearch().Entity(filter.Contains(filter.Component[ScheduledEvent]())).Each(wCtx,
+		func(id types.EntityID) bool {
+			event, err := cardinal.GetComponent[ScheduledEvent](wCtx, id)
+			if err != nil {
+				readErr = err
+				return false
+			}
+			events = append(events, scheduled{id, event})
+			return true
+		})
+	if err == nil {
+		err = readErr
+	}
+	if err != nil {
+		return err
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].event.ID < events[j].event.ID })
+	for _, s := range events {
+		if err := fn(s.id, s.event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns the scheduled events, including the active ones, in the order of their IDs.
+func List(wCtx engine.Context) ([]ScheduledEvent, error) {
+	events := []ScheduledEvent{}
+	err := each(wCtx, func(_ types.EntityID, event *ScheduledEvent) error {
+		events = append(events, *event)
+		return nil
+	})
+	return events, err
+}
+
+// IsActive reports whether an event of the kind is active in the current tick.
+func IsActive(wCtx engine.Context, kind string) (bool, error) {
+	events, err := List(wCtx)
+	if err != nil {
+		return false, err
+	}
+	for _, event := range events {
+		if event.Kind == kind && event.IsActiveAt(wCtx.CurrentTick()) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Schedule schedules the event. The event's start and end are emitted by the module's system, starting with the
+// current tick if the event starts in it.
+func Schedule(wCtx engine.Context, event ScheduledEvent) error {
+	if event.ID == "" || event.Kind == "" {
+		return eris.Wrap(ErrInvalidEvent, "events must have an ID and a kind")
+	}
+	if event.EndTick <= event.StartTick {
+		return eris.Wrapf(ErrInvalidEvent, "event %q must end after it starts", event.ID)
+	}
+	if event.EndTick <= wCtx.CurrentTick() {
+		return eris.Wrapf(ErrInvalidEvent, "event %q would end before the current tick %d", event.ID,
+			wCtx.CurrentTick())
+	}
+	err := each(wCtx, func(_ types.EntityID, scheduled *ScheduledEvent) error {
+		if scheduled.ID == event.ID {
+			return eris.Wrapf(ErrInvalidEvent, "event %q is already scheduled", event.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	event.Started = false
+	_, err = cardinal.Create(wCtx, event)
+	return err
+}
+
+// Cancel removes the scheduled event, and emits its end if it already started. It returns the cancelled event.
+func Cancel(wCtx engine.Context, id string) (*ScheduledEvent, error) {
+	var cancelled *ScheduledEvent
+	err := each(wCtx, func(entity types.EntityID, event *ScheduledEvent) error {
+		if event.ID != id {
+			return nil
+		}
+		cancelled = event
+		return end(wCtx, entity, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cancelled == nil {
+		return nil, eris.Wrapf(ErrUnknownEvent, "event %q", id)
+	}
+	return cancelled, nil
+}
+
+// advance starts the events whose start tick has been reached, and ends the events whose end tick has.
+func advance(wCtx engine.Context) error {
+	tick := wCtx.CurrentTick()
+	return each(wCtx, func(entity types.EntityID, event *ScheduledEvent) error {
+		if tick >= event.EndTick {
+			return end(wCtx, entity, event)
+		}
+		if tick < event.StartTick || event.Started {
+			return nil
+		}
+		event.Started = true
+		if err := cardinal.SetComponent[ScheduledEvent](wCtx, entity, event); err != nil {
+			return err
+		}
+		return wCtx.EmitEvent(eventPayload(EventStarted, event))
+	})
+}
+
+// end removes the event, and emits its end if it started.
+func end(wCtx engine.Context, entity types.EntityID, event *ScheduledEvent) error {
+	if err := cardinal.Remove(wCtx, entity); err != nil {
+		return err
+	}
+	if !event.Started {
+		return nil
+	}
+	return wCtx.EmitEvent(eventPayload(EventEnded, event))
+}
+
+func eventPayload(eventType string, event *ScheduledEvent) map[string]any {
+	payload := map[string]any{
+		"type":      eventType,
+		"id":        event.ID,
+		"kind":      event.Kind,
+		"startTick": event.StartTick,
+		"endTick":   event.EndTick,
+	}
+	if len(event.Data) > 0 {
+		payload["data"] = event.Data
+	}
+	return payload
+}
@@ -0,0 +1,105 @@
+package worldevents_test
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/cardinal"
+	"pkg.world.dev/world-engine/cardinal/server/utils"
+	"pkg.world.dev/world-engine/cardinal/testutils"
+	"pkg.world.dev/world-engine/cardinal/types/engine"
+	"pkg.world.dev/world-engine/cardinal/worldevents"
+	"pkg.world.dev/world-engine/sign"
+)
+
+type worldEventsFixture struct {
+	*testutils.TestFixture
+	adminKey *ecdsa.PrivateKey
+	nonce    uint64
+	// doubleXP records whether a double-xp event was active in each tick.
+	doubleXP []bool
+	events   []map[string]any
+}
+
+func newWorldEventsFixture(t *testing.T) *worldEventsFixture {
+	adminKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	f := &worldEventsFixture{adminKey: adminKey}
+	f.TestFixture = testutils.NewTestFixture(t, nil,
+		cardinal.WithAdminSigners(crypto.PubkeyToAddress(adminKey.PublicKey).Hex()),
+		cardinal.WithHooks(cardinal.Hooks{OnTickEnd: func(results cardinal.TickResults) {
+			for _, event := range results.Events {
+				var e map[string]any
+				assert.NilError(t, json.Unmarshal(event, &e))
+				f.events = append(f.events, e)
+			}
+		}}))
+	assert.NilError(t, f.World.UseModule(worldevents.NewModule()))
+	assert.NilError(t, cardinal.RegisterSystems(f.World, func(wCtx engine.Context) error {
+		active, err := worldevents.IsActive(wCtx, "double-xp")
+		f.doubleXP = append(f.doubleXP, active)
+		return err
+	}))
+	return f
+}
+
+// send sends the message as an admin in the next tick.
+func (f *worldEventsFixture) send(name string, msg any) {
+	tx, err := sign.NewSystemTransaction(f.adminKey, f.World.Namespace(), f.nonce, msg)
+	assert.NilError(f, err)
+	f.nonce++
+	res := f.Post(utils.GetTxURL(worldevents.ModuleName, name), tx)
+	assert.Equal(f, res.StatusCode, http.StatusOK)
+}
+
+func (f *worldEventsFixture) list() []worldevents.ScheduledEvent {
+	res := f.Post("query/worldevents/list", worldevents.ListRequest{})
+	assert.Equal(f, res.StatusCode, http.StatusOK)
+	var reply worldevents.ListReply
+	assert.NilError(f, json.NewDecoder(res.Body).Decode(&reply))
+	return reply.Events
+}
+
+func TestScheduledEventsStartAndEnd(t *testing.T) {
+	f := newWorldEventsFixture(t)
+	f.DoTick()
+
+	f.send(worldevents.ScheduleMessageName, worldevents.ScheduleMsg{ID: "xp", Kind: "double-xp", StartTick: 3,
+		EndTick: 5})
+	f.DoTick()
+	assert.Equal(t, len(f.list()), 1)
+
+	for range 4 {
+		f.DoTick()
+	}
+	assert.DeepEqual(t, f.doubleXP, []bool{false, false, false, true, true, false})
+	assert.Equal(t, len(f.events), 2)
+	assert.Equal(t, f.events[0]["type"], worldevents.EventStarted)
+	assert.Equal(t, f.events[1]["type"], worldevents.EventEnded)
+	assert.Equal(t, f.events[1]["id"], "xp")
+	assert.Equal(t, len(f.list()), 0)
+}
+
+func TestCancelledEventsEnd(t *testing.T) {
+	f := newWorldEventsFixture(t)
+	f.StartWorld()
+	f.send(worldevents.ScheduleMessageName, worldevents.ScheduleMsg{ID: "xp", Kind: "double-xp", EndTick: 100})
+	f.send(worldevents.ScheduleMessageName, worldevents.ScheduleMsg{ID: "later", Kind: "double-xp", StartTick: 50,
+		EndTick: 100})
+	f.DoTick()
+	f.send(worldevents.CancelMessageName, worldevents.CancelMsg{ID: "xp"})
+	f.send(worldevents.CancelMessageName, worldevents.CancelMsg{ID: "later"})
+	f.DoTick()
+
+	// Only the event that started emits its end.
+	assert.DeepEqual(t, f.doubleXP, []bool{true, false})
+	assert.Equal(t, len(f.events), 2)
+	assert.Equal(t, f.events[0]["type"], worldevents.EventStarted)
+	assert.Equal(t, f.events[1]["type"], worldevents.EventEnded)
+	assert.Equal(t, len(f.list()), 0)
+}
@@ -0,0 +1,187 @@
+// Package client is a Go client for the HTTP and websocket API of a Cardinal world. It signs and submits
+// transactions, waits for their receipts, sends queries, and subscribes to tick results, so that Go services and bots
+// don't have to reimplement the protocol.
+//
+// A Client that is given a persona with WithPersona signs the transactions it sends as that persona:
+//
+//	c := client.New("http://localhost:4040", client.WithPersona("alice", key))
+//	move := client.NewMessage[MoveMsg, MoveResult]("game", "move")
+//	result, err := move.Execute(ctx, c, MoveMsg{Direction: "up"})
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rotisserie/eris"
+)
+
+const (
+	// DefaultReceiptPollInterval is how often receipts are polled for while waiting for a transaction.
+	DefaultReceiptPollInterval = 100 * time.Millisecond
+	// DefaultReconnectDelay is how long Subscribe waits before it reconnects to the world's event stream.
+	DefaultReconnectDelay = time.Second
+)
+
+var ErrNoPersona = errors.New("client has no persona to sign transactions with")
+
+// Client talks to the world that serves its HTTP API at a base URL. A Client is safe for concurrent use.
+type Client struct {
+	baseURL        string
+	client         *http.Client
+	personaTag     string
+	key            *ecdsa.PrivateKey
+	nonce          atomic.Uint64
+	pollInterval   time.Duration
+	reconnectDelay time.Duration
+
+	namespaceMu sync.Mutex
+	namespace   string
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sets the client that sends the requests. The default is http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.client = client
+	}
+}
+
+// WithPersona sets the persona that the client's transactions are sent as, and the key that signs them. A client
+// without a persona can only send queries and subscribe to events.
+func WithPersona(personaTag string, key *ecdsa.PrivateKey) Option {
+	return func(c *Client) {
+		c.personaTag = personaTag
+		c.key = key
+	}
+}
+
+// WithNonce sets the nonce of the client's first transaction. Later transactions use increasing nonces. Use this
+// option to continue from the nonces that an earlier client of the same signer used.
+func WithNonce(nonce uint64) Option {
+	return func(c *Client) {
+		c.nonce.Store(nonce)
+	}
+}
+
+// WithNamespace sets the namespace that transactions are signed for. By default, the namespace is asked from the
+// world when the first transaction is sent.
+func WithNamespace(namespace string) Option {
+	return func(c *Client) {
+		c.namespace = namespace
+	}
+}
+
+// WithReceiptPollInterval sets how often receipts are polled for while waiting for a transaction.
+func WithReceiptPollInterval(interval time.Duration) Option {
+	return func(c *Client) {
+		c.pollInterval = interval
+	}
+}
+
+// WithReconnectDelay sets how long Subscribe waits before it reconnects to the world's event stream.
+func WithReconnectDelay(delay time.Duration) Option {
+	return func(c *Client) {
+		c.reconnectDelay = delay
+	}
+}
+
+// New returns a client of the world that serves its HTTP API at baseURL, e.g. "http://localhost:4040".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:        strings.TrimSuffix(baseURL, "/"),
+		client:         http.DefaultClient,
+		pollInterval:   DefaultReceiptPollInterval,
+		reconnectDelay: DefaultReconnectDelay,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// PersonaTag returns the tag of the persona that the client sends transactions as, or "" if it has none.
+func (c *Client) PersonaTag() string {
+	return c.personaTag
+}
+
+// SignerAddress returns the address that signs the client's transactions, or "" if it has no persona.
+func (c *Client) SignerAddress() string {
+	if c.key == nil {
+		return ""
+	}
+	return crypto.PubkeyToAddress(c.key.PublicKey).Hex()
+}
+
+// Namespace returns the namespace of the world, which the client's transactions are signed for.
+func (c *Client) Namespace(ctx context.Context) (string, error) {
+	c.namespaceMu.Lock()
+	defer c.namespaceMu.Unlock()
+	if c.namespace == "" {
+		var res struct {
+			Namespace string `json:"namespace"`
+		}
+		if err := c.do(ctx, http.MethodGet, "world", nil, &res); err != nil {
+			return "", err
+		}
+		c.namespace = res.Namespace
+	}
+	return c.namespace, nil
+}
+
+func (c *Client) post(ctx context.Context, path string, body, reply any) error {
+	return c.do(ctx, http.MethodPost, path, body, reply)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, reply any) error {
+	var reqBody io.Reader
+	if body != nil {
+		bz, err := json.Marshal(body)
+		if err != nil {
+			return eris.Wrapf(err, "failed to encode request to %s", path)
+		}
+		reqBody = bytes.NewReader(bz)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+"/"+path, reqBody)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return eris.Wrapf(err, "request to %s failed", path)
+	}
+	defer res.Body.Close()
+	bz, err := io.ReadAll(res.Body)
+	if err != nil {
+		return eris.Wrapf(err, "failed to read response from %s", path)
+	}
+	if res.StatusCode != http.StatusOK {
+		return &StatusError{Path: path, StatusCode: res.StatusCode, Body: string(bz)}
+	}
+	return eris.Wrapf(json.Unmarshal(bz, reply), "failed to decode response from %s", path)
+}
+
+// StatusError is returned when the world responds to a request with a status other than 200 OK, e.g. because a
+// transaction's signature is invalid, or a query doesn't exist.
+type StatusError struct {
+	Path       string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return "request to " + e.Path + " failed with status " + http.StatusText(e.StatusCode) + ": " + e.Body
+}
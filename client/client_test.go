@@ -0,0 +1,227 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gorilla/websocket"
+	"gotest.tools/v3/assert"
+
+	"pkg.world.dev/world-engine/client"
+	"pkg.world.dev/world-engine/sign"
+)
+
+type MoveMsg struct {
+	Direction string
+}
+
+type MoveResult struct {
+	X, Y int
+}
+
+type LocationRequest struct {
+	Persona string
+}
+
+// fakeWorld serves the parts of Cardinal's HTTP API that the client uses. Every transaction is executed in the tick
+// after the one it was queued for, and moves its persona up, unless its direction is "nowhere".
+type fakeWorld struct {
+	t      *testing.T
+	signer string
+
+	mu       sync.Mutex
+	tick     uint64
+	receipts []client.Receipt
+	y        int
+}
+
+func (f *fakeWorld) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	reply := func(v any) {
+		assert.NilError(f.t, json.NewEncoder(w).Encode(v))
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	bz, err := io.ReadAll(r.Body)
+	assert.NilError(f.t, err)
+
+	switch r.URL.Path {
+	case "/world":
+		reply(map[string]any{"namespace": "test-world"})
+	case "/tx/game/move":
+		tx, err := sign.UnmarshalTransaction(bz)
+		assert.NilError(f.t, err)
+		if tx.Namespace != "test-world" || tx.Verify(f.signer) != nil {
+			http.Error(w, "invalid signature", http.StatusBadRequest)
+			return
+		}
+		reply(f.queue(tx))
+	case "/tx/batch":
+		batch, err := sign.UnmarshalBatch(bz)
+		assert.NilError(f.t, err)
+		if batch.Verify(f.signer) != nil {
+			http.Error(w, "invalid signature", http.StatusBadRequest)
+			return
+		}
+		var res struct{ Transactions []client.TxResponse }
+		for _, tx := range batch.Unpack() {
+			res.Transactions = append(res.Transactions, f.queue(tx))
+		}
+		reply(res)
+	case "/query/receipts/list":
+		// Every request for receipts advances the world by a tick.
+		f.tick++
+		var req struct {
+			StartTick uint64 `json:"startTick"`
+		}
+		assert.NilError(f.t, json.Unmarshal(bz, &req))
+		var receipts []client.Receipt
+		for _, r := range f.receipts {
+			if r.Tick >= req.StartTick && r.Tick < f.tick {
+				receipts = append(receipts, r)
+			}
+		}
+		reply(map[string]any{"startTick": req.StartTick, "endTick": f.tick, "receipts": receipts})
+	case "/query/game/location":
+		var req LocationRequest
+		assert.NilError(f.t, json.Unmarshal(bz, &req))
+		reply(MoveResult{Y: f.y})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeWorld) queue(tx *sign.Transaction) client.TxResponse {
+	var msg MoveMsg
+	assert.NilError(f.t, json.Unmarshal(tx.Body, &msg))
+	receipt := client.Receipt{TxHash: tx.HashHex(), Tick: f.tick + 1}
+	if msg.Direction == "nowhere" {
+		receipt.Errors = []string{"can't move nowhere"}
+	} else {
+		f.y++
+		receipt.Result, _ = json.Marshal(MoveResult{Y: f.y})
+	}
+	f.receipts = append(f.receipts, receipt)
+	return client.TxResponse{TxHash: tx.HashHex(), Tick: f.tick}
+}
+
+func newFakeWorld(t *testing.T) (*fakeWorld, *client.Client) {
+	key, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	world := &fakeWorld{t: t, signer: crypto.PubkeyToAddress(key.PublicKey).Hex()}
+	server := httptest.NewServer(world)
+	t.Cleanup(server.Close)
+	c := client.New(server.URL,
+		client.WithPersona("alice", key),
+		client.WithReceiptPollInterval(time.Millisecond),
+		client.WithReconnectDelay(time.Millisecond),
+	)
+	return world, c
+}
+
+func TestExecuteMessagesAndQueries(t *testing.T) {
+	_, c := newFakeWorld(t)
+	ctx := context.Background()
+	move := client.NewMessage[MoveMsg, MoveResult]("game", "move")
+	location := client.NewQuery[LocationRequest, MoveResult]("game", "location")
+
+	result, err := move.Execute(ctx, c, MoveMsg{Direction: "up"})
+	assert.NilError(t, err)
+	assert.Equal(t, result, MoveResult{Y: 1})
+
+	_, err = move.Execute(ctx, c, MoveMsg{Direction: "nowhere"})
+	assert.ErrorIs(t, err, client.ErrTxFailed)
+	var receiptErr *client.ReceiptError
+	assert.Check(t, errors.As(err, &receiptErr))
+	assert.DeepEqual(t, receiptErr.Receipt.Errors, []string{"can't move nowhere"})
+
+	loc, err := location.Do(ctx, c, LocationRequest{Persona: "alice"})
+	assert.NilError(t, err)
+	assert.Equal(t, loc, MoveResult{Y: 1})
+
+	var statusErr *client.StatusError
+	err = c.Query(ctx, "game", "missing", LocationRequest{}, &loc)
+	assert.Check(t, errors.As(err, &statusErr))
+	assert.Equal(t, statusErr.StatusCode, http.StatusNotFound)
+
+	_, err = client.New("http://localhost").SendTx(ctx, "game", "move", MoveMsg{})
+	assert.ErrorIs(t, err, client.ErrNoPersona)
+}
+
+func TestSendBatch(t *testing.T) {
+	world, c := newFakeWorld(t)
+	ctx := context.Background()
+	move := client.NewMessage[MoveMsg, MoveResult]("game", "move")
+
+	var entries []sign.BatchEntry
+	for i := 0; i < 3; i++ {
+		entry, err := move.Entry(MoveMsg{Direction: "up"})
+		assert.NilError(t, err)
+		entries = append(entries, entry)
+	}
+	txs, err := c.SendBatch(ctx, entries...)
+	assert.NilError(t, err)
+	assert.Equal(t, len(txs), 3)
+
+	// The next transaction uses the nonce after the batch's.
+	tx, err := move.Send(ctx, c, MoveMsg{Direction: "up"})
+	assert.NilError(t, err)
+	receipt, err := c.WaitForReceipt(ctx, tx)
+	assert.NilError(t, err)
+	assert.Equal(t, string(receipt.Result), `{"X":0,"Y":4}`)
+	assert.Equal(t, world.y, 4)
+}
+
+func TestSubscribeReconnects(t *testing.T) {
+	var mu sync.Mutex
+	connections := 0
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Path, "/events")
+		conn, err := upgrader.Upgrade(w, r, nil)
+		assert.NilError(t, err)
+		mu.Lock()
+		connections++
+		tick := connections
+		mu.Unlock()
+		// Each connection only gets the results of one tick before the world drops it.
+		results := map[string]any{
+			"Tick":     tick,
+			"Receipts": []map[string]any{{"txHash": "0xabc", "result": map[string]int{"Y": tick}, "errors": nil}},
+			"Events":   [][]byte{[]byte(`{"type":"moved"}`)},
+		}
+		assert.NilError(t, conn.WriteJSON(results))
+		conn.Close()
+	}))
+	t.Cleanup(server.Close)
+
+	c := client.New(server.URL, client.WithReconnectDelay(time.Millisecond))
+	var got []client.TickResults
+	stop := errors.New("stop")
+	err := c.Subscribe(context.Background(), func(results client.TickResults) error {
+		got = append(got, results)
+		if len(got) == 3 {
+			return stop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stop)
+	for i, results := range got {
+		assert.Equal(t, results.Tick, uint64(i+1))
+		assert.Equal(t, results.Receipts[0].Tick, results.Tick)
+		assert.Equal(t, results.Receipts[0].TxHash, "0xabc")
+		assert.DeepEqual(t, results.Events, [][]byte{[]byte(`{"type":"moved"}`)})
+	}
+
+	// Subscribe returns nil once ctx is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.NilError(t, c.Subscribe(ctx, func(client.TickResults) error { return nil }))
+}
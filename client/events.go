@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rotisserie/eris"
+)
+
+// TickResults are the results of a tick that the world broadcasts on its event stream: the receipts of the
+// transactions that were executed in the tick, and the JSON encoded events that its systems emitted.
+type TickResults struct {
+	Tick     uint64
+	Receipts []Receipt
+	Events   [][]byte
+}
+
+// Subscribe connects to the world's event stream, /events, and calls fn with the results of each tick, until ctx is
+// done, in which case nil is returned, or fn returns an error, which is then returned. When the connection fails, or
+// the world closes it, Subscribe reconnects after the reconnect delay; the results of the ticks that are broadcast
+// while it is disconnected are missed. Use StreamReceipts to get every receipt.
+func (c *Client) Subscribe(ctx context.Context, fn func(TickResults) error) error {
+	url := "ws" + strings.TrimPrefix(c.baseURL, "http") + "/events"
+	for {
+		err := c.subscribeOnce(ctx, url, fn)
+		if ctx.Err() != nil {
+			return nil
+		}
+		var fnErr *subscriberError
+		if eris.As(err, &fnErr) {
+			return fnErr.err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(c.reconnectDelay):
+		}
+	}
+}
+
+// subscriberError is an error of the function that was passed to Subscribe, which stops Subscribe instead of making
+// it reconnect.
+type subscriberError struct {
+	err error
+}
+
+func (e *subscriberError) Error() string {
+	return e.err.Error()
+}
+
+func (c *Client) subscribeOnce(ctx context.Context, url string, fn func(TickResults) error) error {
+	conn, res, err := websocket.DefaultDialer.DialContext(ctx, url, http.Header{})
+	if res != nil && res.Body != nil {
+		res.Body.Close()
+	}
+	if err != nil {
+		return eris.Wrapf(err, "failed to connect to %s", url)
+	}
+	defer conn.Close()
+
+	// Closing the connection unblocks the read when ctx is done.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		_, bz, err := conn.ReadMessage()
+		if err != nil {
+			return eris.Wrap(err, "event stream closed")
+		}
+		var results TickResults
+		if err := json.Unmarshal(bz, &results); err != nil {
+			return eris.Wrap(err, "failed to decode tick results")
+		}
+		for i := range results.Receipts {
+			results.Receipts[i].Tick = results.Tick
+		}
+		if err := fn(results); err != nil {
+			return &subscriberError{err: err}
+		}
+	}
+}
@@ -0,0 +1,23 @@
+module pkg.world.dev/world-engine/client
+
+go 1.22.1
+
+require (
+	github.com/ethereum/go-ethereum v1.13.4
+	github.com/gorilla/websocket v1.5.1
+	github.com/rotisserie/eris v0.5.4
+	gotest.tools/v3 v3.5.1
+	pkg.world.dev/world-engine/sign v1.0.1-beta
+)
+
+require (
+	github.com/btcsuite/btcd/btcec/v2 v2.3.2 // indirect
+	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.2.0 // indirect
+	github.com/google/go-cmp v0.6.0 // indirect
+	github.com/holiman/uint256 v1.2.3 // indirect
+	github.com/mitchellh/mapstructure v1.5.0 // indirect
+	golang.org/x/crypto v0.18.0 // indirect
+	golang.org/x/net v0.17.0 // indirect
+	golang.org/x/sys v0.16.0 // indirect
+)
+
@@ -0,0 +1,28 @@
+package client
+
+import (
+	"context"
+)
+
+// Query sends the request to the query endpoint, /query/<group>/<name>, and decodes the reply into reply.
+func (c *Client) Query(ctx context.Context, group, name string, req, reply any) error {
+	return c.post(ctx, "query/"+group+"/"+name, req, reply)
+}
+
+// Query is a typed handle to a query that the world registered, with request Req and reply Reply.
+type Query[Req, Reply any] struct {
+	group string
+	name  string
+}
+
+// NewQuery returns a handle to the query with the given group and name, such as "game" and "location".
+func NewQuery[Req, Reply any](group, name string) Query[Req, Reply] {
+	return Query[Req, Reply]{group: group, name: name}
+}
+
+// Do sends the request, and returns the reply.
+func (q Query[Req, Reply]) Do(ctx context.Context, c *Client, req Req) (Reply, error) {
+	var reply Reply
+	err := c.Query(ctx, q.group, q.name, req, &reply)
+	return reply, err
+}
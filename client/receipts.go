@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+var ErrTxFailed = errors.New("transaction failed")
+
+// Receipt is the outcome of a transaction: the JSON encoded result of its message, or the errors of the system that
+// executed it.
+type Receipt struct {
+	TxHash string          `json:"txHash"`
+	Tick   uint64          `json:"tick"`
+	Result json.RawMessage `json:"result"`
+	Errors []string        `json:"errors"`
+	// Impersonator is the admin signer address that submitted the transaction on behalf of its persona, if any.
+	Impersonator string `json:"impersonator,omitempty"`
+}
+
+// ReceiptError is the error of a transaction whose receipt has errors. It wraps ErrTxFailed.
+type ReceiptError struct {
+	Receipt Receipt
+}
+
+func (e *ReceiptError) Error() string {
+	return "transaction " + e.Receipt.TxHash + " failed: " + strings.Join(e.Receipt.Errors, "; ")
+}
+
+func (e *ReceiptError) Unwrap() error {
+	return ErrTxFailed
+}
+
+// Err returns a *ReceiptError if the receipt has errors, and nil otherwise.
+func (r Receipt) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	return &ReceiptError{Receipt: r}
+}
+
+// Receipts returns the receipts of the transactions that were executed in the ticks from startTick up to, but not
+// including, the returned end tick. The world only keeps receipts for a few ticks.
+func (c *Client) Receipts(ctx context.Context, startTick uint64) ([]Receipt, uint64, error) {
+	req := struct {
+		StartTick uint64 `json:"startTick"`
+	}{StartTick: startTick}
+	var res struct {
+		EndTick  uint64    `json:"endTick"`
+		Receipts []Receipt `json:"receipts"`
+	}
+	if err := c.post(ctx, "query/receipts/list", req, &res); err != nil {
+		return nil, 0, err
+	}
+	return res.Receipts, res.EndTick, nil
+}
+
+// WaitForReceipt waits until the world has executed the transaction, and returns its receipt. Receipts are only kept
+// for a few ticks, so WaitForReceipt must be called soon after the transaction is submitted.
+func (c *Client) WaitForReceipt(ctx context.Context, tx TxResponse) (Receipt, error) {
+	var found Receipt
+	err := c.StreamReceipts(ctx, tx.Tick, func(r Receipt) bool {
+		if r.TxHash == tx.TxHash {
+			found = r
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return Receipt{}, eris.Wrapf(err, "no receipt for transaction %s", tx.TxHash)
+	}
+	return found, nil
+}
+
+// StreamReceipts polls the world for receipts, starting with the receipts of startTick, and calls fn with each
+// receipt in the order that the transactions were executed in. Streaming stops when fn returns false, in which case
+// nil is returned, or when ctx is done or polling fails, in which case the error is returned.
+func (c *Client) StreamReceipts(ctx context.Context, startTick uint64, fn func(Receipt) bool) error {
+	for {
+		receipts, endTick, err := c.Receipts(ctx, startTick)
+		if err != nil {
+			return err
+		}
+		for _, r := range receipts {
+			if !fn(r) {
+				return nil
+			}
+		}
+		startTick = max(startTick, endTick)
+
+		select {
+		case <-ctx.Done():
+			return eris.Wrap(ctx.Err(), "")
+		case <-time.After(c.pollInterval):
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/sign"
+)
+
+const (
+	personaGroup              = "persona"
+	createPersonaMessageName  = "create-persona"
+	reservePersonaMessageName = "reserve-persona"
+	confirmPersonaMessageName = "confirm-persona"
+)
+
+// TxResponse identifies a transaction that the world accepted: its hash, and the tick that it was queued for.
+type TxResponse struct {
+	TxHash string
+	Tick   uint64
+}
+
+// SendTx signs the message as the client's persona, and submits it to the message's transaction endpoint,
+// /tx/<group>/<name>. The transaction is executed in a later tick; see WaitForReceipt.
+func (c *Client) SendTx(ctx context.Context, group, name string, msg any) (TxResponse, error) {
+	var res TxResponse
+	if c.key == nil {
+		return res, eris.Wrap(ErrNoPersona, "")
+	}
+	namespace, err := c.Namespace(ctx)
+	if err != nil {
+		return res, err
+	}
+	tx, err := sign.NewTransaction(c.key, c.personaTag, namespace, c.nonce.Add(1)-1, msg)
+	if err != nil {
+		return res, eris.Wrap(err, "failed to sign transaction")
+	}
+	err = c.post(ctx, "tx/"+group+"/"+name, tx, &res)
+	return res, err
+}
+
+// SendBatch signs the entries with a single signature, and submits them in one request to /tx/batch. The world queues
+// the transactions in order, or, if any of them is invalid, none of them. The responses are in the order of the
+// entries.
+func (c *Client) SendBatch(ctx context.Context, entries ...sign.BatchEntry) ([]TxResponse, error) {
+	if c.key == nil {
+		return nil, eris.Wrap(ErrNoPersona, "")
+	}
+	namespace, err := c.Namespace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	n := uint64(len(entries))
+	batch, err := sign.NewBatch(c.key, c.personaTag, namespace, c.nonce.Add(n)-n, entries...)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to sign batch")
+	}
+	var res struct {
+		Transactions []TxResponse
+	}
+	if err := c.post(ctx, "tx/batch", batch, &res); err != nil {
+		return nil, err
+	}
+	return res.Transactions, nil
+}
+
+// CreatePersona submits the transaction that creates the client's persona, with the client's signer address. The
+// persona can be used once the transaction is executed; see WaitForReceipt.
+func (c *Client) CreatePersona(ctx context.Context) (TxResponse, error) {
+	return c.SendTx(ctx, personaGroup, createPersonaMessageName, map[string]string{
+		"personaTag":    c.personaTag,
+		"signerAddress": c.SignerAddress(),
+	})
+}
+
+// ReservePersona submits the transaction that holds the client's persona tag for the client's signer address for
+// holdTicks ticks, or the world's default hold if holdTicks is zero. No one else can claim the tag while it is held,
+// and ConfirmPersona turns the hold into the client's persona.
+func (c *Client) ReservePersona(ctx context.Context, holdTicks uint64) (TxResponse, error) {
+	return c.SendTx(ctx, personaGroup, reservePersonaMessageName, map[string]any{
+		"personaTag":    c.personaTag,
+		"signerAddress": c.SignerAddress(),
+		"holdTicks":     holdTicks,
+	})
+}
+
+// ConfirmPersona submits the transaction that creates the client's persona from the hold of ReservePersona.
+func (c *Client) ConfirmPersona(ctx context.Context) (TxResponse, error) {
+	return c.SendTx(ctx, personaGroup, confirmPersonaMessageName, map[string]string{
+		"personaTag":    c.personaTag,
+		"signerAddress": c.SignerAddress(),
+	})
+}
+
+// Message is a typed handle to a message that the world registered, with input In and result Out.
+type Message[In, Out any] struct {
+	group string
+	name  string
+}
+
+// NewMessage returns a handle to the message with the given group and name, such as "game" and "move".
+func NewMessage[In, Out any](group, name string) Message[In, Out] {
+	return Message[In, Out]{group: group, name: name}
+}
+
+// FullName returns the full name of the message, such as "game.move".
+func (m Message[In, Out]) FullName() string {
+	return m.group + "." + m.name
+}
+
+// Send submits the message as the client's persona.
+func (m Message[In, Out]) Send(ctx context.Context, c *Client, in In) (TxResponse, error) {
+	return c.SendTx(ctx, m.group, m.name, in)
+}
+
+// Execute submits the message as the client's persona, waits until the world has executed it, and returns its
+// result. If the message's system failed, the error is a *ReceiptError.
+func (m Message[In, Out]) Execute(ctx context.Context, c *Client, in In) (Out, error) {
+	var out Out
+	tx, err := m.Send(ctx, c, in)
+	if err != nil {
+		return out, err
+	}
+	receipt, err := c.WaitForReceipt(ctx, tx)
+	if err != nil {
+		return out, err
+	}
+	if err := receipt.Err(); err != nil {
+		return out, err
+	}
+	if err := json.Unmarshal(receipt.Result, &out); err != nil {
+		return out, eris.Wrapf(err, "failed to decode the result of %s", m.FullName())
+	}
+	return out, nil
+}
+
+// Entry returns the message as an entry of a batch; see Client.SendBatch.
+func (m Message[In, Out]) Entry(in In) (sign.BatchEntry, error) {
+	return sign.NewBatchEntry(m.FullName(), in)
+}
@@ -1,5 +1,5 @@
 
-.PHONY: tag tag-cardinal tag-sign tag-nakama
+.PHONY: tag tag-cardinal tag-sign tag-client tag-nakama
 
 # scripts/tag identifies the most current version based on git tags, makes
 # a best-guess about the next logical version number, applies the tag to
@@ -13,5 +13,8 @@ tag-cardinal:
 tag-sign:
 	@$(MAKE) tag TAG_PREFIX=sign/v
 
+tag-client:
+	@$(MAKE) tag TAG_PREFIX=client/v
+
 tag-nakama:
 	@$(MAKE) tag TAG_PREFIX=relay/nakama/v
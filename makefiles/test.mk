@@ -88,6 +88,7 @@ unit-test-all:
 	$(MAKE) unit-test cardinal
 	$(MAKE) unit-test evm
 	$(MAKE) unit-test sign
+	$(MAKE) unit-test client
 	$(MAKE) unit-test relay/nakama
 
 #################
@@ -12,6 +12,7 @@ import (
 	"github.com/rotisserie/eris"
 
 	"pkg.world.dev/world-engine/relay/nakama/events"
+	"pkg.world.dev/world-engine/relay/nakama/natstransport"
 	"pkg.world.dev/world-engine/relay/nakama/persona"
 	"pkg.world.dev/world-engine/relay/nakama/utils"
 )
@@ -62,13 +63,42 @@ func getCardinalEndpoints(cardinalAddress string) (txEndpoints []string, queryEn
 	return txEndpoints, queryEndpoints, err
 }
 
+// makeRequestAndReadResp sends the payload to the Cardinal endpoint and returns the response body. Transactions are
+// submitted through NATS instead, if the transport is set.
 func makeRequestAndReadResp(
 	ctx context.Context,
 	notifier *events.Notifier,
+	transport *natstransport.Transport,
 	endpoint string,
 	payload io.Reader,
 	cardinalAddress string,
+	requestID string,
 ) (res string, err error) {
+	var body []byte
+	if transport != nil && strings.HasPrefix(endpoint, TransactionEndpointPrefix) {
+		body, err = transport.SubmitTx(ctx, endpoint, payload)
+	} else {
+		body, err = postToCardinal(ctx, endpoint, payload, cardinalAddress)
+	}
+	if err != nil {
+		return res, err
+	}
+	if strings.HasPrefix(endpoint, TransactionEndpointPrefix) {
+		var asTx persona.TxResponse
+
+		if err = json.Unmarshal(body, &asTx); err != nil {
+			return res, eris.Wrap(err, "failed to decode body")
+		}
+		userID, err := utils.GetUserID(ctx)
+		if err != nil {
+			return res, eris.Wrap(err, "unable to get user id")
+		}
+		notifier.AddTxHashToPendingNotificationsWithRequestID(asTx.TxHash, userID, requestID)
+	}
+	return string(body), nil
+}
+
+func postToCardinal(ctx context.Context, endpoint string, payload io.Reader, cardinalAddress string) ([]byte, error) {
 	req, err := http.NewRequestWithContext(
 		ctx,
 		http.MethodPost,
@@ -76,36 +106,24 @@ func makeRequestAndReadResp(
 		payload,
 	)
 	if err != nil {
-		return res, eris.Wrapf(err, "request setup failed for endpoint %q", endpoint)
+		return nil, eris.Wrapf(err, "request setup failed for endpoint %q", endpoint)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return res, eris.Wrapf(err, "request failed for endpoint %q", endpoint)
+		return nil, eris.Wrapf(err, "request failed for endpoint %q", endpoint)
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return res, eris.Wrapf(err, "failed to read response body, bad status: %s: %s", resp.Status, body)
+			return nil, eris.Wrapf(err, "failed to read response body, bad status: %s: %s", resp.Status, body)
 		}
-		return res, eris.Errorf("bad status code: %s: %s", resp.Status, body)
+		return nil, eris.Errorf("bad status code: %s: %s", resp.Status, body)
 	}
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return res, eris.Wrapf(err, "failed to read response body, bad status: %s: %s", resp.Status, body)
-	}
-	if strings.HasPrefix(endpoint, TransactionEndpointPrefix) {
-		var asTx persona.TxResponse
-
-		if err = json.Unmarshal(body, &asTx); err != nil {
-			return res, eris.Wrap(err, "failed to decode body")
-		}
-		userID, err := utils.GetUserID(ctx)
-		if err != nil {
-			return res, eris.Wrap(err, "unable to get user id")
-		}
-		notifier.AddTxHashToPendingNotifications(asTx.TxHash, userID)
+		return nil, eris.Wrapf(err, "failed to read response body, bad status: %s: %s", resp.Status, body)
 	}
-	return string(body), nil
+	return body, nil
 }
@@ -0,0 +1,272 @@
+// Package discovery keeps track of the Cardinal instances the relay talks to. Writes always go to the primary
+// Cardinal, while reads are spread across the read replicas that are currently passing health checks.
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/relay/nakama/utils"
+)
+
+const (
+	healthEndpoint = "health"
+
+	DefaultHealthCheckInterval = 5 * time.Second
+	healthCheckTimeout         = 2 * time.Second
+)
+
+// Endpoints is the set of Cardinal addresses the relay can reach, along with their health. Each configured address
+// can resolve to several addresses, such as the A and AAAA records of a host name, or the targets of an SRV record.
+// One of them is in use at a time, and the next healthy one takes its place when it fails a health check.
+type Endpoints struct {
+	primary  *endpoint
+	replicas []*endpoint
+	resolver Resolver
+
+	mu sync.RWMutex
+	// healthy is keyed by resolved address.
+	healthy map[string]bool
+
+	// next is used to round-robin reads across healthy replicas.
+	next   atomic.Uint64
+	client *http.Client
+}
+
+// endpoint is a configured Cardinal address, and the addresses that it resolved to.
+type endpoint struct {
+	name string
+	// addrs are the resolved addresses in the order they are tried, and current is the index of the one in use. Both
+	// are guarded by Endpoints.mu.
+	addrs   []string
+	current int
+}
+
+// address returns the resolved address that is in use, or the configured address if it hasn't been resolved.
+func (ep *endpoint) address() string {
+	if len(ep.addrs) == 0 {
+		return ep.name
+	}
+	return ep.addrs[ep.current]
+}
+
+// resolved returns the addresses of the endpoint, or the configured address if it hasn't been resolved.
+func (ep *endpoint) resolved() []string {
+	if len(ep.addrs) == 0 {
+		return []string{ep.name}
+	}
+	return ep.addrs
+}
+
+// New returns Endpoints for the given primary address and read replica addresses. Addresses are resolved by Resolve
+// and CheckHealth. Every endpoint is considered healthy until a health check says otherwise.
+func New(primary string, replicas ...string) *Endpoints {
+	e := &Endpoints{
+		primary:  &endpoint{name: primary},
+		resolver: net.DefaultResolver,
+		healthy:  map[string]bool{},
+		client:   &http.Client{Timeout: healthCheckTimeout},
+	}
+	for _, addr := range replicas {
+		e.replicas = append(e.replicas, &endpoint{name: addr})
+	}
+	for _, ep := range e.all() {
+		e.healthy[ep.name] = true
+	}
+	return e
+}
+
+// ParseReplicas splits a comma separated list of replica addresses, ignoring empty entries.
+func ParseReplicas(s string) []string {
+	var replicas []string
+	for _, addr := range strings.Split(s, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			replicas = append(replicas, addr)
+		}
+	}
+	return replicas
+}
+
+// Primary returns the address of the primary Cardinal. All transactions must be sent here.
+func (e *Endpoints) Primary() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.primary.address()
+}
+
+// Read returns the address that the next read should be sent to. Reads are spread across healthy replicas. If no
+// replica is healthy, the primary is used.
+func (e *Endpoints) Read() string {
+	healthy := e.healthyReplicas()
+	if len(healthy) == 0 {
+		return e.Primary()
+	}
+	return healthy[e.next.Add(1)%uint64(len(healthy))]
+}
+
+// Failover returns every resolved address in the order they should be tried when connecting to the event stream:
+// the primary's, then the replicas', with healthy addresses ahead of unhealthy ones.
+func (e *Endpoints) Failover() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	var healthy, unhealthy []string
+	for _, ep := range e.all() {
+		// The address in use is tried before the other addresses of the same endpoint.
+		addrs := ep.resolved()
+		for i := range addrs {
+			addr := addrs[(ep.current+i)%len(addrs)]
+			if e.healthy[addr] {
+				healthy = append(healthy, addr)
+			} else {
+				unhealthy = append(unhealthy, addr)
+			}
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+// IsHealthy reports whether the most recent health check of the given address succeeded. A configured address is
+// healthy if any of the addresses that it resolved to is.
+func (e *Endpoints) IsHealthy(addr string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, ep := range e.all() {
+		if ep.name == addr {
+			return slices.ContainsFunc(ep.resolved(), func(a string) bool { return e.healthy[a] })
+		}
+	}
+	return e.healthy[addr]
+}
+
+// Resolve resolves every configured address again. An address that fails to resolve keeps its previous addresses.
+func (e *Endpoints) Resolve(ctx context.Context, logger runtime.Logger) {
+	for _, ep := range e.all() {
+		addrs, err := Resolve(ctx, e.resolver, ep.name)
+		if err != nil {
+			logger.Warn("failed to resolve cardinal address %s: %v", ep.name, err)
+			continue
+		}
+
+		e.mu.Lock()
+		inUse := ep.address()
+		ep.addrs = addrs
+		ep.current = max(slices.Index(addrs, inUse), 0)
+		for _, addr := range addrs {
+			if _, ok := e.healthy[addr]; !ok {
+				e.healthy[addr] = true
+			}
+		}
+		e.mu.Unlock()
+	}
+}
+
+// CheckHealth resolves every configured address, queries the health endpoint of every resolved address, and records
+// the result. An endpoint whose address in use is unhealthy switches to its next healthy address.
+func (e *Endpoints) CheckHealth(ctx context.Context, logger runtime.Logger) {
+	e.Resolve(ctx, logger)
+	for _, ep := range e.all() {
+		e.mu.RLock()
+		addrs := ep.resolved()
+		e.mu.RUnlock()
+
+		for _, addr := range addrs {
+			err := e.checkHealth(ctx, addr)
+			healthy := err == nil
+
+			e.mu.Lock()
+			changed := e.healthy[addr] != healthy
+			e.healthy[addr] = healthy
+			e.mu.Unlock()
+
+			if changed && healthy {
+				logger.Info("cardinal at %s is healthy again", addr)
+			} else if changed {
+				logger.Warn("cardinal at %s is unhealthy: %v", addr, err)
+			}
+		}
+		e.rotate(ep, logger)
+	}
+}
+
+// rotate switches the endpoint to its next healthy address if the address in use is unhealthy.
+func (e *Endpoints) rotate(ep *endpoint, logger runtime.Logger) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(ep.addrs) < 2 || e.healthy[ep.address()] {
+		return
+	}
+	for i := 1; i < len(ep.addrs); i++ {
+		next := (ep.current + i) % len(ep.addrs)
+		if e.healthy[ep.addrs[next]] {
+			logger.Info("cardinal %s switched from %s to %s", ep.name, ep.address(), ep.addrs[next])
+			ep.current = next
+			return
+		}
+	}
+}
+
+// Watch runs health checks at the given interval until the context is cancelled. It is meant to be called in a
+// goroutine.
+func (e *Endpoints) Watch(ctx context.Context, logger runtime.Logger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.CheckHealth(ctx, logger)
+		}
+	}
+}
+
+func (e *Endpoints) checkHealth(ctx context.Context, addr string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, utils.MakeHTTPURL(healthEndpoint, addr), nil)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return eris.Wrap(err, "")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return eris.Errorf("health check returned %s", resp.Status)
+	}
+	var health struct {
+		IsGameLoopRunning bool `json:"isGameLoopRunning"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return eris.Wrap(err, "invalid health check response")
+	}
+	if !health.IsGameLoopRunning {
+		return eris.New("game loop is not running")
+	}
+	return nil
+}
+
+func (e *Endpoints) healthyReplicas() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	healthy := make([]string, 0, len(e.replicas))
+	for _, ep := range e.replicas {
+		if addr := ep.address(); e.healthy[addr] {
+			healthy = append(healthy, addr)
+		}
+	}
+	return healthy
+}
+
+func (e *Endpoints) all() []*endpoint {
+	return append([]*endpoint{e.primary}, e.replicas...)
+}
@@ -0,0 +1,149 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pkg.world.dev/world-engine/relay/nakama/testutils"
+)
+
+func newCardinal(t *testing.T, gameLoopRunning bool) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if gameLoopRunning {
+			_, _ = w.Write([]byte(`{"isServerRunning":true,"isGameLoopRunning":true}`))
+		} else {
+			_, _ = w.Write([]byte(`{"isServerRunning":true,"isGameLoopRunning":false}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+func TestReadsAreSpreadAcrossHealthyReplicas(t *testing.T) {
+	primary := newCardinal(t, true)
+	replicaA := newCardinal(t, true)
+	replicaB := newCardinal(t, true)
+	stalled := newCardinal(t, false)
+	dead := "127.0.0.1:1"
+
+	endpoints := New(primary, replicaA, replicaB, stalled, dead)
+	endpoints.CheckHealth(context.Background(), &testutils.FakeLogger{})
+
+	assert.True(t, endpoints.IsHealthy(primary))
+	assert.False(t, endpoints.IsHealthy(stalled))
+	assert.False(t, endpoints.IsHealthy(dead))
+
+	reads := map[string]int{}
+	for i := 0; i < 10; i++ {
+		reads[endpoints.Read()]++
+	}
+	assert.Equal(t, map[string]int{replicaA: 5, replicaB: 5}, reads)
+	assert.Equal(t, primary, endpoints.Primary())
+	assert.Equal(t, []string{primary, replicaA, replicaB}, endpoints.Failover()[:3])
+}
+
+func TestReadsFallBackToPrimary(t *testing.T) {
+	primary := newCardinal(t, true)
+	endpoints := New(primary, "127.0.0.1:1")
+	endpoints.CheckHealth(context.Background(), &testutils.FakeLogger{})
+
+	assert.Equal(t, primary, endpoints.Read())
+	assert.Equal(t, []string{primary, "127.0.0.1:1"}, endpoints.Failover())
+}
+
+func TestParseReplicas(t *testing.T) {
+	assert.Equal(t, []string{"a:4040", "b:4040"}, ParseReplicas(" a:4040, ,b:4040,"))
+	assert.Empty(t, ParseReplicas(""))
+}
+
+// fakeResolver answers from fixed records.
+type fakeResolver struct {
+	srv map[string][]*net.SRV
+	ips map[string][]net.IPAddr
+}
+
+func (r fakeResolver) LookupSRV(_ context.Context, _, _, name string) (string, []*net.SRV, error) {
+	if records, ok := r.srv[name]; ok {
+		return name, records, nil
+	}
+	return "", nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r fakeResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	if ips, ok := r.ips[host]; ok {
+		return ips, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestResolve(t *testing.T) {
+	r := fakeResolver{
+		srv: map[string][]*net.SRV{
+			"_cardinal._tcp.game.test": {
+				{Target: "a.game.test.", Port: 4040},
+				{Target: "gone.game.test.", Port: 4040},
+				{Target: "b.game.test.", Port: 4041},
+			},
+		},
+		ips: map[string][]net.IPAddr{
+			"a.game.test": {{IP: net.ParseIP("10.0.0.1")}, {IP: net.ParseIP("fd00::1")}},
+			"b.game.test": {{IP: net.ParseIP("10.0.0.2")}},
+		},
+	}
+	ctx := context.Background()
+
+	addrs, err := Resolve(ctx, r, "srv://_cardinal._tcp.game.test")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1:4040", "[fd00::1]:4040", "10.0.0.2:4041"}, addrs)
+
+	addrs, err = Resolve(ctx, r, "a.game.test:4040")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1:4040", "[fd00::1]:4040"}, addrs)
+
+	addrs, err = Resolve(ctx, r, "[::1]:4040")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"[::1]:4040"}, addrs)
+
+	_, err = Resolve(ctx, r, "gone.game.test:4040")
+	assert.Error(t, err)
+	_, err = Resolve(ctx, r, "srv://_missing._tcp.game.test")
+	assert.Error(t, err)
+	_, err = Resolve(ctx, r, "a.game.test")
+	assert.Error(t, err)
+}
+
+func TestEndpointRotatesToHealthyAddress(t *testing.T) {
+	healthy := newCardinal(t, true)
+	host, portString, err := net.SplitHostPort(healthy)
+	assert.NoError(t, err)
+	port, err := strconv.Atoi(portString)
+	assert.NoError(t, err)
+	dead := net.JoinHostPort(host, "1")
+
+	// The SRV record lists an address where nothing is listening first, and a healthy Cardinal second.
+	endpoints := New("srv://_cardinal._tcp.game.test")
+	endpoints.resolver = fakeResolver{
+		srv: map[string][]*net.SRV{
+			"_cardinal._tcp.game.test": {{Target: "dead.game.test.", Port: 1}, {Target: "game.test.", Port: uint16(port)}},
+		},
+		ips: map[string][]net.IPAddr{
+			"dead.game.test": {{IP: net.ParseIP(host)}},
+			"game.test":      {{IP: net.ParseIP(host)}},
+		},
+	}
+	endpoints.Resolve(context.Background(), &testutils.FakeLogger{})
+	assert.Equal(t, dead, endpoints.Primary())
+
+	endpoints.CheckHealth(context.Background(), &testutils.FakeLogger{})
+	assert.Equal(t, healthy, endpoints.Primary())
+	assert.True(t, endpoints.IsHealthy("srv://_cardinal._tcp.game.test"))
+	assert.False(t, endpoints.IsHealthy(dead))
+	assert.Equal(t, []string{healthy, dead}, endpoints.Failover())
+}
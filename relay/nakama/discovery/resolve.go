@@ -0,0 +1,73 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/rotisserie/eris"
+)
+
+// SRVPrefix marks a Cardinal address that is the name of a DNS SRV record, such as
+// "srv://_cardinal._tcp.game.example.com", rather than a host and port.
+const SRVPrefix = "srv://"
+
+// Resolver looks up DNS records. *net.Resolver is a Resolver.
+type Resolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+var _ Resolver = net.DefaultResolver
+
+// Resolve returns the addresses that a configured Cardinal address resolves to, in the order they should be tried.
+// An address with the SRVPrefix is looked up as an SRV record, whose targets are ordered by priority and weight. Any
+// other address must be a host and port. Every host name is resolved to all of its A and AAAA records, and IPv6
+// addresses are returned in brackets, so the results can be used in URLs.
+func Resolve(ctx context.Context, r Resolver, addr string) ([]string, error) {
+	name, ok := strings.CutPrefix(addr, SRVPrefix)
+	if !ok {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, eris.Wrapf(err, "invalid cardinal address %q", addr)
+		}
+		return resolveHost(ctx, r, host, port)
+	}
+
+	_, records, err := r.LookupSRV(ctx, "", "", name)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to look up SRV record %q", name)
+	}
+	var addrs []string
+	for _, record := range records {
+		hostAddrs, err := resolveHost(ctx, r, strings.TrimSuffix(record.Target, "."), strconv.Itoa(int(record.Port)))
+		if err != nil {
+			// The other targets may still be reachable.
+			continue
+		}
+		addrs = append(addrs, hostAddrs...)
+	}
+	if len(addrs) == 0 {
+		return nil, eris.Errorf("none of the targets of SRV record %q could be resolved", name)
+	}
+	return addrs, nil
+}
+
+func resolveHost(ctx context.Context, r Resolver, host, port string) ([]string, error) {
+	if net.ParseIP(host) != nil {
+		return []string{net.JoinHostPort(host, port)}, nil
+	}
+	ips, err := r.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to resolve %q", host)
+	}
+	if len(ips) == 0 {
+		return nil, eris.Errorf("%q has no A or AAAA records", host)
+	}
+	addrs := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, net.JoinHostPort(ip.String(), port))
+	}
+	return addrs, nil
+}
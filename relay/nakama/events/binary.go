@@ -0,0 +1,94 @@
+package events
+
+import (
+	"encoding/json"
+
+	"github.com/rotisserie/eris"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Field numbers of Cardinal's binary encoding of tick results. See cardinal.TickResults.MarshalBinary for the schema.
+const (
+	tickResultsTickField           protowire.Number = 1
+	tickResultsReceiptsField       protowire.Number = 2
+	tickResultsEventsField         protowire.Number = 3
+	tickResultsPriorityEventsField protowire.Number = 4
+
+	receiptTxHashField protowire.Number = 1
+	receiptResultField protowire.Number = 2
+	receiptErrorsField protowire.Number = 3
+)
+
+// UnmarshalBinary decodes tick results that Cardinal sent as a binary frame.
+func (tr *TickResults) UnmarshalBinary(data []byte) error {
+	*tr = TickResults{}
+	return consumeFields(data, func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) error {
+		switch {
+		case num == tickResultsTickField && typ == protowire.VarintType:
+			tr.Tick = varint
+		case num == tickResultsReceiptsField && typ == protowire.BytesType:
+			r, err := unmarshalBinaryReceipt(value)
+			if err != nil {
+				return err
+			}
+			tr.Receipts = append(tr.Receipts, r)
+		case num == tickResultsEventsField && typ == protowire.BytesType:
+			tr.Events = append(tr.Events, append([]byte(nil), value...))
+		case num == tickResultsPriorityEventsField && typ == protowire.BytesType:
+			tr.PriorityEvents = append(tr.PriorityEvents, append([]byte(nil), value...))
+		}
+		return nil
+	})
+}
+
+func unmarshalBinaryReceipt(data []byte) (Receipt, error) {
+	var r Receipt
+	err := consumeFields(data, func(num protowire.Number, typ protowire.Type, value []byte, _ uint64) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case receiptTxHashField:
+			r.TxHash = string(value)
+		case receiptResultField:
+			if err := json.Unmarshal(value, &r.Result); err != nil {
+				return eris.Wrap(err, "invalid receipt result")
+			}
+		case receiptErrorsField:
+			r.Errors = append(r.Errors, string(value))
+		}
+		return nil
+	})
+	return r, err
+}
+
+// consumeFields calls fn with each field in the given protobuf message. Fields of unknown types are skipped.
+func consumeFields(
+	data []byte, fn func(num protowire.Number, typ protowire.Type, value []byte, varint uint64) error,
+) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return eris.Wrap(protowire.ParseError(n), "invalid binary tick results")
+		}
+		data = data[n:]
+		var value []byte
+		var varint uint64
+		switch typ { //nolint:exhaustive // other types are skipped
+		case protowire.VarintType:
+			varint, n = protowire.ConsumeVarint(data)
+		case protowire.BytesType:
+			value, n = protowire.ConsumeBytes(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return eris.Wrap(protowire.ParseError(n), "invalid binary tick results")
+		}
+		data = data[n:]
+		if err := fn(num, typ, value, varint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,10 +1,12 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -18,44 +20,201 @@ import (
 
 type EventHub struct {
 	inputConnection *websocket.Conn
-	channels        *sync.Map // map[string]chan []byte or []Receipt
+	channels        *sync.Map // map[string]*eventSubscription or chan []Receipt
 	didShutdown     atomic.Bool
+	// sequence is the sequence number of the most recently dispatched event.
+	sequence atomic.Uint64
+	// maxSubscriberLag is the number of undelivered events after which a subscriber is disconnected. Zero disables
+	// the limit.
+	maxSubscriberLag int
+	// nk is used to report subscriber lag metrics. It may be nil.
+	nk runtime.NakamaModule
+	// binaryFrames requests protobuf encoded tick results from Cardinal instead of JSON.
+	binaryFrames bool
+	// compression asks Cardinal to compress the event websocket's frames with permessage-deflate.
+	compression bool
+	// wireBytes counts the bytes read from the event websocket's network connection, before decompression.
+	wireBytes atomic.Int64
+	// eventsEndpoint is the path of Cardinal's event websocket.
+	eventsEndpoint string
+	// failoverAddresses returns the Cardinal addresses to try, in order, when the event websocket connection is lost.
+	// If nil, the EventHub shuts down when the connection is lost.
+	failoverAddresses func() []string
+	// lastTick is the most recent tick whose results were dispatched.
+	lastTick uint64
+	// skipThroughTick is set after a failover so that tick results that were already dispatched are not sent to
+	// subscribers a second time.
+	skipThroughTick uint64
+	// source is read instead of the event websocket, if it is set.
+	source FrameSource
+}
+
+// FrameSource delivers Cardinal's tick results through a transport other than the event websocket, such as NATS.
+type FrameSource interface {
+	// Next blocks until the next tick results are received, and returns them in their JSON or binary encoding. ack
+	// is called once they are dispatched. Tick results may be received more than once.
+	Next() (frame []byte, binary bool, ack func(), err error)
+	Close() error
+}
+
+type Option func(*EventHub)
+
+// WithMaxSubscriberLag disconnects event subscribers that fall more than maxLag events behind, so that one stuck
+// subscriber can't cause unbounded buffering.
+func WithMaxSubscriberLag(maxLag int) Option {
+	return func(eh *EventHub) {
+		eh.maxSubscriberLag = maxLag
+	}
+}
+
+// WithMetrics reports each event subscriber's delivery lag as a Nakama gauge metric.
+func WithMetrics(nk runtime.NakamaModule) Option {
+	return func(eh *EventHub) {
+		eh.nk = nk
+	}
+}
+
+// WithBinaryFrames negotiates the compact binary encoding of tick results with Cardinal. Events themselves are
+// unchanged, but they are no longer base64 encoded in transit.
+func WithBinaryFrames() Option {
+	return func(eh *EventHub) {
+		eh.binaryFrames = true
+	}
+}
+
+// WithCompression asks Cardinal to compress the frames of the event websocket with permessage-deflate, which Cardinal
+// does if it's started with cardinal.WithEventCompression. The bytes that compression saves are reported as a Nakama
+// counter metric if WithMetrics is used too.
+func WithCompression() Option {
+	return func(eh *EventHub) {
+		eh.compression = true
+	}
+}
+
+// WithFailover reconnects the event websocket to the first reachable address returned by addresses when the
+// connection to Cardinal is lost.
+func WithFailover(addresses func() []string) Option {
+	return func(eh *EventHub) {
+		eh.failoverAddresses = addresses
+	}
+}
+
+// WithFrameSource reads Cardinal's tick results from the source instead of the event websocket. Tick results that
+// the source delivers again after they were dispatched are skipped.
+func WithFrameSource(source FrameSource) Option {
+	return func(eh *EventHub) {
+		eh.source = source
+	}
+}
+
+// Event is a single event emitted by Cardinal. Events are parsed once in Dispatch and shared by all subscribers.
+type Event struct {
+	// Topic is the value of the event's "type" field. Events without a string "type" field have an empty topic.
+	Topic string `json:"topic"`
+	// Tick is the tick during which the event was emitted.
+	Tick uint64 `json:"tick"`
+	// Sequence increases by one for every event dispatched by the EventHub.
+	Sequence uint64 `json:"sequence"`
+	// Payload is the event as emitted by Cardinal. Events that are not valid JSON are encoded as a JSON string.
+	Payload json.RawMessage `json:"payload"`
+	// Recipients lists the persona tags the event is addressed to, taken from the event's "recipients" field. Events
+	// without recipients are meant for everyone.
+	Recipients []string `json:"recipients,omitempty"`
+	// Priority is set for the events that Cardinal emitted as priority events. They are delivered to subscribers
+	// ahead of the other events that the subscribers haven't received yet.
+	Priority bool `json:"priority,omitempty"`
+}
+
+// Content returns the event's payload as a JSON object. Payloads that are not JSON objects are returned under the
+// "message" key.
+func (e Event) Content() map[string]any {
+	content := make(map[string]any)
+	if err := json.Unmarshal(e.Payload, &content); err == nil {
+		return content
+	}
+	var text string
+	if err := json.Unmarshal(e.Payload, &text); err == nil {
+		content["message"] = text
+	} else {
+		content["message"] = string(e.Payload)
+	}
+	return content
 }
 
 type TickResults struct {
 	Tick     uint64
 	Receipts []Receipt
 	Events   [][]byte
+	// PriorityEvents are only sent in the frames of Cardinal's priority lane, which have no receipts or other events,
+	// and which Cardinal sends ahead of the queued tick results of earlier ticks.
+	PriorityEvents [][]byte
 }
 
-func NewEventHub(logger runtime.Logger, eventsEndpoint string, cardinalAddress string) (*EventHub, error) {
-	url := utils.MakeWebSocketURL(eventsEndpoint, cardinalAddress)
-	webSocketConnection, _, err := websocket.DefaultDialer.Dial(url, nil) //nolint:bodyclose // no need.
+// isPriority reports whether the tick results were sent through Cardinal's priority lane.
+func (tr TickResults) isPriority() bool {
+	return len(tr.PriorityEvents) > 0
+}
+
+func NewEventHub(
+	logger runtime.Logger, eventsEndpoint string, cardinalAddress string, opts ...Option,
+) (*EventHub, error) {
+	channelMap := sync.Map{}
+	res := EventHub{
+		channels:       &channelMap,
+		didShutdown:    atomic.Bool{},
+		eventsEndpoint: eventsEndpoint,
+	}
+	res.didShutdown.Store(false)
+	for _, opt := range opts {
+		opt(&res)
+	}
+	if res.source != nil {
+		return &res, nil
+	}
+
+	url := res.url(cardinalAddress)
+	webSocketConnection, _, err := res.dialer().Dial(url, nil) //nolint:bodyclose // no need.
 	for err != nil {
 		if errors.Is(err, &net.DNSError{}) {
 			// sleep a little try again...
 			logger.Info("No host found.")
 			logger.Info(err.Error())
-			time.Sleep(2 * time.Second)                                          //nolint:gomnd // its ok.
-			webSocketConnection, _, err = websocket.DefaultDialer.Dial(url, nil) //nolint:bodyclose // no need.
+			time.Sleep(2 * time.Second)                               //nolint:gomnd // its ok.
+			webSocketConnection, _, err = res.dialer().Dial(url, nil) //nolint:bodyclose // no need.
 		} else {
 			return nil, eris.Wrap(err, "")
 		}
 	}
-	channelMap := sync.Map{}
-	res := EventHub{
-		inputConnection: webSocketConnection,
-		channels:        &channelMap,
-		didShutdown:     atomic.Bool{},
-	}
-	res.didShutdown.Store(false)
+	res.inputConnection = webSocketConnection
 	return &res, nil
 }
 
-func (eh *EventHub) SubscribeToEvents(session string) chan []byte {
-	channel := make(chan []byte)
-	eh.channels.Store(session, channel)
-	return channel
+// Subscribe returns a channel that receives the events whose topic is one of the given topics. If no topics are
+// given, every event is received. Events are buffered per subscriber, so a slow subscriber does not delay others.
+func (eh *EventHub) Subscribe(session string, topics ...string) chan Event {
+	sub := newEventSubscription(session, topics)
+	eh.channels.Store(session, sub)
+	return sub.ch
+}
+
+// SubscriberStats returns the delivery lag of every event subscriber, sorted by session.
+func (eh *EventHub) SubscriberStats() []SubscriberStats {
+	var stats []SubscriberStats
+	eh.channels.Range(func(_ any, value any) bool {
+		if sub, ok := value.(*eventSubscription); ok {
+			stats = append(stats, sub.stats())
+		}
+		return true
+	})
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Session < stats[j].Session
+	})
+	return stats
+}
+
+// LatestSequence returns the sequence number of the most recently dispatched event.
+func (eh *EventHub) LatestSequence() uint64 {
+	return eh.sequence.Load()
 }
 
 func (eh *EventHub) SubscribeToReceipts(session string) chan []Receipt {
@@ -71,8 +230,8 @@ func (eh *EventHub) Unsubscribe(session string) {
 	}
 
 	switch ch := eventChannelUntyped.(type) {
-	case chan []byte:
-		close(ch)
+	case *eventSubscription:
+		ch.close()
 	case chan []Receipt:
 		close(ch)
 	default:
@@ -82,10 +241,127 @@ func (eh *EventHub) Unsubscribe(session string) {
 	eh.channels.Delete(session)
 }
 
+func (eh *EventHub) url(cardinalAddress string) string {
+	url := utils.MakeWebSocketURL(eh.eventsEndpoint, cardinalAddress)
+	if eh.binaryFrames {
+		url += "?encoding=binary"
+	}
+	return url
+}
+
+// dialer returns the dialer of the event websocket. With compression, it counts the bytes that are read from the
+// network, so that the bytes saved by compression can be reported.
+func (eh *EventHub) dialer() *websocket.Dialer {
+	if !eh.compression {
+		return websocket.DefaultDialer
+	}
+	dialer := *websocket.DefaultDialer
+	dialer.EnableCompression = true
+	dialer.NetDialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := (&net.Dialer{}).DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return &countingConn{Conn: conn, read: &eh.wireBytes}, nil
+	}
+	return &dialer
+}
+
+// countingConn counts the bytes that are read from a network connection.
+type countingConn struct {
+	net.Conn
+	read *atomic.Int64
+}
+
+func (c *countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.read.Add(int64(n))
+	return n, err
+}
+
+// reportCompression reports the bytes that compression saved on the frames that were read since the last report.
+func (eh *EventHub) reportCompression(frameBytes int) {
+	if !eh.compression || eh.nk == nil {
+		return
+	}
+	if saved := int64(frameBytes) - eh.wireBytes.Swap(0); saved > 0 {
+		eh.nk.MetricsCounterAdd("event_compression_bytes_saved", nil, saved)
+	}
+}
+
+// failover replaces the lost event websocket connection with a connection to the first reachable failover address.
+// It keeps retrying until a connection is made or the EventHub is shut down.
+func (eh *EventHub) failover(log runtime.Logger) error {
+	_ = eh.inputConnection.Close()
+	for !eh.didShutdown.Load() {
+		for _, addr := range eh.failoverAddresses() {
+			conn, _, err := eh.dialer().Dial(eh.url(addr), nil) //nolint:bodyclose // no need.
+			if err != nil {
+				log.Warn("failed to connect to cardinal event stream at %s: %v", addr, err)
+				continue
+			}
+			log.Info("connected to cardinal event stream at %s", addr)
+			eh.inputConnection = conn
+			eh.skipThroughTick = eh.lastTick
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+	return eris.New("event hub was shut down during failover")
+}
+
 func (eh *EventHub) Shutdown() {
 	eh.didShutdown.Store(true)
 }
 
+// parseEvents converts the raw events in the given TickResults into Events.
+func (eh *EventHub) parseEvents(tickResults TickResults) []Event {
+	raws, priority := tickResults.Events, false
+	if tickResults.isPriority() {
+		raws, priority = tickResults.PriorityEvents, true
+	}
+	events := make([]Event, 0, len(raws))
+	for _, raw := range raws {
+		event := Event{
+			Tick:     tickResults.Tick,
+			Sequence: eh.sequence.Add(1),
+			Payload:  raw,
+			Priority: priority,
+		}
+		var fields struct {
+			Type       string   `json:"type"`
+			Recipients []string `json:"recipients"`
+		}
+		if err := json.Unmarshal(raw, &fields); err == nil {
+			event.Topic = fields.Type
+			event.Recipients = fields.Recipients
+		} else if !json.Valid(raw) {
+			// encoding a string cannot fail
+			event.Payload, _ = json.Marshal(string(raw))
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// dispatchToSubscriber queues the events the given subscriber is interested in, disconnecting the subscriber if it
+// has fallen too far behind.
+func (eh *EventHub) dispatchToSubscriber(log runtime.Logger, sub *eventSubscription, events []Event) {
+	lag := sub.lag()
+	for _, e := range events {
+		if sub.wants(e.Topic) {
+			lag = sub.enqueue(e)
+		}
+	}
+	if eh.nk != nil {
+		eh.nk.MetricsGaugeSet("event_subscriber_lag", map[string]string{"session": sub.session}, float64(lag))
+	}
+	if eh.maxSubscriberLag > 0 && lag > eh.maxSubscriberLag {
+		log.Warn("disconnecting event subscriber %q: %d events behind (max %d)", sub.session, lag, eh.maxSubscriberLag)
+		eh.Unsubscribe(sub.session)
+	}
+}
+
 // Dispatch continually drains eh.inputConnection (events from cardinal) and sends copies to all subscribed channels.
 // This function is meant to be called in a goroutine.
 func (eh *EventHub) Dispatch(log runtime.Logger) error {
@@ -93,43 +369,92 @@ func (eh *EventHub) Dispatch(log runtime.Logger) error {
 	for !eh.didShutdown.Load() {
 		var messageType int
 		var message []byte
-		messageType, message, err = eh.inputConnection.ReadMessage() // will block
+		ack := func() {}
+		if eh.source != nil {
+			messageType, message, ack, err = eh.readSource()
+		} else {
+			messageType, message, err = eh.inputConnection.ReadMessage() // will block
+			eh.reportCompression(len(message))
+		}
+		if err != nil && eh.source == nil && eh.failoverAddresses != nil && !eh.didShutdown.Load() {
+			log.Warn("lost connection to cardinal event stream: %v", err)
+			err = eh.failover(log)
+			continue
+		}
 		if err != nil {
 			err = eris.Wrap(err, "")
 			eh.Shutdown()
 			continue
 		}
-		if messageType != websocket.TextMessage {
+		receivedTickResults := TickResults{}
+		switch messageType {
+		case websocket.TextMessage:
+			err = json.Unmarshal(message, &receivedTickResults)
+		case websocket.BinaryMessage:
+			err = receivedTickResults.UnmarshalBinary(message)
+		default:
 			eh.Shutdown()
 			continue
 		}
-		receivedTickResults := TickResults{}
-		err = json.Unmarshal(message, &receivedTickResults)
 		if err != nil {
 			log.Error("unable to unmarshal message into TickResults: ", err)
+			ack()
 			continue
 		}
+		if eh.skipThroughTick > 0 && receivedTickResults.Tick <= eh.skipThroughTick {
+			// These results were already dispatched before the failover.
+			continue
+		}
+		if eh.source != nil && eh.lastTick > 0 && receivedTickResults.Tick <= eh.lastTick {
+			// The source delivered these results again.
+			ack()
+			continue
+		}
+		priority := receivedTickResults.isPriority()
+		if !priority {
+			// The priority events of a tick are sent before its other results, so a tick is only done once its
+			// other results are dispatched.
+			eh.lastTick = receivedTickResults.Tick
+		}
+
+		events := eh.parseEvents(receivedTickResults)
 
 		eh.channels.Range(func(_ any, value any) bool {
 			switch ch := value.(type) {
-			case chan []byte:
-				for _, e := range receivedTickResults.Events {
-					ch <- e
-				}
+			case *eventSubscription:
+				eh.dispatchToSubscriber(log, ch, events)
 			case chan []Receipt:
-				ch <- receivedTickResults.Receipts
+				if !priority {
+					ch <- receivedTickResults.Receipts
+				}
 			default:
 				log.Warn("Found an unhandled channel type")
 			}
 
 			return true
 		})
+		ack()
 	}
 	eh.channels.Range(func(key any, _ any) bool {
 		log.Info(fmt.Sprintf("shutting down: %s", key.(string)))
 		eh.Unsubscribe(key.(string))
 		return true
 	})
+	if eh.source != nil {
+		return errors.Join(eh.source.Close(), err)
+	}
 	err = errors.Join(eris.Wrap(eh.inputConnection.Close(), ""), err)
 	return err
 }
+
+// readSource reads the next tick results from the frame source, as if they were a frame of the event websocket.
+func (eh *EventHub) readSource() (messageType int, message []byte, ack func(), err error) {
+	message, binary, ack, err := eh.source.Next()
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if binary {
+		return websocket.BinaryMessage, message, ack, nil
+	}
+	return websocket.TextMessage, message, ack, nil
+}
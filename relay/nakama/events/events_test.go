@@ -2,6 +2,7 @@ package events
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"net/http/httptest"
@@ -12,6 +13,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
 
 	"pkg.world.dev/world-engine/relay/nakama/testutils"
 )
@@ -59,7 +61,7 @@ func TestEventHubIntegration(t *testing.T) {
 
 	// Subscribe to the event hub
 	session := "testSession"
-	eventChan := eventHub.SubscribeToEvents(session)
+	eventChan := eventHub.Subscribe(session)
 
 	// Start dispatching events
 	go func() {
@@ -87,10 +89,9 @@ func TestEventHubIntegration(t *testing.T) {
 	// Wait to receive an event
 	select {
 	case event := <-eventChan:
-		jsonMap := make(map[string]any)
-		err = json.Unmarshal(event, &jsonMap)
-		require.NoError(t, err)
-		msg, ok2 := jsonMap["message"]
+		assert.Equal(t, uint64(100), event.Tick)
+		assert.Equal(t, uint64(1), event.Sequence)
+		msg, ok2 := event.Content()["message"]
 		assert.True(t, ok2)
 		msgString, ok2 := msg.(string)
 		assert.True(t, ok2)
@@ -108,3 +109,249 @@ func TestEventHubIntegration(t *testing.T) {
 	// Cleanup and shutdown
 	eventHub.Shutdown()
 }
+
+func TestEventHubTopicSubscription(t *testing.T) {
+	ch := make(chan TickResults)
+	mockServer := setupMockWebSocketServer(t, ch)
+	t.Cleanup(func() {
+		mockServer.Close()
+		close(ch)
+	})
+
+	logger := &testutils.FakeLogger{}
+	eventHub, err := NewEventHub(logger, eventsEndpoint, strings.TrimPrefix(mockServer.URL, "http://"))
+	require.NoError(t, err)
+
+	moves := eventHub.Subscribe("moves", "move")
+	go func() {
+		if err := eventHub.Dispatch(logger); err != nil {
+			t.Logf("Error dispatching: %v", err)
+		}
+	}()
+
+	go func() {
+		ch <- TickResults{
+			Tick: 7,
+			Events: [][]byte{
+				[]byte(`{"type":"attack","target":"bob"}`),
+				[]byte(`{"type":"move","direction":"up","recipients":["alice"]}`),
+				[]byte(`not json`),
+			},
+		}
+	}()
+
+	select {
+	case event := <-moves:
+		assert.Equal(t, "move", event.Topic)
+		assert.Equal(t, uint64(7), event.Tick)
+		assert.Equal(t, uint64(2), event.Sequence)
+		assert.JSONEq(t, `{"type":"move","direction":"up","recipients":["alice"]}`, string(event.Payload))
+		assert.Equal(t, []string{"alice"}, event.Recipients)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Did not receive event in time")
+	}
+
+	eventHub.Unsubscribe("moves")
+	eventHub.Shutdown()
+}
+
+func TestEventContentWrapsNonObjectPayloads(t *testing.T) {
+	event := Event{Payload: json.RawMessage(`"plain text"`)}
+	assert.Equal(t, map[string]any{"message": "plain text"}, event.Content())
+}
+
+func TestSlowSubscriberIsDisconnected(t *testing.T) {
+	ch := make(chan TickResults)
+	mockServer := setupMockWebSocketServer(t, ch)
+	t.Cleanup(func() {
+		mockServer.Close()
+		close(ch)
+	})
+
+	logger := &testutils.FakeLogger{}
+	eventHub, err := NewEventHub(logger, eventsEndpoint, strings.TrimPrefix(mockServer.URL, "http://"),
+		WithMaxSubscriberLag(2))
+	require.NoError(t, err)
+
+	fast := eventHub.Subscribe("fast")
+	slow := eventHub.Subscribe("slow")
+	go func() {
+		if err := eventHub.Dispatch(logger); err != nil {
+			t.Logf("Error dispatching: %v", err)
+		}
+	}()
+
+	go func() {
+		ch <- TickResults{
+			Tick:   1,
+			Events: [][]byte{[]byte(`{"n":1}`), []byte(`{"n":2}`), []byte(`{"n":3}`)},
+		}
+	}()
+
+	// The slow subscriber never reads, but must not prevent the fast subscriber from receiving every event.
+	for want := uint64(1); want <= 3; want++ {
+		select {
+		case event := <-fast:
+			assert.Equal(t, want, event.Sequence)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Did not receive event in time")
+		}
+	}
+
+	// The slow subscriber fell more than 2 events behind, so it is disconnected and its channel closed.
+	select {
+	case _, ok := <-slow:
+		for ok {
+			_, ok = <-slow
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("slow subscriber was not disconnected")
+	}
+	stats := eventHub.SubscriberStats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, "fast", stats[0].Session)
+	assert.Equal(t, 0, stats[0].Lag)
+	assert.Equal(t, uint64(3), stats[0].LastDeliveredSequence)
+
+	eventHub.Unsubscribe("fast")
+	eventHub.Shutdown()
+}
+
+func TestUnmarshalBinaryTickResults(t *testing.T) {
+	var receipt []byte
+	receipt = protowire.AppendTag(receipt, receiptTxHashField, protowire.BytesType)
+	receipt = protowire.AppendString(receipt, "0xabc")
+	receipt = protowire.AppendTag(receipt, receiptResultField, protowire.BytesType)
+	receipt = protowire.AppendBytes(receipt, []byte(`{"success":true}`))
+	receipt = protowire.AppendTag(receipt, receiptErrorsField, protowire.BytesType)
+	receipt = protowire.AppendString(receipt, "some error")
+
+	var frame []byte
+	frame = protowire.AppendTag(frame, tickResultsTickField, protowire.VarintType)
+	frame = protowire.AppendVarint(frame, 7)
+	frame = protowire.AppendTag(frame, tickResultsReceiptsField, protowire.BytesType)
+	frame = protowire.AppendBytes(frame, receipt)
+	frame = protowire.AppendTag(frame, tickResultsEventsField, protowire.BytesType)
+	frame = protowire.AppendBytes(frame, []byte(`{"type":"moved"}`))
+
+	var tickResults TickResults
+	require.NoError(t, tickResults.UnmarshalBinary(frame))
+	assert.Equal(t, uint64(7), tickResults.Tick)
+	assert.Equal(t, [][]byte{[]byte(`{"type":"moved"}`)}, tickResults.Events)
+	require.Len(t, tickResults.Receipts, 1)
+	assert.Equal(t, "0xabc", tickResults.Receipts[0].TxHash)
+	assert.Equal(t, map[string]any{"success": true}, tickResults.Receipts[0].Result)
+	assert.Equal(t, []string{"some error"}, tickResults.Receipts[0].Errors)
+
+	require.Error(t, tickResults.UnmarshalBinary([]byte{0xff}))
+}
+
+func TestPriorityEventsBypassQueuedEvents(t *testing.T) {
+	ch := make(chan TickResults)
+	mockServer := setupMockWebSocketServer(t, ch)
+	t.Cleanup(func() {
+		mockServer.Close()
+		close(ch)
+	})
+
+	logger := &testutils.FakeLogger{}
+	eventHub, err := NewEventHub(logger, eventsEndpoint, strings.TrimPrefix(mockServer.URL, "http://"))
+	require.NoError(t, err)
+	sub := eventHub.Subscribe("session")
+	go func() {
+		if err := eventHub.Dispatch(logger); err != nil {
+			t.Logf("Error dispatching: %v", err)
+		}
+	}()
+	waitForLag := func(lag int) {
+		require.Eventually(t, func() bool {
+			stats := eventHub.SubscriberStats()
+			return len(stats) == 1 && stats[0].Lag == lag
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	ch <- TickResults{
+		Tick:   1,
+		Events: [][]byte{[]byte(`{"type":"bulk"}`), []byte(`{"type":"bulk"}`), []byte(`{"type":"bulk"}`)},
+	}
+	waitForLag(3)
+	ch <- TickResults{Tick: 2, PriorityEvents: [][]byte{[]byte(`{"type":"your-turn"}`)}}
+	waitForLag(4)
+
+	// The first bulk event was already being handed to the subscriber, but the priority event skips the others.
+	var topics []string
+	for i := 0; i < 4; i++ {
+		select {
+		case event := <-sub:
+			topics = append(topics, event.Topic)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Did not receive event in time")
+		}
+	}
+	assert.Equal(t, []string{"bulk", "your-turn", "bulk", "bulk"}, topics)
+
+	eventHub.Unsubscribe("session")
+	eventHub.Shutdown()
+}
+
+// fakeFrameSource delivers the frames sent on its channel, and counts how many were acknowledged.
+type fakeFrameSource struct {
+	frames chan []byte
+	acked  chan struct{}
+}
+
+func (s *fakeFrameSource) Next() ([]byte, bool, func(), error) {
+	frame, ok := <-s.frames
+	if !ok {
+		return nil, false, nil, errors.New("source closed")
+	}
+	return frame, false, func() { s.acked <- struct{}{} }, nil
+}
+
+func (s *fakeFrameSource) Close() error {
+	return nil
+}
+
+func TestFrameSourceSkipsRedeliveredTicks(t *testing.T) {
+	source := &fakeFrameSource{frames: make(chan []byte), acked: make(chan struct{}, 10)}
+	logger := &testutils.FakeLogger{}
+	eventHub, err := NewEventHub(logger, eventsEndpoint, "unused:0", WithFrameSource(source))
+	require.NoError(t, err)
+	sub := eventHub.Subscribe("session")
+	go func() {
+		if err := eventHub.Dispatch(logger); err != nil {
+			t.Logf("Error dispatching: %v", err)
+		}
+	}()
+
+	send := func(tick uint64, eventType string) {
+		frame, err := json.Marshal(TickResults{Tick: tick, Events: [][]byte{[]byte(`{"type":"` + eventType + `"}`)}})
+		require.NoError(t, err)
+		source.frames <- frame
+		select {
+		case <-source.acked:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Frame was not acknowledged in time")
+		}
+	}
+	send(1, "first")
+	send(2, "second")
+	// The source delivers tick 2 again, e.g. because its acknowledgement was lost.
+	send(2, "second")
+	send(3, "third")
+
+	var topics []string
+	for i := 0; i < 3; i++ {
+		select {
+		case event := <-sub:
+			topics = append(topics, event.Topic)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Did not receive event in time")
+		}
+	}
+	assert.Equal(t, []string{"first", "second", "third"}, topics)
+	assert.Equal(t, uint64(3), eventHub.LatestSequence())
+
+	eventHub.Unsubscribe("session")
+	eventHub.Shutdown()
+}
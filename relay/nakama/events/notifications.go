@@ -8,17 +8,28 @@ import (
 	"github.com/rotisserie/eris"
 )
 
+// RequestIDField is the optional RPC payload field that clients use to correlate a transaction with its receipt.
+// When present, the receipt notification for the transaction includes the same request ID.
+const RequestIDField = "requestId"
+
+// errReceiptTimedOut is delivered to clients that supplied a request ID when no receipt arrives for their
+// transaction before it is treated as stale.
+const errReceiptTimedOut = "timed out waiting for transaction receipt"
+
 // targetInfo contains information about who should receive a notification. It contains a user ID as well as
 // when this record of notification was created.
 type targetInfo struct {
 	createdAt time.Time
 	userID    string
+	// requestID is the client supplied request ID of the transaction. It may be empty.
+	requestID string
 }
 
-// txHashAndUser is a tuple of a transaction hash and a userID.
+// txHashAndUser is a tuple of a transaction hash, a userID, and an optional request ID.
 type txHashAndUser struct {
-	txHash string
-	userID string
+	txHash    string
+	userID    string
+	requestID string
 }
 
 type TransactionReceiptsReply struct {
@@ -69,9 +80,19 @@ func NewNotifier(logger runtime.Logger, nk runtime.NakamaModule, eh *EventHub) *
 // results and errors.
 // This method is safe for concurrent access.
 func (r *Notifier) AddTxHashToPendingNotifications(txHash string, userID string) {
+	r.AddTxHashToPendingNotificationsWithRequestID(txHash, userID, "")
+}
+
+// AddTxHashToPendingNotificationsWithRequestID is like AddTxHashToPendingNotifications, but the notification also
+// includes the given client supplied request ID so the client can match the receipt to the request that caused it.
+// If no receipt arrives before the transaction is treated as stale, the user is sent a receipt notification with a
+// timeout error instead.
+// This method is safe for concurrent access.
+func (r *Notifier) AddTxHashToPendingNotificationsWithRequestID(txHash string, userID string, requestID string) {
 	r.newTxHash <- txHashAndUser{
-		txHash: txHash,
-		userID: userID,
+		txHash:    txHash,
+		userID:    userID,
+		requestID: requestID,
 	}
 }
 
@@ -91,6 +112,7 @@ func (r *Notifier) sendNotifications(ch chan []Receipt) {
 			r.txHashToTargetInfo[tx.txHash] = targetInfo{
 				createdAt: time.Now(),
 				userID:    tx.userID,
+				requestID: tx.requestID,
 			}
 		}
 	}
@@ -102,40 +124,68 @@ func (r *Notifier) handleReceipt(receipts []Receipt) error {
 
 	//nolint:prealloc // we cannot know how many notifications we're going to get
 	var notifications []*runtime.NotificationSend
+	var unknownTxHashes []string
 	for _, receipt := range receipts {
 		target, ok := r.txHashToTargetInfo[receipt.TxHash]
 		if !ok {
-			return eris.Errorf("unable to find user for tx hash %q", receipt.TxHash)
+			// Keep delivering the rest of the batch; one unknown receipt shouldn't hold up everyone else's.
+			unknownTxHashes = append(unknownTxHashes, receipt.TxHash)
+			continue
 		}
 		delete(r.txHashToTargetInfo, receipt.TxHash)
+		notifications = append(notifications, receiptNotification(target, receipt))
+	}
 
-		data := map[string]any{
-			"txHash": receipt.TxHash,
-			"result": receipt.Result,
-			"errors": receipt.Errors,
+	if len(notifications) > 0 {
+		if err := r.nk.NotificationsSend(ctx, notifications); err != nil {
+			return eris.Wrapf(err, "unable to send batch of %d receipts from Nakama Notifier", len(receipts))
 		}
-		notifications = append(notifications, &runtime.NotificationSend{
-			UserID:     target.userID,
-			Subject:    "receipt",
-			Content:    data,
-			Code:       1,
-			Sender:     "",
-			Persistent: false,
-		})
 	}
-
-	if err := r.nk.NotificationsSend(ctx, notifications); err != nil {
-		return eris.Wrapf(err, "unable to send batch of %d receipts from Nakama Notifier", len(receipts))
+	if len(unknownTxHashes) > 0 {
+		return eris.Errorf("unable to find user for tx hashes %q", unknownTxHashes)
 	}
 	return nil
 }
 
+func receiptNotification(target targetInfo, receipt Receipt) *runtime.NotificationSend {
+	data := map[string]any{
+		"txHash": receipt.TxHash,
+		"result": receipt.Result,
+		"errors": receipt.Errors,
+	}
+	if target.requestID != "" {
+		data[RequestIDField] = target.requestID
+	}
+	return &runtime.NotificationSend{
+		UserID:     target.userID,
+		Subject:    "receipt",
+		Content:    data,
+		Code:       1,
+		Sender:     "",
+		Persistent: false,
+	}
+}
+
 // cleanupStaleTransactions identifies any transactions that have been pending for too long (see
-// ReceiptNotifier.staleDuration) and deletes them.
+// ReceiptNotifier.staleDuration) and deletes them. Users that are waiting on a stale transaction's request ID are
+// told that no receipt arrived.
 func (r *Notifier) cleanupStaleTransactions() {
+	var timedOut []*runtime.NotificationSend
 	for txHash, info := range r.txHashToTargetInfo {
 		if time.Since(info.createdAt) > r.staleDuration {
 			delete(r.txHashToTargetInfo, txHash)
+			if info.requestID != "" {
+				timedOut = append(timedOut, receiptNotification(info, Receipt{
+					TxHash: txHash,
+					Errors: []string{errReceiptTimedOut},
+				}))
+			}
 		}
 	}
+	if len(timedOut) == 0 {
+		return
+	}
+	if err := r.nk.NotificationsSend(context.Background(), timedOut); err != nil {
+		r.logger.Debug("failed to send %d receipt timeouts: %v", len(timedOut), err)
+	}
 }
@@ -183,3 +183,53 @@ func TestCleanupStaleTransactions(t *testing.T) {
 	_, recentExists := notifier.txHashToTargetInfo[recentTxHash]
 	assert.True(t, recentExists, "Recent transaction should not be removed")
 }
+
+func TestReceiptNotificationsIncludeRequestID(t *testing.T) {
+	ch := make(chan TickResults)
+	logger := &testutils.FakeLogger{}
+	nk := mocks.NewNakamaModule(t)
+	mockServer := setupMockWebSocketServer(t, ch)
+	eh, err := NewEventHub(logger, eventsEndpoint, strings.TrimPrefix(mockServer.URL, "http://"))
+	require.NoError(t, err)
+	notifier := NewNotifier(logger, nk, eh)
+
+	notifier.txHashToTargetInfo["hash1"] = targetInfo{createdAt: time.Now(), userID: "user1", requestID: "req-1"}
+	notifier.txHashToTargetInfo["stale"] = targetInfo{
+		createdAt: time.Now().Add(-2 * time.Hour),
+		userID:    "user2",
+		requestID: "req-2",
+	}
+
+	// Receipts for transactions the relay doesn't know about must not prevent other receipts from being delivered.
+	nk.On("NotificationsSend", mock.Anything, []*runtime.NotificationSend{{
+		UserID:  "user1",
+		Subject: "receipt",
+		Content: map[string]any{
+			"txHash":    "hash1",
+			"result":    map[string]any{"ok": true},
+			"errors":    ([]string)(nil),
+			"requestId": "req-1",
+		},
+		Code: 1,
+	}}).Return(nil).Once()
+	err = notifier.handleReceipt([]Receipt{
+		{TxHash: "unknown"},
+		{TxHash: "hash1", Result: map[string]any{"ok": true}},
+	})
+	require.Error(t, err)
+
+	// Clients waiting on a request ID are told when no receipt arrives in time.
+	nk.On("NotificationsSend", mock.Anything, []*runtime.NotificationSend{{
+		UserID:  "user2",
+		Subject: "receipt",
+		Content: map[string]any{
+			"txHash":    "stale",
+			"result":    (map[string]any)(nil),
+			"errors":    []string{errReceiptTimedOut},
+			"requestId": "req-2",
+		},
+		Code: 1,
+	}}).Return(nil).Once()
+	notifier.cleanupStaleTransactions()
+	assert.Empty(t, notifier.txHashToTargetInfo)
+}
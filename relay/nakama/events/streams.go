@@ -0,0 +1,150 @@
+package events
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/rotisserie/eris"
+)
+
+// The modes of Nakama's built-in streams. The members of a group who joined its chat channel are in the group's
+// stream, and the members of a party are in the party's stream, so data sent to them reaches every member without
+// the relay tracking who they are.
+const (
+	streamModeGroup uint8 = 3
+	streamModeParty uint8 = 7
+)
+
+// The kinds of Nakama streams that events can be routed to.
+const (
+	StreamKindGroup = "group"
+	StreamKindParty = "party"
+)
+
+var streamPlaceholder = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// StreamRoute sends the events of a topic to a Nakama group or party stream. ID is the ID of the group or party, in
+// which "{field}" is replaced by the value of the event's field of that name, such as "{partyId}".
+type StreamRoute struct {
+	Topic string
+	Kind  string
+	ID    string
+}
+
+// ParseStreamRoutes parses a comma separated list of routes of the form topic=kind:id, such as
+// "loot-drop=party:{partyId},guild-chat=group:{guildId}".
+func ParseStreamRoutes(s string) ([]StreamRoute, error) {
+	var routes []StreamRoute
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		topic, target, ok := strings.Cut(entry, "=")
+		kind, id, hasKind := strings.Cut(target, ":")
+		if !ok || !hasKind || topic == "" || id == "" {
+			return nil, eris.Errorf("invalid event stream route %q, expected topic=kind:id", entry)
+		}
+		if kind != StreamKindGroup && kind != StreamKindParty {
+			return nil, eris.Errorf("invalid stream kind %q of event stream route %q, expected %s or %s",
+				kind, entry, StreamKindGroup, StreamKindParty)
+		}
+		routes = append(routes, StreamRoute{Topic: topic, Kind: kind, ID: id})
+	}
+	return routes, nil
+}
+
+// StreamID returns the ID of the group or party that the event is routed to. An error is returned if the event has
+// no value for one of the fields in the route's ID.
+func (r StreamRoute) StreamID(e Event) (string, error) {
+	content := e.Content()
+	var missing []string
+	id := streamPlaceholder.ReplaceAllStringFunc(r.ID, func(placeholder string) string {
+		field := placeholder[1 : len(placeholder)-1]
+		value, ok := content[field]
+		if !ok || value == nil {
+			missing = append(missing, field)
+			return ""
+		}
+		return fmt.Sprint(value)
+	})
+	if len(missing) > 0 {
+		return "", eris.Errorf("event of topic %q has no field %q for its stream ID", e.Topic, missing)
+	}
+	return id, nil
+}
+
+// StreamFanout sends events to the Nakama streams that their topics are routed to.
+type StreamFanout struct {
+	nk     runtime.NakamaModule
+	routes map[string][]StreamRoute
+}
+
+func NewStreamFanout(nk runtime.NakamaModule, routes []StreamRoute) *StreamFanout {
+	f := &StreamFanout{nk: nk, routes: map[string][]StreamRoute{}}
+	for _, route := range routes {
+		f.routes[route.Topic] = append(f.routes[route.Topic], route)
+	}
+	return f
+}
+
+// Topics returns the topics that are routed to a stream.
+func (f *StreamFanout) Topics() []string {
+	topics := make([]string, 0, len(f.routes))
+	for topic := range f.routes {
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+// Forward subscribes to the routed topics of the EventHub, and sends their events to the streams until the
+// subscription ends.
+func (f *StreamFanout) Forward(logger runtime.Logger, eh *EventHub) {
+	if len(f.routes) == 0 {
+		return
+	}
+	ch := eh.Subscribe("streams", f.Topics()...)
+	go func() {
+		for e := range ch {
+			if err := f.Send(e); err != nil {
+				logger.Error("error sending event to stream: %s", eris.ToString(err, true))
+			}
+		}
+	}()
+}
+
+// Send sends the event to every stream that its topic is routed to. The stream data is the event's payload.
+func (f *StreamFanout) Send(e Event) error {
+	var errs []string
+	for _, route := range f.routes[e.Topic] {
+		if err := f.send(route, e); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return eris.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (f *StreamFanout) send(route StreamRoute, e Event) error {
+	id, err := route.StreamID(e)
+	if err != nil {
+		return err
+	}
+	mode, subject, label := streamModeGroup, id, ""
+	if route.Kind == StreamKindParty {
+		// Party IDs are made of the party's UUID and the name of the node that hosts it, which are the subject and
+		// label of its stream.
+		var ok bool
+		subject, label, ok = strings.Cut(id, ".")
+		if !ok {
+			return eris.Errorf("invalid party ID %q, expected <uuid>.<node>", id)
+		}
+		mode = streamModeParty
+	}
+	err = f.nk.StreamSend(mode, subject, "", label, string(e.Payload), nil, true)
+	return eris.Wrapf(err, "failed to send event of topic %q to %s %s", e.Topic, route.Kind, id)
+}
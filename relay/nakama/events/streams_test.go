@@ -0,0 +1,58 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"pkg.world.dev/world-engine/relay/nakama/mocks"
+)
+
+func TestParseStreamRoutes(t *testing.T) {
+	routes, err := ParseStreamRoutes(" loot-drop=party:{partyId}, guild-chat=group:{guildId} ,")
+	require.NoError(t, err)
+	assert.Equal(t, []StreamRoute{
+		{Topic: "loot-drop", Kind: StreamKindParty, ID: "{partyId}"},
+		{Topic: "guild-chat", Kind: StreamKindGroup, ID: "{guildId}"},
+	}, routes)
+
+	routes, err = ParseStreamRoutes("")
+	require.NoError(t, err)
+	assert.Empty(t, routes)
+
+	for _, invalid := range []string{"loot-drop", "loot-drop=party", "loot-drop=match:{id}", "=group:{id}"} {
+		_, err = ParseStreamRoutes(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestStreamFanoutSendsEventsToTheirGroupOrPartyStream(t *testing.T) {
+	nk := mocks.NewNakamaModule(t)
+	routes, err := ParseStreamRoutes("loot-drop=party:{partyId},guild-chat=group:{guildId}")
+	require.NoError(t, err)
+	fanout := NewStreamFanout(nk, routes)
+	assert.ElementsMatch(t, []string{"loot-drop", "guild-chat"}, fanout.Topics())
+
+	event := func(topic string, payload map[string]any) Event {
+		bz, err := json.Marshal(payload)
+		require.NoError(t, err)
+		return Event{Topic: topic, Payload: bz}
+	}
+
+	loot := event("loot-drop", map[string]any{"type": "loot-drop", "partyId": "42.node1"})
+	nk.On("StreamSend", streamModeParty, "42", "", "node1", string(loot.Payload), mock.Anything, true).
+		Return(nil).Once()
+	require.NoError(t, fanout.Send(loot))
+
+	chat := event("guild-chat", map[string]any{"type": "guild-chat", "guildId": "g-7"})
+	nk.On("StreamSend", streamModeGroup, "g-7", "", "", string(chat.Payload), mock.Anything, true).
+		Return(nil).Once()
+	require.NoError(t, fanout.Send(chat))
+
+	// Events without the fields of their stream ID, and events of other topics, are not sent anywhere.
+	assert.ErrorContains(t, fanout.Send(event("guild-chat", map[string]any{"type": "guild-chat"})), "guildId")
+	assert.NoError(t, fanout.Send(event("other", map[string]any{"type": "other", "guildId": "g-7"})))
+}
@@ -0,0 +1,139 @@
+package events
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// eventSubscription buffers the events for a single subscriber so that a slow subscriber does not block event
+// delivery to everyone else. Events are delivered to ch by a dedicated goroutine.
+type eventSubscription struct {
+	session string
+	ch      chan Event
+	topics  map[string]bool
+
+	mu    sync.Mutex
+	queue []Event
+	// priorityQueue holds the priority events, which are delivered before the events in queue.
+	priorityQueue []Event
+	// undelivered counts the queued events plus the event, if any, that is currently being handed to the subscriber.
+	undelivered int
+	// notify signals the delivery goroutine that the queue is no longer empty.
+	notify chan struct{}
+	// done is closed when the subscription is removed.
+	done      chan struct{}
+	closeOnce sync.Once
+	// delivered is the sequence number of the most recent event received by the subscriber.
+	delivered atomic.Uint64
+}
+
+// SubscriberStats describes how far a subscriber has fallen behind the events dispatched by the EventHub.
+type SubscriberStats struct {
+	Session string   `json:"session"`
+	Topics  []string `json:"topics,omitempty"`
+	// Lag is the number of events that have been dispatched to the subscriber but not yet received by it.
+	Lag int `json:"lag"`
+	// LastDeliveredSequence is the sequence number of the most recent event received by the subscriber.
+	LastDeliveredSequence uint64 `json:"lastDeliveredSequence"`
+}
+
+func newEventSubscription(session string, topics []string) *eventSubscription {
+	sub := &eventSubscription{
+		session: session,
+		ch:      make(chan Event),
+		topics:  make(map[string]bool, len(topics)),
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	for _, topic := range topics {
+		sub.topics[topic] = true
+	}
+	go sub.deliver()
+	return sub
+}
+
+func (s *eventSubscription) wants(topic string) bool {
+	return len(s.topics) == 0 || s.topics[topic]
+}
+
+// enqueue adds the given event to the subscriber's queue and returns the number of events waiting to be received.
+func (s *eventSubscription) enqueue(e Event) int {
+	s.mu.Lock()
+	if e.Priority {
+		s.priorityQueue = append(s.priorityQueue, e)
+	} else {
+		s.queue = append(s.queue, e)
+	}
+	s.undelivered++
+	lag := s.undelivered
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+	return lag
+}
+
+func (s *eventSubscription) lag() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.undelivered
+}
+
+func (s *eventSubscription) stats() SubscriberStats {
+	topics := make([]string, 0, len(s.topics))
+	for topic := range s.topics {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return SubscriberStats{
+		Session:               s.session,
+		Topics:                topics,
+		Lag:                   s.lag(),
+		LastDeliveredSequence: s.delivered.Load(),
+	}
+}
+
+// close stops delivery and closes the subscriber's channel. Undelivered events are dropped.
+func (s *eventSubscription) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+func (s *eventSubscription) deliver() {
+	defer close(s.ch)
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 && len(s.priorityQueue) == 0 {
+			s.mu.Unlock()
+			select {
+			case <-s.notify:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+		var e Event
+		if len(s.priorityQueue) > 0 {
+			e = s.priorityQueue[0]
+			s.priorityQueue = s.priorityQueue[1:]
+		} else {
+			e = s.queue[0]
+			s.queue = s.queue[1:]
+		}
+		s.mu.Unlock()
+
+		select {
+		case s.ch <- e:
+			s.delivered.Store(e.Sequence)
+			s.mu.Lock()
+			s.undelivered--
+			s.mu.Unlock()
+		case <-s.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"pkg.world.dev/world-engine/relay/nakama/events"
+	"pkg.world.dev/world-engine/relay/nakama/mocks"
+)
+
+func TestAddressedEventsOnlyReachRecipients(t *testing.T) {
+	ctx := context.Background()
+	nk := mocks.NewNakamaModule(t)
+	assignments := &sync.Map{}
+	assignments.Store("alice", "alice-user-id")
+	assignments.Store("bob", "bob-user-id")
+
+	event := events.Event{
+		Payload:    json.RawMessage(`{"type":"guild-chat","recipients":["alice","carol"]}`),
+		Recipients: []string{"alice", "carol"},
+	}
+	// carol has no user on this relay, and bob is not a recipient.
+	nk.On("NotificationsSend", mock.Anything, []*runtime.NotificationSend{{
+		UserID:  "alice-user-id",
+		Subject: "event",
+		Content: event.Content(),
+		Code:    1,
+	}}).Return(nil).Once()
+	require.NoError(t, sendEventNotifications(ctx, nk, event, assignments))
+
+	broadcast := events.Event{Payload: json.RawMessage(`{"type":"weather"}`)}
+	nk.On("NotificationSendAll", mock.Anything, "event", broadcast.Content(), 1, false).Return(nil).Once()
+	require.NoError(t, sendEventNotifications(ctx, nk, broadcast, assignments))
+
+	// Events addressed only to unknown persona tags are dropped.
+	require.NoError(t, sendEventNotifications(ctx, nk, events.Event{
+		Payload:    json.RawMessage(`{}`),
+		Recipients: []string{"carol"},
+	}, assignments))
+}
@@ -15,7 +15,11 @@ import (
 	"google.golang.org/grpc/codes"
 
 	"pkg.world.dev/world-engine/relay/nakama/allowlist"
+	"pkg.world.dev/world-engine/relay/nakama/discovery"
 	"pkg.world.dev/world-engine/relay/nakama/events"
+	"pkg.world.dev/world-engine/relay/nakama/idempotency"
+	"pkg.world.dev/world-engine/relay/nakama/natstransport"
+	"pkg.world.dev/world-engine/relay/nakama/onboarding"
 	"pkg.world.dev/world-engine/relay/nakama/persona"
 	"pkg.world.dev/world-engine/relay/nakama/signer"
 	"pkg.world.dev/world-engine/relay/nakama/utils"
@@ -104,6 +108,79 @@ func handleShowPersona(txSigner signer.Signer, cardinalAddress string) nakamaRPC
 	}
 }
 
+// OnboardRequest is the payload of the nakama/onboard RPC. The persona tag is only used when the account has not
+// started onboarding yet, or its previous persona tag was rejected.
+type OnboardRequest struct {
+	PersonaTag string `json:"personaTag"`
+}
+
+// handleOnboard runs the onboarding pipeline for the current user, resuming from the step that last failed. While
+// a step is waiting on Cardinal, the user's progress is returned with onboarded set to false; clients call the RPC
+// again to continue.
+func handleOnboard(pipeline *onboarding.Pipeline) nakamaRPCHandler {
+	return func(
+		ctx context.Context,
+		logger runtime.Logger,
+		_ *sql.DB,
+		nk runtime.NakamaModule,
+		payload string,
+	) (string, error) {
+		var req OnboardRequest
+		if payload != "" {
+			if err := json.Unmarshal([]byte(payload), &req); err != nil {
+				return utils.LogErrorWithMessageAndCode(
+					logger,
+					err,
+					codes.InvalidArgument,
+					"unable to unmarshal payload: %v",
+					err)
+			}
+		}
+		state, err := pipeline.Run(ctx, nk, req.PersonaTag)
+		if err == nil || eris.Is(err, onboarding.ErrStepPending) {
+			return utils.MarshalResult(logger, state)
+		}
+		if eris.Is(err, persona.ErrPersonaTagEmpty) {
+			return utils.LogErrorWithMessageAndCode(logger, err, codes.InvalidArgument, "personaTag field is required")
+		}
+		return utils.LogError(logger, err, codes.FailedPrecondition)
+	}
+}
+
+// EventSubscribersReply is the response of the nakama/event-subscribers admin RPC.
+type EventSubscribersReply struct {
+	LatestSequence uint64                   `json:"latestSequence"`
+	Subscribers    []events.SubscriberStats `json:"subscribers"`
+}
+
+// handleEventSubscribers reports the delivery lag of each event hub subscriber. Only the admin may call it.
+func handleEventSubscribers(eventHub *events.EventHub) nakamaRPCHandler {
+	return func(
+		ctx context.Context,
+		logger runtime.Logger,
+		_ *sql.DB,
+		_ runtime.NakamaModule,
+		_ string,
+	) (string, error) {
+		userID, err := utils.GetUserID(ctx)
+		if err != nil {
+			return utils.LogError(logger, err, codes.FailedPrecondition)
+		}
+		if userID != utils.AdminAccountID {
+			return utils.LogErrorWithMessageAndCode(
+				logger,
+				allowlist.ErrPermissionDenied,
+				codes.PermissionDenied,
+				"non-admin user tried to call nakama/event-subscribers",
+			)
+		}
+		return utils.MarshalResult(logger, EventSubscribersReply{
+			LatestSequence: eventHub.LatestSequence(),
+			Subscribers:    eventHub.SubscriberStats(),
+		})
+	}
+}
+
 func handleGenerateKey(ctx context.Context, logger runtime.Logger, _ *sql.DB, nk runtime.NakamaModule, payload string) (
 	string, error,
 ) {
@@ -210,7 +287,8 @@ func handleCardinalRequest(
 	createPayload func(string, string, runtime.NakamaModule, context.Context) (io.Reader, error),
 	notifier *events.Notifier,
 	eventHub *events.EventHub,
-	cardinalAddress string,
+	transport *natstransport.Transport,
+	cardinal *discovery.Endpoints,
 	namespace string,
 	txSigner signer.Signer,
 	autoReClaimPersonaTags bool,
@@ -223,6 +301,19 @@ func handleCardinalRequest(
 		payload string,
 	) (string, error) {
 		logger.Debug("Got request for %q", currEndpoint)
+		// Transactions must be sent to the primary Cardinal. Queries may be served by any healthy replica.
+		cardinalAddress := cardinal.Primary()
+		var requestID string
+		if strings.HasPrefix(currEndpoint, TransactionEndpointPrefix) {
+			// The request ID is not part of the transaction. It's echoed back in the receipt notification.
+			var err error
+			requestID, payload, err = utils.ExtractStringField(payload, events.RequestIDField)
+			if err != nil {
+				return utils.LogErrorWithMessageAndCode(logger, err, codes.InvalidArgument, "invalid request id")
+			}
+		} else {
+			cardinalAddress = cardinal.Read()
+		}
 		// This request may fail if the Cardinal DB has been wiped since Nakama registered this persona tag.
 		// This function will:
 		// 1) Make the initial request. If this succeeds, great. We're done.
@@ -237,7 +328,8 @@ func handleCardinalRequest(
 		if err != nil {
 			return utils.LogErrorWithMessageAndCode(logger, err, codes.FailedPrecondition, "unable to make payload")
 		}
-		result, err := makeRequestAndReadResp(ctx, notifier, currEndpoint, resultPayload, cardinalAddress)
+		result, err := makeRequestAndReadResp(
+			ctx, notifier, transport, currEndpoint, resultPayload, cardinalAddress, requestID)
 		if err == nil {
 			// The request was successful. Return the result.
 			return result, nil
@@ -254,7 +346,7 @@ func handleCardinalRequest(
 		}
 
 		// The rest of this function will attempt to re-register the persona tag and then re-try the initial request.
-		txHash, err := persona.ReclaimPersona(ctx, nk, txSigner, cardinalAddress, namespace)
+		txHash, err := persona.ReclaimPersona(ctx, nk, txSigner, cardinal.Primary(), namespace)
 		if err != nil {
 			logger.Error("failed to re-register the persona tag: %v", err)
 			return initialResult, initialErr
@@ -272,7 +364,8 @@ func handleCardinalRequest(
 		if err != nil {
 			return utils.LogErrorWithMessageAndCode(logger, err, codes.FailedPrecondition, "unable to make payload")
 		}
-		result, err = makeRequestAndReadResp(ctx, notifier, currEndpoint, resultPayload, cardinalAddress)
+		result, err = makeRequestAndReadResp(
+			ctx, notifier, transport, currEndpoint, resultPayload, cardinalAddress, requestID)
 		if err != nil {
 			return utils.LogErrorWithMessageAndCode(logger, err, codes.FailedPrecondition, "")
 		}
@@ -280,6 +373,53 @@ func handleCardinalRequest(
 	}
 }
 
+// withIdempotencyKey wraps a transaction handler so that callers may include an idempotency key in the payload. The
+// first request made with a key is forwarded to the wrapped handler and its result is stored. Retries with the same
+// key return the stored result instead of submitting the transaction to Cardinal again.
+func withIdempotencyKey(currEndpoint string, handler nakamaRPCHandler) nakamaRPCHandler {
+	return func(
+		ctx context.Context,
+		logger runtime.Logger,
+		db *sql.DB,
+		nk runtime.NakamaModule,
+		payload string,
+	) (string, error) {
+		key, payload, err := idempotency.ExtractKey(payload)
+		if err != nil {
+			return utils.LogErrorWithMessageAndCode(logger, err, codes.InvalidArgument, "invalid idempotency key")
+		}
+		if key == "" {
+			return handler(ctx, logger, db, nk, payload)
+		}
+
+		record, replay, err := idempotency.Begin(ctx, nk, key, currEndpoint)
+		switch {
+		case errors.Is(err, idempotency.ErrInProgress):
+			return utils.LogErrorWithMessageAndCode(logger, err, codes.Aborted, "")
+		case errors.Is(err, idempotency.ErrKeyReused):
+			return utils.LogErrorWithMessageAndCode(logger, err, codes.InvalidArgument, "")
+		case err != nil:
+			return utils.LogErrorWithMessageAndCode(logger, err, codes.FailedPrecondition, "")
+		case replay:
+			logger.Debug("returning stored result for idempotency key %q", key)
+			return record.Result, nil
+		}
+
+		result, err := handler(ctx, logger, db, nk, payload)
+		if err != nil {
+			// The request failed, so release the key to allow the caller to retry.
+			if abandonErr := idempotency.Abandon(ctx, nk, key); abandonErr != nil {
+				logger.Error("failed to release idempotency key %q: %v", key, abandonErr)
+			}
+			return result, err
+		}
+		if err := idempotency.Complete(ctx, nk, key, currEndpoint, result); err != nil {
+			logger.Error("failed to store result for idempotency key %q: %v", key, err)
+		}
+		return result, nil
+	}
+}
+
 func blockUntilPersonaTagTxHasBeenProcessed(logger runtime.Logger, eventHub *events.EventHub, txHash string) {
 	ch := eventHub.SubscribeToReceipts(txHash)
 	defer func() {
@@ -0,0 +1,176 @@
+// Package idempotency lets Nakama RPC callers attach an idempotency key to a Cardinal transaction so that retrying
+// the RPC after a timeout returns the original outcome instead of submitting the action to Cardinal a second time.
+//
+// Callers add an "idempotencyKey" field to the RPC payload. The key is removed from the payload before it is signed
+// and forwarded to Cardinal. Keys are scoped to the calling user and expire after DefaultTTL.
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/relay/nakama/utils"
+)
+
+const (
+	Collection   = "idempotency_keys"
+	PayloadField = "idempotencyKey"
+	DefaultTTL   = 24 * time.Hour
+
+	statusPending  = "pending"
+	statusComplete = "complete"
+)
+
+var (
+	ErrInProgress = errors.New("a request with this idempotency key is still in progress")
+	ErrKeyReused  = errors.New("idempotency key has already been used for a different endpoint")
+)
+
+// Record is the stored outcome of a request made with an idempotency key.
+type Record struct {
+	Endpoint string `json:"endpoint"`
+	Status   string `json:"status"`
+	// TxHash is the hash of the transaction Cardinal accepted, if any.
+	TxHash string `json:"txHash,omitempty"`
+	// Result is the original response returned to the caller.
+	Result    string `json:"result,omitempty"`
+	CreatedAt int64  `json:"createdAt"`
+
+	version string
+}
+
+func (r *Record) expired(now time.Time) bool {
+	return now.Sub(time.Unix(r.CreatedAt, 0)) > DefaultTTL
+}
+
+// ExtractKey removes the idempotency key from the given RPC payload. If the payload is not a JSON object or has no
+// idempotency key, the key is empty and the payload is returned unchanged.
+func ExtractKey(payload string) (key string, rest string, err error) {
+	return utils.ExtractStringField(payload, PayloadField)
+}
+
+// Begin reserves the given idempotency key for a request to endpoint made by the current user. If the key has
+// already been used for a completed request, replay is true and the caller should reply with the stored record's
+// Result instead of making the request again. Otherwise, the caller must call either Complete or Abandon once the
+// request finishes.
+func Begin(ctx context.Context, nk runtime.NakamaModule, key, endpoint string) (rec *Record, replay bool, err error) {
+	userID, err := utils.GetUserID(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	pending := &Record{
+		Endpoint:  endpoint,
+		Status:    statusPending,
+		CreatedAt: time.Now().Unix(),
+		// The "*" version only allows the write if the key does not already exist.
+		version: "*",
+	}
+	writeErr := write(ctx, nk, userID, key, pending)
+	if writeErr == nil {
+		return pending, false, nil
+	}
+
+	existing, found, err := load(ctx, nk, userID, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if !found {
+		return nil, false, eris.Wrap(writeErr, "failed to reserve idempotency key")
+	}
+	if existing.expired(time.Now()) {
+		// Replace the expired record. If this fails, another request has claimed the key in the meantime.
+		pending.version = existing.version
+		if err := write(ctx, nk, userID, key, pending); err != nil {
+			return nil, false, eris.Wrap(ErrInProgress, "")
+		}
+		return pending, false, nil
+	}
+	if existing.Endpoint != endpoint {
+		return nil, false, eris.Wrapf(ErrKeyReused, "key was used for %q", existing.Endpoint)
+	}
+	if existing.Status != statusComplete {
+		return nil, false, eris.Wrap(ErrInProgress, "")
+	}
+	return existing, true, nil
+}
+
+// Complete records the result of a request made with the given idempotency key so that retries receive it.
+func Complete(ctx context.Context, nk runtime.NakamaModule, key, endpoint, result string) error {
+	userID, err := utils.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+	var tx struct {
+		TxHash string
+	}
+	// Not every result is a transaction response; a missing hash is fine.
+	_ = json.Unmarshal([]byte(result), &tx)
+	return write(ctx, nk, userID, key, &Record{
+		Endpoint:  endpoint,
+		Status:    statusComplete,
+		TxHash:    tx.TxHash,
+		Result:    result,
+		CreatedAt: time.Now().Unix(),
+	})
+}
+
+// Abandon releases an idempotency key whose request failed, so that it can be retried.
+func Abandon(ctx context.Context, nk runtime.NakamaModule, key string) error {
+	userID, err := utils.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+	return eris.Wrap(nk.StorageDelete(ctx, []*runtime.StorageDelete{
+		{
+			Collection: Collection,
+			Key:        key,
+			UserID:     userID,
+		},
+	}), "")
+}
+
+func load(ctx context.Context, nk runtime.NakamaModule, userID, key string) (rec *Record, found bool, err error) {
+	objs, err := nk.StorageRead(ctx, []*runtime.StorageRead{
+		{
+			Collection: Collection,
+			Key:        key,
+			UserID:     userID,
+		},
+	})
+	if err != nil {
+		return nil, false, eris.Wrap(err, "")
+	}
+	if len(objs) == 0 {
+		return nil, false, nil
+	}
+	rec = &Record{}
+	if err := json.Unmarshal([]byte(objs[0].GetValue()), rec); err != nil {
+		return nil, false, eris.Wrap(err, "unable to unmarshal idempotency record")
+	}
+	rec.version = objs[0].GetVersion()
+	return rec, true, nil
+}
+
+func write(ctx context.Context, nk runtime.NakamaModule, userID, key string, rec *Record) error {
+	buf, err := json.Marshal(rec)
+	if err != nil {
+		return eris.Wrap(err, "unable to marshal idempotency record")
+	}
+	_, err = nk.StorageWrite(ctx, []*runtime.StorageWrite{
+		{
+			Collection:      Collection,
+			Key:             key,
+			UserID:          userID,
+			Value:           string(buf),
+			Version:         rec.version,
+			PermissionRead:  runtime.STORAGE_PERMISSION_NO_READ,
+			PermissionWrite: runtime.STORAGE_PERMISSION_NO_WRITE,
+		},
+	})
+	return eris.Wrap(err, "")
+}
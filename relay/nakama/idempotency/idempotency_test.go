@@ -0,0 +1,68 @@
+package idempotency
+
+import (
+	"testing"
+
+	"pkg.world.dev/world-engine/assert"
+	"pkg.world.dev/world-engine/relay/nakama/testutils"
+)
+
+func TestExtractKey(t *testing.T) {
+	key, rest, err := ExtractKey(`{"idempotencyKey":"abc","direction":"up"}`)
+	assert.NilError(t, err)
+	assert.Equal(t, key, "abc")
+	assert.Equal(t, rest, `{"direction":"up"}`)
+
+	key, rest, err = ExtractKey(`{"direction":"up"}`)
+	assert.NilError(t, err)
+	assert.Equal(t, key, "")
+	assert.Equal(t, rest, `{"direction":"up"}`)
+
+	_, _, err = ExtractKey(`{"idempotencyKey":123}`)
+	assert.Check(t, err != nil)
+}
+
+func TestRetryReturnsOriginalResult(t *testing.T) {
+	ctx := testutils.CtxWithUserID("user-1")
+	nk := testutils.NewFakeNakamaModule()
+	endpoint := "tx/game/move"
+	result := `{"TxHash":"0xabc","Tick":5}`
+
+	_, replay, err := Begin(ctx, nk, "key-1", endpoint)
+	assert.NilError(t, err)
+	assert.False(t, replay)
+
+	// A retry before the first request has finished must not be forwarded to Cardinal.
+	_, _, err = Begin(ctx, nk, "key-1", endpoint)
+	assert.ErrorIs(t, err, ErrInProgress)
+
+	assert.NilError(t, Complete(ctx, nk, "key-1", endpoint, result))
+
+	rec, replay, err := Begin(ctx, nk, "key-1", endpoint)
+	assert.NilError(t, err)
+	assert.True(t, replay)
+	assert.Equal(t, rec.Result, result)
+	assert.Equal(t, rec.TxHash, "0xabc")
+
+	_, _, err = Begin(ctx, nk, "key-1", "tx/game/attack")
+	assert.ErrorIs(t, err, ErrKeyReused)
+
+	// Keys are scoped to the calling user.
+	_, replay, err = Begin(testutils.CtxWithUserID("user-2"), nk, "key-1", endpoint)
+	assert.NilError(t, err)
+	assert.False(t, replay)
+}
+
+func TestAbandonedKeyCanBeReused(t *testing.T) {
+	ctx := testutils.CtxWithUserID("user-1")
+	nk := testutils.NewFakeNakamaModule()
+	endpoint := "tx/game/move"
+
+	_, _, err := Begin(ctx, nk, "key-1", endpoint)
+	assert.NilError(t, err)
+	assert.NilError(t, Abandon(ctx, nk, "key-1"))
+
+	_, replay, err := Begin(ctx, nk, "key-1", endpoint)
+	assert.NilError(t, err)
+	assert.False(t, replay)
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -17,7 +18,9 @@ import (
 	"google.golang.org/api/option"
 
 	"pkg.world.dev/world-engine/relay/nakama/auth"
+	"pkg.world.dev/world-engine/relay/nakama/discovery"
 	"pkg.world.dev/world-engine/relay/nakama/events"
+	"pkg.world.dev/world-engine/relay/nakama/natstransport"
 	"pkg.world.dev/world-engine/relay/nakama/persona"
 	"pkg.world.dev/world-engine/relay/nakama/signer"
 	"pkg.world.dev/world-engine/relay/nakama/utils"
@@ -25,14 +28,30 @@ import (
 
 const (
 	EnvCardinalAddr           = "CARDINAL_ADDR"
+	EnvCardinalReplicaAddrs   = "CARDINAL_REPLICA_ADDRS"
 	EnvCardinalNamespace      = "CARDINAL_NAMESPACE"
 	EnvKMSCredentialsFile     = "GCP_KMS_CREDENTIALS_FILE" // #nosec G101
 	EnvKMSKeyName             = "GCP_KMS_KEY_NAME"
+	EnvEventSubscriberMaxLag  = "EVENT_SUBSCRIBER_MAX_LAG"
+	EnvEventEncoding          = "CARDINAL_EVENT_ENCODING"
+	EnvEventCompression       = "CARDINAL_EVENT_COMPRESSION"
 	WorldEndpoint             = "world"
 	EventEndpoint             = "events"
 	TransactionEndpointPrefix = "tx/"
 )
 
+const (
+	// EnvCardinalNATSURL is the URL of the NATS server to submit transactions and receive events through, instead of
+	// HTTP and the event websocket. Cardinal must be started with cardinal.WithNATS and the same server.
+	EnvCardinalNATSURL = "CARDINAL_NATS_URL"
+	// EnvCardinalNATSConsumerGroup is the durable consumer group the relay reads events with. It defaults to the
+	// host name, so that every relay receives every event. Relays that share a group split the events between them.
+	EnvCardinalNATSConsumerGroup = "CARDINAL_NATS_CONSUMER_GROUP"
+	// EnvEventStreamRoutes routes the events of topics to the Nakama group and party streams named by the events,
+	// which every member of the group or party receives. See events.ParseStreamRoutes for the format.
+	EnvEventStreamRoutes = "EVENT_STREAM_ROUTES"
+)
+
 func InitModule(
 	ctx context.Context,
 	logger runtime.Logger,
@@ -42,17 +61,23 @@ func InitModule(
 ) error {
 	utils.DebugEnabled = getDebugModeFromEnvironment()
 
-	cardinalAddress, err := initCardinalAddress()
+	cardinal, err := initCardinalEndpoints(ctx, logger)
 	if err != nil {
 		return eris.Wrap(err, "failed to init cardinal address")
 	}
+	cardinalAddress := cardinal.Primary()
 
 	globalNamespace, err := initNamespace()
 	if err != nil {
 		return eris.Wrap(err, "failed to init globalNamespace")
 	}
 
-	eventHub, err := initEventHub(ctx, logger, nk, EventEndpoint, cardinalAddress)
+	transport, err := initNATS(globalNamespace)
+	if err != nil {
+		return eris.Wrap(err, "failed to init NATS transport")
+	}
+
+	eventHub, err := initEventHub(logger, nk, EventEndpoint, cardinal, transport)
 	if err != nil {
 		return eris.Wrap(err, "failed to init event hub")
 	}
@@ -74,6 +99,10 @@ func InitModule(
 	); err != nil {
 		return eris.Wrap(err, "failed to init persona tag assignment map")
 	}
+	forwardEvents(ctx, logger, nk, eventHub, globalPersonaAssignment)
+	if err := initEventStreams(logger, nk, eventHub); err != nil {
+		return eris.Wrap(err, "failed to init event streams")
+	}
 
 	verifier := persona.NewVerifier(logger, nk, eventHub)
 
@@ -90,18 +119,39 @@ func InitModule(
 		return eris.Wrap(err, "failed to init persona tag endpoints")
 	}
 
-	if err := initCardinalEndpoints(
+	onboardingPipeline, err := newOnboardingPipeline(
+		verifier,
+		notifier,
+		txSigner,
+		transport,
+		cardinalAddress,
+		globalNamespace,
+		globalPersonaAssignment,
+	)
+	if err != nil {
+		return eris.Wrap(err, "failed to configure onboarding")
+	}
+	if err := initOnboarding(logger, initializer, onboardingPipeline); err != nil {
+		return eris.Wrap(err, "failed to init onboarding endpoint")
+	}
+
+	if err := initCardinalRPCs(
 		logger,
 		initializer,
 		notifier,
 		eventHub,
 		txSigner,
-		cardinalAddress,
+		transport,
+		cardinal,
 		globalNamespace,
 	); err != nil {
 		return eris.Wrap(err, "failed to init cardinal endpoints")
 	}
 
+	if err := initEventHubAdmin(logger, initializer, eventHub); err != nil {
+		return eris.Wrap(err, "failed to init event hub admin endpoint")
+	}
+
 	if err := initAllowlist(logger, initializer); err != nil {
 		return eris.Wrap(err, "failed to init allowlist endpoints")
 	}
@@ -145,13 +195,52 @@ func selectSigner(ctx context.Context, logger runtime.Logger, nk runtime.NakamaM
 }
 
 func initEventHub(
-	ctx context.Context,
 	log runtime.Logger,
 	nk runtime.NakamaModule,
 	eventsEndpoint string,
-	cardinalAddress string,
+	cardinal *discovery.Endpoints,
+	transport *natstransport.Transport,
 ) (*events.EventHub, error) {
-	eventHub, err := events.NewEventHub(log, eventsEndpoint, cardinalAddress)
+	opts := []events.Option{events.WithMetrics(nk), events.WithFailover(cardinal.Failover)}
+	if transport != nil {
+		group := os.Getenv(EnvCardinalNATSConsumerGroup)
+		if group == "" {
+			hostname, err := os.Hostname()
+			if err != nil {
+				return nil, eris.Wrapf(err, "%s is not set, and the host name is unknown", EnvCardinalNATSConsumerGroup)
+			}
+			group = "relay-" + hostname
+		}
+		source, err := transport.Events(group)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, events.WithFrameSource(source))
+	}
+	if maxLagStr := os.Getenv(EnvEventSubscriberMaxLag); maxLagStr != "" {
+		maxLag, err := strconv.Atoi(maxLagStr)
+		if err != nil {
+			return nil, eris.Wrapf(err, "%s must be an integer, got %q", EnvEventSubscriberMaxLag, maxLagStr)
+		}
+		opts = append(opts, events.WithMaxSubscriberLag(maxLag))
+	}
+	switch encoding := os.Getenv(EnvEventEncoding); encoding {
+	case "", "json":
+	case "binary":
+		opts = append(opts, events.WithBinaryFrames())
+	default:
+		return nil, eris.Errorf("%s must be json or binary, got %q", EnvEventEncoding, encoding)
+	}
+	if compressionStr := os.Getenv(EnvEventCompression); compressionStr != "" {
+		compression, err := strconv.ParseBool(compressionStr)
+		if err != nil {
+			return nil, eris.Wrapf(err, "%s must be a boolean, got %q", EnvEventCompression, compressionStr)
+		}
+		if compression {
+			opts = append(opts, events.WithCompression())
+		}
+	}
+	eventHub, err := events.NewEventHub(log, eventsEndpoint, cardinal.Primary(), opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -162,25 +251,68 @@ func initEventHub(
 		}
 	}()
 
-	// Send Events to everyone via Nakama Notifications
+	return eventHub, nil
+}
+
+// forwardEvents sends Cardinal's events to users via Nakama notifications. Events that are addressed to specific
+// persona tags are only sent to the users that own those persona tags; all other events are sent to everyone.
+func forwardEvents(
+	ctx context.Context,
+	log runtime.Logger,
+	nk runtime.NakamaModule,
+	eventHub *events.EventHub,
+	globalPersonaAssignment *sync.Map,
+) {
 	go func() {
-		ch := eventHub.SubscribeToEvents("main")
+		ch := eventHub.Subscribe("main")
 		for event := range ch {
-			content := make(map[string]any)
-			err := json.Unmarshal(event, &content)
-			if err != nil {
-				// The event content isn't in JSON format. Wrap whatever it is in a JSON blob.
-				content["message"] = string(event)
-			}
-
-			err = eris.Wrap(nk.NotificationSendAll(ctx, "event", content, 1, false), "")
-			if err != nil {
+			if err := sendEventNotifications(ctx, nk, event, globalPersonaAssignment); err != nil {
 				log.Error("error sending notifications: %s", eris.ToString(err, true))
 			}
 		}
 	}()
+}
 
-	return eventHub, nil
+// initEventStreams sends the events of the topics in EnvEventStreamRoutes to the Nakama group and party streams they
+// are routed to, which every member of the group or party receives.
+func initEventStreams(log runtime.Logger, nk runtime.NakamaModule, eventHub *events.EventHub) error {
+	routes, err := events.ParseStreamRoutes(os.Getenv(EnvEventStreamRoutes))
+	if err != nil {
+		return eris.Wrapf(err, "invalid %s", EnvEventStreamRoutes)
+	}
+	events.NewStreamFanout(nk, routes).Forward(log, eventHub)
+	return nil
+}
+
+func sendEventNotifications(
+	ctx context.Context,
+	nk runtime.NakamaModule,
+	event events.Event,
+	globalPersonaAssignment *sync.Map,
+) error {
+	if len(event.Recipients) == 0 {
+		return eris.Wrap(nk.NotificationSendAll(ctx, "event", event.Content(), 1, false), "")
+	}
+	notifications := make([]*runtime.NotificationSend, 0, len(event.Recipients))
+	for _, personaTag := range event.Recipients {
+		value, ok := globalPersonaAssignment.Load(personaTag)
+		if !ok {
+			// The persona tag was not claimed through this relay, so there is nobody to notify.
+			continue
+		}
+		userID, _ := value.(string)
+		notifications = append(notifications, &runtime.NotificationSend{
+			UserID:     userID,
+			Subject:    "event",
+			Content:    event.Content(),
+			Code:       1,
+			Persistent: false,
+		})
+	}
+	if len(notifications) == 0 {
+		return nil
+	}
+	return eris.Wrap(nk.NotificationsSend(ctx, notifications), "")
 }
 
 // initPersonaTagAssignmentMap initializes a sync.Map with all the existing mappings of PersonaTag->UserID. This
@@ -222,18 +354,19 @@ func initPersonaTagAssignmentMap(
 	return nil
 }
 
-// initCardinalEndpoints queries the cardinal server to find the list of existing endpoints, and attempts to
+// initCardinalRPCs queries the cardinal server to find the list of existing endpoints, and attempts to
 // set up RPC wrappers around each one.
-func initCardinalEndpoints(
+func initCardinalRPCs(
 	logger runtime.Logger,
 	initializer runtime.Initializer,
 	notifier *events.Notifier,
 	eventHub *events.EventHub,
 	txSigner signer.Signer,
-	cardinalAddress string,
+	transport *natstransport.Transport,
+	cardinal *discovery.Endpoints,
 	globalNamespace string,
 ) error {
-	txEndpoints, queryEndpoints, err := getCardinalEndpoints(cardinalAddress)
+	txEndpoints, queryEndpoints, err := getCardinalEndpoints(cardinal.Primary())
 	if err != nil {
 		return err
 	}
@@ -242,7 +375,7 @@ func initCardinalEndpoints(
 	) (io.Reader, error) {
 		logger.Debug("The %s endpoint requires a signed payload", endpoint)
 		var transaction io.Reader
-		transaction, err = makeTransaction(ctx, nk, txSigner, payload, cardinalAddress, globalNamespace)
+		transaction, err = makeTransaction(ctx, nk, txSigner, payload, cardinal.Primary(), globalNamespace)
 		if err != nil {
 			return nil, err
 		}
@@ -271,7 +404,8 @@ func initCardinalEndpoints(
 		eventHub,
 		txEndpoints,
 		createTransaction,
-		cardinalAddress,
+		transport,
+		cardinal,
 		globalNamespace,
 		txSigner,
 		true,
@@ -291,7 +425,8 @@ func initCardinalEndpoints(
 		eventHub,
 		queryEndpoints,
 		createUnsignedTransaction,
-		cardinalAddress,
+		transport,
+		cardinal,
 		globalNamespace,
 		txSigner,
 		false)
@@ -332,12 +467,28 @@ func makeTransaction(ctx context.Context,
 	return bytes.NewReader(buf), nil
 }
 
-func initCardinalAddress() (string, error) {
+// initCardinalEndpoints reads the primary and read replica Cardinal addresses from the environment, resolves them,
+// and starts checking their health in the background. Addresses can be host:port pairs, whose host names are
+// resolved to all of their A and AAAA records, or DNS SRV record names prefixed with discovery.SRVPrefix.
+func initCardinalEndpoints(ctx context.Context, logger runtime.Logger) (*discovery.Endpoints, error) {
 	globalCardinalAddress := os.Getenv(EnvCardinalAddr)
 	if globalCardinalAddress == "" {
-		return "", eris.Errorf("must specify a cardinal server via %s", EnvCardinalAddr)
+		return nil, eris.Errorf("must specify a cardinal server via %s", EnvCardinalAddr)
+	}
+	cardinal := discovery.New(globalCardinalAddress, discovery.ParseReplicas(os.Getenv(EnvCardinalReplicaAddrs))...)
+	cardinal.Resolve(ctx, logger)
+	go cardinal.Watch(ctx, logger, discovery.DefaultHealthCheckInterval)
+	return cardinal, nil
+}
+
+// initNATS connects to the NATS server in EnvCardinalNATSURL, if it is set. Otherwise it returns nil, and Cardinal is
+// reached through HTTP and the event websocket.
+func initNATS(namespace string) (*natstransport.Transport, error) {
+	url := os.Getenv(EnvCardinalNATSURL)
+	if url == "" {
+		return nil, nil //nolint:nilnil // NATS is optional
 	}
-	return globalCardinalAddress, nil
+	return natstransport.Connect(url, namespace)
 }
 
 func initNamespace() (string, error) {
@@ -0,0 +1,155 @@
+// Package natstransport submits transactions to Cardinal and receives its events through NATS JetStream, as an
+// alternative to HTTP and the event websocket. Cardinal must be started with cardinal.WithNATS.
+//
+// Transactions are published to a stream that Cardinal consumes, so a transaction that is published while Cardinal is
+// unreachable is processed once Cardinal is back. Events are read from a stream with a durable consumer, so a relay
+// that loses its connection resumes from the first event it didn't acknowledge.
+package natstransport
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rotisserie/eris"
+)
+
+// The subjects and headers must match the ones in cardinal/server/nats.go.
+const (
+	replyHeader    = "Cardinal-Reply-To"
+	statusHeader   = "Cardinal-Status"
+	encodingHeader = "Cardinal-Encoding"
+	binaryEncoding = "binary"
+
+	// DefaultReplyTimeout is how long SubmitTx waits for Cardinal's reply, if the context has no deadline.
+	DefaultReplyTimeout = 30 * time.Second
+)
+
+func txSubject(namespace, group, name string) string {
+	return fmt.Sprintf("cardinal.%s.tx.%s.%s", namespace, group, name)
+}
+
+func eventSubject(namespace string) string {
+	return fmt.Sprintf("cardinal.%s.events", namespace)
+}
+
+// Transport is a connection to the NATS server that Cardinal uses.
+type Transport struct {
+	conn      *nats.Conn
+	js        nats.JetStreamContext
+	namespace string
+}
+
+// Connect connects to the NATS server at the URL, and keeps reconnecting to it whenever the connection is lost.
+func Connect(url, namespace string) (*Transport, error) {
+	conn, err := nats.Connect(url,
+		nats.Name("relay-"+namespace),
+		nats.MaxReconnects(-1),
+		nats.RetryOnFailedConnect(true),
+	)
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to connect to NATS at %s", url)
+	}
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, eris.Wrap(err, "failed to use JetStream")
+	}
+	return &Transport{conn: conn, js: js, namespace: namespace}, nil
+}
+
+// SubmitTx publishes the signed transaction for the transaction endpoint, e.g. "tx/game/move", and returns Cardinal's
+// reply, which is the same as the reply of the HTTP endpoint. The transaction's hash is its NATS message ID, so a
+// transaction that is submitted again while JetStream still remembers it is discarded.
+func (t *Transport) SubmitTx(ctx context.Context, endpoint string, payload io.Reader) ([]byte, error) {
+	parts := strings.Split(strings.TrimPrefix(endpoint, "/"), "/")
+	if len(parts) != 3 || parts[0] != "tx" { //nolint:gomnd // tx/group/name
+		return nil, eris.Errorf("%q is not a transaction endpoint", endpoint)
+	}
+	data, err := io.ReadAll(payload)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to read transaction")
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultReplyTimeout)
+		defer cancel()
+	}
+
+	inbox := t.conn.NewRespInbox()
+	replies, err := t.conn.SubscribeSync(inbox)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to subscribe to transaction reply")
+	}
+	defer func() {
+		_ = replies.Unsubscribe()
+	}()
+
+	msg := nats.NewMsg(txSubject(t.namespace, parts[1], parts[2]))
+	msg.Header.Set(replyHeader, inbox)
+	msg.Data = data
+	hash := sha256.Sum256(data)
+	if _, err := t.js.PublishMsg(msg, nats.MsgId(hex.EncodeToString(hash[:])), nats.Context(ctx)); err != nil {
+		return nil, eris.Wrapf(err, "failed to publish transaction for endpoint %q", endpoint)
+	}
+
+	reply, err := replies.NextMsgWithContext(ctx)
+	if err != nil {
+		return nil, eris.Wrapf(err, "transaction for endpoint %q was queued, but Cardinal did not reply", endpoint)
+	}
+	status, err := strconv.Atoi(reply.Header.Get(statusHeader))
+	if err != nil {
+		return nil, eris.Wrap(err, "invalid status in transaction reply")
+	}
+	if status != http.StatusOK {
+		return nil, eris.Errorf("bad status code: %d %s: %s", status, http.StatusText(status), reply.Data)
+	}
+	return reply.Data, nil
+}
+
+// Events returns a source of Cardinal's tick results, which reads the event stream with the durable consumer group.
+// Relays in the same group share its events, each event being received by one of them, and relays in different groups
+// each receive every event. A group that doesn't exist yet starts with the next event.
+func (t *Transport) Events(group string) (*EventSource, error) {
+	sub, err := t.js.QueueSubscribeSync(eventSubject(t.namespace), group,
+		nats.Durable(group), nats.ManualAck(), nats.AckExplicit(), nats.DeliverNew())
+	if err != nil {
+		return nil, eris.Wrapf(err, "failed to consume the event stream as %q", group)
+	}
+	return &EventSource{sub: sub}, nil
+}
+
+// Close drains the connection, waiting for the messages that were received to be handled.
+func (t *Transport) Close() error {
+	return eris.Wrap(t.conn.Drain(), "")
+}
+
+// EventSource reads Cardinal's tick results from the event stream. It implements events.FrameSource.
+type EventSource struct {
+	sub *nats.Subscription
+}
+
+// Next blocks until the next tick results are received. Call ack once they are dispatched; tick results that are
+// never acknowledged are received again.
+func (s *EventSource) Next() (frame []byte, binary bool, ack func(), err error) {
+	msg, err := s.sub.NextMsgWithContext(context.Background())
+	if err != nil {
+		return nil, false, nil, eris.Wrap(err, "failed to receive tick results")
+	}
+	ack = func() {
+		_ = msg.Ack()
+	}
+	return msg.Data, msg.Header.Get(encodingHeader) == binaryEncoding, ack, nil
+}
+
+// Close stops receiving tick results.
+func (s *EventSource) Close() error {
+	return eris.Wrap(s.sub.Unsubscribe(), "")
+}
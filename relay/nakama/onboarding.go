@@ -0,0 +1,156 @@
+package main
+
+// onboarding.go defines the steps of the onboarding pipeline that new accounts go through.
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/relay/nakama/events"
+	"pkg.world.dev/world-engine/relay/nakama/natstransport"
+	"pkg.world.dev/world-engine/relay/nakama/onboarding"
+	"pkg.world.dev/world-engine/relay/nakama/persona"
+	"pkg.world.dev/world-engine/relay/nakama/signer"
+)
+
+const (
+	// EnvOnboardingSeedEndpoint is the transaction endpoint (e.g. "tx/game/create-player") that is called on behalf of
+	// the user to create their starting entities once their persona tag has been accepted. If unset, onboarding does
+	// not seed anything.
+	EnvOnboardingSeedEndpoint = "ONBOARDING_SEED_ENDPOINT"
+	// EnvOnboardingSeedPayload is the JSON message sent to the seed endpoint. It defaults to an empty object.
+	EnvOnboardingSeedPayload = "ONBOARDING_SEED_PAYLOAD"
+
+	seedTxHashKey = "seedTxHash"
+)
+
+// newOnboardingPipeline returns the onboarding pipeline configured by the environment: claim a persona tag, wait
+// for Cardinal to accept it, and optionally seed the player's starting entities with a game transaction.
+func newOnboardingPipeline(
+	verifier *persona.Verifier,
+	notifier *events.Notifier,
+	txSigner signer.Signer,
+	transport *natstransport.Transport,
+	cardinalAddress string,
+	globalNamespace string,
+	globalPersonaAssignment *sync.Map,
+) (*onboarding.Pipeline, error) {
+	steps := []onboarding.Step{
+		claimPersonaStep(verifier, notifier, txSigner, cardinalAddress, globalNamespace, globalPersonaAssignment),
+		awaitPersonaStep(txSigner, cardinalAddress),
+	}
+	if endpoint := os.Getenv(EnvOnboardingSeedEndpoint); endpoint != "" {
+		payload := os.Getenv(EnvOnboardingSeedPayload)
+		if payload == "" {
+			payload = "{}"
+		}
+		if !json.Valid([]byte(payload)) {
+			return nil, eris.Errorf("%s must be valid json, got %q", EnvOnboardingSeedPayload, payload)
+		}
+		steps = append(steps,
+			seedPlayerStep(notifier, txSigner, transport, cardinalAddress, globalNamespace, endpoint, payload))
+	}
+	return onboarding.NewPipeline(steps...), nil
+}
+
+// claimPersonaStep reserves the persona tag in Nakama and submits the create-persona transaction to Cardinal.
+func claimPersonaStep(
+	verifier *persona.Verifier,
+	notifier *events.Notifier,
+	txSigner signer.Signer,
+	cardinalAddress string,
+	globalNamespace string,
+	globalPersonaAssignment *sync.Map,
+) onboarding.Step {
+	return onboarding.Step{
+		Name: "claim-persona",
+		Run: func(ctx context.Context, nk runtime.NakamaModule, state *onboarding.State) error {
+			if state.PersonaTag == "" {
+				return persona.ErrPersonaTagEmpty
+			}
+			ptr, err := persona.LoadPersonaTagStorageObj(ctx, nk)
+			if err == nil && ptr.PersonaTag == state.PersonaTag && ptr.Status != persona.StatusRejected {
+				// The tag was claimed by an earlier attempt that failed before this step was saved as complete.
+				return nil
+			}
+			_, err = persona.ClaimPersona(
+				ctx,
+				nk,
+				verifier,
+				notifier,
+				&persona.StorageObj{PersonaTag: state.PersonaTag},
+				txSigner,
+				cardinalAddress,
+				globalNamespace,
+				globalPersonaAssignment,
+			)
+			return err
+		},
+	}
+}
+
+// awaitPersonaStep waits until Cardinal has accepted or rejected the claimed persona tag. If the tag was rejected,
+// onboarding starts over so the user can pick another tag.
+func awaitPersonaStep(txSigner signer.Signer, cardinalAddress string) onboarding.Step {
+	return onboarding.Step{
+		Name: "await-persona",
+		Run: func(ctx context.Context, nk runtime.NakamaModule, state *onboarding.State) error {
+			ptr, err := persona.LoadPersonaTagStorageObj(ctx, nk)
+			if err != nil {
+				return err
+			}
+			ptr, err = ptr.AttemptToUpdatePending(ctx, nk, txSigner, cardinalAddress)
+			if err != nil {
+				return err
+			}
+			switch ptr.Status {
+			case persona.StatusAccepted:
+				return nil
+			case persona.StatusRejected:
+				state.Reset()
+				return eris.Errorf("persona tag %q was rejected", ptr.PersonaTag)
+			default:
+				return eris.Wrapf(onboarding.ErrStepPending, "persona tag %q is %s", ptr.PersonaTag, ptr.Status)
+			}
+		},
+	}
+}
+
+// seedPlayerStep sends the configured game transaction on behalf of the user to create their starting entities.
+func seedPlayerStep(
+	notifier *events.Notifier,
+	txSigner signer.Signer,
+	transport *natstransport.Transport,
+	cardinalAddress string,
+	globalNamespace string,
+	endpoint string,
+	payload string,
+) onboarding.Step {
+	return onboarding.Step{
+		Name: "seed-player",
+		Run: func(ctx context.Context, nk runtime.NakamaModule, state *onboarding.State) error {
+			if state.Data[seedTxHashKey] != "" {
+				return nil
+			}
+			tx, err := makeTransaction(ctx, nk, txSigner, payload, cardinalAddress, globalNamespace)
+			if err != nil {
+				return err
+			}
+			result, err := makeRequestAndReadResp(ctx, notifier, transport, endpoint, tx, cardinalAddress, "")
+			if err != nil {
+				return err
+			}
+			var txResponse persona.TxResponse
+			if err := json.Unmarshal([]byte(result), &txResponse); err != nil {
+				return eris.Wrap(err, "failed to decode seed transaction response")
+			}
+			state.Data[seedTxHashKey] = txResponse.TxHash
+			return nil
+		},
+	}
+}
@@ -0,0 +1,171 @@
+// Package onboarding runs the steps that turn a new Nakama account into a player that is ready to play, such as
+// claiming a persona tag and seeding the player's starting entities in Cardinal.
+//
+// Each user's progress is saved to Nakama storage after every step. If a step fails, the next call to Run resumes
+// from that step instead of starting over, so an account is never stranded halfway through onboarding.
+package onboarding
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/rotisserie/eris"
+
+	"pkg.world.dev/world-engine/relay/nakama/utils"
+)
+
+const (
+	Collection = "onboarding"
+	StateKey   = "state"
+)
+
+// ErrStepPending is returned by a step that has started but cannot finish yet, for example because it is waiting
+// for a Cardinal tick. The pipeline stops at that step and resumes from it on the next call to Run.
+var ErrStepPending = errors.New("onboarding step is still in progress")
+
+// Step is a single step of the onboarding pipeline.
+type Step struct {
+	// Name identifies the step in the saved onboarding state. It must not change between releases.
+	Name string
+	// Run performs the step. Because onboarding resumes from the step that failed, Run may be called again for a user
+	// whose previous attempt failed partway through the step.
+	Run func(ctx context.Context, nk runtime.NakamaModule, state *State) error
+}
+
+// State is the onboarding progress of a single user.
+type State struct {
+	PersonaTag string `json:"personaTag"`
+	// Completed lists the names of the steps that have finished, in order.
+	Completed []string `json:"completed"`
+	Onboarded bool     `json:"onboarded"`
+	// CurrentStep is the step that failed or is still pending, if any.
+	CurrentStep string `json:"currentStep,omitempty"`
+	LastError   string `json:"lastError,omitempty"`
+	UpdatedAt   int64  `json:"updatedAt"`
+	// Data holds values that steps want to keep between attempts, such as the hash of a transaction they submitted.
+	Data map[string]string `json:"data,omitempty"`
+}
+
+func (s *State) isCompleted(step string) bool {
+	for _, name := range s.Completed {
+		if name == step {
+			return true
+		}
+	}
+	return false
+}
+
+// Reset discards all progress, so that the next call to Run starts from the first step. Steps call this when an
+// earlier step's outcome turns out to be unusable, like a persona tag that Cardinal rejected.
+func (s *State) Reset() {
+	s.Completed = nil
+	s.Data = nil
+	s.Onboarded = false
+}
+
+// Pipeline is an ordered list of onboarding steps.
+type Pipeline struct {
+	steps []Step
+}
+
+func NewPipeline(steps ...Step) *Pipeline {
+	return &Pipeline{steps: steps}
+}
+
+// Run resumes onboarding for the current user, running every step that has not completed yet. The persona tag is
+// only used if the user has not started onboarding, or their progress was reset. The user's state is returned even
+// if a step fails.
+func (p *Pipeline) Run(ctx context.Context, nk runtime.NakamaModule, personaTag string) (*State, error) {
+	state, err := LoadState(ctx, nk)
+	if err != nil {
+		return nil, err
+	}
+	if state.Onboarded {
+		return state, nil
+	}
+	if len(state.Completed) == 0 && personaTag != "" {
+		state.PersonaTag = personaTag
+	}
+	if state.Data == nil {
+		state.Data = map[string]string{}
+	}
+
+	for _, step := range p.steps {
+		if state.isCompleted(step.Name) {
+			continue
+		}
+		state.CurrentStep = step.Name
+		stepErr := step.Run(ctx, nk, state)
+		if stepErr != nil {
+			state.LastError = stepErr.Error()
+			if err := saveState(ctx, nk, state); err != nil {
+				return state, errors.Join(stepErr, err)
+			}
+			return state, eris.Wrapf(stepErr, "onboarding step %q failed", step.Name)
+		}
+		state.Completed = append(state.Completed, step.Name)
+		state.LastError = ""
+		if err := saveState(ctx, nk, state); err != nil {
+			return state, err
+		}
+	}
+
+	state.CurrentStep = ""
+	state.Onboarded = true
+	if err := saveState(ctx, nk, state); err != nil {
+		return state, err
+	}
+	return state, nil
+}
+
+// LoadState loads the current user's onboarding state. Users who have not started onboarding get an empty state.
+func LoadState(ctx context.Context, nk runtime.NakamaModule) (*State, error) {
+	userID, err := utils.GetUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objs, err := nk.StorageRead(ctx, []*runtime.StorageRead{
+		{
+			Collection: Collection,
+			Key:        StateKey,
+			UserID:     userID,
+		},
+	})
+	if err != nil {
+		return nil, eris.Wrap(err, "")
+	}
+	state := &State{}
+	if len(objs) == 0 {
+		return state, nil
+	}
+	if err := json.Unmarshal([]byte(objs[0].GetValue()), state); err != nil {
+		return nil, eris.Wrap(err, "unable to unmarshal onboarding state")
+	}
+	return state, nil
+}
+
+func saveState(ctx context.Context, nk runtime.NakamaModule, state *State) error {
+	userID, err := utils.GetUserID(ctx)
+	if err != nil {
+		return err
+	}
+	state.UpdatedAt = time.Now().Unix()
+	buf, err := json.Marshal(state)
+	if err != nil {
+		return eris.Wrap(err, "unable to marshal onboarding state")
+	}
+	_, err = nk.StorageWrite(ctx, []*runtime.StorageWrite{
+		{
+			Collection:      Collection,
+			Key:             StateKey,
+			UserID:          userID,
+			Value:           string(buf),
+			PermissionRead:  runtime.STORAGE_PERMISSION_OWNER_READ,
+			PermissionWrite: runtime.STORAGE_PERMISSION_NO_WRITE,
+		},
+	})
+	return eris.Wrap(err, "unable to save onboarding state")
+}
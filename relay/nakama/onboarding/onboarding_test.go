@@ -0,0 +1,90 @@
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/heroiclabs/nakama-common/runtime"
+	"github.com/rotisserie/eris"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pkg.world.dev/world-engine/relay/nakama/testutils"
+)
+
+func TestPipelineResumesFromFailedStep(t *testing.T) {
+	ctx := testutils.CtxWithUserID("user1")
+	nk := testutils.NewFakeNakamaModule()
+
+	runs := map[string]int{}
+	failSeed := true
+	pipeline := NewPipeline(
+		Step{Name: "claim", Run: func(_ context.Context, _ runtime.NakamaModule, state *State) error {
+			runs["claim"]++
+			state.Data["tag"] = state.PersonaTag
+			return nil
+		}},
+		Step{Name: "seed", Run: func(context.Context, runtime.NakamaModule, *State) error {
+			runs["seed"]++
+			if failSeed {
+				return errors.New("cardinal is down")
+			}
+			return nil
+		}},
+	)
+
+	state, err := pipeline.Run(ctx, nk, "alice")
+	require.Error(t, err)
+	assert.False(t, state.Onboarded)
+	assert.Equal(t, []string{"claim"}, state.Completed)
+	assert.Equal(t, "seed", state.CurrentStep)
+	assert.Equal(t, "cardinal is down", state.LastError)
+
+	// The failure was saved, so the next attempt skips the steps that already completed.
+	failSeed = false
+	state, err = pipeline.Run(ctx, nk, "ignored")
+	require.NoError(t, err)
+	assert.True(t, state.Onboarded)
+	assert.Equal(t, "alice", state.PersonaTag)
+	assert.Equal(t, "alice", state.Data["tag"])
+	assert.Equal(t, map[string]int{"claim": 1, "seed": 2}, runs)
+
+	saved, err := LoadState(ctx, nk)
+	require.NoError(t, err)
+	assert.True(t, saved.Onboarded)
+	assert.Empty(t, saved.LastError)
+
+	// Onboarded users don't run any steps again.
+	_, err = pipeline.Run(ctx, nk, "")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"claim": 1, "seed": 2}, runs)
+}
+
+func TestPipelineResetStartsOver(t *testing.T) {
+	ctx := testutils.CtxWithUserID("user1")
+	nk := testutils.NewFakeNakamaModule()
+
+	rejected := true
+	pipeline := NewPipeline(
+		Step{Name: "claim", Run: func(context.Context, runtime.NakamaModule, *State) error { return nil }},
+		Step{Name: "await", Run: func(_ context.Context, _ runtime.NakamaModule, state *State) error {
+			if rejected {
+				state.Reset()
+				return errors.New("rejected")
+			}
+			return eris.Wrap(ErrStepPending, "")
+		}},
+	)
+
+	state, err := pipeline.Run(ctx, nk, "taken")
+	require.Error(t, err)
+	assert.Empty(t, state.Completed)
+
+	rejected = false
+	state, err = pipeline.Run(ctx, nk, "available")
+	assert.True(t, eris.Is(err, ErrStepPending))
+	assert.Equal(t, "available", state.PersonaTag)
+	assert.Equal(t, []string{"claim"}, state.Completed)
+	assert.False(t, state.Onboarded)
+}
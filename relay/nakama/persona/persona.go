@@ -7,6 +7,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"net/url"
 	"sync"
 
 	"github.com/heroiclabs/nakama-common/runtime"
@@ -21,6 +22,7 @@ import (
 var (
 	createPersonaEndpoint            = "tx/persona/create-persona"
 	readPersonaSignerEndpoint        = "query/persona/signer"
+	personaExistsEndpoint            = "query/persona/exists"
 	createPersonaSuccess             = "success"
 	readPersonaSignerStatusUnknown   = "unknown"
 	readPersonaSignerStatusAvailable = "available"
@@ -111,6 +113,11 @@ func ClaimPersona(
 	}
 
 	personaTag := personaStorageObj.PersonaTag
+	// Tags that Cardinal already knows are rejected before anything is saved or submitted. If Cardinal can't tell,
+	// the create-persona transaction is sent anyway and Cardinal decides.
+	if exists, err := PersonaTagExists(ctx, personaTag, globalCardinalAddress); err == nil && exists {
+		return res, eris.Errorf("persona tag %q is not available", personaTag)
+	}
 	txHash, tick, err := createPersona(ctx, txSigner, personaTag, globalCardinalAddress, globalNamespace)
 	if err != nil {
 		return res, eris.Wrap(err, "unable to make create persona request to cardinal")
@@ -212,6 +219,33 @@ func createPersona(
 	return createPersonaResponse.TxHash, createPersonaResponse.Tick, nil
 }
 
+// PersonaTagExists asks Cardinal whether the persona tag, ignoring case, was registered as of its last tick. It is
+// cheap enough to check a tag before claiming it. An error is returned if Cardinal hasn't finished a tick yet.
+func PersonaTagExists(ctx context.Context, personaTag string, cardinalAddr string) (bool, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		utils.MakeHTTPURL(personaExistsEndpoint+"?tag="+url.QueryEscape(personaTag), cardinalAddr),
+		http.NoBody,
+	)
+	if err != nil {
+		return false, eris.Wrapf(err, "unable to make request to %q", personaExistsEndpoint)
+	}
+	resp, err := utils.DoRequest(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Exists bool `json:"exists"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return false, eris.Wrap(err, "unable to decode response")
+	}
+	return body.Exists, nil
+}
+
 func ShowPersona(
 	ctx context.Context,
 	nk runtime.NakamaModule,
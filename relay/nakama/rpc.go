@@ -5,13 +5,17 @@ import (
 	"io"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/heroiclabs/nakama-common/runtime"
 	"github.com/rotisserie/eris"
 
 	"pkg.world.dev/world-engine/relay/nakama/allowlist"
+	"pkg.world.dev/world-engine/relay/nakama/discovery"
 	"pkg.world.dev/world-engine/relay/nakama/events"
+	"pkg.world.dev/world-engine/relay/nakama/natstransport"
+	"pkg.world.dev/world-engine/relay/nakama/onboarding"
 	"pkg.world.dev/world-engine/relay/nakama/persona"
 	"pkg.world.dev/world-engine/relay/nakama/signer"
 )
@@ -44,6 +48,15 @@ func initPersonaTagEndpoints(
 	return eris.Wrap(initializer.RegisterRpc("nakama/show-persona", handleShowPersona(txSigner, cardinalAddress)), "")
 }
 
+// initOnboarding sets up the nakama RPC endpoint that takes a new account through the onboarding pipeline.
+func initOnboarding(_ runtime.Logger, initializer runtime.Initializer, pipeline *onboarding.Pipeline) error {
+	return eris.Wrap(initializer.RegisterRpc("nakama/onboard", handleOnboard(pipeline)), "")
+}
+
+func initEventHubAdmin(_ runtime.Logger, initializer runtime.Initializer, eventHub *events.EventHub) error {
+	return eris.Wrap(initializer.RegisterRpc("nakama/event-subscribers", handleEventSubscribers(eventHub)), "")
+}
+
 func initAllowlist(_ runtime.Logger, initializer runtime.Initializer) error {
 	enabledStr := os.Getenv(allowlist.EnabledEnvVar)
 	if enabledStr == "" {
@@ -102,7 +115,8 @@ func registerEndpoints(
 		string, string, runtime.NakamaModule,
 		context.Context,
 	) (io.Reader, error),
-	cardinalAddress string,
+	transport *natstransport.Transport,
+	cardinal *discovery.Endpoints,
 	namespace string,
 	txSigner signer.Signer,
 	autoReclaimPersonaTags bool,
@@ -118,11 +132,15 @@ func registerEndpoints(
 			createPayload,
 			notifier,
 			eventHub,
-			cardinalAddress,
+			transport,
+			cardinal,
 			namespace,
 			txSigner,
 			autoReclaimPersonaTags,
 		)
+		if strings.HasPrefix(currEndpoint, TransactionEndpointPrefix) {
+			requestHandler = withIdempotencyKey(currEndpoint, requestHandler)
+		}
 		err := initializer.RegisterRpc(currEndpoint, requestHandler)
 		if err != nil {
 			return eris.Wrap(err, "")
@@ -148,10 +148,10 @@ func MockMatchWriteKey(key string) interface{} {
 	})
 }
 
-// FakeNakamaModule is a fake implementation of runtime.NakamaModule that ONLY implements the StorageRead and
-// StorageWrite methods. Under the hood, a map is used to map collection/key/userID tuples onto the stored values.
-// Calling other methods on the NakamaModule interface will panic. In addition, searching for values (e.g. specifying
-// a collection, but no user ID) will not return the correct results.
+// FakeNakamaModule is a fake implementation of runtime.NakamaModule that ONLY implements the StorageRead,
+// StorageWrite, and StorageDelete methods. Under the hood, a map is used to map collection/key/userID tuples onto the
+// stored values. Calling other methods on the NakamaModule interface will panic. In addition, searching for values
+// (e.g. specifying a collection, but no user ID) will not return the correct results.
 //
 // This Fake implements the atomic guarantees of StorageRead and StorageWrite.
 // In addition, Version field is populated during StorageRead which allows for compare-and-swap writes.
@@ -250,3 +250,17 @@ func (f *FakeNakamaModule) StorageWrite(
 	}
 	return nil, nil
 }
+
+func (f *FakeNakamaModule) StorageDelete(_ context.Context, deletes []*runtime.StorageDelete) error {
+	f.Lock()
+	defer f.Unlock()
+	if len(f.errsToReturn) > 0 {
+		var err error
+		err, f.errsToReturn = f.errsToReturn[0], f.errsToReturn[1:]
+		return err
+	}
+	for _, del := range deletes {
+		delete(f.store, keyTuple{del.Collection, del.Key, del.UserID})
+	}
+	return nil
+}
@@ -30,3 +30,25 @@ func MarshalResult(logger runtime.Logger, result any) (string, error) {
 	}
 	return string(bz), nil
 }
+
+// ExtractStringField removes the given string field from a JSON object RPC payload. If the payload is not a JSON
+// object or does not have the field, the value is empty and the payload is returned unchanged.
+func ExtractStringField(payload string, field string) (value string, rest string, err error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(payload), &fields); err != nil {
+		return "", payload, nil //nolint:nilerr // payloads that aren't JSON objects can't carry the field
+	}
+	rawValue, ok := fields[field]
+	if !ok {
+		return "", payload, nil
+	}
+	if err := json.Unmarshal(rawValue, &value); err != nil {
+		return "", "", eris.Wrapf(err, "%s must be a string", field)
+	}
+	delete(fields, field)
+	buf, err := json.Marshal(fields)
+	if err != nil {
+		return "", "", eris.Wrap(err, "")
+	}
+	return value, string(buf), nil
+}
@@ -0,0 +1,180 @@
+package sign
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/rotisserie/eris"
+)
+
+var (
+	ErrEmptyBatch          = errors.New("batch must contain at least one transaction")
+	ErrNoTransactionsField = errors.New("batch must contain transactions field")
+	ErrNoMessageField      = errors.New("batch transaction must contain message field")
+)
+
+// BatchEntry is a transaction in a Batch: the full name of the message, such as "game.move", and its body.
+type BatchEntry struct {
+	Message string          `json:"message"`
+	Body    json.RawMessage `json:"body" swaggertype:"object"`
+}
+
+// NewBatchEntry returns a batch entry for the message with the given body. The body is normalized like the body of
+// a Transaction.
+func NewBatchEntry(message string, data any) (BatchEntry, error) {
+	if data == nil {
+		return BatchEntry{}, ErrCannotSignEmptyBody
+	}
+	bz, err := normalizeJSON(data)
+	if err != nil {
+		return BatchEntry{}, err
+	}
+	return BatchEntry{Message: message, Body: bz}, nil
+}
+
+// Batch is a list of transactions from one persona that is signed with a single signature, so that a client can
+// submit a burst of transactions, e.g. after reconnecting, in one request. The transactions of a batch use
+// consecutive nonces starting at Nonce: the i-th transaction has nonce Nonce+i, so the next transaction of the
+// signer should use Nonce+len(Transactions).
+type Batch struct {
+	PersonaTag   string       `json:"personaTag"`
+	Namespace    string       `json:"namespace"`
+	Nonce        uint64       `json:"nonce"`
+	Signature    string       `json:"signature"` // hex encoded string
+	Hash         common.Hash  `json:"hash,omitempty" swaggertype:"string"`
+	Transactions []BatchEntry `json:"transactions"`
+}
+
+// NewBatch signs the given entries, persona tag and first nonce with the given private key.
+func NewBatch(
+	pk *ecdsa.PrivateKey,
+	personaTag,
+	namespace string,
+	nonce uint64,
+	entries ...BatchEntry,
+) (*Batch, error) {
+	if len(personaTag) == 0 || personaTag == SystemPersonaTag {
+		return nil, ErrInvalidPersonaTag
+	}
+	if len(namespace) == 0 {
+		return nil, ErrInvalidNamespace
+	}
+	if len(entries) == 0 {
+		return nil, ErrEmptyBatch
+	}
+	b := &Batch{
+		PersonaTag:   personaTag,
+		Namespace:    namespace,
+		Nonce:        nonce,
+		Transactions: make([]BatchEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		if entry.Message == "" {
+			return nil, ErrNoMessageField
+		}
+		if len(entry.Body) == 0 {
+			return nil, ErrCannotSignEmptyBody
+		}
+		bz, err := normalizeJSON([]byte(entry.Body))
+		if err != nil {
+			return nil, err
+		}
+		b.Transactions = append(b.Transactions, BatchEntry{Message: entry.Message, Body: bz})
+	}
+	b.populateHash()
+	buf, err := crypto.Sign(b.Hash.Bytes(), pk)
+	if err != nil {
+		return nil, eris.Wrap(err, "error signing hash")
+	}
+	b.Signature = common.Bytes2Hex(buf)
+	return b, nil
+}
+
+func UnmarshalBatch(bz []byte) (*Batch, error) {
+	b := new(Batch)
+	dec := json.NewDecoder(bytes.NewBuffer(bz))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(b); err != nil {
+		return nil, eris.Wrap(err, "error decoding Batch")
+	}
+
+	if err := b.checkRequiredFields(); err != nil {
+		return nil, err
+	}
+	b.populateHash()
+	return b, nil
+}
+
+func (b *Batch) checkRequiredFields() error {
+	if b.PersonaTag == "" {
+		return eris.Wrap(ErrNoPersonaTagField, "")
+	}
+	if b.Namespace == "" {
+		return eris.Wrap(ErrNoNamespaceField, "")
+	}
+	if b.Signature == "" {
+		return eris.Wrap(ErrNoSignatureField, "")
+	}
+	if b.Transactions == nil {
+		return eris.Wrap(ErrNoTransactionsField, "")
+	}
+	if len(b.Transactions) == 0 {
+		return eris.Wrap(ErrEmptyBatch, "")
+	}
+	for _, entry := range b.Transactions {
+		if entry.Message == "" {
+			return eris.Wrap(ErrNoMessageField, "")
+		}
+		if len(entry.Body) == 0 {
+			return eris.Wrap(ErrNoBodyField, "")
+		}
+	}
+	return nil
+}
+
+// Marshal serializes this Batch to bytes, which can then be passed in to UnmarshalBatch.
+func (b *Batch) Marshal() ([]byte, error) {
+	res, err := json.Marshal(b)
+	err = eris.Wrap(err, "")
+	return res, err
+}
+
+// Verify verifies this Batch has a valid signature. If nil is returned, the signature is valid. The hash is always
+// recomputed from the transactions, so a batch can't carry a hash that doesn't match them.
+func (b *Batch) Verify(hexAddress string) error {
+	b.populateHash()
+	return verifySignature(b.Hash, b.Signature, hexAddress)
+}
+
+// Unpack returns the transactions of the batch in order. Each transaction has its own nonce and hash, and carries the
+// signature of the batch, so it must be verified as part of the batch rather than on its own.
+func (b *Batch) Unpack() []*Transaction {
+	txs := make([]*Transaction, 0, len(b.Transactions))
+	for i, entry := range b.Transactions {
+		tx := &Transaction{
+			PersonaTag: b.PersonaTag,
+			Namespace:  b.Namespace,
+			Nonce:      b.Nonce + uint64(i),
+			Signature:  b.Signature,
+			Body:       entry.Body,
+		}
+		tx.populateHash()
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+// populateHash hashes the message name and the transaction hash of each entry, so that the signature covers the
+// persona tag, namespace, nonce and body of every transaction, as well as the messages and their order.
+func (b *Batch) populateHash() {
+	hashes := make([][]byte, 0, 2*len(b.Transactions))
+	for i, tx := range b.Unpack() {
+		hashes = append(hashes, crypto.Keccak256([]byte(b.Transactions[i].Message)), tx.Hash.Bytes())
+	}
+	b.Hash = crypto.Keccak256Hash(hashes...)
+}
@@ -0,0 +1,83 @@
+package sign
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"gotest.tools/v3/assert"
+)
+
+func TestCanSignAndVerifyBatch(t *testing.T) {
+	goodKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	badKey, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+
+	type Move struct {
+		X, Y int
+	}
+	move, err := NewBatchEntry("game.move", Move{X: 1, Y: 2})
+	assert.NilError(t, err)
+	attack, err := NewBatchEntry("game.attack", `{"target": "bob"}`)
+	assert.NilError(t, err)
+	batch, err := NewBatch(goodKey, "my-tag", "my-namespace", 100, move, attack, move)
+	assert.NilError(t, err)
+
+	bz, err := batch.Marshal()
+	assert.NilError(t, err)
+	got, err := UnmarshalBatch(bz)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, got, batch)
+	assert.NilError(t, got.Verify(crypto.PubkeyToAddress(goodKey.PublicKey).Hex()))
+	assert.ErrorIs(t, got.Verify(crypto.PubkeyToAddress(badKey.PublicKey).Hex()), ErrSignatureValidationFailed)
+
+	// The transactions are unpacked in order, with consecutive nonces, so identical messages have different hashes.
+	txs := got.Unpack()
+	assert.Equal(t, len(txs), 3)
+	for i, tx := range txs {
+		assert.Equal(t, tx.PersonaTag, "my-tag")
+		assert.Equal(t, tx.Namespace, "my-namespace")
+		assert.Equal(t, tx.Nonce, uint64(100+i))
+		assert.Equal(t, tx.Signature, batch.Signature)
+	}
+	assert.Equal(t, string(txs[0].Body), `{"X":1,"Y":2}`)
+	assert.Equal(t, string(txs[1].Body), `{"target":"bob"}`)
+	assert.Check(t, txs[0].Hash != txs[2].Hash)
+
+	// Reordering or renaming the transactions invalidates the signature.
+	reordered := *got
+	reordered.Transactions = []BatchEntry{attack, move, move}
+	reordered.populateHash()
+	assert.ErrorIs(t, reordered.Verify(crypto.PubkeyToAddress(goodKey.PublicKey).Hex()), ErrSignatureValidationFailed)
+	renamed := *got
+	renamed.Transactions = []BatchEntry{move, {Message: "game.heal", Body: attack.Body}, move}
+	renamed.populateHash()
+	assert.ErrorIs(t, renamed.Verify(crypto.PubkeyToAddress(goodKey.PublicKey).Hex()), ErrSignatureValidationFailed)
+}
+
+func TestRejectInvalidBatches(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	assert.NilError(t, err)
+	entry, err := NewBatchEntry("game.move", `{"x": 1}`)
+	assert.NilError(t, err)
+
+	_, err = NewBatch(key, "", "namespace", 1, entry)
+	assert.ErrorIs(t, err, ErrInvalidPersonaTag)
+	_, err = NewBatch(key, SystemPersonaTag, "namespace", 1, entry)
+	assert.ErrorIs(t, err, ErrInvalidPersonaTag)
+	_, err = NewBatch(key, "tag", "", 1, entry)
+	assert.ErrorIs(t, err, ErrInvalidNamespace)
+	_, err = NewBatch(key, "tag", "namespace", 1)
+	assert.ErrorIs(t, err, ErrEmptyBatch)
+	_, err = NewBatch(key, "tag", "namespace", 1, BatchEntry{Message: "game.move"})
+	assert.ErrorIs(t, err, ErrCannotSignEmptyBody)
+
+	_, err = UnmarshalBatch([]byte(`{"personaTag":"tag","namespace":"ns","nonce":1,"signature":"xyzzy"}`))
+	assert.ErrorIs(t, err, ErrNoTransactionsField)
+	_, err = UnmarshalBatch([]byte(`{"personaTag":"tag","namespace":"ns","nonce":1,"signature":"xyzzy",` +
+		`"transactions":[{"body":{}}]}`))
+	assert.ErrorIs(t, err, ErrNoMessageField)
+	_, err = UnmarshalBatch([]byte(`{"personaTag":"tag","namespace":"ns","nonce":1,"signature":"xyzzy",` +
+		`"transactions":[],"extra":1}`))
+	assert.Check(t, err != nil)
+}
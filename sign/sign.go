@@ -224,13 +224,16 @@ func (s *Transaction) HashHex() string {
 // https://github.com/ethereum/go-ethereum/blob/master/crypto/crypto_test.go#L94
 // TODO: Review this signature verification, and compare it to geth's sig verification
 func (s *Transaction) Verify(hexAddress string) error {
-	addr := common.HexToAddress(hexAddress)
-
 	if isZeroHash(s.Hash) {
 		s.populateHash()
 	}
+	return verifySignature(s.Hash, s.Signature, hexAddress)
+}
 
-	sig := common.Hex2Bytes(s.Signature)
+// verifySignature verifies that the hex encoded signature of the hash was made by the given address.
+func verifySignature(hash common.Hash, signature string, hexAddress string) error {
+	addr := common.HexToAddress(hexAddress)
+	sig := common.Hex2Bytes(signature)
 	if len(sig) < crypto.RecoveryIDOffset {
 		return eris.Wrap(ErrSignatureValidationFailed, "hex to bytes failed")
 	}
@@ -238,7 +241,7 @@ func (s *Transaction) Verify(hexAddress string) error {
 		sig[crypto.RecoveryIDOffset] -= 27 // Transform yellow paper V from 27/28 to 0/1
 	}
 
-	signerPubKey, err := crypto.SigToPub(s.Hash.Bytes(), sig)
+	signerPubKey, err := crypto.SigToPub(hash.Bytes(), sig)
 	err = eris.Wrap(err, "")
 	if err != nil {
 		return err